@@ -21,6 +21,9 @@ type DoFunc[N Noder, T gr.TokenTyper] func(a *Result[N], root *gr.Token[T]) erro
 type Make[N Noder, T gr.TokenTyper] struct {
 	// ast_map is the map of the AST.
 	ast_map map[T]DoFunc[N, T]
+
+	// policy is the convention with which ApplyWithTrivia binds trivia.
+	policy TriviaPolicy
 }
 
 // AddEntry adds an entry to the AST. Nil steps are ignored.
@@ -63,12 +66,54 @@ func (m Make[N, T]) Apply(token *gr.Token[T]) ([]N, error) {
 		return nil, gcers.NewErrNilParameter("tree")
 	}
 
+	return m.apply(token, nil)
+}
+
+// ApplyIn is like Apply but is meant to be called by a step to convert the
+// children of its token. When the step runs under ApplyWithTrivia, the nodes
+// converted this way can hold trivia; otherwise, it is the same as Apply.
+//
+// Parameters:
+//   - a: The result of the calling step.
+//   - token: The token to create the AST from.
+//
+// Returns:
+//   - []N: The AST.
+//   - error: An error if the AST could not be created.
+func (m Make[N, T]) ApplyIn(a *Result[N], token *gr.Token[T]) ([]N, error) {
+	if token == nil {
+		return nil, gcers.NewErrNilParameter("tree")
+	}
+
+	var rec *trivia_recorder[N]
+
+	if a != nil {
+		rec = a.rec
+	}
+
+	return m.apply(token, rec)
+}
+
+// apply is a helper function that creates the AST of a token and records the
+// nodes of the conversion.
+//
+// Parameters:
+//   - token: The token to create the AST from. Assumed to be non-nil.
+//   - rec: The recorder of the conversions. Nil if they are not recorded.
+//
+// Returns:
+//   - []N: The AST.
+//   - error: An error if the AST could not be created.
+func (m Make[N, T]) apply(token *gr.Token[T], rec *trivia_recorder[N]) ([]N, error) {
+
 	step, ok := m.ast_map[token.Type]
 	if !ok {
 		return nil, fmt.Errorf("unexpected token type: %q", token.Type.String())
 	}
 
-	var res Result[N]
+	res := Result[N]{
+		rec: rec,
+	}
 
 	err := step(&res, token)
 	nodes := res.Apply()
@@ -77,5 +122,7 @@ func (m Make[N, T]) Apply(token *gr.Token[T]) ([]N, error) {
 		return nodes, NewErrInRule(token.Type, err)
 	}
 
+	rec.record(span_of(token), nodes)
+
 	return nodes, nil
 }
@@ -10,6 +10,10 @@ import (
 type Result[N Noder] struct {
 	// nodes is the nodes of the result.
 	nodes []N
+
+	// rec is the recorder of the conversions of the current ApplyWithTrivia call.
+	// Nil if the conversions are not recorded.
+	rec *trivia_recorder[N]
 }
 
 // SetNode sets the node of the result. It replaces any existing node.
@@ -0,0 +1,319 @@
+package ast
+
+import (
+	"slices"
+	"strconv"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+// TriviaPolicy is the convention with which trivia, such as comments, are bound
+// to the nodes of the AST. Formatters do not all follow the same one.
+type TriviaPolicy int
+
+const (
+	// AttachToPreceding binds trivia to the node that ends right before it.
+	AttachToPreceding TriviaPolicy = iota
+
+	// AttachToFollowing binds trivia to the node that starts right after it.
+	AttachToFollowing
+
+	// Standalone makes nodes of the trivia, with the entry of their type, and adds
+	// them to the node that encloses them.
+	Standalone
+)
+
+// String implements the fmt.Stringer interface.
+func (p TriviaPolicy) String() string {
+	switch p {
+	case AttachToPreceding:
+		return "attach-to-preceding"
+	case AttachToFollowing:
+		return "attach-to-following"
+	case Standalone:
+		return "standalone"
+	default:
+		return "TriviaPolicy(" + strconv.Itoa(int(p)) + ")"
+	}
+}
+
+// TriviaHolder is implemented by the nodes to which trivia can be bound.
+type TriviaHolder[T gr.TokenTyper] interface {
+	// AddTrivia binds a trivia token to the node. Trivia are bound in the order
+	// of the input.
+	//
+	// Parameters:
+	//   - trivia: The trivia token.
+	//   - leading: True if the trivia is before the node, false if it is after it.
+	AddTrivia(trivia *gr.Token[T], leading bool)
+}
+
+// SplitTrivia removes the trivia from a token stream so that the parser does not
+// see them. The lookaheads of the remaining tokens are linked again.
+//
+// Parameters:
+//   - tokens: The token stream.
+//   - types: The types of the trivia tokens.
+//
+// Returns:
+//   - []*gr.Token[T]: The tokens that are not trivia.
+//   - []*gr.Token[T]: The trivia tokens, in order.
+func SplitTrivia[T gr.TokenTyper](tokens []*gr.Token[T], types ...T) ([]*gr.Token[T], []*gr.Token[T]) {
+	var kept, trivia []*gr.Token[T]
+
+	for _, tk := range tokens {
+		if tk == nil {
+			continue
+		}
+
+		if slices.Contains(types, tk.Type) {
+			trivia = append(trivia, tk)
+		} else {
+			kept = append(kept, tk)
+		}
+	}
+
+	gr.LinkLookaheads(kept)
+
+	return kept, trivia
+}
+
+// built_token is a token converted by a Make along with its nodes.
+type built_token[N Noder] struct {
+	// span is the span of the token.
+	span grm.Span
+
+	// nodes are the nodes of the token.
+	nodes []N
+}
+
+// trivia_recorder records the conversions of one ApplyWithTrivia call. Each call
+// has its own, so that calls can run concurrently and be nested.
+type trivia_recorder[N Noder] struct {
+	// built are the converted tokens, in order of conversion.
+	built []built_token[N]
+}
+
+// record is a helper function that records the nodes of a converted token. Does
+// nothing if the receiver is nil.
+//
+// Parameters:
+//   - span: The span of the token.
+//   - nodes: The nodes of the token.
+func (r *trivia_recorder[N]) record(span grm.Span, nodes []N) {
+	if r == nil || len(nodes) == 0 {
+		return
+	}
+
+	r.built = append(r.built, built_token[N]{
+		span:  span,
+		nodes: nodes,
+	})
+}
+
+// span_of is a helper function that returns the span of a token. The positions
+// of the token are used if they are known; otherwise, its byte range, and then
+// the span from its first leaf to its last one.
+//
+// Parameters:
+//   - tk: The token. Assumed to be non-nil.
+//
+// Returns:
+//   - grm.Span: The span of the token.
+func span_of[T gr.TokenTyper](tk *gr.Token[T]) grm.Span {
+	if tk.Start.IsKnown() && tk.End.IsKnown() {
+		return grm.NewSpan(tk.Start.Offset, tk.End.Offset)
+	}
+
+	if tk.StartAt >= 0 {
+		return tk.Span()
+	}
+
+	first, last := tk, tk
+
+	for first.FirstChild != nil {
+		first = first.FirstChild
+	}
+
+	for last.LastChild != nil {
+		last = last.LastChild
+	}
+
+	return grm.NewSpan(first.At, last.At+len(last.Data))
+}
+
+// SetTriviaPolicy sets the convention with which ApplyWithTrivia binds trivia to
+// the nodes. The default policy is AttachToPreceding.
+//
+// Parameters:
+//   - policy: The trivia policy.
+func (m *Make[N, T]) SetTriviaPolicy(policy TriviaPolicy) {
+	m.policy = policy
+}
+
+// ApplyWithTrivia creates the AST given a token (most often the root) and binds
+// the trivia to its nodes following the trivia policy.
+//
+// With AttachToPreceding and AttachToFollowing, a trivia is bound to the innermost
+// node, implementing TriviaHolder, that ends right before it or starts right after
+// it; if there is none in that direction, the other one is tried. Trivia that
+// cannot be bound are handled as with Standalone: they are converted with the
+// entry of their type and added to the innermost node that encloses them, or
+// returned alongside the AST if none does.
+//
+// Parameters:
+//   - token: The token to create the AST from.
+//   - trivia: The trivia tokens, such as the ones of SplitTrivia.
+//
+// Returns:
+//   - []N: The AST.
+//   - error: An error if the AST could not be created.
+//
+// Only the given token and the tokens that the steps convert with ApplyIn can
+// hold trivia; the ones converted with Apply are not seen. The state of the call
+// is its own, so the Make can be used concurrently.
+func (m Make[N, T]) ApplyWithTrivia(token *gr.Token[T], trivia []*gr.Token[T]) ([]N, error) {
+	if token == nil {
+		return nil, gcers.NewErrNilParameter("tree")
+	}
+
+	rec := &trivia_recorder[N]{}
+
+	nodes, err := m.apply(token, rec)
+	if err != nil {
+		return nodes, err
+	}
+
+	root := span_of(token)
+
+	var before, after []N
+
+	for _, tv := range trivia {
+		if tv == nil {
+			continue
+		}
+
+		span := span_of(tv)
+
+		if m.policy != Standalone && bind(rec.built, tv, span, m.policy) {
+			continue
+		}
+
+		sub_nodes, err := m.Apply(tv)
+		if err != nil {
+			return nodes, err
+		}
+
+		parent, ok := enclosing(rec.built, span)
+
+		switch {
+		case ok:
+			children := make([]Noder, 0, len(sub_nodes))
+
+			for _, node := range sub_nodes {
+				children = append(children, node)
+			}
+
+			parent.AddChildren(children)
+		case span.Start < root.Start:
+			before = append(before, sub_nodes...)
+		default:
+			after = append(after, sub_nodes...)
+		}
+	}
+
+	return slices.Concat(before, nodes, after), nil
+}
+
+// bind is a helper function that binds a trivia to the node it is next to.
+//
+// Parameters:
+//   - built: The converted tokens.
+//   - tv: The trivia. Assumed to be non-nil.
+//   - span: The span of the trivia.
+//   - policy: Either AttachToPreceding or AttachToFollowing.
+//
+// Returns:
+//   - bool: True if the trivia was bound, false otherwise.
+func bind[N Noder, T gr.TokenTyper](built []built_token[N], tv *gr.Token[T], span grm.Span, policy TriviaPolicy) bool {
+	directions := []bool{policy == AttachToFollowing, policy != AttachToFollowing}
+
+	for _, leading := range directions {
+		var best TriviaHolder[T]
+		var best_span grm.Span
+
+		for _, b := range built {
+			var node N
+			var pos int
+
+			if leading {
+				if b.span.Start < span.End {
+					continue
+				}
+
+				node, pos = b.nodes[0], b.span.Start
+			} else {
+				if b.span.End > span.Start {
+					continue
+				}
+
+				node, pos = b.nodes[len(b.nodes)-1], -b.span.End
+			}
+
+			holder, ok := any(node).(TriviaHolder[T])
+			if !ok {
+				continue
+			}
+
+			best_pos := best_span.Start
+			if !leading {
+				best_pos = -best_span.End
+			}
+
+			if best == nil || pos < best_pos || pos == best_pos && b.span.Len() < best_span.Len() {
+				best = holder
+				best_span = b.span
+			}
+		}
+
+		if best != nil {
+			best.AddTrivia(tv, leading)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// enclosing is a helper function that returns the innermost node that encloses a
+// span. Only tokens converted to exactly one node are considered.
+//
+// Parameters:
+//   - built: The converted tokens.
+//   - span: The span.
+//
+// Returns:
+//   - N: The innermost node.
+//   - bool: True if a node encloses the span, false otherwise.
+func enclosing[N Noder](built []built_token[N], span grm.Span) (N, bool) {
+	var best *built_token[N]
+
+	for i, b := range built {
+		if len(b.nodes) != 1 || !b.span.Covers(span) {
+			continue
+		}
+
+		if best == nil || b.span.Len() < best.span.Len() {
+			best = &built[i]
+		}
+	}
+
+	if best == nil {
+		return *new(N), false
+	}
+
+	return best.nodes[0], true
+}
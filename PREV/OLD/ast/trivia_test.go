@@ -0,0 +1,223 @@
+package ast
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+type test_type int
+
+const (
+	tt_word test_type = iota
+	tt_comment
+	nt_list
+)
+
+func (t test_type) String() string {
+	return [...]string{"Word", "Comment", "List"}[t]
+}
+
+func (t test_type) GoString() string {
+	return t.String()
+}
+
+// test_node is a node that writes its trivia as "<data" if leading and ">data"
+// if trailing.
+type test_node struct {
+	data     string
+	children []Noder
+	trivia   []string
+}
+
+func (n *test_node) AddChild(child Noder) {
+	if child != nil {
+		n.children = append(n.children, child)
+	}
+}
+
+func (n *test_node) AddChildren(children []Noder) {
+	for _, child := range children {
+		n.AddChild(child)
+	}
+}
+
+func (n *test_node) IsLeaf() bool {
+	return len(n.children) == 0
+}
+
+func (n *test_node) AddTrivia(trivia *gr.Token[test_type], leading bool) {
+	if leading {
+		n.trivia = append(n.trivia, "<"+trivia.Data)
+	} else {
+		n.trivia = append(n.trivia, ">"+trivia.Data)
+	}
+}
+
+func (n *test_node) String() string {
+	elems := []string{n.data}
+
+	for _, child := range n.children {
+		elems = append(elems, child.String())
+	}
+
+	elems = append(elems, n.trivia...)
+
+	if len(n.children) == 0 && len(n.trivia) == 0 {
+		return n.data
+	}
+
+	return "(" + strings.Join(elems, " ") + ")"
+}
+
+// new_test_make is a helper function that makes the Make of the lists of words.
+// The words are converted with ApplyIn.
+func new_test_make(t *testing.T) *Make[*test_node, test_type] {
+	var m Make[*test_node, test_type]
+
+	leaf := func(a *Result[*test_node], root *gr.Token[test_type]) error {
+		a.SetNode(&test_node{data: root.Data})
+
+		return nil
+	}
+
+	for _, tt := range []test_type{tt_word, tt_comment} {
+		err := m.AddEntry(tt, leaf)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	err := m.AddEntry(nt_list, func(a *Result[*test_node], root *gr.Token[test_type]) error {
+		a.SetNode(&test_node{data: "List"})
+
+		for c := root.FirstChild; c != nil; c = c.NextSibling {
+			nodes, err := m.ApplyIn(a, c)
+			if err != nil {
+				return err
+			}
+
+			children := make([]Noder, 0, len(nodes))
+
+			for _, node := range nodes {
+				children = append(children, node)
+			}
+
+			err = a.AppendChildren(children)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	return &m
+}
+
+// new_test_tree is a helper function that makes the parse tree and the trivia of
+// "a /*x*/ b // y".
+func new_test_tree() (*gr.Token[test_type], []*gr.Token[test_type]) {
+	root := gr.NewToken(nt_list, "", 0, nil)
+
+	root.AddChildren([]*gr.Token[test_type]{
+		gr.NewToken(tt_word, "a", 0, nil),
+		gr.NewToken(tt_word, "b", 8, nil),
+	})
+
+	root.ComputeRange()
+
+	trivia := []*gr.Token[test_type]{
+		gr.NewToken(tt_comment, "/*x*/", 2, nil),
+		gr.NewToken(tt_comment, "// y", 10, nil),
+	}
+
+	return root, trivia
+}
+
+// join_nodes is a helper function that writes nodes separated by spaces.
+func join_nodes(nodes []*test_node) string {
+	elems := make([]string, 0, len(nodes))
+
+	for _, node := range nodes {
+		elems = append(elems, node.String())
+	}
+
+	return strings.Join(elems, " ")
+}
+
+func TestApplyWithTrivia(t *testing.T) {
+	tests := []struct {
+		policy TriviaPolicy
+		want   string
+	}{
+		{AttachToPreceding, "(List (a >/*x*/) (b >// y))"},
+		{AttachToFollowing, "(List a (b </*x*/ >// y))"},
+		{Standalone, "(List a b /*x*/) // y"},
+	}
+
+	for _, test := range tests {
+		m := new_test_make(t)
+		m.SetTriviaPolicy(test.policy)
+
+		root, trivia := new_test_tree()
+
+		nodes, err := m.ApplyWithTrivia(root, trivia)
+		if err != nil {
+			t.Fatalf("%v: expected no error, got %v", test.policy, err)
+		}
+
+		if got := join_nodes(nodes); got != test.want {
+			t.Errorf("%v: expected %s, got %s", test.policy, test.want, got)
+		}
+	}
+}
+
+func TestApplyWithTriviaConcurrent(t *testing.T) {
+	m := new_test_make(t)
+
+	var wg sync.WaitGroup
+
+	errs := make([]string, 16)
+
+	for i := range errs {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			root, trivia := new_test_tree()
+
+			var nodes []*test_node
+			var err error
+
+			want := "(List (a >/*x*/) (b >// y))"
+
+			if i%2 == 0 {
+				nodes, err = m.ApplyWithTrivia(root, trivia)
+			} else {
+				nodes, err = m.Apply(root)
+				want = "(List a b)"
+			}
+
+			if err != nil {
+				errs[i] = err.Error()
+			} else if got := join_nodes(nodes); got != want {
+				errs[i] = "expected " + want + ", got " + got
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != "" {
+			t.Errorf("call %d: %s", i, err)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package displayer
+
+import (
+	"strconv"
+
+	gcint "github.com/PlayerR9/go-commons/ints"
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+// Phase is the phase in which an error occurred.
+type Phase int
+
+const (
+	// PhaseLexing is the lexing phase.
+	PhaseLexing Phase = iota
+
+	// PhaseParsing is the parsing phase.
+	PhaseParsing
+)
+
+// Catalog is the set of formatters used to render the messages of DisplayError.
+// Replace any of them to localize the messages without forking the library; nil
+// formatters fall back to the English defaults.
+type Catalog struct {
+	// Ordinal formats an ordinal number (e.g., 1 -> "1st").
+	Ordinal func(n int) string
+
+	// Heading formats the heading of an error with the given code that occurred in
+	// the given phase at the given character and line. Both are 1-based.
+	Heading func(cat *Catalog, code gr.Code, phase Phase, char, line int) string
+
+	// Reason formats the reason of an error. Use it to render the errors of the
+	// lexing and parsing packages from their exported fields.
+	Reason func(reason error) string
+
+	// Hint formats a suggestion for solving an error.
+	Hint func(suggestion string) string
+
+	// Unknown formats an error that is neither a lexing nor a parsing error.
+	Unknown func(err error) string
+
+	// Located formats the heading of a diagnostic of a bag that is neither a
+	// lexing nor a parsing error. Both char and line are 1-based; 0 if the
+	// location of the diagnostic is unknown.
+	Located func(cat *Catalog, code gr.Code, severity gr.Severity, char, line int) string
+
+	// Related formats a location related to a diagnostic. Both char and line are
+	// 1-based; 0 if the location is unknown.
+	Related func(cat *Catalog, message string, char, line int) string
+
+	// Summary formats the closing line of a bag of diagnostics.
+	Summary func(errors, warnings int) string
+}
+
+// code_of returns the diagnostic code of an error that occurred in the given phase.
+//
+// Parameters:
+//   - phase: The phase in which the error occurred.
+//   - reason: The reason of the error.
+//
+// Returns:
+//   - gr.Code: The diagnostic code. The generic code of the phase if the reason
+//     has no code.
+func code_of(phase Phase, reason error) gr.Code {
+	code, ok := gr.CodeOf(reason)
+	if ok {
+		return code
+	}
+
+	if phase == PhaseLexing {
+		return gr.CodeLexing
+	}
+
+	return gr.CodeParsing
+}
+
+// DefaultCatalog is the English catalog used when no catalog is specified.
+var DefaultCatalog *Catalog
+
+func init() {
+	DefaultCatalog = &Catalog{}
+}
+
+// ordinal formats an ordinal number with the catalog.
+//
+// Parameters:
+//   - n: The number.
+//
+// Returns:
+//   - string: The formatted number.
+func (c *Catalog) ordinal(n int) string {
+	if c.Ordinal != nil {
+		return c.Ordinal(n)
+	}
+
+	return gcint.GetOrdinalSuffix(n)
+}
+
+// heading formats the heading of an error with the catalog.
+//
+// Parameters:
+//   - code: The diagnostic code of the error.
+//   - phase: The phase in which the error occurred.
+//   - char: The 1-based character of the error.
+//   - line: The 1-based line of the error.
+//
+// Returns:
+//   - string: The formatted heading.
+func (c *Catalog) heading(code gr.Code, phase Phase, char, line int) string {
+	if c.Heading != nil {
+		return c.Heading(c, code, phase, char, line)
+	}
+
+	var prefix string
+
+	if phase == PhaseLexing {
+		prefix = "Lexing"
+	} else {
+		prefix = "Parsing"
+	}
+
+	return "[" + string(code) + "] " + prefix + " error at the " + c.ordinal(char) + " character of the " + c.ordinal(line) + " line:"
+}
+
+// reason formats the reason of an error with the catalog.
+//
+// Parameters:
+//   - reason: The reason of the error.
+//
+// Returns:
+//   - string: The formatted reason.
+func (c *Catalog) reason(reason error) string {
+	if c.Reason != nil {
+		return c.Reason(reason)
+	}
+
+	if reason == nil {
+		return "unknown reason"
+	}
+
+	return reason.Error()
+}
+
+// hint formats a suggestion with the catalog.
+//
+// Parameters:
+//   - suggestion: The suggestion.
+//
+// Returns:
+//   - string: The formatted suggestion.
+func (c *Catalog) hint(suggestion string) string {
+	if c.Hint != nil {
+		return c.Hint(suggestion)
+	}
+
+	return "Hint: " + suggestion
+}
+
+// unknown formats an unknown error with the catalog.
+//
+// Parameters:
+//   - err: The error.
+//
+// Returns:
+//   - string: The formatted error.
+func (c *Catalog) unknown(err error) string {
+	if c.Unknown != nil {
+		return c.Unknown(err)
+	}
+
+	code, ok := gr.CodeOf(err)
+	if !ok {
+		return "Error: " + err.Error()
+	}
+
+	return "[" + string(code) + "] Error: " + err.Error()
+}
+
+// located formats the heading of a diagnostic with the catalog.
+//
+// Parameters:
+//   - code: The diagnostic code. Empty if the diagnostic has none.
+//   - severity: The severity of the diagnostic.
+//   - char: The 1-based character of the diagnostic. 0 if unknown.
+//   - line: The 1-based line of the diagnostic. 0 if unknown.
+//
+// Returns:
+//   - string: The formatted heading.
+func (c *Catalog) located(code gr.Code, severity gr.Severity, char, line int) string {
+	if c.Located != nil {
+		return c.Located(c, code, severity, char, line)
+	}
+
+	var prefix string
+
+	if code != "" {
+		prefix = "[" + string(code) + "] "
+	}
+
+	switch severity {
+	case gr.SeverityError:
+		prefix += "Error"
+	case gr.SeverityWarning:
+		prefix += "Warning"
+	default:
+		prefix += "Info"
+	}
+
+	if line <= 0 {
+		return prefix + ":"
+	}
+
+	return prefix + " at the " + c.ordinal(char) + " character of the " + c.ordinal(line) + " line:"
+}
+
+// related formats a related location with the catalog.
+//
+// Parameters:
+//   - message: The message of the location.
+//   - char: The 1-based character of the location. 0 if unknown.
+//   - line: The 1-based line of the location. 0 if unknown.
+//
+// Returns:
+//   - string: The formatted location.
+func (c *Catalog) related(message string, char, line int) string {
+	if c.Related != nil {
+		return c.Related(c, message, char, line)
+	}
+
+	if line <= 0 {
+		return "Note: " + message
+	}
+
+	return "Note: " + message + " (at the " + c.ordinal(char) + " character of the " + c.ordinal(line) + " line)"
+}
+
+// summary formats the closing line of a bag of diagnostics with the catalog.
+//
+// Parameters:
+//   - errors: The number of errors.
+//   - warnings: The number of warnings.
+//
+// Returns:
+//   - string: The formatted line.
+func (c *Catalog) summary(errors, warnings int) string {
+	if c.Summary != nil {
+		return c.Summary(errors, warnings)
+	}
+
+	return strconv.Itoa(errors) + " error(s), " + strconv.Itoa(warnings) + " warning(s)"
+}
@@ -0,0 +1,165 @@
+package displayer
+
+import (
+	"bytes"
+	"os"
+	"strings"
+)
+
+// ColorProfile is the set of colors used to style the output of the printer.
+type ColorProfile int
+
+const (
+	// ColorNone disables the styling.
+	ColorNone ColorProfile = iota
+
+	// ColorANSI styles the output with the 16 standard ANSI colors.
+	ColorANSI
+
+	// ColorANSI256 styles the output with the 256 colors of the xterm palette.
+	ColorANSI256
+
+	// ColorAuto styles the output with the profile detected for the standard
+	// output; see DetectColor.
+	ColorAuto
+)
+
+// color_role is the role of a styled part of the output.
+type color_role int
+
+const (
+	// role_span is the faulty span of the input.
+	role_span color_role = iota
+
+	// role_arrow is the arrow that points to the faulty span.
+	role_arrow
+
+	// role_line_number is a line number.
+	role_line_number
+
+	// role_hint is the suggestion for solving an error.
+	role_hint
+)
+
+// ansi_reset is the escape sequence that resets the style.
+const ansi_reset string = "\x1b[0m"
+
+// color_codes are the escape sequences of the roles, by profile.
+var color_codes = map[ColorProfile][4]string{
+	ColorANSI: {
+		role_span:        "\x1b[1;4;31m",
+		role_arrow:       "\x1b[1;31m",
+		role_line_number: "\x1b[34m",
+		role_hint:        "\x1b[32m",
+	},
+	ColorANSI256: {
+		role_span:        "\x1b[1;4;38;5;196m",
+		role_arrow:       "\x1b[1;38;5;196m",
+		role_line_number: "\x1b[38;5;244m",
+		role_hint:        "\x1b[38;5;114m",
+	},
+}
+
+// DetectColor detects the profile supported by the terminal the given file is
+// written to. The styling is disabled when the file is not a terminal, when the
+// NO_COLOR environment variable is set or when TERM is "dumb".
+//
+// Parameters:
+//   - f: The file. If nil, the styling is disabled.
+//
+// Returns:
+//   - ColorProfile: The profile. Never ColorAuto.
+func DetectColor(f *os.File) ColorProfile {
+	if f == nil || os.Getenv("NO_COLOR") != "" {
+		return ColorNone
+	}
+
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return ColorNone
+	}
+
+	term := os.Getenv("TERM")
+
+	switch {
+	case term == "dumb":
+		return ColorNone
+	case strings.Contains(term, "256color"), os.Getenv("COLORTERM") != "":
+		return ColorANSI256
+	default:
+		return ColorANSI
+	}
+}
+
+// paint is a helper function that styles a text with the escape sequence of a
+// role.
+//
+// Parameters:
+//   - role: The role of the text.
+//   - text: The text.
+//
+// Returns:
+//   - []byte: The styled text. The text itself if the profile has no styling or
+//     if the text is empty.
+func (p ColorProfile) paint(role color_role, text []byte) []byte {
+	codes, ok := color_codes[p]
+	if !ok || len(text) == 0 {
+		return text
+	}
+
+	styled := make([]byte, 0, len(codes[role])+len(text)+len(ansi_reset))
+
+	styled = append(styled, codes[role]...)
+	styled = append(styled, text...)
+	styled = append(styled, ansi_reset...)
+
+	return styled
+}
+
+// paint_range is a helper function that styles the part of a text between two
+// byte offsets.
+//
+// Parameters:
+//   - role: The role of the part.
+//   - text: The text.
+//   - from: The start of the part. Clamped to the text.
+//   - to: The end of the part, exclusive. Clamped to the text.
+//
+// Returns:
+//   - []byte: The text with the part styled. The text itself if the profile has
+//     no styling.
+func (p ColorProfile) paint_range(role color_role, text []byte, from, to int) []byte {
+	if _, ok := color_codes[p]; !ok {
+		return text
+	}
+
+	from = min(max(from, 0), len(text))
+	to = min(max(to, from), len(text))
+
+	styled := make([]byte, 0, len(text)+16)
+
+	styled = append(styled, text[:from]...)
+	styled = append(styled, p.paint(role, text[from:to])...)
+	styled = append(styled, text[to:]...)
+
+	return styled
+}
+
+// paint_arrow is a helper function that styles the carets of an arrow line, from
+// the first one to the last one.
+//
+// Parameters:
+//   - line: The line of the arrow.
+//
+// Returns:
+//   - []byte: The styled line. The line itself if it has no caret.
+func (p ColorProfile) paint_arrow(line []byte) []byte {
+	from := bytes.IndexByte(line, '^')
+	if from < 0 {
+		return line
+	}
+
+	to := bytes.LastIndexAny(line, "^~") + 1
+
+	return p.paint_range(role_arrow, line, from, to)
+}
@@ -0,0 +1,329 @@
+package displayer
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"sync"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+// NoSpan is the span of diagnostics whose location is unknown.
+var NoSpan grm.Span = grm.Span{Start: -1, End: -1}
+
+// Diagnostic is an error or a warning located in the input.
+type Diagnostic struct {
+	// Code is the diagnostic code.
+	Code gr.Code
+
+	// Span is the span of the diagnostic in the input. NoSpan if unknown.
+	Span grm.Span
+
+	// Err is the error that describes the diagnostic.
+	Err error
+
+	// Severity is the severity of the diagnostic.
+	Severity gr.Severity
+
+	// Related are the other locations of the input that explain the diagnostic
+	// (e.g., the previous declaration of a redeclared name). Nil if there is none.
+	Related []Related
+}
+
+// Related is a location of the input related to a diagnostic.
+type Related struct {
+	// Span is the span of the location. NoSpan if unknown.
+	Span grm.Span
+
+	// Message is the message that explains the location.
+	Message string
+}
+
+// diag_key is the key that identifies duplicated diagnostics.
+type diag_key struct {
+	// code is the code of the diagnostic.
+	code gr.Code
+
+	// span is the span of the diagnostic.
+	span grm.Span
+
+	// msg is the message of the diagnostic. Only set if the diagnostic has no code.
+	msg string
+}
+
+// Diagnostics is a bag of diagnostics. The diagnostics covered by a suppression
+// are kept apart and not reported.
+//
+// A diagnostic with the same code and span as one already in the bag is a
+// duplicate and is dropped; so that parallel passes reporting the same problem do
+// not report it several times. Diagnostics without a code must also have the same
+// message to be duplicates.
+//
+// It is safe for concurrent use.
+type Diagnostics struct {
+	// mu protects the fields of the bag.
+	mu sync.Mutex
+
+	// seen are the keys of the diagnostics added to the bag.
+	seen map[diag_key]struct{}
+
+	// duplicates is the number of duplicated diagnostics that were dropped.
+	duplicates int
+
+	// list is the list of reported diagnostics.
+	list []Diagnostic
+
+	// suppressed is the list of suppressed diagnostics.
+	suppressed []Diagnostic
+
+	// suppressions are the suppressions of the bag.
+	suppressions []Suppression
+}
+
+// NewDiagnostics creates a new, empty, bag of diagnostics.
+//
+// Returns:
+//   - *Diagnostics: The new bag. Never returns nil.
+func NewDiagnostics() *Diagnostics {
+	return &Diagnostics{}
+}
+
+// Add adds a diagnostic to the bag. Its code is the one of the error, if any, and
+// its severity is the one of the code.
+//
+// Parameters:
+//   - span: The span of the diagnostic. NoSpan if unknown.
+//   - err: The error that describes the diagnostic.
+//
+// Does nothing if err is nil or if the diagnostic is a duplicate.
+func (d *Diagnostics) Add(span grm.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	code, _ := gr.CodeOf(err)
+
+	d.Report(Diagnostic{
+		Code:     code,
+		Span:     span,
+		Err:      err,
+		Severity: code.Severity(),
+	})
+}
+
+// Report adds a diagnostic to the bag as is. Use it for the diagnostics whose
+// severity is not the one of their code or that have related locations.
+//
+// Parameters:
+//   - diag: The diagnostic. If its code is empty, the one of its error is used.
+//
+// Does nothing if the error of the diagnostic is nil or if the diagnostic is a
+// duplicate.
+func (d *Diagnostics) Report(diag Diagnostic) {
+	if diag.Err == nil {
+		return
+	}
+
+	if diag.Code == "" {
+		diag.Code, _ = gr.CodeOf(diag.Err)
+	}
+
+	key := diag_key{
+		code: diag.Code,
+		span: diag.Span,
+	}
+
+	if diag.Code == "" {
+		key.msg = diag.Err.Error()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.seen[key]; ok {
+		d.duplicates++
+
+		return
+	}
+
+	if d.seen == nil {
+		d.seen = make(map[diag_key]struct{})
+	}
+
+	d.seen[key] = struct{}{}
+
+	d.add(diag)
+}
+
+// add is a helper function that adds a diagnostic to either the reported or the
+// suppressed list.
+//
+// Parameters:
+//   - diag: The diagnostic to add.
+func (d *Diagnostics) add(diag Diagnostic) {
+	for _, s := range d.suppressions {
+		if s.Covers(diag) {
+			d.suppressed = append(d.suppressed, diag)
+			return
+		}
+	}
+
+	d.list = append(d.list, diag)
+}
+
+// Suppress adds suppressions to the bag. Both the diagnostics already in the bag
+// and the ones added later are filtered.
+//
+// Parameters:
+//   - suppressions: The suppressions to add.
+func (d *Diagnostics) Suppress(suppressions ...Suppression) {
+	if len(suppressions) == 0 {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.suppressions = append(d.suppressions, suppressions...)
+
+	list := d.list
+	d.list = nil
+
+	for _, diag := range list {
+		d.add(diag)
+	}
+}
+
+// All returns the reported diagnostics in the order they were added.
+//
+// Returns:
+//   - []Diagnostic: The reported diagnostics.
+func (d *Diagnostics) All() []Diagnostic {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return slices.Clone(d.list)
+}
+
+// Sorted returns the reported diagnostics in the order of their spans. The
+// diagnostics whose span is unknown come last, in the order they were added.
+//
+// Returns:
+//   - []Diagnostic: The reported diagnostics.
+func (d *Diagnostics) Sorted() []Diagnostic {
+	list := d.All()
+
+	slices.SortStableFunc(list, func(a, b Diagnostic) int {
+		a_known := a.Span.Start >= 0
+		b_known := b.Span.Start >= 0
+
+		switch {
+		case a_known && !b_known:
+			return -1
+		case !a_known && b_known:
+			return 1
+		case !a_known:
+			return 0
+		}
+
+		return cmp.Or(cmp.Compare(a.Span.Start, b.Span.Start), cmp.Compare(a.Span.End, b.Span.End))
+	})
+
+	return list
+}
+
+// Suppressed returns the diagnostics that were suppressed.
+//
+// Returns:
+//   - []Diagnostic: The suppressed diagnostics.
+func (d *Diagnostics) Suppressed() []Diagnostic {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return slices.Clone(d.suppressed)
+}
+
+// Len returns the number of reported diagnostics.
+//
+// Returns:
+//   - int: The number of reported diagnostics.
+func (d *Diagnostics) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return len(d.list)
+}
+
+// HasErrors checks whether at least one reported diagnostic is an error.
+//
+// Returns:
+//   - bool: True if a reported diagnostic is an error, false otherwise.
+func (d *Diagnostics) HasErrors() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, diag := range d.list {
+		if diag.Severity == gr.SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Count returns the number of reported diagnostics with the given severity.
+//
+// Parameters:
+//   - severity: The severity.
+//
+// Returns:
+//   - int: The number of reported diagnostics.
+func (d *Diagnostics) Count(severity gr.Severity) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var count int
+
+	for _, diag := range d.list {
+		if diag.Severity == severity {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Error implements the error interface, so that the whole bag can be returned
+// and displayed with DisplayError.
+//
+// Message:
+//
+//	"<n> errors and <m> warnings"
+func (d *Diagnostics) Error() string {
+	return strconv.Itoa(d.Count(gr.SeverityError)) + " errors and " + strconv.Itoa(d.Count(gr.SeverityWarning)) + " warnings"
+}
+
+// Err returns the bag as an error if it has errors.
+//
+// Returns:
+//   - error: The bag. Nil if no reported diagnostic is an error.
+func (d *Diagnostics) Err() error {
+	if !d.HasErrors() {
+		return nil
+	}
+
+	return d
+}
+
+// Duplicates returns the number of duplicated diagnostics that were dropped.
+//
+// Returns:
+//   - int: The number of duplicates.
+func (d *Diagnostics) Duplicates() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.duplicates
+}
@@ -0,0 +1,65 @@
+package displayer
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+func TestDiagnosticsConcurrent(t *testing.T) {
+	// The problems that every pass reports: two coded ones, the second twice with
+	// other messages, and two uncoded ones that only differ by their message.
+	problems := []struct {
+		span grm.Span
+		err  error
+	}{
+		{grm.NewSpan(0, 1), gr.WithCode(gr.CodeUnexpectedChar, errors.New("unexpected 'a'"))},
+		{grm.NewSpan(2, 3), gr.WithCode(gr.CodeUnexpectedToken, errors.New("unexpected b"))},
+		{grm.NewSpan(2, 3), gr.WithCode(gr.CodeUnexpectedToken, errors.New("unexpected B"))},
+		{grm.NewSpan(4, 5), errors.New("first")},
+		{grm.NewSpan(4, 5), errors.New("second")},
+	}
+
+	const passes = 16
+
+	bag := NewDiagnostics()
+
+	var wg sync.WaitGroup
+
+	for range passes {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for _, p := range problems {
+				bag.Add(p.span, p.err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	const unique = 4
+
+	if got := bag.Len(); got != unique {
+		t.Errorf("expected %d diagnostics, got %d", unique, got)
+	}
+
+	if got := bag.Duplicates(); got != passes*len(problems)-unique {
+		t.Errorf("expected %d duplicates, got %d", passes*len(problems)-unique, got)
+	}
+
+	if !bag.HasErrors() {
+		t.Error("expected the bag to have errors")
+	}
+
+	bag.Add(grm.NewSpan(6, 7), nil)
+
+	if got := bag.Len(); got != unique {
+		t.Errorf("expected a nil error to be ignored, got %d diagnostics", got)
+	}
+}
@@ -3,14 +3,17 @@ package displayer
 import (
 	"bytes"
 	"errors"
+	"slices"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 
 	gfch "github.com/PlayerR9/go-commons/Formatting/runes"
-	gcby "github.com/PlayerR9/go-commons/bytes"
-	gcint "github.com/PlayerR9/go-commons/ints"
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
 	"github.com/PlayerR9/grammar/PREV/OLD/lexing"
+	grm "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/grammar/internal/text"
 )
 
 var (
@@ -34,6 +37,19 @@ type PrintSettings struct {
 
 	// tab_size is the tab size.
 	tab_size int
+
+	// catalog is the catalog used to format the messages.
+	catalog *Catalog
+
+	// color is the profile used to style the output.
+	color ColorProfile
+
+	// line_numbers is true if the lines are prefixed by their number.
+	line_numbers bool
+
+	// file_name is the name of the file printed in the header. Empty if there is
+	// no header.
+	file_name string
 }
 
 // make_arrow is a helper function that creates an arrow pointing to the faulty token.
@@ -49,7 +65,7 @@ func (s *PrintSettings) make_arrow(faulty_line []byte, start_pos int) ([]byte, e
 
 	buffer.Grow(len(faulty_line))
 
-	first_tab := gcby.FixTabSize(s.tab_size, []byte{' '})
+	first_tab := text.ExpandTab(s.tab_size, []byte{' '})
 
 	for i := 0; i < start_pos; i++ {
 		if faulty_line[i] == '\t' {
@@ -88,9 +104,9 @@ func (s *PrintSettings) make_arrow(faulty_line []byte, start_pos int) ([]byte, e
 			buffer.WriteRune('^')
 		}
 	} else {
-		second_tab := gcby.FixTabSize(s.tab_size, []byte{'~'})
+		second_tab := text.ExpandTab(s.tab_size, []byte{'~'})
 
-		for i := start_pos; i < start_pos+s.delta; i++ {
+		for i := start_pos; i < min(start_pos+s.delta, len(faulty_line)); i++ {
 			if faulty_line[i] != '\t' {
 				buffer.WriteByte('^')
 			} else {
@@ -102,31 +118,66 @@ func (s *PrintSettings) make_arrow(faulty_line []byte, start_pos int) ([]byte, e
 	return buffer.Bytes(), nil
 }
 
-// PrintSyntaxError is a helper function that prints the syntax error.
+// excerpt is the excerpt of the input printed for a syntax error.
+type excerpt struct {
+	// before are the lines before the faulty line.
+	before []byte
+
+	// faulty_line is the line of the faulty token.
+	faulty_line []byte
+
+	// arrow is the arrow that points to the faulty token.
+	arrow []byte
+
+	// after are the lines after the faulty line.
+	after []byte
+
+	// from is the offset of the faulty token in the faulty line.
+	from int
+
+	// to is the offset right after the faulty token in the faulty line.
+	to int
+
+	// line is the 1-based line of the faulty token in the input.
+	line int
+
+	// column is the 1-based column of the faulty token in the input.
+	column int
+}
+
+// new_settings is a helper function that makes the settings of the printer from
+// the given options.
 //
 // Parameters:
-//   - data: The data of the faulty line.
-//   - start_pos: The start position of the faulty token.
 //   - opts: The print options.
 //
 // Returns:
-//   - []byte: The syntax error data.
-func PrintSyntaxError(data []byte, start_pos int, opts ...PrintOption) []byte {
-	if len(data) == 0 {
-		return nil
-	}
-
+//   - PrintSettings: The settings.
+func new_settings(opts []PrintOption) PrintSettings {
 	s := PrintSettings{
 		prev_lines: -1,
 		next_lines: -1,
 		delta:      -1,
 		tab_size:   -1,
+		catalog:    DefaultCatalog,
 	}
 
 	for _, opt := range opts {
 		opt(&s)
 	}
 
+	return s
+}
+
+// excerpt_of is a helper function that cuts the excerpt of a syntax error.
+//
+// Parameters:
+//   - data: The data of the faulty line. Assumed to be non-empty.
+//   - start_pos: The start position of the faulty token.
+//
+// Returns:
+//   - excerpt: The excerpt.
+func (s *PrintSettings) excerpt_of(data []byte, start_pos int) excerpt {
 	if start_pos < 0 {
 		start_pos = len(data) + start_pos
 	} else if start_pos >= len(data) {
@@ -137,59 +188,195 @@ func PrintSyntaxError(data []byte, start_pos int, opts ...PrintOption) []byte {
 		s.delta = len(data) - start_pos
 	}
 
-	var before, faulty_line, after []byte
+	var ex excerpt
 
-	before_idx := gcby.ReverseSearch(data, start_pos, gcby.Newline)
-	after_idx := gcby.ForwardSearch(data, start_pos, gcby.Newline)
+	before_idx := text.LastIndex(data, start_pos, []byte{'\n'})
+	after_idx := text.Index(data, start_pos, []byte{'\n'})
 
 	if before_idx == -1 {
 		if after_idx == -1 {
-			faulty_line = data
+			ex.faulty_line = data
 		} else {
-			faulty_line = data[:after_idx]
-			after = data[after_idx+1:]
+			ex.faulty_line = data[:after_idx]
+			ex.after = data[after_idx+1:]
 		}
 	} else {
 		if after_idx == -1 {
-			before = data[:before_idx]
-			faulty_line = data[before_idx+1:]
+			ex.before = data[:before_idx]
+			ex.faulty_line = data[before_idx+1:]
 		} else if before_idx == after_idx {
-			before = data[:before_idx]
-			after = data[after_idx+1:]
+			ex.before = data[:before_idx]
+			ex.after = data[after_idx+1:]
 		} else {
-			before = data[:before_idx]
-			faulty_line = data[before_idx+1 : after_idx]
-			after = data[after_idx+1:]
+			ex.before = data[:before_idx]
+			ex.faulty_line = data[before_idx+1 : after_idx]
+			ex.after = data[after_idx+1:]
 		}
 	}
 
-	arrow_data, _ := s.make_arrow(faulty_line, start_pos-len(before))
-	// dbg.AssertErr(err, "PrintSettings.make_arrow(%q, %d)", string(faulty_line), start_pos-len(before))
+	ex.from = min(max(start_pos-before_idx-1, 0), len(ex.faulty_line))
 
-	before = gcby.LimitReverseLines(before, s.prev_lines)
-	after = gcby.LimitLines(after, s.next_lines)
+	if s.delta < 0 {
+		ex.to = ex.from
 
-	var buffer bytes.Buffer
+		for ex.to < len(ex.faulty_line) {
+			r, size := utf8.DecodeRune(ex.faulty_line[ex.to:])
+			if r == utf8.RuneError || (ex.to > ex.from && unicode.IsSpace(r)) {
+				break
+			}
+
+			ex.to += size
+		}
+	} else {
+		ex.to = min(ex.from+s.delta, len(ex.faulty_line))
+	}
+
+	x, y := text.Coords(data, start_pos)
+	ex.column, ex.line = x+1, y+1
+
+	ex.arrow, _ = s.make_arrow(ex.faulty_line, ex.from)
+	// dbg.AssertErr(err, "PrintSettings.make_arrow(%q, %d)", string(ex.faulty_line), ex.from)
+
+	ex.before = text.LimitLines(ex.before, s.prev_lines, true)
+	ex.after = text.LimitLines(ex.after, s.next_lines, false)
+
+	return ex
+}
+
+// lines is a helper function that returns the lines of an excerpt, with the
+// header and the gutters of the settings.
+//
+// Parameters:
+//   - ex: The excerpt.
+//
+// Returns:
+//   - [][]byte: The lines: the header, if any, then the lines of the input from the
+//     first line before the faulty line to the last line after it, with the arrow
+//     right after the faulty line.
+//   - int: The index of the faulty line in the lines. The arrow is the next one.
+//   - int: The length of the gutter of the lines of the input. 0 if there is none.
+func (s *PrintSettings) lines(ex excerpt) ([][]byte, int, int) {
+	var lines [][]byte
+
+	if len(ex.before) > 0 {
+		lines = append(lines, bytes.Split(ex.before, []byte{'\n'})...)
+	}
+
+	faulty := len(lines)
+
+	lines = append(lines, ex.faulty_line, ex.arrow)
+
+	if len(ex.after) > 0 {
+		lines = append(lines, bytes.Split(ex.after, []byte{'\n'})...)
+	}
+
+	var gutter int
+
+	if s.line_numbers {
+		first := ex.line - faulty
+		width := len(strconv.Itoa(first + len(lines) - 2))
+
+		for i, line := range lines {
+			var number string
+
+			switch {
+			case i < faulty:
+				number = strconv.Itoa(first + i)
+			case i > faulty+1:
+				number = strconv.Itoa(first + i - 1)
+			case i == faulty:
+				number = strconv.Itoa(ex.line)
+			}
+
+			prefix := strings.Repeat(" ", width-len(number)) + number + " | "
+
+			lines[i] = append([]byte(prefix), line...)
+		}
+
+		gutter = width + len(" | ")
+	}
+
+	if s.file_name != "" {
+		header := []byte("--> " + s.file_name + ":" + strconv.Itoa(ex.line) + ":" + strconv.Itoa(ex.column))
+
+		lines = slices.Insert(lines, 0, header)
+		faulty++
+	}
+
+	return lines, faulty, gutter
+}
+
+// paint_lines is a helper function that styles the faulty span, the arrow and the
+// gutters of lines made by PrintSettings.lines.
+//
+// Parameters:
+//   - lines: The lines. Each one is shifted by offset bytes.
+//   - faulty: The index of the faulty line.
+//   - gutter: The length of the gutters.
+//   - offset: The number of bytes before each line.
+//   - ex: The excerpt of the lines.
+func (s *PrintSettings) paint_lines(lines [][]byte, faulty, gutter, offset int, ex excerpt) {
+	lines[faulty] = s.color.paint_range(role_span, lines[faulty], offset+gutter+ex.from, offset+gutter+ex.to)
+	lines[faulty+1] = s.color.paint_arrow(lines[faulty+1])
+
+	if gutter == 0 {
+		return
+	}
 
-	buffer.Grow(len(data) + 1 + len(arrow_data))
+	first := 0
 
-	if len(before) > 0 {
-		buffer.Write(before)
-		buffer.WriteByte('\n')
+	if s.file_name != "" {
+		first = 1
 	}
 
-	buffer.Write(faulty_line)
-	buffer.WriteByte('\n')
-	buffer.Write(arrow_data)
+	for i := first; i < len(lines); i++ {
+		lines[i] = s.color.paint_range(role_line_number, lines[i], offset, offset+gutter)
+	}
+}
 
-	if len(after) == 0 {
-		return buffer.Bytes()
+// PrintSyntaxError is a helper function that prints the syntax error.
+//
+// Parameters:
+//   - data: The data of the faulty line.
+//   - start_pos: The start position of the faulty token.
+//   - opts: The print options.
+//
+// Returns:
+//   - []byte: The syntax error data.
+func PrintSyntaxError(data []byte, start_pos int, opts ...PrintOption) []byte {
+	if len(data) == 0 {
+		return nil
 	}
 
-	buffer.WriteByte('\n')
-	buffer.Write(after)
+	s := new_settings(opts)
+
+	ex := s.excerpt_of(data, start_pos)
+
+	lines, faulty, gutter := s.lines(ex)
+
+	s.paint_lines(lines, faulty, gutter, 0, ex)
+
+	return bytes.Join(lines, []byte{'\n'})
+}
+
+// PrintSpan is a helper function that prints the syntax error located at the
+// given span.
+//
+// Parameters:
+//   - data: The data of the faulty line.
+//   - span: The span of the faulty token.
+//   - opts: The print options.
+//
+// Returns:
+//   - []byte: The syntax error data.
+//
+// An empty span is underlined up to the next whitespace.
+func PrintSpan(data []byte, span grm.Span, opts ...PrintOption) []byte {
+	if !span.IsEmpty() {
+		opts = append(opts, WithDelta(span.Len()))
+	}
 
-	return buffer.Bytes()
+	return PrintSyntaxError(data, span.Start, opts...)
 }
 
 // PrintBoxedData is a helper function that prints the boxed data.
@@ -202,15 +389,44 @@ func PrintSyntaxError(data []byte, start_pos int, opts ...PrintOption) []byte {
 // Returns:
 //   - []byte: The boxed data.
 func PrintBoxedData(data []byte, at int, opts ...PrintOption) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+
+	s := new_settings(opts)
+
+	ex := s.excerpt_of(data, at)
+
+	lines, faulty, gutter := s.lines(ex)
+
 	var table gfch.RuneTable
 
-	_ = table.FromBytes(bytes.Split(PrintSyntaxError(data, at, opts...), []byte("\n")))
+	_ = table.FromBytes(lines)
 	// dbg.AssertErr(err, "table.FromBytes(data)")
 
 	_ = BoxStyle.Apply(&table)
 	// dbg.AssertErr(err, "BoxStyle.Apply(&table)")
 
-	return table.Byte()
+	boxed := table.Byte()
+
+	if _, ok := color_codes[s.color]; !ok {
+		return boxed
+	}
+
+	// The rows of the box are the lines prefixed by the side border and the left
+	// padding, below the top border and the top padding.
+	rows := bytes.Split(boxed, []byte{'\n'})
+
+	top := 1 + max(BoxStyle.Padding[0], 0)
+	if top+len(lines) > len(rows) {
+		return boxed
+	}
+
+	prefix := utf8.RuneLen(BoxStyle.SideBorder()) + max(BoxStyle.Padding[3], 0)
+
+	s.paint_lines(rows[top:top+len(lines)], faulty, gutter, prefix, ex)
+
+	return bytes.Join(rows, []byte{'\n'})
 }
 
 // DisplayError is a helper function that displays the error.
@@ -222,68 +438,181 @@ func PrintBoxedData(data []byte, at int, opts ...PrintOption) []byte {
 //
 // Returns:
 //   - string: The error data.
+//
+// The heading locates lexing and parsing errors alike, with the 1-based
+// character and line of the error; parsing errors used to be located with
+// 0-based coordinates, one character and one line before the error.
 func DisplayError(data []byte, err error, opts ...PrintOption) string {
 	if err == nil {
 		return ""
 	}
 
+	s := new_settings(opts)
+
 	var builder strings.Builder
 
-	switch reason := err.(type) {
+	if diags, ok := err.(*Diagnostics); ok {
+		s.display_all(&builder, data, diags, opts)
+	} else {
+		s.display_one(&builder, data, err, opts)
+	}
+
+	return builder.String()
+}
+
+// display_one is a helper function that displays an error.
+//
+// Parameters:
+//   - builder: The builder to write to.
+//   - data: The data read from the input stream.
+//   - err: The error. Assumed to be non-nil.
+//   - opts: The print options.
+func (s *PrintSettings) display_one(builder *strings.Builder, data []byte, err error, opts []PrintOption) {
+	var (
+		phase  Phase
+		reason error
+		at     int
+		delta  int
+		start  gr.Position
+		hint   string
+	)
+
+	switch err := err.(type) {
 	case *lexing.ErrLexing:
-		x, y := gcby.DetermineCoords(data, reason.StartPos)
+		phase, reason, at, delta, start, hint = PhaseLexing, err.Reason, err.StartPos, err.Delta, err.Start, err.Suggestion
+	case *ErrParsing:
+		phase, reason, at, delta, start, hint = PhaseParsing, err.Reason, err.StartPos, err.Delta, err.Start, err.Suggestion
+	default:
+		builder.WriteString(s.catalog.unknown(err))
 
-		builder.WriteString("Lexing error at the ")
-		builder.WriteString(gcint.GetOrdinalSuffix(x + 1))
-		builder.WriteString(" character of the ")
-		builder.WriteString(gcint.GetOrdinalSuffix(y + 1))
-		builder.WriteString(" line:")
-		builder.WriteRune('\n')
-		builder.WriteRune('\t')
-		builder.WriteString(reason.Reason.Error())
-		builder.WriteRune('\n')
-		builder.WriteRune('\n')
+		return
+	}
 
-		opts = append(opts, WithDelta(reason.Delta))
+	x, y := coords(data, at, start)
 
-		_, _ = builder.Write(PrintBoxedData(data, reason.StartPos, opts...))
+	builder.WriteString(s.catalog.heading(code_of(phase, reason), phase, x+1, y+1))
+	builder.WriteRune('\n')
+	builder.WriteRune('\t')
+	builder.WriteString(s.catalog.reason(reason))
+	builder.WriteRune('\n')
+	builder.WriteRune('\n')
+
+	_, _ = builder.Write(PrintBoxedData(data, at, append(slices.Clip(opts), WithDelta(delta))...))
+	builder.WriteRune('\n')
+
+	if hint != "" {
 		builder.WriteRune('\n')
+		builder.Write(s.color.paint(role_hint, []byte(s.catalog.hint(hint))))
+	}
+}
+
+// display_all is a helper function that displays every reported diagnostic of a
+// bag, in the order of their spans, followed by the number of errors and
+// warnings. The lexing and parsing errors are displayed as by DisplayError.
+//
+// Parameters:
+//   - builder: The builder to write to.
+//   - data: The data read from the input stream.
+//   - diags: The bag. Assumed to be non-nil.
+//   - opts: The print options.
+func (s *PrintSettings) display_all(builder *strings.Builder, data []byte, diags *Diagnostics, opts []PrintOption) {
+	for _, diag := range diags.Sorted() {
+		var lex_err *lexing.ErrLexing
+		var parse_err *ErrParsing
+
+		switch {
+		case errors.As(diag.Err, &lex_err):
+			s.display_one(builder, data, lex_err, opts)
+		case errors.As(diag.Err, &parse_err):
+			s.display_one(builder, data, parse_err, opts)
+		default:
+			s.display_diagnostic(builder, data, diag, opts)
+		}
 
-		suggestion := reason.Suggestion
-		if suggestion != "" {
+		if !strings.HasSuffix(builder.String(), "\n") {
 			builder.WriteRune('\n')
-			builder.WriteString("Hint: ")
-			builder.WriteString(suggestion)
 		}
-	case *ErrParsing:
-		x, y := gcby.DetermineCoords(data, reason.StartPos)
 
-		builder.WriteString("Parsing error at the ")
-		builder.WriteString(gcint.GetOrdinalSuffix(x))
-		builder.WriteString(" character of the ")
-		builder.WriteString(gcint.GetOrdinalSuffix(y))
-		builder.WriteString(" line:")
-		builder.WriteRune('\n')
-		builder.WriteRune('\t')
-		builder.WriteString(reason.Reason.Error())
-		builder.WriteRune('\n')
-		builder.WriteRune('\n')
+		for _, rel := range diag.Related {
+			x, y := s.position_of(data, rel.Span)
 
-		opts = append(opts, WithDelta(reason.Delta))
+			builder.WriteString(s.catalog.related(rel.Message, x, y))
+			builder.WriteRune('\n')
+		}
 
-		_, _ = builder.Write(PrintBoxedData(data, reason.StartPos, opts...))
 		builder.WriteRune('\n')
+	}
 
-		suggestion := reason.Suggestion
-		if suggestion != "" {
-			builder.WriteRune('\n')
-			builder.WriteString("Hint: ")
-			builder.WriteString(suggestion)
-		}
-	default:
-		builder.WriteString("Error: ")
-		builder.WriteString(err.Error())
+	builder.WriteString(s.catalog.summary(diags.Count(gr.SeverityError), diags.Count(gr.SeverityWarning)))
+}
+
+// display_diagnostic is a helper function that displays a diagnostic that is
+// neither a lexing nor a parsing error.
+//
+// Parameters:
+//   - builder: The builder to write to.
+//   - data: The data read from the input stream.
+//   - diag: The diagnostic.
+//   - opts: The print options.
+func (s *PrintSettings) display_diagnostic(builder *strings.Builder, data []byte, diag Diagnostic, opts []PrintOption) {
+	x, y := s.position_of(data, diag.Span)
+
+	builder.WriteString(s.catalog.located(diag.Code, diag.Severity, x, y))
+	builder.WriteRune('\n')
+	builder.WriteRune('\t')
+	builder.WriteString(s.catalog.reason(diag.Err))
+	builder.WriteRune('\n')
+
+	if y == 0 {
+		return
 	}
 
-	return builder.String()
+	builder.WriteRune('\n')
+
+	if !diag.Span.IsEmpty() {
+		opts = append(slices.Clip(opts), WithDelta(diag.Span.Len()))
+	}
+
+	_, _ = builder.Write(PrintBoxedData(data, diag.Span.Start, opts...))
+	builder.WriteRune('\n')
+}
+
+// position_of is a helper function that returns the 1-based coordinates of a
+// span.
+//
+// Parameters:
+//   - data: The data read from the input stream.
+//   - span: The span.
+//
+// Returns:
+//   - int: The character. 0 if the span is unknown or outside of the data.
+//   - int: The line. 0 if the span is unknown or outside of the data.
+func (s *PrintSettings) position_of(data []byte, span grm.Span) (int, int) {
+	if span.Start < 0 || span.Start > len(data) || len(data) == 0 {
+		return 0, 0
+	}
+
+	x, y := text.Coords(data, span.Start)
+
+	return x + 1, y + 1
+}
+
+// coords is a helper function that returns the 0-based coordinates of an error.
+// The position carried by the error is used when known; otherwise, the coordinates
+// are computed by scanning the data.
+//
+// Parameters:
+//   - data: The data read from the input stream.
+//   - offset: The offset of the error.
+//   - pos: The position of the error.
+//
+// Returns:
+//   - int: The column.
+//   - int: The line.
+func coords(data []byte, offset int, pos gr.Position) (int, int) {
+	if pos.IsKnown() {
+		return pos.Column - 1, pos.Line - 1
+	}
+
+	return text.Coords(data, offset)
 }
@@ -0,0 +1,63 @@
+package displayer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	"github.com/PlayerR9/grammar/PREV/OLD/lexing"
+)
+
+func TestCoords(t *testing.T) {
+	data := []byte("x = 1\nsé = ?\n")
+
+	// The error is at "?", on the second line.
+	offset := 12
+
+	pos := gr.StartPosition().Advance(data[:offset])
+
+	x, y := coords(data, offset, pos)
+	fx, fy := coords(data, offset, gr.Position{Offset: offset})
+
+	if x != fx || y != fy {
+		t.Errorf("expected the same coordinates with and without the position, got (%d, %d) and (%d, %d)", x, y, fx, fy)
+	}
+
+	if x != 5 || y != 1 {
+		t.Errorf("expected (5, 1), got (%d, %d)", x, y)
+	}
+}
+
+func TestDisplayErrorHeading(t *testing.T) {
+	data := []byte("x = 1\ny = ?\n")
+
+	// The error is at "?", the 5th character of the 2nd line.
+	offset := 10
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "lexing",
+			err:  lexing.NewErrLexing(offset, 1, errors.New("unexpected character")),
+			want: "[E0100] Lexing error at the 5th character of the 2nd line:",
+		},
+		{
+			name: "parsing",
+			err:  NewErrParsing(offset, 1, errors.New("unexpected token")),
+			want: "[E0002] Parsing error at the 5th character of the 2nd line:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, _ := strings.Cut(DisplayError(data, tt.err), "\n")
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
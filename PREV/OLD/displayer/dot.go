@@ -0,0 +1,73 @@
+package displayer
+
+import (
+	"bytes"
+	"strconv"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	"github.com/PlayerR9/grammar/internal/text"
+)
+
+// ToDOT renders a token tree as a Graphviz graph of the DOT language. Each token
+// is a node labeled with its type, its data (if any) and its position; and the
+// edges go from the tokens to their children, in order.
+//
+// Parameters:
+//   - tree: The root of the token tree.
+//
+// Returns:
+//   - []byte: The DOT graph. An empty graph if tree is nil.
+func ToDOT[S gr.TokenTyper](tree *gr.Token[S]) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("digraph tree {\n")
+	buf.WriteString("\tnode [shape=box, fontname=\"monospace\"];\n")
+
+	if tree != nil {
+		type entry struct {
+			tk *gr.Token[S]
+			id int
+		}
+
+		stack := []entry{{tk: tree, id: 0}}
+		next_id := 1
+
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			label := top.tk.Type.String()
+
+			if top.tk.Data != "" {
+				label += "\n" + strconv.Quote(top.tk.Data)
+			}
+
+			if top.tk.At >= 0 {
+				label += "\nat " + strconv.Itoa(top.tk.At)
+			}
+
+			name := "n" + strconv.Itoa(top.id)
+
+			buf.WriteString("\t" + name + " [label=" + text.DOTString(label) + "];\n")
+
+			var children []entry
+
+			for c := top.tk.FirstChild; c != nil; c = c.NextSibling {
+				child := entry{tk: c, id: next_id}
+				next_id++
+
+				buf.WriteString("\t" + name + " -> n" + strconv.Itoa(child.id) + ";\n")
+
+				children = append(children, child)
+			}
+
+			for i := len(children) - 1; i >= 0; i-- {
+				stack = append(stack, children[i])
+			}
+		}
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.Bytes()
+}
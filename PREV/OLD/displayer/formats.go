@@ -0,0 +1,416 @@
+package displayer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"unicode/utf8"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	"github.com/PlayerR9/grammar/PREV/OLD/lexing"
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+// The types below are the JSON form of the diagnostics written by ToJSON.
+type (
+	json_report struct {
+		Diagnostics []json_diagnostic `json:"diagnostics"`
+		Errors      int               `json:"errors"`
+		Warnings    int               `json:"warnings"`
+	}
+
+	json_diagnostic struct {
+		Code     string         `json:"code,omitempty"`
+		Severity string         `json:"severity"`
+		Message  string         `json:"message"`
+		Location *json_location `json:"location,omitempty"`
+		Hint     string         `json:"hint,omitempty"`
+		Related  []json_related `json:"related,omitempty"`
+	}
+
+	json_related struct {
+		Message  string         `json:"message"`
+		Location *json_location `json:"location,omitempty"`
+	}
+
+	json_location struct {
+		Start       int `json:"start"`
+		End         int `json:"end"`
+		StartLine   int `json:"startLine,omitempty"`
+		StartColumn int `json:"startColumn,omitempty"`
+		EndLine     int `json:"endLine,omitempty"`
+		EndColumn   int `json:"endColumn,omitempty"`
+	}
+)
+
+// The types below are the subset of the SARIF 2.1.0 format written by ToSARIF.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+type (
+	sarif_log struct {
+		Version string      `json:"version"`
+		Schema  string      `json:"$schema"`
+		Runs    []sarif_run `json:"runs"`
+	}
+
+	sarif_run struct {
+		Tool       sarif_tool     `json:"tool"`
+		ColumnKind string         `json:"columnKind"`
+		Results    []sarif_result `json:"results"`
+	}
+
+	sarif_tool struct {
+		Driver sarif_driver `json:"driver"`
+	}
+
+	sarif_driver struct {
+		Name  string       `json:"name"`
+		Rules []sarif_rule `json:"rules"`
+	}
+
+	sarif_rule struct {
+		ID string `json:"id"`
+	}
+
+	sarif_message struct {
+		Text string `json:"text"`
+	}
+
+	sarif_result struct {
+		RuleID           string            `json:"ruleId,omitempty"`
+		Level            string            `json:"level"`
+		Message          sarif_message     `json:"message"`
+		Locations        []sarif_location  `json:"locations,omitempty"`
+		RelatedLocations []sarif_location  `json:"relatedLocations,omitempty"`
+		Properties       map[string]string `json:"properties,omitempty"`
+	}
+
+	sarif_location struct {
+		PhysicalLocation sarif_physical `json:"physicalLocation"`
+		Message          *sarif_message `json:"message,omitempty"`
+	}
+
+	sarif_physical struct {
+		ArtifactLocation sarif_artifact `json:"artifactLocation"`
+		Region           sarif_region   `json:"region"`
+	}
+
+	sarif_artifact struct {
+		URI string `json:"uri"`
+	}
+
+	sarif_region struct {
+		CharOffset  *int `json:"charOffset,omitempty"`
+		CharLength  *int `json:"charLength,omitempty"`
+		ByteOffset  *int `json:"byteOffset,omitempty"`
+		ByteLength  *int `json:"byteLength,omitempty"`
+		StartLine   int  `json:"startLine,omitempty"`
+		StartColumn int  `json:"startColumn,omitempty"`
+		EndLine     int  `json:"endLine,omitempty"`
+		EndColumn   int  `json:"endColumn,omitempty"`
+	}
+)
+
+// ToJSON writes the reported diagnostics of a bag as a JSON document, in the
+// order of their spans, with the number of errors and warnings.
+//
+// Parameters:
+//   - diags: The bag.
+//   - data: The data read from the input stream. Used to compute the lines and
+//     columns of the diagnostics; if nil, only their offsets are written.
+//
+// Returns:
+//   - []byte: The document, indented.
+//   - error: An error if diags is nil.
+//
+// The lines and columns are 1-based; the columns count characters, not bytes.
+func ToJSON(diags *Diagnostics, data []byte) ([]byte, error) {
+	if diags == nil {
+		return nil, gcers.NewErrNilParameter("diags")
+	}
+
+	report := json_report{
+		Diagnostics: []json_diagnostic{},
+		Errors:      diags.Count(gr.SeverityError),
+		Warnings:    diags.Count(gr.SeverityWarning),
+	}
+
+	for _, diag := range diags.Sorted() {
+		jd := json_diagnostic{
+			Code:     string(code_of_diagnostic(diag)),
+			Severity: diag.Severity.String(),
+			Message:  message_of(diag.Err),
+			Location: json_location_of(data, diag.Span),
+			Hint:     hint_of(diag.Err),
+		}
+
+		for _, rel := range diag.Related {
+			jd.Related = append(jd.Related, json_related{
+				Message:  rel.Message,
+				Location: json_location_of(data, rel.Span),
+			})
+		}
+
+		report.Diagnostics = append(report.Diagnostics, jd)
+	}
+
+	return json.MarshalIndent(report, "", "  ")
+}
+
+// ToSARIF writes the reported diagnostics of a bag as a SARIF 2.1.0 log, with
+// one run whose rules are the codes of the diagnostics; so that they can be
+// consumed by CI systems and editors.
+//
+// Parameters:
+//   - diags: The bag.
+//   - data: The data read from the input stream. Used to compute the lines and
+//     columns of the diagnostics; if nil, only their offsets are written.
+//   - uri: The URI of the input stream (e.g., its path relative to the root of
+//     the repository).
+//
+// Returns:
+//   - []byte: The log, indented.
+//   - error: An error if diags is nil.
+//
+// The regions hold the character offsets and the lines and columns of the
+// diagnostics; without the data, they only hold their byte offsets.
+func ToSARIF(diags *Diagnostics, data []byte, uri string) ([]byte, error) {
+	if diags == nil {
+		return nil, gcers.NewErrNilParameter("diags")
+	}
+
+	var codes []string
+
+	results := []sarif_result{}
+
+	for _, diag := range diags.Sorted() {
+		code := string(code_of_diagnostic(diag))
+		if code != "" {
+			codes = append(codes, code)
+		}
+
+		res := sarif_result{
+			RuleID:  code,
+			Level:   sarif_level(diag.Severity),
+			Message: sarif_message{Text: message_of(diag.Err)},
+		}
+
+		loc, ok := sarif_location_of(data, uri, diag.Span, nil)
+		if ok {
+			res.Locations = []sarif_location{loc}
+		}
+
+		for _, rel := range diag.Related {
+			loc, ok := sarif_location_of(data, uri, rel.Span, &sarif_message{Text: rel.Message})
+			if ok {
+				res.RelatedLocations = append(res.RelatedLocations, loc)
+			}
+		}
+
+		if hint := hint_of(diag.Err); hint != "" {
+			res.Properties = map[string]string{"hint": hint}
+		}
+
+		results = append(results, res)
+	}
+
+	slices.Sort(codes)
+
+	rules := []sarif_rule{}
+
+	for _, code := range slices.Compact(codes) {
+		rules = append(rules, sarif_rule{ID: code})
+	}
+
+	log := sarif_log{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarif_run{{
+			Tool:       sarif_tool{Driver: sarif_driver{Name: "grammar", Rules: rules}},
+			ColumnKind: "unicodeCodePoints",
+			Results:    results,
+		}},
+	}
+
+	return json.MarshalIndent(log, "", "  ")
+}
+
+// code_of_diagnostic is a helper function that returns the code of a diagnostic.
+// The lexing and parsing errors without a code have the generic code of their
+// phase, as in DisplayError.
+//
+// Parameters:
+//   - diag: The diagnostic.
+//
+// Returns:
+//   - gr.Code: The code. Empty if the diagnostic has none.
+func code_of_diagnostic(diag Diagnostic) gr.Code {
+	if diag.Code != "" {
+		return diag.Code
+	}
+
+	var lex_err *lexing.ErrLexing
+	var parse_err *ErrParsing
+
+	switch {
+	case errors.As(diag.Err, &lex_err):
+		return code_of(PhaseLexing, lex_err.Reason)
+	case errors.As(diag.Err, &parse_err):
+		return code_of(PhaseParsing, parse_err.Reason)
+	default:
+		return ""
+	}
+}
+
+// message_of is a helper function that returns the message of the error of a
+// diagnostic. The lexing and parsing errors are described by their reason.
+//
+// Parameters:
+//   - err: The error.
+//
+// Returns:
+//   - string: The message.
+func message_of(err error) string {
+	var lex_err *lexing.ErrLexing
+	var parse_err *ErrParsing
+
+	switch {
+	case errors.As(err, &lex_err) && lex_err.Reason != nil:
+		return lex_err.Reason.Error()
+	case errors.As(err, &parse_err) && parse_err.Reason != nil:
+		return parse_err.Reason.Error()
+	default:
+		return err.Error()
+	}
+}
+
+// hint_of is a helper function that returns the suggestion of the error of a
+// diagnostic.
+//
+// Parameters:
+//   - err: The error.
+//
+// Returns:
+//   - string: The suggestion. Empty if there is none.
+func hint_of(err error) string {
+	var lex_err *lexing.ErrLexing
+	var parse_err *ErrParsing
+
+	switch {
+	case errors.As(err, &lex_err):
+		return lex_err.Suggestion
+	case errors.As(err, &parse_err):
+		return parse_err.Suggestion
+	default:
+		return ""
+	}
+}
+
+// line_column is a helper function that returns the 1-based line and column of
+// an offset of the data. The columns count characters.
+//
+// Parameters:
+//   - data: The data.
+//   - offset: The offset. Assumed to be within [0, len(data)].
+//
+// Returns:
+//   - int: The line.
+//   - int: The column.
+func line_column(data []byte, offset int) (int, int) {
+	line_start := bytes.LastIndexByte(data[:offset], '\n') + 1
+
+	return bytes.Count(data[:offset], []byte{'\n'}) + 1, utf8.RuneCount(data[line_start:offset]) + 1
+}
+
+// json_location_of is a helper function that returns the JSON location of a span.
+//
+// Parameters:
+//   - data: The data. Nil if the lines and columns are not computed.
+//   - span: The span.
+//
+// Returns:
+//   - *json_location: The location. Nil if the span is unknown.
+func json_location_of(data []byte, span grm.Span) *json_location {
+	if span.Start < 0 {
+		return nil
+	}
+
+	loc := &json_location{
+		Start: span.Start,
+		End:   max(span.End, span.Start),
+	}
+
+	if data != nil && loc.End <= len(data) {
+		loc.StartLine, loc.StartColumn = line_column(data, loc.Start)
+		loc.EndLine, loc.EndColumn = line_column(data, loc.End)
+	}
+
+	return loc
+}
+
+// sarif_location_of is a helper function that returns the SARIF location of a
+// span.
+//
+// Parameters:
+//   - data: The data. Nil if the lines and columns are not computed.
+//   - uri: The URI of the data.
+//   - span: The span.
+//   - msg: The message of the location. Nil if there is none.
+//
+// Returns:
+//   - sarif_location: The location.
+//   - bool: False if the span is unknown, true otherwise.
+func sarif_location_of(data []byte, uri string, span grm.Span, msg *sarif_message) (sarif_location, bool) {
+	jl := json_location_of(data, span)
+	if jl == nil {
+		return sarif_location{}, false
+	}
+
+	region := sarif_region{
+		StartLine:   jl.StartLine,
+		StartColumn: jl.StartColumn,
+		EndLine:     jl.EndLine,
+		EndColumn:   jl.EndColumn,
+	}
+
+	// The columns of the run count characters, and so do the character offsets.
+	// Without the data, only the byte offsets are known.
+	offset, length := jl.Start, jl.End-jl.Start
+
+	if jl.StartLine > 0 {
+		offset, length = utf8.RuneCount(data[:jl.Start]), utf8.RuneCount(data[jl.Start:jl.End])
+
+		region.CharOffset, region.CharLength = &offset, &length
+	} else {
+		region.ByteOffset, region.ByteLength = &offset, &length
+	}
+
+	loc := sarif_location{
+		PhysicalLocation: sarif_physical{
+			ArtifactLocation: sarif_artifact{URI: uri},
+			Region:           region,
+		},
+		Message: msg,
+	}
+
+	return loc, true
+}
+
+// sarif_level is a helper function that returns the SARIF level of a severity.
+//
+// Parameters:
+//   - s: The severity.
+//
+// Returns:
+//   - string: The level.
+func sarif_level(s gr.Severity) string {
+	switch s {
+	case gr.SeverityError:
+		return "error"
+	case gr.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,85 @@
+package displayer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+// format_test_data is the input of the diagnostics of new_format_test_bag. Its
+// first line has characters of several bytes, so that the character and byte
+// offsets differ.
+const format_test_data = "héllo wörld\nfoo bar\n"
+
+// new_format_test_bag is a helper function that makes a bag of diagnostics over
+// format_test_data: an error with a suggestion, a warning with a related location
+// and a diagnostic without location.
+func new_format_test_bag() *Diagnostics {
+	diags := NewDiagnostics()
+
+	parse_err := NewErrParsing(18, 3, errors.New("unexpected \"bar\""))
+	parse_err.SetSuggestion("Remove it.")
+
+	diags.Add(parse_err.Span(), parse_err)
+
+	diags.Report(Diagnostic{
+		Code:     gr.CodePrefixOverlap,
+		Span:     grm.NewSpan(7, 13),
+		Err:      errors.New("\"wörld\" overlaps \"wö\""),
+		Severity: gr.SeverityWarning,
+		Related: []Related{
+			{Span: grm.NewSpan(0, 6), Message: "first word"},
+		},
+	})
+
+	diags.Add(NoSpan, errors.New("no location"))
+
+	return diags
+}
+
+// check_golden is a helper function that compares the given output with the
+// golden file of the given name in testdata.
+func check_golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	want, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if string(got)+"\n" != string(want) {
+		t.Errorf("expected the output of %s\n%s\ngot\n%s", name, want, got)
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	got, err := ToJSON(new_format_test_bag(), []byte(format_test_data))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	check_golden(t, "report.json", got)
+}
+
+func TestToSARIF(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"report.sarif", []byte(format_test_data)},
+		{"report_bytes.sarif", nil},
+	}
+
+	for _, test := range tests {
+		got, err := ToSARIF(new_format_test_bag(), test.data, "input.txt")
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", test.name, err)
+		}
+
+		check_golden(t, test.name, got)
+	}
+}
@@ -1,5 +1,7 @@
 package displayer
 
+import "os"
+
 // PrintOptions are options that can be passed to the Print function.
 type PrintOption func(s *PrintSettings)
 
@@ -80,3 +82,66 @@ func WithFixedTabSize(tab_size int) PrintOption {
 		s.tab_size = tab_size
 	}
 }
+
+// WithCatalog sets the catalog used to format the messages.
+// If the catalog is nil, DefaultCatalog is used.
+//
+// Parameters:
+//   - catalog: The catalog.
+//
+// Returns:
+//   - PrintOption: The function that sets the catalog.
+func WithCatalog(catalog *Catalog) PrintOption {
+	if catalog == nil {
+		catalog = DefaultCatalog
+	}
+
+	return func(s *PrintSettings) {
+		s.catalog = catalog
+	}
+}
+
+// WithColor sets the profile used to style the faulty span, the arrow, the line
+// numbers and the hints with ANSI escapes. ColorAuto is resolved with
+// DetectColor on the standard output; so that the styling is disabled when the
+// output is not a terminal.
+//
+// Parameters:
+//   - profile: The profile.
+//
+// Returns:
+//   - PrintOption: The function that sets the profile.
+func WithColor(profile ColorProfile) PrintOption {
+	if profile == ColorAuto {
+		profile = DetectColor(os.Stdout)
+	}
+
+	return func(s *PrintSettings) {
+		s.color = profile
+	}
+}
+
+// WithLineNumbers prefixes the printed lines with a gutter that holds their
+// 1-based number in the input.
+//
+// Returns:
+//   - PrintOption: The function that enables the line numbers.
+func WithLineNumbers() PrintOption {
+	return func(s *PrintSettings) {
+		s.line_numbers = true
+	}
+}
+
+// WithFileName adds a header with the location of the faulty token, in the
+// "--> <name>:<line>:<column>" format of compilers, above the printed lines.
+//
+// Parameters:
+//   - name: The name of the file. If empty, there is no header.
+//
+// Returns:
+//   - PrintOption: The function that sets the name of the file.
+func WithFileName(name string) PrintOption {
+	return func(s *PrintSettings) {
+		s.file_name = name
+	}
+}
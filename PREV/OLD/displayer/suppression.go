@@ -0,0 +1,166 @@
+package displayer
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+// DefaultDirective is the default suppression directive. A comment such as
+// "//grammar:ignore E0102 W0201" suppresses the listed codes; without codes,
+// every diagnostic is suppressed.
+const DefaultDirective string = "//grammar:ignore"
+
+// Suppression suppresses diagnostics in a range of the input.
+type Suppression struct {
+	// Span is the suppressed range of the input.
+	Span grm.Span
+
+	// Codes are the suppressed codes. If empty, every code is suppressed.
+	Codes []gr.Code
+}
+
+// Covers checks whether the suppression covers the given diagnostic.
+//
+// Parameters:
+//   - diag: The diagnostic.
+//
+// Returns:
+//   - bool: True if the diagnostic is suppressed, false otherwise.
+//
+// Diagnostics with an unknown position are never suppressed.
+func (s Suppression) Covers(diag Diagnostic) bool {
+	if !s.Span.Contains(diag.Span.Start) {
+		return false
+	}
+
+	return len(s.Codes) == 0 || slices.Contains(s.Codes, diag.Code)
+}
+
+// ParseSuppression parses the text of a comment as a suppression directive. Use it
+// on the trivia of a token when comments are kept as trivia.
+//
+// Parameters:
+//   - directive: The directive of the tool (e.g., DefaultDirective).
+//   - text: The text of the comment.
+//   - span: The range to suppress.
+//
+// Returns:
+//   - Suppression: The suppression.
+//   - bool: True if the text is a suppression directive, false otherwise.
+func ParseSuppression(directive, text string, span grm.Span) (Suppression, bool) {
+	text = strings.TrimSpace(text)
+
+	rest, ok := strings.CutPrefix(text, directive)
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return Suppression{}, false
+	}
+
+	var codes []gr.Code
+
+	for _, field := range strings.Fields(rest) {
+		codes = append(codes, gr.Code(field))
+	}
+
+	return Suppression{
+		Span:  span,
+		Codes: codes,
+	}, true
+}
+
+// ScanSuppressions reads the suppression directives from the comments kept as
+// trivia on the tokens; so that a directive inside a string literal, or any other
+// token, is not taken for a comment. A directive covers the line it is on and the
+// line after it.
+//
+// Parameters:
+//   - data: The input.
+//   - tokens: The tokens of the input, lexed with the trivia kept (see
+//     lexing.KeepTrivia).
+//   - directive: The directive of the tool (e.g., DefaultDirective).
+//
+// Returns:
+//   - []Suppression: The suppressions, in order of appearance.
+//
+// Nil tokens and tokens with an unknown position are skipped.
+func ScanSuppressions[S gr.TokenTyper](data []byte, tokens []*gr.Token[S], directive string) []Suppression {
+	if directive == "" {
+		return nil
+	}
+
+	var suppressions []Suppression
+
+	for _, tk := range tokens {
+		if tk == nil || tk.StartAt < 0 {
+			continue
+		}
+
+		suppressions = scan_trivia(suppressions, data, tk.LeadingTrivia, tk.StartAt-len(tk.LeadingTrivia), directive)
+		suppressions = scan_trivia(suppressions, data, tk.TrailingTrivia, tk.EndAt, directive)
+	}
+
+	return suppressions
+}
+
+// scan_trivia is a helper function that appends the suppressions of the lines of
+// a trivia.
+//
+// Parameters:
+//   - suppressions: The suppressions found so far.
+//   - data: The input.
+//   - trivia: The trivia.
+//   - offset: The offset of the trivia in the input.
+//   - directive: The directive of the tool. Assumed to be non-empty.
+//
+// Returns:
+//   - []Suppression: The suppressions, with those of the trivia appended.
+func scan_trivia(suppressions []Suppression, data []byte, trivia string, offset int, directive string) []Suppression {
+	for len(trivia) > 0 {
+		line, rest, _ := strings.Cut(trivia, "\n")
+
+		idx := strings.Index(line, directive)
+		if idx != -1 {
+			s, ok := ParseSuppression(directive, line[idx:], lines_span(data, offset+idx))
+			if ok {
+				suppressions = append(suppressions, s)
+			}
+		}
+
+		offset += len(trivia) - len(rest)
+		trivia = rest
+	}
+
+	return suppressions
+}
+
+// lines_span is a helper function that returns the span of the line that holds
+// the given offset and of the line after it.
+//
+// Parameters:
+//   - data: The input.
+//   - offset: The offset.
+//
+// Returns:
+//   - grm.Span: The span, newline of the second line excluded.
+func lines_span(data []byte, offset int) grm.Span {
+	offset = min(offset, len(data))
+
+	start := bytes.LastIndexByte(data[:offset], '\n') + 1
+
+	end := bytes.IndexByte(data[offset:], '\n')
+	if end == -1 {
+		return grm.NewSpan(start, len(data))
+	}
+
+	end += offset + 1
+
+	next := bytes.IndexByte(data[end:], '\n')
+	if next == -1 {
+		return grm.NewSpan(start, len(data))
+	}
+
+	return grm.NewSpan(start, end+next)
+}
@@ -0,0 +1,86 @@
+package displayer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+// supp_type is the token type of the suppression tests.
+type supp_type int
+
+const (
+	st_eof supp_type = iota
+	st_word
+	st_string
+)
+
+// String implements the fmt.Stringer interface.
+func (t supp_type) String() string {
+	return [...]string{"EOF", "WORD", "STRING"}[t]
+}
+
+// GoString implements the fmt.GoStringer interface.
+func (t supp_type) GoString() string {
+	return t.String()
+}
+
+func TestScanSuppressions(t *testing.T) {
+	const input = "s := \"//grammar:ignore\"\n//grammar:ignore E0001\nx\n"
+
+	data := []byte(input)
+
+	// The tokens as a lexer that keeps the trivia makes them.
+	words := []struct {
+		type_             supp_type
+		data              string
+		leading, trailing string
+	}{
+		{st_word, "s", "", ""},
+		{st_word, ":=", " ", ""},
+		{st_string, "\"//grammar:ignore\"", " ", "\n"},
+		{st_word, "x", "//grammar:ignore E0001\n", "\n"},
+		{st_eof, "", "", ""},
+	}
+
+	var tokens []*gr.Token[supp_type]
+
+	at := 0
+
+	for _, w := range words {
+		at += len(w.leading)
+
+		tk := gr.NewToken(w.type_, w.data, at, nil)
+		tk.LeadingTrivia = w.leading
+		tk.TrailingTrivia = w.trailing
+
+		tokens = append(tokens, tk)
+
+		at += len(w.data) + len(w.trailing)
+	}
+
+	if got := gr.Reconstruct(tokens); got != input {
+		t.Fatalf("expected the tokens to cover %q, got %q", input, got)
+	}
+
+	suppressions := ScanSuppressions(data, tokens, DefaultDirective)
+	if len(suppressions) != 1 {
+		t.Fatalf("expected 1 suppression, got %d", len(suppressions))
+	}
+
+	start := strings.Index(input, "\n//") + 1
+	want := grm.NewSpan(start, len(input)-1)
+
+	s := suppressions[0]
+
+	if s.Span != want {
+		t.Errorf("expected the span %v, got %v", want, s.Span)
+	}
+
+	if !slices.Equal(s.Codes, []gr.Code{gr.CodeUnexpectedToken}) {
+		t.Errorf("expected the codes %v, got %v", []gr.Code{gr.CodeUnexpectedToken}, s.Codes)
+	}
+}
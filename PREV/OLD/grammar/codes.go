@@ -0,0 +1,177 @@
+package grammar
+
+import (
+	"errors"
+	"strings"
+
+	gcch "github.com/PlayerR9/go-commons/runes"
+	gcstr "github.com/PlayerR9/go-commons/strings"
+	grm "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/grammar/lexer"
+)
+
+// Code is the stable code of a kind of diagnostic. Codes starting with 'E' are
+// errors and codes starting with 'W' are warnings. Once assigned, a code never
+// changes meaning, so it can be used in suppression lists and documentation links.
+type Code string
+
+const (
+	// CodeUnexpectedToken is the code of unexpected tokens while parsing.
+	CodeUnexpectedToken Code = "E0001"
+
+	// CodeParsing is the code of parsing errors that have no more specific code.
+	CodeParsing Code = "E0002"
+
+	// CodeLexing is the code of lexing errors that have no more specific code.
+	CodeLexing Code = "E0100"
+
+	// CodeUnexpectedChar is the code of unexpected characters while lexing.
+	CodeUnexpectedChar Code = "E0101"
+
+	// CodeUnterminatedString is the code of string literals that are not closed
+	// before the end of the input.
+	CodeUnterminatedString Code = "E0102"
+
+	// CodeInvalidEncoding is the code of inputs that are not valid UTF-8.
+	CodeInvalidEncoding Code = "E0103"
+
+	// CodeAmbiguousResolved is the code of ambiguities that were resolved.
+	CodeAmbiguousResolved Code = "W0201"
+
+	// CodePrefixOverlap is the code of words that are a prefix of another word.
+	CodePrefixOverlap Code = "W0202"
+
+	// CodeAst is the code of the errors while building the AST that have no more
+	// specific code.
+	CodeAst Code = "E0300"
+
+	// CodePanic is the code of user-supplied callbacks that panicked.
+	CodePanic Code = "E0900"
+)
+
+// IsWarning checks whether the code is the code of a warning.
+//
+// Returns:
+//   - bool: True if the code is the code of a warning, false otherwise.
+func (c Code) IsWarning() bool {
+	return strings.HasPrefix(string(c), "W")
+}
+
+// Severity returns the default severity of the diagnostics with the code.
+//
+// Returns:
+//   - Severity: SeverityWarning for the codes of warnings, SeverityError otherwise.
+func (c Code) Severity() Severity {
+	if c.IsWarning() {
+		return SeverityWarning
+	}
+
+	return SeverityError
+}
+
+// Coder is implemented by the errors that have a diagnostic code.
+type Coder interface {
+	// Code returns the diagnostic code of the error.
+	//
+	// Returns:
+	//   - Code: The diagnostic code.
+	Code() Code
+}
+
+// CodeOf returns the diagnostic code of the given error; that is, the code of the
+// first error of its chain that implements Coder. The errors of the commons
+// library that the lexers return and the panics of user-supplied callbacks are
+// recognized too.
+//
+// Parameters:
+//   - err: The error.
+//
+// Returns:
+//   - Code: The diagnostic code.
+//   - bool: True if a code was found, false otherwise.
+func CodeOf(err error) (Code, bool) {
+	var coder Coder
+
+	if errors.As(err, &coder) {
+		return coder.Code(), true
+	}
+
+	var unexpected *gcstr.ErrUnexpectedRune
+
+	if errors.As(err, &unexpected) {
+		return CodeUnexpectedChar, true
+	}
+
+	var encoding *gcch.ErrInvalidUTF8Encoding
+
+	if errors.As(err, &encoding) {
+		return CodeInvalidEncoding, true
+	}
+
+	var unterminated *lexer.ErrUnterminated
+
+	if errors.As(err, &unterminated) {
+		return CodeUnterminatedString, true
+	}
+
+	var panicked *grm.ErrPanic
+
+	if errors.As(err, &panicked) {
+		return CodePanic, true
+	}
+
+	return "", false
+}
+
+// ErrCoded is an error to which a diagnostic code was attached.
+type ErrCoded struct {
+	// Kind is the diagnostic code.
+	Kind Code
+
+	// Reason is the reason of the error.
+	Reason error
+}
+
+// Error implements the error interface.
+//
+// Message: "<reason>"
+func (e ErrCoded) Error() string {
+	if e.Reason == nil {
+		return "something went wrong"
+	}
+
+	return e.Reason.Error()
+}
+
+// Unwrap returns the reason of the error.
+//
+// Returns:
+//   - error: The reason of the error.
+func (e ErrCoded) Unwrap() error {
+	return e.Reason
+}
+
+// Code implements the Coder interface.
+func (e ErrCoded) Code() Code {
+	return e.Kind
+}
+
+// WithCode attaches a diagnostic code to an error. Use it in custom lexing and
+// decision functions so that their errors are reported with a stable code.
+//
+// Parameters:
+//   - code: The diagnostic code.
+//   - reason: The error.
+//
+// Returns:
+//   - error: The error with the code, of type *ErrCoded. Nil if reason is nil.
+func WithCode(code Code, reason error) error {
+	if reason == nil {
+		return nil
+	}
+
+	return &ErrCoded{
+		Kind:   code,
+		Reason: reason,
+	}
+}
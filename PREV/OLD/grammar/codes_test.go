@@ -0,0 +1,36 @@
+package grammar
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	grm "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/grammar/lexer"
+)
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Code
+		ok   bool
+	}{
+		{"unterminated", lexer.NewErrUnterminated(3, '"'), CodeUnterminatedString, true},
+		{"wrapped unterminated", fmt.Errorf("lexing: %w", lexer.NewErrUnterminated(3, '"')), CodeUnterminatedString, true},
+		{"panic", grm.NewErrPanic("boom", nil), CodePanic, true},
+		{"coded", WithCode(CodeAmbiguousResolved, errors.New("ambiguous")), CodeAmbiguousResolved, true},
+		{"unknown", errors.New("unknown"), "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, ok := CodeOf(tt.err)
+			if ok != tt.ok {
+				t.Fatalf("expected %t, got %t", tt.ok, ok)
+			} else if code != tt.want {
+				t.Errorf("expected the code %q, got %q", tt.want, code)
+			}
+		})
+	}
+}
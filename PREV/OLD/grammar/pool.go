@@ -0,0 +1,212 @@
+package grammar
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// PoolStats are the allocation statistics of a token pool.
+type PoolStats struct {
+	// Gets is the number of tokens taken from the pool.
+	Gets uint64
+
+	// News is the number of tokens that had to be allocated because the pool was
+	// empty.
+	News uint64
+
+	// Puts is the number of tokens returned to the pool.
+	Puts uint64
+}
+
+// Reused returns the number of tokens taken from the pool that were not
+// allocated; that is, the number of allocations saved by the pool.
+//
+// Returns:
+//   - uint64: The number of reused tokens.
+func (s PoolStats) Reused() uint64 {
+	return s.Gets - s.News
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Format:
+//
+//	<gets> gets, <news> news, <puts> puts, <reused> reused
+func (s PoolStats) String() string {
+	return fmt.Sprintf("%d gets, %d news, %d puts, %d reused", s.Gets, s.News, s.Puts, s.Reused())
+}
+
+// TokenPool is a pool of tokens that lets the lexer and the parser reuse the tokens
+// of previous runs instead of allocating new ones. It is safe for concurrent use.
+//
+// A nil *TokenPool is valid: it allocates every token and discards the returned
+// ones.
+//
+// The pool only serves the tokens of this package: the lexing package takes its
+// tokens from it and the parsing package the tokens built by apply_reduce. The
+// reduce of the PREV/parser package is left out on purpose: its branches share
+// the nodes of their stacks, so no branch can tell when a node is no longer used.
+// The same goes for the root parser, whose results are memoized and shared by the
+// callers of Run.
+type TokenPool[S TokenTyper] struct {
+	// pool is the pool of free tokens.
+	pool sync.Pool
+
+	// gets, news and puts are the counters of PoolStats.
+	gets, news, puts atomic.Uint64
+}
+
+// NewTokenPool creates a new, empty token pool.
+//
+// Returns:
+//   - *TokenPool[S]: The new token pool. Never returns nil.
+func NewTokenPool[S TokenTyper]() *TokenPool[S] {
+	p := &TokenPool[S]{}
+
+	p.pool.New = func() any {
+		p.news.Add(1)
+
+		return &Token[S]{}
+	}
+
+	return p
+}
+
+// Get is like NewToken but takes the token from the pool.
+//
+// Parameters:
+//   - t_type: The type of the node.
+//   - data: The data of the node.
+//   - at: The position of the node in the source code.
+//   - lookahead: The lookahead of the node.
+//
+// Returns:
+//   - *Token[S]: The token. Never returns nil.
+func (p *TokenPool[S]) Get(t_type S, data string, at int, lookahead *Token[S]) *Token[S] {
+	if p == nil {
+		return NewToken(t_type, data, at, lookahead)
+	}
+
+	p.gets.Add(1)
+
+	tk := p.pool.Get().(*Token[S])
+
+	tk.pooled = false
+	tk.Type = t_type
+	tk.Data = data
+	tk.At = at
+	tk.Lookahead = lookahead
+	tk.StartAt, tk.EndAt = range_of(at, data)
+
+	return tk
+}
+
+// Copy is like Token.Copy but takes the copy from the pool.
+//
+// Parameters:
+//   - tk: The token to copy. Assumed to be non-nil.
+//
+// Returns:
+//   - *Token[S]: The copy. Never returns nil.
+func (p *TokenPool[S]) Copy(tk *Token[S]) *Token[S] {
+	if p == nil {
+		return tk.Copy()
+	}
+
+	cp := p.Get(tk.Type, tk.Data, tk.At, nil)
+
+	cp.Start = tk.Start
+	cp.End = tk.End
+	cp.StartAt = tk.StartAt
+	cp.EndAt = tk.EndAt
+	cp.LeadingTrivia = tk.LeadingTrivia
+	cp.TrailingTrivia = tk.TrailingTrivia
+
+	return cp
+}
+
+// put is a helper function that returns a token, already cleaned up, to the pool.
+// Tokens that are already in the pool are ignored.
+//
+// Parameters:
+//   - tk: The token. Assumed to be non-nil.
+func (p *TokenPool[S]) put(tk *Token[S]) {
+	if p == nil || tk.pooled {
+		return
+	}
+
+	*tk = Token[S]{
+		pooled: true,
+	}
+
+	p.puts.Add(1)
+
+	p.pool.Put(tk)
+}
+
+// CleanTokens is like the CleanTokens function but also returns the tokens, and
+// all of their descendants, to the pool.
+//
+// Parameters:
+//   - s: The tokens to clean.
+//
+// The tokens must no longer be used afterwards, neither directly nor through the
+// trees, lexers or parsers that hold them.
+func (p *TokenPool[S]) CleanTokens(s []*Token[S]) {
+	if len(s) == 0 {
+		return
+	}
+
+	var stack []*Token[S]
+
+	for i := len(s) - 1; i >= 0; i-- {
+		elem := s[i]
+		if elem == nil {
+			continue
+		}
+
+		tmp := elem.Cleanup()
+
+		if len(tmp) > 0 {
+			stack = append(stack, tmp...)
+		}
+
+		p.put(elem)
+
+		s[i] = nil
+	}
+
+	for len(stack) > 0 {
+		top := stack[0]
+		stack = stack[1:]
+
+		if top == nil {
+			continue
+		}
+
+		tmp := top.Cleanup()
+
+		if len(tmp) > 0 {
+			stack = append(stack, tmp...)
+		}
+
+		p.put(top)
+	}
+}
+
+// Stats returns the allocation statistics of the pool.
+//
+// Returns:
+//   - PoolStats: The statistics. The zero value if the pool is nil.
+func (p *TokenPool[S]) Stats() PoolStats {
+	if p == nil {
+		return PoolStats{}
+	}
+
+	return PoolStats{
+		Gets: p.gets.Load(),
+		News: p.news.Load(),
+		Puts: p.puts.Load(),
+	}
+}
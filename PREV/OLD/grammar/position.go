@@ -0,0 +1,69 @@
+package grammar
+
+import (
+	"unicode/utf8"
+)
+
+// Position is a position in the source code.
+type Position struct {
+	// Offset is the offset, in bytes, from the start of the source code.
+	Offset int
+
+	// Rune is the offset, in runes, from the start of the source code.
+	Rune int
+
+	// Line is the line number. The first line is 1; 0 if the position is unknown.
+	Line int
+
+	// Column is the column number, in runes. The first column is 1.
+	Column int
+}
+
+// StartPosition returns the position of the start of the source code.
+//
+// Returns:
+//   - Position: The position of the first character.
+func StartPosition() Position {
+	return Position{
+		Offset: 0,
+		Line:   1,
+		Column: 1,
+	}
+}
+
+// IsKnown checks whether the position was computed.
+//
+// Returns:
+//   - bool: True if the position is known, false otherwise.
+func (p Position) IsKnown() bool {
+	return p.Line > 0
+}
+
+// Advance returns the position that follows the given data when it starts at p.
+//
+// Parameters:
+//   - data: The source code between p and the returned position.
+//
+// Returns:
+//   - Position: The position right after data.
+//
+// Invalid UTF-8 bytes count as one rune each.
+func (p Position) Advance(data []byte) Position {
+	p.Offset += len(data)
+
+	for len(data) > 0 {
+		c, size := utf8.DecodeRune(data)
+		data = data[size:]
+
+		p.Rune++
+
+		if c == '\n' {
+			p.Line++
+			p.Column = 1
+		} else {
+			p.Column++
+		}
+	}
+
+	return p
+}
@@ -0,0 +1,80 @@
+package grammar
+
+import (
+	"errors"
+	"strconv"
+
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+// Severity is the severity of a diagnostic.
+type Severity int
+
+const (
+	// SeverityError is the severity of the problems that make the input invalid.
+	SeverityError Severity = iota
+
+	// SeverityWarning is the severity of the likely mistakes.
+	SeverityWarning
+
+	// SeverityInfo is the severity of the remarks.
+	SeverityInfo
+)
+
+// String implements the fmt.Stringer interface.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	default:
+		return "Severity(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// Reporter collects the diagnostics of the phases of a run (lexing, parsing and
+// building the AST), so that a run reports every problem it finds rather than
+// only the one that stopped it. *displayer.Diagnostics implements it.
+type Reporter interface {
+	// Add adds a diagnostic. Its code is the one of the error, if any.
+	//
+	// Parameters:
+	//   - span: The span of the diagnostic. {-1, -1} if unknown.
+	//   - err: The error that describes the diagnostic.
+	Add(span grm.Span, err error)
+}
+
+// Spanner is implemented by the errors that know their span in the input, such as
+// the lexing and parsing errors.
+type Spanner interface {
+	// Span returns the span of the error in the input.
+	//
+	// Returns:
+	//   - grm.Span: The span.
+	Span() grm.Span
+}
+
+// Report adds an error to a reporter, at the span of the first error of its chain
+// that implements Spanner.
+//
+// Parameters:
+//   - r: The reporter. If nil, nothing is reported.
+//   - err: The error. If nil, nothing is reported.
+func Report(r Reporter, err error) {
+	if r == nil || err == nil {
+		return
+	}
+
+	span := grm.Span{Start: -1, End: -1}
+
+	var spanner Spanner
+
+	if errors.As(err, &spanner) {
+		span = spanner.Span()
+	}
+
+	r.Add(span, err)
+}
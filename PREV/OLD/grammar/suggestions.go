@@ -0,0 +1,43 @@
+package grammar
+
+import (
+	"github.com/PlayerR9/grammar/internal/text"
+)
+
+// SuggestionProvider suggests what the faulty text of an error was meant to be,
+// so that the errors of the lexer and the parser can hint at a fix. The
+// *matcher.LavenshteinTable implements it with the closest words of a
+// vocabulary.
+type SuggestionProvider interface {
+	// Suggest returns the words that the faulty text may have been meant to be.
+	//
+	// Parameters:
+	//   - faulty: The faulty text.
+	//
+	// Returns:
+	//   - []string: The suggestions, from the most to the least likely. Nil if
+	//     there is none.
+	Suggest(faulty string) []string
+}
+
+// DidYouMean formats the suggestions of a provider for a faulty text.
+//
+// Parameters:
+//   - p: The provider. If nil, there is no suggestion.
+//   - faulty: The faulty text.
+//
+// Returns:
+//   - string: The suggestion (e.g., "Did you mean \"let\"?"). Empty if there is
+//     none.
+func DidYouMean(p SuggestionProvider, faulty string) string {
+	if p == nil || faulty == "" {
+		return ""
+	}
+
+	words := p.Suggest(faulty)
+	if len(words) == 0 {
+		return ""
+	}
+
+	return "Did you mean " + text.JoinList(text.Quoted(words), text.Or) + "?"
+}
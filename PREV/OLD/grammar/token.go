@@ -7,6 +7,8 @@ import (
 	"unicode/utf8"
 
 	gcslc "github.com/PlayerR9/go-commons/slices"
+	grm "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/grammar/internal/chain"
 )
 
 // Token is a node in a tree.
@@ -17,6 +19,25 @@ type Token[S TokenTyper] struct {
 	Data      string
 	At        int
 	Lookahead *Token[S]
+
+	// Start and End are the positions of the first character of the token and of
+	// the character right after it. Unknown if the token was not made by a lexer
+	// or a parser.
+	Start, End Position
+
+	// StartAt and EndAt are the byte range of the token in the source code: the
+	// offset of its first byte and the offset right after its last byte. For a
+	// leaf, the range of its data; for a nonterminal, the range that covers its
+	// children (see ComputeRange). Both are -1 if the position is unknown.
+	StartAt, EndAt int
+
+	// LeadingTrivia and TrailingTrivia are the skipped text (whitespace, comments,
+	// ...) right before and right after the token. Only set by lexers that keep
+	// the trivia.
+	LeadingTrivia, TrailingTrivia string
+
+	// pooled is true if the token is in a token pool.
+	pooled bool
 }
 
 // String implements the fmt.Stringer interface.
@@ -51,8 +72,25 @@ func (t Token[S]) Size() int {
 
 	var size int
 
-	for c := t.FirstChild; c != nil; c = c.NextSibling {
-		size += c.Size()
+	stack := []*Token[S]{t.FirstChild}
+
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if c == nil {
+			continue
+		}
+
+		if c.NextSibling != nil {
+			stack = append(stack, c.NextSibling)
+		}
+
+		if c.Data != "" {
+			size += utf8.RuneCountInString(c.Data)
+		} else if c.FirstChild != nil {
+			stack = append(stack, c.FirstChild)
+		}
 	}
 
 	return size
@@ -86,12 +124,34 @@ func (t Token[S]) IsLeaf() bool {
 //   - *Token[S]: A pointer to the newly created node. It is
 //     never nil.
 func NewToken[S TokenTyper](t_type S, data string, at int, lookahead *Token[S]) *Token[S] {
+	start_at, end_at := range_of(at, data)
+
 	return &Token[S]{
 		Type:      t_type,
 		Data:      data,
 		At:        at,
 		Lookahead: lookahead,
+		StartAt:   start_at,
+		EndAt:     end_at,
+	}
+}
+
+// range_of is a helper function that returns the byte range of the data of a
+// token.
+//
+// Parameters:
+//   - at: The offset of the data. Negative if it is unknown.
+//   - data: The data.
+//
+// Returns:
+//   - int: The start of the range. -1 if at is negative.
+//   - int: The end of the range. -1 if at is negative.
+func range_of(at int, data string) (int, int) {
+	if at < 0 {
+		return -1, -1
 	}
+
+	return at, at + len(data)
 }
 
 // AddChildren is a convenience function to add multiple children to the node at once.
@@ -144,6 +204,56 @@ func (tk *Token[S]) AddChildren(children []*Token[S]) {
 	}
 }
 
+// ComputeRange sets the byte range of the token to the one that covers its
+// children, from the start of the first child to the end of the last one. The
+// children with an unknown or empty range (e.g., the EOF token) are ignored, so
+// that the range is not stretched over the text between them; if every child is
+// empty, the range is the empty range at the first known child. Does nothing if
+// the token is a leaf.
+func (tk *Token[S]) ComputeRange() {
+	if tk.FirstChild == nil {
+		return
+	}
+
+	start, end, empty := -1, -1, -1
+
+	for c := tk.FirstChild; c != nil; c = c.NextSibling {
+		if c.StartAt < 0 {
+			continue
+		}
+
+		if empty < 0 {
+			empty = c.StartAt
+		}
+
+		if c.EndAt <= c.StartAt {
+			continue
+		}
+
+		if start < 0 {
+			start = c.StartAt
+		}
+
+		end = max(end, c.EndAt)
+	}
+
+	if start < 0 {
+		start, end = empty, empty
+	}
+
+	tk.StartAt = start
+	tk.EndAt = end
+}
+
+// Span returns the byte range of the token.
+//
+// Returns:
+//   - grm.Span: The span. Its bounds are -1 if the position of the token is
+//     unknown.
+func (t Token[S]) Span() grm.Span {
+	return grm.NewSpan(t.StartAt, t.EndAt)
+}
+
 // Cleanup cleans up the token.
 //
 // Returns:
@@ -229,5 +339,64 @@ func (t Token[S]) Copy() *Token[S] {
 		Data:      t.Data,
 		At:        t.At,
 		Lookahead: nil,
+		Start:     t.Start,
+		End:       t.End,
+		StartAt:   t.StartAt,
+		EndAt:     t.EndAt,
+
+		LeadingTrivia:  t.LeadingTrivia,
+		TrailingTrivia: t.TrailingTrivia,
 	}
 }
+
+// LinkLookaheads links every token to the next one and unlinks the last token.
+// Nil tokens are skipped.
+//
+// Parameters:
+//   - tokens: The tokens to link.
+func LinkLookaheads[S TokenTyper](tokens []*Token[S]) {
+	chain.Link(tokens, func(tk *Token[S]) **Token[S] {
+		return &tk.Lookahead
+	})
+}
+
+// Reconstruct returns the source text of the given tokens; that is, the data of
+// their leaves with the trivia around it. When the tokens were made by a lexer
+// that keeps the trivia, this is the original input, byte for byte.
+//
+// Parameters:
+//   - tokens: The tokens, or the roots of the trees, in order. Nil tokens are
+//     skipped.
+//
+// Returns:
+//   - string: The source text.
+func Reconstruct[S TokenTyper](tokens []*Token[S]) string {
+	var builder strings.Builder
+
+	for _, root := range tokens {
+		if root == nil {
+			continue
+		}
+
+		stack := []*Token[S]{root}
+
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if top.FirstChild != nil {
+				for c := top.LastChild; c != nil; c = c.PrevSibling {
+					stack = append(stack, c)
+				}
+
+				continue
+			}
+
+			builder.WriteString(top.LeadingTrivia)
+			builder.WriteString(top.Data)
+			builder.WriteString(top.TrailingTrivia)
+		}
+	}
+
+	return builder.String()
+}
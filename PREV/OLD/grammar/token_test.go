@@ -0,0 +1,66 @@
+package grammar
+
+import (
+	"testing"
+
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+// range_type is the token type of the range tests.
+type range_type int
+
+// String implements the fmt.Stringer interface.
+func (t range_type) String() string {
+	return [...]string{"EOF", "WORD", "LIST"}[t]
+}
+
+// GoString implements the fmt.GoStringer interface.
+func (t range_type) GoString() string {
+	return t.String()
+}
+
+const (
+	rt_eof range_type = iota
+	rt_word
+	rt_list
+)
+
+func TestComputeRange(t *testing.T) {
+	leaf := func(type_ range_type, data string, at int) *Token[range_type] {
+		return NewToken(type_, data, at, nil)
+	}
+
+	node := func(children ...*Token[range_type]) *Token[range_type] {
+		tk := NewToken(rt_list, "", -1, nil)
+		tk.AddChildren(children)
+		tk.ComputeRange()
+
+		return tk
+	}
+
+	tests := []struct {
+		name string
+		tk   *Token[range_type]
+		want grm.Span
+	}{
+		{name: "leaf", tk: leaf(rt_word, "ab", 3), want: grm.NewSpan(3, 5)},
+		{name: "unknown leaf", tk: leaf(rt_word, "ab", -1), want: grm.NewSpan(-1, -1)},
+		{name: "children", tk: node(leaf(rt_word, "ab", 0), leaf(rt_word, "cd", 4)), want: grm.NewSpan(0, 6)},
+		{name: "trailing EOF", tk: node(leaf(rt_word, "ab", 0), leaf(rt_eof, "", 9)), want: grm.NewSpan(0, 2)},
+		{name: "unknown child", tk: node(leaf(rt_word, "", -1), leaf(rt_word, "cd", 4)), want: grm.NewSpan(4, 6)},
+		{name: "only EOF", tk: node(leaf(rt_eof, "", 7)), want: grm.NewSpan(7, 7)},
+		{
+			name: "nested",
+			tk:   node(node(leaf(rt_word, "ab", 1), leaf(rt_word, "c", 4)), leaf(rt_word, "de", 8)),
+			want: grm.NewSpan(1, 10),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tk.Span(); got != tt.want {
+				t.Errorf("expected the span %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package lexing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+func TestFullLexCtxCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	lexer := new_test_lexer(t)
+
+	solutions, err := lexer.FullLexCtx(ctx, []byte("abab ba aba"))
+
+	var cancelled *grm.ErrCancelled
+
+	if !errors.As(err, &cancelled) {
+		t.Fatalf("expected an *ErrCancelled, got %v", err)
+	} else if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the error to wrap %v, got %v", context.Canceled, err)
+	}
+
+	if solutions != nil {
+		t.Errorf("expected no solutions, got some")
+	}
+
+	_, err = lexer.FullLexParallel(ctx, []byte("abab ba aba"), 2)
+	if !errors.As(err, &cancelled) {
+		t.Fatalf("expected an *ErrCancelled from the parallel lexing, got %v", err)
+	}
+}
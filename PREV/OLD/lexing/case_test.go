@@ -0,0 +1,59 @@
+package lexing
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCaseInsensitive(t *testing.T) {
+	lexer := new(Lexer[test_type])
+
+	err := lexer.AddToMatch(tt_a, "select")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = lexer.AddToMatch(tt_b, "from")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = lexer.AddToSkipRule(" ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	const input = "SELECT From select"
+
+	if _, err := lexer.FullLex([]byte(input)); err == nil {
+		t.Fatal("expected the words to be matched with their case, got no error")
+	}
+
+	lexer.SetCaseInsensitive(true)
+
+	solutions, err := lexer.FullLex([]byte(input))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var lexings []string
+
+	for solution := range solutions {
+		var elems []string
+
+		for _, tk := range solution.GetTokens() {
+			if tk.Type != tt_eof {
+				elems = append(elems, tk.Type.String()+":"+tk.Data)
+			}
+		}
+
+		lexings = append(lexings, strings.Join(elems, " "))
+	}
+
+	// The data of the tokens is the text as written.
+	const want = "A:SELECT B:From A:select"
+
+	if len(lexings) != 1 || lexings[0] != want {
+		t.Errorf("expected the lexing %q, got %q", want, lexings)
+	}
+}
@@ -3,6 +3,9 @@ package lexing
 import (
 	"fmt"
 	"strings"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	grm "github.com/PlayerR9/grammar/grammar"
 )
 
 // ErrLexing is an error that occurs while lexing.
@@ -18,6 +21,9 @@ type ErrLexing struct {
 
 	// Suggestion is the suggestion for solving the error.
 	Suggestion string
+
+	// Start is the line and column of StartPos. Unknown if it was not computed.
+	Start gr.Position
 }
 
 // Error implements the error interface.
@@ -58,6 +64,14 @@ func (e *ErrLexing) SetSuggestion(suggestions ...string) {
 	e.Suggestion = strings.Join(suggestions, " ")
 }
 
+// Span returns the span of the error in the input.
+//
+// Returns:
+//   - grammar.Span: The span of the error. Empty if the delta is unknown.
+func (e *ErrLexing) Span() grm.Span {
+	return grm.NewSpan(e.StartPos, e.StartPos+max(e.Delta, 0))
+}
+
 // Unwrap returns the reason of the error.
 //
 // Returns:
@@ -0,0 +1,40 @@
+package lexing
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrLexingPosition(t *testing.T) {
+	// The lexer does not hold the EOF token while lexing, so the error comes
+	// right after the last token and the bytes skipped since.
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"c", 0},
+		{"ab c", 3},
+		{"ab ba c", 6},
+		{"ab  ba   c", 9},
+	}
+
+	for _, test := range tests {
+		_, err := new_test_lexer(t).FullLex([]byte(test.input))
+
+		var lex_err *ErrLexing
+
+		if !errors.As(err, &lex_err) {
+			t.Errorf("input %q: expected an *ErrLexing, got %v", test.input, err)
+
+			continue
+		}
+
+		if lex_err.StartPos != test.want {
+			t.Errorf("input %q: expected the error at %d, got %d", test.input, test.want, lex_err.StartPos)
+		}
+
+		if lex_err.Start.Column != test.want+1 {
+			t.Errorf("input %q: expected the error at column %d, got %d", test.input, test.want+1, lex_err.Start.Column)
+		}
+	}
+}
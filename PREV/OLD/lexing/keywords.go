@@ -0,0 +1,178 @@
+package lexing
+
+import (
+	"fmt"
+	"slices"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gcch "github.com/PlayerR9/go-commons/runes"
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	"github.com/PlayerR9/grammar/vocab"
+)
+
+// Keywords is a table of reserved words. It is the single source of truth for both
+// the matcher registrations of the lexer and any identifier-validation logic, so
+// the two cannot get out of sync.
+type Keywords[S gr.TokenTyper] struct {
+	// table maps each reserved word to its symbol.
+	table map[string]S
+}
+
+// NewKeywords creates a new table of reserved words.
+//
+// Parameters:
+//   - words: The reserved words and their symbols.
+//
+// Returns:
+//   - *Keywords[S]: The new table.
+//   - error: An error of type *runes.ErrInvalidUTF8Encoding if a word is not valid UTF-8.
+//
+// Empty words are ignored.
+func NewKeywords[S gr.TokenTyper](words map[string]S) (*Keywords[S], error) {
+	kw := &Keywords[S]{
+		table: make(map[string]S, len(words)),
+	}
+
+	for word, symbol := range words {
+		err := kw.Add(symbol, word)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return kw, nil
+}
+
+// Add adds a reserved word to the table. If the word already exists, its symbol is
+// replaced.
+//
+// Parameters:
+//   - symbol: The symbol of the word.
+//   - word: The reserved word.
+//
+// Returns:
+//   - error: An error of type *runes.ErrInvalidUTF8Encoding if the word is not valid UTF-8.
+//
+// Empty words are ignored.
+func (kw *Keywords[S]) Add(symbol S, word string) error {
+	if word == "" {
+		return nil
+	}
+
+	_, err := gcch.StringToUtf8(word)
+	if err != nil {
+		return err
+	}
+
+	if kw.table == nil {
+		kw.table = make(map[string]S)
+	}
+
+	kw.table[word] = symbol
+
+	return nil
+}
+
+// IsReservedWord checks whether the given word is a reserved word.
+//
+// Parameters:
+//   - word: The word to check.
+//
+// Returns:
+//   - bool: True if the word is reserved, false otherwise.
+func (kw Keywords[S]) IsReservedWord(word string) bool {
+	_, ok := kw.table[word]
+	return ok
+}
+
+// SymbolOf returns the symbol of the given reserved word.
+//
+// Parameters:
+//   - word: The reserved word.
+//
+// Returns:
+//   - S: The symbol of the word.
+//   - bool: True if the word is reserved, false otherwise.
+func (kw Keywords[S]) SymbolOf(word string) (S, bool) {
+	symbol, ok := kw.table[word]
+	return symbol, ok
+}
+
+// Words returns the reserved words in lexicographic order.
+//
+// Returns:
+//   - []string: The reserved words.
+func (kw Keywords[S]) Words() []string {
+	words := make([]string, 0, len(kw.table))
+
+	for word := range kw.table {
+		words = append(words, word)
+	}
+
+	slices.Sort(words)
+
+	return words
+}
+
+// AddKeywords registers every reserved word of the table as a match rule of the lexer.
+//
+// Parameters:
+//   - kw: The table of reserved words.
+//
+// Returns:
+//   - error: An error if a word cannot be added to the lexer.
+//
+// Words are registered in lexicographic order so that the resulting lexer does not
+// depend on map iteration order.
+func (lexer *Lexer[S]) AddKeywords(kw *Keywords[S]) error {
+	if kw == nil {
+		return nil
+	}
+
+	for _, word := range kw.Words() {
+		err := lexer.AddToMatch(kw.table[word], word)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Include registers the words of a vocabulary, such as the ones of the vocab
+// packages, as match rules of the lexer. The words of a case-insensitive
+// vocabulary are matched regardless of their case.
+//
+// Parameters:
+//   - v: The vocabulary.
+//   - symbol_of: The function that gives the symbol of each word. Words for which
+//     it returns false are not registered.
+//
+// Returns:
+//   - error: An error if symbol_of is nil or if a word cannot be added to the lexer.
+func (lexer *Lexer[S]) Include(v vocab.Vocabulary, symbol_of vocab.SymbolFunc[S]) error {
+	if symbol_of == nil {
+		return gcers.NewErrNilParameter("symbol_of")
+	}
+
+	for _, word := range v.Words {
+		symbol, ok := symbol_of(word)
+		if !ok {
+			continue
+		}
+
+		var err error
+
+		if v.CaseInsensitive {
+			err = lexer.AddToMatchFold(symbol, word.Text)
+		} else {
+			err = lexer.AddToMatch(symbol, word.Text)
+		}
+
+		if err != nil {
+			return fmt.Errorf("word %q of %s: %w", word.Text, v.Name, err)
+		}
+	}
+
+	return nil
+}
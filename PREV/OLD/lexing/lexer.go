@@ -1,14 +1,20 @@
 package lexing
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"iter"
+	"slices"
+	"unicode"
 	"unicode/utf8"
 
 	gcch "github.com/PlayerR9/go-commons/runes"
 	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
 	gccdm "github.com/PlayerR9/grammar/PREV/OLD/matcher"
+	grm "github.com/PlayerR9/grammar/grammar"
 )
 
 // LexOneFunc is the function that lexes the next token of the lexer.
@@ -28,6 +34,9 @@ type Lexer[S gr.TokenTyper] struct {
 	// input_stream is the input stream of the lexer.
 	gcch.CharStream
 
+	// input is the whole input of the lexer.
+	input []byte
+
 	// tokens is the tokens of the lexer.
 	tokens []*gr.Token[S]
 
@@ -43,8 +52,36 @@ type Lexer[S gr.TokenTyper] struct {
 	// table is the lavenshtein table of the lexer.
 	table *gccdm.LavenshteinTable
 
-	// skipped is the number of skipped characters.
+	// skipped is the number of skipped bytes since the last token.
 	skipped int
+
+	// track_skips is true if the skipped text is tracked, false otherwise.
+	track_skips bool
+
+	// skip_stats are the statistics of the skipped text.
+	skip_stats SkipStats
+
+	// trace is the writer the match attempts are logged to. Nil if they are not
+	// logged.
+	trace io.Writer
+
+	// keep_trivia is true if the skipped text is kept as trivia on the tokens.
+	keep_trivia bool
+
+	// kept are the skip categories whose matches are made tokens rather than
+	// skipped.
+	kept map[string]bool
+
+	// pool is the pool the tokens are taken from. Nil if they are allocated.
+	pool *gr.TokenPool[S]
+
+	// reporter is the reporter the lexing errors are added to. Nil if they are
+	// only returned.
+	reporter gr.Reporter
+
+	// suggestions is the provider of the suggestions of the lexing errors. Nil
+	// for the lavenshtein table of the lexer.
+	suggestions gr.SuggestionProvider
 }
 
 // WithLexFunc sets the function that lexes the next token of the lexer.
@@ -73,6 +110,7 @@ func (l *Lexer[S]) Reset() {
 
 	l.Err = nil
 	l.skipped = 0
+	l.skip_stats = SkipStats{}
 
 	if l.table == nil {
 		var table gccdm.LavenshteinTable
@@ -98,17 +136,103 @@ func (l Lexer[S]) make_error(reason error) *ErrLexing {
 		return nil
 	}
 
-	var pos int
+	pos := l.end()
 
-	if len(l.tokens) < 2 {
-		pos = 0
-	} else {
-		last_tk := l.tokens[len(l.tokens)-2]
+	err := NewErrLexing(pos.Offset+l.skipped, -1, reason)
+	err.Start = pos.Advance(l.between(pos.Offset, pos.Offset+l.skipped))
 
-		pos = last_tk.At + len(last_tk.Data)
+	suggestion := gr.DidYouMean(l.suggestion_provider(), l.word_at(err.StartPos))
+	if suggestion != "" {
+		err.SetSuggestion(suggestion)
 	}
 
-	return NewErrLexing(pos+l.skipped, -1, reason)
+	return err
+}
+
+// suggestion_provider is a helper function that returns the provider of the
+// suggestions of the lexer.
+//
+// Returns:
+//   - gr.SuggestionProvider: The provider. Nil if there is none.
+func (l Lexer[S]) suggestion_provider() gr.SuggestionProvider {
+	if l.suggestions != nil {
+		return l.suggestions
+	} else if l.table != nil {
+		return l.table
+	}
+
+	return nil
+}
+
+// word_at is a helper function that returns the word of the input that starts at
+// the given offset; that is, the text up to the next whitespace.
+//
+// Parameters:
+//   - at: The offset.
+//
+// Returns:
+//   - string: The word. Empty if there is none.
+func (l Lexer[S]) word_at(at int) string {
+	rest := l.between(at, len(l.input))
+
+	end := bytes.IndexFunc(rest, unicode.IsSpace)
+	if end == -1 {
+		end = len(rest)
+	}
+
+	return string(rest[:end])
+}
+
+// end is a helper function that returns the position right after the last token.
+//
+// Returns:
+//   - gr.Position: The position. The start of the input if there are no tokens.
+func (l Lexer[S]) end() gr.Position {
+	if len(l.tokens) == 0 {
+		return gr.StartPosition()
+	}
+
+	last_tk := l.tokens[len(l.tokens)-1]
+
+	if last_tk.End.IsKnown() {
+		return last_tk.End
+	}
+
+	offset := last_tk.At + len(last_tk.Data)
+
+	return gr.StartPosition().Advance(l.between(0, offset))
+}
+
+// between is a helper function that returns the input between two offsets.
+//
+// Parameters:
+//   - from: The start offset.
+//   - to: The end offset.
+//
+// Returns:
+//   - []byte: The input between the offsets, clamped to the bounds of the input.
+func (l Lexer[S]) between(from, to int) []byte {
+	from = max(min(from, len(l.input)), 0)
+	to = max(min(to, len(l.input)), from)
+
+	return l.input[from:to]
+}
+
+// add_token is a helper function that sets the start and end positions of a token
+// and adds it to the tokens of the lexer.
+//
+// Parameters:
+//   - tk: The token. Assumed to be non-nil and to follow the last token.
+func (l *Lexer[S]) add_token(tk *gr.Token[S]) {
+	l.attach_trivia(tk, tk.At)
+
+	pos := l.end()
+
+	tk.Start = pos.Advance(l.between(pos.Offset, tk.At))
+	tk.End = tk.Start.Advance([]byte(tk.Data))
+
+	l.tokens = append(l.tokens, tk)
+	l.skipped = 0
 }
 
 // GetTokens returns the tokens of the lexer.
@@ -137,13 +261,20 @@ func (lexer Lexer[S]) GetTokens() []*gr.Token[S] {
 		return lexer.tokens
 	} */
 
+	end := lexer.end()
+	end = end.Advance(lexer.between(end.Offset, len(lexer.input)))
+
 	eof_tk := &gr.Token[S]{
 		Type:      S(0),
 		Data:      "",
 		At:        -1,
 		Lookahead: nil,
+		Start:     end,
+		End:       end,
 	}
 
+	lexer.attach_trivia(eof_tk, len(lexer.input))
+
 	if len(lexer.tokens) == 0 {
 		return []*gr.Token[S]{eof_tk}
 	}
@@ -153,12 +284,7 @@ func (lexer Lexer[S]) GetTokens() []*gr.Token[S] {
 
 	tokens[len(tokens)-1] = eof_tk
 
-	prev := tokens[0]
-
-	for _, next := range tokens[1:] {
-		prev.Lookahead = next
-		prev = next
-	}
+	gr.LinkLookaheads(tokens)
 
 	return tokens
 }
@@ -171,7 +297,7 @@ func (lexer *Lexer[S]) copy() *Lexer[S] {
 	new_tokens := make([]*gr.Token[S], 0, len(lexer.tokens))
 
 	for i := 0; i < len(lexer.tokens); i++ {
-		new_tokens = append(new_tokens, lexer.tokens[i].Copy())
+		new_tokens = append(new_tokens, lexer.pool.Copy(lexer.tokens[i]))
 	}
 
 	var err *ErrLexing
@@ -182,20 +308,43 @@ func (lexer *Lexer[S]) copy() *Lexer[S] {
 			Delta:      lexer.Err.Delta,
 			Reason:     lexer.Err.Reason,
 			Suggestion: lexer.Err.Suggestion,
+			Start:      lexer.Err.Start,
 		}
 	}
 
 	return &Lexer[S]{
-		CharStream: lexer.CharStream.Copy(),
-		tokens:     new_tokens,
-		lex_one:    lexer.lex_one,
-		Err:        err,
-		matcher:    lexer.matcher,
-		table:      lexer.table,
-		skipped:    lexer.skipped,
+		CharStream:  lexer.CharStream.Copy(),
+		input:       lexer.input,
+		tokens:      new_tokens,
+		lex_one:     lexer.lex_one,
+		Err:         err,
+		matcher:     lexer.matcher,
+		table:       lexer.table,
+		skipped:     lexer.skipped,
+		track_skips: lexer.track_skips,
+		skip_stats:  lexer.skip_stats.clone(),
+		trace:       lexer.trace,
+		keep_trivia: lexer.keep_trivia,
+		kept:        lexer.kept,
+		pool:        lexer.pool,
+		reporter:    lexer.reporter,
+		suggestions: lexer.suggestions,
 	}
 }
 
+// call_lex_one calls the user-supplied lexing function. Panics are converted
+// into errors of type *grammar.ErrPanic so that a buggy function cannot crash the
+// caller.
+//
+// Returns:
+//   - *gr.Token[S]: The token returned by the lexing function.
+//   - error: The error returned by the lexing function, if any.
+func (lexer *Lexer[S]) call_lex_one() (tk *gr.Token[S], err error) {
+	defer grm.Recover(&err)
+
+	return lexer.lex_one(lexer)
+}
+
 // FullLex lexes the input stream of the lexer and returns the tokens.
 //
 // Parameters:
@@ -215,6 +364,8 @@ func (lexer *Lexer[S]) sub_cmp() ([]*Lexer[S], error) {
 	if has_matcher && has_lexer {
 		at := lexer.Pos()
 
+		lexer.tracef(at)
+
 		is_not_critical, err := lexer.matcher.Match(lexer)
 		if err == nil {
 			matches := lexer.matcher.GetMatches()
@@ -224,15 +375,14 @@ func (lexer *Lexer[S]) sub_cmp() ([]*Lexer[S], error) {
 			for _, match := range matches {
 				new_lexer := lexer.copy()
 
-				if match.IsShouldSkip() {
+				if lexer.should_skip(match) {
 					new_lexer.skip(match.GetChars())
 				} else {
 					symbol, data := match.GetMatch()
 
-					tk := gr.NewToken(symbol, data, at, nil)
+					tk := lexer.pool.Get(symbol, data, at, nil)
 
-					new_lexer.tokens = append(new_lexer.tokens, tk)
-					new_lexer.skipped = 0
+					new_lexer.add_token(tk)
 				}
 
 				next_lexers = append(next_lexers, new_lexer)
@@ -244,30 +394,15 @@ func (lexer *Lexer[S]) sub_cmp() ([]*Lexer[S], error) {
 				return nil, err
 			}
 
-			tmp, err := lexer.lex_one(lexer)
+			tmp, err := lexer.call_lex_one()
 			if err != nil {
 				lexer.Err = lexer.make_error(err)
 
-				/* str, err := lexer.table.Closest(match.GetChars(), 2) // Magic number.
-				if err == nil {
-					lexer.Err.SetSuggestion("Did you mean '" + str + "'?")
-				} else {
-					words := lexer.matcher.GetRuleNames()
-					gcstr.QuoteStrings(words)
-
-					if lexer.matcher.HasSkipped() {
-						words = append(words, "any other skipped character")
-					}
-
-					lexer.Err.SetSuggestion("Did you mean " + gcstr.OrString(words, false) + "?")
-				} */
-
 				return nil, lexer.Err
 			}
 
 			if tmp != nil {
-				lexer.tokens = append(lexer.tokens, tmp)
-				lexer.skipped = 0
+				lexer.add_token(tmp)
 			}
 
 			return []*Lexer[S]{lexer}, nil
@@ -275,6 +410,8 @@ func (lexer *Lexer[S]) sub_cmp() ([]*Lexer[S], error) {
 	} else if has_matcher {
 		at := lexer.Pos()
 
+		lexer.tracef(at)
+
 		_, err := lexer.matcher.Match(lexer)
 		if err == nil {
 			matches := lexer.matcher.GetMatches()
@@ -284,15 +421,14 @@ func (lexer *Lexer[S]) sub_cmp() ([]*Lexer[S], error) {
 			for _, match := range matches {
 				new_lexer := lexer.copy()
 
-				if match.IsShouldSkip() {
+				if lexer.should_skip(match) {
 					new_lexer.skip(match.GetChars())
 				} else {
 					symbol, data := match.GetMatch()
 
-					tk := gr.NewToken(symbol, data, at, nil)
+					tk := lexer.pool.Get(symbol, data, at, nil)
 
-					new_lexer.tokens = append(new_lexer.tokens, tk)
-					new_lexer.skipped = 0
+					new_lexer.add_token(tk)
 				}
 
 				next_lexers = append(next_lexers, new_lexer)
@@ -305,30 +441,15 @@ func (lexer *Lexer[S]) sub_cmp() ([]*Lexer[S], error) {
 	} else {
 		// at := lexer.Pos()
 
-		tmp, err := lexer.lex_one(lexer)
+		tmp, err := lexer.call_lex_one()
 		if err != nil {
 			lexer.Err = lexer.make_error(err)
 
-			/* str, err := lexer.table.Closest(match.GetChars(), 2) // Magic number.
-			if err == nil {
-				lexer.Err.SetSuggestion("Did you mean '" + str + "'?")
-			} else {
-				words := lexer.matcher.GetRuleNames()
-				gcstr.QuoteStrings(words)
-
-				if lexer.matcher.HasSkipped() {
-					words = append(words, "any other skipped character")
-				}
-
-				lexer.Err.SetSuggestion("Did you mean " + gcstr.OrString(words, false) + "?")
-			} */
-
 			return nil, lexer.Err
 		}
 
 		if tmp != nil {
-			lexer.tokens = append(lexer.tokens, tmp)
-			lexer.skipped = 0
+			lexer.add_token(tmp)
 		}
 
 		return []*Lexer[S]{lexer}, nil
@@ -344,6 +465,21 @@ func (lexer *Lexer[S]) sub_cmp() ([]*Lexer[S], error) {
 //   - []*gr.Token[S]: The tokens of the lexer that were lexed so far.
 //   - error: An error of type *ErrLexing if the lexing failed.
 func (lexer *Lexer[S]) FullLex(data []byte) (iter.Seq[*Lexer[S]], error) {
+	return lexer.FullLexCtx(context.Background(), data)
+}
+
+// FullLexCtx is like FullLex but stops as soon as the context is done.
+//
+// Parameters:
+//   - ctx: The context.
+//   - data: The input stream of the lexer.
+//
+// Returns:
+//   - iter.Seq[*Lexer[S]]: The solutions. On cancellation, the ones found so far;
+//     nil if there are none.
+//   - error: An error of type *grm.ErrCancelled if the context is done before the
+//     end of the lexing, or an error of type *ErrLexing if the lexing failed.
+func (lexer *Lexer[S]) FullLexCtx(ctx context.Context, data []byte) (iter.Seq[*Lexer[S]], error) {
 	lexer.Init(data)
 
 	lexer.Reset()
@@ -355,7 +491,19 @@ func (lexer *Lexer[S]) FullLex(data []byte) (iter.Seq[*Lexer[S]], error) {
 	var most_likely_err *ErrLexing
 	var level int
 
+	var steps int
+
+	// The dropped branches are only released on success, so that the tokens of
+	// the failed ones are still there for the diagnostics.
+	var dropped []*Lexer[S]
+
 	for len(stack) > 0 {
+		if err := ctx.Err(); err != nil {
+			return lexers_of(solutions), grm.NewErrCancelled(err, steps)
+		}
+
+		steps++
+
 		top := stack[len(stack)-1]
 		stack = stack[:len(stack)-1]
 
@@ -366,6 +514,10 @@ func (lexer *Lexer[S]) FullLex(data []byte) (iter.Seq[*Lexer[S]], error) {
 
 		new_lexers, err := top.sub_cmp()
 		if err != nil {
+			if top.Err == nil {
+				top.Err = top.make_error(err)
+			}
+
 			weight := len(top.GetTokens())
 
 			if most_likely_err != nil {
@@ -377,32 +529,118 @@ func (lexer *Lexer[S]) FullLex(data []byte) (iter.Seq[*Lexer[S]], error) {
 				level = weight
 				most_likely_err = top.Err
 			}
+
+			dropped = append(dropped, top)
 		} else {
+			if !slices.Contains(new_lexers, top) {
+				dropped = append(dropped, top)
+			}
+
 			stack = append(stack, new_lexers...)
 		}
 	}
 
 	if len(solutions) == 0 {
+		if most_likely_err != nil {
+			lexer.report(most_likely_err)
+		}
+
 		return nil, most_likely_err
 	}
 
+	release_all(dropped)
+
+	return lexers_of(solutions), nil
+}
+
+// lexers_of is a helper function that returns an iterator over the given lexers.
+//
+// Parameters:
+//   - lexers: The lexers.
+//
+// Returns:
+//   - iter.Seq[*Lexer[S]]: The iterator. Nil if there are no lexers.
+func lexers_of[S gr.TokenTyper](lexers []*Lexer[S]) iter.Seq[*Lexer[S]] {
+	if len(lexers) == 0 {
+		return nil
+	}
+
 	return func(yield func(lex *Lexer[S]) bool) {
-		for _, solution := range solutions {
-			if !yield(solution) {
+		for _, lexer := range lexers {
+			if !yield(lexer) {
 				return
 			}
 		}
-	}, nil
+	}
+}
+
+// SetReporter sets the reporter to which the lexing errors are added, on top of
+// being returned; so that the errors of the lexer, the parser and the AST builder
+// of a run can be collected in a single *displayer.Diagnostics.
+//
+// Parameters:
+//   - r: The reporter. If nil, the errors are only returned.
+func (lexer *Lexer[S]) SetReporter(r gr.Reporter) {
+	lexer.reporter = r
+}
+
+// report is a helper function that adds a lexing error to the reporter of the
+// lexer, if any.
+//
+// Parameters:
+//   - err: The error. If nil, nothing is reported.
+func (lexer *Lexer[S]) report(err error) {
+	gr.Report(lexer.reporter, err)
+}
+
+// SetSuggestionProvider sets the provider of the suggestions of the lexing
+// errors, which are computed for the word at the position of the error. By
+// default, the suggestions are the closest words of the matcher.
+//
+// Parameters:
+//   - p: The provider. If nil, the default one is used.
+func (lexer *Lexer[S]) SetSuggestionProvider(p gr.SuggestionProvider) {
+	lexer.suggestions = p
+}
+
+// SetTrace sets the writer to which every match attempt is logged: for each
+// position, the rules that were candidates and why each of them was eliminated.
+// This is meant to debug grammars, such as a keyword that is not matched.
+//
+// Parameters:
+//   - w: The writer. If nil, nothing is logged.
+func (lexer *Lexer[S]) SetTrace(w io.Writer) {
+	lexer.trace = w
+	lexer.matcher.SetTrace(w)
+}
+
+// tracef is a helper function that logs the start of a match attempt, if the
+// trace is enabled.
+//
+// Parameters:
+//   - at: The position, in bytes, of the match attempt.
+func (lexer Lexer[S]) tracef(at int) {
+	if lexer.trace == nil {
+		return
+	}
+
+	_, _ = fmt.Fprintf(lexer.trace, "at %d:\n", at)
 }
 
 // skip skips the characters of the lexer.
 //
 // Parameters:
 //   - chars: The characters to skip.
+//
+// The skipped characters are counted in bytes, like the positions of the lexer.
 func (lexer *Lexer[S]) skip(chars []rune) {
 	for _, c := range chars {
 		lexer.skipped += utf8.RuneLen(c)
 	}
+
+	if lexer.track_skips {
+		lexer.skip_stats.add(string(chars))
+	}
 }
 
 // AddToMatch is a method that adds a new match to the lexer.
@@ -422,6 +660,19 @@ func (lexer *Lexer[S]) AddToMatch(symbol S, word string) error {
 	return nil
 }
 
+// AddToMatchFold is like AddToMatch but the word is matched regardless of its
+// case. The data of the tokens is the text of the input, as written.
+//
+// Parameters:
+//   - symbol: The symbol of the word.
+//   - word: The word to match.
+//
+// Returns:
+//   - error: An error if the word cannot be added to the lexer.
+func (lexer *Lexer[S]) AddToMatchFold(symbol S, word string) error {
+	return lexer.matcher.AddToMatchFold(symbol, word)
+}
+
 // AddToSkipRule is a method that adds a new skip rule to the lexer.
 //
 // Parameters:
@@ -437,3 +688,32 @@ func (lexer *Lexer[S]) AddToSkipRule(words ...string) error {
 
 	return nil
 }
+
+// SetCaseInsensitive sets whether the words of the lexer's matcher are matched
+// regardless of their case. The data of the tokens is the text of the input, as
+// written.
+//
+// Parameters:
+//   - insensitive: True to match regardless of the case, false otherwise.
+func (lexer *Lexer[S]) SetCaseInsensitive(insensitive bool) {
+	lexer.matcher.SetCaseInsensitive(insensitive)
+}
+
+// SetLongestMatch sets the longest-match (maximal munch) policy of the lexer's
+// matcher. When enabled, only the longest registered words are matched.
+//
+// Parameters:
+//   - longest: True to enable the policy, false to disable it.
+func (lexer *Lexer[S]) SetLongestMatch(longest bool) {
+	lexer.matcher.SetLongestMatch(longest)
+}
+
+// CheckPrefixes reports the registered words that are a proper prefix of another
+// registered word while the longest-match policy is disabled. Call it after all
+// the words (and keywords) are registered.
+//
+// Returns:
+//   - []error: The warnings. Each of them is of type *matcher.WarnPrefixOverlap.
+func (lexer Lexer[S]) CheckPrefixes() []error {
+	return lexer.matcher.CheckPrefixes()
+}
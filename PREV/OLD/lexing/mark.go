@@ -0,0 +1,79 @@
+package lexing
+
+import (
+	"errors"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gcch "github.com/PlayerR9/go-commons/runes"
+)
+
+// Mark is a checkpoint of a lexer, made by Lexer.Mark and restored by
+// Lexer.Rewind. It is a value; it can be restored any number of times.
+type Mark struct {
+	// stream is the state of the input stream.
+	stream gcch.CharStream
+
+	// tokens is the number of tokens.
+	tokens int
+
+	// skipped is the number of skipped bytes since the last token.
+	skipped int
+}
+
+// Pos returns the position of the input stream at the checkpoint.
+//
+// Returns:
+//   - int: The position.
+func (m Mark) Pos() int {
+	return m.stream.Pos()
+}
+
+// Mark makes a checkpoint of the lexer: the position of the input stream, the
+// tokens and the skipped bytes. Use it in a LexOneFunc to read a construct
+// speculatively (e.g., "..=" versus "..") and Rewind to backtrack if it does not
+// match.
+//
+// Returns:
+//   - Mark: The checkpoint.
+func (lexer *Lexer[S]) Mark() Mark {
+	return Mark{
+		stream:  lexer.CharStream.Copy(),
+		tokens:  len(lexer.tokens),
+		skipped: lexer.skipped,
+	}
+}
+
+// Rewind restores a checkpoint made by Mark. The runes read since are read again
+// and the tokens added since are removed.
+//
+// Parameters:
+//   - m: The checkpoint. It must have been made by this lexer on the current
+//     input.
+//
+// Returns:
+//   - error: An error if the checkpoint is from a later state of the lexer, such
+//     as one made before the lexer was reset.
+func (lexer *Lexer[S]) Rewind(m Mark) error {
+	if lexer == nil {
+		return gcers.NilReceiver
+	}
+
+	if m.tokens > len(lexer.tokens) || m.Pos() > lexer.Pos() {
+		return errors.New("the mark is past the state of the lexer")
+	}
+
+	lexer.CharStream = m.stream.Copy()
+
+	if m.tokens < len(lexer.tokens) {
+		removed := lexer.tokens[m.tokens:]
+
+		lexer.pool.CleanTokens(removed)
+		clear(removed)
+
+		lexer.tokens = lexer.tokens[:m.tokens]
+	}
+
+	lexer.skipped = m.skipped
+
+	return nil
+}
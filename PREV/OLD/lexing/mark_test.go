@@ -0,0 +1,65 @@
+package lexing
+
+import (
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+func TestMarkRewind(t *testing.T) {
+	lexer := new(Lexer[test_type])
+
+	lexer.CharStream.Init([]byte("..=x"))
+
+	read := func(n int) string {
+		var chars []rune
+
+		for range n {
+			c, _, err := lexer.ReadRune()
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			chars = append(chars, c)
+		}
+
+		return string(chars)
+	}
+
+	start := lexer.Mark()
+
+	if got := read(2); got != ".." {
+		t.Fatalf("expected \"..\", got %q", got)
+	}
+
+	// The speculative read adds a token that the rewind removes.
+	lexer.tokens = append(lexer.tokens, gr.NewToken(tt_a, "..", 0, nil))
+
+	after := lexer.Mark()
+
+	if got := read(1); got != "=" {
+		t.Fatalf("expected \"=\", got %q", got)
+	}
+
+	err := lexer.Rewind(start)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(lexer.tokens) != 0 || lexer.Pos() != 0 {
+		t.Errorf("expected no token at 0, got %d tokens at %d", len(lexer.tokens), lexer.Pos())
+	}
+
+	if got := read(3); got != "..=" {
+		t.Errorf("expected the runes to be read again, got %q", got)
+	}
+
+	// A mark can be restored any number of times.
+	if err := lexer.Rewind(start); err != nil || lexer.Pos() != 0 {
+		t.Errorf("expected to rewind to 0 again, got %d (%v)", lexer.Pos(), err)
+	}
+
+	if err := lexer.Rewind(after); err == nil {
+		t.Error("expected an error for a mark past the state of the lexer, got nil")
+	}
+}
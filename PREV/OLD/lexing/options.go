@@ -0,0 +1,274 @@
+package lexing
+
+import (
+	"io"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	"github.com/PlayerR9/grammar/vocab"
+)
+
+// Option is an option that configures a lexer. Unlike the PrintOption of the
+// displayer, applying it can fail. The parser has the same kind of options; see
+// parsing.Option.
+//
+// Parameters:
+//   - lexer: The lexer to configure. Assume that lexer is not nil.
+//
+// Returns:
+//   - error: An error if the option cannot be applied.
+type Option[S gr.TokenTyper] func(lexer *Lexer[S]) error
+
+// NewLexer creates a new lexer configured with the given options.
+//
+// Parameters:
+//   - opts: The options of the lexer.
+//
+// Returns:
+//   - *Lexer[S]: The new lexer.
+//   - error: An error if an option cannot be applied.
+func NewLexer[S gr.TokenTyper](opts ...Option[S]) (*Lexer[S], error) {
+	lexer := &Lexer[S]{}
+
+	err := lexer.Apply(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return lexer, nil
+}
+
+// Apply applies the given options to the lexer, in order.
+//
+// Parameters:
+//   - opts: The options to apply. Nil options are ignored.
+//
+// Returns:
+//   - error: The error of the first option that cannot be applied.
+func (lexer *Lexer[S]) Apply(opts ...Option[S]) error {
+	if lexer == nil {
+		return gcers.NilReceiver
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		err := opt(lexer)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WithLexOneFunc sets the function that lexes the next token of the lexer.
+//
+// Parameters:
+//   - lex_one: The function that lexes the next token of the lexer.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithLexOneFunc[S gr.TokenTyper](lex_one LexOneFunc[S]) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		lexer.WithLexFunc(lex_one)
+
+		return nil
+	}
+}
+
+// WithMatch adds a word to match.
+//
+// Parameters:
+//   - symbol: The symbol of the word.
+//   - word: The word to match.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithMatch[S gr.TokenTyper](symbol S, word string) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		return lexer.AddToMatch(symbol, word)
+	}
+}
+
+// WithSkip adds words to skip.
+//
+// Parameters:
+//   - words: The words to skip.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithSkip[S gr.TokenTyper](words ...string) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		return lexer.AddToSkipRule(words...)
+	}
+}
+
+// WithSkipCategory adds words to skip under the given category.
+//
+// Parameters:
+//   - category: The name of the category.
+//   - symbol: The symbol of the matches of the category when they are kept.
+//   - words: The words to skip.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithSkipCategory[S gr.TokenTyper](category string, symbol S, words ...string) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		return lexer.AddToSkipCategory(category, symbol, words...)
+	}
+}
+
+// WithCategorySkipped sets whether the matches of the given skip category are
+// skipped or made tokens.
+//
+// Parameters:
+//   - category: The name of the category.
+//   - skipped: True to skip the matches, false to make them tokens.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithCategorySkipped[S gr.TokenTyper](category string, skipped bool) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		lexer.SetCategorySkipped(category, skipped)
+
+		return nil
+	}
+}
+
+// WithKeywords adds the reserved words of the given table.
+//
+// Parameters:
+//   - kw: The table of reserved words.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithKeywords[S gr.TokenTyper](kw *Keywords[S]) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		return lexer.AddKeywords(kw)
+	}
+}
+
+// WithVocabulary adds the words of the given vocabulary.
+//
+// Parameters:
+//   - v: The vocabulary.
+//   - symbol_of: The function that gives the symbol of each word.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithVocabulary[S gr.TokenTyper](v vocab.Vocabulary, symbol_of vocab.SymbolFunc[S]) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		return lexer.Include(v, symbol_of)
+	}
+}
+
+// WithPatterns sets the lexing function of the lexer to the one of the given
+// patterns. The words to match or to skip are tried before the patterns.
+//
+// Parameters:
+//   - patterns: The patterns.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithPatterns[S gr.TokenTyper](patterns ...Pattern[S]) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		lexer.WithLexFunc(PatternLexFunc(patterns...))
+
+		return nil
+	}
+}
+
+// WithLongestMatch sets the longest-match policy of the lexer.
+//
+// Parameters:
+//   - longest: True to enable the policy, false to disable it.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithLongestMatch[S gr.TokenTyper](longest bool) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		lexer.SetLongestMatch(longest)
+
+		return nil
+	}
+}
+
+// WithCaseInsensitive sets whether the words of the matcher are matched
+// regardless of their case.
+//
+// Parameters:
+//   - insensitive: True to match regardless of the case, false otherwise.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithCaseInsensitive[S gr.TokenTyper](insensitive bool) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		lexer.SetCaseInsensitive(insensitive)
+
+		return nil
+	}
+}
+
+// WithSkipStats enables or disables the tracking of the skipped text.
+//
+// Parameters:
+//   - enabled: True to track the skipped text, false otherwise.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithSkipStats[S gr.TokenTyper](enabled bool) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		lexer.SetSkipStats(enabled)
+
+		return nil
+	}
+}
+
+// WithTrace sets the writer to which every match attempt is logged.
+//
+// Parameters:
+//   - w: The writer. If nil, nothing is logged.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithTrace[S gr.TokenTyper](w io.Writer) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		lexer.SetTrace(w)
+
+		return nil
+	}
+}
+
+// WithReporter sets the reporter to which the lexing errors are added.
+//
+// Parameters:
+//   - r: The reporter. If nil, the errors are only returned.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithReporter[S gr.TokenTyper](r gr.Reporter) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		lexer.SetReporter(r)
+
+		return nil
+	}
+}
+
+// WithSuggestionProvider sets the provider of the suggestions of the lexing
+// errors.
+//
+// Parameters:
+//   - p: The provider. If nil, the closest words of the matcher are suggested.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithSuggestionProvider[S gr.TokenTyper](p gr.SuggestionProvider) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		lexer.SetSuggestionProvider(p)
+
+		return nil
+	}
+}
@@ -0,0 +1,212 @@
+package lexing
+
+import (
+	"context"
+	"iter"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+// lex_branch is a branch of the lexing that is yet to be explored.
+type lex_branch[S gr.TokenTyper] struct {
+	// lexer is the lexer of the branch.
+	lexer *Lexer[S]
+
+	// path is the sequence of choices that leads to the branch. Branches are
+	// explored by FullLex in the increasing order of their paths.
+	path []int
+}
+
+// lex_failure is a branch that could not be lexed.
+type lex_failure struct {
+	// err is the error of the branch.
+	err error
+
+	// weight is the number of tokens lexed before the error, EOF included.
+	weight int
+
+	// path is the path of the branch.
+	path []int
+}
+
+// FullLexParallel is like FullLex but explores the branches of the lexing on
+// several goroutines.
+//
+// Parameters:
+//   - ctx: The context. The lexing stops as soon as it is done.
+//   - data: The input stream of the lexer.
+//   - workers: The maximum number of goroutines. If non-positive, GOMAXPROCS is
+//     used.
+//
+// Returns:
+//   - iter.Seq[*Lexer[S]]: The solutions, in the same order as the ones of FullLex.
+//     On cancellation, the ones found so far; nil if there are none.
+//   - error: An error of type *grm.ErrCancelled if ctx is done before the end of
+//     the lexing or, if no branch succeeded, the error of the branch that went the
+//     furthest; between branches that went equally far, the one FullLex would have
+//     reported.
+//
+// The lexing function and the trace writer, if any, are called concurrently and
+// must be safe for concurrent use.
+func (lexer *Lexer[S]) FullLexParallel(ctx context.Context, data []byte, workers int) (iter.Seq[*Lexer[S]], error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	lexer.Init(data)
+
+	lexer.Reset()
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		solutions []lex_branch[S]
+		failures  []lex_failure
+		dropped   []*Lexer[S]
+		steps     atomic.Int64
+	)
+
+	slots := make(chan struct{}, workers)
+
+	var explore func(b lex_branch[S])
+
+	explore = func(b lex_branch[S]) {
+		stack := []lex_branch[S]{b}
+
+		for len(stack) > 0 {
+			if ctx.Err() != nil {
+				return
+			}
+
+			// Hand the branches that would be explored last over to idle workers.
+			for len(stack) > 1 {
+				select {
+				case slots <- struct{}{}:
+					spawned := stack[0]
+					stack = stack[1:]
+
+					wg.Add(1)
+
+					go func() {
+						defer wg.Done()
+						defer func() { <-slots }()
+
+						explore(spawned)
+					}()
+
+					continue
+				default:
+				}
+
+				break
+			}
+
+			steps.Add(1)
+
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if top.lexer.IsExhausted() {
+				mu.Lock()
+				solutions = append(solutions, top)
+				mu.Unlock()
+
+				continue
+			}
+
+			new_lexers, err := top.lexer.sub_cmp()
+			if err != nil {
+				if top.lexer.Err == nil {
+					top.lexer.Err = top.lexer.make_error(err)
+				}
+
+				if top.lexer.Err != nil {
+					err = top.lexer.Err
+				}
+
+				mu.Lock()
+				failures = append(failures, lex_failure{
+					err:    err,
+					weight: len(top.lexer.tokens) + 1,
+					path:   top.path,
+				})
+				dropped = append(dropped, top.lexer)
+				mu.Unlock()
+
+				continue
+			}
+
+			if !slices.Contains(new_lexers, top.lexer) {
+				mu.Lock()
+				dropped = append(dropped, top.lexer)
+				mu.Unlock()
+			}
+
+			for i, new_lexer := range new_lexers {
+				if len(new_lexers) > 1 {
+					// The copies of a lexer share the state of its matcher.
+					new_lexer.matcher = new_lexer.matcher.Fork()
+				}
+
+				stack = append(stack, lex_branch[S]{
+					lexer: new_lexer,
+					path:  append(slices.Clip(top.path), len(new_lexers)-1-i),
+				})
+			}
+		}
+	}
+
+	slots <- struct{}{}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer func() { <-slots }()
+
+		explore(lex_branch[S]{lexer: lexer})
+	}()
+
+	wg.Wait()
+
+	slices.SortFunc(solutions, func(a, b lex_branch[S]) int {
+		return slices.Compare(a.path, b.path)
+	})
+
+	lexers := make([]*Lexer[S], 0, len(solutions))
+
+	for _, solution := range solutions {
+		lexers = append(lexers, solution.lexer)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return lexers_of(lexers), grm.NewErrCancelled(err, int(steps.Load()))
+	}
+
+	if len(solutions) == 0 {
+		if len(failures) == 0 {
+			return nil, nil
+		}
+
+		best := slices.MinFunc(failures, func(a, b lex_failure) int {
+			if a.weight != b.weight {
+				return b.weight - a.weight
+			}
+
+			return slices.Compare(a.path, b.path)
+		})
+
+		lexer.report(best.err)
+
+		return nil, best.err
+	}
+
+	release_all(dropped)
+
+	return lexers_of(lexers), nil
+}
@@ -0,0 +1,143 @@
+package lexing
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// test_type is the symbol type of the test lexers.
+type test_type int
+
+const (
+	tt_eof test_type = iota
+	tt_a
+	tt_ab
+	tt_b
+	tt_ba
+)
+
+// String implements the fmt.Stringer interface.
+func (t test_type) String() string {
+	return [...]string{"EOF", "A", "AB", "B", "BA"}[t]
+}
+
+// GoString implements the fmt.GoStringer interface.
+func (t test_type) GoString() string {
+	return t.String()
+}
+
+// new_test_lexer is a helper function that makes a lexer of the words "a", "ab",
+// "b" and "ba", separated by spaces; so that most inputs have several lexings.
+func new_test_lexer(t *testing.T) *Lexer[test_type] {
+	lexer := new(Lexer[test_type])
+
+	words := map[test_type]string{tt_a: "a", tt_ab: "ab", tt_b: "b", tt_ba: "ba"}
+
+	for _, symbol := range []test_type{tt_a, tt_ab, tt_b, tt_ba} {
+		err := lexer.AddToMatch(symbol, words[symbol])
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	err := lexer.AddToSkipRule(" ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	return lexer
+}
+
+// lexings_of is a helper function that writes the tokens of each solution on
+// its own line, in order.
+func lexings_of(t *testing.T, lexer *Lexer[test_type], parallel bool, workers int) string {
+	var lines []string
+
+	fn := lexer.FullLex
+	if parallel {
+		fn = func(data []byte) (iter.Seq[*Lexer[test_type]], error) {
+			return lexer.FullLexParallel(context.Background(), data, workers)
+		}
+	}
+
+	solutions, err := fn([]byte("abab ba aba"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for solution := range solutions {
+		var elems []string
+
+		for _, tk := range solution.GetTokens() {
+			elems = append(elems, tk.Type.String()+":"+tk.Data)
+		}
+
+		lines = append(lines, strings.Join(elems, " "))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func TestFullLexParallelOrder(t *testing.T) {
+	want := lexings_of(t, new_test_lexer(t), false, 0)
+
+	if n := strings.Count(want, "\n") + 1; n < 2 {
+		t.Fatalf("expected several lexings, got %d", n)
+	}
+
+	for _, workers := range []int{1, 2, 8} {
+		got := lexings_of(t, new_test_lexer(t), true, workers)
+		if got != want {
+			t.Errorf("%d workers: expected the lexings\n%s\ngot\n%s", workers, want, got)
+		}
+	}
+}
+
+func TestFullLexParallelConcurrent(t *testing.T) {
+	want := lexings_of(t, new_test_lexer(t), false, 0)
+
+	var wg sync.WaitGroup
+
+	got := make([]string, 8)
+
+	for i := range got {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			got[i] = lexings_of(t, new_test_lexer(t), true, 4)
+		}()
+	}
+
+	wg.Wait()
+
+	for i, lexings := range got {
+		if lexings != want {
+			t.Errorf("call %d: expected the lexings\n%s\ngot\n%s", i, want, lexings)
+		}
+	}
+}
+
+func TestFullLexParallelError(t *testing.T) {
+	want_lexer := new_test_lexer(t)
+
+	_, want := want_lexer.FullLex([]byte("ab c"))
+	if want == nil {
+		t.Fatalf("expected an error, got nil")
+	}
+
+	lexer := new_test_lexer(t)
+
+	_, err := lexer.FullLexParallel(context.Background(), []byte("ab c"), 4)
+	if err == nil {
+		t.Fatalf("expected the error %v, got nil", want)
+	}
+
+	if err.Error() != want.Error() {
+		t.Errorf("expected the error %v, got %v", want, err)
+	}
+}
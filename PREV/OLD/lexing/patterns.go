@@ -0,0 +1,148 @@
+package lexing
+
+import (
+	"fmt"
+	"regexp"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+// Pattern is a token, or a skip rule, defined by a regular expression rather than
+// by a word.
+type Pattern[S gr.TokenTyper] struct {
+	// Symbol is the symbol of the tokens. Ignored if Skip is true, unless the
+	// category of the pattern is kept.
+	Symbol S
+
+	// Regexp is the regular expression. It is anchored at the current position of
+	// the lexer.
+	Regexp *regexp.Regexp
+
+	// Skip is true if the matched text is skipped rather than made a token.
+	Skip bool
+
+	// Category is the name of the skip category of the pattern. Empty if the
+	// matched text is always skipped.
+	Category string
+}
+
+// NewPattern creates a new pattern for the tokens of the given symbol.
+//
+// Parameters:
+//   - symbol: The symbol of the tokens.
+//   - expr: The regular expression, in the syntax of the regexp package.
+//
+// Returns:
+//   - Pattern[S]: The new pattern.
+//   - error: An error if expr is not a valid regular expression.
+func NewPattern[S gr.TokenTyper](symbol S, expr string) (Pattern[S], error) {
+	re, err := regexp.Compile(`^(?:` + expr + `)`)
+	if err != nil {
+		return Pattern[S]{}, err
+	}
+
+	return Pattern[S]{
+		Symbol: symbol,
+		Regexp: re,
+	}, nil
+}
+
+// NewSkipPattern creates a new pattern whose matches are skipped, such as comments.
+//
+// Parameters:
+//   - expr: The regular expression, in the syntax of the regexp package.
+//
+// Returns:
+//   - Pattern[S]: The new pattern.
+//   - error: An error if expr is not a valid regular expression.
+func NewSkipPattern[S gr.TokenTyper](expr string) (Pattern[S], error) {
+	p, err := NewPattern(S(0), expr)
+	if err != nil {
+		return Pattern[S]{}, err
+	}
+
+	p.Skip = true
+
+	return p, nil
+}
+
+// NewSkipCategoryPattern creates a new pattern whose matches are skipped under
+// the given category; see Lexer.SetCategorySkipped.
+//
+// Parameters:
+//   - category: The name of the category, such as "comment".
+//   - symbol: The symbol of the tokens when the category is kept.
+//   - expr: The regular expression, in the syntax of the regexp package.
+//
+// Returns:
+//   - Pattern[S]: The new pattern.
+//   - error: An error if expr is not a valid regular expression.
+func NewSkipCategoryPattern[S gr.TokenTyper](category string, symbol S, expr string) (Pattern[S], error) {
+	p, err := NewPattern(symbol, expr)
+	if err != nil {
+		return Pattern[S]{}, err
+	}
+
+	p.Skip = true
+	p.Category = category
+
+	return p, nil
+}
+
+// PatternLexFunc creates a lexing function that matches the given patterns at the
+// current position of the lexer. The longest match wins and, between matches of
+// the same length, the pattern that comes first.
+//
+// Parameters:
+//   - patterns: The patterns. Patterns without a regular expression are ignored.
+//
+// Returns:
+//   - LexOneFunc[S]: The lexing function. Never returns nil.
+//
+// Empty matches are ignored. The lexing function returns a nil token, with no
+// error, when a skip pattern of a skipped category matches; and an error when no pattern matches.
+func PatternLexFunc[S gr.TokenTyper](patterns ...Pattern[S]) LexOneFunc[S] {
+	var valid []Pattern[S]
+
+	for _, p := range patterns {
+		if p.Regexp != nil {
+			valid = append(valid, p)
+		}
+	}
+
+	return func(lexer *Lexer[S]) (*gr.Token[S], error) {
+		at := lexer.Pos()
+
+		rest := lexer.input[at:]
+
+		best, size := -1, 0
+
+		for i, p := range valid {
+			loc := p.Regexp.FindIndex(rest)
+			if loc == nil || loc[1] <= size {
+				continue
+			}
+
+			best, size = i, loc[1]
+		}
+
+		if best == -1 {
+			return nil, fmt.Errorf("no pattern matches at %d", at)
+		}
+
+		for lexer.Pos() < at+size {
+			_, _, err := lexer.ReadRune()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if valid[best].Skip && lexer.IsCategorySkipped(valid[best].Category) {
+			lexer.skip([]rune(string(rest[:size])))
+
+			return nil, nil
+		}
+
+		return lexer.pool.Get(valid[best].Symbol, string(rest[:size]), at, nil), nil
+	}
+}
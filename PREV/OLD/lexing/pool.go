@@ -0,0 +1,54 @@
+package lexing
+
+import (
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+// SetTokenPool sets the pool the tokens of the lexer are taken from. When FullLex
+// succeeds, the tokens of the branches it dropped are returned to it; when it
+// fails, they are kept so that the tokens lexed before the error can be reported.
+// The tokens of the solutions are not returned, and are to be returned with
+// pool.CleanTokens once they are no longer used.
+//
+// Parameters:
+//   - pool: The pool. If nil, every token is allocated.
+func (lexer *Lexer[S]) SetTokenPool(pool *gr.TokenPool[S]) {
+	lexer.pool = pool
+}
+
+// WithTokenPool sets the pool the tokens of the lexer are taken from.
+//
+// Parameters:
+//   - pool: The pool. If nil, every token is allocated.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithTokenPool[S gr.TokenTyper](pool *gr.TokenPool[S]) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		lexer.SetTokenPool(pool)
+
+		return nil
+	}
+}
+
+// release is a helper function that returns the tokens of a branch that is
+// dropped to the pool of the lexer, if any.
+func (lexer *Lexer[S]) release() {
+	if lexer.pool == nil {
+		return
+	}
+
+	lexer.pool.CleanTokens(lexer.tokens)
+	lexer.tokens = lexer.tokens[:0]
+}
+
+// release_all is a helper function that releases the tokens of the given
+// lexers.
+//
+// Parameters:
+//   - lexers: The lexers.
+func release_all[S gr.TokenTyper](lexers []*Lexer[S]) {
+	for _, lexer := range lexers {
+		lexer.release()
+	}
+}
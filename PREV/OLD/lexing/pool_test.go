@@ -0,0 +1,87 @@
+package lexing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+// new_pool_lexer is a helper function that makes a lexer of the words "a",
+// separated by spaces, whose tokens are taken from the given pool.
+func new_pool_lexer(pool *gr.TokenPool[test_type]) *Lexer[test_type] {
+	lexer := new(Lexer[test_type])
+	lexer.SetTokenPool(pool)
+
+	lexer.WithLexFunc(func(lexer *Lexer[test_type]) (*gr.Token[test_type], error) {
+		at := lexer.Pos()
+
+		c, _, err := lexer.ReadRune()
+		if err != nil {
+			return nil, err
+		}
+
+		switch c {
+		case 'a':
+			return pool.Get(tt_a, "a", at, nil), nil
+		case ' ':
+			return nil, nil
+		default:
+			return nil, errors.New("unexpected character")
+		}
+	})
+
+	return lexer
+}
+
+func TestTokenPoolFailure(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		pool := gr.NewTokenPool[test_type]()
+
+		lexer := new_pool_lexer(pool)
+
+		var err error
+
+		if parallel {
+			_, err = lexer.FullLexParallel(context.Background(), []byte("a a c"), 2)
+		} else {
+			_, err = lexer.FullLex([]byte("a a c"))
+		}
+
+		if err == nil {
+			t.Fatalf("parallel=%t: expected an error, got nil", parallel)
+		}
+
+		tokens := lexer.GetTokens()
+		if len(tokens) != 3 {
+			t.Fatalf("parallel=%t: expected the 2 tokens before the error and EOF, got %d tokens", parallel, len(tokens))
+		}
+
+		for _, tk := range tokens[:2] {
+			if tk.Type != tt_a || tk.Data != "a" {
+				t.Errorf("parallel=%t: expected A \"a\", got %s %q", parallel, tk.Type, tk.Data)
+			}
+		}
+
+		if puts := pool.Stats().Puts; puts != 0 {
+			t.Errorf("parallel=%t: expected no token to be released, got %d", parallel, puts)
+		}
+	}
+}
+
+func TestTokenPoolSuccess(t *testing.T) {
+	pool := gr.NewTokenPool[test_type]()
+
+	lexer := new_test_lexer(t)
+	lexer.SetTokenPool(pool)
+
+	_, err := lexer.FullLex([]byte("abab ba aba"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if puts := pool.Stats().Puts; puts == 0 {
+		t.Errorf("expected the tokens of the dropped branches to be released, got %d", puts)
+	}
+}
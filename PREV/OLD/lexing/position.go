@@ -0,0 +1,45 @@
+package lexing
+
+import (
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+// Init initializes the lexer with the given input.
+//
+// Parameters:
+//   - data: The input of the lexer.
+//
+// Returns:
+//   - bool: True if the receiver is not nil, false otherwise.
+func (lexer *Lexer[S]) Init(data []byte) bool {
+	if lexer == nil {
+		return false
+	}
+
+	lexer.input = data
+
+	return lexer.CharStream.Init(data)
+}
+
+// Pos returns the current position in the input stream.
+//
+// Returns:
+//   - int: The offset, in bytes, from the start of the input.
+//
+// Token At values and error positions of the lexer are expressed in the same unit.
+func (lexer Lexer[S]) Pos() int {
+	return lexer.CharStream.Pos()
+}
+
+// Position returns the current position in the input stream.
+//
+// Returns:
+//   - gr.Position: The byte, rune, line, and column of the current position.
+//
+// The position is advanced from the end of the last token, so that asking for it
+// after every token does not rescan the input.
+func (lexer Lexer[S]) Position() gr.Position {
+	end := lexer.end()
+
+	return end.Advance(lexer.between(end.Offset, lexer.Pos()))
+}
@@ -0,0 +1,35 @@
+package lexing
+
+import (
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+func TestPosition(t *testing.T) {
+	lexer := new_test_lexer(t)
+
+	err := lexer.AddToSkipRule("\n")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	solutions, err := lexer.FullLex([]byte("ab\nba "))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := gr.Position{Offset: 6, Rune: 6, Line: 2, Column: 4}
+
+	for solution := range solutions {
+		if got := solution.Position(); got != want {
+			t.Errorf("expected the position %+v, got %+v", want, got)
+		}
+
+		tokens := solution.GetTokens()
+
+		if got := tokens[len(tokens)-1].Start; got != want {
+			t.Errorf("expected the EOF token to start at %+v, got %+v", want, got)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package lexing
+
+import (
+	gccdm "github.com/PlayerR9/grammar/PREV/OLD/matcher"
+)
+
+// AddToSkipCategory adds words to skip under the given category, such as
+// "whitespace", "comment" or "shebang". By default, the matches of a category are
+// skipped like those of AddToSkipRule; SetCategorySkipped makes them tokens of the
+// given symbol instead.
+//
+// Parameters:
+//   - category: The name of the category. Empty for the words that are always
+//     skipped.
+//   - symbol: The symbol of the matches of the category when they are kept.
+//   - words: The words of the category.
+//
+// Returns:
+//   - error: An error if a word cannot be added to the lexer.
+func (lexer *Lexer[S]) AddToSkipCategory(category string, symbol S, words ...string) error {
+	err := lexer.matcher.AddToSkipCategory(category, symbol, words...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetCategorySkipped sets whether the matches of the given skip category are
+// skipped or made tokens. This only changes the lexer, not its matcher, so that
+// the same lexer can keep the comments of an input when formatting it and drop
+// them when compiling it.
+//
+// Parameters:
+//   - category: The name of the category. The empty category cannot be kept.
+//   - skipped: True to skip the matches, false to make them tokens.
+//
+// The setting applies to the words of the category as well as to its patterns
+// (see NewSkipCategoryPattern) and is kept across resets.
+func (lexer *Lexer[S]) SetCategorySkipped(category string, skipped bool) {
+	if category == "" {
+		return
+	}
+
+	kept := make(map[string]bool, len(lexer.kept)+1)
+
+	for name := range lexer.kept {
+		kept[name] = true
+	}
+
+	if skipped {
+		delete(kept, category)
+	} else {
+		kept[category] = true
+	}
+
+	lexer.kept = kept
+}
+
+// IsCategorySkipped checks whether the matches of the given skip category are
+// skipped.
+//
+// Parameters:
+//   - category: The name of the category.
+//
+// Returns:
+//   - bool: True if the matches are skipped, false if they are made tokens.
+func (lexer Lexer[S]) IsCategorySkipped(category string) bool {
+	return !lexer.kept[category]
+}
+
+// SkipCategories returns the names of the skip categories of the words of the
+// lexer.
+//
+// Returns:
+//   - []string: The names of the categories, sorted. Nil if there is none.
+func (lexer Lexer[S]) SkipCategories() []string {
+	return lexer.matcher.GetSkipCategories()
+}
+
+// should_skip is a helper function that checks whether a match is skipped.
+//
+// Parameters:
+//   - match: The match.
+//
+// Returns:
+//   - bool: True if the match is skipped, false if it is made a token.
+func (lexer Lexer[S]) should_skip(match gccdm.Matched[S]) bool {
+	return match.IsShouldSkip() && lexer.IsCategorySkipped(match.Category())
+}
@@ -0,0 +1,60 @@
+package lexing
+
+import "maps"
+
+// SkipStats are the statistics of the text that the skip rules of a lexer
+// discarded. Formatters can use them to analyze the style of an input, and grammar
+// authors to check that the skip rules do not eat meaningful text.
+type SkipStats struct {
+	// Count is the number of skipped matches.
+	Count int
+
+	// Bytes is the number of skipped bytes.
+	Bytes int
+
+	// ByWord maps each skipped text to the number of times it was skipped.
+	ByWord map[string]int
+}
+
+// add records a skipped match.
+//
+// Parameters:
+//   - word: The skipped text.
+func (s *SkipStats) add(word string) {
+	if s.ByWord == nil {
+		s.ByWord = make(map[string]int)
+	}
+
+	s.Count++
+	s.Bytes += len(word)
+	s.ByWord[word]++
+}
+
+// clone returns a deep copy of the statistics.
+//
+// Returns:
+//   - SkipStats: The copy.
+func (s SkipStats) clone() SkipStats {
+	s.ByWord = maps.Clone(s.ByWord)
+
+	return s
+}
+
+// SetSkipStats enables or disables the tracking of the skipped text.
+//
+// Parameters:
+//   - enabled: True to track the skipped text, false otherwise.
+//
+// Only the text skipped by the skip rules is tracked; the text that a LexOneFunc
+// consumes without returning a token is not.
+func (lexer *Lexer[S]) SetSkipStats(enabled bool) {
+	lexer.track_skips = enabled
+}
+
+// SkipStats returns the statistics of the text skipped since the last reset.
+//
+// Returns:
+//   - SkipStats: The statistics. The zero value if the tracking is disabled.
+func (lexer Lexer[S]) SkipStats() SkipStats {
+	return lexer.skip_stats.clone()
+}
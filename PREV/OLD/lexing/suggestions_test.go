@@ -0,0 +1,67 @@
+package lexing
+
+import (
+	"errors"
+	"testing"
+)
+
+// test_provider is a gr.SuggestionProvider that always suggests the same words.
+type test_provider []string
+
+// Suggest implements the gr.SuggestionProvider interface.
+func (p test_provider) Suggest(faulty string) []string {
+	return p
+}
+
+func TestLexingSuggestion(t *testing.T) {
+	new_lexer := func() *Lexer[test_type] {
+		lexer := new(Lexer[test_type])
+
+		for symbol, word := range map[test_type]string{tt_a: "let", tt_b: "in"} {
+			err := lexer.AddToMatch(symbol, word)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+
+		err := lexer.AddToSkipRule(" ")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		return lexer
+	}
+
+	tests := []struct {
+		name     string
+		provider test_provider
+		input    string
+		want     string
+	}{
+		{name: "closest word", input: "let lte", want: `Did you mean "let"?`},
+		{name: "no close word", input: "while", want: ""},
+		{name: "custom provider", provider: test_provider{"var", "const"}, input: "lte", want: `Did you mean "var" or "const"?`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lexer := new_lexer()
+
+			if tt.provider != nil {
+				lexer.SetSuggestionProvider(tt.provider)
+			}
+
+			_, err := lexer.FullLex([]byte(tt.input))
+
+			var lex_err *ErrLexing
+
+			if !errors.As(err, &lex_err) {
+				t.Fatalf("expected an *ErrLexing, got %v", err)
+			}
+
+			if lex_err.Suggestion != tt.want {
+				t.Errorf("expected the suggestion %q, got %q", tt.want, lex_err.Suggestion)
+			}
+		})
+	}
+}
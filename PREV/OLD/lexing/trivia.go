@@ -0,0 +1,80 @@
+package lexing
+
+import (
+	"bytes"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+// KeepTrivia sets whether the skipped text is kept as trivia on the tokens. When
+// enabled, the skipped text between two tokens is split after its first newline:
+// the part up to, and including, the newline is the trailing trivia of the token
+// before and the rest is the leading trivia of the token after. The skipped text
+// at the end of the input is split the same way between the last token and the EOF
+// token. Therefore, gr.Reconstruct gives back the input byte for byte.
+//
+// Parameters:
+//   - keep: True to keep the trivia, false to drop it.
+//
+// The trivia is taken from the input rather than from the skip rules, so the text
+// that a LexOneFunc consumes without returning a token is kept as well.
+func (lexer *Lexer[S]) KeepTrivia(keep bool) {
+	lexer.keep_trivia = keep
+}
+
+// WithKeepTrivia sets whether the skipped text is kept as trivia on the tokens.
+//
+// Parameters:
+//   - keep: True to keep the trivia, false to drop it.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithKeepTrivia[S gr.TokenTyper](keep bool) Option[S] {
+	return func(lexer *Lexer[S]) error {
+		lexer.KeepTrivia(keep)
+
+		return nil
+	}
+}
+
+// split_trivia is a helper function that splits skipped text into the trailing
+// trivia of the token before it and the leading trivia of the token after it.
+//
+// Parameters:
+//   - text: The skipped text.
+//
+// Returns:
+//   - string: The trailing trivia; up to, and including, the first newline.
+//   - string: The leading trivia; the rest.
+func split_trivia(text []byte) (string, string) {
+	idx := bytes.IndexByte(text, '\n')
+	if idx == -1 {
+		return "", string(text)
+	}
+
+	return string(text[:idx+1]), string(text[idx+1:])
+}
+
+// attach_trivia is a helper function that attaches the text skipped since the last
+// token, if the trivia is kept.
+//
+// Parameters:
+//   - next: The token after the skipped text. Assumed to be non-nil.
+//   - to: The offset of the end of the skipped text.
+func (l Lexer[S]) attach_trivia(next *gr.Token[S], to int) {
+	if !l.keep_trivia {
+		return
+	}
+
+	from := l.end().Offset
+
+	trailing, leading := split_trivia(l.between(from, to))
+
+	if len(l.tokens) > 0 {
+		l.tokens[len(l.tokens)-1].TrailingTrivia = trailing
+	} else {
+		leading = trailing + leading
+	}
+
+	next.LeadingTrivia = leading
+}
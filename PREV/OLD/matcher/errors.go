@@ -1,5 +1,11 @@
 package matcher
 
+import (
+	"strconv"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
 // ErrNoMatch is the error that occurs when the matcher does not match any rule.
 type ErrNoMatch struct {
 	// Reason is the reason of the error.
@@ -37,3 +43,40 @@ func NewErrNoMatch(reason error) *ErrNoMatch {
 		Reason: reason,
 	}
 }
+
+// WarnPrefixOverlap is the warning for words that are a proper prefix of another word.
+type WarnPrefixOverlap struct {
+	// Prefix is the shorter word.
+	Prefix string
+
+	// Word is the longer word.
+	Word string
+}
+
+// Error implements the error interface.
+//
+// Message: "<prefix> is a prefix of <word>; consider enabling the longest-match policy"
+func (w WarnPrefixOverlap) Error() string {
+	return strconv.Quote(w.Prefix) + " is a prefix of " + strconv.Quote(w.Word) + "; consider enabling the longest-match policy"
+}
+
+// Code implements the grammar.Coder interface.
+func (w WarnPrefixOverlap) Code() gr.Code {
+	return gr.CodePrefixOverlap
+}
+
+// NewWarnPrefixOverlap creates a new warning for words that are a proper prefix of
+// another word.
+//
+// Parameters:
+//   - prefix: The shorter word.
+//   - word: The longer word.
+//
+// Returns:
+//   - *WarnPrefixOverlap: The new warning. Never returns nil.
+func NewWarnPrefixOverlap(prefix, word string) *WarnPrefixOverlap {
+	return &WarnPrefixOverlap{
+		Prefix: prefix,
+		Word:   word,
+	}
+}
@@ -2,6 +2,7 @@ package matcher
 
 import (
 	"errors"
+	"slices"
 
 	gcers "github.com/PlayerR9/go-commons/errors"
 	gcint "github.com/PlayerR9/go-commons/ints"
@@ -24,8 +25,15 @@ type LavenshteinTable struct {
 
 	// word_length_list is the list of word lengths.
 	word_length_list []int
+
+	// limit is the max distance of the suggestions. 0 for DefaultSuggestionLimit.
+	limit int
 }
 
+// DefaultSuggestionLimit is the max distance of the suggestions of the tables
+// whose limit was not set.
+const DefaultSuggestionLimit int = 2
+
 // AddWord adds a word to the table.
 //
 // Parameters:
@@ -118,6 +126,60 @@ func (lt LavenshteinTable) Closest(target []rune, limit int) (string, error) {
 	return string(word), nil
 }
 
+// SetLimit sets the max distance a word can have to a faulty text to be
+// suggested.
+//
+// Parameters:
+//   - limit: The max distance. Non-positive limits reset it to
+//     DefaultSuggestionLimit.
+func (lt *LavenshteinTable) SetLimit(limit int) {
+	lt.limit = max(limit, 0)
+}
+
+// Suggest implements the grammar.SuggestionProvider interface. The suggestions
+// are the words that are the closest to the faulty text, within the limit of the
+// table; the faulty text itself is never suggested.
+//
+// Parameters:
+//   - faulty: The faulty text.
+//
+// Returns:
+//   - []string: The closest words, in the order they were added. Nil if there
+//     is none.
+func (lt LavenshteinTable) Suggest(faulty string) []string {
+	target := []rune(faulty)
+	if len(target) == 0 {
+		return nil
+	}
+
+	limit := lt.limit
+	if limit == 0 {
+		limit = DefaultSuggestionLimit
+	}
+
+	var words []string
+	best := limit + 1
+
+	for i, word := range lt.word_list {
+		d := levenshtein_distance(target, len(target), word, lt.word_length_list[i])
+
+		if d == 0 || d > best {
+			continue
+		}
+
+		if d < best {
+			best = d
+			words = words[:0]
+		}
+
+		if !slices.Contains(words, string(word)) {
+			words = append(words, string(word))
+		}
+	}
+
+	return words
+}
+
 // levenshteinDistance calculates the Levenshtein distance between two strings.
 //
 // Parameters:
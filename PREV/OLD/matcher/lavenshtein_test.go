@@ -0,0 +1,39 @@
+package matcher
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	var table LavenshteinTable
+
+	err := table.AddWords([]string{"let", "in", "if", "let"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		faulty string
+		limit  int
+		want   []string
+	}{
+		{name: "transposed", faulty: "lte", want: []string{"let"}},
+		{name: "ties", faulty: "iz", want: []string{"in", "if"}},
+		{name: "exact", faulty: "in", want: []string{"if"}},
+		{name: "too far", faulty: "while", want: nil},
+		{name: "within a larger limit", faulty: "lxyz", limit: 3, want: []string{"let"}},
+		{name: "empty", faulty: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table.SetLimit(tt.limit)
+
+			if got := table.Suggest(tt.faulty); !slices.Equal(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
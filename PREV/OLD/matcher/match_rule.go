@@ -1,5 +1,7 @@
 package matcher
 
+import "strconv"
+
 // RuleTyper is a rule type.
 type RuleTyper interface {
 	~int
@@ -21,6 +23,14 @@ type MatchRule[T RuleTyper] struct {
 
 	// should_skip is true if the rule should be skipped.
 	should_skip bool
+
+	// category is the name of the skip category of the rule. Empty for the rules
+	// that are always skipped.
+	category string
+
+	// fold is true if the rule is matched regardless of the case, whatever the
+	// setting of the matcher.
+	fold bool
 }
 
 // CharAt returns the character at the given index.
@@ -35,3 +45,17 @@ func (r MatchRule[T]) CharAt(at int) (rune, bool) {
 
 	return r.chars[at], true
 }
+
+// String implements the fmt.Stringer interface.
+//
+// Format: "<word>" (<symbol>), or "<word>" (skip) for skip rules and
+// "<word>" (skip <category>) for the rules of a skip category.
+func (r MatchRule[T]) String() string {
+	if r.should_skip && r.category != "" {
+		return strconv.Quote(string(r.chars)) + " (skip " + r.category + ")"
+	} else if r.should_skip {
+		return strconv.Quote(string(r.chars)) + " (skip)"
+	}
+
+	return strconv.Quote(string(r.chars)) + " (" + r.symbol.String() + ")"
+}
@@ -10,22 +10,27 @@ type Matched[T RuleTyper] struct {
 
 	// should_skip is true if the rule should be skipped.
 	should_skip bool
+
+	// category is the skip category of the rule. Empty if there is none.
+	category string
 }
 
 // new_matched creates a new matched.
 //
 // Parameters:
-//   - symbol: The matched symbol.
+//   - rule: The matched rule.
 //   - chars: The matched characters.
-//   - should_skip: True if the rule should be skipped.
 //
 // Returns:
 //   - Matched: The new matched.
-func new_matched[T RuleTyper](symbol T, chars []rune, should_skip bool) Matched[T] {
+func new_matched[T RuleTyper](rule MatchRule[T], chars []rune) Matched[T] {
+	symbol := rule.symbol
+
 	return Matched[T]{
 		symbol:      &symbol,
 		chars:       chars,
-		should_skip: should_skip,
+		should_skip: rule.should_skip,
+		category:    rule.category,
 	}
 }
 
@@ -67,3 +72,11 @@ func (m Matched[T]) IsValidMatch() bool {
 func (m Matched[T]) IsShouldSkip() bool {
 	return m.should_skip
 }
+
+// Category returns the skip category of the matched rule.
+//
+// Returns:
+//   - string: The name of the category. Empty if the rule has none.
+func (m Matched[T]) Category() string {
+	return m.category
+}
@@ -2,8 +2,10 @@ package matcher
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"slices"
+	"unicode"
 	"unicode/utf8"
 
 	gcers "github.com/PlayerR9/go-commons/errors"
@@ -11,6 +13,7 @@ import (
 
 	gcslc "github.com/PlayerR9/go-commons/slices"
 	gcstr "github.com/PlayerR9/go-commons/strings"
+	"github.com/PlayerR9/grammar/internal/text"
 )
 
 var (
@@ -46,6 +49,203 @@ type Matcher[T RuleTyper] struct {
 
 	// matches are the matches of the matcher.
 	matches []Matched[T]
+
+	// longest is true if only the longest matches are kept.
+	longest bool
+
+	// fold is true if the characters are compared regardless of their case.
+	fold bool
+
+	// trace is the writer the match attempts are logged to. Nil if they are not
+	// logged.
+	trace io.Writer
+}
+
+// Fork returns a matcher with the same rules and settings but with its own
+// matching state, so that the two matchers can match concurrently.
+//
+// Returns:
+//   - Matcher[T]: The new matcher.
+//
+// The rules are shared, so no rule should be added to either matcher afterwards.
+func (m Matcher[T]) Fork() Matcher[T] {
+	return Matcher[T]{
+		rules:   m.rules,
+		longest: m.longest,
+		fold:    m.fold,
+		trace:   m.trace,
+	}
+}
+
+// SetTrace sets the writer to which every match attempt is logged: the rules that
+// were candidates at each character and why each of them was eliminated. This is
+// meant to debug grammars, such as a keyword that is not matched.
+//
+// Parameters:
+//   - w: The writer. If nil, nothing is logged.
+func (m *Matcher[T]) SetTrace(w io.Writer) {
+	m.trace = w
+}
+
+// tracef is a helper function that logs a line of the trace, if any.
+//
+// Parameters:
+//   - format: The format of the line.
+//   - args: The arguments of the format.
+func (m Matcher[T]) tracef(format string, args ...any) {
+	if m.trace == nil {
+		return
+	}
+
+	_, _ = fmt.Fprintf(m.trace, "  "+format+"\n", args...)
+}
+
+// SetLongestMatch sets the longest-match (maximal munch) policy of the matcher.
+// When enabled, only the longest of the matches found at a given position are
+// returned by GetMatches; otherwise, every match is returned.
+//
+// Parameters:
+//   - longest: True to enable the policy, false to disable it.
+func (m *Matcher[T]) SetLongestMatch(longest bool) {
+	m.longest = longest
+}
+
+// SetCaseInsensitive sets whether the words of the matcher are matched
+// regardless of their case (e.g., "SELECT", "select" and "Select" all match the
+// word "select"), as in SQL-like languages. The matches keep the characters of
+// the input, not the ones of the word.
+//
+// Parameters:
+//   - insensitive: True to match regardless of the case, false otherwise.
+func (m *Matcher[T]) SetCaseInsensitive(insensitive bool) {
+	m.fold = insensitive
+}
+
+// IsCaseInsensitive checks whether the words of the matcher are matched
+// regardless of their case.
+//
+// Returns:
+//   - bool: True if the matcher is case-insensitive, false otherwise.
+func (m Matcher[T]) IsCaseInsensitive() bool {
+	return m.fold
+}
+
+// same_char is a helper function that checks whether a character of a word
+// matches a character of the input.
+//
+// Parameters:
+//   - rule: The rule of the word.
+//   - c: The character of the word.
+//   - char: The character of the input.
+//
+// Returns:
+//   - bool: True if the characters match, false otherwise.
+func (m Matcher[T]) same_char(rule MatchRule[T], c, char rune) bool {
+	if c == char {
+		return true
+	} else if !m.fold && !rule.fold {
+		return false
+	}
+
+	for r := unicode.SimpleFold(c); r != c; r = unicode.SimpleFold(r) {
+		if r == char {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckPrefixes reports the words that are a proper prefix of another word of
+// the matcher (e.g., "in" and "int"). Without the longest-match policy, such
+// words make the input "int" lex both as "int" and as "in" followed by "t".
+//
+// Returns:
+//   - []error: A warning of type *WarnPrefixOverlap for each word that is a proper
+//     prefix of others; it names the first of them in alphabetical order. Nil if
+//     the longest-match policy is enabled.
+//
+// The words are sorted once, so that the ones that start with a given word follow
+// it; the check does not compare every pair of words.
+func (m Matcher[T]) CheckPrefixes() []error {
+	if m.longest {
+		return nil
+	}
+
+	type entry struct {
+		rule MatchRule[T]
+		key  []rune
+	}
+
+	// The words are sorted regardless of the case, so that the words that extend a
+	// case-insensitive word follow it too.
+	entries := make([]entry, 0, len(m.rules))
+
+	for _, rule := range m.rules {
+		entries = append(entries, entry{rule: rule, key: fold_chars(rule.chars)})
+	}
+
+	slices.SortStableFunc(entries, func(a, b entry) int {
+		return slices.Compare(a.key, b.key)
+	})
+
+	var warnings []error
+
+	seen := make(map[string]struct{})
+
+	for i, prefix := range entries {
+		word := string(prefix.rule.chars)
+
+		if _, ok := seen[word]; ok {
+			continue
+		}
+
+		seen[word] = struct{}{}
+
+		for _, e := range entries[i+1:] {
+			if len(e.key) < len(prefix.key) || !slices.Equal(e.key[:len(prefix.key)], prefix.key) {
+				break
+			}
+
+			same := func(c, char rune) bool {
+				return m.same_char(prefix.rule, c, char) || m.same_char(e.rule, c, char)
+			}
+
+			if len(prefix.rule.chars) < len(e.rule.chars) && slices.EqualFunc(prefix.rule.chars, e.rule.chars[:len(prefix.rule.chars)], same) {
+				warnings = append(warnings, NewWarnPrefixOverlap(word, string(e.rule.chars)))
+
+				break
+			}
+		}
+	}
+
+	return warnings
+}
+
+// fold_chars is a helper function that maps every character to the smallest
+// character of its case folding orbit (e.g., 'A' and 'a' to 'A').
+//
+// Parameters:
+//   - chars: The characters.
+//
+// Returns:
+//   - []rune: The folded characters.
+func fold_chars(chars []rune) []rune {
+	folded := make([]rune, 0, len(chars))
+
+	for _, c := range chars {
+		least := c
+
+		for r := unicode.SimpleFold(c); r != c; r = unicode.SimpleFold(r) {
+			if r < least {
+				least = r
+			}
+		}
+
+		folded = append(folded, least)
+	}
+
+	return folded
 }
 
 // GetWords returns the words of the matcher.
@@ -133,6 +333,32 @@ func (m Matcher[T]) find_index(chars []rune) int {
 // Returns:
 //   - error: An error if the rule to match is invalid.
 func (m *Matcher[T]) AddToMatch(symbol T, word string) error {
+	return m.add_to_match(symbol, word, false)
+}
+
+// AddToMatchFold is like AddToMatch but the word is matched regardless of its
+// case, even if the matcher is case-sensitive.
+//
+// Parameters:
+//   - symbol: The symbol to match.
+//   - word: The word to match.
+//
+// Returns:
+//   - error: An error if the rule to match is invalid.
+func (m *Matcher[T]) AddToMatchFold(symbol T, word string) error {
+	return m.add_to_match(symbol, word, true)
+}
+
+// add_to_match is a helper function that adds a rule to match.
+//
+// Parameters:
+//   - symbol: The symbol to match.
+//   - word: The word to match.
+//   - fold: True if the word is matched regardless of its case.
+//
+// Returns:
+//   - error: An error if the rule to match is invalid.
+func (m *Matcher[T]) add_to_match(symbol T, word string, fold bool) error {
 	if word == "" {
 		return nil
 	}
@@ -153,6 +379,7 @@ func (m *Matcher[T]) AddToMatch(symbol T, word string) error {
 	rule := MatchRule[T]{
 		symbol: symbol,
 		chars:  chars,
+		fold:   fold,
 	}
 
 	idx := m.find_index(chars)
@@ -173,7 +400,24 @@ func (m *Matcher[T]) AddToMatch(symbol T, word string) error {
 // Returns:
 //   - error: An error if the rule to skip is invalid.
 func (m *Matcher[T]) AddToSkipRule(words ...string) error {
-	words = gcstr.FilterNonEmpty(words)
+	return m.AddToSkipCategory("", T(0), words...)
+}
+
+// AddToSkipCategory adds words to skip under the given category. Matches of the
+// rules of a category carry its name (see Matched.Category) so that the caller can
+// decide, per input, whether to skip them or to make them tokens of the given
+// symbol; the matcher itself does not change.
+//
+// Parameters:
+//   - category: The name of the category, such as "comment". Empty for the words
+//     that are always skipped.
+//   - symbol: The symbol of the matches of the category when they are not skipped.
+//   - words: The words to skip.
+//
+// Returns:
+//   - error: An error if the rule to skip is invalid.
+func (m *Matcher[T]) AddToSkipCategory(category string, symbol T, words ...string) error {
+	words = text.NonEmpty(words)
 	if len(words) == 0 {
 		return nil
 	}
@@ -193,9 +437,10 @@ func (m *Matcher[T]) AddToSkipRule(words ...string) error {
 		}
 
 		rule := MatchRule[T]{
-			symbol:      T(0),
+			symbol:      symbol,
 			chars:       chars,
 			should_skip: true,
+			category:    category,
 		}
 
 		idx := m.find_index(chars)
@@ -209,6 +454,27 @@ func (m *Matcher[T]) AddToSkipRule(words ...string) error {
 	return nil
 }
 
+// GetSkipCategories returns the names of the skip categories of the matcher.
+//
+// Returns:
+//   - []string: The names of the categories, sorted. Nil if there is none.
+func (m Matcher[T]) GetSkipCategories() []string {
+	var names []string
+
+	for _, rule := range m.rules {
+		if !rule.should_skip || rule.category == "" {
+			continue
+		}
+
+		pos, ok := slices.BinarySearch(names, rule.category)
+		if !ok {
+			names = slices.Insert(names, pos, rule.category)
+		}
+	}
+
+	return names
+}
+
 // match_first matches the first character of the matcher.
 //
 // Parameters:
@@ -237,8 +503,12 @@ func (m *Matcher[T]) match_first(scanner io.RuneScanner) (bool, error) {
 	for i, rule := range m.rules {
 		c, _ := rule.CharAt(m.at)
 
-		if char == c {
+		if m.same_char(rule, c, char) {
 			m.indices = append(m.indices, i)
+
+			m.tracef("candidate %s", rule)
+		} else {
+			m.tracef("eliminated %s: expected %q, got %q", rule, c, char)
 		}
 	}
 
@@ -269,6 +539,23 @@ func (m *Matcher[T]) match_first(scanner io.RuneScanner) (bool, error) {
 func (m *Matcher[T]) filter(scanner io.RuneScanner) (bool, error) {
 	char, _, err := scanner.ReadRune()
 	if err == io.EOF {
+		// Rules that end with the input are matches as well.
+		for _, idx := range m.indices {
+			rule := m.rules[idx]
+
+			if _, ok := rule.CharAt(m.at); ok {
+				m.tracef("eliminated %s: the input ended after %q", rule, string(m.chars))
+
+				continue
+			}
+
+			m.matches = append(m.matches, new_matched(rule, m.chars))
+
+			m.tracef("matched %s", rule)
+		}
+
+		m.indices = m.indices[:0]
+
 		return true, nil
 	} else if err != nil {
 		return false, err
@@ -280,13 +567,17 @@ func (m *Matcher[T]) filter(scanner io.RuneScanner) (bool, error) {
 		rule := m.rules[idx]
 
 		c, ok := rule.CharAt(m.at)
-		if ok && c == char {
+		if ok && m.same_char(rule, c, char) {
 			return true
 		}
 
 		if !ok {
-			tmp := new_matched(rule.symbol, m.chars, rule.should_skip)
+			tmp := new_matched(rule, m.chars)
 			m.matches = append(m.matches, tmp)
+
+			m.tracef("matched %s", rule)
+		} else {
+			m.tracef("eliminated %s: expected %q after %q, got %q", rule, c, string(m.chars), char)
 		}
 
 		return false
@@ -380,13 +671,35 @@ func (m *Matcher[T]) Match(scanner io.RuneScanner) (bool, error) {
 //
 // Returns:
 //   - []Matched[T]: The matches of the matcher. Nil if no matches were found.
+//
+// With the longest-match policy, only the longest matches are returned.
 func (m Matcher[T]) GetMatches() []Matched[T] {
 	if len(m.matches) == 0 {
 		return nil
 	}
 
-	matches := make([]Matched[T], 0, len(m.matches))
-	copy(matches, m.matches)
+	if !m.longest {
+		matches := make([]Matched[T], len(m.matches))
+		copy(matches, m.matches)
+
+		return matches
+	}
+
+	var size int
+
+	for _, match := range m.matches {
+		size = max(size, len(match.chars))
+	}
+
+	var matches []Matched[T]
+
+	for _, match := range m.matches {
+		if len(match.chars) == size {
+			matches = append(matches, match)
+		} else {
+			m.tracef("discarded %q: a longer match exists", string(match.chars))
+		}
+	}
 
 	return matches
 }
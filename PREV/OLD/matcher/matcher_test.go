@@ -0,0 +1,214 @@
+package matcher
+
+import (
+	"strings"
+	"testing"
+
+	gcch "github.com/PlayerR9/go-commons/runes"
+)
+
+// test_type is the symbol type of the test matchers.
+type test_type int
+
+const (
+	tt_if test_type = iota
+	tt_ident
+)
+
+// String implements the RuleTyper interface.
+func (t test_type) String() string {
+	return [...]string{"IF", "IDENT"}[t]
+}
+
+func TestGetMatches(t *testing.T) {
+	var m Matcher[test_type]
+
+	err := m.AddToMatch(tt_if, "if")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var scanner gcch.CharStream
+
+	scanner.Init([]byte("if x"))
+
+	_, err = m.Match(&scanner)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	matches := m.GetMatches()
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(matches))
+	}
+
+	symbol, word := matches[0].GetMatch()
+	if symbol != tt_if || word != "if" {
+		t.Errorf("expected IF \"if\", got %s %q", symbol, word)
+	}
+}
+
+func TestMatchAtEndOfInput(t *testing.T) {
+	var m Matcher[test_type]
+
+	err := m.AddToMatch(tt_if, "if")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var scanner gcch.CharStream
+
+	scanner.Init([]byte("if"))
+
+	_, err = m.Match(&scanner)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	matches := m.GetMatches()
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(matches))
+	}
+
+	symbol, word := matches[0].GetMatch()
+	if symbol != tt_if || word != "if" {
+		t.Errorf("expected IF \"if\", got %s %q", symbol, word)
+	}
+}
+
+func TestMatchTrace(t *testing.T) {
+	var m Matcher[test_type]
+
+	err := m.AddToMatch(tt_if, "if")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var trace strings.Builder
+
+	m.SetTrace(&trace)
+
+	var scanner gcch.CharStream
+
+	scanner.Init([]byte("in"))
+
+	_, _ = m.Match(&scanner)
+
+	const want = "  candidate \"if\" (IF)\n  eliminated \"if\" (IF): expected 'f' after \"i\", got 'n'\n"
+
+	if got := trace.String(); got != want {
+		t.Errorf("expected the trace %q, got %q", want, got)
+	}
+}
+
+func TestAddToMatchFold(t *testing.T) {
+	var m Matcher[test_type]
+
+	err := m.AddToMatchFold(tt_if, "IF")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = m.AddToMatch(tt_ident, "ID")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var scanner gcch.CharStream
+
+	scanner.Init([]byte("iF"))
+
+	_, err = m.Match(&scanner)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	matches := m.GetMatches()
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(matches))
+	}
+
+	symbol, word := matches[0].GetMatch()
+	if symbol != tt_if || word != "iF" {
+		t.Errorf("expected IF \"iF\", got %s %q", symbol, word)
+	}
+
+	scanner.Init([]byte("id"))
+
+	_, err = m.Match(&scanner)
+	if err == nil {
+		t.Errorf("expected the case-sensitive word not to match, got %v", m.GetMatches())
+	}
+}
+
+func TestCheckPrefixes(t *testing.T) {
+	type rule struct {
+		word string
+		fold bool
+	}
+
+	tests := []struct {
+		name    string
+		rules   []rule
+		longest bool
+		want    []string
+	}{
+		{
+			name:  "chain",
+			rules: []rule{{word: "integer"}, {word: "in"}, {word: "int"}},
+			want:  []string{`"in" is a prefix of "int"`, `"int" is a prefix of "integer"`},
+		},
+		{
+			name:  "once per prefix",
+			rules: []rule{{word: "if"}, {word: "ifdef"}, {word: "ifndef"}},
+			want:  []string{`"if" is a prefix of "ifdef"`},
+		},
+		{
+			name:  "case",
+			rules: []rule{{word: "IN"}, {word: "int"}},
+		},
+		{
+			name:  "fold",
+			rules: []rule{{word: "IN", fold: true}, {word: "int"}},
+			want:  []string{`"IN" is a prefix of "int"`},
+		},
+		{
+			name:    "longest",
+			rules:   []rule{{word: "in"}, {word: "int"}},
+			longest: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Matcher[test_type]
+
+			m.SetLongestMatch(tt.longest)
+
+			for _, r := range tt.rules {
+				var err error
+
+				if r.fold {
+					err = m.AddToMatchFold(tt_ident, r.word)
+				} else {
+					err = m.AddToMatch(tt_ident, r.word)
+				}
+
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			}
+
+			warnings := m.CheckPrefixes()
+			if len(warnings) != len(tt.want) {
+				t.Fatalf("expected %d warnings, got %v", len(tt.want), warnings)
+			}
+
+			for i, w := range warnings {
+				if !strings.HasPrefix(w.Error(), tt.want[i]) {
+					t.Errorf("expected the warning %q, got %q", tt.want[i], w.Error())
+				}
+			}
+		})
+	}
+}
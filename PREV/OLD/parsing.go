@@ -9,6 +9,7 @@ import (
 	"github.com/PlayerR9/grammar/PREV/OLD/grammar"
 	"github.com/PlayerR9/grammar/PREV/OLD/lexing"
 	"github.com/PlayerR9/grammar/PREV/OLD/parsing"
+	grm "github.com/PlayerR9/grammar/grammar"
 )
 
 // DebugSetting is the debug setting.
@@ -55,6 +56,10 @@ type Parser[T interface {
 
 	// debug is the debug setting.
 	debug DebugSetting
+
+	// reporter is the reporter the errors of the AST builder are added to. Nil if
+	// they are only returned.
+	reporter grammar.Reporter
 }
 
 // Init initializes the parser with the given lexer, parser and builder.
@@ -80,6 +85,32 @@ func (p *Parser[T, S]) SetDebug(debug DebugSetting) {
 	p.debug = debug
 }
 
+// SetReporter sets the reporter to which the errors of the lexer, the parser and
+// the AST builder are added, on top of being returned. With a
+// *displayer.Diagnostics, the whole run can then be displayed at once by
+// displayer.DisplayError.
+//
+// Parameters:
+//   - r: The reporter. If nil, the errors are only returned.
+func (p *Parser[T, S]) SetReporter(r grammar.Reporter) {
+	p.lexer.SetReporter(r)
+	p.parser.SetReporter(r)
+	p.reporter = r
+}
+
+// report_ast is a helper function that adds an error of the AST builder to the
+// reporter, if any. Errors without a code are reported with grammar.CodeAst.
+//
+// Parameters:
+//   - err: The error.
+func (p Parser[T, S]) report_ast(err error) {
+	if _, ok := grammar.CodeOf(err); !ok {
+		err = grammar.WithCode(grammar.CodeAst, err)
+	}
+
+	grammar.Report(p.reporter, err)
+}
+
 // Parse parses the given data and returns the AST tree.
 //
 // Parameters:
@@ -113,18 +144,20 @@ func (p Parser[T, S]) Parse(data []byte) (T, error) {
 		return *new(T), err
 	}
 
-	var forest []*grammar.Token[S]
+	var result grm.Result[*grammar.Token[S]]
 
 	for lexer := range lexers {
 		tokens := lexer.GetTokens()
 
 		if p.debug&ShowParsing != 0 {
-			forest = p.parser.FullParseWithSteps(tokens, data, 3)
+			result = p.parser.FullParseWithSteps(tokens, data, 3)
 		} else {
-			forest = p.parser.FullParse(tokens)
+			result = p.parser.FullParse(tokens)
 		}
 	}
 
+	forest := result.Forest
+
 	if p.debug&ShowTree != 0 {
 		fmt.Println("Debug option show_tree is enabled, printing forest:")
 
@@ -143,10 +176,8 @@ func (p Parser[T, S]) Parse(data []byte) (T, error) {
 		fmt.Println()
 	}
 
-	if p.parser.Err != nil {
-		return *new(T), p.parser.Err
-	} else if len(forest) == 0 {
-		return *new(T), fmt.Errorf("expected at least 1 tree, got 0 trees instead")
+	if result.Err != nil {
+		return *new(T), result.Err
 	}
 
 	nodes, err := p.builder.Apply(forest[0])
@@ -170,9 +201,15 @@ func (p Parser[T, S]) Parse(data []byte) (T, error) {
 	}
 
 	if err != nil {
+		p.report_ast(err)
+
 		return *new(T), fmt.Errorf("error while converting to AST: %w", err)
 	} else if len(nodes) != 1 {
-		return *new(T), fmt.Errorf("expected 1 node, got %d nodes instead", len(nodes))
+		err := fmt.Errorf("expected 1 node, got %d nodes instead", len(nodes))
+
+		p.report_ast(err)
+
+		return *new(T), err
 	}
 
 	return nodes[0], nil
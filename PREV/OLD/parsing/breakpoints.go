@@ -0,0 +1,137 @@
+package parsing
+
+import (
+	"fmt"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+// Breakpoint tells whether the step debugger must stop at a decision of the parser.
+//
+// Parameters:
+//   - parser: The parser, right after its decision. Never nil.
+//   - act: The action that was decided. Never nil.
+//
+// Returns:
+//   - bool: True if the debugger must stop, false otherwise.
+type Breakpoint[S gr.TokenTyper] func(parser *Parser[S], act Actioner) bool
+
+// BreakOnRule stops at the reductions of the rules of the given left-hand side.
+//
+// Parameters:
+//   - lhs: The left-hand side of the rules.
+//
+// Returns:
+//   - Breakpoint[S]: The breakpoint.
+func BreakOnRule[S gr.TokenTyper](lhs S) Breakpoint[S] {
+	return func(parser *Parser[S], act Actioner) bool {
+		switch act := act.(type) {
+		case *ReduceAction[S]:
+			return act.rule != nil && act.rule.lhs == lhs
+		case *AcceptAction[S]:
+			return act.rule != nil && act.rule.lhs == lhs
+		default:
+			return false
+		}
+	}
+}
+
+// BreakOnToken stops at the shifts of the tokens of the given type.
+//
+// Parameters:
+//   - type_: The type of the tokens.
+//
+// Returns:
+//   - Breakpoint[S]: The breakpoint.
+func BreakOnToken[S gr.TokenTyper](type_ S) Breakpoint[S] {
+	return func(parser *Parser[S], act Actioner) bool {
+		if _, ok := act.(*ShiftAction); !ok || len(parser.tokens) == 0 {
+			return false
+		}
+
+		return parser.tokens[0].Type == type_
+	}
+}
+
+// BreakAtOffset stops at the first decision taken once the input is consumed up
+// to the given offset.
+//
+// Parameters:
+//   - offset: The offset, in bytes, in the input.
+//
+// Returns:
+//   - Breakpoint[S]: The breakpoint.
+func BreakAtOffset[S gr.TokenTyper](offset int) Breakpoint[S] {
+	return func(parser *Parser[S], act Actioner) bool {
+		if len(parser.tokens) == 0 {
+			return true
+		}
+
+		at := parser.tokens[0].At
+
+		return at < 0 || at >= offset
+	}
+}
+
+// AddBreakpoints adds breakpoints to the step debugger. When the parser has
+// breakpoints, FullParseWithSteps runs without pausing until one of them stops
+// it.
+//
+// Parameters:
+//   - bps: The breakpoints to add. Nil breakpoints are ignored.
+func (p *Parser[S]) AddBreakpoints(bps ...Breakpoint[S]) {
+	for _, bp := range bps {
+		if bp != nil {
+			p.breakpoints = append(p.breakpoints, bp)
+		}
+	}
+}
+
+// ClearBreakpoints removes every breakpoint of the step debugger. A debugger that
+// was running to the next breakpoint pauses again at every step.
+func (p *Parser[S]) ClearBreakpoints() {
+	p.breakpoints = nil
+	p.running = false
+}
+
+// should_pause is a helper function that checks whether the step debugger must
+// pause at the current step. While running, it only pauses at the decisions that
+// hit a breakpoint.
+//
+// Returns:
+//   - bool: True if the debugger must pause, false otherwise.
+func (p *Parser[S]) should_pause() bool {
+	if !p.running {
+		return true
+	} else if p.last_action == nil {
+		return false
+	}
+
+	for _, bp := range p.breakpoints {
+		if bp(p, p.last_action) {
+			p.running = false
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// wait is a helper function that waits for the user to continue. Typing "c" runs
+// the parser until the next breakpoint.
+func (p *Parser[S]) wait() {
+	if len(p.breakpoints) == 0 {
+		fmt.Println("Press ENTER to continue...")
+	} else {
+		fmt.Println("Press ENTER to continue, or type c and press ENTER to run to the next breakpoint...")
+	}
+
+	var cmd string
+
+	_, _ = fmt.Scanln(&cmd)
+
+	if cmd == "c" && len(p.breakpoints) > 0 {
+		p.running = true
+	}
+}
@@ -0,0 +1,34 @@
+package parsing
+
+import "testing"
+
+type test_type int
+
+func (t test_type) String() string {
+	return [...]string{"EOF", "ID"}[t]
+}
+
+func (t test_type) GoString() string {
+	return t.String()
+}
+
+func TestClearBreakpoints(t *testing.T) {
+	var p Parser[test_type]
+
+	p.AddBreakpoints(func(_ *Parser[test_type], _ Actioner) bool {
+		return false
+	})
+
+	p.running = true
+	p.last_action = NewShiftAction()
+
+	if p.should_pause() {
+		t.Fatalf("expected the debugger to run past a step without breakpoint")
+	}
+
+	p.ClearBreakpoints()
+
+	if !p.should_pause() {
+		t.Errorf("expected the debugger to pause at every step once the breakpoints are cleared")
+	}
+}
@@ -0,0 +1,40 @@
+package parsing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+func TestFullParseCtxCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := NewParser(func(_ *Parser[test_type], _ *gr.Token[test_type]) (Actioner, error) {
+		cancel()
+
+		return NewShiftAction(), nil
+	})
+
+	eof := gr.NewToken(test_type(0), "", 4, nil)
+	b := gr.NewToken(test_type(1), "b", 2, eof)
+	a := gr.NewToken(test_type(1), "a", 0, b)
+
+	res := p.FullParseCtx(ctx, []*gr.Token[test_type]{a, b, eof})
+
+	var cancelled *grm.ErrCancelled
+
+	if !errors.As(res.Err, &cancelled) {
+		t.Fatalf("expected an *ErrCancelled, got %v", res.Err)
+	} else if cancelled.Steps != 1 {
+		t.Errorf("expected 1 step, got %d", cancelled.Steps)
+	}
+
+	forest, _ := res.PartialForest()
+	if len(forest) != 2 {
+		t.Fatalf("expected the partial forest to hold the 2 shifted tokens, got %d trees", len(forest))
+	}
+}
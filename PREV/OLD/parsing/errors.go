@@ -4,8 +4,8 @@ import (
 	"strconv"
 	"strings"
 
-	gcstr "github.com/PlayerR9/go-commons/strings"
 	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	"github.com/PlayerR9/grammar/internal/text"
 )
 
 // ErrUnexpectedToken is an error that occurs when an unexpected token is
@@ -19,25 +19,41 @@ type ErrUnexpectedToken[T gr.TokenTyper] struct {
 
 	// After is the token that was encountered after the expected token.
 	After *T
+
+	// Rule is the rule that was being reduced when the error occurred. Nil if the
+	// error did not occur while reducing.
+	Rule *Rule[T]
+
+	// Context are the nearest tokens of the stack, from the furthest to the
+	// nearest one. Empty if unknown.
+	Context []*gr.Token[T]
 }
 
 // Error implements the error interface.
 //
 // Format:
 //
-//	"expected either <value 0>, <value 1>, <value 2>, ..., or <value n> after <after> instead, got <actual> instead"
+//	"[while parsing <rule>: ]expected either <value 0>, <value 1>, <value 2>, ..., or <value n> after <after> instead, got <actual> instead[ near <context>]"
+//
+// The context is the data of its tokens separated by spaces; the type stands for
+// the data of the tokens that have none.
 func (e *ErrUnexpectedToken[T]) Error() string {
 	var builder strings.Builder
 
+	if e.Rule != nil {
+		builder.WriteString("while parsing ")
+		builder.WriteString(strconv.Quote(e.Rule.GetLhs().String()))
+		builder.WriteString(": ")
+	}
+
 	builder.WriteString("expected ")
 
 	if len(e.Expecteds) == 0 {
 		builder.WriteString("nothing")
 	} else {
-		elems := gcstr.SliceOfStringer(e.Expecteds)
-		gcstr.QuoteStrings(elems)
+		elems := text.Quoted(text.Strings(e.Expecteds))
 
-		builder.WriteString(gcstr.EitherOrString(elems))
+		builder.WriteString(text.JoinList(elems, text.EitherOr))
 	}
 
 	if e.After != nil {
@@ -55,9 +71,45 @@ func (e *ErrUnexpectedToken[T]) Error() string {
 
 	builder.WriteString(" instead")
 
+	if len(e.Context) > 0 {
+		elems := make([]string, 0, len(e.Context))
+
+		for _, tk := range e.Context {
+			if tk.Data != "" {
+				elems = append(elems, tk.Data)
+			} else {
+				elems = append(elems, tk.Type.String())
+			}
+		}
+
+		builder.WriteString(" near ")
+		builder.WriteString(strconv.Quote(strings.Join(elems, " ")))
+	}
+
 	return builder.String()
 }
 
+// Code implements the grammar.Coder interface.
+func (e *ErrUnexpectedToken[T]) Code() gr.Code {
+	return gr.CodeUnexpectedToken
+}
+
+// WithContext sets the rule that was being reduced and the nearest tokens of the
+// stack.
+//
+// Parameters:
+//   - rule: The rule that was being reduced.
+//   - context: The nearest tokens of the stack, from the furthest to the nearest one.
+//
+// Returns:
+//   - *ErrUnexpectedToken[T]: The error itself. Never returns nil.
+func (e *ErrUnexpectedToken[T]) WithContext(rule *Rule[T], context []*gr.Token[T]) *ErrUnexpectedToken[T] {
+	e.Rule = rule
+	e.Context = context
+
+	return e
+}
+
 // NewErrUnexpectedToken creates a new unexpected token error.
 //
 // Parameters:
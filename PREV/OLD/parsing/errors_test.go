@@ -0,0 +1,38 @@
+package parsing
+
+import (
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+func TestErrUnexpectedTokenContext(t *testing.T) {
+	rule := NewRule(test_type(1), []test_type{test_type(0)})
+
+	var shifted bool
+
+	p := NewParser(func(_ *Parser[test_type], _ *gr.Token[test_type]) (Actioner, error) {
+		if !shifted {
+			shifted = true
+
+			return NewShiftAction(), nil
+		}
+
+		return NewReduceAction(rule)
+	})
+
+	eof := gr.NewToken(test_type(0), "", 4, nil)
+	b := gr.NewToken(test_type(1), "b", 2, eof)
+	a := gr.NewToken(test_type(1), "a", 0, b)
+
+	res := p.FullParse([]*gr.Token[test_type]{a, b, eof})
+	if res.Err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+
+	const want = `error while parsing: while parsing "ID": expected "EOF", got "ID" instead near "a b"`
+
+	if got := res.Err.Error(); got != want {
+		t.Errorf("expected the message %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,150 @@
+package parsing
+
+import (
+	"errors"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+// Option is an option that configures a parser. It is the counterpart of the
+// lexing.Option of the lexer.
+//
+// Parameters:
+//   - p: The parser to configure. Assume that p is not nil.
+//
+// Returns:
+//   - error: An error if the option cannot be applied.
+type Option[S gr.TokenTyper] func(p *Parser[S]) error
+
+// NewParserWithOptions creates a new parser configured with the given options.
+// One of them must set the decision function; see WithDecision.
+//
+// Parameters:
+//   - opts: The options of the parser.
+//
+// Returns:
+//   - *Parser[S]: The new parser.
+//   - error: An error if an option cannot be applied or if no decision function
+//     was set.
+func NewParserWithOptions[S gr.TokenTyper](opts ...Option[S]) (*Parser[S], error) {
+	p := &Parser[S]{}
+
+	err := p.Apply(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.decision == nil {
+		return nil, gcers.NewErrInvalidParameter("opts", errors.New("no decision function was set"))
+	}
+
+	return p, nil
+}
+
+// Apply applies the given options to the parser, in order.
+//
+// Parameters:
+//   - opts: The options to apply. Nil options are ignored.
+//
+// Returns:
+//   - error: The error of the first option that cannot be applied.
+func (p *Parser[S]) Apply(opts ...Option[S]) error {
+	if p == nil {
+		return gcers.NilReceiver
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		err := opt(p)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WithDecision sets the function that returns the decision of the parser.
+//
+// Parameters:
+//   - decision_func: The decision function.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithDecision[S gr.TokenTyper](decision_func DecisionFunc[S]) Option[S] {
+	return func(p *Parser[S]) error {
+		if decision_func == nil {
+			return gcers.NewErrNilParameter("decision_func")
+		}
+
+		p.decision = decision_func
+
+		return nil
+	}
+}
+
+// WithStateFunc sets the function that describes the states of the automaton in
+// the step debugger.
+//
+// Parameters:
+//   - fn: The function. If nil, the states are not displayed.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithStateFunc[S gr.TokenTyper](fn StateFunc[S]) Option[S] {
+	return func(p *Parser[S]) error {
+		p.SetStateFunc(fn)
+
+		return nil
+	}
+}
+
+// WithBreakpoints adds breakpoints to the step debugger.
+//
+// Parameters:
+//   - bps: The breakpoints to add. Nil breakpoints are ignored.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithBreakpoints[S gr.TokenTyper](bps ...Breakpoint[S]) Option[S] {
+	return func(p *Parser[S]) error {
+		p.AddBreakpoints(bps...)
+
+		return nil
+	}
+}
+
+// WithReporter sets the reporter to which the parse errors are added.
+//
+// Parameters:
+//   - r: The reporter. If nil, the errors are only returned.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithReporter[S gr.TokenTyper](r gr.Reporter) Option[S] {
+	return func(p *Parser[S]) error {
+		p.SetReporter(r)
+
+		return nil
+	}
+}
+
+// WithSuggestionProvider sets the provider of the suggestions of the failed
+// decisions.
+//
+// Parameters:
+//   - sp: The provider. If nil, there is no suggestion.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithSuggestionProvider[S gr.TokenTyper](sp gr.SuggestionProvider) Option[S] {
+	return func(p *Parser[S]) error {
+		p.SetSuggestionProvider(sp)
+
+		return nil
+	}
+}
@@ -0,0 +1,52 @@
+package parsing
+
+import (
+	"errors"
+	"testing"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+// test_reporter is a reporter that keeps the reported errors.
+type test_reporter []error
+
+// Add implements the grammar.Reporter interface.
+func (r *test_reporter) Add(_ grm.Span, err error) {
+	*r = append(*r, err)
+}
+
+func TestNewParserWithOptions(t *testing.T) {
+	_, err := NewParserWithOptions[test_type]()
+
+	var param_err *gcers.ErrInvalidParameter
+
+	if !errors.As(err, &param_err) {
+		t.Fatalf("expected an *ErrInvalidParameter without decision function, got %v", err)
+	}
+
+	var reported test_reporter
+
+	p, err := NewParserWithOptions(
+		WithDecision(func(_ *Parser[test_type], _ *gr.Token[test_type]) (Actioner, error) {
+			return nil, errors.New("no action")
+		}),
+		WithReporter[test_type](&reported),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	eof := gr.NewToken(test_type(0), "", 1, nil)
+	id := gr.NewToken(test_type(1), "a", 0, eof)
+
+	res := p.FullParse([]*gr.Token[test_type]{id, eof})
+	if res.Err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+
+	if len(reported) != 1 || reported[0] != res.Err {
+		t.Errorf("expected the error to be reported once, got %v", reported)
+	}
+}
@@ -1,16 +1,19 @@
 package parsing
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"slices"
 	"strings"
 
+	gcers "github.com/PlayerR9/go-commons/errors"
 	gcos "github.com/PlayerR9/go-commons/os"
 	gcstr "github.com/PlayerR9/go-commons/strings"
 	"github.com/PlayerR9/grammar/PREV/OLD/ast"
 	displ "github.com/PlayerR9/grammar/PREV/OLD/displayer"
 	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	grm "github.com/PlayerR9/grammar/grammar"
 )
 
 // DecisionFunc is the function that returns the decision of the parser.
@@ -38,11 +41,39 @@ type Parser[S gr.TokenTyper] struct {
 	// decision is the function that returns the decision of the parser.
 	decision DecisionFunc[S]
 
-	// Err is the error reason of the parser.
-	Err *displ.ErrParsing
+	// err is the error reason of the parser. See the Err field of the result of
+	// FullParse.
+	err *displ.ErrParsing
 
 	// last_action is the last action of the parser.
 	last_action Actioner
+
+	// state_fn describes the states of the automaton. Nil if they are not described.
+	state_fn StateFunc[S]
+
+	// last_state is the state in which the last decision was taken. Nil if unknown.
+	last_state *StateInfo
+
+	// last_lookahead is the lookahead of the last decision.
+	last_lookahead *gr.Token[S]
+
+	// breakpoints are the breakpoints of the step debugger.
+	breakpoints []Breakpoint[S]
+
+	// running is true if the step debugger runs until the next breakpoint.
+	running bool
+
+	// pool is the pool the non-terminal tokens are taken from. Nil if they are
+	// allocated.
+	pool *gr.TokenPool[S]
+
+	// reporter is the reporter the parse errors are added to. Nil if they are only
+	// returned.
+	reporter gr.Reporter
+
+	// suggestions is the provider of the suggestions of the unexpected tokens. Nil
+	// if there is none.
+	suggestions gr.SuggestionProvider
 }
 
 // NewParser creates a new parser.
@@ -64,6 +95,22 @@ func NewParser[S gr.TokenTyper](decision_func DecisionFunc[S]) *Parser[S] {
 	}
 }
 
+// call_decision calls the user-supplied decision function. Panics are converted
+// into errors of type *grammar.ErrPanic so that a buggy function cannot crash the
+// caller.
+//
+// Parameters:
+//   - lookahead: The lookahead token.
+//
+// Returns:
+//   - Actioner: The action returned by the decision function.
+//   - error: The error returned by the decision function, if any.
+func (p *Parser[S]) call_decision(lookahead *gr.Token[S]) (act Actioner, err error) {
+	defer grm.Recover(&err)
+
+	return p.decision(p, lookahead)
+}
+
 // SetInputStream sets the input stream of the parser.
 //
 // Parameters:
@@ -79,7 +126,7 @@ func (p *Parser[S]) SetInputStream(tokens []*gr.Token[S]) {
 	gr.CleanTokens(p.popped)
 	p.popped = p.popped[:0]
 
-	p.Err = nil
+	p.err = nil
 
 	// Set the new input stream.
 
@@ -179,6 +226,26 @@ func (p *Parser[S]) Accept() {
 	p.popped = p.popped[:0]
 }
 
+// ContextSize is the number of stack tokens that parse errors carry as context.
+const ContextSize int = 3
+
+// context returns the nearest tokens of the stack, including the popped ones.
+//
+// Parameters:
+//   - n: The maximum number of tokens to return.
+//
+// Returns:
+//   - []*gr.Token[S]: The nearest tokens, from the furthest to the nearest one.
+func (p Parser[S]) context(n int) []*gr.Token[S] {
+	tokens := append(slices.Clone(p.stack), p.GetPopped()...)
+
+	if len(tokens) > n {
+		tokens = tokens[len(tokens)-n:]
+	}
+
+	return tokens
+}
+
 // get_forest returns the syntax forest of the parser.
 //
 // Parameters:
@@ -212,12 +279,13 @@ func get_forest[S gr.TokenTyper](parser *Parser[S]) []*gr.Token[S] {
 //   - rule: The rule to reduce.
 //
 // Returns:
-//   - error: An error if the parser encounters an error while applying the reduce action.
+//   - error: An error if the parser encounters an error while applying the reduce
+//     action, or an error of type *errors.ErrInvalidParameter if rule is nil.
 func apply_reduce[S gr.TokenTyper](parser *Parser[S], rule *Rule[S]) error {
 	if parser == nil {
 		panic("parser cannot be nil")
 	} else if rule == nil {
-		panic("rule cannot be nil")
+		return gcers.NewErrNilParameter("rule")
 	}
 
 	var prev *S
@@ -225,13 +293,13 @@ func apply_reduce[S gr.TokenTyper](parser *Parser[S], rule *Rule[S]) error {
 	for _, rhs := range rule.GetRhss() {
 		top, ok := parser.Pop()
 		if !ok {
-			return NewErrUnexpectedToken(prev, nil, rhs)
+			return NewErrUnexpectedToken(prev, nil, rhs).WithContext(rule, parser.context(ContextSize))
 		}
 
 		top_type := top.GetType()
 
 		if top_type != rhs {
-			return NewErrUnexpectedToken(prev, &top_type, rhs)
+			return NewErrUnexpectedToken(prev, &top_type, rhs).WithContext(rule, parser.context(ContextSize))
 		}
 	}
 
@@ -240,40 +308,149 @@ func apply_reduce[S gr.TokenTyper](parser *Parser[S], rule *Rule[S]) error {
 
 	parser.Accept()
 
-	tk := gr.NewToken(rule.lhs, "", popped[0].At, last_token.Lookahead)
+	tk := parser.pool.Get(rule.lhs, "", popped[0].At, last_token.Lookahead)
+	tk.Start = popped[0].Start
+	tk.End = last_token.End
 	tk.AddChildren(popped)
+	tk.ComputeRange()
 
 	parser.Push(tk)
 
 	return nil
 }
 
+// error_at is a helper function that creates the parse error located at a token.
+//
+// Parameters:
+//   - tk: The token. Assumed to be non-nil.
+//   - reason: The reason of the error.
+//
+// Returns:
+//   - *displ.ErrParsing: The error, with the position of the token. Never returns nil.
+func error_at[S gr.TokenTyper](tk *gr.Token[S], reason error) *displ.ErrParsing {
+	err := displ.NewErrParsing(tk.At, -1, reason)
+	err.Start = tk.Start
+
+	return err
+}
+
+// decision_error is a helper function that creates the parse error of a failed
+// decision, with the suggestions for the lookahead token, if any.
+//
+// Parameters:
+//   - top: The top of the stack. Assumed to be non-nil.
+//   - reason: The reason of the error.
+//
+// Returns:
+//   - *displ.ErrParsing: The error, with the position of top. Never returns nil.
+func (p Parser[S]) decision_error(top *gr.Token[S], reason error) *displ.ErrParsing {
+	err := error_at(top, reason)
+
+	if top.Lookahead == nil {
+		return err
+	}
+
+	suggestion := gr.DidYouMean(p.suggestions, top.Lookahead.Data)
+	if suggestion != "" {
+		err.SetSuggestion(suggestion)
+	}
+
+	return err
+}
+
+// result makes the result of a parse from the given forest and the error of the parser.
+//
+// Parameters:
+//   - forest: The syntax forest.
+//
+// Returns:
+//   - grm.Result[*gr.Token[S]]: The result.
+func (p Parser[S]) result(forest []*gr.Token[S]) grm.Result[*gr.Token[S]] {
+	if p.err != nil {
+		gr.Report(p.reporter, p.err)
+
+		return grm.NewFailedResult(forest, error(p.err)).At(p.err.StartPos)
+	} else if len(forest) != 1 {
+		err := fmt.Errorf("expected exactly one root but got %d", len(forest))
+
+		gr.Report(p.reporter, gr.WithCode(gr.CodeParsing, err))
+
+		return grm.NewFailedResult(forest, err)
+	}
+
+	return grm.NewResult(forest[0])
+}
+
+// SetSuggestionProvider sets the provider of the suggestions of the failed
+// decisions, which are computed for the data of the lookahead token. A
+// *matcher.LavenshteinTable of the keywords of the grammar suggests the closest
+// keywords.
+//
+// Parameters:
+//   - sp: The provider. If nil, there is no suggestion.
+func (p *Parser[S]) SetSuggestionProvider(sp gr.SuggestionProvider) {
+	p.suggestions = sp
+}
+
+// SetReporter sets the reporter to which the parse errors are added, on top of
+// being returned in the results; so that the errors of the lexer, the parser and
+// the AST builder of a run can be collected in a single *displayer.Diagnostics.
+//
+// Parameters:
+//   - r: The reporter. If nil, the errors are only returned.
+func (p *Parser[S]) SetReporter(r gr.Reporter) {
+	p.reporter = r
+}
+
 // FullParse is just a wrapper around the Grammar.FullParse function.
 //
 // Parameters:
 //   - tokens: The input stream of the parser.
 //
 // Returns:
-//   - []*gr.Token[S]: The syntax forest of the input stream.
-func (p *Parser[S]) FullParse(tokens []*gr.Token[S]) []*gr.Token[S] {
+//   - grm.Result[*gr.Token[S]]: The result of the parse.
+func (p *Parser[S]) FullParse(tokens []*gr.Token[S]) grm.Result[*gr.Token[S]] {
+	return p.FullParseCtx(context.Background(), tokens)
+}
+
+// FullParseCtx is like FullParse but stops as soon as the context is done.
+//
+// Parameters:
+//   - ctx: The context.
+//   - tokens: The input stream of the parser.
+//
+// Returns:
+//   - grm.Result[*gr.Token[S]]: The result of the parse. On cancellation, the
+//     forest parsed so far with an error of type *grm.ErrCancelled.
+func (p *Parser[S]) FullParseCtx(ctx context.Context, tokens []*gr.Token[S]) grm.Result[*gr.Token[S]] {
 	p.SetInputStream(tokens)
 
 	ok := p.Shift() // initial shift
 	if !ok {
 		forest := get_forest(p)
 
-		p.Err = displ.NewErrParsing(0, -1, errors.New("no tokens were specified"))
+		p.err = displ.NewErrParsing(0, -1, errors.New("no tokens were specified"))
 
-		return forest
+		return p.result(forest)
 	}
 
-	for p.Err == nil {
+	var steps int
+
+	for p.err == nil {
 		top, _ := p.Peek()
 		// luc.AssertOk(ok, "parser.Peek()")
 
-		act, err := p.decision(p, top.Lookahead)
+		if err := ctx.Err(); err != nil {
+			forest := get_forest(p)
+
+			return grm.NewFailedResult(forest, error(grm.NewErrCancelled(err, steps))).At(top.At)
+		}
+
+		steps++
+
+		act, err := p.call_decision(top.Lookahead)
 		if err != nil {
-			p.Err = displ.NewErrParsing(top.At, -1, err)
+			p.err = p.decision_error(top, err)
 			p.Refuse()
 			break
 		}
@@ -287,63 +464,84 @@ func (p *Parser[S]) FullParse(tokens []*gr.Token[S]) []*gr.Token[S] {
 		case *ReduceAction[S]:
 			err := apply_reduce(p, act.rule)
 			if err != nil {
-				p.Err = displ.NewErrParsing(top.At, -1, err)
+				p.err = error_at(top, err)
 			}
 		case *AcceptAction[S]:
 			err := apply_reduce(p, act.rule)
 			if err == nil {
 				forest := get_forest(p)
 
-				return forest
+				return p.result(forest)
 			}
 
-			p.Err = displ.NewErrParsing(top.At, -1, err)
+			p.err = error_at(top, err)
 		default:
-			p.Err = displ.NewErrParsing(top.At, -1, errors.New("invalid action type"))
+			p.err = error_at(top, errors.New("invalid action type"))
 		}
 	}
 
 	p.Refuse()
 	forest := get_forest(p)
 
-	return forest
+	return p.result(forest)
 }
 
 // FullParseWithSteps is like FullParse but, for each step, it pauses and prints
-// its debug state.
+// its debug state. If the parser has breakpoints, it only starts pausing once one
+// of them is hit.
 //
 // Parameters:
 //   - tokens: The input stream of the parser.
 //
 // Returns:
-//   - []*gr.Token[S]: The syntax forest of the input stream.
-func (p *Parser[S]) FullParseWithSteps(tokens []*gr.Token[S], data []byte, tab_size int) []*gr.Token[S] {
+//   - grm.Result[*gr.Token[S]]: The result of the parse.
+func (p *Parser[S]) FullParseWithSteps(tokens []*gr.Token[S], data []byte, tab_size int) grm.Result[*gr.Token[S]] {
+	p.running = len(p.breakpoints) > 0
+
+	step := func(title string) {
+		_ = p.Step(title, data, tab_size)
+		// dbg.AssertErr(err, "parser.Step()")
+	}
+
+	return p.steps(tokens, step)
+}
+
+// steps is a helper function that parses the tokens and calls the given function
+// at each step of the parse.
+//
+// Parameters:
+//   - tokens: The input stream of the parser.
+//   - step: The function to call at each step, with the title of the step.
+//
+// Returns:
+//   - grm.Result[*gr.Token[S]]: The result of the parse.
+func (p *Parser[S]) steps(tokens []*gr.Token[S], step func(title string)) grm.Result[*gr.Token[S]] {
 	p.SetInputStream(tokens)
 
-	_ = p.Step("\t\t**Initial State:**\n", data, tab_size)
-	// dbg.AssertErr(err, "parser.Step()")
+	step("\t\t**Initial State:**\n")
 
 	ok := p.Shift() // initial shift
 	if !ok {
 		forest := get_forest(p)
 
-		p.Err = displ.NewErrParsing(0, -1, errors.New("no tokens were specified"))
+		p.err = displ.NewErrParsing(0, -1, errors.New("no tokens were specified"))
 
-		return forest
+		return p.result(forest)
 	}
 
 	p.last_action = NewShiftAction()
 
-	_ = p.Step("\t\t**Initial Shift:**\n", data, tab_size)
-	// dbg.AssertErr(err, "parser.Step()")
+	step("\t\t**Initial Shift:**\n")
 
-	for p.Err == nil {
+	for p.err == nil {
 		top, _ := p.Peek()
 		// luc.AssertOk(ok, "parser.Peek()")
 
-		act, err := p.decision(p, top.Lookahead)
+		p.describe_state(top.Lookahead)
+
+		act, err := p.call_decision(top.Lookahead)
 		if err != nil {
-			p.Err = displ.NewErrParsing(top.At, -1, err)
+			p.err = p.decision_error(top, err)
 			p.Refuse()
 			break
 		}
@@ -352,8 +550,7 @@ func (p *Parser[S]) FullParseWithSteps(tokens []*gr.Token[S], data []byte, tab_s
 
 		p.last_action = act
 
-		_ = p.Step("\t\t**Decision:**\n", data, tab_size)
-		// dbg.AssertErr(err, "parser.Step()")
+		step("\t\t**Decision:**\n")
 
 		switch act := act.(type) {
 		case *ShiftAction:
@@ -362,34 +559,35 @@ func (p *Parser[S]) FullParseWithSteps(tokens []*gr.Token[S], data []byte, tab_s
 		case *ReduceAction[S]:
 			err := apply_reduce(p, act.rule)
 			if err != nil {
-				p.Err = displ.NewErrParsing(top.At, -1, err)
+				p.err = error_at(top, err)
 			}
 		case *AcceptAction[S]:
 			err := apply_reduce(p, act.rule)
 			if err == nil {
 				forest := get_forest(p)
 
-				return forest
+				return p.result(forest)
 			}
 
-			p.Err = displ.NewErrParsing(top.At, -1, err)
+			p.err = error_at(top, err)
 		default:
-			p.Err = displ.NewErrParsing(top.At, -1, errors.New("invalid action type"))
+			p.err = error_at(top, errors.New("invalid action type"))
 		}
 
 		p.last_action = nil
+		p.last_state = nil
 
-		_ = p.Step("\t\t**Apply Action:**\n", data, tab_size)
-		// dbg.AssertErr(err, "parser.Step()")
+		step("\t\t**Apply Action:**\n")
 	}
 
 	p.Refuse()
 	forest := get_forest(p)
 
-	_ = p.Step("\t\t**Final State:**\n", data, tab_size)
-	// dbg.AssertErr(err, "parser.Step()")
+	p.running = false
 
-	return forest
+	step("\t\t**Final State:**\n")
+
+	return p.result(forest)
 }
 
 // display_stack is a helper function that displays the stack.
@@ -467,6 +665,10 @@ func (p Parser[S]) display_data(data []byte, tab_size int) {
 // Returns:
 //   - error: Any error that might have occurred. This is used for fatal errors.
 func (p *Parser[S]) Step(title string, data []byte, tab_size int) error {
+	if !p.should_pause() {
+		return nil
+	}
+
 	gcos.ClearScreen()
 
 	p.display_data(data, tab_size)
@@ -484,8 +686,7 @@ func (p *Parser[S]) Step(title string, data []byte, tab_size int) error {
 
 		fmt.Println()
 
-		fmt.Println("Press ENTER to continue...")
-		fmt.Scanln()
+		p.wait()
 
 		return nil
 	}
@@ -517,12 +718,12 @@ func (p *Parser[S]) Step(title string, data []byte, tab_size int) error {
 
 	fmt.Println()
 
+	p.display_state()
 	p.display_tokens(3 * 80)
 	p.display_stack()
 	fmt.Println()
 
-	fmt.Println("Press ENTER to continue...")
-	fmt.Scanln()
+	p.wait()
 
 	return nil
 }
@@ -0,0 +1,31 @@
+package parsing
+
+import (
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+// SetTokenPool sets the pool the non-terminal tokens of the parser are taken from.
+// The trees of the results are not returned to it; they are to be returned with
+// pool.CleanTokens once they are no longer used.
+//
+// Parameters:
+//   - pool: The pool. If nil, every token is allocated.
+func (p *Parser[S]) SetTokenPool(pool *gr.TokenPool[S]) {
+	p.pool = pool
+}
+
+// WithTokenPool sets the pool the non-terminal tokens of the parser are taken
+// from.
+//
+// Parameters:
+//   - pool: The pool. If nil, every token is allocated.
+//
+// Returns:
+//   - Option[S]: The option.
+func WithTokenPool[S gr.TokenTyper](pool *gr.TokenPool[S]) Option[S] {
+	return func(p *Parser[S]) error {
+		p.SetTokenPool(pool)
+
+		return nil
+	}
+}
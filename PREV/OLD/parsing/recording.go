@@ -0,0 +1,296 @@
+package parsing
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+// RecordingVersion is the version of the format written by FullParseRecorded. It
+// is increased whenever the format changes in a way that older releases cannot
+// read.
+const RecordingVersion int = 1
+
+// recording_header is the first line of a recording.
+type recording_header struct {
+	// Format is the name of the format; always "recording".
+	Format string `json:"format"`
+
+	// Version is the version of the format.
+	Version int `json:"version"`
+}
+
+// Snapshot is the state of the parser at a step of the parse.
+type Snapshot struct {
+	// Step is the index of the step, starting from 0.
+	Step int `json:"step"`
+
+	// Title is the title of the step.
+	Title string `json:"title"`
+
+	// Action is the action that was decided at the step. Empty if none.
+	Action string `json:"action,omitempty"`
+
+	// Stack are the types of the tokens of the stack, from the bottom to the top.
+	Stack []string `json:"stack"`
+
+	// Next is the next token to shift. Empty if none.
+	Next string `json:"next,omitempty"`
+}
+
+// snapshot is a helper function that takes a snapshot of the parser.
+//
+// Parameters:
+//   - step: The index of the step.
+//   - title: The title of the step.
+//
+// Returns:
+//   - Snapshot: The snapshot.
+func (p Parser[S]) snapshot(step int, title string) Snapshot {
+	snap := Snapshot{
+		Step:  step,
+		Title: strings.Trim(title, "\t\n*"),
+		Stack: make([]string, 0, len(p.stack)),
+	}
+
+	switch act := p.last_action.(type) {
+	case nil:
+	case *ReduceAction[S]:
+		snap.Action = action_of("reduce", act.rule)
+	case *AcceptAction[S]:
+		snap.Action = action_of("accept", act.rule)
+	default:
+		snap.Action = strings.ToLower(act.String())
+	}
+
+	for _, tk := range p.stack {
+		snap.Stack = append(snap.Stack, tk.Type.GoString())
+	}
+
+	if len(p.tokens) > 0 {
+		snap.Next = p.tokens[0].String()
+	}
+
+	return snap
+}
+
+// action_of is a helper function that describes an action on a rule.
+//
+// Parameters:
+//   - name: The name of the action.
+//   - rule: The rule of the action. Nil if the decision gave none.
+//
+// Returns:
+//   - string: The description of the action.
+func action_of[S gr.TokenTyper](name string, rule *Rule[S]) string {
+	if rule == nil {
+		return name
+	}
+
+	return name + " " + rule.String()
+}
+
+// FullParseRecorded is like FullParseWithSteps but, instead of pausing, it writes
+// a snapshot of every step to the given writer, one JSON object per line, after a
+// header that holds the version of the format (see RecordingVersion). Two
+// recordings of the same input can be compared with CompareRecordings; for
+// instance, to check that a refactor of the decision function did not change its
+// behavior.
+//
+// Parameters:
+//   - tokens: The input stream of the parser.
+//   - w: The writer to write the recording to.
+//
+// Returns:
+//   - grm.Result[*gr.Token[S]]: The result of the parse.
+//   - error: An error if the recording could not be written.
+func (p *Parser[S]) FullParseRecorded(tokens []*gr.Token[S], w io.Writer) (grm.Result[*gr.Token[S]], error) {
+	if w == nil {
+		return grm.Result[*gr.Token[S]]{}, gcers.NewErrNilParameter("w")
+	}
+
+	enc := json.NewEncoder(w)
+
+	var idx int
+
+	write_err := enc.Encode(recording_header{
+		Format:  "recording",
+		Version: RecordingVersion,
+	})
+
+	step := func(title string) {
+		if write_err != nil {
+			return
+		}
+
+		write_err = enc.Encode(p.snapshot(idx, title))
+		idx++
+	}
+
+	res := p.steps(tokens, step)
+
+	return res, write_err
+}
+
+// ReadRecording reads a recording written by FullParseRecorded.
+//
+// Parameters:
+//   - r: The reader to read the recording from.
+//
+// Returns:
+//   - []Snapshot: The snapshots of the recording.
+//   - error: An error if the recording could not be read or is malformed, or an
+//     error of type *grammar.ErrUnsupportedVersion if it was written in a version
+//     of the format that cannot be read.
+func ReadRecording(r io.Reader) ([]Snapshot, error) {
+	if r == nil {
+		return nil, gcers.NewErrNilParameter("r")
+	}
+
+	var snaps []Snapshot
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+
+	first := true
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		if first {
+			first = false
+
+			var header recording_header
+
+			err := json.Unmarshal(line, &header)
+			if err != nil {
+				return nil, fmt.Errorf("header: %w", err)
+			} else if header.Format != "recording" {
+				return nil, fmt.Errorf("expected a recording, got a %q instead", header.Format)
+			}
+
+			err = grm.CheckVersion("recording", header.Version, 1, RecordingVersion)
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		var snap Snapshot
+
+		err := json.Unmarshal(line, &snap)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %d: %w", len(snaps), err)
+		}
+
+		snaps = append(snaps, snap)
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return snaps, nil
+}
+
+// RecordingDiff is the first difference between two recordings.
+type RecordingDiff struct {
+	// Step is the index of the step at which the recordings differ.
+	Step int
+
+	// Field is the name of the field of the snapshots that differs.
+	Field string
+
+	// Old is the value of the field in the first recording.
+	Old string
+
+	// New is the value of the field in the second recording.
+	New string
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Format: "step <step>: <field>: <old> != <new>".
+func (d RecordingDiff) String() string {
+	return fmt.Sprintf("step %d: %s: %s != %s", d.Step, d.Field, d.Old, d.New)
+}
+
+// CompareRecordings compares two recordings written by FullParseRecorded.
+//
+// Parameters:
+//   - old: The reader of the first recording.
+//   - new: The reader of the second recording.
+//
+// Returns:
+//   - *RecordingDiff: The first difference between the recordings. Nil if they are
+//     the same.
+//   - error: An error if a recording could not be read.
+//
+// Only the first difference is reported as the steps that follow a divergence
+// rarely line up.
+func CompareRecordings(old, new io.Reader) (*RecordingDiff, error) {
+	a, err := ReadRecording(old)
+	if err != nil {
+		return nil, fmt.Errorf("old recording: %w", err)
+	}
+
+	b, err := ReadRecording(new)
+	if err != nil {
+		return nil, fmt.Errorf("new recording: %w", err)
+	}
+
+	for i := range min(len(a), len(b)) {
+		diff := compare_snapshots(a[i], b[i])
+		if diff != nil {
+			diff.Step = i
+
+			return diff, nil
+		}
+	}
+
+	if len(a) == len(b) {
+		return nil, nil
+	}
+
+	return &RecordingDiff{
+		Step:  min(len(a), len(b)),
+		Field: "steps",
+		Old:   fmt.Sprintf("%d steps", len(a)),
+		New:   fmt.Sprintf("%d steps", len(b)),
+	}, nil
+}
+
+// compare_snapshots is a helper function that compares two snapshots.
+//
+// Parameters:
+//   - a: The first snapshot.
+//   - b: The second snapshot.
+//
+// Returns:
+//   - *RecordingDiff: The first difference. Nil if the snapshots are the same.
+func compare_snapshots(a, b Snapshot) *RecordingDiff {
+	switch {
+	case a.Title != b.Title:
+		return &RecordingDiff{Field: "title", Old: a.Title, New: b.Title}
+	case a.Action != b.Action:
+		return &RecordingDiff{Field: "action", Old: a.Action, New: b.Action}
+	case !slices.Equal(a.Stack, b.Stack):
+		return &RecordingDiff{Field: "stack", Old: strings.Join(a.Stack, " "), New: strings.Join(b.Stack, " ")}
+	case a.Next != b.Next:
+		return &RecordingDiff{Field: "next", Old: a.Next, New: b.Next}
+	default:
+		return nil
+	}
+}
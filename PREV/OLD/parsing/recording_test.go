@@ -0,0 +1,71 @@
+package parsing
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+func TestFullParseRecordedNilRule(t *testing.T) {
+	p := NewParser(func(_ *Parser[test_type], _ *gr.Token[test_type]) (Actioner, error) {
+		return &ReduceAction[test_type]{}, nil
+	})
+
+	eof := gr.NewToken(test_type(0), "", 1, nil)
+	id := gr.NewToken(test_type(1), "a", 0, eof)
+
+	var buf bytes.Buffer
+
+	res, err := p.FullParseRecorded([]*gr.Token[test_type]{id, eof}, &buf)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var param_err *gcers.ErrInvalidParameter
+
+	if !errors.As(res.Err, &param_err) {
+		t.Fatalf("expected an *ErrInvalidParameter, got %v", res.Err)
+	}
+
+	if !strings.Contains(buf.String(), `"action":"reduce"`) {
+		t.Errorf("expected the recording to hold the reduce action, got %s", buf.String())
+	}
+}
+
+func TestReadRecordingVersion(t *testing.T) {
+	tests := []struct {
+		data    string
+		version bool
+	}{
+		{`{"index":0,"title":"shift"}` + "\n", false},
+		{`{"format":"recording","version":0}` + "\n", true},
+		{`{"format":"recording","version":2}` + "\n", true},
+	}
+
+	for _, test := range tests {
+		_, err := ReadRecording(strings.NewReader(test.data))
+		if err == nil {
+			t.Errorf("%q: expected an error, got none", test.data)
+
+			continue
+		}
+
+		var version_err *grm.ErrUnsupportedVersion
+
+		if got := errors.As(err, &version_err); got != test.version {
+			t.Errorf("%q: expected an *ErrUnsupportedVersion to be %t, got %v", test.data, test.version, err)
+		}
+	}
+
+	snaps, err := ReadRecording(strings.NewReader(`{"format":"recording","version":1}` + "\n"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	} else if len(snaps) != 0 {
+		t.Errorf("expected no snapshots, got %d", len(snaps))
+	}
+}
@@ -0,0 +1,109 @@
+package parsing
+
+import (
+	"fmt"
+
+	gr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+// TableEntry is an entry of a row of a parse table.
+type TableEntry struct {
+	// Lookahead is the name of the lookahead of the entry.
+	Lookahead string
+
+	// Action is the description of the action of the entry.
+	Action string
+}
+
+// StateInfo describes the state of the automaton in which a decision is taken.
+type StateInfo struct {
+	// Name is the name of the state.
+	Name string
+
+	// Items are the items of the state.
+	Items []string
+
+	// Row is the row of the parse table of the state.
+	Row []TableEntry
+}
+
+// StateFunc describes the state of the automaton in which the decision function
+// is about to decide. Decision functions that are generated from an automaton
+// can provide one so that the step debugger shows the automaton along with the
+// stack and the tokens.
+//
+// Parameters:
+//   - parser: The parser.
+//   - lookahead: The lookahead token.
+//
+// Returns:
+//   - StateInfo: The state.
+//   - bool: True if the state is known, false otherwise.
+type StateFunc[S gr.TokenTyper] func(parser *Parser[S], lookahead *gr.Token[S]) (StateInfo, bool)
+
+// SetStateFunc sets the function that describes the states of the automaton in
+// the step debugger.
+//
+// Parameters:
+//   - fn: The function. If nil, the states are not displayed.
+func (p *Parser[S]) SetStateFunc(fn StateFunc[S]) {
+	p.state_fn = fn
+}
+
+// describe_state is a helper function that records the state in which the next
+// decision is taken.
+//
+// Parameters:
+//   - lookahead: The lookahead token.
+func (p *Parser[S]) describe_state(lookahead *gr.Token[S]) {
+	p.last_state = nil
+	p.last_lookahead = lookahead
+
+	if p.state_fn == nil {
+		return
+	}
+
+	info, ok := p.state_fn(p, lookahead)
+	if ok {
+		p.last_state = &info
+	}
+}
+
+// display_state is a helper function that displays the state in which the last
+// decision was taken. The entry of the row that matches the lookahead is marked.
+func (p Parser[S]) display_state() {
+	if p.last_state == nil {
+		return
+	}
+
+	fmt.Printf("State: %s\n", p.last_state.Name)
+
+	for _, item := range p.last_state.Items {
+		fmt.Printf("    %s\n", item)
+	}
+
+	if len(p.last_state.Row) == 0 {
+		fmt.Println()
+
+		return
+	}
+
+	la := S(0).String() // EOF
+
+	if p.last_lookahead != nil {
+		la = p.last_lookahead.Type.String()
+	}
+
+	fmt.Println("Table row:")
+
+	for _, entry := range p.last_state.Row {
+		mark := " "
+		if entry.Lookahead == la {
+			mark = ">"
+		}
+
+		fmt.Printf("  %s %s: %s\n", mark, entry.Lookahead, entry.Action)
+	}
+
+	fmt.Println()
+}
@@ -0,0 +1,173 @@
+package ast
+
+import (
+	"errors"
+	"maps"
+
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+	internal "github.com/PlayerR9/grammar/PREV/internal"
+	uttr "github.com/PlayerR9/tree/tree"
+)
+
+// TransformFunc is a function that converts a token, whose children were already
+// converted, to AST nodes.
+//
+// Parameters:
+//   - tk: The token. Assume tk is not nil.
+//   - children: The nodes of the children of the token, in order.
+//
+// Returns:
+//   - []N: The nodes of the token. Usually one, but tokens such as lists may give
+//     several nodes (or none) to their parent.
+//   - error: An error if the function failed.
+type TransformFunc[T internal.TokenTyper, N any] func(tk *gr.Token[T], children []N) ([]N, error)
+
+// Builder builds user-defined AST nodes from the trees of a parse by walking them
+// bottom-up.
+type Builder[T internal.TokenTyper, N any] struct {
+	// table are the transform functions of each token type.
+	table map[T]TransformFunc[T, N]
+}
+
+// NewBuilder creates a new builder.
+//
+// Parameters:
+//   - table: The transform functions of each token type. Nil functions are
+//     ignored.
+//
+// Returns:
+//   - *Builder[T, N]: The new builder. Never returns nil.
+//
+// Tokens whose type has no transform function are transparent: the nodes of their
+// children are given to their parent as is.
+func NewBuilder[T internal.TokenTyper, N any](table map[T]TransformFunc[T, N]) *Builder[T, N] {
+	b := &Builder[T, N]{
+		table: make(map[T]TransformFunc[T, N], len(table)),
+	}
+
+	maps.Copy(b.table, table)
+	maps.DeleteFunc(b.table, func(_ T, fn TransformFunc[T, N]) bool {
+		return fn == nil
+	})
+
+	return b
+}
+
+// Build builds the AST nodes of a forest, such as the one of an active parser.
+//
+// Parameters:
+//   - forest: The trees of the parse. Nil trees are ignored.
+//
+// Returns:
+//   - []N: The nodes of the trees, in order. Trees that failed give no node.
+//   - error: The errors of every failed subtree, each of type *ErrIn wrapped by
+//     the ErrIn of its ancestors, joined. Nil if no subtree failed.
+func (b Builder[T, N]) Build(forest []*uttr.Tree[*gr.Token[T]]) ([]N, error) {
+	var nodes []N
+	var errs []error
+
+	for _, tree := range forest {
+		if tree == nil {
+			continue
+		}
+
+		sub_nodes, err := b.BuildToken(tree.Root())
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			nodes = append(nodes, sub_nodes...)
+		}
+	}
+
+	return nodes, errors.Join(errs...)
+}
+
+// BuildToken builds the AST nodes of a parse tree.
+//
+// Parameters:
+//   - root: The root of the parse tree.
+//
+// Returns:
+//   - []N: The nodes of the parse tree.
+//   - error: An error of type *ErrIn if a subtree failed. Its reason joins the
+//     errors of the failed children; so, errors of sibling subtrees are all
+//     reported.
+func (b Builder[T, N]) BuildToken(root *gr.Token[T]) ([]N, error) {
+	if root == nil {
+		return nil, nil
+	}
+
+	type frame struct {
+		tk       *gr.Token[T]
+		next     *gr.Token[T]
+		children []N
+		errs     []error
+	}
+
+	// Tokens are visited in post-order with an explicit stack so that the depth of
+	// the parse tree is not bound by the one of the goroutine stack.
+	stack := []*frame{{tk: root, next: root.FirstChild}}
+
+	for {
+		top := stack[len(stack)-1]
+
+		if top.next != nil {
+			child := top.next
+			top.next = child.NextSibling
+
+			stack = append(stack, &frame{tk: child, next: child.FirstChild})
+
+			continue
+		}
+
+		stack = stack[:len(stack)-1]
+
+		nodes, err := b.transform(top.tk, top.children, top.errs)
+
+		if len(stack) == 0 {
+			return nodes, err
+		}
+
+		parent := stack[len(stack)-1]
+
+		switch {
+		case err != nil:
+			parent.errs = append(parent.errs, err)
+		case len(parent.children) == 0:
+			// Reusing the nodes avoids copying them again at each transparent token
+			// of a deep chain.
+			parent.children = nodes
+		default:
+			parent.children = append(parent.children, nodes...)
+		}
+	}
+}
+
+// transform is a helper function that builds the AST nodes of a token whose
+// children were already built.
+//
+// Parameters:
+//   - tk: The token. Assumed to be non-nil.
+//   - children: The nodes of the children of the token.
+//   - errs: The errors of the failed children of the token.
+//
+// Returns:
+//   - []N: The nodes of the token.
+//   - error: An error of type *ErrIn if a child or the token itself failed.
+func (b Builder[T, N]) transform(tk *gr.Token[T], children []N, errs []error) ([]N, error) {
+	if len(errs) > 0 {
+		return nil, NewErrIn(tk.Type, errors.Join(errs...))
+	}
+
+	fn, ok := b.table[tk.Type]
+	if !ok {
+		return children, nil
+	}
+
+	nodes, err := fn(tk, children)
+	if err != nil {
+		return nil, NewErrIn(tk.Type, err)
+	}
+
+	return nodes, nil
+}
@@ -0,0 +1,453 @@
+package ast
+
+import (
+	"errors"
+	"strconv"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+	internal "github.com/PlayerR9/grammar/PREV/internal"
+)
+
+// Action is what a walk does after a token is visited.
+type Action int
+
+const (
+	// Continue goes on with the walk.
+	Continue Action = iota
+
+	// SkipChildren goes on with the walk without visiting the children of the
+	// token. It only has an effect when the token is entered.
+	SkipChildren
+
+	// Stop ends the walk.
+	Stop
+)
+
+// String implements the fmt.Stringer interface.
+func (a Action) String() string {
+	switch a {
+	case Continue:
+		return "continue"
+	case SkipChildren:
+		return "skip children"
+	case Stop:
+		return "stop"
+	default:
+		return "Action(" + strconv.Itoa(int(a)) + ")"
+	}
+}
+
+// Visitor is visited by Walk on every token of a tree.
+type Visitor[T internal.TokenTyper] interface {
+	// Enter is called on a token before its children are visited (pre-order).
+	//
+	// Parameters:
+	//   - tk: The token. Never nil.
+	//
+	// Returns:
+	//   - Action: What to do next.
+	Enter(tk *gr.Token[T]) Action
+
+	// Leave is called on a token after its children are visited (post-order). It
+	// is not called if Enter skipped the children of the token.
+	//
+	// Parameters:
+	//   - tk: The token. Never nil.
+	//
+	// Returns:
+	//   - Action: What to do next.
+	Leave(tk *gr.Token[T]) Action
+}
+
+// visitor_funcs is a visitor made of functions.
+type visitor_funcs[T internal.TokenTyper] struct {
+	// enter is the function of Enter. Nil if there is none.
+	enter func(tk *gr.Token[T]) Action
+
+	// leave is the function of Leave. Nil if there is none.
+	leave func(tk *gr.Token[T]) Action
+}
+
+// Enter implements the Visitor interface.
+func (v visitor_funcs[T]) Enter(tk *gr.Token[T]) Action {
+	if v.enter == nil {
+		return Continue
+	}
+
+	return v.enter(tk)
+}
+
+// Leave implements the Visitor interface.
+func (v visitor_funcs[T]) Leave(tk *gr.Token[T]) Action {
+	if v.leave == nil {
+		return Continue
+	}
+
+	return v.leave(tk)
+}
+
+// PreOrder returns a visitor that calls a function on every token before its
+// children.
+//
+// Parameters:
+//   - fn: The function. If nil, the visitor does nothing.
+//
+// Returns:
+//   - Visitor[T]: The visitor. Never returns nil.
+func PreOrder[T internal.TokenTyper](fn func(tk *gr.Token[T]) Action) Visitor[T] {
+	return visitor_funcs[T]{enter: fn}
+}
+
+// PostOrder returns a visitor that calls a function on every token after its
+// children.
+//
+// Parameters:
+//   - fn: The function. If nil, the visitor does nothing.
+//
+// Returns:
+//   - Visitor[T]: The visitor. Never returns nil.
+func PostOrder[T internal.TokenTyper](fn func(tk *gr.Token[T]) Action) Visitor[T] {
+	return visitor_funcs[T]{leave: fn}
+}
+
+// Walk visits every token of a tree, depth-first and from the first child to the
+// last one. The tree is walked with an explicit stack, so that deep trees do not
+// overflow the call stack.
+//
+// Parameters:
+//   - root: The root of the tree.
+//   - v: The visitor.
+//
+// Returns:
+//   - error: An error of type *errors.ErrInvalidParameter if root or v is nil.
+//
+// The tree must not be modified during the walk; see Rewrite.
+func Walk[T internal.TokenTyper](root *gr.Token[T], v Visitor[T]) error {
+	if root == nil {
+		return gcers.NewErrNilParameter("root")
+	} else if v == nil {
+		return gcers.NewErrNilParameter("v")
+	}
+
+	type pair struct {
+		tk   *gr.Token[T]
+		done bool
+	}
+
+	stack := []pair{{tk: root}}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if top.done {
+			if v.Leave(top.tk) == Stop {
+				return nil
+			}
+
+			continue
+		}
+
+		switch v.Enter(top.tk) {
+		case Stop:
+			return nil
+		case SkipChildren:
+			continue
+		}
+
+		stack = append(stack, pair{tk: top.tk, done: true})
+
+		for child := range top.tk.BackwardChild() {
+			stack = append(stack, pair{tk: child})
+		}
+	}
+
+	return nil
+}
+
+// RewriteFunc is a function that edits the tree of Rewrite through a cursor on
+// the token being visited.
+//
+// Parameters:
+//   - c: The cursor. Never nil.
+//
+// Returns:
+//   - Action: What to do next.
+//   - error: An error if the function failed. The rewrite is stopped.
+type RewriteFunc[T internal.TokenTyper] func(c *Cursor[T]) (Action, error)
+
+// Cursor is the position of Rewrite in the tree. It edits the tree around the
+// token being visited; the parent and sibling pointers are fixed up.
+type Cursor[T internal.TokenTyper] struct {
+	// rw is the rewrite the cursor belongs to.
+	rw *rewrite[T]
+
+	// tk is the token being visited.
+	tk *gr.Token[T]
+
+	// replaced are the tokens that took the place of the token. Only valid if
+	// removed is true.
+	replaced []*gr.Token[T]
+
+	// removed is true if the token was replaced or deleted.
+	removed bool
+}
+
+// Token returns the token being visited.
+//
+// Returns:
+//   - *gr.Token[T]: The token. It is no longer in the tree if it was replaced or
+//     deleted.
+func (c Cursor[T]) Token() *gr.Token[T] {
+	return c.tk
+}
+
+// Parent returns the parent of the token being visited.
+//
+// Returns:
+//   - *gr.Token[T]: The parent. Nil for the root.
+func (c Cursor[T]) Parent() *gr.Token[T] {
+	if c.removed {
+		return nil
+	}
+
+	return c.tk.Parent
+}
+
+// Replace replaces the token being visited with other tokens, which are detached
+// from their former place first. When the tokens are entered, the children of
+// the new tokens are visited instead of the ones of the token.
+//
+// Parameters:
+//   - tokens: The new tokens, in order. Nil tokens are ignored; no token deletes
+//     the token being visited.
+//
+// Returns:
+//   - error: An error if the token was already replaced or deleted, or if the
+//     root is replaced with more than one token.
+func (c *Cursor[T]) Replace(tokens ...*gr.Token[T]) error {
+	if c.removed {
+		return errors.New("the token was already replaced or deleted")
+	}
+
+	var news []*gr.Token[T]
+
+	for _, tk := range tokens {
+		if tk != nil {
+			news = append(news, tk)
+		}
+	}
+
+	if c.tk.Parent == nil {
+		if c.tk != c.rw.root {
+			return errors.New("the token has no parent")
+		}
+
+		switch len(news) {
+		case 0:
+			c.rw.root = nil
+		case 1:
+			news[0].Detach()
+			c.rw.root = news[0]
+		default:
+			return errors.New("the root can only be replaced with one token, got " + strconv.Itoa(len(news)))
+		}
+	} else {
+		err := c.tk.InsertBefore(news)
+		if err != nil {
+			return err
+		}
+
+		c.tk.Detach()
+	}
+
+	c.replaced = news
+	c.removed = true
+
+	return nil
+}
+
+// Delete deletes the token being visited and its children. It is the same as
+// Replace without tokens.
+//
+// Returns:
+//   - error: An error if the token was already replaced or deleted.
+func (c *Cursor[T]) Delete() error {
+	return c.Replace()
+}
+
+// InsertBefore inserts siblings right before the token being visited. They are
+// not visited.
+//
+// Parameters:
+//   - siblings: The siblings, in order. Nil siblings are ignored.
+//
+// Returns:
+//   - error: An error if the token was replaced or deleted, or if it is the
+//     root.
+func (c *Cursor[T]) InsertBefore(siblings ...*gr.Token[T]) error {
+	if c.removed {
+		return errors.New("the token was already replaced or deleted")
+	}
+
+	return c.tk.InsertBefore(siblings)
+}
+
+// InsertAfter inserts siblings right after the token being visited. They are not
+// visited.
+//
+// Parameters:
+//   - siblings: The siblings, in order. Nil siblings are ignored.
+//
+// Returns:
+//   - error: An error if the token was replaced or deleted, or if it is the
+//     root.
+func (c *Cursor[T]) InsertAfter(siblings ...*gr.Token[T]) error {
+	if c.removed {
+		return errors.New("the token was already replaced or deleted")
+	}
+
+	return c.tk.InsertAfter(siblings)
+}
+
+// rewrite is the state of a call to Rewrite.
+type rewrite[T internal.TokenTyper] struct {
+	// root is the root of the tree. Nil if it was deleted.
+	root *gr.Token[T]
+}
+
+// visit is a helper function that calls the function of the rewrite on a token.
+//
+// Parameters:
+//   - fn: The function.
+//   - tk: The token.
+//
+// Returns:
+//   - []*gr.Token[T]: The tokens that are in place of tk after the call.
+//   - Action: The action of the function.
+//   - error: The error of the function, wrapped in an *ErrIn.
+func (rw *rewrite[T]) visit(fn RewriteFunc[T], tk *gr.Token[T]) ([]*gr.Token[T], Action, error) {
+	c := &Cursor[T]{
+		rw: rw,
+		tk: tk,
+	}
+
+	act, err := fn(c)
+	if err != nil {
+		return nil, Stop, NewErrIn(tk.Type, err)
+	}
+
+	if c.removed {
+		return c.replaced, act, nil
+	}
+
+	return []*gr.Token[T]{tk}, act, nil
+}
+
+// Rewrite edits a tree in place by calling a function on every token, after its
+// children (post-order), so that the tree is rewritten bottom-up. The function
+// edits the tree through its cursor; the tokens it inserts or replaces with are
+// not visited.
+//
+// Parameters:
+//   - root: The root of the tree.
+//   - fn: The function.
+//
+// Returns:
+//   - *gr.Token[T]: The root of the tree, which changes if the root was replaced.
+//     Nil if it was deleted.
+//   - error: An error of type *errors.ErrInvalidParameter if root or fn is nil,
+//     or the first error of fn or of an edit, of type *ErrIn. The edits made so
+//     far are kept.
+func Rewrite[T internal.TokenTyper](root *gr.Token[T], fn RewriteFunc[T]) (*gr.Token[T], error) {
+	if root == nil {
+		return nil, gcers.NewErrNilParameter("root")
+	} else if fn == nil {
+		return root, gcers.NewErrNilParameter("fn")
+	}
+
+	rw := &rewrite[T]{root: root}
+
+	type pair struct {
+		tk   *gr.Token[T]
+		done bool
+	}
+
+	stack := []pair{{tk: root}}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if !top.done {
+			stack = append(stack, pair{tk: top.tk, done: true})
+
+			for child := range top.tk.BackwardChild() {
+				stack = append(stack, pair{tk: child})
+			}
+
+			continue
+		}
+
+		_, act, err := rw.visit(fn, top.tk)
+		if err != nil {
+			return rw.root, err
+		}
+
+		if act == Stop {
+			break
+		}
+	}
+
+	return rw.root, nil
+}
+
+// RewritePre is like Rewrite but calls the function on every token before its
+// children (pre-order), so that the tree is rewritten top-down. When the token is
+// replaced, the children of the new tokens are visited instead; SkipChildren
+// skips them.
+//
+// Parameters:
+//   - root: The root of the tree.
+//   - fn: The function.
+//
+// Returns:
+//   - *gr.Token[T]: The root of the tree. Nil if it was deleted.
+//   - error: An error, as for Rewrite.
+func RewritePre[T internal.TokenTyper](root *gr.Token[T], fn RewriteFunc[T]) (*gr.Token[T], error) {
+	if root == nil {
+		return nil, gcers.NewErrNilParameter("root")
+	} else if fn == nil {
+		return root, gcers.NewErrNilParameter("fn")
+	}
+
+	rw := &rewrite[T]{root: root}
+
+	stack := []*gr.Token[T]{root}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		tokens, act, err := rw.visit(fn, top)
+		if err != nil {
+			return rw.root, err
+		}
+
+		switch act {
+		case Stop:
+			return rw.root, nil
+		case SkipChildren:
+			continue
+		}
+
+		for i := len(tokens) - 1; i >= 0; i-- {
+			for child := range tokens[i].BackwardChild() {
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	return rw.root, nil
+}
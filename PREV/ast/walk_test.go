@@ -0,0 +1,228 @@
+package ast
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+)
+
+// walk_type is the token type of the test trees.
+type walk_type int
+
+const (
+	wt_word walk_type = iota
+	wn_list
+	wn_pair
+)
+
+// String implements the fmt.Stringer interface.
+func (t walk_type) String() string {
+	return [...]string{"Word", "List", "Pair"}[t]
+}
+
+// IsTerminal implements the internal.TokenTyper interface.
+func (t walk_type) IsTerminal() bool {
+	return t == wt_word
+}
+
+// new_walk_tree is a helper function that makes the tree of "(List a (Pair b c) d)".
+func new_walk_tree() *gr.Token[walk_type] {
+	word := func(data string) *gr.Token[walk_type] {
+		return gr.NewToken(wt_word, data, nil)
+	}
+
+	pair := gr.NewToken(wn_pair, "", nil)
+	pair.AddChildren([]*gr.Token[walk_type]{word("b"), word("c")})
+
+	root := gr.NewToken(wn_list, "", nil)
+	root.AddChildren([]*gr.Token[walk_type]{word("a"), pair, word("d")})
+
+	return root
+}
+
+// tree_string is a helper function that writes a tree as "(Type children...)"
+// and its leaves as their data.
+func tree_string(tk *gr.Token[walk_type]) string {
+	if tk == nil {
+		return "<nil>"
+	}
+
+	if tk.FirstChild == nil {
+		return tk.Data
+	}
+
+	elems := []string{tk.Type.String()}
+
+	for child := range tk.Child() {
+		if child.Parent != tk {
+			elems = append(elems, "!parent")
+		}
+
+		elems = append(elems, tree_string(child))
+	}
+
+	return "(" + strings.Join(elems, " ") + ")"
+}
+
+// name_of is a helper function that names a token by its data or, if it has
+// none, by its type.
+func name_of(tk *gr.Token[walk_type]) string {
+	if tk.Data != "" {
+		return tk.Data
+	}
+
+	return tk.Type.String()
+}
+
+// test_visitor is a visitor that writes "+name" on entering and "-name" on
+// leaving; the action of an event is the one of acts, Continue if none.
+type test_visitor struct {
+	events []string
+	acts   map[string]Action
+}
+
+func (v *test_visitor) Enter(tk *gr.Token[walk_type]) Action {
+	v.events = append(v.events, "+"+name_of(tk))
+
+	return v.acts["+"+name_of(tk)]
+}
+
+func (v *test_visitor) Leave(tk *gr.Token[walk_type]) Action {
+	v.events = append(v.events, "-"+name_of(tk))
+
+	return v.acts["-"+name_of(tk)]
+}
+
+func TestWalk(t *testing.T) {
+	tests := []struct {
+		name string
+		acts map[string]Action
+		want string
+	}{
+		{"all", nil, "+List +a -a +Pair +b -b +c -c -Pair +d -d -List"},
+		{"skip children", map[string]Action{"+Pair": SkipChildren}, "+List +a -a +Pair +d -d -List"},
+		{"stop on enter", map[string]Action{"+b": Stop}, "+List +a -a +Pair +b"},
+		{"stop on leave", map[string]Action{"-Pair": Stop}, "+List +a -a +Pair +b -b +c -c -Pair"},
+	}
+
+	for _, tt := range tests {
+		v := &test_visitor{acts: tt.acts}
+
+		err := Walk(new_walk_tree(), Visitor[walk_type](v))
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.name, err)
+		}
+
+		if got := strings.Join(v.events, " "); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+
+	err := Walk[walk_type](nil, PreOrder(func(tk *gr.Token[walk_type]) Action { return Continue }))
+	if err == nil {
+		t.Errorf("expected an error for a nil root, got nil")
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	var visited []string
+
+	// Flatten the pairs and delete the word "c"; the children of the pair are
+	// visited before it, so "c" is deleted before the pair is flattened.
+	root, err := Rewrite(new_walk_tree(), func(c *Cursor[walk_type]) (Action, error) {
+		tk := c.Token()
+
+		visited = append(visited, name_of(tk))
+
+		switch {
+		case tk.Data == "c":
+			return Continue, c.Delete()
+		case tk.Type == wn_pair:
+			return Continue, c.Replace(tk.Children()...)
+		}
+
+		return Continue, nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got, want := tree_string(root), "(List a b d)"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	if got, want := strings.Join(visited, " "), "a b c Pair d List"; got != want {
+		t.Errorf("expected the visits %s, got %s", want, got)
+	}
+}
+
+func TestRewriteRoot(t *testing.T) {
+	replacement := gr.NewToken(wt_word, "x", nil)
+
+	root, err := Rewrite(new_walk_tree(), func(c *Cursor[walk_type]) (Action, error) {
+		if c.Parent() != nil {
+			return Continue, nil
+		}
+
+		return Continue, c.Replace(replacement)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if root != replacement {
+		t.Errorf("expected the root to be replaced, got %s", tree_string(root))
+	}
+
+	_, err = Rewrite(new_walk_tree(), func(c *Cursor[walk_type]) (Action, error) {
+		if c.Parent() != nil {
+			return Continue, nil
+		}
+
+		return Continue, c.Replace(gr.NewToken(wt_word, "x", nil), gr.NewToken(wt_word, "y", nil))
+	})
+
+	var in *ErrIn[walk_type]
+
+	if !errors.As(err, &in) {
+		t.Errorf("expected an error of type *ErrIn for two roots, got %v", err)
+	}
+}
+
+func TestRewritePre(t *testing.T) {
+	var visited []string
+
+	// Replace the pair with a new one whose children are visited instead.
+	root, err := RewritePre(new_walk_tree(), func(c *Cursor[walk_type]) (Action, error) {
+		tk := c.Token()
+
+		visited = append(visited, name_of(tk))
+
+		if tk.Type != wn_pair {
+			return Continue, nil
+		}
+
+		pair := gr.NewToken(wn_pair, "", nil)
+		pair.AddChildren([]*gr.Token[walk_type]{gr.NewToken(wt_word, "x", nil)})
+
+		err := c.InsertAfter(gr.NewToken(wt_word, "y", nil))
+		if err != nil {
+			return Stop, err
+		}
+
+		return Continue, c.Replace(pair)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got, want := tree_string(root), "(List a (Pair x) y d)"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	if got, want := strings.Join(visited, " "), "List a Pair x d"; got != want {
+		t.Errorf("expected the visits %s, got %s", want, got)
+	}
+}
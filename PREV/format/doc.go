@@ -0,0 +1,228 @@
+// Package format turns syntax trees back into source text. The trees are first
+// converted, bottom-up, into documents that describe the text and where it may be
+// broken; the documents are then laid out by a layout engine within a maximum
+// line length. Code formatters can be built directly on the output of a parse by
+// giving a document rule to the nonterminals that need one.
+package format
+
+import (
+	"strings"
+)
+
+// Doc is a document: a text with the places where it may be broken into lines.
+// Documents are made with Text, Line, SoftLine, HardLine, Concat, Join, Nest and
+// Group.
+type Doc interface {
+	// is_doc marks the types of the documents of this package.
+	is_doc()
+}
+
+// text is a document made of a text without line breaks.
+type text string
+
+// is_doc implements the Doc interface.
+func (text) is_doc() {}
+
+// line_kind is the kind of a line break.
+type line_kind int
+
+const (
+	// line_space is a line break printed as a space when it is not broken.
+	line_space line_kind = iota
+
+	// line_soft is a line break printed as nothing when it is not broken.
+	line_soft
+
+	// line_hard is a line break that is always broken.
+	line_hard
+)
+
+// line is a document made of a line break.
+type line line_kind
+
+// is_doc implements the Doc interface.
+func (line) is_doc() {}
+
+// concat is a document made of documents, one after the other.
+type concat []Doc
+
+// is_doc implements the Doc interface.
+func (concat) is_doc() {}
+
+// nest is a document whose line breaks are followed by one more level of
+// indentation.
+type nest struct {
+	// doc is the nested document.
+	doc Doc
+}
+
+// is_doc implements the Doc interface.
+func (nest) is_doc() {}
+
+// group is a document whose line breaks are broken together, according to the
+// break policy of the layout.
+type group struct {
+	// doc is the grouped document.
+	doc Doc
+}
+
+// is_doc implements the Doc interface.
+func (group) is_doc() {}
+
+// Text returns a document made of a text. The newlines of the text are always
+// broken and followed by the indentation.
+//
+// Parameters:
+//   - s: The text.
+//
+// Returns:
+//   - Doc: The document. Empty if s is empty.
+func Text(s string) Doc {
+	if !strings.Contains(s, "\n") {
+		return text(s)
+	}
+
+	parts := strings.Split(s, "\n")
+
+	docs := make(concat, 0, 2*len(parts)-1)
+
+	for i, part := range parts {
+		if i > 0 {
+			docs = append(docs, line(line_hard))
+		}
+
+		if part != "" {
+			docs = append(docs, text(part))
+		}
+	}
+
+	return docs
+}
+
+// Line returns a line break that is printed as a space when its group is not
+// broken.
+//
+// Returns:
+//   - Doc: The line break.
+func Line() Doc {
+	return line(line_space)
+}
+
+// SoftLine returns a line break that is printed as nothing when its group is not
+// broken.
+//
+// Returns:
+//   - Doc: The line break.
+func SoftLine() Doc {
+	return line(line_soft)
+}
+
+// HardLine returns a line break that is always broken. A group that contains
+// one is always broken.
+//
+// Returns:
+//   - Doc: The line break.
+func HardLine() Doc {
+	return line(line_hard)
+}
+
+// Concat returns the documents one after the other. Nil documents are ignored.
+//
+// Parameters:
+//   - docs: The documents.
+//
+// Returns:
+//   - Doc: The document.
+func Concat(docs ...Doc) Doc {
+	result := make(concat, 0, len(docs))
+
+	for _, doc := range docs {
+		if doc != nil {
+			result = append(result, doc)
+		}
+	}
+
+	return result
+}
+
+// Join returns the documents separated by a separator. Nil and empty documents
+// are ignored, so that the separator is never doubled.
+//
+// Parameters:
+//   - sep: The separator. (e.g., Line() or Concat(Text(","), Line()))
+//   - docs: The documents.
+//
+// Returns:
+//   - Doc: The document.
+func Join(sep Doc, docs []Doc) Doc {
+	result := make(concat, 0, 2*len(docs))
+
+	for _, doc := range docs {
+		if IsEmpty(doc) {
+			continue
+		}
+
+		if len(result) > 0 && sep != nil {
+			result = append(result, sep)
+		}
+
+		result = append(result, doc)
+	}
+
+	return result
+}
+
+// Nest returns a document whose line breaks are followed by one more level of
+// indentation than the enclosing document.
+//
+// Parameters:
+//   - docs: The documents, one after the other.
+//
+// Returns:
+//   - Doc: The document.
+func Nest(docs ...Doc) Doc {
+	return nest{doc: Concat(docs...)}
+}
+
+// Group returns a document whose line breaks are laid out together: with the
+// default break policy, they are either all printed flat, if the group fits on
+// the rest of the line, or all broken.
+//
+// Parameters:
+//   - docs: The documents, one after the other.
+//
+// Returns:
+//   - Doc: The document.
+func Group(docs ...Doc) Doc {
+	return group{doc: Concat(docs...)}
+}
+
+// IsEmpty checks whether a document prints nothing.
+//
+// Parameters:
+//   - doc: The document.
+//
+// Returns:
+//   - bool: True if doc is nil or prints nothing, false otherwise.
+func IsEmpty(doc Doc) bool {
+	switch doc := doc.(type) {
+	case nil:
+		return true
+	case text:
+		return doc == ""
+	case concat:
+		for _, d := range doc {
+			if !IsEmpty(d) {
+				return false
+			}
+		}
+
+		return true
+	case nest:
+		return IsEmpty(doc.doc)
+	case group:
+		return IsEmpty(doc.doc)
+	default:
+		return false
+	}
+}
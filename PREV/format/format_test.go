@@ -0,0 +1,124 @@
+package format
+
+import (
+	"strconv"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+)
+
+// test_type is the token type of the test trees.
+type test_type int
+
+const (
+	tt_eof test_type = iota
+	tt_num
+	tt_comma
+	tt_lbrack
+	tt_rbrack
+	nt_list
+	nt_elems
+)
+
+// String implements the fmt.Stringer interface.
+func (t test_type) String() string {
+	return [...]string{"EOF", "NUM", "COMMA", "LBRACK", "RBRACK", "List", "Elems"}[t]
+}
+
+// IsTerminal implements the internal.TokenTyper interface.
+func (t test_type) IsTerminal() bool {
+	return t < nt_list
+}
+
+// new_test_list creates the tree of a list of the numbers from 1 to n.
+func new_test_list(n int) *gr.Token[test_type] {
+	elems := gr.NewToken[test_type](nt_elems, "", nil)
+
+	var children []*gr.Token[test_type]
+
+	for i := 1; i <= n; i++ {
+		if i > 1 {
+			children = append(children, gr.NewToken(tt_comma, ",", nil))
+		}
+
+		children = append(children, gr.NewToken(tt_num, strconv.Itoa(i*100), nil))
+	}
+
+	elems.AddChildren(children)
+
+	list := gr.NewToken[test_type](nt_list, "", nil)
+
+	list.AddChildren([]*gr.Token[test_type]{
+		gr.NewToken(tt_lbrack, "[", nil),
+		elems,
+		gr.NewToken(tt_rbrack, "]", nil),
+	})
+
+	return list
+}
+
+// new_test_formatter creates a formatter that lays out the lists like Go
+// composite literals.
+func new_test_formatter(layout Layout) *Formatter[test_type] {
+	f := NewFormatter[test_type](layout)
+
+	f.SetRule(nt_list, func(tk *gr.Token[test_type], children []Doc) Doc {
+		return Group(children[0], Nest(SoftLine(), children[1]), SoftLine(), children[2])
+	})
+
+	f.SetRule(nt_elems, func(tk *gr.Token[test_type], children []Doc) Doc {
+		var docs []Doc
+
+		for i, child := range children {
+			if i%2 == 1 {
+				docs = append(docs, child, Line())
+			} else {
+				docs = append(docs, child)
+			}
+		}
+
+		return Concat(docs...)
+	})
+
+	return f
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		n      int
+		layout Layout
+		want   string
+	}{
+		{"flat", 3, Layout{Indent: 2, Width: 20}, "[100, 200, 300]"},
+		{"consistent", 5, Layout{Indent: 2, Width: 20}, "[\n  100,\n  200,\n  300,\n  400,\n  500\n]"},
+		{"fill", 5, Layout{Indent: 2, Width: 20, Policy: BreakFill}, "[100, 200, 300, 400,\n  500]"},
+		{"always", 1, Layout{Width: 20, Policy: BreakAlways}, "[\n\t100\n]"},
+		{"never", 5, Layout{Indent: 2, Width: 20, Policy: BreakNever}, "[100, 200, 300, 400, 500]"},
+		{"unlimited", 5, Layout{Indent: 2}, "[100, 200, 300, 400, 500]"},
+	}
+
+	for _, tt := range tests {
+		got, err := new_test_formatter(tt.layout).Format(new_test_list(tt.n))
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.name, err)
+		}
+
+		if got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestFormatDefault(t *testing.T) {
+	got, err := NewFormatter[test_type](DefaultLayout).Format(new_test_list(2))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	const want = "[ 100 , 200 ]"
+
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
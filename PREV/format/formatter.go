@@ -0,0 +1,174 @@
+package format
+
+import (
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+	internal "github.com/PlayerR9/grammar/PREV/internal"
+	uttr "github.com/PlayerR9/tree/tree"
+)
+
+// RuleFunc is a function that makes the document of a token whose children were
+// already turned into documents.
+//
+// Parameters:
+//   - tk: The token. Assume tk is not nil.
+//   - children: The documents of the children of the token, in order. Nil for the
+//     leaves.
+//
+// Returns:
+//   - Doc: The document of the token. Nil if the token prints nothing.
+type RuleFunc[T internal.TokenTyper] func(tk *gr.Token[T], children []Doc) Doc
+
+// Formatter turns syntax trees into source text. The trees are walked bottom-up:
+// every token is turned into a document by the rule of its type, and the document
+// of the root is laid out.
+//
+// The tokens without a rule use the default ones: a leaf is its data and a
+// nonterminal is the group of the documents of its children, separated by line
+// breaks (see Line).
+type Formatter[T internal.TokenTyper] struct {
+	// rules are the rules, by token type.
+	rules map[T]RuleFunc[T]
+
+	// layout is the layout of the documents.
+	layout Layout
+}
+
+// NewFormatter creates a new formatter without rules.
+//
+// Parameters:
+//   - layout: The layout of the documents. (e.g., DefaultLayout)
+//
+// Returns:
+//   - *Formatter[T]: The new formatter. Never returns nil.
+func NewFormatter[T internal.TokenTyper](layout Layout) *Formatter[T] {
+	return &Formatter[T]{
+		rules:  make(map[T]RuleFunc[T]),
+		layout: layout,
+	}
+}
+
+// SetRule sets the rule of a token type, replacing the previous one, if any.
+//
+// Parameters:
+//   - type_: The type of the tokens.
+//   - fn: The rule. If nil, the tokens of the type use the default rule.
+func (f *Formatter[T]) SetRule(type_ T, fn RuleFunc[T]) {
+	if fn == nil {
+		delete(f.rules, type_)
+	} else {
+		f.rules[type_] = fn
+	}
+}
+
+// SetLayout sets the layout of the documents.
+//
+// Parameters:
+//   - layout: The layout.
+func (f *Formatter[T]) SetLayout(layout Layout) {
+	f.layout = layout
+}
+
+// Doc turns a syntax tree into a document. The tree is walked with an explicit
+// stack, so that deep trees do not overflow the call stack.
+//
+// Parameters:
+//   - root: The root of the tree.
+//
+// Returns:
+//   - Doc: The document.
+//   - error: An error of type *errors.ErrInvalidParameter if root is nil.
+func (f Formatter[T]) Doc(root *gr.Token[T]) (Doc, error) {
+	if root == nil {
+		return nil, gcers.NewErrNilParameter("root")
+	}
+
+	type pair struct {
+		tk   *gr.Token[T]
+		done bool
+	}
+
+	docs := make(map[*gr.Token[T]]Doc)
+
+	stack := []pair{{tk: root}}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if !top.done && !top.tk.IsLeaf() {
+			stack = append(stack, pair{tk: top.tk, done: true})
+
+			for child := range top.tk.BackwardChild() {
+				stack = append(stack, pair{tk: child})
+			}
+
+			continue
+		}
+
+		var children []Doc
+
+		for child := range top.tk.Child() {
+			children = append(children, docs[child])
+			delete(docs, child)
+		}
+
+		docs[top.tk] = f.doc_of(top.tk, children)
+	}
+
+	return docs[root], nil
+}
+
+// doc_of is a helper function that applies the rule of a token.
+//
+// Parameters:
+//   - tk: The token.
+//   - children: The documents of the children of the token.
+//
+// Returns:
+//   - Doc: The document of the token.
+func (f Formatter[T]) doc_of(tk *gr.Token[T], children []Doc) Doc {
+	fn, ok := f.rules[tk.Type]
+	if ok {
+		return fn(tk, children)
+	}
+
+	if tk.IsLeaf() {
+		return Text(tk.Data)
+	}
+
+	return Group(Join(Line(), children))
+}
+
+// Format turns a syntax tree into source text.
+//
+// Parameters:
+//   - root: The root of the tree.
+//
+// Returns:
+//   - string: The text, laid out by the layout of the formatter.
+//   - error: An error of type *errors.ErrInvalidParameter if root is nil.
+func (f Formatter[T]) Format(root *gr.Token[T]) (string, error) {
+	doc, err := f.Doc(root)
+	if err != nil {
+		return "", err
+	}
+
+	return f.layout.Render(doc), nil
+}
+
+// FormatTree is like Format but for the trees made by the parsers.
+//
+// Parameters:
+//   - tree: The tree.
+//
+// Returns:
+//   - string: The text.
+//   - error: An error of type *errors.ErrInvalidParameter if tree is nil.
+func (f Formatter[T]) FormatTree(tree *uttr.Tree[*gr.Token[T]]) (string, error) {
+	if tree == nil {
+		return "", gcers.NewErrNilParameter("tree")
+	}
+
+	return f.Format(tree.Root())
+}
@@ -0,0 +1,314 @@
+package format
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// BreakPolicy is the way the line breaks of a group are laid out.
+type BreakPolicy int
+
+const (
+	// BreakConsistent prints the line breaks of a group flat if the group fits on
+	// the rest of the line and breaks all of them otherwise.
+	BreakConsistent BreakPolicy = iota
+
+	// BreakFill prints the line breaks of a group flat if the group fits on the
+	// rest of the line; otherwise, it only breaks the ones that are followed by a
+	// text that does not fit, so that lines are filled as much as possible.
+	BreakFill
+
+	// BreakAlways breaks every line break of every group.
+	BreakAlways
+
+	// BreakNever prints the line breaks of every group flat, unless the group
+	// holds a hard line break.
+	BreakNever
+)
+
+// String implements the fmt.Stringer interface.
+func (p BreakPolicy) String() string {
+	switch p {
+	case BreakConsistent:
+		return "consistent"
+	case BreakFill:
+		return "fill"
+	case BreakAlways:
+		return "always"
+	case BreakNever:
+		return "never"
+	default:
+		return "BreakPolicy(" + strconv.Itoa(int(p)) + ")"
+	}
+}
+
+// Layout is the configuration of the layout engine.
+type Layout struct {
+	// Indent is the number of spaces of a level of indentation. If non-positive,
+	// a level of indentation is a tab.
+	Indent int
+
+	// Width is the maximum line length, in characters. A tab counts as one
+	// character. If non-positive, there is no maximum: groups are only broken when
+	// they hold a hard line break.
+	Width int
+
+	// Policy is the break policy of the groups.
+	Policy BreakPolicy
+}
+
+// DefaultLayout is the layout of four spaces of indentation, lines of at most 80
+// characters and consistent groups.
+var DefaultLayout = Layout{
+	Indent: 4,
+	Width:  80,
+	Policy: BreakConsistent,
+}
+
+// mode is the way the line breaks of a document are printed.
+type mode int
+
+const (
+	// mode_flat prints the line breaks flat.
+	mode_flat mode = iota
+
+	// mode_break breaks the line breaks.
+	mode_break
+
+	// mode_fill breaks the line breaks that are followed by a text that does not
+	// fit.
+	mode_fill
+)
+
+// command is a document to print, with its indentation and its mode.
+type command struct {
+	// level is the number of levels of indentation.
+	level int
+
+	// mode is the mode of the document.
+	mode mode
+
+	// doc is the document.
+	doc Doc
+}
+
+// Render lays out a document.
+//
+// Parameters:
+//   - doc: The document.
+//
+// Returns:
+//   - string: The text. Lines have no trailing whitespace.
+func (l Layout) Render(doc Doc) string {
+	var builder strings.Builder
+
+	// pending is the whitespace that is only written if a text follows it on the
+	// same line, so that lines have no trailing whitespace.
+	var pending string
+
+	col := 0
+
+	stack := []command{{level: 0, mode: mode_break, doc: doc}}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		switch d := top.doc.(type) {
+		case text:
+			if d == "" {
+				continue
+			}
+
+			builder.WriteString(pending)
+			builder.WriteString(string(d))
+
+			pending = ""
+			col += utf8.RuneCountInString(string(d))
+		case concat:
+			for i := len(d) - 1; i >= 0; i-- {
+				stack = append(stack, command{level: top.level, mode: top.mode, doc: d[i]})
+			}
+		case nest:
+			stack = append(stack, command{level: top.level + 1, mode: top.mode, doc: d.doc})
+		case group:
+			stack = append(stack, command{level: top.level, mode: l.mode_of(top, stack, col), doc: d.doc})
+		case line:
+			var broken bool
+
+			switch {
+			case line_kind(d) == line_hard:
+				broken = true
+			case top.mode == mode_break:
+				broken = true
+			case top.mode == mode_fill:
+				flat := command{level: top.level, mode: mode_flat, doc: d}
+				broken = !l.fits(flat, stack, col)
+			}
+
+			if !broken {
+				if line_kind(d) == line_space {
+					pending += " "
+					col++
+				}
+
+				continue
+			}
+
+			builder.WriteByte('\n')
+
+			pending = l.indentation(top.level)
+			col = len(pending)
+		}
+	}
+
+	return builder.String()
+}
+
+// mode_of is a helper function that returns the mode of the document of a group,
+// according to the break policy.
+//
+// Parameters:
+//   - top: The command of the group.
+//   - rest: The commands that follow the group.
+//   - col: The current column.
+//
+// Returns:
+//   - mode: The mode.
+func (l Layout) mode_of(top command, rest []command, col int) mode {
+	if top.mode == mode_flat {
+		return mode_flat
+	}
+
+	doc := top.doc.(group).doc
+
+	switch l.Policy {
+	case BreakAlways:
+		return mode_break
+	case BreakNever:
+		if has_hard_line(doc) {
+			return mode_break
+		}
+
+		return mode_flat
+	}
+
+	if l.fits(command{level: top.level, mode: mode_flat, doc: doc}, rest, col) {
+		return mode_flat
+	}
+
+	if l.Policy == BreakFill {
+		return mode_fill
+	}
+
+	return mode_break
+}
+
+// fits is a helper function that checks whether a document fits on the rest of
+// the line. The documents that follow it are measured up to their first line
+// break.
+//
+// Parameters:
+//   - next: The command of the document.
+//   - rest: The commands that follow the document.
+//   - col: The current column.
+//
+// Returns:
+//   - bool: True if the document fits, false otherwise. A document printed flat
+//     that holds a hard line break never fits.
+func (l Layout) fits(next command, rest []command, col int) bool {
+	width := l.Width - col
+
+	stack := []command{next}
+	rest_idx := len(rest)
+
+	for {
+		if l.Width > 0 && width < 0 {
+			return false
+		}
+
+		if len(stack) == 0 {
+			if rest_idx == 0 {
+				return true
+			}
+
+			rest_idx--
+			stack = append(stack, rest[rest_idx])
+
+			continue
+		}
+
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		switch d := top.doc.(type) {
+		case text:
+			width -= utf8.RuneCountInString(string(d))
+		case concat:
+			for i := len(d) - 1; i >= 0; i-- {
+				stack = append(stack, command{level: top.level, mode: top.mode, doc: d[i]})
+			}
+		case nest:
+			stack = append(stack, command{level: top.level + 1, mode: top.mode, doc: d.doc})
+		case group:
+			stack = append(stack, command{level: top.level, mode: top.mode, doc: d.doc})
+		case line:
+			if top.mode != mode_flat {
+				return true
+			}
+
+			if line_kind(d) == line_hard {
+				return false
+			}
+
+			if line_kind(d) == line_space {
+				width--
+			}
+		}
+	}
+}
+
+// indentation is a helper function that returns the indentation of a level.
+//
+// Parameters:
+//   - level: The number of levels of indentation.
+//
+// Returns:
+//   - string: The indentation.
+func (l Layout) indentation(level int) string {
+	if l.Indent <= 0 {
+		return strings.Repeat("\t", level)
+	}
+
+	return strings.Repeat(" ", level*l.Indent)
+}
+
+// has_hard_line is a helper function that checks whether a document holds a hard
+// line break.
+//
+// Parameters:
+//   - doc: The document.
+//
+// Returns:
+//   - bool: True if doc holds a hard line break, false otherwise.
+func has_hard_line(doc Doc) bool {
+	switch d := doc.(type) {
+	case line:
+		return line_kind(d) == line_hard
+	case concat:
+		for _, sub := range d {
+			if has_hard_line(sub) {
+				return true
+			}
+		}
+
+		return false
+	case nest:
+		return has_hard_line(d.doc)
+	case group:
+		return has_hard_line(d.doc)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,111 @@
+package grammar
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	internal "github.com/PlayerR9/grammar/PREV/internal"
+)
+
+// ChanTokenReader is a token reader backed by a channel. A producer (e.g., a
+// lexer running in its own goroutine) sends the tokens as soon as they are made
+// and a consumer (e.g., a parser) reads them concurrently, so that the parse of a
+// large input or of a network stream starts before the input is complete.
+//
+// Send and Close are meant to be called by one goroutine and ReadToken by
+// another one.
+type ChanTokenReader[T internal.TokenTyper] struct {
+	// ctx is the context of the pipeline.
+	ctx context.Context
+
+	// tokens is the channel of the tokens.
+	tokens chan *Token[T]
+
+	// err is the error the producer closed the reader with. Only read once tokens
+	// is closed.
+	err error
+
+	// once guards the closing of tokens.
+	once sync.Once
+}
+
+// NewChanTokenReader creates a new token reader backed by a channel.
+//
+// Parameters:
+//   - ctx: The context of the pipeline. When it is done, Send and ReadToken stop
+//     waiting and return its error. If nil, context.Background() is used.
+//   - size: The number of tokens the producer can send ahead of the consumer. If
+//     non-positive, every Send waits for the matching ReadToken.
+//
+// Returns:
+//   - *ChanTokenReader[T]: The new token reader. Never returns nil.
+func NewChanTokenReader[T internal.TokenTyper](ctx context.Context, size int) *ChanTokenReader[T] {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return &ChanTokenReader[T]{
+		ctx:    ctx,
+		tokens: make(chan *Token[T], max(size, 0)),
+	}
+}
+
+// Send sends a token to the consumer, waiting for room in the channel.
+//
+// Parameters:
+//   - tk: The token to send. Nil tokens are ignored.
+//
+// Returns:
+//   - error: The error of the context if it is done before the token is sent.
+//
+// Send must not be called after Close.
+func (r *ChanTokenReader[T]) Send(tk *Token[T]) error {
+	if tk == nil {
+		return nil
+	}
+
+	select {
+	case r.tokens <- tk:
+		return nil
+	case <-r.ctx.Done():
+		return r.ctx.Err()
+	}
+}
+
+// Close tells the consumer that no more tokens will be sent. Once the tokens sent
+// so far are read, ReadToken returns the given error. Only the first call has an
+// effect.
+//
+// Parameters:
+//   - err: The error that ended the production (e.g., a lexing error). If nil,
+//     the token stream ended normally and ReadToken returns io.EOF.
+func (r *ChanTokenReader[T]) Close(err error) {
+	r.once.Do(func() {
+		if err == nil {
+			err = io.EOF
+		}
+
+		r.err = err
+
+		close(r.tokens)
+	})
+}
+
+// ReadToken implements the TokenReader interface.
+//
+// It waits for the next token. Once the reader is closed and every token is read,
+// it returns io.EOF or the error the reader was closed with. If the context is
+// done first, it returns the error of the context.
+func (r *ChanTokenReader[T]) ReadToken() (*Token[T], error) {
+	select {
+	case tk, ok := <-r.tokens:
+		if !ok {
+			return nil, r.err
+		}
+
+		return tk, nil
+	case <-r.ctx.Done():
+		return nil, r.ctx.Err()
+	}
+}
@@ -4,8 +4,8 @@ import (
 	"strconv"
 	"strings"
 
-	gcstr "github.com/PlayerR9/go-commons/strings"
 	internal "github.com/PlayerR9/grammar/PREV/internal"
+	"github.com/PlayerR9/grammar/internal/text"
 )
 
 // ErrUnexpectedToken is the error for unexpected tokens.
@@ -31,12 +31,8 @@ func (e ErrUnexpectedToken[T]) Error() string {
 	if len(e.Expecteds) == 0 {
 		builder.WriteString("nothing")
 	} else {
-		values := make([]string, 0, len(e.Expecteds))
-		for _, expected := range e.Expecteds {
-			values = append(values, expected.String())
-		}
-		gcstr.QuoteStrings(values)
-		builder.WriteString(gcstr.EitherOrString(values))
+		values := text.Quoted(text.Strings(e.Expecteds))
+		builder.WriteString(text.JoinList(values, text.EitherOr))
 	}
 
 	if e.Prev == nil {
@@ -1,6 +1,7 @@
 package grammar
 
 import (
+	"errors"
 	"iter"
 	"strconv"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	gcers "github.com/PlayerR9/go-commons/errors"
 	gcslc "github.com/PlayerR9/go-commons/slices"
 	internal "github.com/PlayerR9/grammar/PREV/internal"
+	"github.com/PlayerR9/grammar/internal/chain"
 )
 
 // Token is a token in the token stream.
@@ -37,8 +39,37 @@ type Token[T internal.TokenTyper] struct {
 	Lookahead *Token[T]
 }
 
+// Cleanup unlinks the token from its parent, its siblings, its children and its
+// lookahead. It does not clean up the children, so that the caller can clean up a
+// whole tree with an explicit stack instead of recursion.
+//
+// Returns:
+//   - []*Token[T]: The children of the token, that still need to be cleaned up.
 func (t *Token[T]) Cleanup() []*Token[T] {
-	panic("implement me")
+	if t.PrevSibling != nil {
+		t.PrevSibling.NextSibling = t.NextSibling
+	}
+
+	if t.NextSibling != nil {
+		t.NextSibling.PrevSibling = t.PrevSibling
+	}
+
+	var children []*Token[T]
+
+	for c := t.FirstChild; c != nil; c = c.NextSibling {
+		c.Parent = nil
+
+		children = append(children, c)
+	}
+
+	t.Parent = nil
+	t.FirstChild = nil
+	t.NextSibling = nil
+	t.LastChild = nil
+	t.PrevSibling = nil
+	t.Lookahead = nil
+
+	return children
 }
 
 func (t *Token[T]) IsSingleton() bool {
@@ -207,6 +238,103 @@ func (tk *Token[T]) IsLeaf() bool {
 	return tk.FirstChild == nil
 }
 
+// Detach unlinks the token from its parent and its siblings, so that it can be
+// moved elsewhere. Its children are kept.
+func (tk *Token[T]) Detach() {
+	if tk.PrevSibling != nil {
+		tk.PrevSibling.NextSibling = tk.NextSibling
+	} else if tk.Parent != nil {
+		tk.Parent.FirstChild = tk.NextSibling
+	}
+
+	if tk.NextSibling != nil {
+		tk.NextSibling.PrevSibling = tk.PrevSibling
+	} else if tk.Parent != nil {
+		tk.Parent.LastChild = tk.PrevSibling
+	}
+
+	tk.Parent = nil
+	tk.PrevSibling = nil
+	tk.NextSibling = nil
+}
+
+// InsertBefore inserts siblings right before the token. The siblings are detached
+// from their former place first.
+//
+// Parameters:
+//   - siblings: The siblings to insert, in order. Nil siblings and the token
+//     itself are ignored.
+//
+// Returns:
+//   - error: An error if the token has no parent.
+func (tk *Token[T]) InsertBefore(siblings []*Token[T]) error {
+	if tk.Parent == nil {
+		return errors.New("the token has no parent")
+	}
+
+	for _, sibling := range siblings {
+		if sibling == nil || sibling == tk {
+			continue
+		}
+
+		sibling.Detach()
+
+		sibling.Parent = tk.Parent
+		sibling.PrevSibling = tk.PrevSibling
+		sibling.NextSibling = tk
+
+		if tk.PrevSibling != nil {
+			tk.PrevSibling.NextSibling = sibling
+		} else {
+			tk.Parent.FirstChild = sibling
+		}
+
+		tk.PrevSibling = sibling
+	}
+
+	return nil
+}
+
+// InsertAfter inserts siblings right after the token. The siblings are detached
+// from their former place first.
+//
+// Parameters:
+//   - siblings: The siblings to insert, in order. Nil siblings and the token
+//     itself are ignored.
+//
+// Returns:
+//   - error: An error if the token has no parent.
+func (tk *Token[T]) InsertAfter(siblings []*Token[T]) error {
+	if tk.Parent == nil {
+		return errors.New("the token has no parent")
+	}
+
+	prev := tk
+
+	for _, sibling := range siblings {
+		if sibling == nil || sibling == tk {
+			continue
+		}
+
+		sibling.Detach()
+
+		sibling.Parent = prev.Parent
+		sibling.PrevSibling = prev
+		sibling.NextSibling = prev.NextSibling
+
+		if prev.NextSibling != nil {
+			prev.NextSibling.PrevSibling = sibling
+		} else {
+			prev.Parent.LastChild = sibling
+		}
+
+		prev.NextSibling = sibling
+		prev = sibling
+	}
+
+	return nil
+}
+
 // CheckTokenAt checks if the token at the given index is of the given type.
 //
 // Parameters:
@@ -279,3 +407,28 @@ func CheckToken[T internal.TokenTyper](token, prev *Token[T], type_ T) error {
 
 	return nil
 }
+
+// LinkLookaheads links each token to the token that follows it in the slice, and
+// unlinks the last one. Nil tokens are skipped.
+//
+// Parameters:
+//   - tokens: The tokens to link, in the order of the token stream.
+func LinkLookaheads[T internal.TokenTyper](tokens []*Token[T]) {
+	chain.Link(tokens, func(tk *Token[T]) **Token[T] {
+		return &tk.Lookahead
+	})
+}
+
+// LookaheadAt returns the token k links ahead of the token in its lookahead chain.
+//
+// Parameters:
+//   - k: The number of links to follow. 0 is the token itself.
+//
+// Returns:
+//   - *Token[T]: The token k links ahead.
+//   - bool: False if the chain is shorter than k or k is negative, true otherwise.
+func (tk *Token[T]) LookaheadAt(k int) (*Token[T], bool) {
+	return chain.At(tk, k, func(tk *Token[T]) **Token[T] {
+		return &tk.Lookahead
+	})
+}
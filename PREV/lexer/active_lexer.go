@@ -125,9 +125,7 @@ func (l ActiveLexer[T]) Tokens() []*gr.Token[T] {
 	eof := gr.NewToken(T(0), "", nil)
 	tokens = append(tokens, eof)
 
-	for i := 0; i < len(tokens)-1; i++ {
-		tokens[i].Lookahead = tokens[i+1]
-	}
+	gr.LinkLookaheads(tokens)
 
 	return tokens
 }
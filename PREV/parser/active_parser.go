@@ -1,13 +1,15 @@
 package parser
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"slices"
 
 	gr "github.com/PlayerR9/grammar/PREV/grammar"
 	internal "github.com/PlayerR9/grammar/PREV/internal"
-	"github.com/PlayerR9/listlike/stack"
+	grm "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/grammar/internal/text"
 	"github.com/PlayerR9/tree/tree"
 )
 
@@ -16,11 +18,20 @@ type ActiveParser[T internal.TokenTyper] struct {
 	// global contains the shared information between active parsers.
 	global *Parser[T]
 
-	// reader is the token reader.
-	reader gr.TokenReader[T]
+	// input is the input of the parse session. It is shared by the forks of the
+	// active parser. Nil once the active parser gave up.
+	input *token_feed[T]
 
-	// token_stack is the token token_stack.
-	token_stack *stack.RefusableStack[*gr.Token[T]]
+	// next is the position in the input of the next token to shift.
+	next int
+
+	// top is the top of the stack. Nil if the stack is empty.
+	top *stack_cell[T]
+
+	// cursor is the top of the stack once the tokens popped since the last call to
+	// accept are removed. The decision pops tokens to look at them and then refuses
+	// the pops.
+	cursor *stack_cell[T]
 
 	// err is the reason to why the active parser has failed. Nil if it has succeded.
 	err error
@@ -30,8 +41,21 @@ type ActiveParser[T internal.TokenTyper] struct {
 
 	// accept_found is true if an accept was found. False otherwise.
 	accept_found bool
+
+	// shifted is the number of tokens that were shifted.
+	shifted int
+
+	// forest are the trees set aside by the recoveries from parse errors.
+	forest []*parse_node[T]
+
+	// errs are the parse errors the active parser recovered from.
+	errs []*ErrParsing
 }
 
+// MaxFrames is the maximum number of nonterminals that are attached as context
+// frames to a parse error.
+const MaxFrames int = 3
+
 // HasError checks if the error is not nil.
 //
 // Returns:
@@ -56,29 +80,28 @@ func (ap *ActiveParser[T]) WalkOne(item *Item[T]) bool {
 
 	switch act {
 	case internal.ActShiftType:
-		err := ap.shift()
-
+		err := ap.shift(item)
 		if err != nil {
 			ap.err = fmt.Errorf("error shifting: %w", err)
 		}
 	case internal.ActReduceType:
 		err := ap.reduce(item.rule)
 		if err != nil {
-			ap.token_stack.Refuse()
+			ap.refuse()
 
 			ap.err = fmt.Errorf("error reducing: %w", err)
 		}
 	case internal.ActAcceptType:
 		err := ap.reduce(item.rule)
 		if err == nil {
-			if ap.token_stack.Size() == 1 {
+			if size_of(ap.cursor) == 1 {
 				return true
 			}
 
 			ap.err = errors.New("not a valid parse")
 			ap.possible_cause = nil
 		} else {
-			ap.token_stack.Refuse()
+			ap.refuse()
 
 			ap.err = fmt.Errorf("error reducing: %w", err)
 		}
@@ -89,6 +112,24 @@ func (ap *ActiveParser[T]) WalkOne(item *Item[T]) bool {
 	return false
 }
 
+// call_decision calls the decision function of the parser, or the decision of
+// its rule set if it has none. Panics of the decision function are converted
+// into errors of type *grammar.ErrPanic so that a buggy function cannot crash the
+// caller.
+//
+// Returns:
+//   - []*Item[T]: The items returned by the decision.
+//   - error: The error returned by the decision, if any.
+func (ap *ActiveParser[T]) call_decision() (items []*Item[T], err error) {
+	if ap.global.decision_fn == nil {
+		return ap.global.rule_set.Decision(ap)
+	}
+
+	defer grm.Recover(&err)
+
+	return ap.global.decision_fn(ap)
+}
+
 // exec executes the active parser.
 //
 // Parameters:
@@ -97,8 +138,8 @@ func (ap *ActiveParser[T]) WalkOne(item *Item[T]) bool {
 // Returns:
 //   - []*Item[T]: The possible paths.
 func (ap *ActiveParser[T]) NextEvents() []*Item[T] {
-	items, decision_err := ap.global.rule_set.Decision(ap)
-	ap.token_stack.Refuse()
+	items, decision_err := ap.call_decision()
+	ap.refuse()
 
 	if len(items) == 0 {
 		if decision_err == nil {
@@ -118,13 +159,250 @@ func (ap *ActiveParser[T]) NextEvents() []*Item[T] {
 	return items
 }
 
+// Exec is like ExecCtx but cannot be cancelled.
+//
+// Returns:
+//   - error: The error of the active parser. Nil if it accepted.
+func (ap *ActiveParser[T]) Exec() error {
+	return ap.ExecCtx(context.Background())
+}
+
+// ExecCtx runs the active parser to the end of its branch: it takes the first
+// item at every decision and does not recover from parse errors. Use
+// Parser.ParseCtx to explore every branch instead.
+//
+// The active parser stops as soon as the context is done and its error is then of
+// type *grm.ErrCancelled; its forest holds what was parsed so far. An active
+// parser that was cancelled this way, by ExecCtx or by Parser.ParseCtx, goes on
+// from where it stopped when ExecCtx is called again.
+//
+// Parameters:
+//   - ctx: The context.
+//
+// Returns:
+//   - error: The error of the active parser. Nil if it accepted.
+func (ap *ActiveParser[T]) ExecCtx(ctx context.Context) error {
+	var cancelled *grm.ErrCancelled
+
+	if errors.As(ap.err, &cancelled) {
+		ap.err = nil
+	}
+
+	var steps int
+
+	for ap.err == nil {
+		err := ap.global.usage.exceeded(ap.global.limits)
+		if err == nil {
+			err = ap.overflow(ap.global.limits.MaxDepth)
+		}
+
+		if err == nil && ctx.Err() != nil {
+			err = grm.NewErrCancelled(ctx.Err(), steps)
+		}
+
+		if err != nil {
+			ap.err = err
+			ap.possible_cause = nil
+
+			break
+		}
+
+		steps++
+
+		nexts := ap.NextEvents()
+		if len(nexts) == 0 {
+			break
+		}
+
+		if ap.WalkOne(nexts[0]) {
+			return nil
+		}
+	}
+
+	return ap.Error()
+}
+
+// frames is a helper function that finds the nonterminals that are being parsed
+// on the stack. The shift item that pushed a cell tells the rule that the cell
+// below is part of and its position in it; the frame of that rule starts as many
+// cells further down, right above the cell whose own shift item tells the
+// enclosing rule.
+//
+// Returns:
+//   - []frame[T]: The frames, from the innermost to the outermost one.
+func (ap ActiveParser[T]) frames() []frame[T] {
+	var frames []frame[T]
+
+	for c := ap.cursor; c != nil && c.via != nil; {
+		start := max(size_of(c.below)-c.via.pos-1, 0)
+
+		frames = append(frames, frame[T]{
+			item:  c.via,
+			start: start,
+		})
+
+		for c = c.below; c != nil && c.size > start+1; c = c.below {
+		}
+	}
+
+	return frames
+}
+
+// top_frames is a helper function that finds the rules the token on top of the
+// stack may be part of; that is, the items of its type whose right-hand side
+// matches the stack below it. When the decision failed on that token, none of
+// them is in the frames yet.
+//
+// Returns:
+//   - []frame[T]: The frames, in the order of the items of the rule set.
+func (ap ActiveParser[T]) top_frames() []frame[T] {
+	rs := ap.global.rule_set
+	if rs == nil || ap.cursor == nil {
+		return nil
+	}
+
+	var frames []frame[T]
+
+	for _, item := range rs.items[ap.cursor.node.tk.Type] {
+		c := ap.cursor
+
+		for i := item.pos - 1; i >= 0 && c != nil; i-- {
+			c = c.below
+
+			rhs, _ := item.RhsAt(i)
+
+			if c == nil || c.node.tk.Type != rhs {
+				c = nil
+			}
+		}
+
+		if c != nil {
+			frames = append(frames, frame[T]{
+				item:  item,
+				start: size_of(c) - 1,
+			})
+		}
+	}
+
+	return frames
+}
+
+// Frames returns the nonterminals that are being parsed.
+//
+// Parameters:
+//   - n: The maximum number of nonterminals to return. If non-positive, all of
+//     them are returned.
+//
+// Returns:
+//   - []T: The nonterminals, from the innermost to the outermost one.
+func (ap ActiveParser[T]) Frames(n int) []T {
+	var frames []T
+
+	for _, f := range ap.frames() {
+		if n > 0 && len(frames) == n {
+			break
+		}
+
+		frames = append(frames, f.item.Lhs())
+	}
+
+	return frames
+}
+
+// overflow is a helper function that checks the depth of the stack against the
+// given limit.
+//
+// Parameters:
+//   - limit: The maximum depth. If non-positive, the depth is not limited.
+//
+// Returns:
+//   - error: An error of type *ErrStackOverflow[T] naming the rule with the most
+//     frames if the stack is deeper than limit. Nil otherwise.
+func (ap ActiveParser[T]) overflow(limit int) error {
+	depth := size_of(ap.top)
+
+	if limit <= 0 || depth <= limit {
+		return nil
+	}
+
+	counts := make(map[*Rule[T]]int)
+
+	var (
+		rule  *Rule[T]
+		count int
+	)
+
+	for _, f := range ap.frames() {
+		counts[f.item.rule]++
+
+		if counts[f.item.rule] > count {
+			rule, count = f.item.rule, counts[f.item.rule]
+		}
+	}
+
+	return NewErrStackOverflow(limit, depth, rule, count)
+}
+
 // Pop pops a token from the stack.
 //
 // Returns:
 //   - *Token[T]: The popped token.
 //   - bool: True if the token was popped, false otherwise.
 func (ap *ActiveParser[T]) Pop() (*gr.Token[T], bool) {
-	return ap.token_stack.Pop()
+	if ap.cursor == nil {
+		return nil, false
+	}
+
+	tk := ap.cursor.node.tk
+	ap.cursor = ap.cursor.below
+
+	return tk, true
+}
+
+// push is a helper function that pushes a node onto the stack. There must be no
+// pending pops.
+//
+// Parameters:
+//   - node: The node to push.
+//   - via: The shift item that pushed the node. Nil if there is none.
+func (ap *ActiveParser[T]) push(node *parse_node[T], via *Item[T]) {
+	ap.top = &stack_cell[T]{
+		node:  node,
+		below: ap.top,
+		size:  size_of(ap.top) + 1,
+		via:   via,
+	}
+
+	ap.cursor = ap.top
+
+	ap.global.stats.PeakDepth = max(ap.global.stats.PeakDepth, ap.top.size)
+}
+
+// popped is a helper function that returns the nodes popped since the last call
+// to accept.
+//
+// Returns:
+//   - []*parse_node[T]: The nodes, from the bottom to the top of the stack.
+func (ap ActiveParser[T]) popped() []*parse_node[T] {
+	var nodes []*parse_node[T]
+
+	for c := ap.top; c != ap.cursor; c = c.below {
+		nodes = append(nodes, c.node)
+	}
+
+	slices.Reverse(nodes)
+
+	return nodes
+}
+
+// accept is a helper function that removes the popped nodes from the stack.
+func (ap *ActiveParser[T]) accept() {
+	ap.top = ap.cursor
+}
+
+// refuse is a helper function that puts the popped nodes back onto the stack.
+func (ap *ActiveParser[T]) refuse() {
+	ap.cursor = ap.top
 }
 
 /* // exec_witn_fn executes the active parser with a custom decision function.
@@ -214,7 +492,7 @@ func (ap *ActiveParser[T]) reduce(rule *Rule[T]) error {
 	var prev *T
 
 	for rhs := range rule.Backwards() {
-		top, ok := ap.token_stack.Pop()
+		top, ok := ap.Pop()
 		if !ok {
 			return gr.NewErrUnexpectedToken(prev, nil, rhs)
 		} else if top.Type != rhs {
@@ -224,54 +502,113 @@ func (ap *ActiveParser[T]) reduce(rule *Rule[T]) error {
 		prev = &top.Type
 	}
 
-	popped := ap.token_stack.Popped()
+	popped := ap.popped()
 
-	ap.token_stack.Accept()
+	// The node takes the place of its first child, so it is part of the same rule.
+	first := ap.top
+	for first.below != ap.cursor {
+		first = first.below
+	}
 
-	tk := gr.NewToken(rule.Lhs(), "", popped[len(popped)-1].Lookahead)
-	tk.AddChildren(popped)
+	ap.accept()
 
-	ap.token_stack.Push(tk)
+	ap.push(&parse_node[T]{
+		tk:       gr.NewToken(rule.Lhs(), "", popped[len(popped)-1].tk.Lookahead),
+		children: popped,
+	}, first.via)
+
+	ap.global.usage.Nodes++
+	ap.global.stats.Reduces++
 
 	return nil
 }
 
 // shift is a helper function that shifts the token.
 //
+// Parameters:
+//   - via: The shift item that was applied. Nil if there is none.
+//
 // Returns:
 //   - error: An error if any.
-func (ap *ActiveParser[T]) shift() error {
-	tk, err := ap.reader.ReadToken()
+func (ap *ActiveParser[T]) shift(via *Item[T]) error {
+	tk, err := ap.read()
 	if err != nil {
 		return err
 	}
 
-	ap.token_stack.Push(tk)
+	ap.push(&parse_node[T]{tk: tk}, via)
+	ap.shifted++
+	ap.global.stats.Shifts++
 
 	return nil
 }
 
-// Forest returns the tree that were parsed.
+// read is a helper function that reads the next input token.
 //
 // Returns:
-//   - []*uttr.Tree[*grammar.Token[T]]: The forest.
-func (ap ActiveParser[T]) Forest() []*tree.Tree[*gr.Token[T]] {
-	var forest []*tree.Tree[*gr.Token[T]]
+//   - *gr.Token[T]: The token.
+//   - error: io.EOF if the input is exhausted, or the error of the token reader
+//     of the parse session.
+func (ap *ActiveParser[T]) read() (*gr.Token[T], error) {
+	tk, err := ap.input.at(ap.next)
+	if err != nil {
+		return nil, err
+	}
 
-	for {
-		top, ok := ap.token_stack.Pop()
-		if !ok {
-			break
-		}
+	ap.next++
+
+	return tk, nil
+}
+
+// Shifted returns the number of tokens that the active parser has shifted. On
+// failure, it tells how far into the input the parser went.
+//
+// Returns:
+//   - int: The number of shifted tokens.
+func (ap ActiveParser[T]) Shifted() int {
+	return ap.shifted
+}
 
-		forest = append(forest, tree.NewTree(top))
+// Forest returns the tree that were parsed. The trees are made anew on every
+// call, so that active parsers that share part of their history do not share
+// their trees.
+//
+// Returns:
+//   - []*uttr.Tree[*grammar.Token[T]]: The forest. When the active parser recovered
+//     from parse errors, the trees set aside by the recoveries come first.
+func (ap ActiveParser[T]) Forest() []*tree.Tree[*gr.Token[T]] {
+	m := &materializer[T]{
+		copies: make(map[*gr.Token[T]]*gr.Token[T]),
 	}
 
-	slices.Reverse(forest)
+	nodes := ap.stack_nodes()
+
+	forest := make([]*tree.Tree[*gr.Token[T]], 0, len(ap.forest)+len(nodes))
+
+	for _, node := range slices.Concat(ap.forest, nodes) {
+		forest = append(forest, m.tree(node))
+	}
 
 	return forest
 }
 
+// stack_nodes is a helper function that returns the nodes on the stack, the
+// popped ones excluded.
+//
+// Returns:
+//   - []*parse_node[T]: The nodes, from the bottom to the top of the stack.
+func (ap ActiveParser[T]) stack_nodes() []*parse_node[T] {
+	var nodes []*parse_node[T]
+
+	for c := ap.cursor; c != nil; c = c.below {
+		nodes = append(nodes, c.node)
+	}
+
+	slices.Reverse(nodes)
+
+	return nodes
+}
+
 // Error returns the error if any.
 //
 // Returns:
@@ -281,5 +618,224 @@ func (ap ActiveParser[T]) Error() error {
 		return nil
 	}
 
-	return NewErrParsing(ap.err, ap.possible_cause)
+	return ap.parsing_error()
+}
+
+// parsing_error is a helper function that wraps the error of the active parser.
+//
+// Returns:
+//   - *ErrParsing: The error. Never returns nil.
+func (ap ActiveParser[T]) parsing_error() *ErrParsing {
+	frames := ap.Frames(MaxFrames)
+	if len(frames) == 0 {
+		return NewErrParsing(ap.err, ap.possible_cause)
+	}
+
+	return NewErrParsing(ap.err, ap.possible_cause).WithFrames(text.Strings(frames))
+}
+
+// Errors returns the parse errors the active parser recovered from.
+//
+// Returns:
+//   - []*ErrParsing: The errors, in the order they occurred.
+func (ap ActiveParser[T]) Errors() []*ErrParsing {
+	return ap.errs
+}
+
+// recover recovers from the error of the active parser. The error productions are
+// tried first; otherwise, if there are synchronization symbols, the recovery is
+// done in panic mode: the error is recorded, the stack is set aside as a partial
+// forest and the tokens are discarded up to, and including, the next
+// synchronization symbol. The parse then starts again right after it.
+//
+// Returns:
+//   - bool: True if the parse can go on. False if the input stream is exhausted, if
+//     the maximum number of errors is reached or, when the active parser still
+//     holds its error, if it could not recover.
+func (ap *ActiveParser[T]) recover() bool {
+	if limit := ap.global.max_diags; limit > 0 && len(ap.errs) >= limit {
+		ap.give_up(limit)
+
+		return false
+	}
+
+	if ap.recover_with_rule() {
+		return true
+	}
+
+	if len(ap.global.sync) == 0 {
+		return false
+	}
+
+	ap.errs = append(ap.errs, ap.parsing_error())
+
+	ap.err = nil
+	ap.possible_cause = nil
+	ap.accept_found = false
+
+	sync := ap.global.sync
+
+	synced := ap.cursor != nil && slices.Contains(sync, ap.cursor.node.tk.Type)
+
+	ap.forest = append(ap.forest, ap.stack_nodes()...)
+	ap.top = nil
+	ap.cursor = nil
+
+	for !synced {
+		tk, err := ap.read()
+		if err != nil {
+			return false
+		}
+
+		synced = slices.Contains(sync, tk.Type)
+	}
+
+	err := ap.shift(nil)
+
+	return err == nil
+}
+
+// give_up is a helper function that stops the active parser once it reached the
+// maximum number of errors: the final *ErrTooManyErrors is recorded in place of
+// its error, the stack is set aside as a partial forest and the rest of the input
+// is dropped.
+//
+// Parameters:
+//   - limit: The maximum number of errors.
+func (ap *ActiveParser[T]) give_up(limit int) {
+	ap.errs = append(ap.errs, NewErrParsing(NewErrTooManyErrors(limit), nil))
+
+	ap.err = nil
+	ap.possible_cause = nil
+	ap.accept_found = false
+
+	ap.forest = append(ap.forest, ap.stack_nodes()...)
+	ap.top = nil
+	ap.cursor = nil
+	ap.input = nil
+}
+
+// recover_with_rule recovers from the error of the active parser with the error
+// production of the innermost nonterminal being parsed that has one. The error is
+// recorded and the tokens of the nonterminal, followed by the input tokens up to
+// the right-hand sides that follow the error symbol, are reduced with the error
+// production.
+//
+// Returns:
+//   - bool: True if the active parser recovered, false otherwise.
+func (ap *ActiveParser[T]) recover_with_rule() bool {
+	rs := ap.global.rule_set
+	if rs == nil || len(rs.error_rules) == 0 {
+		return false
+	}
+
+	if ap.cursor == nil {
+		return false
+	}
+
+	top := ap.cursor.node.tk
+
+	var (
+		rule  *Rule[T]
+		start int
+		found bool
+	)
+
+	for _, f := range slices.Concat(ap.top_frames(), ap.frames()) {
+		rule, found = rs.error_rule_of(f.item.Lhs())
+		if found {
+			start = f.start
+			break
+		}
+	}
+
+	if !found {
+		return false
+	}
+
+	follow := rule.rhss[1:]
+
+	// at is the first token after the erroneous input. The input is read as the
+	// right-hand sides that follow the error symbol are searched.
+	at := top.Lookahead
+	skipped := 0
+
+	for len(follow) > 0 {
+		if at == nil {
+			return false
+		}
+
+		found := true
+
+		for j, rhs := range follow {
+			_, _ = ap.input.at(ap.next + skipped + j)
+
+			tk, ok := at.LookaheadAt(j)
+			if !ok || tk.Type != rhs {
+				found = false
+				break
+			}
+		}
+
+		if found {
+			break
+		}
+
+		at = at.Lookahead
+		skipped++
+	}
+
+	ap.errs = append(ap.errs, ap.parsing_error())
+
+	// The error production takes the place of the frame, so it is part of the
+	// same enclosing rule as the first token of the frame.
+	var via *Item[T]
+
+	for size_of(ap.cursor) > start {
+		via = ap.cursor.via
+
+		_, _ = ap.Pop()
+	}
+
+	discarded := ap.popped()
+
+	ap.accept()
+
+	for range skipped {
+		tk, err := ap.read()
+		if err != nil {
+			break
+		}
+
+		discarded = append(discarded, &parse_node[T]{tk: tk})
+	}
+
+	error_ := &parse_node[T]{
+		tk:       gr.NewToken(rule.rhss[0], "", at),
+		children: discarded,
+	}
+
+	children := []*parse_node[T]{error_}
+
+	for range follow {
+		tk, err := ap.read()
+		if err != nil {
+			break
+		}
+
+		children = append(children, &parse_node[T]{tk: tk})
+	}
+
+	ap.push(&parse_node[T]{
+		tk:       gr.NewToken(rule.Lhs(), "", children[len(children)-1].tk.Lookahead),
+		children: children,
+	}, via)
+
+	ap.global.usage.Nodes += 2
+
+	ap.err = nil
+	ap.possible_cause = nil
+	ap.accept_found = false
+
+	return true
 }
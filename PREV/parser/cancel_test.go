@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+// new_cancel_parser is a helper function that makes a parser of the rule set of
+// new_test_rule_set whose decision cancels the returned context at the given
+// decision.
+func new_cancel_parser(t *testing.T, at int) (*Parser[test_type], context.Context) {
+	rs := new_test_rule_set()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	var decisions int
+
+	p, err := NewParserWithFunc(func(ap *ActiveParser[test_type]) ([]*Item[test_type], error) {
+		decisions++
+
+		if decisions == at {
+			cancel()
+		}
+
+		return rs.Decision(ap)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	return p, ctx
+}
+
+func TestParseResultCtxCancelled(t *testing.T) {
+	p, ctx := new_cancel_parser(t, 3)
+
+	res := p.ParseResultCtx(ctx, lex_test_input("1 + 2"))
+
+	var cancelled *grm.ErrCancelled
+
+	if !errors.As(res.Err, &cancelled) {
+		t.Fatalf("expected an *ErrCancelled, got %v", res.Err)
+	} else if !errors.Is(res.Err, context.Canceled) {
+		t.Errorf("expected the error to wrap %v, got %v", context.Canceled, res.Err)
+	}
+
+	if cancelled.Steps != 3 {
+		t.Errorf("expected 3 steps, got %d", cancelled.Steps)
+	}
+
+	forest, _ := res.PartialForest()
+	if len(forest) == 0 {
+		t.Fatalf("expected a partial forest, got %v", forest)
+	}
+
+	if got := forest[0].Root().Type; got != nt_expr {
+		t.Errorf("expected the partial forest to start with %v, got %v", nt_expr, got)
+	}
+}
+
+func TestExecCtx(t *testing.T) {
+	p, ctx := new_cancel_parser(t, 3)
+
+	var cancelled *ActiveParser[test_type]
+
+	for ap := range p.ParseCtx(ctx, lex_test_input("( 1 + 2 ) + 3")) {
+		cancelled = ap
+	}
+
+	if cancelled == nil {
+		t.Fatal("expected the cancelled active parser, got none")
+	}
+
+	err := cancelled.ExecCtx(ctx)
+
+	var cancel_err *grm.ErrCancelled
+
+	if !errors.As(err, &cancel_err) {
+		t.Fatalf("expected an *ErrCancelled, got %v", err)
+	} else if cancel_err.Steps != 0 {
+		t.Errorf("expected 0 steps, got %d", cancel_err.Steps)
+	}
+
+	err = cancelled.Exec()
+	if err != nil {
+		t.Fatalf("expected the parse to resume, got %v", err)
+	}
+
+	forest := cancelled.Forest()
+	if len(forest) != 1 {
+		t.Fatalf("expected 1 tree, got %d", len(forest))
+	}
+
+	const want = "(Source (Expr (Expr (Term ( (Expr (Expr (Term 1)) + (Term 2)) ))) + (Term 3)) EOF)"
+
+	if got := sexpr_of(forest[0].Root()); got != want {
+		t.Errorf("expected the tree %s, got %s", want, got)
+	}
+}
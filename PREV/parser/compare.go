@@ -0,0 +1,289 @@
+package parser
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+	"github.com/PlayerR9/grammar/PREV/internal"
+	grm "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/tree/tree"
+)
+
+// StrategyReport is the measurement of a parsing strategy over a set of inputs.
+type StrategyReport struct {
+	// Name is the name of the strategy.
+	Name string
+
+	// Duration is the total time spent parsing, over every round.
+	Duration time.Duration
+
+	// Allocs is the number of heap allocations, over every round.
+	Allocs uint64
+
+	// Bytes is the number of bytes allocated on the heap, over every round.
+	Bytes uint64
+
+	// Failures is the number of inputs that could not be parsed, in one round.
+	Failures int
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Format:
+//
+//	<name>: <duration>, <allocs> allocs, <bytes> bytes, <failures> failures
+func (r StrategyReport) String() string {
+	return fmt.Sprintf("%s: %s, %d allocs, %d bytes, %d failures", r.Name, r.Duration, r.Allocs, r.Bytes, r.Failures)
+}
+
+// Difference is an input on which the parsing strategies disagree.
+type Difference struct {
+	// Input is the index of the input.
+	Input int
+
+	// Decision is the outcome of the rule-set decision strategy; that is, the
+	// shape of the parsed tree or the error.
+	Decision string
+
+	// Table is the outcome of the parse-table strategy.
+	Table string
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Format:
+//
+//	input <input>:
+//		decision: <outcome>
+//		table:    <outcome>
+func (d Difference) String() string {
+	return fmt.Sprintf("input %d:\n\tdecision: %s\n\ttable:    %s", d.Input, d.Decision, d.Table)
+}
+
+// Comparison is the result of running both parsing strategies on the same inputs.
+type Comparison struct {
+	// Inputs is the number of inputs.
+	Inputs int
+
+	// Rounds is the number of times each input was parsed by each strategy.
+	Rounds int
+
+	// Decision is the report of the rule-set decision strategy (Parser.ParseResult).
+	Decision StrategyReport
+
+	// Table is the report of the parse-table strategy (ParseTable.ParseResult).
+	Table StrategyReport
+
+	// Differences are the inputs on which the strategies disagree, in order.
+	Differences []Difference
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Format:
+//
+//	<inputs> inputs, <rounds> rounds
+//	<decision report>
+//	<table report>
+//	<n> differences
+//	<difference>
+//	...
+func (c Comparison) String() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "%d inputs, %d rounds\n", c.Inputs, c.Rounds)
+	builder.WriteString(c.Decision.String())
+	builder.WriteRune('\n')
+	builder.WriteString(c.Table.String())
+	builder.WriteRune('\n')
+	builder.WriteString(strconv.Itoa(len(c.Differences)))
+	builder.WriteString(" differences")
+
+	for _, d := range c.Differences {
+		builder.WriteRune('\n')
+		builder.WriteString(d.String())
+	}
+
+	return builder.String()
+}
+
+// Compare runs the rule-set decision strategy and the parse-table strategy on the
+// same inputs and reports their speed, their memory usage and the inputs on which
+// they disagree.
+//
+// Parameters:
+//   - rule_set: The rule set. Its items and conflicts must have been solved, as for
+//     NewParser.
+//   - inputs: The inputs, each one a token stream ending with the EOF token.
+//   - rounds: The number of times each input is parsed by each strategy. If less
+//     than 1, it is set to 1.
+//
+// Returns:
+//   - *Comparison: The comparison. Nil if an error occurred.
+//   - error: An error if rule_set is nil, if the parser could not be made or if the
+//     grammar is not LALR(1).
+//
+// Two outcomes agree when both strategies fail, or when both succeed with trees of
+// the same shape. Measurements include the garbage collector but not the making of
+// the parser and of the table.
+func Compare[T internal.TokenTyper](rule_set *RuleSet[T], inputs [][]*gr.Token[T], rounds int) (*Comparison, error) {
+	if rule_set == nil {
+		return nil, gcers.NewErrNilParameter("rule_set")
+	}
+
+	decision, err := NewEngine(rule_set)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := NewEngine(rule_set, WithEngine(TableEngine))
+	if err != nil {
+		return nil, fmt.Errorf("the table strategy needs an LALR(1) grammar: %w", err)
+	}
+
+	rounds = max(rounds, 1)
+
+	c := &Comparison{
+		Inputs: len(inputs),
+		Rounds: rounds,
+	}
+
+	decisions := make([]string, len(inputs))
+
+	c.Decision = measure(DecisionEngine.String(), decision, inputs, rounds, decisions)
+
+	tables := make([]string, len(inputs))
+
+	c.Table = measure(TableEngine.String(), table, inputs, rounds, tables)
+
+	for i := range inputs {
+		both_failed := strings.HasPrefix(decisions[i], "error: ") && strings.HasPrefix(tables[i], "error: ")
+
+		if !both_failed && decisions[i] != tables[i] {
+			c.Differences = append(c.Differences, Difference{
+				Input:    i,
+				Decision: decisions[i],
+				Table:    tables[i],
+			})
+		}
+	}
+
+	return c, nil
+}
+
+// measure is a helper function that measures a parsing strategy.
+//
+// Parameters:
+//   - name: The name of the strategy.
+//   - engine: The engine of the strategy.
+//   - inputs: The inputs.
+//   - rounds: The number of rounds. Must be positive.
+//   - outcomes: The outcomes of the inputs, filled by the first round.
+//
+// Returns:
+//   - StrategyReport: The report of the strategy.
+func measure[T internal.TokenTyper](name string, engine Engine[T], inputs [][]*gr.Token[T], rounds int, outcomes []string) StrategyReport {
+	report := StrategyReport{
+		Name: name,
+	}
+
+	var before, after runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	start := time.Now()
+
+	for round := 0; round < rounds; round++ {
+		for i, input := range inputs {
+			res := engine.ParseResult(input)
+
+			if round > 0 {
+				continue
+			}
+
+			outcomes[i] = outcome_of(res)
+
+			if res.Err != nil {
+				report.Failures++
+			}
+		}
+	}
+
+	report.Duration = time.Since(start)
+
+	runtime.ReadMemStats(&after)
+
+	report.Allocs = after.Mallocs - before.Mallocs
+	report.Bytes = after.TotalAlloc - before.TotalAlloc
+
+	return report
+}
+
+// outcome_of is a helper function that describes the outcome of a parse.
+//
+// Returns:
+//   - string: "error: <message>" on failure, the shape of the tree otherwise.
+//
+// Format of the shape:
+//
+//	<type>[(<data>)] [{ <child> ... }]
+func outcome_of[T internal.TokenTyper](res grm.Result[*tree.Tree[*gr.Token[T]]]) string {
+	if res.Err != nil {
+		return "error: " + res.Err.Error()
+	}
+
+	if len(res.Forest) != 1 {
+		return "error: expected exactly one root but got " + strconv.Itoa(len(res.Forest))
+	}
+
+	type frame struct {
+		tk    *gr.Token[T]
+		close bool
+	}
+
+	var builder strings.Builder
+
+	stack := []frame{{tk: res.Forest[0].Root()}}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if top.close {
+			builder.WriteString(" }")
+
+			continue
+		}
+
+		if builder.Len() > 0 {
+			builder.WriteRune(' ')
+		}
+
+		builder.WriteString(top.tk.Type.String())
+
+		if top.tk.Data != "" {
+			builder.WriteString("(" + strconv.Quote(top.tk.Data) + ")")
+		}
+
+		children := top.tk.Children()
+		if len(children) == 0 {
+			continue
+		}
+
+		builder.WriteString(" {")
+
+		stack = append(stack, frame{close: true})
+
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, frame{tk: children[i]})
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+)
+
+var test_inputs = []string{
+	"1",
+	"1 + 2",
+	"1 + 2 + 3",
+	"( 1 + 2 ) + 3",
+	"1 + ( 2 + ( 3 ) )",
+	"1 +",
+	"( 1",
+}
+
+func TestParseTableParse(t *testing.T) {
+	pt, err := NewLALRTable(new_test_rule_set())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	res := pt.ParseResult(lex_test_input("1 + ( 2 )"))
+	if res.Err != nil {
+		t.Fatalf("expected no error, got %v", res.Err)
+	}
+
+	const want = `Source { Expr { Expr { Term { NUM("1") } } PLUS("+") Term { LPAREN("(") Expr { Term { NUM("2") } } RPAREN(")") } } EOF }`
+
+	if got := outcome_of(res); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	res = pt.ParseResult(lex_test_input("1 + +"))
+	if res.Err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+
+	const want_err = `expected either "NUM" or "LPAREN" after "PLUS", got "PLUS" instead`
+
+	if got := res.Err.Error(); got != want_err {
+		t.Errorf("expected %q, got %q", want_err, got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	inputs := make([][]*gr.Token[test_type], 0, len(test_inputs))
+
+	for _, input := range test_inputs {
+		inputs = append(inputs, lex_test_input(input))
+	}
+
+	c, err := Compare(new_test_rule_set(), inputs, 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(c.Differences) > 0 {
+		t.Errorf("expected no differences, got:\n%s", c.String())
+	}
+
+	if c.Decision.Failures != 2 {
+		t.Errorf("expected %d decision failures, got %d", 2, c.Decision.Failures)
+	}
+
+	if c.Table.Failures != 2 {
+		t.Errorf("expected %d table failures, got %d", 2, c.Table.Failures)
+	}
+
+	if c.Rounds != 3 {
+		t.Errorf("expected %d rounds, got %d", 3, c.Rounds)
+	}
+
+	if c.Inputs != len(test_inputs) {
+		t.Errorf("expected %d inputs, got %d", len(test_inputs), c.Inputs)
+	}
+}
+
+func benchmark_strategy(b *testing.B, parse func([]*gr.Token[test_type])) {
+	inputs := make([][]*gr.Token[test_type], 0, len(test_inputs))
+
+	for _, input := range test_inputs {
+		inputs = append(inputs, lex_test_input(input))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, input := range inputs {
+			parse(input)
+		}
+	}
+}
+
+func BenchmarkDecision(b *testing.B) {
+	p, err := NewParser(new_test_rule_set())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	benchmark_strategy(b, func(tokens []*gr.Token[test_type]) { _ = p.ParseResult(tokens) })
+}
+
+func BenchmarkTable(b *testing.B) {
+	pt, err := NewLALRTable(new_test_rule_set())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	benchmark_strategy(b, func(tokens []*gr.Token[test_type]) { _ = pt.ParseResult(tokens) })
+}
+
+func TestNewLALRTableStartRules(t *testing.T) {
+	rs := NewRuleSet[test_type]()
+
+	rs.MustMakeRule(nt_source, []test_type{nt_expr, tt_eof})
+	rs.MustMakeRule(nt_source, []test_type{tt_eof})
+	rs.MustMakeRule(nt_expr, []test_type{tt_num})
+
+	pt, err := NewLALRTable(rs)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for input, want := range map[string]string{
+		"":  `Source { EOF }`,
+		"1": `Source { Expr { NUM("1") } EOF }`,
+	} {
+		if got := outcome_of(pt.ParseResult(lex_test_input(input))); got != want {
+			t.Errorf("%q: expected %s, got %s", input, want, got)
+		}
+	}
+
+	rs.MustMakeRule(nt_term, []test_type{tt_num, tt_eof})
+
+	_, err = NewLALRTable(rs)
+	if err == nil {
+		t.Errorf("expected the start rules of different left-hand sides to be rejected, got nil")
+	}
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMaxDiagnostics(t *testing.T) {
+	rs := new_stmt_rule_set()
+
+	rs.DetermineItems()
+	_ = rs.SolveConflicts()
+
+	// Four statements with a stray number each. Past the maximum, the result keeps
+	// as many errors and ends with a notice.
+	const input = "1 1 ; 2 2 ; 3 3 ; 4 4 ; 5 ;"
+
+	tests := []struct {
+		name  string
+		max   int
+		diags int
+		cut   bool
+	}{
+		{name: "no maximum", max: 0, diags: 3},
+		{name: "above", max: 5, diags: 3},
+		{name: "exact", max: 4, diags: 3},
+		{name: "below", max: 2, diags: 2, cut: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(rs)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			p.AddSyncSymbols(tt_semi)
+			p.SetMaxDiagnostics(tt.max)
+
+			res := p.ParseResult(lex_test_input(input))
+			if res.Err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+
+			if len(res.Diagnostics) != tt.diags {
+				t.Fatalf("expected %d diagnostics, got %v", tt.diags, res.Diagnostics)
+			}
+
+			var too_many *ErrTooManyErrors
+
+			last := res.Diagnostics[len(res.Diagnostics)-1]
+
+			if got := errors.As(last, &too_many); got != tt.cut {
+				t.Fatalf("expected the last diagnostic to be an *ErrTooManyErrors: %t, got %v", tt.cut, last)
+			} else if got && too_many.Limit != tt.max {
+				t.Errorf("expected the limit %d, got %d", tt.max, too_many.Limit)
+			}
+		})
+	}
+}
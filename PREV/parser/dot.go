@@ -0,0 +1,146 @@
+package parser
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/PlayerR9/grammar/PREV/internal"
+	"github.com/PlayerR9/grammar/internal/text"
+)
+
+// ToDOT renders the automaton of the table as a Graphviz graph of the DOT language.
+// Each state is a node that lists its items and its reductions; the transitions
+// are edges labeled with their symbol, dashed for the gotos on non-terminals.
+// States with a conflict, found by NewLALRTable, are drawn in red and list their
+// conflicts.
+//
+// Returns:
+//   - []byte: The DOT graph.
+func (pt ParseTable[T]) ToDOT() []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("digraph automaton {\n")
+	buf.WriteString("\trankdir=LR;\n")
+	buf.WriteString("\tnode [shape=box, fontname=\"monospace\"];\n")
+
+	index := make(map[*State[T]]int, len(pt.states))
+
+	for i, state := range pt.states {
+		index[state] = i
+	}
+
+	for i, state := range pt.states {
+		lines := []string{"state " + strconv.Itoa(i)}
+
+		for _, item := range state.items {
+			lines = append(lines, item_line(item))
+		}
+
+		actions := pt.action_table[state]
+
+		for _, la := range slices.Sorted(maps.Keys(actions)) {
+			switch actions[la] {
+			case internal.ActReduceType:
+				line := "on " + la.String() + ": reduce"
+
+				if rule := pt.reductions[state][la]; rule != nil {
+					line += " " + rule_line(rule)
+				}
+
+				lines = append(lines, line)
+			case internal.ActAcceptType:
+				lines = append(lines, "on "+la.String()+": accept")
+			}
+		}
+
+		attrs := ""
+
+		for _, conflict := range pt.conflicts {
+			if conflict.State == i {
+				lines = append(lines, "conflict on "+conflict.Lookahead.String()+": "+conflict.Kind.String())
+				attrs = ", color=red, penwidth=2"
+			}
+		}
+
+		buf.WriteString("\ts" + strconv.Itoa(i) + " [label=" + text.DOTLabel(lines) + attrs + "];\n")
+	}
+
+	for i, state := range pt.states {
+		gotos := pt.goto_table[state]
+
+		for _, symbol := range slices.Sorted(maps.Keys(gotos)) {
+			target, ok := index[gotos[symbol]]
+			if !ok {
+				continue
+			}
+
+			attrs := ""
+
+			if !symbol.IsTerminal() {
+				attrs = ", style=dashed"
+			}
+
+			buf.WriteString("\ts" + strconv.Itoa(i) + " -> s" + strconv.Itoa(target) + " [label=" + text.DOTString(symbol.String()) + attrs + "];\n")
+		}
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.Bytes()
+}
+
+// item_line is a helper function that formats an item for ToDOT.
+//
+// Parameters:
+//   - item: The item. Assumed to be non-nil.
+//
+// Returns:
+//   - string: The formatted item.
+//
+// Format:
+//
+//	"<lhs> -> <rhs> . <rhs>"
+func item_line[T internal.TokenTyper](item *Item[T]) string {
+	elems := []string{item.Lhs().String(), "->"}
+
+	var i int
+
+	for rhs := range item.Rhs() {
+		if i == item.Pos() {
+			elems = append(elems, ".")
+		}
+
+		elems = append(elems, rhs.String())
+		i++
+	}
+
+	if i == item.Pos() {
+		elems = append(elems, ".")
+	}
+
+	return strings.Join(elems, " ")
+}
+
+// rule_line is a helper function that formats a rule for ToDOT.
+//
+// Parameters:
+//   - rule: The rule. Assumed to be non-nil.
+//
+// Returns:
+//   - string: The formatted rule.
+//
+// Format:
+//
+//	"<lhs> -> <rhs> <rhs>"
+func rule_line[T internal.TokenTyper](rule *Rule[T]) string {
+	elems := []string{rule.Lhs().String(), "->"}
+
+	for rhs := range rule.Rhs() {
+		elems = append(elems, rhs.String())
+	}
+
+	return strings.Join(elems, " ")
+}
@@ -0,0 +1,162 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+	"github.com/PlayerR9/grammar/PREV/internal"
+	grm "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/tree/tree"
+)
+
+// Engine is a parsing engine. Both *Parser, which searches the decisions of the
+// rule set, and *ParseTable, which follows the action and goto tables, are engines;
+// code that only needs to parse should depend on this interface rather than on
+// either of them.
+type Engine[T internal.TokenTyper] interface {
+	// ParseResult parses the tokens.
+	//
+	// Parameters:
+	//   - tokens: The tokens to be parsed. The last one should be the EOF token.
+	//
+	// Returns:
+	//   - grm.Result[*tree.Tree[*gr.Token[T]]]: The result. On failure, the trees
+	//     parsed so far with the error and, if any, the other diagnostics.
+	ParseResult(tokens []*gr.Token[T]) grm.Result[*tree.Tree[*gr.Token[T]]]
+}
+
+// EngineKind is the kind of a parsing engine.
+type EngineKind int
+
+const (
+	// DecisionEngine is the engine that searches the decisions of the rule set; it
+	// explores every branch of ambiguous parses and can recover from errors.
+	DecisionEngine EngineKind = iota
+
+	// TableEngine is the engine that follows the LALR(1) action and goto tables;
+	// it is faster but needs a grammar without conflicts.
+	TableEngine
+)
+
+// String implements the fmt.Stringer interface.
+func (k EngineKind) String() string {
+	switch k {
+	case DecisionEngine:
+		return "decision"
+	case TableEngine:
+		return "table"
+	default:
+		return "EngineKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// engine_config is the configuration of NewEngine.
+type engine_config struct {
+	// kind is the kind of the engine.
+	kind EngineKind
+
+	// max_diags is the maximum number of errors of a parse. Zero if there is no
+	// maximum.
+	max_diags int
+}
+
+// EngineOption is an option of NewEngine.
+//
+// Parameters:
+//   - cfg: The configuration to change. Assume that cfg is not nil.
+//
+// Returns:
+//   - error: An error if the option cannot be applied.
+type EngineOption func(cfg *engine_config) error
+
+// WithEngine selects the kind of the engine. The default is DecisionEngine.
+//
+// Parameters:
+//   - kind: The kind of the engine.
+//
+// Returns:
+//   - EngineOption: The option. Never returns nil.
+func WithEngine(kind EngineKind) EngineOption {
+	return func(cfg *engine_config) error {
+		if kind != DecisionEngine && kind != TableEngine {
+			return gcers.NewErrInvalidParameter("kind", fmt.Errorf("unknown engine kind %d", int(kind)))
+		}
+
+		cfg.kind = kind
+
+		return nil
+	}
+}
+
+// WithMaxDiagnostics sets the maximum number of errors of a parse of the decision
+// engine; see Parser.SetMaxDiagnostics. The table engine, which does not recover
+// from errors, ignores it.
+//
+// Parameters:
+//   - n: The maximum number of errors. Zero means that there is no maximum.
+//
+// Returns:
+//   - EngineOption: The option. Never returns nil.
+func WithMaxDiagnostics(n int) EngineOption {
+	return func(cfg *engine_config) error {
+		if n < 0 {
+			return gcers.NewErrInvalidParameter("n", fmt.Errorf("value (%d) must not be negative", n))
+		}
+
+		cfg.max_diags = n
+
+		return nil
+	}
+}
+
+// NewEngine creates a new parsing engine for the given rule set.
+//
+// Parameters:
+//   - rule_set: The rule set. Its items and conflicts must have been solved, as for
+//     NewParser.
+//   - opts: The options. Nil options are ignored.
+//
+// Returns:
+//   - Engine[T]: The new engine: a *Parser[T] or a *ParseTable[T], according to the
+//     kind. Nil if an error occurred.
+//   - error: An error if rule_set is nil, if an option cannot be applied or if the
+//     engine could not be made; for instance, an error of type *ErrConflicts if the
+//     table engine is chosen for a grammar that is not LALR(1).
+func NewEngine[T internal.TokenTyper](rule_set *RuleSet[T], opts ...EngineOption) (Engine[T], error) {
+	if rule_set == nil {
+		return nil, gcers.NewErrNilParameter("rule_set")
+	}
+
+	var cfg engine_config
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		err := opt(&cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.kind == TableEngine {
+		pt, err := NewLALRTable(rule_set)
+		if err != nil {
+			return nil, err
+		}
+
+		return pt, nil
+	}
+
+	p, err := NewParser(rule_set)
+	if err != nil {
+		return nil, err
+	}
+
+	p.SetMaxDiagnostics(cfg.max_diags)
+
+	return p, nil
+}
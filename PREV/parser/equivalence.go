@@ -0,0 +1,271 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	"github.com/PlayerR9/grammar/PREV/internal"
+)
+
+// Discrepancy is a sentence that is in the language of only one of two rule sets.
+type Discrepancy[T internal.TokenTyper] struct {
+	// Sentence is the terminals of the sentence, without the EOF terminal.
+	Sentence []T
+
+	// InFirst is true if the sentence is only in the language of the first rule
+	// set, false if it is only in the one of the second rule set.
+	InFirst bool
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Format:
+//
+//	"<sentence>" is only in the language of the <first|second> rule set
+func (d Discrepancy[T]) String() string {
+	which := "second"
+	if d.InFirst {
+		which = "first"
+	}
+
+	words := make([]string, 0, len(d.Sentence))
+
+	for _, symbol := range d.Sentence {
+		words = append(words, symbol.String())
+	}
+
+	return fmt.Sprintf("%q is only in the language of the %s rule set", strings.Join(words, " "), which)
+}
+
+// CheckEquivalence checks that two rule sets over the same symbols accept the same
+// sentences of at most the given length; for instance, after a grammar was
+// refactored. Every sentence of one rule set is generated and looked up among the
+// sentences of the other one, and conversely.
+//
+// Parameters:
+//   - first: The first rule set.
+//   - second: The second rule set.
+//   - max_len: The maximum number of terminals of the sentences, the EOF terminal
+//     excluded.
+//
+// Returns:
+//   - *Discrepancy[T]: The shortest sentence accepted by only one of the rule sets;
+//     between sentences of the same length, the first one in the order of the
+//     symbols. Nil if the rule sets are equivalent up to max_len.
+//   - error: An error if a rule set is nil, if max_len is negative or if a rule set
+//     has no rule that ends with the EOF terminal.
+//
+// Since every sentence up to max_len is generated, the check takes time exponential
+// in max_len; small bounds, such as 6 or 8, already catch most refactoring mistakes.
+func CheckEquivalence[T internal.TokenTyper](first, second *RuleSet[T], max_len int) (*Discrepancy[T], error) {
+	if first == nil {
+		return nil, gcers.NewErrNilParameter("first")
+	} else if second == nil {
+		return nil, gcers.NewErrNilParameter("second")
+	} else if max_len < 0 {
+		return nil, gcers.NewErrInvalidParameter("max_len", fmt.Errorf("value (%d) must be non-negative", max_len))
+	}
+
+	in_first, err := sentences_of(first, max_len)
+	if err != nil {
+		return nil, fmt.Errorf("first rule set: %w", err)
+	}
+
+	in_second, err := sentences_of(second, max_len)
+	if err != nil {
+		return nil, fmt.Errorf("second rule set: %w", err)
+	}
+
+	var diffs []Discrepancy[T]
+
+	for key, sentence := range in_first {
+		if _, ok := in_second[key]; !ok {
+			diffs = append(diffs, Discrepancy[T]{Sentence: sentence, InFirst: true})
+		}
+	}
+
+	for key, sentence := range in_second {
+		if _, ok := in_first[key]; !ok {
+			diffs = append(diffs, Discrepancy[T]{Sentence: sentence, InFirst: false})
+		}
+	}
+
+	if len(diffs) == 0 {
+		return nil, nil
+	}
+
+	d := slices.MinFunc(diffs, func(a, b Discrepancy[T]) int {
+		if len(a.Sentence) != len(b.Sentence) {
+			return len(a.Sentence) - len(b.Sentence)
+		}
+
+		return slices.Compare(a.Sentence, b.Sentence)
+	})
+
+	return &d, nil
+}
+
+// sentences_of is a helper function that generates every sentence of a rule set
+// of at most the given length, by leftmost derivations from its start rule.
+//
+// Parameters:
+//   - rs: The rule set. Assumed to be non-nil.
+//   - max_len: The maximum number of terminals, the EOF terminal excluded.
+//
+// Returns:
+//   - map[string][]T: The sentences, without the EOF terminal, by key.
+//   - error: An error if no rule ends with the EOF terminal.
+func sentences_of[T internal.TokenTyper](rs *RuleSet[T], max_len int) (map[string][]T, error) {
+	var start *Rule[T]
+
+	for _, rule := range rs.rules {
+		last, _ := rule.RhsAt(rule.Size() - 1)
+		if last == T(0) {
+			start = rule
+			break
+		}
+	}
+
+	if start == nil {
+		return nil, fmt.Errorf("there is no rule that ends with %q", T(0).String())
+	}
+
+	lengths := shortest_lengths(rs.rules)
+
+	// bound is the number of terminals of the shortest sentence a form derives.
+	bound := func(form []T) (int, bool) {
+		var total int
+
+		for _, symbol := range form {
+			switch {
+			case symbol == T(0):
+			case symbol.IsTerminal():
+				total++
+			default:
+				n, ok := lengths[symbol]
+				if !ok {
+					return 0, false
+				}
+
+				total += n
+			}
+		}
+
+		return total, true
+	}
+
+	sentences := make(map[string][]T)
+
+	todo := [][]T{slices.Clone(start.rhss)}
+	seen := map[string]bool{sentence_key(start.rhss): true}
+
+	for len(todo) > 0 {
+		form := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+
+		idx := slices.IndexFunc(form, func(symbol T) bool {
+			return !symbol.IsTerminal()
+		})
+
+		if idx == -1 {
+			sentence := slices.DeleteFunc(form, func(symbol T) bool {
+				return symbol == T(0)
+			})
+
+			sentences[sentence_key(sentence)] = sentence
+
+			continue
+		}
+
+		for _, rule := range rs.RulesWithLhs(form[idx]) {
+			next := slices.Concat(form[:idx], rule.rhss, form[idx+1:])
+
+			n, ok := bound(next)
+			if !ok || n > max_len {
+				continue
+			}
+
+			key := sentence_key(next)
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+			todo = append(todo, next)
+		}
+	}
+
+	return sentences, nil
+}
+
+// shortest_lengths is a helper function that computes the number of terminals of
+// the shortest sentence of every nonterminal, the EOF terminal excluded.
+//
+// Parameters:
+//   - rules: The rules.
+//
+// Returns:
+//   - map[T]int: The lengths. The nonterminals that derive no sentence are absent.
+func shortest_lengths[T internal.TokenTyper](rules []*Rule[T]) map[T]int {
+	lengths := make(map[T]int)
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, rule := range rules {
+			total, ok := 0, true
+
+			for rhs := range rule.Rhs() {
+				switch {
+				case rhs == T(0):
+				case rhs.IsTerminal():
+					total++
+				default:
+					n, found := lengths[rhs]
+					if !found {
+						ok = false
+					}
+
+					total += n
+				}
+
+				if !ok {
+					break
+				}
+			}
+
+			if !ok {
+				continue
+			}
+
+			prev, found := lengths[rule.Lhs()]
+			if !found || total < prev {
+				lengths[rule.Lhs()] = total
+				changed = true
+			}
+		}
+	}
+
+	return lengths
+}
+
+// sentence_key is a helper function that makes the key of a sequence of symbols.
+//
+// Parameters:
+//   - symbols: The symbols.
+//
+// Returns:
+//   - string: The key.
+func sentence_key[T internal.TokenTyper](symbols []T) string {
+	var builder strings.Builder
+
+	for _, symbol := range symbols {
+		builder.WriteString(strconv.Itoa(int(symbol)))
+		builder.WriteRune(' ')
+	}
+
+	return builder.String()
+}
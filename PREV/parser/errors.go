@@ -5,8 +5,9 @@ import (
 	"strings"
 
 	gcers "github.com/PlayerR9/go-commons/errors"
-	gcstr "github.com/PlayerR9/go-commons/strings"
+	ogr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
 	"github.com/PlayerR9/grammar/PREV/internal"
+	"github.com/PlayerR9/grammar/internal/text"
 )
 
 // ErrUnexpectedLookahead is the error for unexpected tokens.
@@ -32,12 +33,8 @@ func (e ErrUnexpectedLookahead[T]) Error() string {
 	if len(e.Expecteds) == 0 {
 		builder.WriteString("nothing")
 	} else {
-		values := make([]string, 0, len(e.Expecteds))
-		for _, expected := range e.Expecteds {
-			values = append(values, expected.String())
-		}
-		gcstr.QuoteStrings(values)
-		builder.WriteString(gcstr.EitherOrString(values))
+		values := text.Quoted(text.Strings(e.Expecteds))
+		builder.WriteString(text.JoinList(values, text.EitherOr))
 	}
 
 	builder.WriteString(" after ")
@@ -95,12 +92,8 @@ func (e ErrUnexpectedNode[N]) Error() string {
 	if len(e.Expecteds) == 0 {
 		builder.WriteString("nothing")
 	} else {
-		values := make([]string, 0, len(e.Expecteds))
-		for _, expected := range e.Expecteds {
-			values = append(values, expected.String())
-		}
-		gcstr.QuoteStrings(values)
-		builder.WriteString(gcstr.EitherOrString(values))
+		values := text.Quoted(text.Strings(e.Expecteds))
+		builder.WriteString(text.JoinList(values, text.EitherOr))
 	}
 
 	if e.Prev == nil {
@@ -148,22 +141,39 @@ type ErrParsing struct {
 
 	// PossibleCause is the possible cause of the error.
 	PossibleCause error
+
+	// Frames are the names of the nonterminals that were being parsed when the
+	// error occurred, from the innermost to the outermost one.
+	Frames []string
+
+	// Suggestion is the suggestion for solving the error.
+	Suggestion string
 }
 
 // Error implements the error interface.
 //
-// Message: "<err>, possible cause: <possible cause>".
+// Message: "while parsing <frame> in <frame>: <err>, possible cause: <possible cause>.
+// <suggestion>".
 func (e ErrParsing) Error() string {
 	var builder strings.Builder
 
+	if len(e.Frames) > 0 {
+		builder.WriteString("while parsing ")
+		builder.WriteString(strings.Join(text.Quoted(e.Frames), " in "))
+		builder.WriteString(": ")
+	}
+
 	builder.WriteString(gcers.Error(e.Err))
 
-	if e.PossibleCause == nil {
-		return builder.String()
+	if e.PossibleCause != nil {
+		builder.WriteString(", possible cause: ")
+		builder.WriteString(e.PossibleCause.Error())
 	}
 
-	builder.WriteString(", possible cause: ")
-	builder.WriteString(e.PossibleCause.Error())
+	if e.Suggestion != "" {
+		builder.WriteString(". ")
+		builder.WriteString(e.Suggestion)
+	}
 
 	return builder.String()
 }
@@ -190,3 +200,246 @@ func NewErrParsing(err error, possible_cause error) *ErrParsing {
 		PossibleCause: possible_cause,
 	}
 }
+
+// WithFrames sets the nonterminals that were being parsed when the error occurred.
+//
+// Parameters:
+//   - frames: The names of the nonterminals, from the innermost to the outermost one.
+//
+// Returns:
+//   - *ErrParsing: The error itself. Never returns nil.
+func (e *ErrParsing) WithFrames(frames []string) *ErrParsing {
+	e.Frames = frames
+
+	return e
+}
+
+// SetSuggestion sets the suggestion for solving the error.
+//
+// Parameters:
+//   - suggestions: The suggestions for solving the error.
+func (e *ErrParsing) SetSuggestion(suggestions ...string) {
+	e.Suggestion = strings.Join(suggestions, " ")
+}
+
+// WarnUnusedTerminal is the warning for terminals that no rule consumes.
+type WarnUnusedTerminal[T internal.TokenTyper] struct {
+	// Terminal is the unused terminal.
+	Terminal T
+}
+
+// Error implements the error interface.
+//
+// Message: "terminal <terminal> is never used in the right-hand side of any rule".
+func (w WarnUnusedTerminal[T]) Error() string {
+	return "terminal " + strconv.Quote(w.Terminal.String()) + " is never used in the right-hand side of any rule"
+}
+
+// NewWarnUnusedTerminal creates a new WarnUnusedTerminal.
+//
+// Parameters:
+//   - terminal: The unused terminal.
+//
+// Returns:
+//   - *WarnUnusedTerminal[T]: A pointer to the new WarnUnusedTerminal. Never returns nil.
+func NewWarnUnusedTerminal[T internal.TokenTyper](terminal T) *WarnUnusedTerminal[T] {
+	return &WarnUnusedTerminal[T]{
+		Terminal: terminal,
+	}
+}
+
+// ErrResourceLimit is the error for parse sessions that exceed one of their
+// resource limits.
+type ErrResourceLimit struct {
+	// Resource is the name of the exceeded resource.
+	Resource string
+
+	// Limit is the configured limit.
+	Limit int
+
+	// Used is the amount of the resource that was used when the parse was aborted.
+	Used int
+}
+
+// Error implements the error interface.
+//
+// Message: "resource limit exceeded: <used> <resource> (limit is <limit>)".
+func (e ErrResourceLimit) Error() string {
+	return "resource limit exceeded: " + strconv.Itoa(e.Used) + " " + e.Resource + " (limit is " + strconv.Itoa(e.Limit) + ")"
+}
+
+// NewErrResourceLimit creates a new ErrResourceLimit.
+//
+// Parameters:
+//   - resource: The name of the exceeded resource.
+//   - limit: The configured limit.
+//   - used: The amount of the resource that was used.
+//
+// Returns:
+//   - *ErrResourceLimit: A pointer to the new ErrResourceLimit. Never returns nil.
+func NewErrResourceLimit(resource string, limit, used int) *ErrResourceLimit {
+	return &ErrResourceLimit{
+		Resource: resource,
+		Limit:    limit,
+		Used:     used,
+	}
+}
+
+// ErrStackOverflow is the error for parse sessions whose stack exceeds the
+// maximum depth.
+type ErrStackOverflow[T internal.TokenTyper] struct {
+	// Limit is the maximum depth.
+	Limit int
+
+	// Depth is the depth of the stack when the parse was aborted.
+	Depth int
+
+	// Rule is the rule that was the most often being parsed, which is likely to
+	// recurse without end. Nil if no rule was being parsed.
+	Rule *Rule[T]
+
+	// Count is the number of times Rule was being parsed.
+	Count int
+}
+
+// Error implements the error interface.
+//
+// Message: "stack overflow: depth <depth> exceeds the limit of <limit>; <rule> is
+// being parsed <count> times, possible runaway recursion".
+func (e ErrStackOverflow[T]) Error() string {
+	msg := "stack overflow: depth " + strconv.Itoa(e.Depth) + " exceeds the limit of " + strconv.Itoa(e.Limit)
+
+	if e.Rule == nil {
+		return msg
+	}
+
+	return msg + "; " + strconv.Quote(rule_line(e.Rule)) + " is being parsed " + strconv.Itoa(e.Count) + " times, possible runaway recursion"
+}
+
+// NewErrStackOverflow creates a new ErrStackOverflow.
+//
+// Parameters:
+//   - limit: The maximum depth.
+//   - depth: The depth of the stack.
+//   - rule: The rule that was the most often being parsed.
+//   - count: The number of times rule was being parsed.
+//
+// Returns:
+//   - *ErrStackOverflow[T]: A pointer to the new ErrStackOverflow. Never returns nil.
+func NewErrStackOverflow[T internal.TokenTyper](limit, depth int, rule *Rule[T], count int) *ErrStackOverflow[T] {
+	return &ErrStackOverflow[T]{
+		Limit: limit,
+		Depth: depth,
+		Rule:  rule,
+		Count: count,
+	}
+}
+
+// ErrTooManyErrors is the final diagnostic of a parse that stopped recording
+// errors because it reached its maximum number of diagnostics.
+type ErrTooManyErrors struct {
+	// Limit is the maximum number of diagnostics.
+	Limit int
+}
+
+// Error implements the error interface.
+//
+// Message: "too many errors (limit is <limit>)".
+func (e ErrTooManyErrors) Error() string {
+	return "too many errors (limit is " + strconv.Itoa(e.Limit) + ")"
+}
+
+// NewErrTooManyErrors creates a new ErrTooManyErrors.
+//
+// Parameters:
+//   - limit: The maximum number of diagnostics.
+//
+// Returns:
+//   - *ErrTooManyErrors: A pointer to the new ErrTooManyErrors. Never returns nil.
+func NewErrTooManyErrors(limit int) *ErrTooManyErrors {
+	return &ErrTooManyErrors{
+		Limit: limit,
+	}
+}
+
+// WarnAmbiguity is the warning for chronic ambiguity sites.
+type WarnAmbiguity[T internal.TokenTyper] struct {
+	// Site is the ambiguity site.
+	Site *AmbiguitySite[T]
+}
+
+// Error implements the error interface.
+//
+// Message: "ambiguity between <n> alternatives reached <hits> times (successes: <s1>, <s2>, ...)".
+func (w WarnAmbiguity[T]) Error() string {
+	if w.Site == nil {
+		return "ambiguity site is unknown"
+	}
+
+	successes := make([]string, 0, len(w.Site.Successes))
+
+	for _, count := range w.Site.Successes {
+		successes = append(successes, strconv.Itoa(count))
+	}
+
+	return "ambiguity between " + strconv.Itoa(len(w.Site.Alternatives)) + " alternatives reached " +
+		strconv.Itoa(w.Site.Hits) + " times (successes: " + strings.Join(successes, ", ") + ")"
+}
+
+// Code implements the grammar.Coder interface.
+func (w WarnAmbiguity[T]) Code() ogr.Code {
+	return ogr.CodeAmbiguousResolved
+}
+
+// NewWarnAmbiguity creates a new WarnAmbiguity.
+//
+// Parameters:
+//   - site: The ambiguity site.
+//
+// Returns:
+//   - *WarnAmbiguity[T]: A pointer to the new WarnAmbiguity. Never returns nil.
+func NewWarnAmbiguity[T internal.TokenTyper](site *AmbiguitySite[T]) *WarnAmbiguity[T] {
+	return &WarnAmbiguity[T]{
+		Site: site,
+	}
+}
+
+// ErrConflicts is an error that occurs when a grammar is not LALR(1).
+type ErrConflicts[T internal.TokenTyper] struct {
+	// Conflicts are the conflicts of the table.
+	Conflicts []Conflict[T]
+}
+
+// Error implements the error interface.
+//
+// Message:
+//
+//	"<n> conflicts:
+//		<conflict>
+//		..."
+func (e ErrConflicts[T]) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString(strconv.Itoa(len(e.Conflicts)))
+	builder.WriteString(" conflicts:")
+
+	for _, c := range e.Conflicts {
+		builder.WriteString("\n\t")
+		builder.WriteString(c.String())
+	}
+
+	return builder.String()
+}
+
+// NewErrConflicts creates a new ErrConflicts error.
+//
+// Parameters:
+//   - conflicts: The conflicts of the table.
+//
+// Returns:
+//   - *ErrConflicts[T]: The new error. Never returns nil.
+func NewErrConflicts[T internal.TokenTyper](conflicts []Conflict[T]) *ErrConflicts[T] {
+	return &ErrConflicts[T]{
+		Conflicts: conflicts,
+	}
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"errors"
+	"io"
+
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+	"github.com/PlayerR9/grammar/PREV/internal"
+)
+
+// token_feed is the input of a parse session, shared by its active parsers. The
+// tokens are copied, so that the tokens of the caller are not modified, and are
+// linked to their lookaheads as they are read. When the tokens come from a
+// reader, they are only read when they are needed, so that the parse can start
+// before the input is complete.
+type token_feed[T internal.TokenTyper] struct {
+	// reader is the reader of the tokens. Nil once it is exhausted.
+	reader gr.TokenReader[T]
+
+	// tokens are the copies of the tokens read so far.
+	tokens []*gr.Token[T]
+
+	// err is the error of the reader, other than io.EOF. Nil if there is none.
+	err error
+
+	// depth is the number of tokens read ahead of the token being shifted, so that
+	// the decisions can look at their lookaheads. If negative, the whole input is
+	// read at once.
+	depth int
+
+	// usage is the resource usage of the parse session.
+	usage *Usage
+}
+
+// new_slice_feed creates the input of a parse session from a slice of tokens.
+//
+// Parameters:
+//   - tokens: The tokens.
+//   - usage: The resource usage of the parse session. Assumed to be non-nil.
+//
+// Returns:
+//   - *token_feed[T]: The new feed. Never returns nil.
+func new_slice_feed[T internal.TokenTyper](tokens []*gr.Token[T], usage *Usage) *token_feed[T] {
+	copies := make([]*gr.Token[T], 0, len(tokens))
+
+	for _, tk := range tokens {
+		copies = append(copies, tk.Copy())
+	}
+
+	gr.LinkLookaheads(copies)
+
+	usage.Tokens += len(copies)
+
+	return &token_feed[T]{
+		tokens: copies,
+		usage:  usage,
+	}
+}
+
+// new_reader_feed creates the input of a parse session from a token reader.
+//
+// Parameters:
+//   - reader: The token reader. Assumed to be non-nil.
+//   - depth: The number of tokens to read ahead. If negative, the whole input is
+//     read on the first access.
+//   - usage: The resource usage of the parse session. Assumed to be non-nil.
+//
+// Returns:
+//   - *token_feed[T]: The new feed. Never returns nil.
+func new_reader_feed[T internal.TokenTyper](reader gr.TokenReader[T], depth int, usage *Usage) *token_feed[T] {
+	return &token_feed[T]{
+		reader: reader,
+		depth:  depth,
+		usage:  usage,
+	}
+}
+
+// fill is a helper function that reads tokens until there are n of them or the
+// reader is exhausted.
+//
+// Parameters:
+//   - n: The number of tokens. If negative, every token is read.
+func (f *token_feed[T]) fill(n int) {
+	for f.reader != nil && (n < 0 || len(f.tokens) < n) {
+		tk, err := f.reader.ReadToken()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				f.err = err
+			}
+
+			f.reader = nil
+
+			break
+		}
+
+		if tk == nil {
+			continue
+		}
+
+		cp := tk.Copy()
+
+		if len(f.tokens) > 0 {
+			f.tokens[len(f.tokens)-1].Lookahead = cp
+		}
+
+		f.tokens = append(f.tokens, cp)
+		f.usage.Tokens++
+	}
+}
+
+// at returns the token at the given position of the input, reading it and the
+// tokens that follow it up to the depth of the feed if needed.
+//
+// Parameters:
+//   - i: The position of the token.
+//
+// Returns:
+//   - *gr.Token[T]: The token.
+//   - error: io.EOF if the input ends before i or if the feed is nil, or the
+//     error of the reader.
+func (f *token_feed[T]) at(i int) (*gr.Token[T], error) {
+	if f == nil {
+		return nil, io.EOF
+	}
+
+	if f.depth < 0 {
+		f.fill(-1)
+	} else {
+		f.fill(i + 1 + f.depth)
+	}
+
+	if i < len(f.tokens) {
+		return f.tokens[i], nil
+	}
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return nil, io.EOF
+}
+
+// all returns every token of the input, reading the ones that are left.
+//
+// Returns:
+//   - []*gr.Token[T]: The tokens.
+func (f *token_feed[T]) all() []*gr.Token[T] {
+	f.fill(-1)
+
+	return f.tokens
+}
@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+)
+
+func TestParseReader(t *testing.T) {
+	p, err := NewParser(new_test_rule_set())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, input := range test_inputs {
+		want := outcome_of(p.ParseResult(lex_test_input(input)))
+
+		r := gr.NewChanTokenReader[test_type](context.Background(), 0)
+
+		go func() {
+			for _, tk := range lex_test_input(input) {
+				_ = r.Send(tk)
+			}
+
+			r.Close(nil)
+		}()
+
+		if got := outcome_of(p.ParseReader(r)); got != want {
+			t.Errorf("input %q: expected %s, got %s", input, want, got)
+		}
+	}
+}
+
+func TestParseReaderError(t *testing.T) {
+	p, err := NewParser(new_test_rule_set())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	lex_err := errors.New("unexpected character")
+
+	r := gr.NewChanTokenReader[test_type](context.Background(), 4)
+
+	go func() {
+		for _, tk := range lex_test_input("1 + 2")[:2] {
+			_ = r.Send(tk)
+		}
+
+		r.Close(lex_err)
+	}()
+
+	res := p.ParseReader(r)
+	if !errors.Is(res.Err, lex_err) {
+		t.Fatalf("expected %v, got %v", lex_err, res.Err)
+	}
+}
@@ -0,0 +1,165 @@
+package parser
+
+import (
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+	"github.com/PlayerR9/grammar/PREV/internal"
+	"github.com/PlayerR9/tree/tree"
+)
+
+// parse_node is a node of the parse tree of an active parser. Nodes are never
+// modified once made, so the forks of an active parser share them; the tokens are
+// only linked to their children when the tree is materialized by Forest.
+type parse_node[T internal.TokenTyper] struct {
+	// tk is the token of the node. For leaves, the input token itself.
+	tk *gr.Token[T]
+
+	// children are the children of the node. Nil for leaves.
+	children []*parse_node[T]
+}
+
+// stack_cell is a cell of the persistent stack of an active parser.
+type stack_cell[T internal.TokenTyper] struct {
+	// node is the node of the cell.
+	node *parse_node[T]
+
+	// below is the cell below this one. Nil at the bottom of the stack.
+	below *stack_cell[T]
+
+	// size is the number of cells from the bottom of the stack to this one.
+	size int
+
+	// via is the shift item that pushed the cell; it was decided while the cell
+	// below was on top. Nil for the cells that no shift item pushed.
+	via *Item[T]
+}
+
+// size_of is a helper function that returns the size of a stack.
+//
+// Parameters:
+//   - top: The top of the stack. Nil if the stack is empty.
+//
+// Returns:
+//   - int: The number of cells of the stack.
+func size_of[T internal.TokenTyper](top *stack_cell[T]) int {
+	if top == nil {
+		return 0
+	}
+
+	return top.size
+}
+
+// frame is a nonterminal that an active parser is parsing.
+type frame[T internal.TokenTyper] struct {
+	// item is the partially matched item.
+	item *Item[T]
+
+	// start is the size of the stack below the first token of the frame.
+	start int
+}
+
+// fork returns an active parser that continues independently from the same
+// point. It takes constant time: the stack and the input are shared
+// and never modified, and both active parsers copy the recorded errors and forest
+// before they append to them.
+//
+// Returns:
+//   - *ActiveParser[T]: The fork. Never returns nil.
+func (ap *ActiveParser[T]) fork() *ActiveParser[T] {
+	ap.refuse()
+
+	ap.forest = ap.forest[:len(ap.forest):len(ap.forest)]
+	ap.errs = ap.errs[:len(ap.errs):len(ap.errs)]
+
+	fork := *ap
+
+	return &fork
+}
+
+// materializer turns parse nodes into token trees. Every token is copied, so the
+// trees of different calls to Forest share nothing.
+type materializer[T internal.TokenTyper] struct {
+	// copies are the copies of the input tokens, by input token.
+	copies map[*gr.Token[T]]*gr.Token[T]
+}
+
+// leaf is a helper function that returns the copy of an input token, whose
+// lookahead is the copy of the next input token.
+//
+// Parameters:
+//   - tk: The input token.
+//
+// Returns:
+//   - *gr.Token[T]: The copy. Nil if tk is nil.
+func (m *materializer[T]) leaf(tk *gr.Token[T]) *gr.Token[T] {
+	var chain []*gr.Token[T]
+
+	for curr := tk; curr != nil; curr = curr.Lookahead {
+		if _, ok := m.copies[curr]; ok {
+			break
+		}
+
+		chain = append(chain, curr)
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		cp := chain[i].Copy()
+		cp.Lookahead = m.copies[chain[i].Lookahead]
+
+		m.copies[chain[i]] = cp
+	}
+
+	return m.copies[tk]
+}
+
+// tree is a helper function that materializes the tree of a parse node.
+//
+// Parameters:
+//   - root: The parse node. Assumed to be non-nil.
+//
+// Returns:
+//   - *tree.Tree[*gr.Token[T]]: The tree. Never returns nil.
+func (m *materializer[T]) tree(root *parse_node[T]) *tree.Tree[*gr.Token[T]] {
+	type pair struct {
+		node *parse_node[T]
+		done bool
+	}
+
+	tokens := make(map[*parse_node[T]]*gr.Token[T])
+
+	stack := []pair{{node: root}}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if len(top.node.children) == 0 {
+			tokens[top.node] = m.leaf(top.node.tk)
+
+			continue
+		}
+
+		if !top.done {
+			stack = append(stack, pair{node: top.node, done: true})
+
+			for _, child := range top.node.children {
+				stack = append(stack, pair{node: child})
+			}
+
+			continue
+		}
+
+		tk := gr.NewToken(top.node.tk.Type, top.node.tk.Data, m.leaf(top.node.tk.Lookahead))
+
+		children := make([]*gr.Token[T], 0, len(top.node.children))
+
+		for _, child := range top.node.children {
+			children = append(children, tokens[child])
+		}
+
+		tk.AddChildren(children)
+
+		tokens[top.node] = tk
+	}
+
+	return tree.NewTree(tokens[root])
+}
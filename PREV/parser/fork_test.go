@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// new_ambiguous_rule_set creates the rule set of sums of numbers where the
+// associativity of PLUS is left unresolved, so that the parser forks at every
+// PLUS.
+func new_ambiguous_rule_set() *RuleSet[test_type] {
+	rs := NewRuleSet[test_type]()
+
+	rs.MustMakeRule(nt_source, []test_type{nt_expr, tt_eof})
+	rs.MustMakeRule(nt_expr, []test_type{nt_expr, tt_plus, nt_expr})
+	rs.MustMakeRule(nt_expr, []test_type{tt_num})
+
+	rs.DetermineItems()
+
+	return rs
+}
+
+// sum_of returns the sum of the numbers from 1 to n.
+func sum_of(n int) string {
+	words := make([]string, 0, n)
+
+	for i := 1; i <= n; i++ {
+		words = append(words, strconv.Itoa(i))
+	}
+
+	return strings.Join(words, " + ")
+}
+
+func TestForkShare(t *testing.T) {
+	p, err := NewParser(new_ambiguous_rule_set())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tokens := lex_test_input(sum_of(8))
+
+	res := p.ParseResult(tokens)
+	if res.Err != nil {
+		t.Fatalf("expected no error, got %v", res.Err)
+	}
+
+	usage := p.Usage()
+
+	if usage.Forks < 2 {
+		t.Fatalf("expected the parser to fork, got %d branches", usage.Forks)
+	}
+
+	if usage.Tokens != len(tokens) {
+		t.Errorf("expected %d tokens allocated, got %d", len(tokens), usage.Tokens)
+	}
+}
+
+func BenchmarkFork(b *testing.B) {
+	p, err := NewParser(new_ambiguous_rule_set())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, n := range []int{4, 16, 64} {
+		tokens := lex_test_input(sum_of(n))
+
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				_ = p.ParseResult(tokens)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package parser
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	"github.com/PlayerR9/grammar/PREV/internal"
+)
+
+// DefaultMaxDepth is the default depth after which the generator only expands
+// the shortest derivations of the nonterminals.
+const DefaultMaxDepth int = 16
+
+// Generator generates random sentences of a rule set; for instance, to produce
+// inputs for tests or fuzzers.
+//
+// The generator draws every random decision from the source it was given, so two
+// generators of the same rule set whose sources are seeded alike generate the
+// same sentences, in the same order. With a source of type *rand.PCG or
+// *rand.ChaCha8, this also holds across Go versions and platforms.
+type Generator[T internal.TokenTyper] struct {
+	// rule_set is the rule set.
+	rule_set *RuleSet[T]
+
+	// rng is the random number generator.
+	rng *rand.Rand
+
+	// max_depth is the depth after which only the shortest derivations are expanded.
+	max_depth int
+
+	// heights are the heights of the shortest derivations of the nonterminals.
+	heights map[T]int
+}
+
+// NewGenerator creates a new sentence generator.
+//
+// Parameters:
+//   - rule_set: The rule set.
+//   - rng: The random number generator.
+//
+// Returns:
+//   - *Generator[T]: The new generator.
+//   - error: An error of type *errors.ErrInvalidParameter if rule_set or rng is nil.
+func NewGenerator[T internal.TokenTyper](rule_set *RuleSet[T], rng *rand.Rand) (*Generator[T], error) {
+	if rule_set == nil {
+		return nil, gcers.NewErrNilParameter("rule_set")
+	} else if rng == nil {
+		return nil, gcers.NewErrNilParameter("rng")
+	}
+
+	return &Generator[T]{
+		rule_set:  rule_set,
+		rng:       rng,
+		max_depth: DefaultMaxDepth,
+		heights:   shortest_heights(rule_set.rules),
+	}, nil
+}
+
+// NewSeededGenerator is like NewGenerator but the random number generator is
+// created from the given seed.
+//
+// Parameters:
+//   - rule_set: The rule set.
+//   - seed: The seed.
+//
+// Returns:
+//   - *Generator[T]: The new generator.
+//   - error: An error of type *errors.ErrInvalidParameter if rule_set is nil.
+func NewSeededGenerator[T internal.TokenTyper](rule_set *RuleSet[T], seed uint64) (*Generator[T], error) {
+	return NewGenerator(rule_set, rand.New(rand.NewPCG(seed, seed)))
+}
+
+// SetMaxDepth sets the depth after which only the shortest derivations of the
+// nonterminals are expanded. This bounds the size of the sentences.
+//
+// Parameters:
+//   - depth: The maximum depth. If non-positive, DefaultMaxDepth is used.
+func (g *Generator[T]) SetMaxDepth(depth int) {
+	if depth <= 0 {
+		depth = DefaultMaxDepth
+	}
+
+	g.max_depth = depth
+}
+
+// Sentence generates a random sentence derived from the given symbol.
+//
+// Parameters:
+//   - start: The symbol to derive the sentence from.
+//
+// Returns:
+//   - []T: The terminals of the sentence. The EOF terminal is never included.
+//   - error: An error if a nonterminal cannot derive any sentence.
+func (g *Generator[T]) Sentence(start T) ([]T, error) {
+	var sentence []T
+
+	err := g.expand(start, &sentence)
+	if err != nil {
+		return nil, err
+	}
+
+	return sentence, nil
+}
+
+// expand is a helper function that appends a random derivation of the given
+// symbol to the sentence.
+//
+// Parameters:
+//   - symbol: The symbol to expand.
+//   - sentence: The sentence.
+//
+// Returns:
+//   - error: An error if a nonterminal cannot derive any sentence.
+func (g *Generator[T]) expand(symbol T, sentence *[]T) error {
+	type pending struct {
+		symbol T
+		depth  int
+	}
+
+	// The symbols are expanded with an explicit stack, as the derivations may be
+	// too deep for recursion; the right-hand sides are pushed in reverse so that
+	// they are expanded, and draw from the random number generator, from left to
+	// right.
+	stack := []pending{{symbol: symbol}}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if top.symbol.IsTerminal() {
+			if top.symbol != T(0) {
+				*sentence = append(*sentence, top.symbol)
+			}
+
+			continue
+		}
+
+		_, ok := g.heights[top.symbol]
+		if !ok {
+			return fmt.Errorf("nonterminal %q cannot derive any sentence", top.symbol.String())
+		}
+
+		rules := g.rule_set.RulesWithLhs(top.symbol)
+
+		if top.depth >= g.max_depth {
+			rules = g.shortest(rules)
+		} else {
+			rules = slices.DeleteFunc(rules, func(rule *Rule[T]) bool {
+				_, ok := rule_height(rule, g.heights)
+				return !ok
+			})
+		}
+
+		rule := rules[g.rng.IntN(len(rules))]
+
+		for rhs := range rule.Backwards() {
+			stack = append(stack, pending{symbol: rhs, depth: top.depth + 1})
+		}
+	}
+
+	return nil
+}
+
+// shortest is a helper function that keeps the rules whose derivations are
+// the shortest.
+//
+// Parameters:
+//   - rules: The rules of a nonterminal.
+//
+// Returns:
+//   - []*Rule[T]: The shortest rules. Never empty if the nonterminal is productive.
+func (g Generator[T]) shortest(rules []*Rule[T]) []*Rule[T] {
+	var result []*Rule[T]
+
+	min_height := -1
+
+	for _, rule := range rules {
+		height, ok := rule_height(rule, g.heights)
+		if !ok {
+			continue
+		}
+
+		if min_height == -1 || height < min_height {
+			min_height = height
+			result = result[:0]
+		}
+
+		if height == min_height {
+			result = append(result, rule)
+		}
+	}
+
+	return result
+}
+
+// rule_height is a helper function that computes the height of the shortest
+// derivation of a rule.
+//
+// Parameters:
+//   - rule: The rule.
+//   - heights: The known heights of the nonterminals.
+//
+// Returns:
+//   - int: The height.
+//   - bool: True if every symbol of the rule has a known height, false otherwise.
+func rule_height[T internal.TokenTyper](rule *Rule[T], heights map[T]int) (int, bool) {
+	height := 0
+
+	for rhs := range rule.Rhs() {
+		if rhs.IsTerminal() {
+			continue
+		}
+
+		h, ok := heights[rhs]
+		if !ok {
+			return 0, false
+		}
+
+		height = max(height, h)
+	}
+
+	return height + 1, true
+}
+
+// shortest_heights is a helper function that computes the heights of the shortest
+// derivations of the nonterminals. Nonterminals that cannot derive any sentence
+// are left out.
+//
+// Parameters:
+//   - rules: The rules of the grammar.
+//
+// Returns:
+//   - map[T]int: The heights of the nonterminals.
+func shortest_heights[T internal.TokenTyper](rules []*Rule[T]) map[T]int {
+	heights := make(map[T]int)
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, rule := range rules {
+			height, ok := rule_height(rule, heights)
+			if !ok {
+				continue
+			}
+
+			prev, ok := heights[rule.lhs]
+			if !ok || height < prev {
+				heights[rule.lhs] = height
+				changed = true
+			}
+		}
+	}
+
+	return heights
+}
@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+)
+
+func TestGeneratorSentence(t *testing.T) {
+	rs := new_test_rule_set()
+
+	p, err := NewParser(rs)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for seed := range uint64(20) {
+		g, err := NewSeededGenerator(rs, seed)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		g.SetMaxDepth(6)
+
+		sentence, err := g.Sentence(nt_source)
+		if err != nil {
+			t.Fatalf("seed %d: expected no error, got %v", seed, err)
+		}
+
+		tokens := make([]*gr.Token[test_type], 0, len(sentence)+1)
+
+		for _, type_ := range sentence {
+			tokens = append(tokens, gr.NewToken(type_, type_.String(), nil))
+		}
+
+		tokens = append(tokens, gr.NewToken(tt_eof, "", nil))
+
+		if res := p.ParseResult(tokens); res.Err != nil {
+			t.Errorf("seed %d: expected %v to parse, got %v", seed, sentence, res.Err)
+		}
+	}
+}
+
+func TestGeneratorSeeded(t *testing.T) {
+	rs := new_test_rule_set()
+
+	sentences := func(seed uint64) [][]test_type {
+		g, err := NewSeededGenerator(rs, seed)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		g.SetMaxDepth(4)
+
+		var all [][]test_type
+
+		for range 5 {
+			sentence, err := g.Sentence(nt_source)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			all = append(all, sentence)
+		}
+
+		return all
+	}
+
+	// The sentences of a PCG source do not change across Go versions.
+	want := [][]test_type{
+		{tt_lparen, tt_num, tt_plus, tt_num, tt_rparen},
+		{tt_num},
+		{tt_num},
+		{tt_num},
+		{tt_num, tt_plus, tt_num, tt_plus, tt_num},
+	}
+
+	for range 2 {
+		for i, got := range sentences(42) {
+			if !slices.Equal(got, want[i]) {
+				t.Errorf("sentence %d: expected %v, got %v", i, want[i], got)
+			}
+		}
+	}
+
+	_, err := NewGenerator[test_type](rs, nil)
+	if err == nil {
+		t.Error("expected an error without a random number generator, got nil")
+	}
+}
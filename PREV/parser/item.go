@@ -26,6 +26,10 @@ type Item[T internal.TokenTyper] struct {
 
 	// lookaheads is the set of lookaheads.
 	lookaheads []*gccmp.Set[T]
+
+	// expected are the terminals the symbol that follows the one of a shift item
+	// can start with. Nil for the other items.
+	expected *gccmp.Set[T]
 }
 
 // Equals implements the pkg.Type interface.
@@ -112,9 +116,10 @@ func NewItem[T internal.TokenTyper](rule *Rule[T], pos int) (*Item[T], error) {
 	}
 
 	return &Item[T]{
-		rule: rule,
-		pos:  pos,
-		act:  act,
+		rule:  rule,
+		pos:   pos,
+		act:   act,
+		prevs: gccmp.NewSet[T](),
 	}, nil
 }
 
@@ -123,13 +128,66 @@ func NewItem[T internal.TokenTyper](rule *Rule[T], pos int) (*Item[T], error) {
 // Returns:
 //   - bool: True if the item is a shift, otherwise false.
 func (item Item[T]) IsShift() bool {
-	return item.pos < item.rule.Size()
+	return item.act == internal.ActShiftType
 }
 
 // IsReduce checks if the item is a reduce.
 //
 // Returns:
 //   - bool: True if the item is a reduce, otherwise false.
+func (item Item[T]) IsReduce() bool {
+	return item.act == internal.ActReduceType
+}
+
+// IsAccept checks if the item is an accept; that is, a reduce of a rule that ends
+// with the EOF token.
+//
+// Returns:
+//   - bool: True if the item is an accept, otherwise false.
+func (item Item[T]) IsAccept() bool {
+	return item.act == internal.ActAcceptType
+}
+
+// Rule returns the rule of the item.
+//
+// Returns:
+//   - *Rule[T]: The rule. Never returns nil.
+func (item Item[T]) Rule() *Rule[T] {
+	return item.rule
+}
+
+// Lookbehinds returns an iterator over the symbols that must precede the item for
+// it to be chosen.
+//
+// Returns:
+//   - iter.Seq[T]: The iterator. Never returns nil.
+func (item Item[T]) Lookbehinds() iter.Seq[T] {
+	if item.prevs == nil {
+		return func(yield func(T) bool) {}
+	}
+
+	return item.prevs.All()
+}
+
+// Lookaheads returns an iterator over the lookahead sets of the item, with the
+// offset of each of them.
+//
+// Returns:
+//   - iter.Seq2[int, *gccmp.Set[T]]: The iterator. Never returns nil.
+func (item Item[T]) Lookaheads() iter.Seq2[int, *gccmp.Set[T]] {
+	return func(yield func(int, *gccmp.Set[T]) bool) {
+		for i, la := range item.lookaheads {
+			if !yield(i, la) {
+				return
+			}
+		}
+	}
+}
+
+// IncreaseLookbehind extends the lookbehind of the item by one symbol.
+//
+// Returns:
+//   - bool: True if the lookbehind was extended, otherwise false.
 func (item *Item[T]) IncreaseLookbehind() bool {
 	if item.pos == 0 {
 		return false
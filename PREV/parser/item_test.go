@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestNewItemLookbehind(t *testing.T) {
+	rule, err := NewRule(nt_expr, []test_type{nt_expr, tt_plus, nt_term})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	item, err := NewItem(rule, 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if item.prevs == nil || item.prevs.Len() != 0 {
+		t.Fatalf("expected an empty lookbehind set, got %v", item.prevs)
+	}
+
+	for _, want := range []int{1, 2} {
+		if !item.IncreaseLookbehind() {
+			t.Fatalf("expected the lookbehind to grow to %d", want)
+		}
+
+		if item.prevs.Len() != want {
+			t.Fatalf("expected %d lookbehinds, got %d", want, item.prevs.Len())
+		}
+	}
+
+	if item.IncreaseLookbehind() {
+		t.Fatalf("expected the lookbehind not to grow past the start of the rule")
+	}
+
+	other, _ := NewItem(rule, 0)
+
+	if item.IsInConflictWith(other) {
+		t.Errorf("expected items with different lookbehinds not to conflict")
+	}
+}
@@ -0,0 +1,586 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/PlayerR9/go-commons/cmp"
+	gcers "github.com/PlayerR9/go-commons/errors"
+	"github.com/PlayerR9/grammar/PREV/internal"
+)
+
+// ConflictKind is the kind of a conflict of an LALR(1) table.
+type ConflictKind int
+
+const (
+	// ShiftReduce is a conflict between a shift and a reduce.
+	ShiftReduce ConflictKind = iota
+
+	// ReduceReduce is a conflict between two reduces.
+	ReduceReduce
+)
+
+// String implements the fmt.Stringer interface.
+func (k ConflictKind) String() string {
+	switch k {
+	case ShiftReduce:
+		return "shift/reduce"
+	case ReduceReduce:
+		return "reduce/reduce"
+	default:
+		return "ConflictKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// Conflict is a conflict of an LALR(1) table; that is, a state in which more than
+// one action is possible on the same lookahead.
+type Conflict[T internal.TokenTyper] struct {
+	// State is the index of the state of the conflict.
+	State int
+
+	// Lookahead is the lookahead on which the actions conflict.
+	Lookahead T
+
+	// Kind is the kind of the conflict.
+	Kind ConflictKind
+
+	// Items are the items of the conflicting actions. The first one is the item
+	// of the action that was kept.
+	Items []*Item[T]
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Format:
+//
+//	state <state>, on <lookahead>: <kind> between <item> and <item>
+func (c Conflict[T]) String() string {
+	items := make([]string, 0, len(c.Items))
+
+	for _, item := range c.Items {
+		items = append(items, item.String())
+	}
+
+	return fmt.Sprintf("state %d, on %s: %s between %s", c.State, c.Lookahead.String(), c.Kind.String(), strings.Join(items, " and "))
+}
+
+// item_key is the key of an LR(0) item.
+type item_key struct {
+	// rule is the index of the rule of the item.
+	rule int
+
+	// pos is the position of the dot.
+	pos int
+}
+
+// lalr_state is a state of the LALR(1) automaton under construction.
+type lalr_state[T internal.TokenTyper] struct {
+	// kernel are the kernel items of the state, sorted.
+	kernel []item_key
+
+	// lookaheads are the lookaheads of the kernel items.
+	lookaheads map[item_key]*cmp.Set[T]
+
+	// gotos are the indices of the states reached on each symbol.
+	gotos map[T]int
+}
+
+// new_lalr_state creates a new state with empty lookaheads.
+//
+// Parameters:
+//   - kernel: The kernel items of the state, sorted.
+//
+// Returns:
+//   - *lalr_state[T]: The new state. Never returns nil.
+func new_lalr_state[T internal.TokenTyper](kernel []item_key) *lalr_state[T] {
+	lookaheads := make(map[item_key]*cmp.Set[T], len(kernel))
+
+	for _, key := range kernel {
+		lookaheads[key] = cmp.NewSet[T]()
+	}
+
+	return &lalr_state[T]{
+		kernel:     kernel,
+		lookaheads: lookaheads,
+	}
+}
+
+// lalr_builder builds an LALR(1) table.
+type lalr_builder[T internal.TokenTyper] struct {
+	// rules are the rules of the grammar.
+	rules []*Rule[T]
+
+	// by_lhs are the indices of the rules of each left-hand side.
+	by_lhs map[T][]int
+
+	// first are the FIRST sets of the symbols.
+	first map[T]*cmp.Set[T]
+
+	// follow are the FOLLOW sets of the non-terminals.
+	follow map[T]*cmp.Set[T]
+
+	// states are the states of the automaton. The first one is the initial state.
+	states []*lalr_state[T]
+}
+
+// next_of is a helper function that returns the symbol after the dot of an item.
+//
+// Parameters:
+//   - key: The item.
+//
+// Returns:
+//   - T: The symbol after the dot.
+//   - bool: True if the item is not complete, false otherwise.
+func (b lalr_builder[T]) next_of(key item_key) (T, bool) {
+	return b.rules[key.rule].RhsAt(key.pos)
+}
+
+// make_first is a helper function that computes the FIRST sets. Rules are never
+// empty, so the FIRST set of a rule is the one of its first symbol.
+func (b *lalr_builder[T]) make_first() {
+	b.first = make(map[T]*cmp.Set[T])
+
+	get := func(symbol T) *cmp.Set[T] {
+		set, ok := b.first[symbol]
+		if !ok {
+			set = cmp.NewSet[T]()
+
+			if symbol.IsTerminal() {
+				set.Add(symbol)
+			}
+
+			b.first[symbol] = set
+		}
+
+		return set
+	}
+
+	for _, rule := range b.rules {
+		get(rule.Lhs())
+
+		for rhs := range rule.Rhs() {
+			get(rhs)
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, rule := range b.rules {
+			rhs, _ := rule.RhsAt(0)
+
+			if get(rule.Lhs()).Union(get(rhs)) > 0 {
+				changed = true
+			}
+		}
+	}
+}
+
+// make_follow is a helper function that computes the FOLLOW sets.
+func (b *lalr_builder[T]) make_follow() {
+	b.follow = make(map[T]*cmp.Set[T])
+
+	for lhs := range b.by_lhs {
+		b.follow[lhs] = cmp.NewSet[T]()
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, rule := range b.rules {
+			for i := 0; i < rule.Size(); i++ {
+				symbol, _ := rule.RhsAt(i)
+
+				set, ok := b.follow[symbol]
+				if !ok {
+					continue
+				}
+
+				var added int
+
+				if next, ok := rule.RhsAt(i + 1); ok {
+					added = set.Union(b.first[next])
+				} else {
+					added = set.Union(b.follow[rule.Lhs()])
+				}
+
+				if added > 0 {
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// closure is a helper function that computes the LR(1) closure of the kernel of a
+// state.
+//
+// Parameters:
+//   - state: The state.
+//
+// Returns:
+//   - []item_key: The items of the closure, kernel items first.
+//   - map[item_key]*cmp.Set[T]: The lookaheads of the items.
+func (b lalr_builder[T]) closure(state *lalr_state[T]) ([]item_key, map[item_key]*cmp.Set[T]) {
+	items := slices.Clone(state.kernel)
+	lookaheads := make(map[item_key]*cmp.Set[T], len(items))
+
+	for _, key := range state.kernel {
+		set := cmp.NewSet[T]()
+		set.Union(state.lookaheads[key])
+
+		lookaheads[key] = set
+	}
+
+	todo := slices.Clone(items)
+
+	for len(todo) > 0 {
+		key := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+
+		symbol, ok := b.next_of(key)
+		if !ok || symbol.IsTerminal() {
+			continue
+		}
+
+		la := lookaheads[key]
+
+		if next, ok := b.rules[key.rule].RhsAt(key.pos + 1); ok {
+			la = b.first[next]
+		}
+
+		for _, idx := range b.by_lhs[symbol] {
+			child := item_key{rule: idx, pos: 0}
+
+			set, ok := lookaheads[child]
+			if !ok {
+				set = cmp.NewSet[T]()
+				lookaheads[child] = set
+				items = append(items, child)
+			}
+
+			if set.Union(la) > 0 || !ok {
+				todo = append(todo, child)
+			}
+		}
+	}
+
+	return items, lookaheads
+}
+
+// kernel_id is a helper function that returns the identifier of a kernel.
+//
+// Parameters:
+//   - kernel: The kernel, sorted.
+//
+// Returns:
+//   - string: The identifier.
+func kernel_id(kernel []item_key) string {
+	var builder strings.Builder
+
+	for _, key := range kernel {
+		builder.WriteString(strconv.Itoa(key.rule))
+		builder.WriteRune('.')
+		builder.WriteString(strconv.Itoa(key.pos))
+		builder.WriteRune(';')
+	}
+
+	return builder.String()
+}
+
+// make_states is a helper function that makes the LR(0) automaton; that is, the
+// states of the LALR(1) automaton without their lookaheads.
+//
+// Parameters:
+//   - starts: The indices of the start rules, in increasing order.
+func (b *lalr_builder[T]) make_states(starts []int) {
+	kernel := make([]item_key, 0, len(starts))
+
+	for _, start := range starts {
+		kernel = append(kernel, item_key{rule: start, pos: 0})
+	}
+
+	initial := new_lalr_state[T](kernel)
+
+	b.states = []*lalr_state[T]{initial}
+	index := map[string]int{kernel_id(initial.kernel): 0}
+
+	for i := 0; i < len(b.states); i++ {
+		state := b.states[i]
+		state.gotos = make(map[T]int)
+
+		items, _ := b.closure(state)
+
+		kernels := make(map[T][]item_key)
+		var symbols []T
+
+		for _, key := range items {
+			symbol, ok := b.next_of(key)
+			if !ok {
+				continue
+			}
+
+			if _, ok := kernels[symbol]; !ok {
+				symbols = append(symbols, symbol)
+			}
+
+			kernels[symbol] = append(kernels[symbol], item_key{rule: key.rule, pos: key.pos + 1})
+		}
+
+		for _, symbol := range symbols {
+			kernel := kernels[symbol]
+
+			slices.SortFunc(kernel, func(a, b item_key) int {
+				if a.rule != b.rule {
+					return a.rule - b.rule
+				}
+
+				return a.pos - b.pos
+			})
+
+			kernel = slices.Compact(kernel)
+
+			id := kernel_id(kernel)
+
+			idx, ok := index[id]
+			if !ok {
+				idx = len(b.states)
+				index[id] = idx
+
+				b.states = append(b.states, new_lalr_state[T](kernel))
+			}
+
+			state.gotos[symbol] = idx
+		}
+	}
+}
+
+// propagate is a helper function that propagates the lookaheads between the
+// states until none of them changes.
+func (b *lalr_builder[T]) propagate() {
+	for changed := true; changed; {
+		changed = false
+
+		for _, state := range b.states {
+			items, lookaheads := b.closure(state)
+
+			for _, key := range items {
+				symbol, ok := b.next_of(key)
+				if !ok {
+					continue
+				}
+
+				target := b.states[state.gotos[symbol]]
+				advanced := item_key{rule: key.rule, pos: key.pos + 1}
+
+				if target.lookaheads[advanced].Union(lookaheads[key]) > 0 {
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// NewLALRTable creates a new LALR(1) parse table from the rules of the given rule set.
+//
+// The start rules are the rules that end with the EOF symbol (T(0)); they must have
+// the same left-hand side, such as "source : start EOF" and, for a start symbol
+// that derives the empty input, "source : EOF". Reducing one accepts the input. Shifts are stored in the goto table, on terminals, along with
+// the gotos on non-terminals.
+//
+// Parameters:
+//   - rs: The rule set.
+//
+// Returns:
+//   - *ParseTable[T]: The new parse table. Nil only if rs is nil, has no start
+//     rule or has start rules of different left-hand sides.
+//   - error: An error of type *ErrConflicts if the grammar is not LALR(1).
+//
+// On conflicts, the table is still returned: shifts are preferred over reduces and
+// reduces of earlier rules over those of later ones.
+func NewLALRTable[T internal.TokenTyper](rs *RuleSet[T]) (*ParseTable[T], error) {
+	if rs == nil {
+		return nil, gcers.NewErrNilParameter("rs")
+	}
+
+	b := &lalr_builder[T]{
+		rules:  rs.rules,
+		by_lhs: make(map[T][]int),
+	}
+
+	var starts []int
+
+	for i, rule := range b.rules {
+		b.by_lhs[rule.Lhs()] = append(b.by_lhs[rule.Lhs()], i)
+
+		last, _ := rule.RhsAt(rule.Size() - 1)
+		if last != T(0) {
+			continue
+		}
+
+		if len(starts) > 0 && b.rules[starts[0]].Lhs() != rule.Lhs() {
+			return nil, fmt.Errorf("the rules that end with %q have different left-hand sides", T(0).String())
+		}
+
+		starts = append(starts, i)
+	}
+
+	if len(starts) == 0 {
+		return nil, fmt.Errorf("there is no rule that ends with %q", T(0).String())
+	}
+
+	b.make_first()
+	b.make_follow()
+	b.make_states(starts)
+
+	for _, start := range starts {
+		b.states[0].lookaheads[item_key{rule: start, pos: 0}].Add(T(0))
+	}
+
+	b.propagate()
+
+	return b.table(starts)
+}
+
+// table is a helper function that makes the parse table of the automaton.
+//
+// Parameters:
+//   - starts: The indices of the start rules.
+//
+// Returns:
+//   - *ParseTable[T]: The parse table. Never returns nil.
+//   - error: An error of type *ErrConflicts if the grammar is not LALR(1).
+func (b lalr_builder[T]) table(starts []int) (*ParseTable[T], error) {
+	pt := new_parse_table(b.rules)
+
+	pt.first = b.first
+	pt.follow = b.follow
+
+	rule_idx := make(map[*Rule[T]]int, len(b.rules))
+
+	for i, rule := range b.rules {
+		rule_idx[rule] = i
+	}
+
+	item_of := make(map[item_key]*Item[T])
+
+	for item := range pt.item_set.All() {
+		item_of[item_key{rule: rule_idx[item.rule], pos: item.pos}] = item
+	}
+
+	pt.states = make([]*State[T], 0, len(b.states))
+
+	closures := make([][]item_key, 0, len(b.states))
+	lookaheads := make([]map[item_key]*cmp.Set[T], 0, len(b.states))
+
+	for _, state := range b.states {
+		items, las := b.closure(state)
+
+		closure := make([]*Item[T], 0, len(items))
+
+		for _, key := range items {
+			closure = append(closure, item_of[key])
+		}
+
+		pt.states = append(pt.states, NewState(closure[0], closure))
+		closures = append(closures, items)
+		lookaheads = append(lookaheads, las)
+	}
+
+	pt.action_table = make(map[*State[T]]map[T]internal.ActionType, len(b.states))
+	pt.goto_table = make(map[*State[T]]map[T]*State[T], len(b.states))
+	pt.reductions = make(map[*State[T]]map[T]*Rule[T], len(b.states))
+
+	var conflicts []Conflict[T]
+
+	for i, state := range b.states {
+		s := pt.states[i]
+
+		actions := make(map[T]internal.ActionType)
+		gotos := make(map[T]*State[T])
+		reductions := make(map[T]*Rule[T])
+		shifted := make(map[T]item_key)
+		reduced := make(map[T]item_key)
+
+		for symbol, target := range state.gotos {
+			s.AddNext(pt.states[target])
+			gotos[symbol] = pt.states[target]
+
+			if symbol.IsTerminal() {
+				actions[symbol] = internal.ActShiftType
+			}
+		}
+
+		for _, key := range closures[i] {
+			symbol, ok := b.next_of(key)
+			if !ok || !symbol.IsTerminal() {
+				continue
+			}
+
+			if _, ok := shifted[symbol]; !ok {
+				shifted[symbol] = key
+			}
+		}
+
+		for _, key := range closures[i] {
+			if _, ok := b.next_of(key); ok {
+				continue
+			}
+
+			act := internal.ActReduceType
+			if slices.Contains(starts, key.rule) {
+				act = internal.ActAcceptType
+			}
+
+			for la := range lookaheads[i][key].All() {
+				if other, ok := shifted[la]; ok {
+					conflicts = append(conflicts, Conflict[T]{
+						State:     i,
+						Lookahead: la,
+						Kind:      ShiftReduce,
+						Items:     []*Item[T]{item_of[other], item_of[key]},
+					})
+
+					continue
+				}
+
+				if other, ok := reduced[la]; ok {
+					kept, dropped := other, key
+
+					if key.rule < other.rule {
+						kept, dropped = key, other
+					}
+
+					conflicts = append(conflicts, Conflict[T]{
+						State:     i,
+						Lookahead: la,
+						Kind:      ReduceReduce,
+						Items:     []*Item[T]{item_of[kept], item_of[dropped]},
+					})
+
+					if kept == other {
+						continue
+					}
+				}
+
+				reduced[la] = key
+				actions[la] = act
+				reductions[la] = b.rules[key.rule]
+			}
+		}
+
+		pt.action_table[s] = actions
+		pt.goto_table[s] = gotos
+		pt.reductions[s] = reductions
+	}
+
+	pt.conflicts = conflicts
+
+	if len(conflicts) > 0 {
+		return pt, NewErrConflicts(conflicts)
+	}
+
+	return pt, nil
+}
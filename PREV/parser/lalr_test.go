@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestLALRFirstFollow(t *testing.T) {
+	pt, err := NewLALRTable(new_test_rule_set())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  []test_type
+		want []test_type
+	}{
+		{name: "first of Expr", got: pt.First(nt_expr), want: []test_type{tt_num, tt_lparen}},
+		{name: "first of Term", got: pt.First(nt_term), want: []test_type{tt_num, tt_lparen}},
+		{name: "follow of Expr", got: pt.Follow(nt_expr), want: []test_type{tt_eof, tt_plus, tt_rparen}},
+		{name: "follow of Term", got: pt.Follow(nt_term), want: []test_type{tt_eof, tt_plus, tt_rparen}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.Equal(tt.got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, tt.got)
+			}
+		})
+	}
+}
+
+func TestLALRNotSLR(t *testing.T) {
+	// The classic grammar that is LALR(1) but not SLR(1):
+	//
+	//	S -> L = R | R
+	//	L -> * R | id
+	//	R -> L
+	//
+	// with S as Stmt, L as Expr, R as Term, "=" as PLUS, "*" as LPAREN and id as
+	// NUM. As PLUS follows Term, an SLR table would conflict after an Expr.
+	rs := NewRuleSet[test_type]()
+
+	rs.MustMakeRule(nt_source, []test_type{nt_stmt, tt_eof})
+	rs.MustMakeRule(nt_stmt, []test_type{nt_expr, tt_plus, nt_term})
+	rs.MustMakeRule(nt_stmt, []test_type{nt_term})
+	rs.MustMakeRule(nt_expr, []test_type{tt_lparen, nt_term})
+	rs.MustMakeRule(nt_expr, []test_type{tt_num})
+	rs.MustMakeRule(nt_term, []test_type{nt_expr})
+
+	pt, err := NewLALRTable(rs)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, input := range []string{"1", "( 1", "1 + 2", "( ( 1 + ( 2"} {
+		if res := pt.ParseResult(lex_test_input(input)); res.Err != nil {
+			t.Errorf("%q: expected no error, got %v", input, res.Err)
+		}
+	}
+
+	if res := pt.ParseResult(lex_test_input("1 + 2 + 3")); res.Err == nil {
+		t.Error("expected an error for two assignments, got nil")
+	}
+}
+
+func TestLALRConflicts(t *testing.T) {
+	rs := NewRuleSet[test_type]()
+
+	rs.MustMakeRule(nt_source, []test_type{nt_expr, tt_eof})
+	rs.MustMakeRule(nt_expr, []test_type{nt_expr, tt_plus, nt_expr})
+	rs.MustMakeRule(nt_expr, []test_type{tt_num})
+
+	pt, err := NewLALRTable(rs)
+
+	var conflicts *ErrConflicts[test_type]
+
+	if !errors.As(err, &conflicts) {
+		t.Fatalf("expected an *ErrConflicts, got %v", err)
+	}
+
+	if len(conflicts.Conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %v", conflicts.Conflicts)
+	}
+
+	c := conflicts.Conflicts[0]
+
+	if c.Kind != ShiftReduce || c.Lookahead != tt_plus || len(c.Items) != 2 {
+		t.Errorf("expected a shift/reduce conflict on PLUS between 2 items, got %s", c.String())
+	}
+
+	// The table is still made, with the shift preferred: "1 + 2 + 3" groups to the
+	// right.
+	if pt == nil {
+		t.Fatal("expected the table despite the conflicts, got nil")
+	}
+
+	const want = `Source { Expr { Expr { NUM("1") } PLUS("+") Expr { Expr { NUM("2") } PLUS("+") Expr { NUM("3") } } } EOF }`
+
+	if got := outcome_of(pt.ParseResult(lex_test_input("1 + 2 + 3"))); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
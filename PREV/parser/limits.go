@@ -0,0 +1,56 @@
+package parser
+
+// Limits are the resource limits of a parse session. They are meant for services
+// that parse untrusted inputs on behalf of several tenants. A non-positive limit
+// means that the resource is not limited.
+type Limits struct {
+	// MaxTokens is the maximum number of tokens allocated. The input tokens are
+	// copied once per parse, whatever the number of forks.
+	MaxTokens int
+
+	// MaxNodes is the maximum number of nodes created by reductions.
+	MaxNodes int
+
+	// MaxForks is the maximum number of branches alive at the same time.
+	MaxForks int
+
+	// MaxDepth is the maximum number of nodes on the stack of a branch. Inputs that
+	// exceed it usually reveal a runaway recursion in the grammar; the error names
+	// the rule that is the most often being parsed.
+	MaxDepth int
+}
+
+// Usage is the approximate amount of resources used by a parse session.
+type Usage struct {
+	// Tokens is the number of tokens allocated.
+	Tokens int
+
+	// Nodes is the number of nodes created by reductions.
+	Nodes int
+
+	// Forks is the highest number of branches alive at the same time.
+	Forks int
+}
+
+// exceeded checks whether the usage exceeds any of the given limits.
+//
+// Parameters:
+//   - limits: The limits to check against.
+//
+// Returns:
+//   - error: An error of type *ErrResourceLimit if a limit is exceeded. Nil otherwise.
+func (u Usage) exceeded(limits Limits) error {
+	if limits.MaxTokens > 0 && u.Tokens > limits.MaxTokens {
+		return NewErrResourceLimit("tokens", limits.MaxTokens, u.Tokens)
+	}
+
+	if limits.MaxNodes > 0 && u.Nodes > limits.MaxNodes {
+		return NewErrResourceLimit("nodes", limits.MaxNodes, u.Nodes)
+	}
+
+	if limits.MaxForks > 0 && u.Forks > limits.MaxForks {
+		return NewErrResourceLimit("forks", limits.MaxForks, u.Forks)
+	}
+
+	return nil
+}
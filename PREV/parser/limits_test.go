@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStackOverflow(t *testing.T) {
+	rs := new_test_rule_set()
+
+	p, err := NewParser(rs)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	input := strings.Repeat("( ", 20) + "1" + strings.Repeat(" )", 20)
+
+	p.SetLimits(Limits{MaxDepth: 10})
+
+	res := p.ParseResult(lex_test_input(input))
+
+	var overflow *ErrStackOverflow[test_type]
+
+	if !errors.As(res.Err, &overflow) {
+		t.Fatalf("expected a stack overflow, got %v", res.Err)
+	}
+
+	if overflow.Rule == nil || overflow.Rule.Lhs() != nt_term {
+		t.Fatalf("expected a rule of %q, got %v", nt_term, overflow)
+	}
+
+	p.SetLimits(Limits{})
+
+	res = p.ParseResult(lex_test_input(input))
+	if res.Err != nil {
+		t.Fatalf("expected no error, got %v", res.Err)
+	}
+
+	if p.Stats().PeakDepth <= 10 {
+		t.Errorf("expected a peak depth above 10, got %d", p.Stats().PeakDepth)
+	}
+}
+
+func TestResourceLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   Limits
+		resource string
+	}{
+		{"tokens", Limits{MaxTokens: 3}, "tokens"},
+		{"nodes", Limits{MaxNodes: 2}, "nodes"},
+		{"forks", Limits{MaxForks: 1}, "forks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(new_ambiguous_rule_set())
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			p.SetLimits(tt.limits)
+
+			res := p.ParseResult(lex_test_input(sum_of(4)))
+
+			var limit *ErrResourceLimit
+
+			if !errors.As(res.Err, &limit) {
+				t.Fatalf("expected an *ErrResourceLimit, got %v", res.Err)
+			} else if limit.Resource != tt.resource {
+				t.Errorf("expected the resource %q, got %q", tt.resource, limit.Resource)
+			}
+
+			p.SetLimits(Limits{})
+
+			res = p.ParseResult(lex_test_input(sum_of(4)))
+			if res.Err != nil {
+				t.Fatalf("expected no error without limits, got %v", res.Err)
+			}
+		})
+	}
+}
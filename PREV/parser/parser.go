@@ -1,13 +1,17 @@
 package parser
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"iter"
+	"slices"
 
-	util "github.com/PlayerR9/go-commons/backup"
 	gcers "github.com/PlayerR9/go-commons/errors"
 	gr "github.com/PlayerR9/grammar/PREV/grammar"
 	"github.com/PlayerR9/grammar/PREV/internal"
-	"github.com/PlayerR9/listlike/stack"
+	grm "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/tree/tree"
 )
 
 // DecisionFn is the decision function.
@@ -25,14 +29,40 @@ type Parser[T internal.TokenTyper] struct {
 	// tokens is the token stream.
 	tokens []*gr.Token[T]
 
+	// reader is the reader of the token stream. Nil if the tokens are given as a
+	// slice.
+	reader gr.TokenReader[T]
+
+	// feed is the input of the current parse session.
+	feed *token_feed[T]
+
 	// rule_set is the rule set.
 	rule_set *RuleSet[T]
 
-	// table is the parsing table.
-	table *parse_table[T]
-
 	// decision_fn is the decision function.
 	decision_fn DecisionFn[T]
+
+	// limits are the resource limits of a parse session.
+	limits Limits
+
+	// usage is the resource usage of the current parse session.
+	usage Usage
+
+	// stats are the statistics of the current parse session.
+	stats Stats
+
+	// profile is the ambiguity profile. Nil if ambiguities are not profiled.
+	profile *AmbiguityProfile[T]
+
+	// sync are the synchronization symbols of the recovery from parse errors.
+	sync []T
+
+	// repair is true if repairs are suggested when a parse fails.
+	repair bool
+
+	// max_diags is the maximum number of errors of a parse. Non-positive if there
+	// is no maximum.
+	max_diags int
 }
 
 // NewParser creates a new parser with the given rule set.
@@ -48,15 +78,8 @@ func NewParser[T internal.TokenTyper](rule_set *RuleSet[T]) (*Parser[T], error)
 		return nil, gcers.NewErrNilParameter("rule_set")
 	}
 
-	pt := new_parse_table(rule_set.rules)
-	err := pt.init()
-	if err != nil {
-		return nil, err
-	}
-
 	return &Parser[T]{
 		rule_set: rule_set,
-		table:    pt,
 	}, nil
 }
 
@@ -78,54 +101,476 @@ func NewParserWithFunc[T internal.TokenTyper](decision_fn DecisionFn[T]) (*Parse
 	}, nil
 }
 
-// new_active_parser creates a new active parser.
-//
-// Parameters:
-//   - global: The shared information between active parsers.
+// active_parser_of creates the first active parser of a parse session. The input
+// tokens are copied, so that the tokens of the caller are not modified; the other
+// active parsers are forks of this one and share the copies.
 //
 // Returns:
-//   - *ActiveParser: The new active parser.
-//   - error: An error if shifting the first token failed.
+//   - *ActiveParser: The new active parser. If shifting the first token failed, it
+//     holds the error.
 func (p *Parser[T]) active_parser_of() *ActiveParser[T] {
 	// dbg.AssertThat("len(p.tokens)", dbg.NewOrderedAssert(len(p.tokens)).GreaterThan(0)).Panic()
 
-	tokens := make([]*gr.Token[T], 0, len(p.tokens))
-	for i := 0; i < len(p.tokens); i++ {
-		tokens = append(tokens, p.tokens[i].Copy())
-	}
-
-	for i := 0; i < len(tokens)-1; i++ {
-		tokens[i].Lookahead = tokens[i+1]
+	if p.reader != nil {
+		p.feed = new_reader_feed(p.reader, p.lookahead_depth(), &p.usage)
+	} else {
+		p.feed = new_slice_feed(p.tokens, &p.usage)
 	}
 
 	new_ap := &ActiveParser[T]{
 		global:         p,
-		reader:         gr.NewTokenStream(tokens),
-		token_stack:    stack.NewRefusableStack[*gr.Token[T]](),
+		input:          p.feed,
 		err:            nil,
 		possible_cause: nil,
 	}
 
-	err := new_ap.shift() // initial shift
+	err := new_ap.shift(nil) // initial shift
 	if err != nil {
 		new_ap.err = err
-
-		return nil
 	}
 
 	return new_ap
 }
 
-// Parse is the main function of the parser.
+// lookahead_depth is a helper function that returns the number of tokens to read
+// ahead of the token being shifted: the longest lookahead of the items of the
+// rule set.
+//
+// Returns:
+//   - int: The number of tokens. -1 if the parser has no rule set, as a decision
+//     function may look at any token; the whole input is then read at once.
+func (p Parser[T]) lookahead_depth() int {
+	if p.rule_set == nil {
+		return -1
+	}
+
+	depth := 1
+
+	for _, items := range p.rule_set.items {
+		for _, item := range items {
+			depth = max(depth, len(item.lookaheads))
+		}
+	}
+
+	return depth
+}
+
+// SetLimits sets the resource limits of the parse sessions. When a parse session
+// exceeds one of them, the parse is aborted and the last active parser yielded
+// holds an error of type *ErrResourceLimit, or of type *ErrStackOverflow[T] for the
+// maximum depth.
+//
+// Parameters:
+//   - limits: The resource limits.
+func (p *Parser[T]) SetLimits(limits Limits) {
+	p.limits = limits
+}
+
+// Usage returns the approximate resource usage of the last parse session.
+//
+// Returns:
+//   - Usage: The resource usage.
+func (p Parser[T]) Usage() Usage {
+	return p.usage
+}
+
+// Stats returns the statistics of the last parse session.
+//
+// Returns:
+//   - Stats: The statistics.
+func (p Parser[T]) Stats() Stats {
+	return p.stats
+}
+
+// SetMaxDiagnostics sets the maximum number of errors of a parse, counting the
+// error of the result and its diagnostics. An active parser that reaches it stops
+// recovering from parse errors, and the result ends with an *ErrTooManyErrors
+// diagnostic, so that pathological inputs cannot flood the caller with messages.
+//
+// Parameters:
+//   - n: The maximum number of errors. If non-positive, there is no maximum.
+func (p *Parser[T]) SetMaxDiagnostics(n int) {
+	p.max_diags = n
+}
+
+// capped is a helper function that applies the maximum number of errors to the
+// diagnostics of a failed result.
+//
+// Parameters:
+//   - diagnostics: The diagnostics that follow the error of the result.
+//
+// Returns:
+//   - []error: The diagnostics, cut to the maximum and followed by an
+//     *ErrTooManyErrors if there were too many.
+func (p Parser[T]) capped(diagnostics []error) []error {
+	if p.max_diags <= 0 || len(diagnostics) < p.max_diags {
+		return diagnostics
+	}
+
+	return append(diagnostics[:p.max_diags-1:p.max_diags-1], NewErrTooManyErrors(p.max_diags))
+}
+
+// SetProfile sets the profile in which the ambiguities met while parsing are
+// recorded. The same profile can be shared by several parse sessions so that
+// chronic ambiguity sites stand out.
+//
+// Parameters:
+//   - profile: The ambiguity profile. If nil, ambiguities are not profiled.
+func (p *Parser[T]) SetProfile(profile *AmbiguityProfile[T]) {
+	p.profile = profile
+}
+
+// AddSyncSymbols adds synchronization symbols and, with them, enables the recovery
+// from parse errors: on an error, the tokens are discarded up to, and including,
+// the next synchronization symbol and the parse starts again right after it. The
+// error is recorded and what was parsed so far is kept as a partial forest.
+//
+// Parameters:
+//   - types: The types of the terminals to synchronize on. (e.g., the semicolon)
+func (p *Parser[T]) AddSyncSymbols(types ...T) {
+	for _, type_ := range types {
+		if !slices.Contains(p.sync, type_) {
+			p.sync = append(p.sync, type_)
+		}
+	}
+}
+
+// branch is a branch of the parse that is yet to be explored.
+type branch[T internal.TokenTyper] struct {
+	// ap is the active parser of the branch, forked at the decision that led to it.
+	ap *ActiveParser[T]
+
+	// next is the item the branch starts with. Nil for the first branch.
+	next *Item[T]
+
+	// choices are the ambiguous decisions taken along the path.
+	choices []choice[T]
+}
+
+// execute explores every branch of the parse, one at a time. At an ambiguous
+// decision, the active parser is forked for every alternative but the first one;
+// forks share the history of the active parser, so that forking takes constant
+// time.
+//
+// Returns:
+//   - iter.Seq[*ActiveParser[T]]: The successful active parsers, followed by the
+//     ones that recovered from parse errors and by the failed ones.
+func (p *Parser[T]) execute(ctx context.Context) iter.Seq[*ActiveParser[T]] {
+	return func(yield func(*ActiveParser[T]) bool) {
+		p.usage = Usage{}
+		p.stats = Stats{}
+
+		branches := []branch[T]{{ap: p.active_parser_of()}}
+		var recovered, invalids []*ActiveParser[T]
+
+		var steps int
+
+		for len(branches) > 0 {
+			b := branches[len(branches)-1]
+			branches = branches[:len(branches)-1]
+
+			ap := b.ap
+			item := b.next
+
+			for {
+				p.usage.Forks = max(p.usage.Forks, len(branches)+1)
+
+				err := p.usage.exceeded(p.limits)
+				if err == nil {
+					err = ap.overflow(p.limits.MaxDepth)
+				}
+
+				if err == nil && ctx.Err() != nil {
+					err = grm.NewErrCancelled(ctx.Err(), steps)
+				}
+
+				steps++
+
+				if err != nil {
+					ap.err = err
+					ap.possible_cause = nil
+
+					_ = yield(ap)
+
+					return
+				}
+
+				if item == nil {
+					if ap.HasError() {
+						if ap.recover() {
+							continue
+						}
+
+						if ap.HasError() {
+							invalids = append(invalids, ap)
+							p.stats.Discarded++
+						} else {
+							recovered = append(recovered, ap)
+						}
+
+						break
+					}
+
+					nexts := ap.NextEvents()
+					if len(nexts) == 0 {
+						if ap.HasError() {
+							continue
+						}
+
+						if len(ap.errs) > 0 {
+							recovered = append(recovered, ap)
+
+							break
+						}
+
+						succeed(b.choices)
+
+						if !yield(ap) {
+							return
+						}
+
+						break
+					}
+
+					p.stats.Forks += len(nexts) - 1
+
+					if len(nexts) > 1 && p.profile != nil {
+						site := p.profile.hit(nexts)
+
+						for i := len(nexts) - 1; i > 0; i-- {
+							branches = append(branches, branch[T]{
+								ap:      ap.fork(),
+								next:    nexts[i],
+								choices: append(slices.Clip(b.choices), choice[T]{site: site, idx: i}),
+							})
+						}
+
+						b.choices = append(b.choices, choice[T]{site: site, idx: 0})
+					} else {
+						for i := len(nexts) - 1; i > 0; i-- {
+							branches = append(branches, branch[T]{
+								ap:      ap.fork(),
+								next:    nexts[i],
+								choices: slices.Clip(b.choices),
+							})
+						}
+					}
+
+					item = nexts[0]
+				}
+
+				accepted := ap.WalkOne(item)
+				item = nil
+
+				if accepted {
+					if len(ap.errs) > 0 {
+						recovered = append(recovered, ap)
+
+						break
+					}
+
+					succeed(b.choices)
+
+					if !yield(ap) {
+						return
+					}
+
+					break
+				}
+			}
+		}
+
+		for _, ap := range slices.Concat(recovered, invalids) {
+			if !yield(ap) {
+				return
+			}
+		}
+	}
+}
+
+// Parse is the main function of the parser. It explores every branch of the
+// parse.
 //
 // Parameters:
 //   - tokens: The tokens to be parsed.
 //
 // Returns:
-//   - *ActiveParser[T]: The parser.
-//   - error: An error if any.
+//   - iter.Seq[*ActiveParser[T]]: The successful active parsers, followed by
+//     the ones that recovered from parse errors and by the failed ones.
+//
+// Use ParseResult to get the result of the parse instead of every branch.
 func (p *Parser[T]) Parse(tokens []*gr.Token[T]) iter.Seq[*ActiveParser[T]] {
+	return p.ParseCtx(context.Background(), tokens)
+}
+
+// ParseCtx is like Parse but stops as soon as the context is done; the active
+// parser that was running is then yielded, with an error of type
+// *grm.ErrCancelled, and no other one is.
+//
+// Parameters:
+//   - ctx: The context.
+//   - tokens: The tokens to be parsed.
+//
+// Returns:
+//   - iter.Seq[*ActiveParser[T]]: The active parsers.
+func (p *Parser[T]) ParseCtx(ctx context.Context, tokens []*gr.Token[T]) iter.Seq[*ActiveParser[T]] {
 	p.tokens = tokens
+	p.reader = nil
+
+	return p.execute(ctx)
+}
+
+// ParseResult parses the tokens and makes the result of the parse out of its
+// branches.
+//
+// Parameters:
+//   - tokens: The tokens to be parsed.
+//
+// Returns:
+//   - grm.Result[*tree.Tree[*gr.Token[T]]]: The result of the first successful
+//     branch. If every branch failed, the result of the failed branch that went
+//     the furthest; the errors it recovered from, followed by the errors of the
+//     other failed branches, are its diagnostics. If no branch succeeded but one
+//     recovered from parse errors, its partial forest with its first error; the
+//     other errors are its diagnostics. In repair mode, the error of a failed parse
+//     suggests the cheapest repair, if any.
+func (p *Parser[T]) ParseResult(tokens []*gr.Token[T]) grm.Result[*tree.Tree[*gr.Token[T]]] {
+	return p.ParseResultCtx(context.Background(), tokens)
+}
+
+// ParseResultCtx is like ParseResult but stops as soon as the context is done.
+//
+// Parameters:
+//   - ctx: The context.
+//   - tokens: The tokens to be parsed.
+//
+// Returns:
+//   - grm.Result[*tree.Tree[*gr.Token[T]]]: The result, as for ParseResult. On
+//     cancellation, the forest of the branch that was running with an error of
+//     type *grm.ErrCancelled.
+func (p *Parser[T]) ParseResultCtx(ctx context.Context, tokens []*gr.Token[T]) grm.Result[*tree.Tree[*gr.Token[T]]] {
+	p.tokens = tokens
+	p.reader = nil
+
+	return p.result(ctx)
+}
+
+// ParseReader is like ParseResult but reads the tokens from a token reader as the parse
+// goes, so that the parse runs concurrently with the producer of the tokens (see
+// gr.ChanTokenReader). Only the tokens the decisions look at are read ahead; the
+// rest of the input is read at once to recover from a parse error or to search
+// for a repair.
+//
+// Parameters:
+//   - reader: The token reader. It is read until it returns an error; io.EOF ends
+//     the token stream normally.
+//
+// Returns:
+//   - grm.Result[*tree.Tree[*gr.Token[T]]]: The result, as for ParseResult. If the
+//     reader failed, the parse fails with its error.
+func (p *Parser[T]) ParseReader(reader gr.TokenReader[T]) grm.Result[*tree.Tree[*gr.Token[T]]] {
+	return p.ParseReaderCtx(context.Background(), reader)
+}
+
+// ParseReaderCtx is like ParseReader but stops as soon as the context is done.
+// The context is only checked between two steps of the parse: a reader that
+// waits for its tokens should be given the same context (see
+// gr.NewChanTokenReader).
+//
+// Parameters:
+//   - ctx: The context.
+//   - reader: The token reader.
+//
+// Returns:
+//   - grm.Result[*tree.Tree[*gr.Token[T]]]: The result, as for ParseResultCtx.
+func (p *Parser[T]) ParseReaderCtx(ctx context.Context, reader gr.TokenReader[T]) grm.Result[*tree.Tree[*gr.Token[T]]] {
+	if reader == nil {
+		return grm.NewFailedResult[*tree.Tree[*gr.Token[T]]](nil, gcers.NewErrNilParameter("reader"))
+	}
+
+	p.tokens = nil
+	p.reader = reader
+
+	return p.result(ctx)
+}
+
+// result is a helper function that runs a parse session on the input of the
+// parser and makes its result.
+//
+// Parameters:
+//   - ctx: The context.
+//
+// Returns:
+//   - grm.Result[*tree.Tree[*gr.Token[T]]]: The result, as for ParseResultCtx.
+func (p *Parser[T]) result(ctx context.Context) grm.Result[*tree.Tree[*gr.Token[T]]] {
+	var failed []*ActiveParser[T]
+
+	for ap := range p.execute(ctx) {
+		var cancelled *grm.ErrCancelled
+
+		if errors.As(ap.err, &cancelled) {
+			return grm.NewFailedResult(ap.Forest(), error(cancelled))
+		}
+
+		if ap.HasError() {
+			failed = append(failed, ap)
+
+			continue
+		}
+
+		forest := ap.Forest()
+
+		if errs := ap.Errors(); len(errs) > 0 {
+			diagnostics := make([]error, 0, len(errs)-1)
+
+			for _, err := range errs[1:] {
+				diagnostics = append(diagnostics, err)
+			}
+
+			return grm.NewFailedResult(forest, error(errs[0]), p.capped(diagnostics)...)
+		}
+
+		if len(forest) != 1 {
+			return grm.NewFailedResult(forest, fmt.Errorf("expected exactly one root but got %d", len(forest)))
+		}
+
+		return grm.NewResult(forest[0])
+	}
+
+	if len(failed) == 0 {
+		return grm.NewFailedResult[*tree.Tree[*gr.Token[T]]](nil, errors.New("nothing was parsed"))
+	}
+
+	// The branch that went the furthest holds the largest well-formed prefix.
+	best := 0
+
+	for i, ap := range failed[1:] {
+		if ap.Shifted() > failed[best].Shifted() {
+			best = i + 1
+		}
+	}
+
+	diagnostics := make([]error, 0, len(failed)-1+len(failed[best].Errors()))
+
+	// The errors the branch recovered from come before the ones of the other branches.
+	for _, err := range failed[best].Errors() {
+		diagnostics = append(diagnostics, err)
+	}
+
+	for i, ap := range failed {
+		if i != best {
+			diagnostics = append(diagnostics, ap.Error())
+		}
+	}
+
+	err := failed[best].parsing_error()
+
+	if p.repair {
+		repair, ok := p.find_repair(ctx, p.feed.all(), failed[best].Shifted())
+		if ok {
+			err.SetSuggestion("Try to " + repair.String() + ".")
+		}
+	}
 
-	return util.Execute(p.active_parser_of)
+	return grm.NewFailedResult(failed[best].Forest(), error(err), p.capped(diagnostics)...)
 }
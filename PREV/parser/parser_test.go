@@ -0,0 +1,308 @@
+package parser
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+	grm "github.com/PlayerR9/grammar/grammar"
+)
+
+// test_type is the token type of the test grammars.
+type test_type int
+
+const (
+	tt_eof test_type = iota
+	tt_num
+	tt_plus
+	tt_lparen
+	tt_rparen
+	tt_semi
+	tt_error
+	nt_source
+	nt_expr
+	nt_term
+	nt_stmts
+	nt_stmt
+)
+
+// String implements the fmt.Stringer interface.
+func (t test_type) String() string {
+	return [...]string{"EOF", "NUM", "PLUS", "LPAREN", "RPAREN", "SEMI", "error", "Source", "Expr", "Term", "Stmts", "Stmt"}[t]
+}
+
+// IsTerminal implements the internal.TokenTyper interface.
+func (t test_type) IsTerminal() bool {
+	return t < nt_source
+}
+
+// new_test_rule_set creates the rule set of sums of numbers and parenthesized
+// sums.
+func new_test_rule_set() *RuleSet[test_type] {
+	rs := NewRuleSet[test_type]()
+
+	rs.MustMakeRule(nt_source, []test_type{nt_expr, tt_eof})
+	rs.MustMakeRule(nt_expr, []test_type{nt_expr, tt_plus, nt_term})
+	rs.MustMakeRule(nt_expr, []test_type{nt_term})
+	rs.MustMakeRule(nt_term, []test_type{tt_lparen, nt_expr, tt_rparen})
+	rs.MustMakeRule(nt_term, []test_type{tt_num})
+
+	rs.DetermineItems()
+	_ = rs.SolveConflicts()
+
+	return rs
+}
+
+// lex_test_input splits the input on spaces into tokens and appends the EOF token.
+func lex_test_input(input string) []*gr.Token[test_type] {
+	types := map[string]test_type{"+": tt_plus, "(": tt_lparen, ")": tt_rparen, ";": tt_semi}
+
+	var tokens []*gr.Token[test_type]
+
+	for _, word := range strings.Fields(input) {
+		type_, ok := types[word]
+		if !ok {
+			type_ = tt_num
+		}
+
+		tokens = append(tokens, gr.NewToken(type_, word, nil))
+	}
+
+	return append(tokens, gr.NewToken(tt_eof, "", nil))
+}
+
+// sexpr_of returns the token tree rooted at tk in the form "(Type child...)",
+// with the data of the leaves that have some instead of their type.
+func sexpr_of(tk *gr.Token[test_type]) string {
+	if tk.FirstChild == nil && tk.Data != "" {
+		return tk.Data
+	} else if tk.FirstChild == nil {
+		return tk.Type.String()
+	}
+
+	elems := []string{tk.Type.String()}
+
+	for child := range tk.Child() {
+		elems = append(elems, sexpr_of(child))
+	}
+
+	return "(" + strings.Join(elems, " ") + ")"
+}
+
+func TestParse(t *testing.T) {
+	p, err := NewParser(new_test_rule_set())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"1", "(Source (Expr (Term 1)) EOF)"},
+		{"1 + 2", "(Source (Expr (Expr (Term 1)) + (Term 2)) EOF)"},
+		{"( 1 + 2 ) + 3", "(Source (Expr (Expr (Term ( (Expr (Expr (Term 1)) + (Term 2)) ))) + (Term 3)) EOF)"},
+	}
+
+	for _, test := range tests {
+		var got []string
+
+		for ap := range p.Parse(lex_test_input(test.input)) {
+			if ap.HasError() {
+				continue
+			}
+
+			forest := ap.Forest()
+			if len(forest) != 1 {
+				t.Fatalf("input %q: expected 1 tree, got %d", test.input, len(forest))
+			}
+
+			got = append(got, sexpr_of(forest[0].Root()))
+		}
+
+		if len(got) != 1 || got[0] != test.want {
+			t.Errorf("input %q: expected [%s], got %q", test.input, test.want, got)
+		}
+	}
+}
+
+func TestParseFailure(t *testing.T) {
+	p, err := NewParser(new_test_rule_set())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, input := range []string{"1 +", "( 1", "+"} {
+		var parsed int
+
+		for ap := range p.Parse(lex_test_input(input)) {
+			if !ap.HasError() {
+				parsed++
+			}
+		}
+
+		if parsed != 0 {
+			t.Errorf("input %q: expected no successful parse, got %d", input, parsed)
+		}
+	}
+}
+
+func TestParseDecisionPanic(t *testing.T) {
+	p, err := NewParserWithFunc(func(ap *ActiveParser[test_type]) ([]*Item[test_type], error) {
+		panic("boom")
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	res := p.ParseResult(lex_test_input("1"))
+
+	var panic_err *grm.ErrPanic
+
+	if !errors.As(res.Err, &panic_err) {
+		t.Fatalf("expected an *ErrPanic, got %v", res.Err)
+	}
+
+	if panic_err.Value != "boom" {
+		t.Errorf("expected the panic value %q, got %v", "boom", panic_err.Value)
+	}
+}
+
+func TestParseFrames(t *testing.T) {
+	p, err := NewParser(new_test_rule_set())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		input string
+		want  []test_type
+	}{
+		{"( 1 + ( 2 + ) )", []test_type{nt_expr, nt_term, nt_expr, nt_term}},
+		{"( ( 1 +", []test_type{nt_expr, nt_term, nt_term}},
+	}
+
+	for _, test := range tests {
+		var got []test_type
+
+		for ap := range p.Parse(lex_test_input(test.input)) {
+			if !ap.HasError() {
+				t.Fatalf("input %q: expected an error, got none", test.input)
+			}
+
+			got = ap.Frames(0)
+		}
+
+		if !slices.Equal(got, test.want) {
+			t.Errorf("input %q: expected the frames %v, got %v", test.input, test.want, got)
+		}
+	}
+
+	res := p.ParseResult(lex_test_input("( 1 + ( 2 + ) )"))
+
+	var parsing_err *ErrParsing
+
+	if !errors.As(res.Err, &parsing_err) {
+		t.Fatalf("expected an *ErrParsing, got %v", res.Err)
+	}
+
+	want := []string{"Expr", "Term", "Expr"}
+
+	if !slices.Equal(parsing_err.Frames, want) {
+		t.Errorf("expected the frames %v, got %v", want, parsing_err.Frames)
+	}
+
+	const want_prefix = `while parsing "Expr" in "Term" in "Expr": `
+
+	if msg := parsing_err.Error(); !strings.HasPrefix(msg, want_prefix) {
+		t.Errorf("expected the message to start with %q, got %q", want_prefix, msg)
+	}
+}
+
+// new_stmt_rule_set creates the rule set of lists of statements made of a number
+// followed by a semicolon.
+func new_stmt_rule_set() *RuleSet[test_type] {
+	rs := NewRuleSet[test_type]()
+
+	rs.MustMakeRule(nt_source, []test_type{nt_stmts, tt_eof})
+	rs.MustMakeRule(nt_stmts, []test_type{nt_stmts, nt_stmt})
+	rs.MustMakeRule(nt_stmts, []test_type{nt_stmt})
+	rs.MustMakeRule(nt_stmt, []test_type{tt_num, tt_semi})
+
+	return rs
+}
+
+func TestParseSyncRecovery(t *testing.T) {
+	rs := new_stmt_rule_set()
+
+	rs.DetermineItems()
+	_ = rs.SolveConflicts()
+
+	p, err := NewParser(rs)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	p.AddSyncSymbols(tt_semi)
+
+	res := p.ParseResult(lex_test_input("1 ; 2 2 ; 3 ;"))
+
+	var unexpected *gr.ErrUnexpectedToken[test_type]
+
+	if !errors.As(res.Err, &unexpected) {
+		t.Fatalf("expected an *ErrUnexpectedToken, got %v", res.Err)
+	}
+
+	if unexpected.Got == nil || *unexpected.Got != tt_num || !slices.Equal(unexpected.Expecteds, []test_type{tt_semi}) {
+		t.Errorf("expected %q instead of the stray number, got %v", tt_semi, unexpected)
+	}
+
+	if len(res.Diagnostics) != 0 {
+		t.Errorf("expected no other error, got %v", res.Diagnostics)
+	}
+
+	want := []string{"(Stmts (Stmt 1 ;))", "2", "(Source (Stmts (Stmt 3 ;)) EOF)"}
+
+	var got []string
+
+	for _, tree := range res.Forest {
+		got = append(got, sexpr_of(tree.Root()))
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("expected the forest %q, got %q", want, got)
+	}
+}
+
+func TestParseErrorProduction(t *testing.T) {
+	rs := new_stmt_rule_set()
+	rs.MustMakeRuleWithError(nt_stmt, tt_error, []test_type{tt_semi})
+
+	rs.DetermineItems()
+	_ = rs.SolveConflicts()
+
+	p, err := NewParser(rs)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	res := p.ParseResult(lex_test_input("1 ; 2 2 ; 3 ;"))
+
+	var unexpected *gr.ErrUnexpectedToken[test_type]
+
+	if !errors.As(res.Err, &unexpected) {
+		t.Fatalf("expected an *ErrUnexpectedToken, got %v", res.Err)
+	}
+
+	const want = "(Source (Stmts (Stmts (Stmts (Stmt 1 ;)) (Stmt (error 2 2) ;)) (Stmt 3 ;)) EOF)"
+
+	if len(res.Forest) != 1 {
+		t.Fatalf("expected 1 tree, got %d", len(res.Forest))
+	}
+
+	if got := sexpr_of(res.Forest[0].Root()); got != want {
+		t.Errorf("expected the tree %s, got %s", want, got)
+	}
+}
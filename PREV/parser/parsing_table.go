@@ -2,16 +2,18 @@ package parser
 
 import (
 	"fmt"
+	"iter"
 	"slices"
 
 	"github.com/PlayerR9/go-commons/cmp"
+	gcers "github.com/PlayerR9/go-commons/errors"
 	"github.com/PlayerR9/go-commons/set"
 	"github.com/PlayerR9/grammar/PREV/internal"
 	"github.com/PlayerR9/listlike/queue"
 )
 
-// parse_table is the parsing table.
-type parse_table[T internal.TokenTyper] struct {
+// ParseTable is the parsing table.
+type ParseTable[T internal.TokenTyper] struct {
 	// symbols is the set of all symbols in the grammar.
 	symbols *cmp.Set[T]
 
@@ -29,10 +31,24 @@ type parse_table[T internal.TokenTyper] struct {
 
 	// goto_table is the goto table.
 	goto_table map[*State[T]]map[T]*State[T]
+
+	// reductions are the rules reduced by the reduce and accept actions. Only set
+	// by NewLALRTable.
+	reductions map[*State[T]]map[T]*Rule[T]
+
+	// first are the FIRST sets of the symbols. Only set by NewLALRTable.
+	first map[T]*cmp.Set[T]
+
+	// follow are the FOLLOW sets of the non-terminals. Only set by NewLALRTable.
+	follow map[T]*cmp.Set[T]
+
+	// conflicts are the conflicts found while making the table. Only set by
+	// NewLALRTable.
+	conflicts []Conflict[T]
 }
 
 // make_symbols is a helper function that makes the symbols set.
-func (pt *parse_table[T]) make_symbols() {
+func (pt *ParseTable[T]) make_symbols() {
 	// dbg.AssertNotNil(pt, "pt")
 	// dbg.AssertNotNil(pt.rule_set, "pt.rule_set")
 	// dbg.Assert(pt.symbols.IsEmpty(), "symbols is not empty")
@@ -44,7 +60,7 @@ func (pt *parse_table[T]) make_symbols() {
 }
 
 // make_items is a helper function that makes the items set.
-func (pt *parse_table[T]) make_items() {
+func (pt *ParseTable[T]) make_items() {
 	// dbg.AssertNotNil(pt, "pt")
 	// dbg.AssertNotNil(pt.rule_set, "pt.rule_set")
 	// dbg.Assert(pt.item_set.IsEmpty(), "item_set is not empty")
@@ -65,9 +81,9 @@ func (pt *parse_table[T]) make_items() {
 //   - rules: The rules of the grammar.
 //
 // Returns:
-//   - *parse_table[T]: The new parse table. Never returns nil.
-func new_parse_table[T internal.TokenTyper](rules []*Rule[T]) *parse_table[T] {
-	pt := &parse_table[T]{
+//   - *ParseTable[T]: The new parse table. Never returns nil.
+func new_parse_table[T internal.TokenTyper](rules []*Rule[T]) *ParseTable[T] {
+	pt := &ParseTable[T]{
 		symbols:  cmp.NewSet[T](),
 		rule_set: set.NewSetWithItems(rules),
 		item_set: set.NewSet[*Item[T]](),
@@ -79,6 +95,122 @@ func new_parse_table[T internal.TokenTyper](rules []*Rule[T]) *parse_table[T] {
 	return pt
 }
 
+// NewParseTable creates a new parse table from the rules of the given rule set.
+//
+// Parameters:
+//   - rule_set: The rule set.
+//
+// Returns:
+//   - *ParseTable[T]: The new parse table.
+//   - error: An error if the rule set is nil or the table could not be initialized.
+func NewParseTable[T internal.TokenTyper](rule_set *RuleSet[T]) (*ParseTable[T], error) {
+	if rule_set == nil {
+		return nil, gcers.NewErrNilParameter("rule_set")
+	}
+
+	pt := new_parse_table(rule_set.rules)
+
+	err := pt.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return pt, nil
+}
+
+// Items returns an iterator over every item of the grammar; that is, every rule
+// with every position of the dot.
+//
+// Returns:
+//   - iter.Seq[*Item[T]]: The iterator. Never returns nil.
+func (pt ParseTable[T]) Items() iter.Seq[*Item[T]] {
+	return pt.item_set.All()
+}
+
+// Rules returns an iterator over the rules of the grammar.
+//
+// Returns:
+//   - iter.Seq[*Rule[T]]: The iterator. Never returns nil.
+func (pt ParseTable[T]) Rules() iter.Seq[*Rule[T]] {
+	return pt.rule_set.All()
+}
+
+// States returns an iterator over the states of the automaton.
+//
+// Returns:
+//   - iter.Seq[*State[T]]: The iterator. Never returns nil.
+func (pt ParseTable[T]) States() iter.Seq[*State[T]] {
+	return slices.Values(pt.states)
+}
+
+// Action returns the action to perform in the given state on the given lookahead.
+//
+// Parameters:
+//   - state: The state.
+//   - lookahead: The lookahead.
+//
+// Returns:
+//   - internal.ActionType: The action. ActErrorType if there is none.
+//   - *Rule[T]: The rule to reduce, for reduce and accept actions of tables made
+//     by NewLALRTable. Nil otherwise.
+func (pt ParseTable[T]) Action(state *State[T], lookahead T) (internal.ActionType, *Rule[T]) {
+	act, ok := pt.action_table[state][lookahead]
+	if !ok {
+		return internal.ActErrorType, nil
+	}
+
+	return act, pt.reductions[state][lookahead]
+}
+
+// Next returns the state reached from the given state on the given symbol; that is,
+// the target of a shift, for terminals, or of a goto, for non-terminals.
+//
+// Parameters:
+//   - state: The state.
+//   - symbol: The symbol.
+//
+// Returns:
+//   - *State[T]: The next state.
+//   - bool: True if there is a next state, false otherwise.
+func (pt ParseTable[T]) Next(state *State[T], symbol T) (*State[T], bool) {
+	next := pt.goto_table[state][symbol]
+	return next, next != nil
+}
+
+// First returns the FIRST set of the given symbol; that is, the terminals that
+// can start it.
+//
+// Parameters:
+//   - symbol: The symbol.
+//
+// Returns:
+//   - []T: The terminals, sorted. Nil if the table was not made by NewLALRTable.
+func (pt ParseTable[T]) First(symbol T) []T {
+	set, ok := pt.first[symbol]
+	if !ok {
+		return nil
+	}
+
+	return set.Slice()
+}
+
+// Follow returns the FOLLOW set of the given non-terminal; that is, the terminals
+// that can follow it.
+//
+// Parameters:
+//   - symbol: The non-terminal.
+//
+// Returns:
+//   - []T: The terminals, sorted. Nil if the table was not made by NewLALRTable.
+func (pt ParseTable[T]) Follow(symbol T) []T {
+	set, ok := pt.follow[symbol]
+	if !ok {
+		return nil
+	}
+
+	return set.Slice()
+}
+
 // get_items_with_lhs returns all items with the given lhs.
 //
 // Parameters:
@@ -86,7 +218,7 @@ func new_parse_table[T internal.TokenTyper](rules []*Rule[T]) *parse_table[T] {
 //
 // Returns:
 //   - []*Item[T]: The items with the given lhs.
-func (pt parse_table[T]) get_items_with_lhs(lhs T) []*Item[T] {
+func (pt ParseTable[T]) get_items_with_lhs(lhs T) []*Item[T] {
 	var items []*Item[T]
 
 	for item := range pt.item_set.All() {
@@ -105,7 +237,7 @@ func (pt parse_table[T]) get_items_with_lhs(lhs T) []*Item[T] {
 //
 // Returns:
 //   - []*Item[T]: The closure of the item set.
-func (pt parse_table[T]) closure(seed []*Item[T]) []*Item[T] {
+func (pt ParseTable[T]) closure(seed []*Item[T]) []*Item[T] {
 	if len(seed) == 0 {
 		return nil
 	}
@@ -169,7 +301,7 @@ func (pt ParseTable[T]) Goto(item *Item[T], rhs T) ([]*Item[T], error) {
 //
 // Returns:
 //   - error: An error if the closure failed.
-func (pt *parse_table[T]) make_all_states() error {
+func (pt *ParseTable[T]) make_all_states() error {
 	start_symbol := T(0)
 
 	initial_items := pt.get_items_with_lhs(start_symbol)
@@ -230,7 +362,7 @@ func (pt *parse_table[T]) make_all_states() error {
 //
 // Returns:
 //   - error: An error if the initialization failed.
-func (pt *parse_table[T]) init() error {
+func (pt *ParseTable[T]) init() error {
 	err := pt.make_all_states()
 	if err != nil {
 		return err
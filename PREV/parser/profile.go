@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"slices"
+
+	"github.com/PlayerR9/grammar/PREV/internal"
+)
+
+// AmbiguitySite is a decision point that produced more than one item.
+type AmbiguitySite[T internal.TokenTyper] struct {
+	// Alternatives are the items that were produced at the decision point.
+	Alternatives []*Item[T]
+
+	// Hits is the number of times the decision point was reached.
+	Hits int
+
+	// Successes is, for each alternative, the number of times that a parse
+	// that took it eventually succeeded.
+	Successes []int
+}
+
+// Chronic checks whether the site is a chronic ambiguity site; that is, a site
+// reached at least min_hits times where no single alternative accounts for all
+// of the successful parses.
+//
+// Parameters:
+//   - min_hits: The minimum number of hits.
+//
+// Returns:
+//   - bool: True if the site is chronic, false otherwise.
+func (site AmbiguitySite[T]) Chronic(min_hits int) bool {
+	if site.Hits < min_hits {
+		return false
+	}
+
+	var winners int
+
+	for _, count := range site.Successes {
+		if count > 0 {
+			winners++
+		}
+	}
+
+	return winners != 1
+}
+
+// AmbiguityProfile records, across parse sessions, the decision points that
+// produced multiple items and how often each alternative eventually succeeded.
+// Grammar authors can use it to find where lookaheads or precedences are missing.
+type AmbiguityProfile[T internal.TokenTyper] struct {
+	// sites are the recorded sites, in order of discovery.
+	sites []*AmbiguitySite[T]
+}
+
+// NewAmbiguityProfile creates a new, empty, ambiguity profile.
+//
+// Returns:
+//   - *AmbiguityProfile[T]: The new profile. Never returns nil.
+func NewAmbiguityProfile[T internal.TokenTyper]() *AmbiguityProfile[T] {
+	return &AmbiguityProfile[T]{}
+}
+
+// hit records that a decision point produced the given items.
+//
+// Parameters:
+//   - items: The items produced by the decision point.
+//
+// Returns:
+//   - *AmbiguitySite[T]: The site of the decision point. Never returns nil.
+func (ap *AmbiguityProfile[T]) hit(items []*Item[T]) *AmbiguitySite[T] {
+	// dbg.AssertThat("len(items)", dbg.NewOrderedAssert(len(items)).GreaterThan(1)).Panic()
+
+	idx := slices.IndexFunc(ap.sites, func(site *AmbiguitySite[T]) bool {
+		return slices.Equal(site.Alternatives, items)
+	})
+
+	var site *AmbiguitySite[T]
+
+	if idx == -1 {
+		site = &AmbiguitySite[T]{
+			Alternatives: slices.Clone(items),
+			Successes:    make([]int, len(items)),
+		}
+
+		ap.sites = append(ap.sites, site)
+	} else {
+		site = ap.sites[idx]
+	}
+
+	site.Hits++
+
+	return site
+}
+
+// Sites returns the recorded sites, the most hit first.
+//
+// Returns:
+//   - []*AmbiguitySite[T]: The recorded sites.
+func (ap AmbiguityProfile[T]) Sites() []*AmbiguitySite[T] {
+	sites := slices.Clone(ap.sites)
+
+	slices.SortStableFunc(sites, func(a, b *AmbiguitySite[T]) int {
+		return b.Hits - a.Hits
+	})
+
+	return sites
+}
+
+// Report returns a warning for each chronic ambiguity site, the most hit first.
+//
+// Parameters:
+//   - min_hits: The minimum number of hits for a site to be reported.
+//
+// Returns:
+//   - []error: The warnings. Each of them is of type *WarnAmbiguity.
+func (ap AmbiguityProfile[T]) Report(min_hits int) []error {
+	var warnings []error
+
+	for _, site := range ap.Sites() {
+		if site.Chronic(min_hits) {
+			warnings = append(warnings, NewWarnAmbiguity(site))
+		}
+	}
+
+	return warnings
+}
+
+// Reset removes all the recorded sites.
+func (ap *AmbiguityProfile[T]) Reset() {
+	ap.sites = nil
+}
+
+// choice is a decision taken by a branch of the parse.
+type choice[T internal.TokenTyper] struct {
+	// site is the site of the decision.
+	site *AmbiguitySite[T]
+
+	// idx is the index of the alternative that was taken.
+	idx int
+}
+
+// succeed records that the parse that took the given choices succeeded.
+//
+// Parameters:
+//   - choices: The choices of the successful parse.
+func succeed[T internal.TokenTyper](choices []choice[T]) {
+	for _, c := range choices {
+		c.site.Successes[c.idx]++
+	}
+}
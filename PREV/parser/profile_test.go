@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+
+	ogr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+func TestAmbiguitySiteChronic(t *testing.T) {
+	tests := []struct {
+		name      string
+		hits      int
+		successes []int
+		want      bool
+	}{
+		{name: "too few hits", hits: 1, successes: []int{1, 1}, want: false},
+		{name: "one winner", hits: 3, successes: []int{3, 0}, want: false},
+		{name: "two winners", hits: 3, successes: []int{2, 1}, want: true},
+		{name: "no winner", hits: 3, successes: []int{0, 0}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := AmbiguitySite[test_type]{
+				Hits:      tt.hits,
+				Successes: tt.successes,
+			}
+
+			if got := site.Chronic(2); got != tt.want {
+				t.Errorf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProfile(t *testing.T) {
+	rs := new_test_rule_set()
+
+	// Every decision is offered twice, so both alternatives of every site succeed.
+	p, err := NewParserWithFunc(func(ap *ActiveParser[test_type]) ([]*Item[test_type], error) {
+		items, err := rs.Decision(ap)
+		if err != nil || len(items) == 0 {
+			return items, err
+		}
+
+		return []*Item[test_type]{items[0], items[0]}, nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	profile := NewAmbiguityProfile[test_type]()
+
+	p.SetProfile(profile)
+
+	for range p.Parse(lex_test_input("1 + 2")) {
+	}
+
+	sites := profile.Sites()
+	if len(sites) == 0 {
+		t.Fatal("expected some ambiguity sites, got none")
+	}
+
+	for _, count := range sites[0].Successes {
+		if count == 0 {
+			t.Errorf("expected both alternatives to succeed, got %v", sites[0].Successes)
+		}
+	}
+
+	for i := 1; i < len(sites); i++ {
+		if sites[i-1].Hits < sites[i].Hits {
+			t.Errorf("expected the sites to be sorted by hits, got %d before %d", sites[i-1].Hits, sites[i].Hits)
+		}
+	}
+
+	warnings := profile.Report(1)
+	if len(warnings) != len(sites) {
+		t.Fatalf("expected %d warnings, got %d", len(sites), len(warnings))
+	}
+
+	var warn *WarnAmbiguity[test_type]
+
+	if !errors.As(warnings[0], &warn) {
+		t.Fatalf("expected a *WarnAmbiguity, got %T", warnings[0])
+	}
+
+	if warn.Site != sites[0] {
+		t.Errorf("expected the warning of the most hit site, got %v", warn.Site)
+	}
+
+	code, ok := ogr.CodeOf(warnings[0])
+	if !ok || code != ogr.CodeAmbiguousResolved {
+		t.Errorf("expected the code %s, got %s", ogr.CodeAmbiguousResolved, code)
+	}
+
+	profile.Reset()
+
+	if sites := profile.Sites(); len(sites) != 0 {
+		t.Errorf("expected no site after Reset, got %d", len(sites))
+	}
+}
@@ -0,0 +1,360 @@
+package parser
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+	"github.com/PlayerR9/grammar/PREV/internal"
+)
+
+// RepairWindow is the number of tokens a repaired parse must go past the original
+// error for the repair to be accepted; unless it reaches the end of the input.
+const RepairWindow int = 3
+
+// RepairBudget is the maximum number of candidate repairs that are parsed in
+// search of the cheapest one. The pairs of edits grow with the square of the
+// terminals of the grammar, so the search is cut short on large grammars.
+const RepairBudget int = 1000
+
+// EditKind is the kind of an edit of the token stream.
+type EditKind int
+
+const (
+	// EditDelete deletes a token.
+	EditDelete EditKind = iota
+
+	// EditInsert inserts a token.
+	EditInsert
+
+	// EditReplace replaces a token with another one.
+	EditReplace
+)
+
+// String implements the fmt.Stringer interface.
+func (k EditKind) String() string {
+	switch k {
+	case EditDelete:
+		return "delete"
+	case EditInsert:
+		return "insert"
+	case EditReplace:
+		return "replace"
+	default:
+		return "EditKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// TokenEdit is an edit of the token stream.
+type TokenEdit[T internal.TokenTyper] struct {
+	// Kind is the kind of the edit.
+	Kind EditKind
+
+	// Pos is the index, in the original token stream, of the token the edit applies
+	// to. Insertions take place before it.
+	Pos int
+
+	// Type is the type of the inserted token or of the replacement. Unused for
+	// deletions.
+	Type T
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Format:
+//
+//	"delete token <pos>"
+//	"insert <type> before token <pos>"
+//	"replace token <pos> with <type>"
+func (e TokenEdit[T]) String() string {
+	pos := strconv.Itoa(e.Pos)
+
+	switch e.Kind {
+	case EditInsert:
+		return "insert " + strconv.Quote(e.Type.String()) + " before token " + pos
+	case EditReplace:
+		return "replace token " + pos + " with " + strconv.Quote(e.Type.String())
+	default:
+		return "delete token " + pos
+	}
+}
+
+// Repair is a sequence of edits of the token stream that lets a failed parse go on.
+type Repair[T internal.TokenTyper] struct {
+	// Edits are the edits, sorted by position.
+	Edits []TokenEdit[T]
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Format:
+//
+//	"<edit>, then <edit>"
+func (r Repair[T]) String() string {
+	elems := make([]string, 0, len(r.Edits))
+
+	for _, edit := range r.Edits {
+		elems = append(elems, edit.String())
+	}
+
+	return strings.Join(elems, ", then ")
+}
+
+// Cost returns the cost of the repair; that is, its number of edits.
+//
+// Returns:
+//   - int: The cost.
+func (r Repair[T]) Cost() int {
+	return len(r.Edits)
+}
+
+// apply is a helper function that applies the repair to a token stream.
+//
+// Parameters:
+//   - tokens: The token stream. It is not modified.
+//
+// Returns:
+//   - []*gr.Token[T]: The repaired token stream.
+func (r Repair[T]) apply(tokens []*gr.Token[T]) []*gr.Token[T] {
+	repaired := make([]*gr.Token[T], 0, len(tokens)+len(r.Edits))
+
+	edits := r.Edits
+
+	for i, tk := range tokens {
+		keep := true
+
+		for len(edits) > 0 && edits[0].Pos == i {
+			edit := edits[0]
+			edits = edits[1:]
+
+			switch edit.Kind {
+			case EditInsert:
+				repaired = append(repaired, gr.NewToken(edit.Type, "", nil))
+			case EditReplace:
+				repaired = append(repaired, gr.NewToken(edit.Type, tk.Data, nil))
+				keep = false
+			case EditDelete:
+				keep = false
+			}
+		}
+
+		if keep {
+			repaired = append(repaired, tk)
+		}
+	}
+
+	return repaired
+}
+
+// SetRepair enables or disables the repair mode. In repair mode, when every branch
+// of a parse fails, the cheapest repair of the token stream (see Repair) is
+// suggested by the error of the result.
+//
+// Parameters:
+//   - enabled: True to enable the repair mode, false to disable it.
+func (p *Parser[T]) SetRepair(enabled bool) {
+	p.repair = enabled
+}
+
+// Repair searches the cheapest sequence of at most two edits (insertions, deletions
+// or replacements of a token) around the first syntax error of the tokens that lets
+// the parse go on.
+//
+// Parameters:
+//   - tokens: The tokens to be parsed. They are not modified.
+//
+// Returns:
+//   - *Repair[T]: The cheapest repair. Among repairs of the same cost, the one that
+//     lets the parse go the furthest.
+//   - bool: False if the parse succeeds or if no repair was found, true otherwise.
+//
+// At most RepairBudget candidates are tried.
+func (p *Parser[T]) Repair(tokens []*gr.Token[T]) (*Repair[T], bool) {
+	return p.RepairCtx(context.Background(), tokens)
+}
+
+// RepairCtx is like Repair but stops searching as soon as the context is done.
+//
+// Parameters:
+//   - ctx: The context.
+//   - tokens: The tokens to be parsed. They are not modified.
+//
+// Returns:
+//   - *Repair[T]: The cheapest repair found before the context was done.
+//   - bool: False if the parse succeeds or if no repair was found, true otherwise.
+func (p *Parser[T]) RepairCtx(ctx context.Context, tokens []*gr.Token[T]) (*Repair[T], bool) {
+	if p == nil || p.rule_set == nil {
+		return nil, false
+	}
+
+	shifted, ok := p.probe().furthest(ctx, tokens)
+	if ok {
+		return nil, false
+	}
+
+	return p.find_repair(ctx, tokens, shifted)
+}
+
+// probe is a helper function that returns a copy of the parser that does not
+// recover from errors, does not profile ambiguities and does not search repairs.
+//
+// Returns:
+//   - *Parser[T]: The copy. Never returns nil.
+func (p Parser[T]) probe() *Parser[T] {
+	rs := *p.rule_set
+	rs.error_rules = nil
+
+	p.rule_set = &rs
+	p.sync = nil
+	p.profile = nil
+	p.repair = false
+
+	return &p
+}
+
+// furthest is a helper function that parses the tokens and tells how far the
+// parse went.
+//
+// Parameters:
+//   - ctx: The context.
+//   - tokens: The tokens to be parsed.
+//
+// Returns:
+//   - int: The number of tokens shifted by the branch that went the furthest.
+//   - bool: True if a branch succeeded, false otherwise.
+func (p *Parser[T]) furthest(ctx context.Context, tokens []*gr.Token[T]) (int, bool) {
+	var shifted int
+
+	for ap := range p.ParseCtx(ctx, tokens) {
+		if !ap.HasError() {
+			return ap.Shifted(), true
+		}
+
+		shifted = max(shifted, ap.Shifted())
+	}
+
+	return shifted, false
+}
+
+// find_repair is a helper function that searches the cheapest repair of a failed
+// parse. Edits are only tried on the tokens around the error, and at most
+// RepairBudget candidates are parsed.
+//
+// Parameters:
+//   - ctx: The context. The search stops as soon as it is done.
+//   - tokens: The tokens that were parsed.
+//   - shifted: The number of tokens shifted by the failed parse.
+//
+// Returns:
+//   - *Repair[T]: The cheapest repair.
+//   - bool: True if a repair was found, false otherwise.
+func (p *Parser[T]) find_repair(ctx context.Context, tokens []*gr.Token[T], shifted int) (*Repair[T], bool) {
+	if len(tokens) == 0 || p.rule_set.symbols == nil {
+		return nil, false
+	}
+
+	probe := p.probe()
+
+	var terminals []T
+
+	for symbol := range probe.rule_set.symbols.All() {
+		if symbol != T(0) && symbol.IsTerminal() {
+			terminals = append(terminals, symbol)
+		}
+	}
+
+	slices.Sort(terminals)
+
+	// The edits are tried on the two tokens before the error, the error and the
+	// token after it.
+	last := min(shifted, len(tokens)-1)
+
+	var singles []TokenEdit[T]
+
+	for pos := max(last-3, 0); pos <= last; pos++ {
+		for _, type_ := range terminals {
+			singles = append(singles, TokenEdit[T]{Kind: EditInsert, Pos: pos, Type: type_})
+		}
+
+		if tokens[pos].Type == T(0) {
+			continue
+		}
+
+		singles = append(singles, TokenEdit[T]{Kind: EditDelete, Pos: pos})
+
+		for _, type_ := range terminals {
+			if type_ != tokens[pos].Type {
+				singles = append(singles, TokenEdit[T]{Kind: EditReplace, Pos: pos, Type: type_})
+			}
+		}
+	}
+
+	remaining := len(tokens) - shifted
+	budget := RepairBudget
+
+	// try returns the candidate that lets the parse go the furthest, if any lets it
+	// go on. When the failed parse reached the end of the input, the candidate must
+	// make the parse succeed.
+	try := func(candidates []Repair[T]) (*Repair[T], bool) {
+		var best *Repair[T]
+		best_left := 0
+
+		for i, candidate := range candidates {
+			if budget == 0 || ctx.Err() != nil {
+				break
+			}
+
+			budget--
+
+			repaired := candidate.apply(tokens)
+
+			n, ok := probe.furthest(ctx, repaired)
+			if ctx.Err() != nil {
+				break
+			}
+
+			left := len(repaired) - n
+			if !ok && (left >= remaining || left > 0 && left > remaining-RepairWindow) {
+				continue
+			}
+
+			if best == nil || left < best_left {
+				best = &candidates[i]
+				best_left = left
+			}
+		}
+
+		return best, best != nil
+	}
+
+	candidates := make([]Repair[T], 0, len(singles))
+
+	for _, edit := range singles {
+		candidates = append(candidates, Repair[T]{Edits: []TokenEdit[T]{edit}})
+	}
+
+	best, ok := try(candidates)
+	if ok {
+		return best, true
+	}
+
+	candidates = candidates[:0]
+
+	for i, first := range singles {
+		for j, second := range singles {
+			if len(candidates) == budget {
+				break
+			}
+
+			if second.Pos < first.Pos || second.Pos == first.Pos && (i == j || first.Kind != EditInsert || second.Kind != EditInsert) {
+				continue
+			}
+
+			candidates = append(candidates, Repair[T]{Edits: []TokenEdit[T]{first, second}})
+		}
+	}
+
+	return try(candidates)
+}
@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRepair(t *testing.T) {
+	p, err := NewParser(new_test_rule_set())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"1 +", "delete token 1"},
+		{"1 2", "delete token 0"},
+		{"( 1", "delete token 0"},
+	}
+
+	for _, test := range tests {
+		repair, ok := p.Repair(lex_test_input(test.input))
+		if !ok {
+			t.Errorf("input %q: expected a repair, got none", test.input)
+		} else if got := repair.String(); got != test.want {
+			t.Errorf("input %q: expected the repair %q, got %q", test.input, test.want, got)
+		}
+	}
+
+	if repair, ok := p.Repair(lex_test_input("1 + 2")); ok {
+		t.Errorf("expected no repair of a valid input, got %q", repair)
+	}
+}
+
+func TestRepairCtx(t *testing.T) {
+	p, err := NewParser(new_test_rule_set())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if repair, ok := p.RepairCtx(ctx, lex_test_input("1 +")); ok {
+		t.Errorf("expected no repair once the context is done, got %q", repair)
+	}
+}
+
+func TestParseResultRepair(t *testing.T) {
+	p, err := NewParser(new_test_rule_set())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	p.SetRepair(true)
+
+	res := p.ParseResult(lex_test_input("1 +"))
+
+	var parsing_err *ErrParsing
+
+	if !errors.As(res.Err, &parsing_err) {
+		t.Fatalf("expected an *ErrParsing, got %v", res.Err)
+	}
+
+	const want = "Try to delete token 1."
+
+	if parsing_err.Suggestion != want {
+		t.Errorf("expected the suggestion %q, got %q", want, parsing_err.Suggestion)
+	}
+
+	if msg := parsing_err.Error(); !strings.HasSuffix(msg, ". "+want) {
+		t.Errorf("expected the message to end with the suggestion, got %q", msg)
+	}
+}
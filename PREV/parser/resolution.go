@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+
+	ogr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+	"github.com/PlayerR9/grammar/PREV/internal"
+)
+
+// Resolution is a conflict between items and how it was resolved.
+type Resolution[T internal.TokenTyper] struct {
+	// Symbol is the symbol on which the items were in conflict.
+	Symbol T
+
+	// Items are the items that were in conflict. After the resolution, their
+	// lookbehinds and lookaheads tell them apart.
+	Items []*Item[T]
+
+	// Strategy is the strategy that resolved the conflict. Unresolved if none did.
+	Strategy Strategy
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Format:
+//
+//	<symbol>: <strategy>
+//		<item>
+//		...
+func (r Resolution[T]) String() string {
+	var builder strings.Builder
+
+	builder.WriteString(r.Symbol.String())
+	builder.WriteString(": ")
+	builder.WriteString(r.Strategy.String())
+
+	for _, item := range r.Items {
+		builder.WriteString("\n\t")
+		builder.WriteString(item.String())
+	}
+
+	return builder.String()
+}
+
+// Code returns the diagnostic code of the resolution.
+//
+// Returns:
+//   - ogr.Code: The code of resolved ambiguities.
+//   - bool: False if the conflict was not resolved, true otherwise.
+func (r Resolution[T]) Code() (ogr.Code, bool) {
+	if r.Strategy == Unresolved {
+		return "", false
+	}
+
+	return ogr.CodeAmbiguousResolved, true
+}
+
+// Resolutions returns every conflict found by the last call to SolveConflicts,
+// along with the strategy that resolved it; so that grammar authors can check
+// that each automatic resolution matches their intent.
+//
+// Returns:
+//   - []Resolution[T]: The resolutions, ordered by symbol.
+func (rs RuleSet[T]) Resolutions() []Resolution[T] {
+	return slices.Clone(rs.resolution_log)
+}
+
+// ResolutionReport returns a human-readable report of the resolutions of the last
+// call to SolveConflicts. The conflicts that were resolved are prefixed by the
+// code of the warning; that is, "[W0201] ".
+//
+// Returns:
+//   - string: The report. Empty if there were no conflicts.
+func (rs RuleSet[T]) ResolutionReport() string {
+	elems := make([]string, 0, len(rs.resolution_log))
+
+	for _, res := range rs.resolution_log {
+		str := res.String()
+
+		if code, ok := res.Code(); ok {
+			str = "[" + string(code) + "] " + str
+		}
+
+		elems = append(elems, str)
+	}
+
+	return strings.Join(elems, "\n\n")
+}
+
+// conflicts_of is a helper function that takes a snapshot of the conflicts of a
+// conflict map.
+//
+// Parameters:
+//   - cm: The conflict map.
+//
+// Returns:
+//   - map[T][]*Item[T]: The conflicting items of each symbol.
+func conflicts_of[T internal.TokenTyper](cm *ConflictMap[T]) map[T][]*Item[T] {
+	conflicts := make(map[T][]*Item[T])
+
+	for symbol, items := range cm.All() {
+		conflicts[symbol] = slices.Collect(items)
+	}
+
+	return conflicts
+}
+
+// log_resolutions is a helper function that records, for each symbol in conflict
+// before a strategy was applied, whether the strategy resolved it.
+//
+// Parameters:
+//   - before: The conflicts before the strategy was applied.
+//   - after: The conflicts after the strategy was applied.
+//   - strategy: The strategy.
+func (rs *RuleSet[T]) log_resolutions(before, after map[T][]*Item[T], strategy Strategy) {
+	symbols := make([]T, 0, len(before))
+
+	for symbol := range before {
+		symbols = append(symbols, symbol)
+	}
+
+	slices.Sort(symbols)
+
+	for _, symbol := range symbols {
+		if _, ok := after[symbol]; ok {
+			continue
+		}
+
+		rs.resolution_log = append(rs.resolution_log, Resolution[T]{
+			Symbol:   symbol,
+			Items:    before[symbol],
+			Strategy: strategy,
+		})
+	}
+}
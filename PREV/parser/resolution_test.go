@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	ogr "github.com/PlayerR9/grammar/PREV/OLD/grammar"
+)
+
+func TestResolutionReport(t *testing.T) {
+	rs := NewRuleSet[calc_type]()
+
+	rs.MustMakeRule(cn_source, []calc_type{cn_expr, ct_eof})
+	rs.MustMakeRule(cn_expr, []calc_type{cn_expr, ct_plus, cn_term})
+	rs.MustMakeRule(cn_expr, []calc_type{cn_term})
+	rs.MustMakeRule(cn_term, []calc_type{ct_num})
+
+	rs.DetermineItems()
+
+	_ = rs.SolveConflicts()
+
+	resolutions := rs.Resolutions()
+	if len(resolutions) == 0 {
+		t.Fatal("expected at least one resolution, got none")
+	}
+
+	report := rs.ResolutionReport()
+
+	for _, res := range resolutions {
+		code, ok := res.Code()
+
+		if res.Strategy == Unresolved {
+			if ok {
+				t.Errorf("expected no code for %s, got %s", res.Symbol, code)
+			}
+
+			continue
+		}
+
+		if !ok || code != ogr.CodeAmbiguousResolved {
+			t.Errorf("expected the code %s for %s, got %s", ogr.CodeAmbiguousResolved, res.Symbol, code)
+		}
+
+		if want := "[W0201] " + res.String(); !strings.Contains(report, want) {
+			t.Errorf("expected the report to contain %q, got %q", want, report)
+		}
+	}
+}
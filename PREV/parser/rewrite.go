@@ -0,0 +1,186 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	utst "github.com/PlayerR9/go-commons/cmp"
+	gcers "github.com/PlayerR9/go-commons/errors"
+	"github.com/PlayerR9/grammar/PREV/internal"
+)
+
+// ReplaceRule replaces a rule of the rule set with another one, at the same
+// position.
+//
+// Parameters:
+//   - old: The rule to replace.
+//   - rule: The rule that replaces it.
+//
+// Returns:
+//   - error: An error if a rule is nil, if old is not in the rule set or if rule
+//     already is.
+//
+// On success, the items and the solved conflicts of the rule set are dropped:
+// DetermineItems and SolveConflicts must be called again before the rule set is
+// used for parsing. Parsers and tables made before are not affected.
+func (rs *RuleSet[T]) ReplaceRule(old, rule *Rule[T]) error {
+	if old == nil {
+		return gcers.NewErrNilParameter("old")
+	} else if rule == nil {
+		return gcers.NewErrNilParameter("rule")
+	}
+
+	idx := slices.IndexFunc(rs.rules, old.Equals)
+	if idx == -1 {
+		return fmt.Errorf("rule %q is not in the rule set", rule_line(old))
+	}
+
+	if !old.Equals(rule) && slices.ContainsFunc(rs.rules, rule.Equals) {
+		return fmt.Errorf("rule %q already exists", rule_line(rule))
+	}
+
+	rules := slices.Clone(rs.rules)
+	rules[idx] = rule
+
+	rs.rules = rules
+	rs.invalidate()
+
+	return nil
+}
+
+// SplitAlternative splits a rule in two at the given position; that is, the rule
+// "lhs -> a b" is replaced with "lhs -> a symbol" and "symbol -> b", where the
+// first one takes the position of the split rule and the second one is added at
+// the end. If "lhs -> a symbol" already exists, only "symbol -> b" is added; thus,
+// splitting "A -> x y" and "A -> x z" after x with the same symbol left-factors
+// them into "A -> x B", "B -> y" and "B -> z".
+//
+// Parameters:
+//   - rule: The rule to split.
+//   - at: The number of right-hand sides kept by the first rule.
+//   - symbol: The non-terminal that stands for the right-hand sides moved to the
+//     second rule.
+//
+// Returns:
+//   - error: An error if rule is nil or not in the rule set, if at does not leave
+//     at least one right-hand side to each rule, if symbol is a terminal or if
+//     "symbol -> b" already exists.
+//
+// On success, the derived data of the rule set are dropped, as for ReplaceRule.
+func (rs *RuleSet[T]) SplitAlternative(rule *Rule[T], at int, symbol T) error {
+	if rule == nil {
+		return gcers.NewErrNilParameter("rule")
+	} else if at < 1 || at >= rule.Size() {
+		return gcers.NewErrInvalidParameter("at", fmt.Errorf("value (%d) must be in [1, %d)", at, rule.Size()))
+	} else if symbol.IsTerminal() {
+		return gcers.NewErrInvalidParameter("symbol", fmt.Errorf("%q is a terminal", symbol.String()))
+	}
+
+	idx := slices.IndexFunc(rs.rules, rule.Equals)
+	if idx == -1 {
+		return fmt.Errorf("rule %q is not in the rule set", rule_line(rule))
+	}
+
+	head, _ := NewRule(rule.lhs, append(slices.Clone(rule.rhss[:at]), symbol))
+	tail, _ := NewRule(symbol, slices.Clone(rule.rhss[at:]))
+
+	if slices.ContainsFunc(rs.rules, tail.Equals) {
+		return fmt.Errorf("rule %q already exists", rule_line(tail))
+	}
+
+	rules := slices.Clone(rs.rules)
+
+	if slices.ContainsFunc(rules, head.Equals) {
+		rules = slices.Delete(rules, idx, idx+1)
+	} else {
+		rules[idx] = head
+	}
+
+	rs.rules = append(rules, tail)
+	rs.invalidate()
+
+	return nil
+}
+
+// RenameSymbol renames a symbol in every rule of the rule set, error productions
+// included.
+//
+// Parameters:
+//   - from: The symbol to rename.
+//   - to: The new symbol.
+//
+// Returns:
+//   - error: An error if from is the EOF terminal, if the symbols are not both
+//     terminals or both non-terminals, if from is not used by any rule or if to
+//     already is.
+//
+// On success, the derived data of the rule set are dropped, as for ReplaceRule.
+func (rs *RuleSet[T]) RenameSymbol(from, to T) error {
+	if from == T(0) {
+		return gcers.NewErrInvalidParameter("from", errors.New("the EOF terminal cannot be renamed"))
+	} else if from.IsTerminal() != to.IsTerminal() {
+		return fmt.Errorf("%q and %q are not of the same kind", from.String(), to.String())
+	}
+
+	used := utst.NewSet[T]()
+
+	for _, rule := range slices.Concat(rs.rules, rs.error_rules) {
+		used.Union(rule.Symbols())
+	}
+
+	if !used.Contains(from) {
+		return fmt.Errorf("%q is not used by any rule", from.String())
+	} else if from != to && used.Contains(to) {
+		return fmt.Errorf("%q is already used", to.String())
+	}
+
+	rs.rules = renamed(rs.rules, from, to)
+	rs.error_rules = renamed(rs.error_rules, from, to)
+	rs.invalidate()
+
+	return nil
+}
+
+// invalidate is a helper function that drops the data derived from the rules;
+// that is, the symbols, the items and the solved conflicts.
+func (rs *RuleSet[T]) invalidate() {
+	rs.items = make(map[T][]*Item[T])
+	rs.symbols = utst.NewSet[T]()
+	rs.resolutions = [3]int{}
+	rs.resolution_log = nil
+}
+
+// renamed is a helper function that renames a symbol in a list of rules.
+//
+// Parameters:
+//   - rules: The rules. They are not modified.
+//   - from: The symbol to rename.
+//   - to: The new symbol.
+//
+// Returns:
+//   - []*Rule[T]: The new list of rules, in the same order.
+func renamed[T internal.TokenTyper](rules []*Rule[T], from, to T) []*Rule[T] {
+	rename := func(symbol T) T {
+		if symbol == from {
+			return to
+		}
+
+		return symbol
+	}
+
+	result := make([]*Rule[T], 0, len(rules))
+
+	for _, rule := range rules {
+		rhss := make([]T, 0, len(rule.rhss))
+
+		for _, rhs := range rule.rhss {
+			rhss = append(rhss, rename(rhs))
+		}
+
+		r, _ := NewRule(rename(rule.lhs), rhss)
+		result = append(result, r)
+	}
+
+	return result
+}
@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+)
+
+// rule_lines is a helper function that writes the rules of a rule set, in order.
+func rule_lines(rs *RuleSet[test_type]) []string {
+	var lines []string
+
+	for rule := range rs.Rules() {
+		lines = append(lines, rule_line(rule))
+	}
+
+	return lines
+}
+
+func TestReplaceRule(t *testing.T) {
+	rs := new_test_rule_set()
+
+	old, _ := NewRule(nt_term, []test_type{tt_num})
+	rule, _ := NewRule(nt_term, []test_type{tt_num, tt_semi})
+
+	err := rs.ReplaceRule(old, rule)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := []string{
+		"Source -> Expr EOF",
+		"Expr -> Expr PLUS Term",
+		"Expr -> Term",
+		"Term -> LPAREN Expr RPAREN",
+		"Term -> NUM SEMI",
+	}
+
+	if got := rule_lines(rs); !slices.Equal(got, want) {
+		t.Errorf("expected the rules %q, got %q", want, got)
+	}
+
+	err = rs.ReplaceRule(old, rule)
+	if err == nil {
+		t.Errorf("expected an error for a rule that is not in the rule set, got nil")
+	}
+
+	pt, err := NewLALRTable(rs)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	const want_tree = `Source { Expr { Term { NUM("1") SEMI(";") } } EOF }`
+
+	if got := outcome_of(pt.ParseResult(lex_test_input("1 ;"))); got != want_tree {
+		t.Errorf("expected %s, got %s", want_tree, got)
+	}
+}
+
+func TestSplitAlternative(t *testing.T) {
+	rs := NewRuleSet[test_type]()
+
+	rs.MustMakeRule(nt_source, []test_type{nt_expr, tt_eof})
+	rs.MustMakeRule(nt_expr, []test_type{tt_num, tt_plus})
+	rs.MustMakeRule(nt_expr, []test_type{tt_num, tt_semi})
+
+	for _, rhs := range []test_type{tt_plus, tt_semi} {
+		rule, _ := NewRule(nt_expr, []test_type{tt_num, rhs})
+
+		err := rs.SplitAlternative(rule, 1, nt_term)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	want := []string{
+		"Source -> Expr EOF",
+		"Expr -> NUM Term",
+		"Term -> PLUS",
+		"Term -> SEMI",
+	}
+
+	if got := rule_lines(rs); !slices.Equal(got, want) {
+		t.Errorf("expected the rules %q, got %q", want, got)
+	}
+
+	split, _ := NewRule(nt_expr, []test_type{tt_num, tt_plus})
+	rule, _ := NewRule(nt_expr, []test_type{tt_num, nt_term})
+
+	tests := []struct {
+		name   string
+		rule   *Rule[test_type]
+		at     int
+		symbol test_type
+	}{
+		{"at 0", rule, 0, nt_stmt},
+		{"at the end", rule, 2, nt_stmt},
+		{"terminal symbol", rule, 1, tt_semi},
+		{"rule not in the rule set", split, 1, nt_stmt},
+	}
+
+	for _, tt := range tests {
+		err := rs.SplitAlternative(tt.rule, tt.at, tt.symbol)
+		if err == nil {
+			t.Errorf("%s: expected an error, got nil", tt.name)
+		}
+	}
+}
+
+func TestRenameSymbol(t *testing.T) {
+	rs := new_test_rule_set()
+
+	err := rs.RenameSymbol(tt_plus, tt_semi)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := []string{
+		"Source -> Expr EOF",
+		"Expr -> Expr SEMI Term",
+		"Expr -> Term",
+		"Term -> LPAREN Expr RPAREN",
+		"Term -> NUM",
+	}
+
+	if got := rule_lines(rs); !slices.Equal(got, want) {
+		t.Errorf("expected the rules %q, got %q", want, got)
+	}
+
+	tests := []struct {
+		name     string
+		from, to test_type
+	}{
+		{"EOF", tt_eof, tt_plus},
+		{"different kinds", tt_semi, nt_stmt},
+		{"unused symbol", tt_plus, tt_error},
+		{"used symbol", tt_semi, tt_num},
+	}
+
+	for _, tt := range tests {
+		err := rs.RenameSymbol(tt.from, tt.to)
+		if err == nil {
+			t.Errorf("%s: expected an error, got nil", tt.name)
+		}
+	}
+}
@@ -2,11 +2,13 @@ package parser
 
 import (
 	"fmt"
+	"iter"
 	"slices"
 	"strings"
 
 	dbg "github.com/PlayerR9/go-commons/assert"
 	utst "github.com/PlayerR9/go-commons/cmp"
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
 	"github.com/PlayerR9/grammar/PREV/internal"
 )
 
@@ -20,6 +22,18 @@ type RuleSet[T internal.TokenTyper] struct {
 
 	// symbols is the list of all symbols in the grammar.
 	symbols *utst.Set[T]
+
+	// resolutions is the number of conflicts resolved by each strategy during
+	// the last call to SolveConflicts.
+	resolutions [3]int
+
+	// resolution_log are the conflicts found during the last call to
+	// SolveConflicts, with the strategy that resolved each of them.
+	resolution_log []Resolution[T]
+
+	// error_rules are the error productions. Their first right-hand side is the
+	// error symbol.
+	error_rules []*Rule[T]
 }
 
 // String implements the fmt.Stringer interface.
@@ -94,6 +108,81 @@ func (rs *RuleSet[T]) MustMakeRule(lhs T, rhss []T) {
 	rs.rules = append(rs.rules, rule)
 }
 
+// MustMakeRuleWithError adds a new error production to the rule set; that is, the
+// rule "lhs -> error_ rhss...", where error_ stands for the erroneous input.
+//
+// When a syntax error occurs while a lhs is being parsed, the parser discards the
+// tokens of that lhs, as well as the input tokens up to the first occurrence of
+// rhss, and reduces them with the error production: the discarded tokens become
+// the children of a token of type error_. The parse then resumes after rhss.
+//
+// Panics if the rule already exists.
+//
+// Parameters:
+//   - lhs: The left hand side of the rule.
+//   - error_: The type of the token that holds the erroneous input.
+//   - rhss: The terminals that must follow the erroneous input. (e.g., a semicolon)
+//     If empty, only the tokens of the lhs are discarded.
+func (rs *RuleSet[T]) MustMakeRuleWithError(lhs T, error_ T, rhss []T) {
+	rule, _ := NewRule(lhs, append([]T{error_}, rhss...))
+	// dbg.AssertErr(err, "NewRule(%q, rhss)", lhs.String())
+
+	if slices.ContainsFunc(rs.error_rules, rule.Equals) {
+		panic("rule already exists")
+	}
+
+	rs.error_rules = append(rs.error_rules, rule)
+}
+
+// error_rule_of is a helper function that returns the first error production of
+// the given left hand side.
+//
+// Parameters:
+//   - lhs: The left hand side.
+//
+// Returns:
+//   - *Rule[T]: The error production.
+//   - bool: True if lhs has an error production, false otherwise.
+func (rs RuleSet[T]) error_rule_of(lhs T) (*Rule[T], bool) {
+	for _, rule := range rs.error_rules {
+		if rule.Lhs() == lhs {
+			return rule, true
+		}
+	}
+
+	return nil, false
+}
+
+// Rules returns an iterator over the rules of the rule set, in the order they
+// were added.
+//
+// Returns:
+//   - iter.Seq[*Rule[T]]: The iterator. Never returns nil.
+func (rs RuleSet[T]) Rules() iter.Seq[*Rule[T]] {
+	return slices.Values(rs.rules)
+}
+
+// Items returns an iterator over the items of the rule set, grouped by the symbol
+// at their position. Items are only available once DetermineItems was called.
+//
+// Returns:
+//   - iter.Seq[*Item[T]]: The iterator. Never returns nil.
+func (rs RuleSet[T]) Items() iter.Seq[*Item[T]] {
+	return func(yield func(*Item[T]) bool) {
+		if rs.symbols == nil {
+			return
+		}
+
+		for symbol := range rs.symbols.All() {
+			for _, item := range rs.items[symbol] {
+				if !yield(item) {
+					return
+				}
+			}
+		}
+	}
+}
+
 // DetermineSymbols determines the symbols in the rule set.
 func (rs *RuleSet[T]) DetermineSymbols() {
 	rs.symbols = utst.NewSet[T]()
@@ -122,6 +211,19 @@ func (rs *RuleSet[T]) DetermineItems() {
 				item, _ := NewItem(rule, idx)
 				// dbg.AssertErr(err, "NewItem(rule, %d)", idx)
 
+				if idx == rule.Size()-1 {
+					// The item's position is the one of its symbol, not of a dot:
+					// the rule is complete once its last symbol is on the stack.
+					item.act = internal.ActReduceType
+
+					if symbol == T(0) {
+						item.act = internal.ActAcceptType
+					}
+				} else {
+					next, _ := rule.RhsAt(idx + 1)
+					item.expected = rs.first_of(next)
+				}
+
 				item_list = append(item_list, item)
 			}
 		}
@@ -178,9 +280,21 @@ func (rs RuleSet[T]) DetermineLookaheads(item *Item[T], offset int) {
 		return
 	}
 
+	item.AppendLookahead(rs.first_of(next_rhs))
+}
+
+// first_of is a helper function that returns the terminals a symbol can start
+// with.
+//
+// Parameters:
+//   - symbol: The symbol.
+//
+// Returns:
+//   - *utst.Set[T]: The terminals. Never returns nil.
+func (rs RuleSet[T]) first_of(symbol T) *utst.Set[T] {
 	solution := utst.NewSet[T]()
 
-	todo := []T{next_rhs}
+	todo := []T{symbol}
 	seen := make(map[T]bool)
 
 	for len(todo) > 0 {
@@ -216,10 +330,12 @@ func (rs RuleSet[T]) DetermineLookaheads(item *Item[T], offset int) {
 		}
 	}
 
-	item.AppendLookahead(solution)
+	return solution
 }
 
-// solve_lookaheads is a helper function that solves the lookaheads.
+// solve_lookaheads is a helper function that solves the lookaheads. It stops when
+// the conflicts are solved or when the conflicting items have no symbol left to
+// look at.
 func (rs *RuleSet[T]) solve_lookaheads() {
 	cm := NewConflictMap[T]()
 	defer cm.Cleanup()
@@ -233,10 +349,22 @@ func (rs *RuleSet[T]) solve_lookaheads() {
 			break
 		}
 
+		var progress bool
+
 		for _, item := range cm.Entry() {
+			_, ok := item.RhsAt(item.pos + offset)
+			if ok {
+				progress = true
+			}
+
 			rs.DetermineLookaheads(item, offset)
 		}
 
+		if !progress {
+			// The conflicting items have no symbol left to look at.
+			break
+		}
+
 		offset++
 	}
 }
@@ -248,13 +376,30 @@ func (rs *RuleSet[T]) solve_lookaheads() {
 //
 // If conflicts are not solved, this function will print out the conflicts.
 func (rs *RuleSet[T]) SolveConflicts() bool {
-	rs.solve_lookbehinds()
-	rs.solve_lookaheads()
-
 	cm := NewConflictMap[T]()
 	defer cm.Cleanup()
 
 	cm.Init(rs.items)
+	initial := conflicts_of(cm)
+
+	rs.solve_lookbehinds()
+
+	cm.Init(rs.items)
+	after_lookbehinds := conflicts_of(cm)
+
+	rs.solve_lookaheads()
+
+	cm.Init(rs.items)
+	remaining := conflicts_of(cm)
+
+	rs.resolutions[ResolvedByLookbehind] = len(initial) - len(after_lookbehinds)
+	rs.resolutions[ResolvedByLookahead] = len(after_lookbehinds) - len(remaining)
+	rs.resolutions[Unresolved] = len(remaining)
+
+	rs.resolution_log = rs.resolution_log[:0]
+	rs.log_resolutions(initial, after_lookbehinds, ResolvedByLookbehind)
+	rs.log_resolutions(after_lookbehinds, remaining, ResolvedByLookahead)
+	rs.log_resolutions(remaining, nil, Unresolved)
 
 	if cm.Len() == 0 {
 		return true
@@ -309,8 +454,22 @@ func (rs RuleSet[T]) Decision(p *ActiveParser[T]) ([]*Item[T], error) {
 
 	indices := make([]int, 0, len(item_list))
 
-	for i := range item_list {
-		indices = append(indices, i)
+	// A shift item can only be chosen if the next input token can follow its
+	// symbol; otherwise, the error is reported here rather than once the stray
+	// token was shifted. Without a next token, the shift reports why.
+	la := top1.Lookahead
+	expected := utst.NewSet[T]()
+
+	for i, item := range item_list {
+		if item.expected == nil || la == nil || item.expected.Contains(la.Type) {
+			indices = append(indices, i)
+		} else {
+			expected.Union(item.expected)
+		}
+	}
+
+	if len(indices) == 0 {
+		return nil, gr.NewErrUnexpectedToken(&top1.Type, &la.Type, expected.Slice()...)
 	}
 
 	curr := top1.Type
@@ -356,3 +515,37 @@ func (rs RuleSet[T]) Decision(p *ActiveParser[T]) ([]*Item[T], error) {
 
 	return items, nil
 }
+
+// CheckUnusedTerminals checks that every given terminal appears in the right-hand side
+// of at least one rule. Terminals that are never consumed frequently indicate a
+// forgotten production.
+//
+// Parameters:
+//   - terminals: The terminals declared by the token type (or the lexer's matcher).
+//
+// Returns:
+//   - []error: The warnings of type *WarnUnusedTerminal, one per unused terminal. Nil if
+//     every terminal is used.
+//
+// Non-terminals among the given symbols are ignored.
+func (rs RuleSet[T]) CheckUnusedTerminals(terminals []T) []error {
+	used := utst.NewSet[T]()
+
+	for _, rule := range rs.rules {
+		for rhs := range rule.Rhs() {
+			used.Add(rhs)
+		}
+	}
+
+	var warnings []error
+
+	for _, terminal := range terminals {
+		if !terminal.IsTerminal() || !used.Add(terminal) {
+			continue // used, or already reported
+		}
+
+		warnings = append(warnings, NewWarnUnusedTerminal(terminal))
+	}
+
+	return warnings
+}
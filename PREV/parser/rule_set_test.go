@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+// calc_type is the token type of the grammar of the four operations.
+type calc_type int
+
+const (
+	ct_eof calc_type = iota
+	ct_num
+	ct_plus
+	ct_minus
+	ct_star
+	ct_slash
+	ct_lparen
+	ct_rparen
+	cn_source
+	cn_expr
+	cn_term
+	cn_factor
+)
+
+// String implements the fmt.Stringer interface.
+func (t calc_type) String() string {
+	return [...]string{
+		"EOF", "NUM", "PLUS", "MINUS", "STAR", "SLASH", "LPAREN", "RPAREN",
+		"Source", "Expr", "Term", "Factor",
+	}[t]
+}
+
+// IsTerminal implements the internal.TokenTyper interface.
+func (t calc_type) IsTerminal() bool {
+	return t < cn_source
+}
+
+func TestSolveConflictsTerminates(t *testing.T) {
+	rs := NewRuleSet[calc_type]()
+
+	rs.MustMakeRule(cn_source, []calc_type{cn_expr, ct_eof})
+	rs.MustMakeRule(cn_expr, []calc_type{cn_expr, ct_plus, cn_term})
+	rs.MustMakeRule(cn_expr, []calc_type{cn_expr, ct_minus, cn_term})
+	rs.MustMakeRule(cn_expr, []calc_type{cn_term})
+	rs.MustMakeRule(cn_term, []calc_type{cn_term, ct_star, cn_factor})
+	rs.MustMakeRule(cn_term, []calc_type{cn_term, ct_slash, cn_factor})
+	rs.MustMakeRule(cn_term, []calc_type{cn_factor})
+	rs.MustMakeRule(cn_factor, []calc_type{ct_num})
+	rs.MustMakeRule(cn_factor, []calc_type{ct_lparen, cn_expr, ct_rparen})
+	rs.MustMakeRule(cn_factor, []calc_type{ct_minus, cn_factor})
+
+	rs.DetermineItems()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_ = rs.SolveConflicts()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected SolveConflicts to return")
+	}
+}
@@ -0,0 +1,180 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"github.com/PlayerR9/grammar/PREV/internal"
+)
+
+// Strategy is a conflict resolution strategy.
+type Strategy int
+
+const (
+	// ResolvedByLookbehind is the strategy that resolves conflicts by looking
+	// at the symbols before the item.
+	ResolvedByLookbehind Strategy = iota
+
+	// ResolvedByLookahead is the strategy that resolves conflicts by looking
+	// at the symbols after the item.
+	ResolvedByLookahead
+
+	// Unresolved is used for the conflicts that no strategy could resolve.
+	Unresolved
+)
+
+// String implements the fmt.Stringer interface.
+func (s Strategy) String() string {
+	switch s {
+	case ResolvedByLookbehind:
+		return "lookbehind"
+	case ResolvedByLookahead:
+		return "lookahead"
+	case Unresolved:
+		return "unresolved"
+	default:
+		return fmt.Sprintf("Strategy(%d)", int(s))
+	}
+}
+
+// GrammarStats are the statistics of a rule set or a parse table.
+type GrammarStats struct {
+	// Rules is the number of rules.
+	Rules int
+
+	// Symbols is the number of distinct symbols.
+	Symbols int
+
+	// Items is the number of items.
+	Items int
+
+	// States is the number of states. Always 0 for rule sets.
+	States int
+
+	// Conflicts is the number of conflicting symbols per strategy. A conflict
+	// is counted under the strategy that resolved it.
+	Conflicts [3]int
+
+	// MemoryEstimate is a rough estimate, in bytes, of the memory used by the
+	// action and goto tables. Always 0 for rule sets.
+	MemoryEstimate int
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Format:
+//
+//	rules:      <rules>
+//	symbols:    <symbols>
+//	items:      <items>
+//	states:     <states>
+//	conflicts:  <lookbehind> lookbehind, <lookahead> lookahead, <unresolved> unresolved
+//	memory:     ~<memory> bytes
+func (s GrammarStats) String() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "rules:      %d\n", s.Rules)
+	fmt.Fprintf(&builder, "symbols:    %d\n", s.Symbols)
+	fmt.Fprintf(&builder, "items:      %d\n", s.Items)
+	fmt.Fprintf(&builder, "states:     %d\n", s.States)
+
+	conflicts := make([]string, 0, len(s.Conflicts))
+
+	for i, count := range s.Conflicts {
+		conflicts = append(conflicts, fmt.Sprintf("%d %s", count, Strategy(i).String()))
+	}
+
+	fmt.Fprintf(&builder, "conflicts:  %s\n", strings.Join(conflicts, ", "))
+	fmt.Fprintf(&builder, "memory:     ~%d bytes", s.MemoryEstimate)
+
+	return builder.String()
+}
+
+// Stats are the statistics of a parse session of a *Parser, meant for tuning the
+// performance of a grammar.
+type Stats struct {
+	// Shifts is the number of tokens shifted, over every branch.
+	Shifts int
+
+	// Reduces is the number of reductions, over every branch.
+	Reduces int
+
+	// Forks is the number of branches forked at ambiguous decisions.
+	Forks int
+
+	// Discarded is the number of branches that failed and could not recover.
+	Discarded int
+
+	// PeakDepth is the highest number of nodes on the stack of a branch.
+	PeakDepth int
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Format:
+//
+//	<shifts> shifts, <reduces> reduces, <forks> forks, <discarded> discarded, peak depth <depth>
+func (s Stats) String() string {
+	return fmt.Sprintf("%d shifts, %d reduces, %d forks, %d discarded, peak depth %d", s.Shifts, s.Reduces, s.Forks, s.Discarded, s.PeakDepth)
+}
+
+// Stats returns the statistics of the rule set.
+//
+// Returns:
+//   - GrammarStats: The statistics.
+//
+// Symbols and items are the ones determined by the last call to DetermineItems and
+// conflicts are the ones of the last call to SolveConflicts.
+func (rs RuleSet[T]) Stats() GrammarStats {
+	var items int
+
+	for _, item_list := range rs.items {
+		items += len(item_list)
+	}
+
+	var symbols int
+
+	if rs.symbols != nil {
+		symbols = rs.symbols.Len()
+	}
+
+	return GrammarStats{
+		Rules:     len(rs.rules),
+		Symbols:   symbols,
+		Items:     items,
+		Conflicts: rs.resolutions,
+	}
+}
+
+// Stats returns the statistics of the parse table.
+//
+// Returns:
+//   - GrammarStats: The statistics.
+func (pt ParseTable[T]) Stats() GrammarStats {
+	var entries int
+
+	for _, actions := range pt.action_table {
+		entries += len(actions)
+	}
+
+	for _, gotos := range pt.goto_table {
+		entries += len(gotos)
+	}
+
+	var (
+		key   T
+		act   internal.ActionType
+		state *State[T]
+	)
+
+	entry_size := int(unsafe.Sizeof(key)) + max(int(unsafe.Sizeof(act)), int(unsafe.Sizeof(state)))
+
+	return GrammarStats{
+		Rules:          pt.rule_set.Size(),
+		Symbols:        pt.symbols.Len(),
+		Items:          pt.item_set.Size(),
+		States:         len(pt.states),
+		MemoryEstimate: entries * entry_size,
+	}
+}
@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"errors"
+
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+	"github.com/PlayerR9/grammar/PREV/internal"
+	grm "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/tree/tree"
+)
+
+// ParseResult parses the tokens with the action and goto tables; that is, without
+// calling the decision function of the rule set. Unlike Parser.ParseResult, it follows a
+// single branch and neither recovers from nor repairs parse errors.
+//
+// Parameters:
+//   - tokens: The tokens to be parsed. The last one should be the EOF token.
+//
+// Returns:
+//   - grm.Result[*tree.Tree[*gr.Token[T]]]: The result. On failure, the trees
+//     parsed so far with an error of type *gr.ErrUnexpectedToken.
+//
+// The table must have been made by NewLALRTable; otherwise, every parse fails. The
+// tokens are copied, so they can be parsed again.
+func (pt ParseTable[T]) ParseResult(tokens []*gr.Token[T]) grm.Result[*tree.Tree[*gr.Token[T]]] {
+	if pt.reductions == nil || len(pt.states) == 0 {
+		return grm.NewFailedResult[*tree.Tree[*gr.Token[T]]](nil, errors.New("the table was not made by NewLALRTable"))
+	}
+
+	input := make([]*gr.Token[T], 0, len(tokens))
+
+	for _, tk := range tokens {
+		input = append(input, tk.Copy())
+	}
+
+	gr.LinkLookaheads(input)
+
+	states := []*State[T]{pt.states[0]}
+
+	var stack []*gr.Token[T]
+
+	for {
+		var la *gr.Token[T]
+
+		if len(input) > 0 {
+			la = input[0]
+		}
+
+		top := states[len(states)-1]
+
+		var la_type T
+
+		if la != nil {
+			la_type = la.Type
+		}
+
+		act, rule := pt.Action(top, la_type)
+
+		switch {
+		case act == internal.ActShiftType && la != nil:
+			next, _ := pt.Next(top, la_type)
+
+			input = input[1:]
+
+			stack = append(stack, la)
+			states = append(states, next)
+
+			continue
+		case act == internal.ActShiftType || rule == nil:
+			return grm.NewFailedResult(forest_of(stack), error(pt.unexpected(top, stack, la)))
+		}
+
+		n := rule.Size()
+
+		children := make([]*gr.Token[T], n)
+		copy(children, stack[len(stack)-n:])
+
+		stack = stack[:len(stack)-n]
+		states = states[:len(states)-n]
+
+		tk := gr.NewToken(rule.Lhs(), "", children[n-1].Lookahead)
+		tk.AddChildren(children)
+
+		if act == internal.ActAcceptType {
+			if len(stack) > 0 {
+				stack = append(stack, tk)
+
+				return grm.NewFailedResult(forest_of(stack), errors.New("not a valid parse"))
+			}
+
+			return grm.NewResult(tree.NewTree(tk))
+		}
+
+		next, _ := pt.Next(states[len(states)-1], rule.Lhs())
+
+		stack = append(stack, tk)
+		states = append(states, next)
+	}
+}
+
+// unexpected is a helper function that makes the error of a parse that cannot go
+// on.
+//
+// Parameters:
+//   - state: The current state.
+//   - stack: The tokens on the stack.
+//   - la: The lookahead. Nil if the input is exhausted.
+//
+// Returns:
+//   - *gr.ErrUnexpectedToken[T]: The error. Never returns nil.
+func (pt ParseTable[T]) unexpected(state *State[T], stack []*gr.Token[T], la *gr.Token[T]) *gr.ErrUnexpectedToken[T] {
+	var expecteds []T
+
+	for symbol := range pt.symbols.All() {
+		if !symbol.IsTerminal() {
+			continue
+		}
+
+		act, _ := pt.Action(state, symbol)
+		if act != internal.ActErrorType {
+			expecteds = append(expecteds, symbol)
+		}
+	}
+
+	var prev, got *T
+
+	if len(stack) > 0 {
+		prev = &stack[len(stack)-1].Type
+	}
+
+	if la != nil {
+		got = &la.Type
+	}
+
+	return gr.NewErrUnexpectedToken(prev, got, expecteds...)
+}
+
+// forest_of is a helper function that turns the tokens of a stack into a forest.
+//
+// Parameters:
+//   - stack: The tokens on the stack, from the bottom to the top.
+//
+// Returns:
+//   - []*tree.Tree[*gr.Token[T]]: The forest, in the same order.
+func forest_of[T internal.TokenTyper](stack []*gr.Token[T]) []*tree.Tree[*gr.Token[T]] {
+	forest := make([]*tree.Tree[*gr.Token[T]], 0, len(stack))
+
+	for _, tk := range stack {
+		forest = append(forest, tree.NewTree(tk))
+	}
+
+	return forest
+}
@@ -0,0 +1,223 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// TreeSitterConfig is the configuration of the tree-sitter export of a rule set.
+type TreeSitterConfig[T comparable] struct {
+	// Name is the name of the language. (e.g., "calc")
+	Name string
+
+	// Patterns are the JavaScript expressions that match the terminals, by
+	// terminal. (e.g., `"+"` or `/[0-9]+/`) The terminals without a pattern are
+	// declared as externals, to be matched by an external scanner.
+	Patterns map[T]string
+
+	// Extras are the JavaScript expressions of what may appear between any two
+	// tokens. If empty, the whitespace (`/\s/`) is.
+	Extras []string
+}
+
+// ts_name_pattern is the pattern of the names of the tree-sitter languages.
+var ts_name_pattern = regexp.MustCompile(`^[a-zA-Z_]\w*$`)
+
+// ts_rule_name is a helper function that converts the name of a symbol into the
+// name of a tree-sitter rule, in snake case. (e.g., "NtExpr" -> "nt_expr")
+//
+// Parameters:
+//   - name: The name of the symbol.
+//
+// Returns:
+//   - string: The name of the rule.
+func ts_rule_name(name string) string {
+	runes := []rune(name)
+
+	var builder strings.Builder
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			r = '_'
+		} else if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			next_lower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && next_lower {
+				builder.WriteRune('_')
+			}
+		}
+
+		builder.WriteRune(unicode.ToLower(r))
+	}
+
+	str := strings.Trim(builder.String(), "_")
+
+	if str == "" || unicode.IsDigit(rune(str[0])) {
+		str = "s_" + str
+	}
+
+	return str
+}
+
+// ToTreeSitter converts the rule set into the grammar.js definition of a
+// tree-sitter grammar, so that the grammar can be compared with its tree-sitter
+// counterpart or reused by the editors that consume tree-sitter parsers.
+//
+// The first rule (the start rule) becomes the first rule of the grammar, without
+// its EOF symbol (T(0)). The other rules are grouped by left-hand side, in order
+// of appearance; the terminals follow, in order of value. The rules are named
+// after the String method of their symbol, in snake case. The error productions
+// are left out, as tree-sitter recovers from errors on its own.
+//
+// Parameters:
+//   - cfg: The configuration of the export.
+//
+// Returns:
+//   - []byte: The grammar.js definition.
+//   - error: An error if the rule set has no rule, if cfg.Name is not a valid
+//     name, or if two symbols have the same rule name.
+//
+// The precedences and the conflicts are not exported; tree-sitter reports the
+// latter when the grammar is generated.
+func (rs RuleSet[T]) ToTreeSitter(cfg TreeSitterConfig[T]) ([]byte, error) {
+	if len(rs.rules) == 0 {
+		return nil, errors.New("the rule set has no rule")
+	} else if !ts_name_pattern.MatchString(cfg.Name) {
+		return nil, fmt.Errorf("%q is not a valid language name", cfg.Name)
+	}
+
+	var lhss, terminals []T
+
+	names := make(map[T]string)
+	owners := make(map[string]T)
+
+	add_symbol := func(symbol T) error {
+		if _, ok := names[symbol]; ok {
+			return nil
+		}
+
+		name := ts_rule_name(symbol.String())
+
+		if other, ok := owners[name]; ok {
+			return fmt.Errorf("symbols %q and %q are both named %q", other.String(), symbol.String(), name)
+		}
+
+		names[symbol] = name
+		owners[name] = symbol
+
+		if symbol.IsTerminal() {
+			terminals = append(terminals, symbol)
+		}
+
+		return nil
+	}
+
+	for _, rule := range rs.rules {
+		if !slices.Contains(lhss, rule.lhs) {
+			lhss = append(lhss, rule.lhs)
+		}
+
+		err := add_symbol(rule.lhs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rhs := range rule.rhss {
+			if rhs == T(0) {
+				continue
+			}
+
+			err := add_symbol(rhs)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	slices.Sort(terminals)
+
+	var buf bytes.Buffer
+
+	buf.WriteString("module.exports = grammar({\n")
+	buf.WriteString("  name: '" + cfg.Name + "',\n\n")
+
+	extras := cfg.Extras
+	if len(extras) == 0 {
+		extras = []string{`/\s/`}
+	}
+
+	buf.WriteString("  extras: $ => [" + strings.Join(extras, ", ") + "],\n\n")
+
+	var externals []string
+
+	for _, terminal := range terminals {
+		if _, ok := cfg.Patterns[terminal]; !ok {
+			externals = append(externals, "$."+names[terminal])
+		}
+	}
+
+	if len(externals) > 0 {
+		buf.WriteString("  externals: $ => [" + strings.Join(externals, ", ") + "],\n\n")
+	}
+
+	buf.WriteString("  rules: {\n")
+
+	for _, lhs := range lhss {
+		var alts []string
+
+		for _, rule := range rs.rules {
+			if rule.lhs != lhs {
+				continue
+			}
+
+			var elems []string
+
+			for _, rhs := range rule.rhss {
+				if rhs != T(0) {
+					elems = append(elems, "$."+names[rhs])
+				}
+			}
+
+			switch len(elems) {
+			case 0:
+				alts = append(alts, "blank()")
+			case 1:
+				alts = append(alts, elems[0])
+			default:
+				alts = append(alts, "seq("+strings.Join(elems, ", ")+")")
+			}
+		}
+
+		buf.WriteString("    " + names[lhs] + ": $ => ")
+
+		if len(alts) == 1 {
+			buf.WriteString(alts[0] + ",\n")
+		} else {
+			buf.WriteString("choice(\n")
+
+			for _, alt := range alts {
+				buf.WriteString("      " + alt + ",\n")
+			}
+
+			buf.WriteString("    ),\n")
+		}
+	}
+
+	for _, terminal := range terminals {
+		pattern, ok := cfg.Patterns[terminal]
+		if ok {
+			buf.WriteString("    " + names[terminal] + ": $ => " + pattern + ",\n")
+		}
+	}
+
+	buf.WriteString("  },\n")
+	buf.WriteString("});\n")
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,70 @@
+package parser
+
+import "testing"
+
+func TestToTreeSitter(t *testing.T) {
+	cfg := TreeSitterConfig[test_type]{
+		Name: "sum",
+		Patterns: map[test_type]string{
+			tt_plus:   `"+"`,
+			tt_lparen: `"("`,
+			tt_rparen: `")"`,
+		},
+	}
+
+	data, err := new_test_rule_set().ToTreeSitter(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	const want = `module.exports = grammar({
+  name: 'sum',
+
+  extras: $ => [/\s/],
+
+  externals: $ => [$.num],
+
+  rules: {
+    source: $ => $.expr,
+    expr: $ => choice(
+      seq($.expr, $.plus, $.term),
+      $.term,
+    ),
+    term: $ => choice(
+      seq($.lparen, $.expr, $.rparen),
+      $.num,
+    ),
+    plus: $ => "+",
+    lparen: $ => "(",
+    rparen: $ => ")",
+  },
+});
+`
+
+	if got := string(data); got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+
+	cfg.Name = "not a name"
+
+	_, err = new_test_rule_set().ToTreeSitter(cfg)
+	if err == nil {
+		t.Errorf("expected an error, got nil")
+	}
+}
+
+func TestTsRuleName(t *testing.T) {
+	tests := map[string]string{
+		"NtExpr":   "nt_expr",
+		"EOF":      "eof",
+		"HTTPCode": "http_code",
+		"a-b":      "a_b",
+		"1st":      "s_1st",
+	}
+
+	for name, want := range tests {
+		if got := ts_rule_name(name); got != want {
+			t.Errorf("%q: expected %q, got %q", name, want, got)
+		}
+	}
+}
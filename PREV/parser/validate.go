@@ -0,0 +1,419 @@
+package parser
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/PlayerR9/grammar/PREV/internal"
+)
+
+// ProblemKind is the kind of a problem of a rule set.
+type ProblemKind int
+
+const (
+	// MissingStart is the problem of a rule set without a start rule; that is,
+	// without a rule that ends with the EOF symbol (T(0)).
+	MissingStart ProblemKind = iota
+
+	// MultipleStarts is the problem of a rule set with more than one rule that
+	// ends with the EOF symbol.
+	MultipleStarts
+
+	// DuplicateRule is the problem of a rule that appears more than once.
+	DuplicateRule
+
+	// DerivationCycle is the problem of a nonterminal that derives itself alone
+	// through rules with a single right-hand side (e.g., "A -> B" and "B -> A").
+	// Such left-recursion cycles make the decision engine reduce forever.
+	DerivationCycle
+
+	// NonProductive is the problem of a nonterminal that derives no sequence of
+	// terminals; its rules can never be reduced.
+	NonProductive
+
+	// Unreachable is the problem of a nonterminal that the start rule never
+	// derives.
+	Unreachable
+
+	// UnusedTerminal is the problem of a declared terminal that no rule consumes,
+	// which frequently indicates a forgotten production.
+	UnusedTerminal
+)
+
+// String implements the fmt.Stringer interface.
+func (k ProblemKind) String() string {
+	switch k {
+	case MissingStart:
+		return "missing start"
+	case MultipleStarts:
+		return "multiple starts"
+	case DuplicateRule:
+		return "duplicate rule"
+	case DerivationCycle:
+		return "derivation cycle"
+	case NonProductive:
+		return "non-productive"
+	case Unreachable:
+		return "unreachable"
+	case UnusedTerminal:
+		return "unused terminal"
+	default:
+		return "ProblemKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// Problem is a problem of a rule set found by Validate.
+type Problem[T internal.TokenTyper] struct {
+	// Kind is the kind of the problem.
+	Kind ProblemKind
+
+	// Symbol is the nonterminal of the problem. The EOF symbol for the problems of
+	// the start rule and the terminal for UnusedTerminal.
+	Symbol T
+
+	// Rules are the rules of the problem: the start rules, the duplicate rule or
+	// the rules of the nonterminal. Nil if there is none.
+	Rules []*Rule[T]
+
+	// Cycle are the nonterminals that Symbol derives, in order, until it derives
+	// itself again. Only for DerivationCycle.
+	Cycle []T
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Format:
+//
+//	<kind>: <description>
+func (p Problem[T]) String() string {
+	var desc string
+
+	switch p.Kind {
+	case MissingStart:
+		desc = "no rule ends with " + strconv.Quote(p.Symbol.String())
+	case MultipleStarts:
+		lines := make([]string, 0, len(p.Rules))
+
+		for _, rule := range p.Rules {
+			lines = append(lines, rule_line(rule))
+		}
+
+		desc = strconv.Itoa(len(p.Rules)) + " rules end with " + strconv.Quote(p.Symbol.String()) + ": " + strings.Join(lines, "; ")
+	case DuplicateRule:
+		desc = rule_line(p.Rules[0])
+	case DerivationCycle:
+		path := []string{p.Symbol.String()}
+
+		for _, symbol := range p.Cycle {
+			path = append(path, symbol.String())
+		}
+
+		desc = strconv.Quote(p.Symbol.String()) + " derives itself: " + strings.Join(path, " -> ")
+	case NonProductive:
+		if len(p.Rules) == 0 {
+			desc = strconv.Quote(p.Symbol.String()) + " has no rule"
+		} else {
+			desc = strconv.Quote(p.Symbol.String()) + " derives no sequence of terminals"
+		}
+	case Unreachable:
+		desc = strconv.Quote(p.Symbol.String()) + " is not derived by the start rule"
+	case UnusedTerminal:
+		desc = strconv.Quote(p.Symbol.String()) + " is never used in the right-hand side of any rule"
+	}
+
+	return p.Kind.String() + ": " + desc
+}
+
+// ErrInvalidGrammar is the error of a rule set that failed its validation.
+type ErrInvalidGrammar[T internal.TokenTyper] struct {
+	// Problems are the problems of the rule set.
+	Problems []Problem[T]
+}
+
+// Error implements the error interface.
+//
+// Message:
+//
+//	"<n> problems:
+//		<problem>
+//		..."
+func (e ErrInvalidGrammar[T]) Error() string {
+	var builder strings.Builder
+
+	builder.WriteString(strconv.Itoa(len(e.Problems)))
+	builder.WriteString(" problems:")
+
+	for _, p := range e.Problems {
+		builder.WriteString("\n\t")
+		builder.WriteString(p.String())
+	}
+
+	return builder.String()
+}
+
+// NewErrInvalidGrammar creates a new ErrInvalidGrammar error.
+//
+// Parameters:
+//   - problems: The problems of the rule set.
+//
+// Returns:
+//   - *ErrInvalidGrammar[T]: The new error. Never returns nil.
+func NewErrInvalidGrammar[T internal.TokenTyper](problems []Problem[T]) *ErrInvalidGrammar[T] {
+	return &ErrInvalidGrammar[T]{
+		Problems: problems,
+	}
+}
+
+// Validate checks the rule set for the problems that would otherwise only show
+// at parse time: a missing or ambiguous start rule, duplicate rules, derivation
+// cycles, nonterminals that derive no sequence of terminals, nonterminals that
+// the start rule never derives and declared terminals that no rule consumes.
+//
+// Parameters:
+//   - terminals: The terminals declared by the token type (or the lexer's
+//     matcher). Those that CheckUnusedTerminals reports are problems.
+//
+// Returns:
+//   - error: An error of type *ErrInvalidGrammar[T] that lists every problem, in
+//     the order of the kinds and then of the symbols. Nil if there is none.
+//
+// The error productions only count for reachability, as they only apply to
+// erroneous inputs; their first right-hand side is ignored.
+func (rs RuleSet[T]) Validate(terminals ...T) error {
+	var problems []Problem[T]
+
+	by_lhs := make(map[T][]*Rule[T])
+	var starts []*Rule[T]
+
+	for _, rule := range rs.rules {
+		by_lhs[rule.Lhs()] = append(by_lhs[rule.Lhs()], rule)
+
+		last, _ := rule.RhsAt(rule.Size() - 1)
+		if last == T(0) {
+			starts = append(starts, rule)
+		}
+	}
+
+	switch len(starts) {
+	case 0:
+		problems = append(problems, Problem[T]{Kind: MissingStart, Symbol: T(0)})
+	case 1:
+	default:
+		problems = append(problems, Problem[T]{Kind: MultipleStarts, Symbol: T(0), Rules: starts})
+	}
+
+	for i, rule := range rs.rules {
+		if slices.ContainsFunc(rs.rules[:i], rule.Equals) {
+			problems = append(problems, Problem[T]{Kind: DuplicateRule, Symbol: rule.Lhs(), Rules: []*Rule[T]{rule}})
+		}
+	}
+
+	nonterminals := rs.nonterminals()
+
+	for _, symbol := range nonterminals {
+		cycle, ok := unit_cycle(symbol, by_lhs)
+		if ok {
+			problems = append(problems, Problem[T]{Kind: DerivationCycle, Symbol: symbol, Rules: by_lhs[symbol], Cycle: cycle})
+		}
+	}
+
+	productive := rs.productive()
+
+	for _, symbol := range nonterminals {
+		if !productive[symbol] {
+			problems = append(problems, Problem[T]{Kind: NonProductive, Symbol: symbol, Rules: by_lhs[symbol]})
+		}
+	}
+
+	if len(starts) > 0 {
+		reachable := rs.reachable(starts)
+
+		for _, symbol := range nonterminals {
+			if !reachable[symbol] {
+				problems = append(problems, Problem[T]{Kind: Unreachable, Symbol: symbol, Rules: by_lhs[symbol]})
+			}
+		}
+	}
+
+	for _, warning := range rs.CheckUnusedTerminals(terminals) {
+		unused := warning.(*WarnUnusedTerminal[T])
+
+		problems = append(problems, Problem[T]{Kind: UnusedTerminal, Symbol: unused.Terminal})
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return NewErrInvalidGrammar(problems)
+}
+
+// nonterminals is a helper function that returns the nonterminals of the rule set.
+//
+// Returns:
+//   - []T: The nonterminals, sorted.
+func (rs RuleSet[T]) nonterminals() []T {
+	var symbols []T
+
+	add := func(rule *Rule[T], rhss []T) {
+		for _, symbol := range rhss {
+			if !symbol.IsTerminal() {
+				symbols = append(symbols, symbol)
+			}
+		}
+
+		symbols = append(symbols, rule.Lhs())
+	}
+
+	for _, rule := range rs.rules {
+		add(rule, rule.rhss)
+	}
+
+	for _, rule := range rs.error_rules {
+		add(rule, rule.rhss[1:])
+	}
+
+	slices.Sort(symbols)
+
+	return slices.Compact(symbols)
+}
+
+// productive is a helper function that computes the nonterminals that derive a
+// sequence of terminals without the error productions.
+//
+// Returns:
+//   - map[T]bool: The productive nonterminals.
+func (rs RuleSet[T]) productive() map[T]bool {
+	productive := make(map[T]bool)
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, rule := range rs.rules {
+			if productive[rule.Lhs()] {
+				continue
+			}
+
+			ok := true
+
+			for _, symbol := range rule.rhss {
+				if !symbol.IsTerminal() && !productive[symbol] {
+					ok = false
+					break
+				}
+			}
+
+			if ok {
+				productive[rule.Lhs()] = true
+				changed = true
+			}
+		}
+	}
+
+	return productive
+}
+
+// reachable is a helper function that computes the nonterminals that the start
+// rules derive.
+//
+// Parameters:
+//   - starts: The start rules.
+//
+// Returns:
+//   - map[T]bool: The reachable nonterminals.
+func (rs RuleSet[T]) reachable(starts []*Rule[T]) map[T]bool {
+	reachable := make(map[T]bool)
+
+	var todo []T
+
+	for _, rule := range starts {
+		if !reachable[rule.Lhs()] {
+			reachable[rule.Lhs()] = true
+			todo = append(todo, rule.Lhs())
+		}
+	}
+
+	for len(todo) > 0 {
+		top := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+
+		for _, rule := range slices.Concat(rs.rules, rs.error_rules) {
+			if rule.Lhs() != top {
+				continue
+			}
+
+			rhss := rule.rhss
+			if slices.Contains(rs.error_rules, rule) {
+				rhss = rhss[1:]
+			}
+
+			for _, symbol := range rhss {
+				if symbol.IsTerminal() || reachable[symbol] {
+					continue
+				}
+
+				reachable[symbol] = true
+				todo = append(todo, symbol)
+			}
+		}
+	}
+
+	return reachable
+}
+
+// unit_cycle is a helper function that searches the shortest derivation of a
+// nonterminal into itself through rules with a single right-hand side. Cycles are
+// only reported for their smallest nonterminal, so that each one is reported once.
+//
+// Parameters:
+//   - symbol: The nonterminal.
+//   - by_lhs: The rules, by left-hand side.
+//
+// Returns:
+//   - []T: The nonterminals derived, in order, ending with symbol.
+//   - bool: True if there is such a cycle and symbol is its smallest nonterminal,
+//     false otherwise.
+func unit_cycle[T internal.TokenTyper](symbol T, by_lhs map[T][]*Rule[T]) ([]T, bool) {
+	prev := map[T]T{}
+	queue := []T{symbol}
+	seen := map[T]bool{}
+
+	for len(queue) > 0 {
+		first := queue[0]
+		queue = queue[1:]
+
+		for _, rule := range by_lhs[first] {
+			if rule.Size() != 1 {
+				continue
+			}
+
+			next := rule.rhss[0]
+
+			if next.IsTerminal() || next < symbol || seen[next] {
+				continue
+			}
+
+			seen[next] = true
+			prev[next] = first
+
+			if next != symbol {
+				queue = append(queue, next)
+
+				continue
+			}
+
+			cycle := []T{symbol}
+
+			for curr := first; curr != symbol; curr = prev[curr] {
+				cycle = append(cycle, curr)
+			}
+
+			slices.Reverse(cycle)
+
+			return cycle, true
+		}
+	}
+
+	return nil, false
+}
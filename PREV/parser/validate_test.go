@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateUnusedTerminal(t *testing.T) {
+	rs := NewRuleSet[test_type]()
+
+	rs.MustMakeRule(nt_source, []test_type{nt_expr, tt_eof})
+	rs.MustMakeRule(nt_expr, []test_type{nt_expr, tt_plus, nt_term})
+	rs.MustMakeRule(nt_expr, []test_type{nt_term})
+	rs.MustMakeRule(nt_term, []test_type{tt_num})
+
+	err := rs.Validate()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = rs.Validate(tt_eof, tt_num, tt_plus, tt_lparen, tt_rparen)
+
+	var invalid *ErrInvalidGrammar[test_type]
+
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected an *ErrInvalidGrammar, got %v", err)
+	}
+
+	want := []test_type{tt_lparen, tt_rparen}
+
+	if len(invalid.Problems) != len(want) {
+		t.Fatalf("expected %d problems, got %v", len(want), invalid.Problems)
+	}
+
+	for i, p := range invalid.Problems {
+		if p.Kind != UnusedTerminal || p.Symbol != want[i] {
+			t.Errorf("problem %d: expected unused terminal %s, got %s", i, want[i], p)
+		}
+	}
+}
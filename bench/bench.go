@@ -0,0 +1,350 @@
+// Package bench provides standard grammar fixtures (JSON, arithmetic expressions
+// and a small Go-like language) on which the performance of the parsers can be
+// measured and compared across changes.
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+	prx "github.com/PlayerR9/grammar/PREV/parser"
+)
+
+// Symbol is a symbol of the fixtures. The EOF symbol is 0 and is followed by the
+// terminals, then by the non-terminals. The fixtures share the symbols; each one
+// only uses some of them.
+type Symbol int
+
+const (
+	EtEOF Symbol = iota
+	TtNumber
+	TtString
+	TtIdent
+	TtTrue
+	TtFalse
+	TtNull
+	TtFunc
+	TtVar
+	TtReturn
+	TtIf
+	TtElse
+	TtFor
+	TtLbrace
+	TtRbrace
+	TtLbrack
+	TtRbrack
+	TtLparen
+	TtRparen
+	TtComma
+	TtColon
+	TtSemi
+	TtPlus
+	TtMinus
+	TtStar
+	TtSlash
+	TtLess
+	TtAssign
+	TtDefine
+
+	NtSource
+	NtValue
+	NtObject
+	NtMembers
+	NtPair
+	NtArray
+	NtElements
+	NtExpr
+	NtTerm
+	NtFactor
+	NtDecls
+	NtDecl
+	NtParams
+	NtBlock
+	NtStmts
+	NtStmt
+	NtCall
+	NtArgs
+)
+
+// symbol_names are the names of the symbols.
+var symbol_names = [...]string{
+	"EOF", "NUMBER", "STRING", "IDENT", "TRUE", "FALSE", "NULL", "FUNC", "VAR", "RETURN",
+	"IF", "ELSE", "FOR", "LBRACE", "RBRACE", "LBRACK", "RBRACK", "LPAREN", "RPAREN",
+	"COMMA", "COLON", "SEMI", "PLUS", "MINUS", "STAR", "SLASH", "LESS", "ASSIGN", "DEFINE",
+	"Source", "Value", "Object", "Members", "Pair", "Array", "Elements", "Expr", "Term",
+	"Factor", "Decls", "Decl", "Params", "Block", "Stmts", "Stmt", "Call", "Args",
+}
+
+// String implements the fmt.Stringer interface.
+func (s Symbol) String() string {
+	if s < 0 || int(s) >= len(symbol_names) {
+		return "Symbol(" + strconv.Itoa(int(s)) + ")"
+	}
+
+	return symbol_names[s]
+}
+
+// IsTerminal checks whether the symbol is a terminal. EOF is a terminal.
+//
+// Returns:
+//   - bool: True if the symbol is a terminal, false otherwise.
+func (s Symbol) IsTerminal() bool {
+	return s < NtSource
+}
+
+// Fixture is a standard grammar fixture: a grammar, the words of its lexer and
+// sample inputs.
+type Fixture struct {
+	// Name is the name of the fixture.
+	Name string
+
+	// Inputs are the sample inputs, from the smallest to the largest. They are all
+	// valid; the last one is a large generated input.
+	Inputs []string
+
+	// rules are the rules of the grammar, left-hand side first. The first rule is
+	// the start rule and ends with EtEOF.
+	rules [][]Symbol
+
+	// words are the keywords and the punctuation of the lexer.
+	words map[string]Symbol
+}
+
+// RuleSet returns the rule set of the grammar, with its items determined and its
+// conflicts solved. The grammars are LALR(1); the conflicts that the rule set
+// cannot solve are left to the forks of the decision engine.
+//
+// Returns:
+//   - *prx.RuleSet[Symbol]: The rule set. Never returns nil.
+func (f Fixture) RuleSet() *prx.RuleSet[Symbol] {
+	rs := prx.NewRuleSet[Symbol]()
+
+	for _, rule := range f.rules {
+		rs.MustMakeRule(rule[0], rule[1:])
+	}
+
+	rs.DetermineItems()
+	_ = rs.SolveConflicts()
+
+	return rs
+}
+
+// Lex splits an input into tokens and appends the EOF token. Numbers, strings
+// (double-quoted, with backslash escapes) and identifiers are recognized by every
+// fixture; keywords and punctuation depend on the fixture.
+//
+// Parameters:
+//   - input: The input.
+//
+// Returns:
+//   - []*gr.Token[Symbol]: The tokens. Nil if an error occurred.
+//   - error: An error if the input contains a character that the fixture does not
+//     recognize or an unterminated string.
+func (f Fixture) Lex(input string) ([]*gr.Token[Symbol], error) {
+	var tokens []*gr.Token[Symbol]
+
+	for pos := 0; pos < len(input); {
+		c, size := utf8.DecodeRuneInString(input[pos:])
+
+		var (
+			type_ Symbol
+			end   int
+		)
+
+		switch {
+		case unicode.IsSpace(c):
+			pos += size
+
+			continue
+		case c == '"':
+			end = string_end(input, pos)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at %d", pos)
+			}
+
+			type_ = TtString
+		case is_digit(c) || c == '-' && f.words["-"] == 0 && pos+1 < len(input) && is_digit(rune(input[pos+1])):
+			end = pos + 1
+
+			for end < len(input) && (is_digit(rune(input[end])) || input[end] == '.') {
+				end++
+			}
+
+			type_ = TtNumber
+		case c == '_' || unicode.IsLetter(c):
+			end = pos + size
+
+			for end < len(input) {
+				c, size := utf8.DecodeRuneInString(input[end:])
+				if c != '_' && !unicode.IsLetter(c) && !is_digit(c) {
+					break
+				}
+
+				end += size
+			}
+
+			var ok bool
+
+			type_, ok = f.words[input[pos:end]]
+			if !ok {
+				type_ = TtIdent
+			}
+		default:
+			var ok bool
+
+			if pos+2 <= len(input) {
+				type_, ok = f.words[input[pos:pos+2]]
+				end = pos + 2
+			}
+
+			if !ok {
+				type_, ok = f.words[input[pos:pos+size]]
+				end = pos + size
+			}
+
+			if !ok {
+				return nil, fmt.Errorf("unexpected character %q at %d", c, pos)
+			}
+		}
+
+		tokens = append(tokens, gr.NewToken(type_, input[pos:end], nil))
+		pos = end
+	}
+
+	return append(tokens, gr.NewToken(EtEOF, "", nil)), nil
+}
+
+// is_digit is a helper function that checks whether a character is an ASCII digit.
+//
+// Parameters:
+//   - c: The character.
+//
+// Returns:
+//   - bool: True if c is a digit, false otherwise.
+func is_digit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
+
+// string_end is a helper function that finds the end of a string literal.
+//
+// Parameters:
+//   - input: The input.
+//   - pos: The position of the opening quote.
+//
+// Returns:
+//   - int: The position right after the closing quote. -1 if the string is not
+//     terminated.
+func string_end(input string, pos int) int {
+	for i := pos + 1; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+
+	return -1
+}
+
+// Measure parses every input of the fixture with the given parser, so that the
+// statistics of the parser can be compared across grammars and changes. Inputs
+// that fail to parse are measured as well.
+//
+// Parameters:
+//   - p: The parser of the rule set of the fixture. Its limits, if any, apply.
+//
+// Returns:
+//   - []prx.Stats: The statistics of the parse of every input, in order.
+//   - error: The errors of the inputs that could not be lexed or parsed, joined.
+func (f Fixture) Measure(p *prx.Parser[Symbol]) ([]prx.Stats, error) {
+	if p == nil {
+		return nil, gcers.NewErrNilParameter("p")
+	}
+
+	stats := make([]prx.Stats, 0, len(f.Inputs))
+
+	var errs []error
+
+	for i, input := range f.Inputs {
+		tokens, err := f.Lex(input)
+		if err != nil {
+			stats = append(stats, prx.Stats{})
+			errs = append(errs, fmt.Errorf("%s input %d: %w", f.Name, i, err))
+
+			continue
+		}
+
+		res := p.ParseResult(tokens)
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("%s input %d: %w", f.Name, i, res.Err))
+		}
+
+		stats = append(stats, p.Stats())
+	}
+
+	return stats, errors.Join(errs...)
+}
+
+// Benchmark parses every input of the fixture b.N times with the given engine and
+// reports the allocations. When the engine is a *prx.Parser, the shifts, reduces,
+// forks and discarded branches per run are reported as well.
+//
+// Parameters:
+//   - b: The benchmark.
+//   - engine: The engine of the rule set of the fixture.
+func (f Fixture) Benchmark(b *testing.B, engine prx.Engine[Symbol]) {
+	if engine == nil {
+		b.Fatal(gcers.NewErrNilParameter("engine"))
+	}
+
+	inputs := make([][]*gr.Token[Symbol], 0, len(f.Inputs))
+
+	for _, input := range f.Inputs {
+		tokens, err := f.Lex(input)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		inputs = append(inputs, tokens)
+	}
+
+	p, _ := engine.(*prx.Parser[Symbol])
+
+	var shifts, reduces, forks, discarded int
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, tokens := range inputs {
+			_ = engine.ParseResult(tokens)
+
+			if p == nil {
+				continue
+			}
+
+			stats := p.Stats()
+
+			shifts += stats.Shifts
+			reduces += stats.Reduces
+			forks += stats.Forks
+			discarded += stats.Discarded
+		}
+	}
+
+	if p == nil {
+		return
+	}
+
+	b.ReportMetric(float64(shifts)/float64(b.N), "shifts/op")
+	b.ReportMetric(float64(reduces)/float64(b.N), "reduces/op")
+	b.ReportMetric(float64(forks)/float64(b.N), "forks/op")
+	b.ReportMetric(float64(discarded)/float64(b.N), "discarded/op")
+}
@@ -0,0 +1,80 @@
+package bench
+
+import (
+	"testing"
+
+	prx "github.com/PlayerR9/grammar/PREV/parser"
+)
+
+// decision_limits bound the decision engine on the inputs where its branches
+// multiply.
+var decision_limits = prx.Limits{MaxNodes: 1 << 16}
+
+func TestFixtures(t *testing.T) {
+	for _, f := range Fixtures() {
+		t.Run(f.Name, func(t *testing.T) {
+			rs := f.RuleSet()
+
+			pt, err := prx.NewLALRTable(rs)
+			if err != nil {
+				t.Fatalf("expected an LALR(1) grammar, got %v", err)
+			}
+
+			for i, input := range f.Inputs {
+				tokens, err := f.Lex(input)
+				if err != nil {
+					t.Fatalf("input %d: expected no error, got %v", i, err)
+				}
+
+				res := pt.ParseResult(tokens)
+				if res.Err != nil {
+					t.Errorf("input %d: expected no error, got %v", i, res.Err)
+				}
+			}
+
+			p, err := prx.NewParser(rs)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			p.SetLimits(decision_limits)
+
+			stats, _ := f.Measure(p)
+			if len(stats) != len(f.Inputs) {
+				t.Fatalf("expected %d stats, got %d", len(f.Inputs), len(stats))
+			}
+
+			if stats[0].Shifts == 0 || stats[0].Reduces == 0 || stats[0].PeakDepth == 0 {
+				t.Errorf("expected shifts, reduces and a stack, got %v", stats[0])
+			}
+		})
+	}
+}
+
+func BenchmarkDecision(b *testing.B) {
+	for _, f := range Fixtures() {
+		p, err := prx.NewParser(f.RuleSet())
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		p.SetLimits(decision_limits)
+
+		b.Run(f.Name, func(b *testing.B) {
+			f.Benchmark(b, p)
+		})
+	}
+}
+
+func BenchmarkTable(b *testing.B) {
+	for _, f := range Fixtures() {
+		pt, err := prx.NewLALRTable(f.RuleSet())
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(f.Name, func(b *testing.B) {
+			f.Benchmark(b, pt)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package bench
+
+import (
+	"strconv"
+	"strings"
+)
+
+// JSON returns the fixture of JSON documents.
+//
+// Returns:
+//   - Fixture: The fixture.
+func JSON() Fixture {
+	var large strings.Builder
+
+	large.WriteString("[")
+
+	for i := 0; i < 100; i++ {
+		if i > 0 {
+			large.WriteString(", ")
+		}
+
+		large.WriteString(`{"id": ` + strconv.Itoa(i) + `, "name": "item \"` + strconv.Itoa(i) + `\"", "tags": ["a", "b"], "price": -` + strconv.Itoa(i) + `.5, "sold": false, "parent": null}`)
+	}
+
+	large.WriteString("]")
+
+	return Fixture{
+		Name: "json",
+		Inputs: []string{
+			`42`,
+			`{}`,
+			`{"name": "grammar", "stars": 3, "tags": ["go", "parser"], "archived": false, "fork": null}`,
+			`[[1, 2], [3, [4, [5, {"deep": true}]]], []]`,
+			large.String(),
+		},
+		rules: [][]Symbol{
+			{NtSource, NtValue, EtEOF},
+			{NtValue, NtObject},
+			{NtValue, NtArray},
+			{NtValue, TtString},
+			{NtValue, TtNumber},
+			{NtValue, TtTrue},
+			{NtValue, TtFalse},
+			{NtValue, TtNull},
+			{NtObject, TtLbrace, TtRbrace},
+			{NtObject, TtLbrace, NtMembers, TtRbrace},
+			{NtMembers, NtPair},
+			{NtMembers, NtMembers, TtComma, NtPair},
+			{NtPair, TtString, TtColon, NtValue},
+			{NtArray, TtLbrack, TtRbrack},
+			{NtArray, TtLbrack, NtElements, TtRbrack},
+			{NtElements, NtValue},
+			{NtElements, NtElements, TtComma, NtValue},
+		},
+		words: map[string]Symbol{
+			"true": TtTrue, "false": TtFalse, "null": TtNull,
+			"{": TtLbrace, "}": TtRbrace, "[": TtLbrack, "]": TtRbrack, ",": TtComma, ":": TtColon,
+		},
+	}
+}
+
+// Arithmetic returns the fixture of arithmetic expressions with the four
+// operations, parentheses and unary minus.
+//
+// Returns:
+//   - Fixture: The fixture.
+func Arithmetic() Fixture {
+	var large strings.Builder
+
+	for i := 0; i < 100; i++ {
+		if i > 0 {
+			large.WriteString(" - ")
+		}
+
+		large.WriteString("(" + strconv.Itoa(i) + " + 2) * -3 / " + strconv.Itoa(i+1))
+	}
+
+	return Fixture{
+		Name: "arithmetic",
+		Inputs: []string{
+			`1`,
+			`1 + 2 * 3`,
+			`(1 + 2) * (3 - 4) / -5`,
+			`((((1))))`,
+			large.String(),
+		},
+		rules: [][]Symbol{
+			{NtSource, NtExpr, EtEOF},
+			{NtExpr, NtExpr, TtPlus, NtTerm},
+			{NtExpr, NtExpr, TtMinus, NtTerm},
+			{NtExpr, NtTerm},
+			{NtTerm, NtTerm, TtStar, NtFactor},
+			{NtTerm, NtTerm, TtSlash, NtFactor},
+			{NtTerm, NtFactor},
+			{NtFactor, TtNumber},
+			{NtFactor, TtLparen, NtExpr, TtRparen},
+			{NtFactor, TtMinus, NtFactor},
+		},
+		words: map[string]Symbol{
+			"+": TtPlus, "-": TtMinus, "*": TtStar, "/": TtSlash, "(": TtLparen, ")": TtRparen,
+		},
+	}
+}
+
+// GoLike returns the fixture of a small Go-like language: functions and global
+// variables, with assignments, calls, returns, if and for statements, and
+// explicit semicolons.
+//
+// Returns:
+//   - Fixture: The fixture.
+func GoLike() Fixture {
+	var large strings.Builder
+
+	for i := 0; i < 50; i++ {
+		n := strconv.Itoa(i)
+
+		large.WriteString("var limit" + n + " = " + n + " * 2;\n")
+		large.WriteString("func f" + n + "(a, b) {\n")
+		large.WriteString("\tx := a + b * limit" + n + ";\n")
+		large.WriteString("\tfor x < 100 { x = x + f" + n + "(x, 1); }\n")
+		large.WriteString("\tif x < b { return x; } else { print(\"done\", x); }\n")
+		large.WriteString("\treturn (x - 1) * 2;\n")
+		large.WriteString("}\n")
+	}
+
+	return Fixture{
+		Name: "golike",
+		Inputs: []string{
+			`func main() {}`,
+			`var answer = 42;`,
+			`func add(a, b) { return a + b; }`,
+			`func main() { x := add(1, 2); if x < 3 { print("small"); } else { print("big"); } }`,
+			large.String(),
+		},
+		rules: [][]Symbol{
+			{NtSource, NtDecls, EtEOF},
+			{NtDecls, NtDecl},
+			{NtDecls, NtDecls, NtDecl},
+			{NtDecl, TtFunc, TtIdent, TtLparen, TtRparen, NtBlock},
+			{NtDecl, TtFunc, TtIdent, TtLparen, NtParams, TtRparen, NtBlock},
+			{NtDecl, TtVar, TtIdent, TtAssign, NtExpr, TtSemi},
+			{NtParams, TtIdent},
+			{NtParams, NtParams, TtComma, TtIdent},
+			{NtBlock, TtLbrace, TtRbrace},
+			{NtBlock, TtLbrace, NtStmts, TtRbrace},
+			{NtStmts, NtStmt},
+			{NtStmts, NtStmts, NtStmt},
+			{NtStmt, TtIdent, TtDefine, NtExpr, TtSemi},
+			{NtStmt, TtIdent, TtAssign, NtExpr, TtSemi},
+			{NtStmt, TtReturn, NtExpr, TtSemi},
+			{NtStmt, TtIf, NtExpr, NtBlock},
+			{NtStmt, TtIf, NtExpr, NtBlock, TtElse, NtBlock},
+			{NtStmt, TtFor, NtExpr, NtBlock},
+			{NtStmt, NtCall, TtSemi},
+			{NtExpr, NtExpr, TtPlus, NtTerm},
+			{NtExpr, NtExpr, TtMinus, NtTerm},
+			{NtExpr, NtExpr, TtLess, NtTerm},
+			{NtExpr, NtTerm},
+			{NtTerm, NtTerm, TtStar, NtFactor},
+			{NtTerm, NtFactor},
+			{NtFactor, TtNumber},
+			{NtFactor, TtString},
+			{NtFactor, TtIdent},
+			{NtFactor, NtCall},
+			{NtFactor, TtLparen, NtExpr, TtRparen},
+			{NtCall, TtIdent, TtLparen, TtRparen},
+			{NtCall, TtIdent, TtLparen, NtArgs, TtRparen},
+			{NtArgs, NtExpr},
+			{NtArgs, NtArgs, TtComma, NtExpr},
+		},
+		words: map[string]Symbol{
+			"func": TtFunc, "var": TtVar, "return": TtReturn, "if": TtIf, "else": TtElse, "for": TtFor,
+			"{": TtLbrace, "}": TtRbrace, "(": TtLparen, ")": TtRparen, ",": TtComma, ";": TtSemi,
+			"+": TtPlus, "-": TtMinus, "*": TtStar, "<": TtLess, "=": TtAssign, ":=": TtDefine,
+		},
+	}
+}
+
+// Fixtures returns every standard fixture.
+//
+// Returns:
+//   - []Fixture: The fixtures.
+func Fixtures() []Fixture {
+	return []Fixture{JSON(), Arithmetic(), GoLike()}
+}
@@ -0,0 +1,94 @@
+// Package cache stores serialized artifacts under a key so that they need not be
+// rebuilt on every start. It is only a store: it is up to the caller to encode
+// what it caches and to call GetOrCompile. The parsergen command, for instance,
+// caches its parse tables with the -cache flag. Embedders can plug in any backend
+// (Redis, a database, ...) by implementing Cache.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+)
+
+// Cache is a store of serialized artifacts.
+type Cache interface {
+	// Get returns the artifact stored under the given key.
+	//
+	// Parameters:
+	//   - key: The key of the artifact.
+	//
+	// Returns:
+	//   - []byte: The artifact. Nil if it is not cached.
+	//   - bool: True if the artifact is cached, false otherwise.
+	//   - error: An error if the backend failed.
+	Get(key string) ([]byte, bool, error)
+
+	// Put stores the given artifact under the given key, replacing any previous one.
+	//
+	// Parameters:
+	//   - key: The key of the artifact.
+	//   - data: The artifact.
+	//
+	// Returns:
+	//   - error: An error if the backend failed.
+	Put(key string, data []byte) error
+}
+
+// Key computes a key from the sources an artifact is compiled from, so that
+// changing any of them invalidates the artifact.
+//
+// Parameters:
+//   - sources: The sources of the artifact. They are hashed in order.
+//
+// Returns:
+//   - string: The key; the hex-encoded SHA-256 hash of the sources.
+func Key(sources ...[]byte) string {
+	h := sha256.New()
+
+	for _, source := range sources {
+		var size [8]byte
+
+		binary.LittleEndian.PutUint64(size[:], uint64(len(source)))
+
+		// The size prefix keeps ("ab", "c") and ("a", "bc") apart.
+		_, _ = h.Write(size[:])
+		_, _ = h.Write(source)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// GetOrCompile returns the artifact stored under the given key or, if it is not
+// cached, compiles it and stores it.
+//
+// Parameters:
+//   - c: The cache. If nil, the artifact is always compiled.
+//   - key: The key of the artifact.
+//   - compile: The function that compiles the artifact.
+//
+// Returns:
+//   - []byte: The artifact.
+//   - error: An error if the compilation or the cache failed.
+//
+// Failing to store the compiled artifact is reported along with the artifact.
+func GetOrCompile(c Cache, key string, compile func() ([]byte, error)) ([]byte, error) {
+	if c == nil {
+		return compile()
+	}
+
+	data, ok, err := c.Get(key)
+	if err != nil {
+		return nil, err
+	} else if ok {
+		return data, nil
+	}
+
+	data, err = compile()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.Put(key, data)
+	return data, err
+}
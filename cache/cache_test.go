@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	if Key([]byte("ab"), []byte("c")) == Key([]byte("a"), []byte("bc")) {
+		t.Errorf("expected the keys of different sources to differ")
+	}
+
+	if Key([]byte("a")) != Key([]byte("a")) {
+		t.Errorf("expected the keys of the same sources to be equal")
+	}
+}
+
+func TestGetOrCompile(t *testing.T) {
+	dir, err := NewDir(t.TempDir())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, c := range []Cache{NewMemory(), dir} {
+		calls := 0
+
+		compile := func() ([]byte, error) {
+			calls++
+
+			return []byte("artifact"), nil
+		}
+
+		for range 2 {
+			data, err := GetOrCompile(c, Key([]byte("source")), compile)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			} else if !bytes.Equal(data, []byte("artifact")) {
+				t.Errorf("expected %q, got %q", "artifact", data)
+			}
+		}
+
+		if calls != 1 {
+			t.Errorf("%T: expected %d compilation, got %d", c, 1, calls)
+		}
+	}
+
+	want := errors.New("boom")
+
+	_, err = GetOrCompile(NewMemory(), "key", func() ([]byte, error) { return nil, want })
+	if !errors.Is(err, want) {
+		t.Errorf("expected %v, got %v", want, err)
+	}
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+)
+
+// Dir is a cache that stores each artifact in its own file of a directory. It is
+// safe for concurrent use, including by several processes.
+type Dir struct {
+	// path is the path of the directory.
+	path string
+}
+
+// NewDir creates a new cache in the given directory. The directory is created if
+// it does not exist.
+//
+// Parameters:
+//   - path: The path of the directory.
+//
+// Returns:
+//   - *Dir: The new cache.
+//   - error: An error if the directory could not be created.
+func NewDir(path string) (*Dir, error) {
+	if path == "" {
+		return nil, gcers.NewErrInvalidParameter("path", errors.New("must not be empty"))
+	}
+
+	err := os.MkdirAll(path, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dir{
+		path: path,
+	}, nil
+}
+
+// file returns the path of the file of the given key. Keys are hashed so that
+// any string is a valid key.
+//
+// Parameters:
+//   - key: The key of the artifact.
+//
+// Returns:
+//   - string: The path of the file.
+func (d Dir) file(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(d.path, hex.EncodeToString(sum[:]))
+}
+
+// Get implements the Cache interface.
+func (d Dir) Get(key string) ([]byte, bool, error) {
+	data, err := os.ReadFile(d.file(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+// Put implements the Cache interface.
+//
+// The artifact is written to a temporary file that is then renamed, so readers
+// never see a partially written artifact.
+func (d Dir) Put(key string, data []byte) error {
+	tmp, err := os.CreateTemp(d.path, "tmp-*")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Close()
+	} else {
+		_ = tmp.Close()
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), d.file(key))
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+
+	return err
+}
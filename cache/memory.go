@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"slices"
+	"sync"
+)
+
+// Memory is an in-memory cache. It is safe for concurrent use.
+type Memory struct {
+	// mu protects entries.
+	mu sync.RWMutex
+
+	// entries maps the keys to the artifacts.
+	entries map[string][]byte
+}
+
+// NewMemory creates a new, empty, in-memory cache.
+//
+// Returns:
+//   - *Memory: The new cache. Never returns nil.
+func NewMemory() *Memory {
+	return &Memory{
+		entries: make(map[string][]byte),
+	}
+}
+
+// Get implements the Cache interface.
+//
+// The returned artifact does not share memory with the cache.
+func (m *Memory) Get(key string) ([]byte, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	data, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	return slices.Clone(data), true, nil
+}
+
+// Put implements the Cache interface.
+func (m *Memory) Put(key string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.entries == nil {
+		m.entries = make(map[string][]byte)
+	}
+
+	m.entries[key] = slices.Clone(data)
+
+	return nil
+}
+
+// Len returns the number of cached artifacts.
+//
+// Returns:
+//   - int: The number of cached artifacts.
+func (m *Memory) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.entries)
+}
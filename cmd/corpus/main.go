@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/PlayerR9/grammar/corpus"
+	"github.com/PlayerR9/grammar/plugins"
+)
+
+var (
+	// Logger is the logger.
+	Logger *log.Logger
+
+	DirFlag *string
+
+	OutputFlag *string
+
+	VerifyFlag *string
+
+	PluginFlag *string
+)
+
+func init() {
+	Logger = log.New(os.Stdout, "[corpus]: ", log.LstdFlags)
+
+	DirFlag = flag.String("dir", "", "The directory of the corpus to bundle: <name>.input files, with optional <name>.tokens and <name>.tree.json files.")
+	OutputFlag = flag.String("o", "corpus.tar.gz", "The archive to write.")
+	VerifyFlag = flag.String("verify", "", "The archive to verify instead of bundling a directory.")
+	PluginFlag = flag.String("plugin", "", "A Go plugin that exports a grammar provider. If set, the input of every case must parse with its grammar.")
+}
+
+// check_cases is a helper function that parses the input of every case of a
+// corpus with the grammar of a plugin.
+//
+// Parameters:
+//   - c: The corpus. Assumed to be non-nil.
+//   - path: The path of the plugin.
+//
+// Returns:
+//   - error: An error if the plugin could not be loaded or if a case does not
+//     parse.
+func check_cases(c *corpus.Corpus, path string) error {
+	p, err := plugins.Open(path)
+	if err != nil {
+		return err
+	}
+
+	for _, cs := range c.Cases {
+		res, err := p.Parse(cs.Input)
+		if err == nil {
+			err = res.Err
+		}
+
+		if err != nil {
+			return fmt.Errorf("case %q does not parse with %s: %w", cs.Name, p.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+func main() {
+	flag.Parse()
+
+	if *VerifyFlag != "" {
+		data, err := os.ReadFile(*VerifyFlag)
+		if err != nil {
+			Logger.Fatalf("Failed to read archive: %s", err.Error())
+		}
+
+		c, err := corpus.Read(bytes.NewReader(data))
+		if err != nil {
+			Logger.Fatalf("Invalid archive: %s", err.Error())
+		}
+
+		if *PluginFlag != "" {
+			err = check_cases(c, *PluginFlag)
+			if err != nil {
+				Logger.Fatalf("Invalid archive: %s", err.Error())
+			}
+		}
+
+		Logger.Printf("%q is valid: %d cases, digest %s", *VerifyFlag, len(c.Cases), c.Digest())
+
+		return
+	}
+
+	if *DirFlag == "" {
+		flag.PrintDefaults()
+
+		Logger.Fatalf("Failed to parse flags: dir flag is required")
+	}
+
+	c, err := corpus.FromDir(os.DirFS(*DirFlag))
+	if err != nil {
+		Logger.Fatalf("Failed to read corpus: %s", err.Error())
+	}
+
+	if *PluginFlag != "" {
+		err = check_cases(c, *PluginFlag)
+		if err != nil {
+			Logger.Fatalf("Invalid corpus: %s", err.Error())
+		}
+	}
+
+	var buf bytes.Buffer
+
+	err = c.Write(&buf)
+	if err != nil {
+		Logger.Fatalf("Failed to bundle corpus: %s", err.Error())
+	}
+
+	err = os.WriteFile(*OutputFlag, buf.Bytes(), 0o644)
+	if err != nil {
+		Logger.Fatal(err.Error())
+	}
+
+	Logger.Printf("Successfully bundled %d cases into %q (digest %s)", len(c.Cases), *OutputFlag, c.Digest())
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"go/format"
+	"os"
+
+	ggen "github.com/PlayerR9/go-commons/generator"
+	pkg "github.com/PlayerR9/grammar/cmd/lexergen/pkg"
+)
+
+func main() {
+	input, file_name, err := pkg.ParseFlags()
+	if err != nil {
+		ggen.PrintFlags()
+
+		pkg.Logger.Fatalf("Failed to parse flags: %s", err.Error())
+	}
+
+	content, err := os.ReadFile(input)
+	if err != nil {
+		pkg.Logger.Fatalf("Failed to read config: %s", err.Error())
+	}
+
+	cfg, err := pkg.LoadLexerConfig(content)
+	if err != nil {
+		pkg.Logger.Fatalf("Invalid config: %s", err.Error())
+	}
+
+	data, err := pkg.NewGenData(input, cfg)
+	if err != nil {
+		pkg.Logger.Fatalf("Invalid config: %s", err.Error())
+	}
+
+	res, err := pkg.Generator.Generate(pkg.OutputLocFlag, file_name, data)
+	if err != nil {
+		pkg.Logger.Fatalf("Failed to generate: %s", err.Error())
+	}
+
+	res.Data, err = format.Source(res.Data)
+	if err != nil {
+		pkg.Logger.Fatalf("Failed to format: %s", err.Error())
+	}
+
+	err = res.WriteFile()
+	if err != nil {
+		pkg.Logger.Fatal(err.Error())
+	}
+
+	pkg.Logger.Printf("Successfully generated: %q", res.DestLoc)
+}
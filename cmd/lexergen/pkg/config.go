@@ -0,0 +1,127 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"regexp"
+)
+
+// TokenDef is the definition of a token in the config file.
+type TokenDef struct {
+	// Name is the name of the token, used for its String method and, capitalized,
+	// for its constant ("Tt" + Name).
+	Name string `json:"name"`
+
+	// Literal is the word of the token. Empty if the token is defined by Pattern.
+	Literal string `json:"literal,omitempty"`
+
+	// Pattern is the regular expression of the token. Empty if the token is
+	// defined by Literal.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// LexerConfig is the content of the config file of the command.
+//
+// Format:
+//
+//	{
+//		"tokens": [
+//			{"name": "Plus", "literal": "+"},
+//			{"name": "Number", "pattern": "[0-9]+"}
+//		],
+//		"skip": [" ", "\t", "\n"],
+//		"skip_patterns": ["#[^\n]*"],
+//		"longest_match": true
+//	}
+//
+// The tokens are numbered in order, after the EOF token.
+type LexerConfig struct {
+	// Tokens are the definitions of the tokens.
+	Tokens []TokenDef `json:"tokens"`
+
+	// Skip are the words to skip.
+	Skip []string `json:"skip,omitempty"`
+
+	// SkipPatterns are the regular expressions of the text to skip.
+	SkipPatterns []string `json:"skip_patterns,omitempty"`
+
+	// LongestMatch is true if only the longest literals are matched; that is, if
+	// "+=" is never lexed as "+" followed by "=".
+	LongestMatch bool `json:"longest_match,omitempty"`
+}
+
+// LoadLexerConfig decodes and validates a config file.
+//
+// Parameters:
+//   - data: The content of the config file.
+//
+// Returns:
+//   - *LexerConfig: The config. Nil if an error occurred.
+//   - error: An error if the content is not valid JSON or if the config is
+//     invalid. Every problem of the config is reported.
+func LoadLexerConfig(data []byte) (*LexerConfig, error) {
+	var cfg LexerConfig
+
+	err := json.Unmarshal(data, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// validate is a helper function that validates the config.
+//
+// Returns:
+//   - error: The problems of the config, joined. Nil if it is valid.
+func (cfg LexerConfig) validate() error {
+	if len(cfg.Tokens) == 0 {
+		return errors.New("no token is defined")
+	}
+
+	var errs []error
+
+	seen := make(map[string]bool, len(cfg.Tokens))
+
+	for i, def := range cfg.Tokens {
+		if def.Name == "" || !token.IsIdentifier("Tt"+def.Name) {
+			errs = append(errs, fmt.Errorf("token %d: invalid name %q", i, def.Name))
+		} else if seen[def.Name] || def.Name == "EOF" {
+			errs = append(errs, fmt.Errorf("token %d: name %q is already used", i, def.Name))
+		}
+
+		seen[def.Name] = true
+
+		switch {
+		case (def.Literal == "") == (def.Pattern == ""):
+			errs = append(errs, fmt.Errorf("token %q: exactly one of literal and pattern must be set", def.Name))
+		case def.Pattern != "":
+			_, err := regexp.Compile(def.Pattern)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("token %q: %w", def.Name, err))
+			}
+		}
+	}
+
+	for _, word := range cfg.Skip {
+		if word == "" {
+			errs = append(errs, errors.New("skip words must not be empty"))
+		}
+	}
+
+	for _, expr := range cfg.SkipPatterns {
+		_, err := regexp.Compile(expr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("skip pattern %q: %w", expr, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,40 @@
+package pkg
+
+import (
+	"errors"
+	"flag"
+	"path/filepath"
+	"strings"
+
+	ggen "github.com/PlayerR9/go-commons/generator"
+)
+
+var (
+	OutputLocFlag *ggen.OutputLocVal
+
+	InputFlag *string
+)
+
+func init() {
+	InputFlag = flag.String("i", "", "The JSON file of the token definitions. This flag is required.")
+
+	OutputLocFlag = ggen.NewOutputFlag("<config>_lexer.go", false)
+}
+
+// ParseFlags parses the flags of the command.
+//
+// Returns:
+//   - string: The path of the config file.
+//   - string: The default name of the output file.
+//   - error: An error if the flags are invalid.
+func ParseFlags() (string, string, error) {
+	ggen.ParseFlags()
+
+	if *InputFlag == "" {
+		return "", "", errors.New("i flag is required")
+	}
+
+	base := strings.TrimSuffix(filepath.Base(*InputFlag), filepath.Ext(*InputFlag))
+
+	return *InputFlag, base + "_lexer.go", nil
+}
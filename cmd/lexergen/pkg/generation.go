@@ -0,0 +1,177 @@
+package pkg
+
+import (
+	"log"
+	"os"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	ggen "github.com/PlayerR9/go-commons/generator"
+	"github.com/PlayerR9/grammar/internal/tokentype"
+)
+
+var (
+	// Logger is the logger.
+	Logger *log.Logger
+)
+
+func init() {
+	Logger = log.New(os.Stdout, "[lexergen]: ", log.LstdFlags)
+}
+
+type GenData struct {
+	PackageName string
+
+	// Source is the name of the config file.
+	Source string
+
+	// Type is the type of the tokens.
+	Type *tokentype.Type
+
+	*LexerConfig
+}
+
+// NewGenData creates the data of the lexer of a config.
+//
+// Parameters:
+//   - source: The name of the config file.
+//   - cfg: The config.
+//
+// Returns:
+//   - *GenData: The data. Nil if an error occurred.
+//   - error: An error if cfg is nil or if two tokens have the same constant.
+func NewGenData(source string, cfg *LexerConfig) (*GenData, error) {
+	if cfg == nil {
+		return nil, gcers.NewErrNilParameter("cfg")
+	}
+
+	names := make([]string, 0, len(cfg.Tokens))
+
+	for _, def := range cfg.Tokens {
+		names = append(names, def.Name)
+	}
+
+	t, err := tokentype.New("TokenType", names, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GenData{
+		Source:      source,
+		Type:        t,
+		LexerConfig: cfg,
+	}, nil
+}
+
+// SetPackageName implements the generator.Generater interface.
+func (gd *GenData) SetPackageName(pkg_name string) bool {
+	if gd == nil {
+		return false
+	}
+
+	gd.PackageName = pkg_name
+
+	return true
+}
+
+// Const gives the name of the constant of a token.
+//
+// Parameters:
+//   - name: The name of the token.
+//
+// Returns:
+//   - string: The name of the constant.
+func (gd GenData) Const(name string) string {
+	return tokentype.ConstName("Tt", name)
+}
+
+// HasPatterns checks whether some tokens or skip rules are defined by a pattern.
+//
+// Returns:
+//   - bool: True if a pattern is defined, false otherwise.
+func (gd GenData) HasPatterns() bool {
+	if len(gd.SkipPatterns) > 0 {
+		return true
+	}
+
+	for _, def := range gd.Tokens {
+		if def.Pattern != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+var (
+	Generator *ggen.CodeGenerator[*GenData]
+)
+
+func init() {
+	tmp, err := ggen.NewCodeGeneratorFromTemplate[*GenData]("", templ)
+	if err != nil {
+		Logger.Fatalf("Failed to create code generator: %s", err.Error())
+	}
+
+	Generator = tmp
+}
+
+// templ is the template for the lexer.
+const templ = `// Code generated by lexergen from {{ .Source }}; DO NOT EDIT.
+package {{ .PackageName }}
+
+import (
+	"strconv"
+
+	lxr "github.com/PlayerR9/grammar/PREV/OLD/lexing"
+)
+
+{{ with .Type }}` + tokentype.Template + `{{ end }}
+// NewLexer creates a new lexer of the tokens.
+//
+// Returns:
+//   - *lxr.Lexer[TokenType]: The new lexer.
+//   - error: An error if the lexer could not be created.
+func NewLexer() (*lxr.Lexer[TokenType], error) {
+	opts := []lxr.Option[TokenType]{
+{{- range .Tokens }}{{ if .Literal }}
+		lxr.WithMatch({{ $.Const .Name }}, {{ printf "%q" .Literal }}),
+{{- end }}{{ end }}
+{{- if .Skip }}
+		lxr.WithSkip[TokenType]({{ range $i, $w := .Skip }}{{ if $i }}, {{ end }}{{ printf "%q" $w }}{{ end }}),
+{{- end }}
+{{- if .LongestMatch }}
+		lxr.WithLongestMatch[TokenType](true),
+{{- end }}
+	}
+{{ if .HasPatterns }}
+	defs := []struct {
+		symbol TokenType
+		expr   string
+		skip   bool
+	}{
+{{- range .Tokens }}{{ if .Pattern }}
+		{ {{- $.Const .Name }}, {{ printf "%q" .Pattern }}, false},
+{{- end }}{{ end }}
+{{- range .SkipPatterns }}
+		{EtEOF, {{ printf "%q" . }}, true},
+{{- end }}
+	}
+
+	patterns := make([]lxr.Pattern[TokenType], 0, len(defs))
+
+	for _, def := range defs {
+		p, err := lxr.NewPattern(def.symbol, def.expr)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Skip = def.skip
+
+		patterns = append(patterns, p)
+	}
+
+	opts = append(opts, lxr.WithPatterns(patterns...))
+{{ end }}
+	return lxr.NewLexer(opts...)
+}
+`
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"go/format"
+	"os"
+
+	ggen "github.com/PlayerR9/go-commons/generator"
+	"github.com/PlayerR9/grammar/cache"
+	pkg "github.com/PlayerR9/grammar/cmd/parsergen/pkg"
+)
+
+func main() {
+	input, file_name, err := pkg.ParseFlags()
+	if err != nil {
+		ggen.PrintFlags()
+
+		pkg.Logger.Fatalf("Failed to parse flags: %s", err.Error())
+	}
+
+	content, err := os.ReadFile(input)
+	if err != nil {
+		pkg.Logger.Fatalf("Failed to read grammar: %s", err.Error())
+	}
+
+	var c cache.Cache
+
+	if *pkg.CacheFlag != "" {
+		dir, err := cache.NewDir(*pkg.CacheFlag)
+		if err != nil {
+			pkg.Logger.Fatalf("Failed to open the cache: %s", err.Error())
+		}
+
+		c = dir
+	}
+
+	table, err := pkg.LoadTable(c, content)
+	if err != nil {
+		pkg.Logger.Fatalf("Failed to make the parse table: %s", err.Error())
+	}
+
+	if *pkg.StatsFlag {
+		pkg.Logger.Printf("Statistics of %q:\n%s", input, table.Stats.String())
+	}
+
+	data := &pkg.GenData{
+		Source:    input,
+		TableData: table,
+	}
+
+	res, err := pkg.Generator.Generate(pkg.OutputLocFlag, file_name, data)
+	if err != nil {
+		pkg.Logger.Fatalf("Failed to generate: %s", err.Error())
+	}
+
+	// The tables are aligned by gofmt rather than by the template.
+	res.Data, err = format.Source(res.Data)
+	if err != nil {
+		pkg.Logger.Fatalf("Failed to format: %s", err.Error())
+	}
+
+	err = res.WriteFile()
+	if err != nil {
+		pkg.Logger.Fatal(err.Error())
+	}
+
+	pkg.Logger.Printf("Successfully generated: %q", res.DestLoc)
+}
@@ -0,0 +1,48 @@
+package pkg
+
+import (
+	"errors"
+	"flag"
+	"path/filepath"
+	"strings"
+
+	ggen "github.com/PlayerR9/go-commons/generator"
+)
+
+var (
+	OutputLocFlag *ggen.OutputLocVal
+
+	InputFlag *string
+
+	CacheFlag *string
+
+	StatsFlag *bool
+)
+
+func init() {
+	InputFlag = flag.String("i", "", "The .grammar file to generate the parser from. This flag is required.")
+
+	CacheFlag = flag.String("cache", "", "The directory where the parse tables are cached between runs. If empty, the tables are not cached.")
+
+	StatsFlag = flag.Bool("stats", false, "Print the statistics of the parse table (rules, symbols, items, states and memory estimate).")
+
+	OutputLocFlag = ggen.NewOutputFlag("<grammar>_parser.go", false)
+}
+
+// ParseFlags parses the flags of the command.
+//
+// Returns:
+//   - string: The path of the .grammar file.
+//   - string: The default name of the output file.
+//   - error: An error if the flags are invalid.
+func ParseFlags() (string, string, error) {
+	ggen.ParseFlags()
+
+	if *InputFlag == "" {
+		return "", "", errors.New("i flag is required")
+	}
+
+	base := strings.TrimSuffix(filepath.Base(*InputFlag), filepath.Ext(*InputFlag))
+
+	return *InputFlag, base + "_parser.go", nil
+}
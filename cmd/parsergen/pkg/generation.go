@@ -0,0 +1,196 @@
+package pkg
+
+import (
+	"log"
+	"os"
+
+	ggen "github.com/PlayerR9/go-commons/generator"
+	"github.com/PlayerR9/grammar/internal/tokentype"
+)
+
+var (
+	// Logger is the logger.
+	Logger *log.Logger
+)
+
+func init() {
+	Logger = log.New(os.Stdout, "[parsergen]: ", log.LstdFlags)
+}
+
+type GenData struct {
+	PackageName string
+
+	// Source is the name of the .grammar file.
+	Source string
+
+	*TableData
+}
+
+// SetPackageName implements the generator.Generater interface.
+func (gd *GenData) SetPackageName(pkg_name string) bool {
+	if gd == nil {
+		return false
+	}
+
+	gd.PackageName = pkg_name
+
+	return true
+}
+
+var (
+	Generator *ggen.CodeGenerator[*GenData]
+)
+
+func init() {
+	tmp, err := ggen.NewCodeGeneratorFromTemplate[*GenData]("", templ)
+	if err != nil {
+		Logger.Fatalf("Failed to create code generator: %s", err.Error())
+	}
+
+	Generator = tmp
+}
+
+// templ is the template for the parser.
+const templ = `// Code generated by parsergen from {{ .Source }}; DO NOT EDIT.
+package {{ .PackageName }}
+
+import (
+	"fmt"
+	"strconv"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+{{ with .Type }}` + tokentype.Template + `{{ end }}
+// Rule is a rule of the grammar.
+type Rule struct {
+	// Lhs is the left-hand side of the rule.
+	Lhs TokenType
+
+	// Rhs is the right-hand side of the rule.
+	Rhs []TokenType
+}
+
+// Rules are the rules of the grammar. The first one is the start rule.
+var Rules = []Rule{
+{{- range .Rules }}
+	{Lhs: {{ .Lhs }}, Rhs: []TokenType{ {{- range $i, $s := .Rhs }}{{ if $i }}, {{ end }}{{ $s }}{{ end -}} }},
+{{- end }}
+}
+
+// action_kind is the kind of an action of the parse table.
+type action_kind int
+
+const (
+	act_shift action_kind = iota
+	act_reduce
+	act_accept
+)
+
+// action is an action of the parse table.
+type action struct {
+	// kind is the kind of the action.
+	kind action_kind
+
+	// arg is the next state, for shifts, or the index of the rule, otherwise.
+	arg int
+}
+
+// action_table are the actions of each state, by lookahead.
+var action_table = [...]map[TokenType]action{
+{{- range .States }}
+	{
+	{{- range .Actions }}
+		{{ .Symbol }}: { {{- .Kind }}, {{ .Arg -}} },
+	{{- end }}
+	},
+{{- end }}
+}
+
+// goto_table are the states reached from each state, by non-terminal.
+var goto_table = [...]map[TokenType]int{
+{{- range .States }}
+	{
+	{{- range .Gotos }}
+		{{ .Symbol }}: {{ .State }},
+	{{- end }}
+	},
+{{- end }}
+}
+
+// Parse parses the given tokens with the parse table.
+//
+// Parameters:
+//   - tokens: The tokens, such as the ones of a lexer. The EOF token is optional.
+//
+// Returns:
+//   - gr.Result[*gr.Token[TokenType]]: The result of the parse. On success, its
+//     root is of type {{ .Start }}.
+func Parse(tokens []*gr.Token[TokenType]) gr.Result[*gr.Token[TokenType]] {
+	eof := &gr.Token[TokenType]{Type: EtEOF, Pos: -1}
+	if len(tokens) > 0 && tokens[len(tokens)-1].Type == EtEOF {
+		eof = tokens[len(tokens)-1]
+		tokens = tokens[:len(tokens)-1]
+	}
+
+	// end is the end of the input, where the errors at EOF are reported when the
+	// position of EOF is not known.
+	end := 0
+	if len(tokens) > 0 {
+		end = tokens[len(tokens)-1].Span().End
+	}
+
+	states := []int{0}
+
+	var stack []*gr.Token[TokenType]
+
+	for {
+		la := eof
+		if len(tokens) > 0 {
+			la = tokens[0]
+		}
+
+		act, ok := action_table[states[len(states)-1]][la.Type]
+		if !ok {
+			forest := make([]*gr.Token[TokenType], len(stack))
+			copy(forest, stack)
+
+			err := fmt.Errorf("unexpected token %s", la.Type.String())
+
+			at := la.Pos
+			if la == eof && at < 0 {
+				at = end
+			}
+
+			return gr.NewFailedResult(forest, err).At(at)
+		}
+
+		switch act.kind {
+		case act_shift:
+			if len(tokens) > 0 {
+				tokens = tokens[1:]
+			}
+
+			stack = append(stack, la)
+			states = append(states, act.arg)
+		case act_accept:
+			// The start rule ends with EtEOF, which is not part of the tree.
+			return gr.NewResult(stack[len(stack)-2])
+		case act_reduce:
+			rule := Rules[act.arg]
+			n := len(rule.Rhs)
+
+			children := make([]*gr.Token[TokenType], n)
+			copy(children, stack[len(stack)-n:])
+
+			stack = stack[:len(stack)-n]
+			states = states[:len(states)-n]
+
+			tk, _ := gr.NewToken(rule.Lhs, "", children)
+
+			stack = append(stack, tk)
+			states = append(states, goto_table[states[len(states)-1]][rule.Lhs])
+		}
+	}
+}
+`
@@ -0,0 +1,279 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// GrammarFile is the content of a .grammar file.
+//
+// Format:
+//
+//	# Comments run from '#' to the end of the line.
+//	%token Number Plus Star
+//	%start expr
+//
+//	expr : expr Plus term | term ;
+//	term : term Star Number | Number ;
+//
+// The %token directives declare the terminals, in order; every other symbol must
+// be the left-hand side of a rule. The %start directive is optional; by default,
+// the start symbol is the left-hand side of the first rule.
+type GrammarFile struct {
+	// Terminals are the declared terminals, in order of declaration.
+	Terminals []string
+
+	// NonTerminals are the left-hand sides of the rules, in order of appearance.
+	NonTerminals []string
+
+	// Start is the start symbol.
+	Start string
+
+	// Rules are the rules of the grammar, in order of appearance. Alternatives
+	// are split into separate rules.
+	Rules []FileRule
+}
+
+// FileRule is a rule of a .grammar file.
+type FileRule struct {
+	// Lhs is the left-hand side of the rule.
+	Lhs string
+
+	// Rhs is the right-hand side of the rule. Never empty.
+	Rhs []string
+}
+
+// file_token is a token of a .grammar file.
+type file_token struct {
+	// text is the text of the token; either an identifier, a directive (with its
+	// '%') or one of ":", "|" and ";".
+	text string
+
+	// line is the line of the token, starting from 1.
+	line int
+}
+
+// is_ident_rune is a helper function that checks whether a rune can be part of
+// an identifier.
+//
+// Parameters:
+//   - r: The rune.
+//
+// Returns:
+//   - bool: True if r can be part of an identifier, false otherwise.
+func is_ident_rune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// scan_file is a helper function that splits the content of a .grammar file into
+// tokens.
+//
+// Parameters:
+//   - data: The content of the file.
+//
+// Returns:
+//   - []file_token: The tokens.
+//   - error: An error if the content holds an invalid character.
+func scan_file(data []byte) ([]file_token, error) {
+	var tokens []file_token
+
+	line := 1
+
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+
+		switch {
+		case r == '\n':
+			line++
+		case unicode.IsSpace(r):
+		case r == '#':
+			for len(data) > size && data[size] != '\n' {
+				size++
+			}
+		case r == ':' || r == '|' || r == ';':
+			tokens = append(tokens, file_token{text: string(r), line: line})
+		case r == '%' || is_ident_rune(r):
+			for len(data) > size {
+				next, next_size := utf8.DecodeRune(data[size:])
+				if !is_ident_rune(next) {
+					break
+				}
+
+				size += next_size
+			}
+
+			text := string(data[:size])
+			if text == "%" {
+				return nil, fmt.Errorf("line %d: expected a directive after '%%'", line)
+			}
+
+			tokens = append(tokens, file_token{text: text, line: line})
+		default:
+			return nil, fmt.Errorf("line %d: unexpected character %q", line, r)
+		}
+
+		data = data[size:]
+	}
+
+	return tokens, nil
+}
+
+// ParseGrammarFile parses the content of a .grammar file.
+//
+// Parameters:
+//   - data: The content of the file.
+//
+// Returns:
+//   - *GrammarFile: The grammar. Nil if an error occurred.
+//   - error: An error if the content is not a valid grammar.
+func ParseGrammarFile(data []byte) (*GrammarFile, error) {
+	tokens, err := scan_file(data)
+	if err != nil {
+		return nil, err
+	}
+
+	gf := &GrammarFile{}
+
+	for len(tokens) > 0 {
+		head := tokens[0]
+		tokens = tokens[1:]
+
+		switch head.text {
+		case "%token":
+			for len(tokens) > 0 && tokens[0].line == head.line && is_ident_rune([]rune(tokens[0].text)[0]) {
+				if slices.Contains(gf.Terminals, tokens[0].text) {
+					return nil, fmt.Errorf("line %d: terminal %q is declared twice", head.line, tokens[0].text)
+				}
+
+				gf.Terminals = append(gf.Terminals, tokens[0].text)
+				tokens = tokens[1:]
+			}
+		case "%start":
+			if len(tokens) == 0 || tokens[0].line != head.line {
+				return nil, fmt.Errorf("line %d: expected a symbol after %%start", head.line)
+			}
+
+			gf.Start = tokens[0].text
+			tokens = tokens[1:]
+		case ":", "|", ";":
+			return nil, fmt.Errorf("line %d: expected a rule, got %q instead", head.line, head.text)
+		default:
+			if head.text[0] == '%' {
+				return nil, fmt.Errorf("line %d: unknown directive %q", head.line, head.text)
+			}
+
+			tokens, err = gf.parse_rule(head, tokens)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err = gf.check()
+	if err != nil {
+		return nil, err
+	}
+
+	return gf, nil
+}
+
+// parse_rule is a helper function that parses the alternatives of a rule.
+//
+// Parameters:
+//   - lhs: The left-hand side of the rule.
+//   - tokens: The tokens after the left-hand side.
+//
+// Returns:
+//   - []file_token: The tokens after the rule.
+//   - error: An error if the rule is not well-formed.
+func (gf *GrammarFile) parse_rule(lhs file_token, tokens []file_token) ([]file_token, error) {
+	if len(tokens) == 0 || tokens[0].text != ":" {
+		return nil, fmt.Errorf("line %d: expected ':' after %q", lhs.line, lhs.text)
+	}
+
+	tokens = tokens[1:]
+
+	if !slices.Contains(gf.NonTerminals, lhs.text) {
+		gf.NonTerminals = append(gf.NonTerminals, lhs.text)
+	}
+
+	var rhs []string
+
+	for {
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("line %d: rule %q is not terminated by ';'", lhs.line, lhs.text)
+		}
+
+		tk := tokens[0]
+		tokens = tokens[1:]
+
+		switch tk.text {
+		case "|", ";":
+			if len(rhs) == 0 {
+				return nil, fmt.Errorf("line %d: rule %q has an empty alternative", tk.line, lhs.text)
+			}
+
+			gf.Rules = append(gf.Rules, FileRule{Lhs: lhs.text, Rhs: rhs})
+			rhs = nil
+
+			if tk.text == ";" {
+				return tokens, nil
+			}
+		case ":":
+			return nil, fmt.Errorf("line %d: unexpected ':' in rule %q", tk.line, lhs.text)
+		default:
+			if tk.text[0] == '%' {
+				return nil, fmt.Errorf("line %d: unexpected directive %q in rule %q", tk.line, tk.text, lhs.text)
+			}
+
+			rhs = append(rhs, tk.text)
+		}
+	}
+}
+
+// check is a helper function that checks that every symbol is either a terminal
+// or a non-terminal, and sets the start symbol.
+//
+// Returns:
+//   - error: An error if a symbol is undefined or defined twice, or if a rule
+//     is repeated.
+func (gf *GrammarFile) check() error {
+	if len(gf.Rules) == 0 {
+		return errors.New("the grammar has no rule")
+	}
+
+	var errs []error
+
+	for _, nt := range gf.NonTerminals {
+		if slices.Contains(gf.Terminals, nt) {
+			errs = append(errs, fmt.Errorf("%q is both a terminal and a non-terminal", nt))
+		}
+	}
+
+	for i, rule := range gf.Rules {
+		dup := slices.ContainsFunc(gf.Rules[:i], func(other FileRule) bool {
+			return other.Lhs == rule.Lhs && slices.Equal(other.Rhs, rule.Rhs)
+		})
+
+		if dup {
+			errs = append(errs, fmt.Errorf("rule %q has the same alternative twice", rule.Lhs))
+		}
+
+		for _, symbol := range rule.Rhs {
+			if !slices.Contains(gf.Terminals, symbol) && !slices.Contains(gf.NonTerminals, symbol) {
+				errs = append(errs, fmt.Errorf("symbol %q of rule %q is undefined", symbol, rule.Lhs))
+			}
+		}
+	}
+
+	if gf.Start == "" {
+		gf.Start = gf.Rules[0].Lhs
+	} else if !slices.Contains(gf.NonTerminals, gf.Start) {
+		errs = append(errs, fmt.Errorf("start symbol %q is not a non-terminal", gf.Start))
+	}
+
+	return errors.Join(errs...)
+}
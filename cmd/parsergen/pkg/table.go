@@ -0,0 +1,392 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	prx "github.com/PlayerR9/grammar/PREV/parser"
+	"github.com/PlayerR9/grammar/internal/tokentype"
+)
+
+// symbol is a symbol of the grammar being generated. The terminals are the
+// non-negative symbols, from EOF (0) on; the non-terminals are the negative ones,
+// from -1 down. Their names are only known by the table_maker that made them.
+type symbol int
+
+// String implements the fmt.Stringer interface.
+func (s symbol) String() string {
+	if s < 0 {
+		return "nonterminal(" + strconv.Itoa(int(-s-1)) + ")"
+	}
+
+	return "terminal(" + strconv.Itoa(int(s)) + ")"
+}
+
+// IsTerminal checks whether the symbol is a terminal.
+//
+// Returns:
+//   - bool: True if the symbol is a terminal, false otherwise.
+func (s symbol) IsTerminal() bool {
+	return s >= 0
+}
+
+// table_maker makes the parse table of a grammar.
+type table_maker struct {
+	// names are the names of the constants of the symbols: the terminals, EOF
+	// first, then the non-terminals.
+	names []string
+
+	// terminals is the number of terminals, EOF included.
+	terminals int
+}
+
+// symbol_at is a helper function that returns the symbol of the constant at the
+// given index of names.
+//
+// Parameters:
+//   - idx: The index of the constant.
+//
+// Returns:
+//   - symbol: The symbol.
+func (tm table_maker) symbol_at(idx int) symbol {
+	if idx < tm.terminals {
+		return symbol(idx)
+	}
+
+	return symbol(tm.terminals - idx - 1)
+}
+
+// name is a helper function that returns the name of the constant of a symbol.
+//
+// Parameters:
+//   - s: The symbol.
+//
+// Returns:
+//   - string: The name of the constant.
+func (tm table_maker) name(s symbol) string {
+	idx := int(s)
+	if s < 0 {
+		idx = tm.terminals - idx - 1
+	}
+
+	if idx < 0 || idx >= len(tm.names) {
+		return s.String()
+	}
+
+	return tm.names[idx]
+}
+
+// add is a helper function that adds a symbol.
+//
+// Parameters:
+//   - name: The name of the constant of the symbol.
+//
+// Returns:
+//   - symbol: The new symbol.
+//   - error: An error if another symbol has the same constant.
+func (tm *table_maker) add(name string) (symbol, error) {
+	if slices.Contains(tm.names, name) {
+		return 0, fmt.Errorf("two symbols are named %q in the generated code", name)
+	}
+
+	tm.names = append(tm.names, name)
+
+	return tm.symbol_at(len(tm.names) - 1), nil
+}
+
+// TableData is the parse table of a grammar, ready to be written as Go source.
+type TableData struct {
+	// Type is the type of the tokens. Its symbols are named as in the .grammar
+	// file.
+	Type tokentype.Type
+
+	// Start is the name of the constant of the start symbol.
+	Start string
+
+	// Rules are the rules of the grammar. The first one is the augmented rule
+	// "NtSource : start EtEOF"; its left-hand side is NtSource followed by a
+	// number if the grammar already has a symbol of that name. The String of
+	// NtSource is "source", followed by the same number.
+	Rules []RuleData
+
+	// States are the states of the automaton. The first one is the initial state.
+	States []StateData
+
+	// Stats are the statistics of the parse table.
+	Stats prx.GrammarStats
+}
+
+// RuleData is a rule of a parse table.
+type RuleData struct {
+	// Lhs is the constant of the left-hand side.
+	Lhs string
+
+	// Rhs are the constants of the right-hand side.
+	Rhs []string
+}
+
+// StateData is a state of a parse table.
+type StateData struct {
+	// Actions are the actions of the state, by terminal.
+	Actions []ActionData
+
+	// Gotos are the gotos of the state, by non-terminal.
+	Gotos []GotoData
+}
+
+// ActionData is an action of a state.
+type ActionData struct {
+	// Symbol is the constant of the lookahead.
+	Symbol string
+
+	// Kind is the kind of the action: "act_shift", "act_reduce" or "act_accept".
+	Kind string
+
+	// Arg is the next state, for shifts, or the index of the rule, otherwise.
+	Arg int
+}
+
+// GotoData is a goto of a state.
+type GotoData struct {
+	// Symbol is the constant of the non-terminal.
+	Symbol string
+
+	// State is the next state.
+	State int
+}
+
+// MakeTable computes the LALR(1) parse table of a grammar.
+//
+// Parameters:
+//   - gf: The grammar.
+//
+// Returns:
+//   - *TableData: The parse table. Nil if an error occurred.
+//   - error: An error if gf is nil, if two symbols have the same constant or if the
+//     grammar is not LALR(1).
+func MakeTable(gf *GrammarFile) (*TableData, error) {
+	if gf == nil {
+		return nil, errors.New("no grammar was given")
+	}
+
+	tm := &table_maker{
+		names:     []string{"EtEOF"},
+		terminals: 1 + len(gf.Terminals),
+	}
+
+	symbols := make(map[string]symbol)
+
+	for _, name := range gf.Terminals {
+		s, err := tm.add(tokentype.ConstName("Tt", name))
+		if err != nil {
+			return nil, err
+		}
+
+		symbols[name] = s
+	}
+
+	// The augmented start symbol comes first among the non-terminals and takes
+	// the first name that the grammar does not use.
+	taken := make(map[string]bool, len(gf.Terminals)+2*len(gf.NonTerminals))
+
+	for _, name := range gf.Terminals {
+		taken[name] = true
+	}
+
+	for _, name := range gf.NonTerminals {
+		taken[name] = true
+		taken[tokentype.ConstName("Nt", name)] = true
+	}
+
+	source_raw := "source"
+
+	for i := 1; taken[source_raw] || taken[tokentype.ConstName("Nt", source_raw)]; i++ {
+		source_raw = "source" + strconv.Itoa(i)
+	}
+
+	source_name := tokentype.ConstName("Nt", source_raw)
+
+	source, err := tm.add(source_name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range gf.NonTerminals {
+		s, err := tm.add(tokentype.ConstName("Nt", name))
+		if err != nil {
+			return nil, err
+		}
+
+		symbols[name] = s
+	}
+
+	rs := prx.NewRuleSet[symbol]()
+
+	rules := []*prx.Rule[symbol]{}
+
+	start, _ := prx.NewRule(source, []symbol{symbols[gf.Start], 0})
+	rules = append(rules, start)
+
+	for _, fr := range gf.Rules {
+		rhs := make([]symbol, 0, len(fr.Rhs))
+
+		for _, name := range fr.Rhs {
+			rhs = append(rhs, symbols[name])
+		}
+
+		rule, err := prx.NewRule(symbols[fr.Lhs], rhs)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, rule)
+	}
+
+	for _, rule := range rules {
+		rs.MustAddRule(rule)
+	}
+
+	pt, err := prx.NewLALRTable(rs)
+	if err != nil {
+		return nil, tm.describe(err)
+	}
+
+	tt, err := tokentype.New("TokenType", gf.Terminals, append([]string{source_raw}, gf.NonTerminals...))
+	if err != nil {
+		return nil, err
+	}
+
+	td := tm.table_data(pt, rules)
+	td.Type = *tt
+	td.Stats = pt.Stats()
+
+	return td, nil
+}
+
+// describe is a helper function that writes the conflicts of an error of
+// NewLALRTable with the names of the constants of the symbols.
+//
+// Parameters:
+//   - err: The error.
+//
+// Returns:
+//   - error: The error, with the names of the symbols if it is about conflicts.
+func (tm table_maker) describe(err error) error {
+	var conflicts *prx.ErrConflicts[symbol]
+
+	if !errors.As(err, &conflicts) {
+		return err
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString(strconv.Itoa(len(conflicts.Conflicts)))
+	builder.WriteString(" conflicts:")
+
+	for _, c := range conflicts.Conflicts {
+		items := make([]string, 0, len(c.Items))
+
+		for _, item := range c.Items {
+			elems := []string{tm.name(item.Lhs()), ":"}
+
+			i := 0
+
+			for rhs := range item.Rhs() {
+				if i == item.Pos() {
+					elems = append(elems, ".")
+				}
+
+				elems = append(elems, tm.name(rhs))
+				i++
+			}
+
+			if i == item.Pos() {
+				elems = append(elems, ".")
+			}
+
+			items = append(items, strings.Join(elems, " "))
+		}
+
+		fmt.Fprintf(&builder, "\n\tstate %d, on %s: %s between %s", c.State, tm.name(c.Lookahead), c.Kind.String(), strings.Join(items, " and "))
+	}
+
+	return errors.New(builder.String())
+}
+
+// table_data is a helper function that converts a parse table to its data.
+//
+// Parameters:
+//   - pt: The parse table. Assumed to be made by NewLALRTable.
+//   - rules: The rules of the grammar, the start rule first.
+//
+// Returns:
+//   - *TableData: The data of the table. Never returns nil.
+func (tm table_maker) table_data(pt *prx.ParseTable[symbol], rules []*prx.Rule[symbol]) *TableData {
+	start, _ := rules[0].RhsAt(0)
+
+	td := &TableData{
+		Start: tm.name(start),
+		Rules: make([]RuleData, 0, len(rules)),
+	}
+
+	rule_idx := make(map[*prx.Rule[symbol]]int, len(rules))
+
+	for i, rule := range rules {
+		rule_idx[rule] = i
+
+		rd := RuleData{
+			Lhs: tm.name(rule.Lhs()),
+		}
+
+		for rhs := range rule.Rhs() {
+			rd.Rhs = append(rd.Rhs, tm.name(rhs))
+		}
+
+		td.Rules = append(td.Rules, rd)
+	}
+
+	var states []*prx.State[symbol]
+
+	state_idx := make(map[*prx.State[symbol]]int)
+
+	for state := range pt.States() {
+		state_idx[state] = len(states)
+		states = append(states, state)
+	}
+
+	for _, state := range states {
+		var sd StateData
+
+		for i := range tm.names {
+			s := tm.symbol_at(i)
+
+			next, has_next := pt.Next(state, s)
+
+			if !s.IsTerminal() {
+				if has_next {
+					sd.Gotos = append(sd.Gotos, GotoData{Symbol: tm.name(s), State: state_idx[next]})
+				}
+
+				continue
+			}
+
+			_, rule := pt.Action(state, s)
+
+			switch {
+			case rule == rules[0]:
+				sd.Actions = append(sd.Actions, ActionData{Symbol: tm.name(s), Kind: "act_accept", Arg: 0})
+			case rule != nil:
+				sd.Actions = append(sd.Actions, ActionData{Symbol: tm.name(s), Kind: "act_reduce", Arg: rule_idx[rule]})
+			case has_next:
+				sd.Actions = append(sd.Actions, ActionData{Symbol: tm.name(s), Kind: "act_shift", Arg: state_idx[next]})
+			}
+		}
+
+		td.States = append(td.States, sd)
+	}
+
+	return td
+}
@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"encoding/json"
+
+	"github.com/PlayerR9/grammar/cache"
+)
+
+// table_format is the format of the cached parse tables. Change it whenever
+// TableData or the construction of the tables changes, so that the tables cached
+// by older versions are rebuilt.
+const table_format string = "parsergen table v2"
+
+// LoadTable parses a .grammar file and makes its parse table, or reads the table
+// from the cache if the same file was already compiled.
+//
+// Parameters:
+//   - c: The cache. If nil, the table is always made.
+//   - content: The content of the .grammar file.
+//
+// Returns:
+//   - *TableData: The parse table.
+//   - error: An error if the grammar is invalid, if the table could not be made or
+//     if the cache failed.
+func LoadTable(c cache.Cache, content []byte) (*TableData, error) {
+	key := cache.Key([]byte(table_format), content)
+
+	data, err := cache.GetOrCompile(c, key, func() ([]byte, error) {
+		gf, err := ParseGrammarFile(content)
+		if err != nil {
+			return nil, err
+		}
+
+		table, err := MakeTable(gf)
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(table)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var table TableData
+
+	err = json.Unmarshal(data, &table)
+	if err != nil {
+		return nil, err
+	}
+
+	return &table, nil
+}
@@ -0,0 +1,45 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/PlayerR9/grammar/cache"
+)
+
+func TestLoadTable(t *testing.T) {
+	content := []byte("%token n plus\n%start expr\n\nexpr : expr plus n | n ;\n")
+
+	want, err := LoadTable(nil, content)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	c := cache.NewMemory()
+
+	for i := range 2 {
+		got, err := LoadTable(c, content)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		} else if !reflect.DeepEqual(got, want) {
+			t.Fatalf("expected the table %v on call %d, got %v", want, i, got)
+		}
+
+		if _, ok, _ := c.Get(cache.Key([]byte(table_format), content)); !ok {
+			t.Fatalf("expected the table to be cached after call %d", i)
+		}
+	}
+
+	// A cached table is used as is, without compiling the grammar again.
+	err = c.Put(cache.Key([]byte(table_format), []byte("not a grammar")), []byte(`{"Start":"cached"}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got, err := LoadTable(c, []byte("not a grammar"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	} else if got.Start != "cached" {
+		t.Errorf("expected the cached table, got %v", got)
+	}
+}
@@ -0,0 +1,131 @@
+package pkg
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/PlayerR9/grammar/internal/tokentype"
+)
+
+// make_test_table is a helper function that makes the parse table of a .grammar
+// file.
+func make_test_table(t *testing.T, data string) *TableData {
+	gf, err := ParseGrammarFile([]byte(data))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	td, err := MakeTable(gf)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	return td
+}
+
+func TestMakeTableSourceCollision(t *testing.T) {
+	td := make_test_table(t, "%token word\n%start source\n\nsource : word ;\n")
+
+	want := []tokentype.Symbol{
+		{Const: "EtEOF", Name: "EOF"},
+		{Const: "TtWord", Name: "word"},
+		{Const: "NtSource1", Name: "source1"},
+		{Const: "NtSource", Name: "source"},
+	}
+
+	if got := td.Type.Symbols; !slices.Equal(got, want) {
+		t.Fatalf("expected the symbols %v, got %v", want, got)
+	}
+
+	if lhs := td.Rules[0].Lhs; lhs != "NtSource1" {
+		t.Errorf("expected the augmented rule of NtSource1, got %s", lhs)
+	}
+
+	if td.Start != "NtSource" {
+		t.Errorf("expected the start symbol NtSource, got %s", td.Start)
+	}
+}
+
+func TestMakeTableSourceTerminal(t *testing.T) {
+	td := make_test_table(t, "%token source\n%start list\n\nlist : source ;\n")
+
+	if lhs := td.Rules[0].Lhs; lhs != "NtSource1" {
+		t.Errorf("expected the augmented rule of NtSource1, got %s", lhs)
+	}
+
+	if got := td.Type.Symbols[2].Name; got != "source1" {
+		t.Errorf("expected the name source1, got %s", got)
+	}
+}
+
+func TestMakeTableStats(t *testing.T) {
+	td := make_test_table(t, "%token a b\n%start list\n\nlist : list a | b ;\n")
+
+	if td.Stats.Rules != len(td.Rules) {
+		t.Errorf("expected %d rules, got %d", len(td.Rules), td.Stats.Rules)
+	}
+
+	if td.Stats.States != len(td.States) {
+		t.Errorf("expected %d states, got %d", len(td.States), td.Stats.States)
+	}
+
+	if td.Stats.MemoryEstimate <= 0 {
+		t.Errorf("expected a positive memory estimate, got %d", td.Stats.MemoryEstimate)
+	}
+}
+
+func TestMakeTableConcurrent(t *testing.T) {
+	grammars := []string{
+		"%token a b\n%start list\n\nlist : list a | a ;\n",
+		"%token x\n%start pair\n\npair : x x ;\n",
+	}
+
+	want := make([]string, len(grammars))
+
+	for i, data := range grammars {
+		want[i] = fmt.Sprint(*make_test_table(t, data))
+	}
+
+	var wg sync.WaitGroup
+
+	got := make([]string, 8)
+
+	for i := range got {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			got[i] = fmt.Sprint(*make_test_table(t, grammars[i%len(grammars)]))
+		}()
+	}
+
+	wg.Wait()
+
+	for i, table := range got {
+		if table != want[i%len(grammars)] {
+			t.Errorf("call %d: expected the table %s, got %s", i, want[i%len(grammars)], table)
+		}
+	}
+}
+
+func TestMakeTableConflicts(t *testing.T) {
+	gf, err := ParseGrammarFile([]byte("%token n plus\n%start expr\n\nexpr : expr plus expr | n ;\n"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err = MakeTable(gf)
+	if err == nil {
+		t.Fatalf("expected an error, got none")
+	}
+
+	const want = "on TtPlus: "
+
+	if got := err.Error(); !strings.Contains(got, want) || !strings.Contains(got, "NtExpr : NtExpr TtPlus NtExpr .") {
+		t.Errorf("expected the conflicts to name the symbols, got %q", got)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"go/format"
+
+	ggen "github.com/PlayerR9/go-commons/generator"
+	pkg "github.com/PlayerR9/grammar/cmd/tokentype/pkg"
+)
+
+func main() {
+	data, file_name, err := pkg.ParseFlags()
+	if err != nil {
+		ggen.PrintFlags()
+
+		pkg.Logger.Fatalf("Failed to parse flags: %s", err.Error())
+	}
+
+	res, err := pkg.Generator.Generate(pkg.OutputLocFlag, file_name, data)
+	if err != nil {
+		pkg.Logger.Fatalf("Failed to generate: %s", err.Error())
+	}
+
+	res.Data, err = format.Source(res.Data)
+	if err != nil {
+		pkg.Logger.Fatalf("Failed to format: %s", err.Error())
+	}
+
+	err = res.WriteFile()
+	if err != nil {
+		pkg.Logger.Fatal(err.Error())
+	}
+
+	pkg.Logger.Printf("Successfully generated: %q", res.DestLoc)
+}
@@ -0,0 +1,70 @@
+package pkg
+
+import (
+	"errors"
+	"flag"
+	"strings"
+
+	ggen "github.com/PlayerR9/go-commons/generator"
+	"github.com/PlayerR9/grammar/internal/tokentype"
+)
+
+var (
+	OutputLocFlag *ggen.OutputLocVal
+
+	TypeNameFlag *string
+
+	TerminalsFlag *string
+
+	NonTerminalsFlag *string
+)
+
+func init() {
+	TypeNameFlag = flag.String("type", "TokenType", "The name of the type to generate.")
+	TerminalsFlag = flag.String("t", "", "The comma-separated names of the terminals, without EOF. This flag is required.")
+	NonTerminalsFlag = flag.String("n", "", "The comma-separated names of the non-terminals.")
+
+	OutputLocFlag = ggen.NewOutputFlag("<type>.go (snake case)", false)
+}
+
+// ParseFlags parses the flags of the command.
+//
+// Returns:
+//   - *GenData: The data of the type to generate. Nil if an error occurred.
+//   - string: The default name of the output file.
+//   - error: An error if the flags are invalid.
+func ParseFlags() (*GenData, string, error) {
+	ggen.ParseFlags()
+
+	if *TerminalsFlag == "" {
+		return nil, "", errors.New("t flag is required")
+	}
+
+	data, err := NewGenData(*TypeNameFlag, split_names(*TerminalsFlag), split_names(*NonTerminalsFlag))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, tokentype.SnakeCase(data.Name) + ".go", nil
+}
+
+// split_names is a helper function that splits a comma-separated list of names.
+//
+// Parameters:
+//   - list: The list.
+//
+// Returns:
+//   - []string: The names, trimmed. Empty names are kept so that they are reported.
+func split_names(list string) []string {
+	if strings.TrimSpace(list) == "" {
+		return nil
+	}
+
+	names := strings.Split(list, ",")
+
+	for i, name := range names {
+		names[i] = strings.TrimSpace(name)
+	}
+
+	return names
+}
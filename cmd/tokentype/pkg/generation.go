@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"log"
+	"os"
+
+	ggen "github.com/PlayerR9/go-commons/generator"
+	"github.com/PlayerR9/grammar/internal/tokentype"
+)
+
+var (
+	// Logger is the logger.
+	Logger *log.Logger
+)
+
+func init() {
+	Logger = log.New(os.Stdout, "[tokentype]: ", log.LstdFlags)
+}
+
+type GenData struct {
+	PackageName string
+
+	// Type is the generated type.
+	*tokentype.Type
+}
+
+// NewGenData creates the data of a token type and checks the names of its symbols.
+//
+// Parameters:
+//   - type_name: The name of the type.
+//   - terminals: The names of the terminals, without EOF.
+//   - non_terminals: The names of the non-terminals.
+//
+// Returns:
+//   - *GenData: The data. Nil if an error occurred.
+//   - error: An error if a name is invalid or if two symbols have the same
+//     constant. Every invalid name is reported.
+//
+// The constant of EOF is "EtEOF"; the one of a terminal is "Tt" followed by its
+// name and the one of a non-terminal "Nt" followed by its name, capitalized.
+func NewGenData(type_name string, terminals, non_terminals []string) (*GenData, error) {
+	t, err := tokentype.New(type_name, terminals, non_terminals)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GenData{
+		Type: t,
+	}, nil
+}
+
+// SetPackageName implements the generator.Generater interface.
+func (gd *GenData) SetPackageName(pkg_name string) bool {
+	if gd == nil {
+		return false
+	}
+
+	gd.PackageName = pkg_name
+
+	return true
+}
+
+var (
+	Generator *ggen.CodeGenerator[*GenData]
+)
+
+func init() {
+	tmp, err := ggen.NewCodeGeneratorFromTemplate[*GenData]("", templ)
+	if err != nil {
+		Logger.Fatalf("Failed to create code generator: %s", err.Error())
+	}
+
+	Generator = tmp
+}
+
+// templ is the template for the token type.
+const templ = `// Code generated by tokentype; DO NOT EDIT.
+package {{ .PackageName }}
+
+import "strconv"
+
+{{ with .Type }}` + tokentype.Template + `{{ end }}`
@@ -0,0 +1,159 @@
+package grammar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	"github.com/PlayerR9/grammar/parser"
+)
+
+// Config gathers the settings of the lexer and the parser of a compiled grammar,
+// so that applications can expose them in their own configuration files. The field
+// tags allow it to be decoded from JSON as well as from YAML. See
+// CompiledGrammar.Configure.
+type Config struct {
+	// Lexer are the settings of the lexer.
+	Lexer LexerConfig `json:"lexer" yaml:"lexer"`
+
+	// Parser are the settings of the parser.
+	Parser ParserConfig `json:"parser" yaml:"parser"`
+}
+
+// LexerConfig are the settings of the lexer.
+type LexerConfig struct {
+	// Replacement is the character that replaces the bytes of the input that are
+	// not text. Empty to fail on them instead. See lexer.Lexer.SetReplacement.
+	Replacement string `json:"replacement" yaml:"replacement"`
+}
+
+// ParserConfig are the settings of the parser. Limits of 0 are disabled. See
+// parser.Limits.
+type ParserConfig struct {
+	// MaxDepth is the maximum depth of the parse tree.
+	MaxDepth int `json:"max_depth" yaml:"max_depth"`
+
+	// MaxNodes is the maximum number of nodes created by reductions.
+	MaxNodes int `json:"max_nodes" yaml:"max_nodes"`
+}
+
+// DefaultConfig returns the default configuration; that is, the one that behaves
+// as if no setting was changed.
+//
+// Returns:
+//   - Config: The default configuration.
+func DefaultConfig() Config {
+	return Config{}
+}
+
+// LoadConfig decodes a configuration from JSON. Settings missing from the input
+// keep their default value.
+//
+// Parameters:
+//   - r: The reader of the JSON input.
+//
+// Returns:
+//   - Config: The configuration.
+//   - error: An error if the input cannot be decoded or if the configuration is
+//     invalid.
+func LoadConfig(r io.Reader) (Config, error) {
+	cfg := DefaultConfig()
+
+	if r == nil {
+		return cfg, nil
+	}
+
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&cfg)
+	if err != nil && err != io.EOF {
+		return cfg, err
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks the configuration.
+//
+// Returns:
+//   - error: The errors of type *ErrInvalidConfig, joined, if any setting is invalid.
+func (c Config) Validate() error {
+	var errs []error
+
+	check := func(field string, value, least int) {
+		if value < least {
+			errs = append(errs, NewErrInvalidConfig(field, fmt.Errorf("must be at least %d, got %d", least, value)))
+		}
+	}
+
+	if c.Lexer.Replacement != "" && utf8.RuneCountInString(c.Lexer.Replacement) != 1 {
+		errs = append(errs, NewErrInvalidConfig("lexer.replacement", fmt.Errorf("must be a single character, got %q", c.Lexer.Replacement)))
+	}
+
+	check("parser.max_depth", c.Parser.MaxDepth, 0)
+	check("parser.max_nodes", c.Parser.MaxNodes, 0)
+
+	return errors.Join(errs...)
+}
+
+// Replacement returns the replacement of the lexer.
+//
+// Returns:
+//   - rune: The replacement. -1 if the lexer fails on the bytes that are not text.
+func (c Config) Replacement() rune {
+	if c.Lexer.Replacement == "" {
+		return -1
+	}
+
+	r, _ := utf8.DecodeRuneInString(c.Lexer.Replacement)
+
+	return r
+}
+
+// Limits returns the resource limits of the parser.
+//
+// Returns:
+//   - parser.Limits: The limits.
+func (c Config) Limits() parser.Limits {
+	return parser.Limits{
+		MaxDepth: c.Parser.MaxDepth,
+		MaxNodes: c.Parser.MaxNodes,
+	}
+}
+
+// Configure applies a configuration to the lexer and the parser of the grammar.
+// The results memoized by Run are dropped, as they were computed with the previous
+// settings.
+//
+// Parameters:
+//   - cfg: The configuration.
+//
+// Returns:
+//   - error: An error if the configuration is invalid. See Config.Validate.
+func (g *CompiledGrammar[T]) Configure(cfg Config) error {
+	if g == nil {
+		return gcers.NilReceiver
+	}
+
+	err := cfg.Validate()
+	if err != nil {
+		return err
+	}
+
+	g.update(func() {
+		g.lexer.SetReplacement(cfg.Replacement())
+		g.parser.SetLimits(cfg.Limits())
+		g.cfg = &cfg
+	})
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package grammar
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/PlayerR9/grammar/parser"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cfg, err := LoadConfig(strings.NewReader(`{"lexer": {"replacement": "x"}, "parser": {"max_depth": 3}}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if cfg.Replacement() != 'x' {
+		t.Errorf("expected the replacement %q, got %q", 'x', cfg.Replacement())
+	}
+
+	if cfg.Parser.MaxDepth != 3 || cfg.Parser.MaxNodes != 0 {
+		t.Errorf("expected the limits {3 0}, got %+v", cfg.Parser)
+	}
+
+	_, err = LoadConfig(strings.NewReader(`{"lexer": {"replacement": "xy"}, "parser": {"max_nodes": -1}}`))
+
+	var config_err *ErrInvalidConfig
+
+	if !errors.As(err, &config_err) {
+		t.Fatalf("expected an *ErrInvalidConfig, got %v", err)
+	}
+
+	if got := strings.Count(err.Error(), "invalid setting"); got != 2 {
+		t.Errorf("expected 2 invalid settings, got %d: %v", got, err)
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	g := new_test_grammar(t)
+
+	_, err := Run([]byte("a\xffb"), g)
+	if err == nil {
+		t.Fatalf("expected an error, got none")
+	}
+
+	err = g.Configure(Config{
+		Lexer:  LexerConfig{Replacement: "x"},
+		Parser: ParserConfig{MaxDepth: 3},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	res, err := Run([]byte("a\xffb"), g)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got := words_of(res); fmt.Sprint(got) != "[axb]" {
+		t.Errorf("expected the words [axb], got %v", got)
+	}
+
+	if len(res.Diagnostics) != 1 {
+		t.Errorf("expected 1 diagnostic, got %v", res.Diagnostics)
+	}
+
+	res, err = Run([]byte("a b c"), g)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var limit_err *parser.ErrTreeLimit[test_type]
+
+	if !errors.As(res.Err, &limit_err) {
+		t.Errorf("expected an *ErrTreeLimit, got %v", res.Err)
+	}
+
+	err = g.Configure(Config{Parser: ParserConfig{MaxNodes: -1}})
+	if err == nil {
+		t.Errorf("expected an error, got none")
+	}
+}
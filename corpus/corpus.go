@@ -0,0 +1,483 @@
+// Package corpus bundles the golden test corpus of a grammar (the inputs, the
+// expected token streams and the expected trees) into a single archive whose
+// content is verified by checksums when it is read, so that regression suites can
+// be shared between repositories.
+package corpus
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// Version is the version of the format written by Write. It is increased
+// whenever the format changes in a way that older releases cannot read.
+const Version int = 1
+
+// MaxEntrySize is the maximum size, in bytes, of an entry of an archive read by
+// Read. Larger entries are rejected before they are read, so that a malicious
+// archive cannot exhaust the memory.
+const MaxEntrySize int64 = 64 << 20
+
+const (
+	// ManifestName is the name of the entry of the archive that lists the
+	// checksums of the other entries.
+	ManifestName string = "MANIFEST"
+
+	// InputName, TokensName and TreeName are the names of the files of a case.
+	InputName  string = "input"
+	TokensName string = "tokens"
+	TreeName   string = "tree.json"
+)
+
+// Case is a case of a golden corpus.
+type Case struct {
+	// Name is the name of the case. It must not be empty nor contain a slash.
+	Name string
+
+	// Input is the input of the case.
+	Input []byte
+
+	// Tokens is the expected token stream, in the encoding of grammar.NewTokenReader.
+	// Nil if the case does not check the tokens.
+	Tokens []byte
+
+	// Tree is the expected forest, in the encoding of grammar.ForestToJSON. Nil if
+	// the case does not check the trees.
+	Tree []byte
+}
+
+// files is a helper function that returns the files of the case, by path.
+//
+// Returns:
+//   - map[string][]byte: The files. Never returns nil.
+func (c Case) files() map[string][]byte {
+	files := map[string][]byte{
+		c.Name + "/" + InputName: c.Input,
+	}
+
+	if c.Tokens != nil {
+		files[c.Name+"/"+TokensName] = c.Tokens
+	}
+
+	if c.Tree != nil {
+		files[c.Name+"/"+TreeName] = c.Tree
+	}
+
+	return files
+}
+
+// Corpus is a golden corpus.
+type Corpus struct {
+	// Cases are the cases of the corpus, sorted by name.
+	Cases []Case
+}
+
+// Get returns the case with the given name.
+//
+// Parameters:
+//   - name: The name of the case.
+//
+// Returns:
+//   - Case: The case.
+//   - bool: True if the case exists, false otherwise.
+func (c Corpus) Get(name string) (Case, bool) {
+	idx, ok := slices.BinarySearchFunc(c.Cases, name, func(c Case, name string) int {
+		return strings.Compare(c.Name, name)
+	})
+	if !ok {
+		return Case{}, false
+	}
+
+	return c.Cases[idx], true
+}
+
+// Digest returns the digest of the corpus; that is, the hex-encoded SHA-256 hash
+// of its manifest. Two corpora have the same digest if and only if they have the
+// same cases, so the digest can pin the version of a shared corpus.
+//
+// Returns:
+//   - string: The digest.
+func (c Corpus) Digest() string {
+	sum := sha256.Sum256(manifest_of(c.Cases))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// ErrChecksum occurs when an entry of an archive does not match its checksum.
+type ErrChecksum struct {
+	// Path is the path of the entry.
+	Path string
+
+	// Want is the checksum listed in the manifest. Empty if the entry is not
+	// listed.
+	Want string
+
+	// Got is the checksum of the entry. Empty if the entry is missing.
+	Got string
+}
+
+// Error implements the error interface.
+//
+// Message:
+//
+//	"<path>: checksum mismatch (want <want>, got <got>)"
+//
+// or "<path>: not in the manifest" and "<path>: missing" when the entry is not
+// listed or is missing.
+func (e ErrChecksum) Error() string {
+	switch {
+	case e.Want == "":
+		return e.Path + ": not in the manifest"
+	case e.Got == "":
+		return e.Path + ": missing"
+	default:
+		return fmt.Sprintf("%s: checksum mismatch (want %s, got %s)", e.Path, e.Want, e.Got)
+	}
+}
+
+// NewErrChecksum creates a new ErrChecksum error.
+//
+// Parameters:
+//   - path: The path of the entry.
+//   - want: The checksum listed in the manifest.
+//   - got: The checksum of the entry.
+//
+// Returns:
+//   - *ErrChecksum: The new error. Never returns nil.
+func NewErrChecksum(path, want, got string) *ErrChecksum {
+	return &ErrChecksum{
+		Path: path,
+		Want: want,
+		Got:  got,
+	}
+}
+
+// New creates a new corpus from the given cases.
+//
+// Parameters:
+//   - cases: The cases, in any order.
+//
+// Returns:
+//   - *Corpus: The new corpus. Nil if an error occurred.
+//   - error: An error if a case has an invalid name or if two cases have the same
+//     name.
+func New(cases ...Case) (*Corpus, error) {
+	sorted := slices.Clone(cases)
+
+	slices.SortFunc(sorted, func(a, b Case) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for i, c := range sorted {
+		if c.Name == "" || c.Name == "." || c.Name == ".." || strings.ContainsAny(c.Name, "/\\\n") {
+			return nil, fmt.Errorf("invalid case name %q", c.Name)
+		}
+
+		if i > 0 && sorted[i-1].Name == c.Name {
+			return nil, fmt.Errorf("case %q appears twice", c.Name)
+		}
+	}
+
+	return &Corpus{
+		Cases: sorted,
+	}, nil
+}
+
+// Write writes the corpus as a gzip-compressed tar archive. Its first entry is
+// the manifest, followed by the files of the cases: "<name>/input" and, if any,
+// "<name>/tokens" and "<name>/tree.json".
+//
+// Format of the manifest:
+//
+//	corpus <Version>
+//	<sha256>  <path>
+//	...
+//
+// The checksum lines are sorted by path and can be checked by sha256sum once the
+// archive is extracted.
+//
+// Parameters:
+//   - w: The writer.
+//
+// Returns:
+//   - error: An error if w could not be written.
+func (c Corpus) Write(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	tw := tar.NewWriter(zw)
+
+	write := func(path string, data []byte) error {
+		err := tw.WriteHeader(&tar.Header{
+			Name:     path,
+			Mode:     0o644,
+			Size:     int64(len(data)),
+			Typeflag: tar.TypeReg,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = tw.Write(data)
+		return err
+	}
+
+	err := write(ManifestName, manifest_of(c.Cases))
+	if err != nil {
+		return err
+	}
+
+	for _, cs := range c.Cases {
+		files := cs.files()
+
+		for _, name := range []string{InputName, TokensName, TreeName} {
+			data, ok := files[cs.Name+"/"+name]
+			if !ok {
+				continue
+			}
+
+			err := write(cs.Name+"/"+name, data)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// Read reads an archive written by Write and verifies every entry against the
+// manifest.
+//
+// Parameters:
+//   - r: The reader.
+//
+// Returns:
+//   - *Corpus: The corpus. Nil if an error occurred.
+//   - error: An error if the archive could not be read, if an entry is larger
+//     than MaxEntrySize, if it was written in a version of the format that cannot
+//     be read (of type *grammar.ErrUnsupportedVersion) or if an entry is missing,
+//     is not listed in the manifest or does not match its checksum (of type
+//     *ErrChecksum).
+func Read(r io.Reader) (*Corpus, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer zr.Close()
+
+	tr := tar.NewReader(zr)
+
+	var (
+		manifest []byte
+		files    = make(map[string][]byte)
+	)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		} else if hdr.Size > MaxEntrySize {
+			return nil, fmt.Errorf("%s: size of %d bytes exceeds the limit of %d bytes", hdr.Name, hdr.Size, MaxEntrySize)
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", hdr.Name, err)
+		}
+
+		if hdr.Name == ManifestName {
+			manifest = data
+		} else {
+			files[hdr.Name] = data
+		}
+	}
+
+	if manifest == nil {
+		return nil, errors.New("the archive has no " + ManifestName)
+	}
+
+	sums, err := parse_manifest(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ManifestName, err)
+	}
+
+	paths := make([]string, 0, len(files))
+
+	for path := range files {
+		paths = append(paths, path)
+	}
+
+	slices.Sort(paths)
+
+	cases := make(map[string]*Case)
+
+	for _, path := range paths {
+		data := files[path]
+
+		want, ok := sums[path]
+		if !ok {
+			return nil, NewErrChecksum(path, "", checksum(data))
+		}
+
+		delete(sums, path)
+
+		if got := checksum(data); got != want {
+			return nil, NewErrChecksum(path, want, got)
+		}
+
+		name, file, ok := strings.Cut(path, "/")
+		if !ok {
+			return nil, fmt.Errorf("unexpected entry %q", path)
+		}
+
+		c, ok := cases[name]
+		if !ok {
+			c = &Case{Name: name}
+			cases[name] = c
+		}
+
+		switch file {
+		case InputName:
+			c.Input = data
+		case TokensName:
+			c.Tokens = data
+		case TreeName:
+			c.Tree = data
+		default:
+			return nil, fmt.Errorf("unexpected entry %q", path)
+		}
+	}
+
+	if len(sums) > 0 {
+		missing := slices.Sorted(maps.Keys(sums))
+
+		return nil, NewErrChecksum(missing[0], sums[missing[0]], "")
+	}
+
+	list := make([]Case, 0, len(cases))
+
+	for _, c := range cases {
+		if c.Input == nil {
+			return nil, NewErrChecksum(c.Name+"/"+InputName, "", "")
+		}
+
+		list = append(list, *c)
+	}
+
+	return New(list...)
+}
+
+// checksum is a helper function that computes the checksum of an entry.
+//
+// Parameters:
+//   - data: The content of the entry.
+//
+// Returns:
+//   - string: The hex-encoded SHA-256 hash of data.
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// manifest_of is a helper function that makes the manifest of the given cases.
+//
+// Parameters:
+//   - cases: The cases.
+//
+// Returns:
+//   - []byte: The manifest.
+func manifest_of(cases []Case) []byte {
+	var paths []string
+
+	files := make(map[string][]byte)
+
+	for _, c := range cases {
+		for path, data := range c.files() {
+			paths = append(paths, path)
+			files[path] = data
+		}
+	}
+
+	slices.Sort(paths)
+
+	var buf bytes.Buffer
+
+	buf.WriteString("corpus " + strconv.Itoa(Version) + "\n")
+
+	for _, path := range paths {
+		buf.WriteString(checksum(files[path]) + "  " + path + "\n")
+	}
+
+	return buf.Bytes()
+}
+
+// parse_manifest is a helper function that parses a manifest written by
+// manifest_of.
+//
+// Parameters:
+//   - manifest: The manifest.
+//
+// Returns:
+//   - map[string]string: The checksums, by path.
+//   - error: An error if the manifest is malformed or of a version that cannot be
+//     read.
+func parse_manifest(manifest []byte) (map[string]string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(manifest))
+
+	if !scanner.Scan() {
+		return nil, errors.New("missing header")
+	}
+
+	version_text, ok := strings.CutPrefix(scanner.Text(), "corpus ")
+	if !ok {
+		return nil, fmt.Errorf("invalid header %q", scanner.Text())
+	}
+
+	version, err := strconv.Atoi(version_text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid header %q", scanner.Text())
+	}
+
+	err = gr.CheckVersion("corpus", version, 1, Version)
+	if err != nil {
+		return nil, err
+	}
+
+	sums := make(map[string]string)
+
+	for line := 2; scanner.Scan(); line++ {
+		sum, path, ok := strings.Cut(scanner.Text(), "  ")
+		if !ok || path == "" {
+			return nil, fmt.Errorf("line %d: expected \"<sha256>  <path>\"", line)
+		}
+
+		sums[path] = sum
+	}
+
+	return sums, scanner.Err()
+}
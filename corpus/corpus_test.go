@@ -0,0 +1,226 @@
+package corpus
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// new_test_archive is a helper function that writes a corpus of two cases.
+func new_test_archive(t *testing.T) (*Corpus, []byte) {
+	c, err := New(
+		Case{Name: "sum", Input: []byte("1 + 2"), Tokens: []byte("tokens"), Tree: []byte("{}")},
+		Case{Name: "empty", Input: []byte("")},
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var buf bytes.Buffer
+
+	err = c.Write(&buf)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	return c, buf.Bytes()
+}
+
+// rewrite is a helper function that rewrites the entries of an archive with the
+// given function. Entries for which fn returns nil are dropped; the extra
+// entries are added at the end.
+func rewrite(t *testing.T, archive []byte, fn func(name string, data []byte) []byte, extra map[string][]byte) []byte {
+	zr, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tr := tar.NewReader(zr)
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+
+	write := func(name string, data []byte) {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg})
+		if err == nil {
+			_, err = tw.Write(data)
+		}
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if data = fn(hdr.Name, data); data != nil {
+			write(hdr.Name, data)
+		}
+	}
+
+	for name, data := range extra {
+		write(name, data)
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	} else if err := zw.Close(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	want, archive := new_test_archive(t)
+
+	got, err := Read(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Cases, want.Cases) {
+		t.Errorf("expected the cases %+v, got %+v", want.Cases, got.Cases)
+	}
+
+	if got.Digest() != want.Digest() {
+		t.Errorf("expected the digest %s, got %s", want.Digest(), got.Digest())
+	}
+}
+
+func TestReadTampered(t *testing.T) {
+	_, archive := new_test_archive(t)
+
+	keep := func(_ string, data []byte) []byte {
+		return data
+	}
+
+	tests := []struct {
+		name  string
+		fn    func(name string, data []byte) []byte
+		extra map[string][]byte
+		path  string
+		want  bool
+		got   bool
+	}{
+		{
+			name: "changed entry",
+			fn: func(name string, data []byte) []byte {
+				if name == "sum/input" {
+					return []byte("1 + 3")
+				}
+
+				return data
+			},
+			path: "sum/input",
+			want: true,
+			got:  true,
+		},
+		{
+			name: "missing entry",
+			fn: func(name string, data []byte) []byte {
+				if name == "sum/tree.json" {
+					return nil
+				}
+
+				return data
+			},
+			path: "sum/tree.json",
+			want: true,
+		},
+		{
+			name:  "unlisted entry",
+			fn:    keep,
+			extra: map[string][]byte{"extra/input": []byte("x")},
+			path:  "extra/input",
+			got:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Read(bytes.NewReader(rewrite(t, archive, test.fn, test.extra)))
+
+			var sum_err *ErrChecksum
+
+			if !errors.As(err, &sum_err) {
+				t.Fatalf("expected an *ErrChecksum, got %v", err)
+			}
+
+			if sum_err.Path != test.path {
+				t.Errorf("expected the path %q, got %q", test.path, sum_err.Path)
+			}
+
+			if (sum_err.Want != "") != test.want || (sum_err.Got != "") != test.got {
+				t.Errorf("expected the checksums to be set as %t and %t, got %q and %q", test.want, test.got, sum_err.Want, sum_err.Got)
+			}
+		})
+	}
+}
+
+func TestReadVersion(t *testing.T) {
+	_, archive := new_test_archive(t)
+
+	for _, header := range []string{"corpus 0", "corpus 2"} {
+		tampered := rewrite(t, archive, func(name string, data []byte) []byte {
+			if name == ManifestName {
+				_, rest, _ := strings.Cut(string(data), "\n")
+
+				return []byte(header + "\n" + rest)
+			}
+
+			return data
+		}, nil)
+
+		_, err := Read(bytes.NewReader(tampered))
+
+		var version_err *gr.ErrUnsupportedVersion
+
+		if !errors.As(err, &version_err) {
+			t.Errorf("header %q: expected an *ErrUnsupportedVersion, got %v", header, err)
+		}
+	}
+}
+
+func TestReadTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+
+	// Only the header is written: the entry must be rejected before it is read.
+	err := tw.WriteHeader(&tar.Header{Name: "big/input", Mode: 0o644, Size: MaxEntrySize + 1, Typeflag: tar.TypeReg})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = zw.Close()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err = Read(&buf)
+	if err == nil || !strings.Contains(err.Error(), "exceeds the limit") {
+		t.Errorf("expected the entry to exceed the limit, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package corpus
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+)
+
+const (
+	// InputExt, TokensExt and TreeExt are the extensions of the files of a case
+	// in a corpus directory.
+	InputExt  string = ".input"
+	TokensExt string = ".tokens"
+	TreeExt   string = ".tree.json"
+)
+
+// FromDir reads a corpus from the files at the root of a directory: every
+// "<name>.input" file is a case, whose expected tokens and trees, if any, are in
+// "<name>.tokens" and "<name>.tree.json". Other files and subdirectories are
+// ignored.
+//
+// Parameters:
+//   - fsys: The directory; for instance, os.DirFS(path).
+//
+// Returns:
+//   - *Corpus: The corpus. Nil if an error occurred.
+//   - error: An error if fsys is nil, if the directory could not be read or if a
+//     case has an invalid name.
+func FromDir(fsys fs.FS) (*Corpus, error) {
+	if fsys == nil {
+		return nil, gcers.NewErrNilParameter("fsys")
+	}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var cases []Case
+
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), InputExt)
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+
+		c := Case{
+			Name: name,
+		}
+
+		c.Input, err = fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		c.Tokens, err = read_optional(fsys, name+TokensExt)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Tree, err = read_optional(fsys, name+TreeExt)
+		if err != nil {
+			return nil, err
+		}
+
+		cases = append(cases, c)
+	}
+
+	return New(cases...)
+}
+
+// read_optional is a helper function that reads a file that may not exist.
+//
+// Parameters:
+//   - fsys: The directory.
+//   - name: The name of the file.
+//
+// Returns:
+//   - []byte: The content of the file. Nil if it does not exist.
+//   - error: An error if the file exists but could not be read.
+func read_optional(fsys fs.FS, name string) ([]byte, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err == nil {
+		return data, nil
+	} else if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	return nil, err
+}
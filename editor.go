@@ -0,0 +1,358 @@
+package grammar
+
+import (
+	"fmt"
+	"slices"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// Edit is a change of the content of a document: the bytes of Span are replaced
+// with Text.
+type Edit struct {
+	// Span is the span of the bytes to replace. Empty to insert at Span.Start.
+	Span gr.Span
+
+	// Text is the new text. Empty to delete the bytes of Span.
+	Text []byte
+}
+
+// Highlight is the span of a token, to be colored according to its type.
+type Highlight[T gr.Enumer] struct {
+	// Type is the type of the token.
+	Type T
+
+	// Span is the span of the token.
+	Span gr.Span
+}
+
+// Symbol is an entry of the outline of a document.
+type Symbol[T gr.Enumer] struct {
+	// Type is the type of the node of the symbol.
+	Type T
+
+	// Span is the span of the node.
+	Span gr.Span
+
+	// Children are the symbols nested in the symbol.
+	Children []*Symbol[T]
+}
+
+// EditorSession keeps the artifacts an editor needs about a document (tokens,
+// tree, diagnostics, highlights and outline) up to date as the document is edited.
+// It is not safe for concurrent use.
+type EditorSession[T gr.Enumer] struct {
+	// g is the grammar of the document.
+	g *CompiledGrammar[T]
+
+	// in is the instance of the grammar that the session keeps for itself, so that
+	// its lexer still holds the tokens of the document on the next edit.
+	in *instance[T]
+
+	// outline_types are the types of the nodes that appear in the outline.
+	outline_types []T
+
+	// data is the content of the document.
+	data []byte
+
+	// tokens are the tokens of the document. Nil if the document could not be lexed.
+	tokens []*gr.Token[T]
+
+	// result is the result of the parse of the document.
+	result gr.Result[*gr.Token[T]]
+
+	// highlights are the highlights of the tokens of the document.
+	highlights []Highlight[T]
+
+	// outline is the outline of the document.
+	outline []*Symbol[T]
+
+	// gen is the generation of the last run of the grammar on the document. 0 if
+	// the document could not be lexed.
+	gen uint64
+}
+
+// NewEditorSession creates a new editor session and processes the initial content
+// of the document.
+//
+// Parameters:
+//   - g: The grammar of the document.
+//   - data: The initial content of the document.
+//   - outline_types: The types of the nodes that appear in the outline.
+//
+// Returns:
+//   - *EditorSession[T]: The new editor session.
+//   - error: An error of type *errors.ErrInvalidParameter if g is nil.
+func NewEditorSession[T gr.Enumer](g *CompiledGrammar[T], data []byte, outline_types ...T) (*EditorSession[T], error) {
+	if g == nil {
+		return nil, gcers.NewErrNilParameter("g")
+	}
+
+	s := &EditorSession[T]{
+		g:             g,
+		outline_types: outline_types,
+		data:          slices.Clone(data),
+	}
+
+	s.update(gr.Span{}, nil)
+
+	return s, nil
+}
+
+// ApplyEdit applies an edit to the document and updates every artifact.
+//
+// Parameters:
+//   - edit: The edit to apply.
+//
+// Returns:
+//   - error: An error of type *errors.ErrInvalidParameter if the span of the edit is
+//     not within the document.
+//
+// Lexing and syntax errors are not returned; they are part of the diagnostics. Only
+// the tokens around the edit are lexed again, unless the grammar was configured
+// since the last edit, and only their highlights are computed again. However, the
+// whole document is parsed again, so the tree and the outline are rebuilt.
+func (s *EditorSession[T]) ApplyEdit(edit Edit) error {
+	if s == nil {
+		return gcers.NilReceiver
+	}
+
+	if edit.Span.Start < 0 || edit.Span.End > len(s.data) || edit.Span.End < edit.Span.Start {
+		return gcers.NewErrInvalidParameter("edit", fmt.Errorf("span [%d, %d) is not within the document of %d bytes", edit.Span.Start, edit.Span.End, len(s.data)))
+	}
+
+	s.data = slices.Concat(s.data[:edit.Span.Start], edit.Text, s.data[edit.Span.End:])
+
+	s.update(edit.Span, edit.Text)
+
+	return nil
+}
+
+// update is a helper function that recomputes the artifacts of the document. The
+// highlights of the tokens away from the edit are reused; the tree and the outline
+// are built again.
+//
+// Parameters:
+//   - edit: The span of the bytes of the previous content that were replaced.
+//   - text: The new bytes.
+func (s *EditorSession[T]) update(edit gr.Span, text []byte) {
+	s.in = s.g.refresh(s.in)
+
+	tokens, res, gen, err := s.in.rerun(s.gen, edit, text, s.data)
+
+	s.gen = gen
+
+	if err != nil {
+		res = gr.NewFailedResult[*gr.Token[T]](nil, err)
+	}
+
+	s.tokens = tokens
+	s.result = res
+
+	s.highlights = update_highlights(s.highlights, tokens, edit, len(text))
+
+	s.outline = nil
+
+	for _, root := range res.Forest {
+		s.outline = append(s.outline, s.symbols(root)...)
+	}
+}
+
+// update_highlights is a helper function that updates the highlights of a document
+// after an edit. The highlights before the edit and those after it that match the
+// new tokens are kept, the latter moved by the difference of size of the edit;
+// only the ones in between are computed again.
+//
+// Parameters:
+//   - highlights: The highlights before the edit. Nil if there are none yet.
+//   - tokens: The tokens after the edit, EOF included.
+//   - edit: The span of the bytes of the previous content that were replaced.
+//   - size: The number of bytes that replaced them.
+//
+// Returns:
+//   - []Highlight[T]: The highlights after the edit.
+func update_highlights[T gr.Enumer](highlights []Highlight[T], tokens []*gr.Token[T], edit gr.Span, size int) []Highlight[T] {
+	// The tokens without a position (EOF) are at the end and have no highlight.
+	n := len(tokens)
+
+	for n > 0 && tokens[n-1].Pos < 0 {
+		n--
+	}
+
+	tokens = tokens[:n]
+
+	delta := size - edit.Len()
+
+	moved := func(span gr.Span) gr.Span {
+		return gr.NewSpan(span.Start+delta, span.End+delta)
+	}
+
+	prefix := 0
+
+	for prefix < len(highlights) && prefix < len(tokens) {
+		h := highlights[prefix]
+		tk := tokens[prefix]
+
+		if h.Span.End > edit.Start || h.Type != tk.Type || h.Span != tk.Span() {
+			break
+		}
+
+		prefix++
+	}
+
+	suffix := 0
+
+	for suffix < len(highlights)-prefix && suffix < len(tokens)-prefix {
+		h := highlights[len(highlights)-1-suffix]
+		tk := tokens[len(tokens)-1-suffix]
+
+		if h.Span.Start < edit.End || h.Type != tk.Type || moved(h.Span) != tk.Span() {
+			break
+		}
+
+		suffix++
+	}
+
+	// A new slice is made as the previous highlights may still be in use.
+	updated := make([]Highlight[T], 0, len(tokens))
+	updated = append(updated, highlights[:prefix]...)
+
+	for _, tk := range tokens[prefix : len(tokens)-suffix] {
+		if tk.Pos < 0 {
+			continue
+		}
+
+		updated = append(updated, Highlight[T]{
+			Type: tk.Type,
+			Span: tk.Span(),
+		})
+	}
+
+	for _, h := range highlights[len(highlights)-suffix:] {
+		h.Span = moved(h.Span)
+		updated = append(updated, h)
+	}
+
+	return updated
+}
+
+// symbols is a helper function that returns the outline of a node.
+//
+// Parameters:
+//   - node: The node.
+//
+// Returns:
+//   - []*Symbol[T]: The symbols of the node; that is, the node itself if it appears
+//     in the outline, or the symbols of its children otherwise.
+func (s EditorSession[T]) symbols(node *gr.Token[T]) []*Symbol[T] {
+	if node == nil {
+		return nil
+	}
+
+	// The tree is walked in post-order with an explicit stack as parse trees may be
+	// too deep for recursion.
+	type frame struct {
+		node     *gr.Token[T]
+		next     int
+		children []*Symbol[T]
+	}
+
+	stack := []*frame{{node: node}}
+
+	for {
+		top := stack[len(stack)-1]
+
+		if top.next < len(top.node.Children) {
+			child := top.node.Children[top.next]
+			top.next++
+
+			if child != nil {
+				stack = append(stack, &frame{node: child})
+			}
+
+			continue
+		}
+
+		stack = stack[:len(stack)-1]
+
+		symbols := top.children
+
+		if slices.Contains(s.outline_types, top.node.Type) {
+			symbols = []*Symbol[T]{
+				{
+					Type:     top.node.Type,
+					Span:     top.node.Span(),
+					Children: top.children,
+				},
+			}
+		}
+
+		if len(stack) == 0 {
+			return symbols
+		}
+
+		parent := stack[len(stack)-1]
+
+		if len(parent.children) == 0 {
+			parent.children = symbols
+		} else {
+			parent.children = append(parent.children, symbols...)
+		}
+	}
+}
+
+// Data returns the current content of the document.
+//
+// Returns:
+//   - []byte: The content of the document.
+func (s EditorSession[T]) Data() []byte {
+	return s.data
+}
+
+// Tokens returns the tokens of the document.
+//
+// Returns:
+//   - []*gr.Token[T]: The tokens, EOF included. Nil if the document could not be lexed.
+func (s EditorSession[T]) Tokens() []*gr.Token[T] {
+	return s.tokens
+}
+
+// Result returns the result of the parse of the document.
+//
+// Returns:
+//   - gr.Result[*gr.Token[T]]: The result of the parse.
+func (s EditorSession[T]) Result() gr.Result[*gr.Token[T]] {
+	return s.result
+}
+
+// Diagnostics returns the problems of the document.
+//
+// Returns:
+//   - []error: The diagnostics of the parse, followed by the reason of its failure if
+//     it failed.
+func (s EditorSession[T]) Diagnostics() []error {
+	diagnostics := slices.Clone(s.result.Diagnostics)
+
+	if s.result.Err != nil {
+		diagnostics = append(diagnostics, s.result.Err)
+	}
+
+	return diagnostics
+}
+
+// Highlights returns the highlights of the tokens of the document.
+//
+// Returns:
+//   - []Highlight[T]: The highlights, in the order of the document.
+func (s EditorSession[T]) Highlights() []Highlight[T] {
+	return s.highlights
+}
+
+// Outline returns the outline of the document.
+//
+// Returns:
+//   - []*Symbol[T]: The top-level symbols of the document.
+func (s EditorSession[T]) Outline() []*Symbol[T] {
+	return s.outline
+}
@@ -0,0 +1,126 @@
+package grammar
+
+import (
+	"fmt"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+func TestEditorSessionApplyEdit(t *testing.T) {
+	g := new_test_grammar(t)
+
+	s, err := NewEditorSession(g, []byte("ab cd ef"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	before := s.Tokens()
+
+	err = s.ApplyEdit(Edit{Span: gr.Span{Start: 3, End: 5}, Text: []byte("xyz")})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got := string(s.Data()); got != "ab xyz ef" {
+		t.Fatalf("expected the data %q, got %q", "ab xyz ef", got)
+	}
+
+	want := []string{"ab", "xyz", "ef"}
+
+	if got := words_of(s.Result()); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected the words %v, got %v", want, got)
+	}
+
+	after := s.Tokens()
+
+	// The tokens of the previous version of the document are left untouched.
+	if before[0].Lookahead != before[1] || before[1].Data != "cd" || before[2].Pos != 6 {
+		t.Errorf("expected the tokens before the edit to be left untouched")
+	}
+
+	if after[2].Pos != 7 {
+		t.Errorf("expected the token after the edit at 7, got %d", after[2].Pos)
+	}
+
+	// Running the grammar on another input must not corrupt the session.
+	_, err = Run([]byte("other words"), g)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = s.ApplyEdit(Edit{Span: gr.Span{Start: 0, End: 0}, Text: []byte("z ")})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want = []string{"z", "ab", "xyz", "ef"}
+
+	if got := words_of(s.Result()); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected the words %v, got %v", want, got)
+	}
+}
+
+func TestEditorSessionHighlights(t *testing.T) {
+	g := new_test_grammar(t)
+
+	s, err := NewEditorSession(g, []byte("ab cd ef gh"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	before := s.Highlights()
+
+	tests := []struct {
+		edit Edit
+		want string
+	}{
+		{Edit{Span: gr.NewSpan(3, 5), Text: []byte("xyz")}, "[0:2 3:6 7:9 10:12]"},
+		{Edit{Span: gr.NewSpan(0, 3), Text: nil}, "[0:3 4:6 7:9]"},
+		{Edit{Span: gr.NewSpan(9, 9), Text: []byte(" i")}, "[0:3 4:6 7:9 10:11]"},
+		{Edit{Span: gr.NewSpan(3, 3), Text: []byte("k")}, "[0:4 5:7 8:10 11:12]"},
+	}
+
+	for _, tt := range tests {
+		err := s.ApplyEdit(tt.edit)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		var spans []string
+
+		for _, h := range s.Highlights() {
+			if h.Type != tt_word {
+				t.Errorf("expected a highlight of type %v, got %v", tt_word, h.Type)
+			}
+
+			spans = append(spans, fmt.Sprintf("%d:%d", h.Span.Start, h.Span.End))
+		}
+
+		if got := fmt.Sprint(spans); got != tt.want {
+			t.Errorf("after %q: expected the highlights %s, got %s", s.Data(), tt.want, got)
+		}
+	}
+
+	if got := fmt.Sprint(before); got != "[{Word {0 2}} {Word {3 5}} {Word {6 8}} {Word {9 11}}]" {
+		t.Errorf("expected the highlights of the first version to be left untouched, got %s", got)
+	}
+}
+
+func TestEditorSessionApplyEditInvalidSpan(t *testing.T) {
+	g := new_test_grammar(t)
+
+	s, err := NewEditorSession(g, []byte("ab"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = s.ApplyEdit(Edit{Span: gr.Span{Start: 1, End: 3}})
+	if err == nil {
+		t.Fatalf("expected an error, got none")
+	}
+
+	if got := string(s.Data()); got != "ab" {
+		t.Errorf("expected the data %q, got %q", "ab", got)
+	}
+}
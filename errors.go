@@ -0,0 +1,139 @@
+package grammar
+
+import (
+	"strconv"
+	"strings"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+)
+
+// ErrUnknownGrammar is the error for grammars that are not registered.
+type ErrUnknownGrammar struct {
+	// Name is the name of the grammar.
+	Name string
+}
+
+// Error implements the error interface.
+//
+// Message: "grammar <name> is not registered".
+func (e ErrUnknownGrammar) Error() string {
+	return "grammar " + strconv.Quote(e.Name) + " is not registered"
+}
+
+// NewErrUnknownGrammar creates a new ErrUnknownGrammar.
+//
+// Parameters:
+//   - name: The name of the grammar.
+//
+// Returns:
+//   - *ErrUnknownGrammar: A pointer to the new ErrUnknownGrammar. Never returns nil.
+func NewErrUnknownGrammar(name string) *ErrUnknownGrammar {
+	return &ErrUnknownGrammar{
+		Name: name,
+	}
+}
+
+// ErrInFile is the error for errors that occurred in a file of a project.
+type ErrInFile struct {
+	// File is the name of the file.
+	File string
+
+	// Err is the error.
+	Err error
+}
+
+// Error implements the error interface.
+//
+// Message: "<file>: <err>".
+func (e ErrInFile) Error() string {
+	return e.File + ": " + gcers.Error(e.Err)
+}
+
+// Unwrap returns the underlying error.
+//
+// Returns:
+//   - error: The underlying error.
+func (e ErrInFile) Unwrap() error {
+	return e.Err
+}
+
+// NewErrInFile creates a new ErrInFile.
+//
+// Parameters:
+//   - file: The name of the file.
+//   - err: The error.
+//
+// Returns:
+//   - *ErrInFile: A pointer to the new ErrInFile. Never returns nil.
+func NewErrInFile(file string, err error) *ErrInFile {
+	return &ErrInFile{
+		File: file,
+		Err:  err,
+	}
+}
+
+// ErrImportCycle is the error for files that import each other.
+type ErrImportCycle struct {
+	// Files are the names of the files of the cycle. The first and the last ones
+	// are the same.
+	Files []string
+}
+
+// Error implements the error interface.
+//
+// Message: "import cycle: <file> -> <file> -> ...".
+func (e ErrImportCycle) Error() string {
+	return "import cycle: " + strings.Join(e.Files, " -> ")
+}
+
+// NewErrImportCycle creates a new ErrImportCycle.
+//
+// Parameters:
+//   - files: The names of the files of the cycle.
+//
+// Returns:
+//   - *ErrImportCycle: A pointer to the new ErrImportCycle. Never returns nil.
+func NewErrImportCycle(files []string) *ErrImportCycle {
+	return &ErrImportCycle{
+		Files: files,
+	}
+}
+
+// ErrInvalidConfig is the error for settings of a configuration that are invalid.
+type ErrInvalidConfig struct {
+	// Field is the name of the setting.
+	Field string
+
+	// Reason is the reason why the setting is invalid.
+	Reason error
+}
+
+// Error implements the error interface.
+//
+// Message: "invalid setting <field>: <reason>".
+func (e ErrInvalidConfig) Error() string {
+	return "invalid setting " + strconv.Quote(e.Field) + ": " + gcers.Error(e.Reason)
+}
+
+// Unwrap returns the underlying error.
+//
+// Returns:
+//   - error: The reason why the setting is invalid.
+func (e ErrInvalidConfig) Unwrap() error {
+	return e.Reason
+}
+
+// NewErrInvalidConfig creates a new ErrInvalidConfig.
+//
+// Parameters:
+//   - field: The name of the setting.
+//   - reason: The reason why the setting is invalid.
+//
+// Returns:
+//   - *ErrInvalidConfig: A pointer to the new ErrInvalidConfig. Never returns nil.
+func NewErrInvalidConfig(field string, reason error) *ErrInvalidConfig {
+	return &ErrInvalidConfig{
+		Field:  field,
+		Reason: reason,
+	}
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"fmt"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// Program is the root of the AST: the statements of an input, in order.
+type Program struct {
+	// Stmts are the statements.
+	Stmts []Stmt
+}
+
+// Stmt is a statement: either a *Let or a *Print.
+type Stmt interface {
+	// Span returns the span of the statement in the input.
+	//
+	// Returns:
+	//   - gr.Span: The span.
+	Span() gr.Span
+}
+
+// Expr is an expression: either an *Ident, a *Number or a *Sum.
+type Expr interface {
+	// Span returns the span of the expression in the input.
+	//
+	// Returns:
+	//   - gr.Span: The span.
+	Span() gr.Span
+}
+
+// Let is the declaration of a variable: "let <name> = <value>;".
+type Let struct {
+	// Name is the declared variable.
+	Name *Ident
+
+	// Value is the value of the variable.
+	Value Expr
+
+	// span is the span of the statement.
+	span gr.Span
+}
+
+// Span implements the Stmt interface.
+func (s Let) Span() gr.Span {
+	return s.span
+}
+
+// Print is the print of a value: "print <value>;".
+type Print struct {
+	// Value is the printed value.
+	Value Expr
+
+	// span is the span of the statement.
+	span gr.Span
+}
+
+// Span implements the Stmt interface.
+func (s Print) Span() gr.Span {
+	return s.span
+}
+
+// Ident is a variable name.
+type Ident struct {
+	// Name is the name.
+	Name string
+
+	// span is the span of the name.
+	span gr.Span
+}
+
+// Span implements the Expr interface.
+func (e Ident) Span() gr.Span {
+	return e.span
+}
+
+// Number is an integer literal.
+type Number struct {
+	// Text is the literal, as written.
+	Text string
+
+	// Value is the value of the literal.
+	Value int64
+
+	// span is the span of the literal.
+	span gr.Span
+}
+
+// Span implements the Expr interface.
+func (e Number) Span() gr.Span {
+	return e.span
+}
+
+// Sum is the sum of two expressions: "<left> + <right>". Parentheses only group
+// and are not kept in the AST.
+type Sum struct {
+	// Left is the left operand.
+	Left Expr
+
+	// Right is the right operand.
+	Right Expr
+
+	// span is the span of the sum, parentheses included.
+	span gr.Span
+}
+
+// Span implements the Expr interface.
+func (e Sum) Span() gr.Span {
+	return e.span
+}
+
+// to_program converts a parse tree into a program.
+//
+// Parameters:
+//   - root: The root of the parse tree; of type NtProgram. Assumed to be non-nil.
+//
+// Returns:
+//   - *Program: The program. Never returns nil.
+//   - error: An error if the tree does not follow the grammar.
+//
+// NtStmts is left-recursive, so the statements are walked with a loop rather than
+// by recursion; inputs may have any number of them.
+func to_program(root *gr.Token[TokenType]) (*Program, error) {
+	if root.Type != NtProgram || len(root.Children) != 1 {
+		return nil, fmt.Errorf("expected a %s, got %s", NtProgram, root.Type)
+	}
+
+	var stmts []Stmt
+
+	for node := root.Children[0]; node != nil; {
+		var last *gr.Token[TokenType]
+
+		switch len(node.Children) {
+		case 1:
+			last, node = node.Children[0], nil
+		case 2:
+			last, node = node.Children[1], node.Children[0]
+		default:
+			return nil, fmt.Errorf("expected %s to have 1 or 2 children, got %d", NtStmts, len(node.Children))
+		}
+
+		stmt, err := to_stmt(last)
+		if err != nil {
+			return nil, err
+		}
+
+		stmts = append(stmts, stmt)
+	}
+
+	for i, j := 0, len(stmts)-1; i < j; i, j = i+1, j-1 {
+		stmts[i], stmts[j] = stmts[j], stmts[i]
+	}
+
+	return &Program{Stmts: stmts}, nil
+}
+
+// to_stmt converts a NtStmt node into a statement.
+//
+// Parameters:
+//   - node: The node. Assumed to be non-nil.
+//
+// Returns:
+//   - Stmt: The statement.
+//   - error: An error if the node does not follow the grammar.
+func to_stmt(node *gr.Token[TokenType]) (Stmt, error) {
+	children := node.Children
+
+	switch {
+	case len(children) == 5 && children[0].Type == TtLet:
+		value, err := to_expr(children[3])
+		if err != nil {
+			return nil, err
+		}
+
+		return &Let{
+			Name:  &Ident{Name: children[1].Data, span: children[1].Span()},
+			Value: value,
+			span:  node.Span(),
+		}, nil
+	case len(children) == 3 && children[0].Type == TtPrint:
+		value, err := to_expr(children[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return &Print{Value: value, span: node.Span()}, nil
+	default:
+		return nil, fmt.Errorf("unexpected %s at %d", NtStmt, node.Span().Start)
+	}
+}
+
+// to_expr converts a NtExpr or NtAtom node into an expression.
+//
+// Parameters:
+//   - node: The node. Assumed to be non-nil.
+//
+// Returns:
+//   - Expr: The expression.
+//   - error: An error if the node does not follow the grammar.
+func to_expr(node *gr.Token[TokenType]) (Expr, error) {
+	// The nodes are converted with an explicit stack, as expressions may be nested
+	// too deeply for recursion. A sum is pushed back under its operands, and built
+	// once both of them are on top of exprs.
+	type frame struct {
+		node *gr.Token[TokenType]
+		sum  bool
+	}
+
+	stack := []frame{{node: node}}
+
+	var exprs []Expr
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if top.sum {
+			left, right := exprs[len(exprs)-2], exprs[len(exprs)-1]
+			exprs = append(exprs[:len(exprs)-2], &Sum{Left: left, Right: right, span: top.node.Span()})
+
+			continue
+		}
+
+		children := top.node.Children
+
+		switch {
+		case top.node.Type == TtIdent:
+			exprs = append(exprs, &Ident{Name: top.node.Data, span: top.node.Span()})
+		case top.node.Type == TtNumber:
+			value, _ := gr.TokenValueAs[int64](top.node)
+
+			exprs = append(exprs, &Number{Text: top.node.Data, Value: value, span: top.node.Span()})
+		case len(children) == 1:
+			stack = append(stack, frame{node: children[0]})
+		case len(children) == 3 && children[0].Type == TtLparen:
+			stack = append(stack, frame{node: children[1]})
+		case len(children) == 3 && children[1].Type == TtPlus:
+			stack = append(stack, frame{node: top.node, sum: true}, frame{node: children[2]}, frame{node: children[0]})
+		default:
+			return nil, fmt.Errorf("unexpected %s at %d", top.node.Type, top.node.Span().Start)
+		}
+	}
+
+	return exprs[0], nil
+}
+
+// idents returns the variables that an expression reads, from left to right.
+//
+// Parameters:
+//   - e: The expression.
+//
+// Returns:
+//   - []*Ident: The variables.
+func idents(e Expr) []*Ident {
+	var ids []*Ident
+
+	stack := []Expr{e}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		switch top := top.(type) {
+		case *Ident:
+			ids = append(ids, top)
+		case *Sum:
+			stack = append(stack, top.Right, top.Left)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// Severity is the severity of a diagnostic.
+type Severity int
+
+const (
+	// SeverityError is the severity of the problems that make the input invalid.
+	SeverityError Severity = iota
+
+	// SeverityWarning is the severity of the likely mistakes.
+	SeverityWarning
+
+	// SeverityNote is the severity of the remarks.
+	SeverityNote
+)
+
+// String implements the fmt.Stringer interface.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityNote:
+		return "note"
+	default:
+		return "Severity(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+const (
+	// CodeLexing is the code of the inputs that cannot be lexed: characters that
+	// cannot start a token and numbers that do not fit in an int64.
+	CodeLexing string = "E001"
+
+	// CodeSyntax is the code of the inputs that do not follow the grammar.
+	CodeSyntax string = "E002"
+)
+
+// Related is a location related to a diagnostic, such as a previous declaration.
+type Related struct {
+	// Span is the span of the location.
+	Span gr.Span
+
+	// Message explains how the location relates to the diagnostic.
+	Message string
+}
+
+// Diagnostic is a problem found in an input.
+type Diagnostic struct {
+	// Code is the stable code of the kind of problem, such as "L001". Tools filter
+	// and suppress diagnostics by code, so codes are never reused.
+	Code string
+
+	// Severity is the severity of the problem.
+	Severity Severity
+
+	// Span is the span of the problem in the input.
+	Span gr.Span
+
+	// Message describes the problem.
+	Message string
+
+	// Related are the other locations involved in the problem.
+	Related []Related
+}
+
+// Position is a position in an input, for humans.
+type Position struct {
+	// Line is the line, starting from 1.
+	Line int
+
+	// Column is the column in characters, starting from 1.
+	Column int
+}
+
+// position_of returns the position of an offset in an input.
+//
+// Parameters:
+//   - data: The input.
+//   - offset: The offset, in bytes. Clamped to the input.
+//
+// Returns:
+//   - Position: The position.
+func position_of(data []byte, offset int) Position {
+	offset = max(min(offset, len(data)), 0)
+
+	before := data[:offset]
+
+	line_start := strings.LastIndexByte(string(before), '\n') + 1
+
+	return Position{
+		Line:   strings.Count(string(before), "\n") + 1,
+		Column: utf8.RuneCount(before[line_start:]) + 1,
+	}
+}
+
+// Format formats the diagnostic like compilers do, followed by one line per
+// related location.
+//
+// Parameters:
+//   - name: The name of the input.
+//   - data: The input.
+//
+// Returns:
+//   - string: The formatted diagnostic.
+//
+// Format:
+//
+//	<name>:<line>:<col>: <severity>[<code>]: <message>
+//		<name>:<line>:<col>: note: <related message>
+func (d Diagnostic) Format(name string, data []byte) string {
+	var builder strings.Builder
+
+	pos := position_of(data, d.Span.Start)
+
+	builder.WriteString(name + ":" + strconv.Itoa(pos.Line) + ":" + strconv.Itoa(pos.Column) + ": ")
+	builder.WriteString(d.Severity.String() + "[" + d.Code + "]: " + d.Message)
+
+	for _, rel := range d.Related {
+		pos := position_of(data, rel.Span.Start)
+
+		builder.WriteString("\n\t" + name + ":" + strconv.Itoa(pos.Line) + ":" + strconv.Itoa(pos.Column) + ": note: " + rel.Message)
+	}
+
+	return builder.String()
+}
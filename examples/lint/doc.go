@@ -0,0 +1,25 @@
+// Command lint is an example linter for a small language of variable declarations
+// and prints. It shows the intended composition of the module, from the grammar to
+// the reports:
+//
+//  1. lint.grammar is the grammar of the language and lint_parser.go is the parser
+//     that cmd/parsergen generates from it;
+//  2. lexer.go lexes the input with a lexer.Builder and the generated parser turns
+//     the tokens into a parse tree;
+//  3. ast.go converts the parse tree into typed AST nodes;
+//  4. pass.go runs passes over the AST, each of which reports diagnostics with a
+//     stable code, a severity, a span and related locations;
+//  5. sarif.go writes the diagnostics as a SARIF log for CI systems; the default
+//     output is one line per diagnostic, like compilers do.
+//
+// Copy this directory as the template of a new linter: change the grammar, run
+// go generate, and write the AST conversion and the passes of the new language.
+//
+// Usage:
+//
+//	lint [-format text|sarif] file...
+//
+// The exit status is 1 if any diagnostic is an error, 2 if the usage is invalid.
+package main
+
+//go:generate go run github.com/PlayerR9/grammar/cmd/parsergen -i lint.grammar -o lint_parser.go
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"unicode"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/grammar/lexer"
+)
+
+// keywords are the reserved words of the language.
+var keywords = map[string]TokenType{
+	"let":   TtLet,
+	"print": TtPrint,
+}
+
+// new_lexer creates the lexer of the language.
+//
+// Returns:
+//   - *lexer.Lexer[TokenType]: The new lexer. Never returns nil.
+//
+// Comments run from '#' to the end of the line and are skipped, like whitespace.
+// Numbers get their int64 value, so that the passes need not parse them.
+func new_lexer() *lexer.Lexer[TokenType] {
+	b := lexer.NewBuilder[TokenType]()
+
+	for _, ws := range []string{" ", "\t", "\r", "\n"} {
+		_ = b.RegisterSkip(ws)
+	}
+
+	_ = b.RegisterLiteral(TtAssign, "=")
+	_ = b.RegisterLiteral(TtPlus, "+")
+	_ = b.RegisterLiteral(TtSemi, ";")
+	_ = b.RegisterLiteral(TtLparen, "(")
+	_ = b.RegisterLiteral(TtRparen, ")")
+
+	b.Register('#', lex_comment)
+	b.RegisterDefault(lex_word)
+
+	b.RegisterValue(TtNumber, lexer.IntValue)
+
+	return b.Build()
+}
+
+// lex_comment is a helper function that skips a comment.
+//
+// Parameters:
+//   - l: The lexer. Assumed to be non-nil.
+//
+// Returns:
+//   - *gr.Token[TokenType]: Always nil, as comments are skipped.
+//   - error: Always nil.
+func lex_comment(l *lexer.Lexer[TokenType]) (*gr.Token[TokenType], error) {
+	for {
+		c, ok := l.PeekRune()
+		if !ok || c == '\n' {
+			return nil, nil
+		}
+
+		_, _ = l.NextRune()
+	}
+}
+
+// lex_word is a helper function that lexes a number, an identifier or a keyword.
+//
+// Parameters:
+//   - l: The lexer. Assumed to be non-nil.
+//
+// Returns:
+//   - *gr.Token[TokenType]: The token.
+//   - error: An error if the next character cannot start a token.
+func lex_word(l *lexer.Lexer[TokenType]) (*gr.Token[TokenType], error) {
+	c, _ := l.PeekRune()
+
+	var is func(rune) bool
+
+	switch {
+	case unicode.IsDigit(c):
+		is = unicode.IsDigit
+	case c == '_' || unicode.IsLetter(c):
+		is = func(c rune) bool {
+			return c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected character %q", c)
+	}
+
+	var word []rune
+
+	for {
+		c, ok := l.PeekRune()
+		if !ok || !is(c) {
+			break
+		}
+
+		_, _ = l.NextRune()
+		word = append(word, c)
+	}
+
+	if unicode.IsDigit(word[0]) {
+		return gr.NewTerminalToken(TtNumber, string(word)), nil
+	}
+
+	type_, ok := keywords[string(word)]
+	if !ok {
+		type_ = TtIdent
+	}
+
+	return gr.NewTerminalToken(type_, string(word)), nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// Lint checks an input: it lexes and parses it and, if it is valid, runs the
+// passes over its AST.
+//
+// Parameters:
+//   - data: The input.
+//   - passes: The passes to run.
+//
+// Returns:
+//   - []Diagnostic: The diagnostics, in the order of the input. A lexing or syntax
+//     error is the only diagnostic, as the passes need an AST.
+func Lint(data []byte, passes []Pass) []Diagnostic {
+	lx := new_lexer()
+
+	err := lx.SetInputStream(data)
+	if err == nil {
+		err = lx.Lex()
+	}
+
+	tokens := lx.Tokens()
+
+	if err != nil {
+		at := resume_offset(data, tokens)
+		_, size := utf8.DecodeRune(data[at:])
+
+		return []Diagnostic{{
+			Code:     CodeLexing,
+			Severity: SeverityError,
+			Span:     gr.NewSpan(at, at+size),
+			Message:  err.Error(),
+		}}
+	}
+
+	res := Parse(tokens)
+
+	root, ok := res.Root()
+	if !ok {
+		return []Diagnostic{{
+			Code:     CodeSyntax,
+			Severity: SeverityError,
+			Span:     error_span(data, tokens, res.ErrPos),
+			Message:  res.Err.Error(),
+		}}
+	}
+
+	prog, err := to_program(root)
+	if err != nil {
+		return []Diagnostic{{
+			Code:     CodeSyntax,
+			Severity: SeverityError,
+			Span:     root.Span(),
+			Message:  err.Error(),
+		}}
+	}
+
+	return RunPasses(prog, passes)
+}
+
+// resume_offset is a helper function that finds where the lexer stopped: right
+// after the last token, once the whitespace and the comments are skipped.
+//
+// Parameters:
+//   - data: The input.
+//   - tokens: The tokens lexed before the error, EOF included.
+//
+// Returns:
+//   - int: The offset of the character that the lexer could not lex.
+func resume_offset(data []byte, tokens []*gr.Token[TokenType]) int {
+	var at int
+
+	if len(tokens) > 1 {
+		last := tokens[len(tokens)-2]
+		at = last.Pos + len(last.Data)
+	}
+
+	for at < len(data) {
+		c, size := utf8.DecodeRune(data[at:])
+
+		switch {
+		case unicode.IsSpace(c):
+			at += size
+		case c == '#':
+			for at < len(data) && data[at] != '\n' {
+				at++
+			}
+		default:
+			return at
+		}
+	}
+
+	return at
+}
+
+// error_span is a helper function that gives the span of the token at which the
+// parse failed.
+//
+// Parameters:
+//   - data: The input.
+//   - tokens: The tokens of the input, EOF included.
+//   - pos: The offset of the failure. The end of the input, or -1, at EOF.
+//
+// Returns:
+//   - gr.Span: The span of the token; empty at the end of the input.
+func error_span(data []byte, tokens []*gr.Token[TokenType], pos int) gr.Span {
+	for _, tk := range tokens {
+		if tk.Type != EtEOF && tk.Pos == pos {
+			return tk.Span()
+		}
+	}
+
+	return gr.NewSpan(len(data), len(data))
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample.lint")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	diags := Lint(data, Passes)
+
+	var codes []string
+
+	for _, d := range diags {
+		codes = append(codes, d.Code)
+	}
+
+	want := []string{"L001", "L002", "L003"}
+	if !slices.Equal(codes, want) {
+		t.Fatalf("expected codes %v, got %v", want, codes)
+	}
+
+	const want_fmt = "sample.lint:4:5: warning[L003]: \"x\" is already declared\n\tsample.lint:2:5: note: previous declaration of \"x\""
+
+	if got := diags[2].Format("sample.lint", data); got != want_fmt {
+		t.Errorf("expected %q, got %q", want_fmt, got)
+	}
+}
+
+func TestLintErrors(t *testing.T) {
+	tests := map[string]string{
+		"let a = 1;\nprint a +;\n": CodeSyntax,
+		"let a = 1 $;\n":           CodeLexing,
+		"let a = 1;\nprint a":      CodeSyntax,
+	}
+
+	for input, code := range tests {
+		diags := Lint([]byte(input), Passes)
+
+		if len(diags) != 1 || diags[0].Code != code || diags[0].Severity != SeverityError {
+			t.Errorf("%q: expected one %s error, got %v", input, code, diags)
+		}
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample.lint")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	files := []File{{Name: "sample.lint", Data: data, Diagnostics: Lint(data, Passes)}}
+
+	var buf bytes.Buffer
+
+	err = WriteSARIF(&buf, files, Passes)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var log sarif_log
+
+	err = json.Unmarshal(buf.Bytes(), &log)
+	if err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+
+	results := log.Runs[0].Results
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	region := results[2].RelatedLocations[0].PhysicalLocation.Region
+	if region.StartLine != 2 || region.StartColumn != 5 {
+		t.Errorf("expected the related location at 2:5, got %d:%d", region.StartLine, region.StartColumn)
+	}
+}
+
+func TestLintDeepExpression(t *testing.T) {
+	// Deeply nested expressions are converted to the AST without recursion.
+	const depth = 1000
+
+	var input bytes.Buffer
+
+	input.WriteString("let a = 1;\nprint ")
+	input.Write(bytes.Repeat([]byte("(a + "), depth))
+	input.WriteString("a")
+	input.Write(bytes.Repeat([]byte(")"), depth))
+	input.WriteString(";\n")
+
+	if diags := Lint(input.Bytes(), Passes); len(diags) != 0 {
+		t.Errorf("expected no diagnostic, got %v", diags)
+	}
+}
+
+func TestParseErrorAtEOF(t *testing.T) {
+	const input = "let a = 1;\nprint a"
+
+	lx := new_lexer()
+
+	err := lx.SetInputStream([]byte(input))
+	if err == nil {
+		err = lx.Lex()
+	}
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	res := Parse(lx.Tokens())
+	if res.Err == nil {
+		t.Fatalf("expected an error, got none")
+	} else if res.ErrPos != len(input) {
+		t.Errorf("expected the error at %d, got %d", len(input), res.ErrPos)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// format is the output format: "text" or "sarif".
+var format = flag.String("format", "text", "The output format: text or sarif.")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: lint [-format text|sarif] file...")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if flag.NArg() == 0 || *format != "text" && *format != "sarif" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	files := make([]File, 0, flag.NArg())
+
+	for _, name := range flag.Args() {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+
+		files = append(files, File{
+			Name:        name,
+			Data:        data,
+			Diagnostics: Lint(data, Passes),
+		})
+	}
+
+	if *format == "sarif" {
+		err := WriteSARIF(os.Stdout, files, Passes)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	} else {
+		for _, file := range files {
+			for _, d := range file.Diagnostics {
+				fmt.Println(d.Format(file.Name, file.Data))
+			}
+		}
+	}
+
+	for _, file := range files {
+		for _, d := range file.Diagnostics {
+			if d.Severity == SeverityError {
+				os.Exit(1)
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"slices"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// Pass is a check of the AST. Passes are independent of each other, so that each
+// one can be enabled, disabled and tested alone.
+type Pass struct {
+	// Code is the code of the diagnostics of the pass.
+	Code string
+
+	// Name is the name of the pass, such as "unused".
+	Name string
+
+	// Doc is a one-line description of what the pass reports.
+	Doc string
+
+	// Severity is the severity of the diagnostics of the pass.
+	Severity Severity
+
+	// Run checks the program and reports the problems to the reporter.
+	Run func(prog *Program, r *Reporter)
+}
+
+// Reporter collects the diagnostics of a pass.
+type Reporter struct {
+	// pass is the pass that reports.
+	pass Pass
+
+	// diags are the reported diagnostics.
+	diags []Diagnostic
+}
+
+// Report reports a problem with the code and the severity of the pass.
+//
+// Parameters:
+//   - span: The span of the problem.
+//   - message: The description of the problem.
+//   - related: The other locations involved in the problem.
+func (r *Reporter) Report(span gr.Span, message string, related ...Related) {
+	r.diags = append(r.diags, Diagnostic{
+		Code:     r.pass.Code,
+		Severity: r.pass.Severity,
+		Span:     span,
+		Message:  message,
+		Related:  related,
+	})
+}
+
+// RunPasses runs the passes over the program, in order.
+//
+// Parameters:
+//   - prog: The program. Assumed to be non-nil.
+//   - passes: The passes. Passes without a Run function are skipped.
+//
+// Returns:
+//   - []Diagnostic: The diagnostics of every pass, in the order of the input and,
+//     at the same position, in the order of the passes.
+func RunPasses(prog *Program, passes []Pass) []Diagnostic {
+	var diags []Diagnostic
+
+	for _, pass := range passes {
+		if pass.Run == nil {
+			continue
+		}
+
+		r := &Reporter{pass: pass}
+
+		pass.Run(prog, r)
+
+		diags = append(diags, r.diags...)
+	}
+
+	slices.SortStableFunc(diags, func(a, b Diagnostic) int {
+		return a.Span.Start - b.Span.Start
+	})
+
+	return diags
+}
@@ -0,0 +1,121 @@
+package main
+
+import "strconv"
+
+// Passes are the passes of the linter, in the order they run.
+var Passes = []Pass{
+	{
+		Code:     "L001",
+		Name:     "undefined",
+		Doc:      "reports the variables that are read before they are declared",
+		Severity: SeverityError,
+		Run:      check_undefined,
+	},
+	{
+		Code:     "L002",
+		Name:     "unused",
+		Doc:      "reports the declarations whose value is never read",
+		Severity: SeverityWarning,
+		Run:      check_unused,
+	},
+	{
+		Code:     "L003",
+		Name:     "redeclared",
+		Doc:      "reports the variables that are declared more than once",
+		Severity: SeverityWarning,
+		Run:      check_redeclared,
+	},
+}
+
+// reads returns the variables that a statement reads.
+//
+// Parameters:
+//   - stmt: The statement.
+//
+// Returns:
+//   - []*Ident: The variables, from left to right.
+func reads(stmt Stmt) []*Ident {
+	switch stmt := stmt.(type) {
+	case *Let:
+		return idents(stmt.Value)
+	case *Print:
+		return idents(stmt.Value)
+	default:
+		return nil
+	}
+}
+
+// check_undefined is the Run function of the "undefined" pass. The value of a
+// declaration cannot read the variable it declares.
+func check_undefined(prog *Program, r *Reporter) {
+	declared := make(map[string]bool)
+
+	for _, stmt := range prog.Stmts {
+		for _, id := range reads(stmt) {
+			if !declared[id.Name] {
+				r.Report(id.Span(), "undefined variable "+strconv.Quote(id.Name))
+			}
+		}
+
+		if let, ok := stmt.(*Let); ok {
+			declared[let.Name.Name] = true
+		}
+	}
+}
+
+// check_unused is the Run function of the "unused" pass. A declaration is unused
+// if its variable is not read before it is declared again or before the end.
+func check_unused(prog *Program, r *Reporter) {
+	live := make(map[string]*Let)
+	used := make(map[*Let]bool)
+
+	report := func(let *Let) {
+		if let != nil && !used[let] {
+			r.Report(let.Name.Span(), "the value of "+strconv.Quote(let.Name.Name)+" is never read")
+		}
+	}
+
+	for _, stmt := range prog.Stmts {
+		for _, id := range reads(stmt) {
+			if let, ok := live[id.Name]; ok {
+				used[let] = true
+			}
+		}
+
+		if let, ok := stmt.(*Let); ok {
+			report(live[let.Name.Name])
+
+			live[let.Name.Name] = let
+		}
+	}
+
+	for _, stmt := range prog.Stmts {
+		if let, ok := stmt.(*Let); ok && live[let.Name.Name] == let {
+			report(let)
+		}
+	}
+}
+
+// check_redeclared is the Run function of the "redeclared" pass.
+func check_redeclared(prog *Program, r *Reporter) {
+	first := make(map[string]*Let)
+
+	for _, stmt := range prog.Stmts {
+		let, ok := stmt.(*Let)
+		if !ok {
+			continue
+		}
+
+		prev, ok := first[let.Name.Name]
+		if !ok {
+			first[let.Name.Name] = let
+
+			continue
+		}
+
+		r.Report(let.Name.Span(), strconv.Quote(let.Name.Name)+" is already declared", Related{
+			Span:    prev.Name.Span(),
+			Message: "previous declaration of " + strconv.Quote(let.Name.Name),
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// File is an input and its diagnostics.
+type File struct {
+	// Name is the name of the input, as given on the command line.
+	Name string
+
+	// Data is the input.
+	Data []byte
+
+	// Diagnostics are the diagnostics of the input.
+	Diagnostics []Diagnostic
+}
+
+// The types below are the subset of the SARIF 2.1.0 format that the linter
+// writes. See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+type (
+	sarif_log struct {
+		Version string      `json:"version"`
+		Schema  string      `json:"$schema"`
+		Runs    []sarif_run `json:"runs"`
+	}
+
+	sarif_run struct {
+		Tool       sarif_tool     `json:"tool"`
+		ColumnKind string         `json:"columnKind"`
+		Results    []sarif_result `json:"results"`
+	}
+
+	sarif_tool struct {
+		Driver sarif_driver `json:"driver"`
+	}
+
+	sarif_driver struct {
+		Name  string       `json:"name"`
+		Rules []sarif_rule `json:"rules"`
+	}
+
+	sarif_rule struct {
+		ID               string        `json:"id"`
+		Name             string        `json:"name"`
+		ShortDescription sarif_message `json:"shortDescription"`
+	}
+
+	sarif_message struct {
+		Text string `json:"text"`
+	}
+
+	sarif_result struct {
+		RuleID           string           `json:"ruleId"`
+		Level            string           `json:"level"`
+		Message          sarif_message    `json:"message"`
+		Locations        []sarif_location `json:"locations"`
+		RelatedLocations []sarif_location `json:"relatedLocations,omitempty"`
+	}
+
+	sarif_location struct {
+		PhysicalLocation sarif_physical `json:"physicalLocation"`
+		Message          *sarif_message `json:"message,omitempty"`
+	}
+
+	sarif_physical struct {
+		ArtifactLocation sarif_artifact `json:"artifactLocation"`
+		Region           sarif_region   `json:"region"`
+	}
+
+	sarif_artifact struct {
+		URI string `json:"uri"`
+	}
+
+	sarif_region struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+		EndLine     int `json:"endLine"`
+		EndColumn   int `json:"endColumn"`
+	}
+)
+
+// WriteSARIF writes the diagnostics of the files as a SARIF 2.1.0 log, with one
+// run whose rules are the syntax errors and the given passes.
+//
+// Parameters:
+//   - w: The writer.
+//   - files: The files.
+//   - passes: The passes that were run.
+//
+// Returns:
+//   - error: An error if the log cannot be written.
+func WriteSARIF(w io.Writer, files []File, passes []Pass) error {
+	rules := []sarif_rule{
+		{ID: CodeLexing, Name: "lexing", ShortDescription: sarif_message{Text: "reports the characters that cannot start a token and the numbers that do not fit in an int64"}},
+		{ID: CodeSyntax, Name: "syntax", ShortDescription: sarif_message{Text: "reports the inputs that do not follow the grammar"}},
+	}
+
+	for _, pass := range passes {
+		rules = append(rules, sarif_rule{ID: pass.Code, Name: pass.Name, ShortDescription: sarif_message{Text: pass.Doc}})
+	}
+
+	results := []sarif_result{}
+
+	for _, file := range files {
+		for _, d := range file.Diagnostics {
+			res := sarif_result{
+				RuleID:    d.Code,
+				Level:     sarif_level(d.Severity),
+				Message:   sarif_message{Text: d.Message},
+				Locations: []sarif_location{sarif_location_of(file, d.Span.Start, d.Span.End, nil)},
+			}
+
+			for _, rel := range d.Related {
+				msg := &sarif_message{Text: rel.Message}
+
+				res.RelatedLocations = append(res.RelatedLocations, sarif_location_of(file, rel.Span.Start, rel.Span.End, msg))
+			}
+
+			results = append(results, res)
+		}
+	}
+
+	log := sarif_log{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarif_run{{
+			Tool:       sarif_tool{Driver: sarif_driver{Name: "lint", Rules: rules}},
+			ColumnKind: "unicodeCodePoints",
+			Results:    results,
+		}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(log)
+}
+
+// sarif_level is a helper function that gives the SARIF level of a severity.
+//
+// Parameters:
+//   - s: The severity.
+//
+// Returns:
+//   - string: The level.
+func sarif_level(s Severity) string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarif_location_of is a helper function that gives the SARIF location of a span
+// of a file.
+//
+// Parameters:
+//   - file: The file.
+//   - start: The start offset of the span.
+//   - end: The end offset of the span.
+//   - msg: The message of the location. Nil if there is none.
+//
+// Returns:
+//   - sarif_location: The location.
+func sarif_location_of(file File, start, end int, msg *sarif_message) sarif_location {
+	from := position_of(file.Data, start)
+	to := position_of(file.Data, end)
+
+	return sarif_location{
+		PhysicalLocation: sarif_physical{
+			ArtifactLocation: sarif_artifact{URI: file.Name},
+			Region: sarif_region{
+				StartLine:   from.Line,
+				StartColumn: from.Column,
+				EndLine:     to.Line,
+				EndColumn:   to.Column,
+			},
+		},
+		Message: msg,
+	}
+}
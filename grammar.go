@@ -0,0 +1,417 @@
+// Package grammar wires the lexer and the parser of the module together for the
+// common case of turning an input into a parse tree in one call.
+package grammar
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/grammar/lexer"
+	"github.com/PlayerR9/grammar/parser"
+)
+
+// CompiledGrammar is a lexer and a parser that are ready to be run. It is safe for
+// concurrent use: every run gets its own instance of the lexer and of the parser.
+type CompiledGrammar[T gr.Enumer] struct {
+	// mu protects the fields below, except memo.
+	mu sync.Mutex
+
+	// lexer is the lexer from which the instances are cloned.
+	lexer *lexer.Lexer[T]
+
+	// parser is the parser from which the instances are cloned.
+	parser *parser.Parser[T]
+
+	// version is increased every time the settings of the grammar change, so that
+	// the instances made with the previous settings are dropped.
+	version uint64
+
+	// free are the instances that are not in use.
+	free []*instance[T]
+
+	// cfg is the last configuration applied to the grammar. Nil if none.
+	cfg *Config
+
+	// metrics are the metrics to which the runs are reported. Nil if none.
+	metrics gr.Metrics
+
+	// memo is the memoization of the results of Run. See EnableMemo.
+	memo memo_table[T]
+}
+
+// instance is a lexer and a parser that are used by one run at a time.
+type instance[T gr.Enumer] struct {
+	// lexer is the lexer of the instance.
+	lexer *lexer.Lexer[T]
+
+	// parser is the parser of the instance.
+	parser *parser.Parser[T]
+
+	// version is the version of the settings of the grammar the instance was made
+	// with.
+	version uint64
+
+	// lexed counts the input streams given to the lexer. It tells whether the lexer
+	// still holds the tokens of a given run.
+	lexed uint64
+}
+
+// NewCompiledGrammar creates a new compiled grammar from a lexer and a parser. The
+// grammar takes ownership of both: the runs use clones of them, so they must not
+// be changed afterwards.
+//
+// Parameters:
+//   - lx: The lexer.
+//   - p: The parser.
+//
+// Returns:
+//   - *CompiledGrammar[T]: The new compiled grammar.
+//   - error: An error of type *errors.ErrInvalidParameter if lx or p is nil.
+func NewCompiledGrammar[T gr.Enumer](lx *lexer.Lexer[T], p *parser.Parser[T]) (*CompiledGrammar[T], error) {
+	if lx == nil {
+		return nil, gcers.NewErrNilParameter("lx")
+	} else if p == nil {
+		return nil, gcers.NewErrNilParameter("p")
+	}
+
+	return &CompiledGrammar[T]{
+		lexer:  lx,
+		parser: p,
+	}, nil
+}
+
+// Compile builds the lexer and the parser of the given builders.
+//
+// Parameters:
+//   - lb: The lexer builder.
+//   - pb: The parser builder.
+//
+// Returns:
+//   - *CompiledGrammar[T]: The new compiled grammar. Never returns nil.
+func Compile[T gr.Enumer](lb lexer.Builder[T], pb parser.Builder[T]) *CompiledGrammar[T] {
+	return &CompiledGrammar[T]{
+		lexer:  lb.Build(),
+		parser: pb.Build(),
+	}
+}
+
+// Run lexes and parses the given data with the given grammar.
+//
+// Parameters:
+//   - data: The input stream.
+//   - g: The compiled grammar.
+//
+// Returns:
+//   - gr.Result[*gr.Token[T]]: The result of the parse.
+//   - error: An error if g is nil or if the data could not be lexed.
+//
+// Syntax errors are not returned as errors; they are the Err field of the result.
+// Concurrent runs of the same grammar do not wait for each other. If the grammar
+// memoizes its results (see EnableMemo), the result of an input that was already
+// run is a copy of the previous one.
+//
+// The result shares no token with the grammar, which works on copies of the
+// tokens it lexes. Once frozen (see gr.Result.Freeze), it can thus be read from
+// several goroutines while the grammar goes on running.
+func Run[T gr.Enumer](data []byte, g *CompiledGrammar[T]) (gr.Result[*gr.Token[T]], error) {
+	if g == nil {
+		return gr.Result[*gr.Token[T]]{}, gcers.NewErrNilParameter("g")
+	}
+
+	return g.memoized(context.Background(), data)
+}
+
+// RunCtx is like Run but stops as soon as the context is done.
+//
+// Parameters:
+//   - ctx: The context.
+//   - data: The input stream.
+//   - g: The compiled grammar.
+//
+// Returns:
+//   - gr.Result[*gr.Token[T]]: The result of the parse. If the context is done
+//     during the parse, its error is of type *gr.ErrCancelled and its forest holds
+//     what was parsed so far.
+//   - error: An error of type *gr.ErrCancelled if the context is done during the
+//     lexing, or an error as for Run.
+//
+// Cancelled results are not memoized.
+func RunCtx[T gr.Enumer](ctx context.Context, data []byte, g *CompiledGrammar[T]) (gr.Result[*gr.Token[T]], error) {
+	if g == nil {
+		return gr.Result[*gr.Token[T]]{}, gcers.NewErrNilParameter("g")
+	}
+
+	return g.memoized(ctx, data)
+}
+
+// RunWith is like Run but gives a user context to the parse functions of the
+// grammar; see parser.UserContext. The context is only seen by this run. Since
+// the parse may depend on it, the memoization of the grammar is not used.
+//
+// Parameters:
+//   - data: The input stream.
+//   - g: The compiled grammar.
+//   - user_ctx: The user context. Nil for none.
+//
+// Returns:
+//   - gr.Result[*gr.Token[T]]: The result of the parse.
+//   - error: An error if g is nil or if the data could not be lexed.
+func RunWith[T gr.Enumer](data []byte, g *CompiledGrammar[T], user_ctx any) (gr.Result[*gr.Token[T]], error) {
+	return RunWithCtx(context.Background(), data, g, user_ctx)
+}
+
+// RunWithCtx combines RunWith and RunCtx.
+//
+// Parameters:
+//   - ctx: The context.
+//   - data: The input stream.
+//   - g: The compiled grammar.
+//   - user_ctx: The user context. Nil for none.
+//
+// Returns:
+//   - gr.Result[*gr.Token[T]]: The result of the parse, as for RunCtx.
+//   - error: An error as for RunCtx.
+func RunWithCtx[T gr.Enumer](ctx context.Context, data []byte, g *CompiledGrammar[T], user_ctx any) (gr.Result[*gr.Token[T]], error) {
+	if g == nil {
+		return gr.Result[*gr.Token[T]]{}, gcers.NewErrNilParameter("g")
+	}
+
+	_, res, err := g.run(ctx, data, nil, user_ctx)
+
+	return res, err
+}
+
+// acquire is a helper function that returns an instance that is not in use. The
+// instance must be given back with release.
+//
+// Returns:
+//   - *instance[T]: The instance. Never returns nil.
+func (g *CompiledGrammar[T]) acquire() *instance[T] {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.free) > 0 {
+		in := g.free[len(g.free)-1]
+		g.free = g.free[:len(g.free)-1]
+
+		return in
+	}
+
+	return &instance[T]{
+		lexer:   g.lexer.Clone(),
+		parser:  g.parser.Clone(),
+		version: g.version,
+	}
+}
+
+// release is a helper function that gives back an instance obtained with acquire.
+// Instances made with outdated settings are dropped.
+//
+// Parameters:
+//   - in: The instance. Assumed to be non-nil.
+func (g *CompiledGrammar[T]) release(in *instance[T]) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if in.version == g.version {
+		g.free = append(g.free, in)
+	}
+}
+
+// refresh is a helper function that returns the given instance if it was made
+// with the current settings and a new instance otherwise. It is used by the
+// sessions that keep an instance for themselves.
+//
+// Parameters:
+//   - in: The instance. Nil if there is none yet.
+//
+// Returns:
+//   - *instance[T]: The up to date instance. Never returns nil.
+func (g *CompiledGrammar[T]) refresh(in *instance[T]) *instance[T] {
+	g.mu.Lock()
+
+	ok := in != nil && in.version == g.version
+
+	g.mu.Unlock()
+
+	if ok {
+		return in
+	}
+
+	return g.acquire()
+}
+
+// update is a helper function that changes the settings of the grammar. The
+// instances made with the previous settings are dropped, and so are the memoized
+// results.
+//
+// Parameters:
+//   - fn: The function that changes the lexer and the parser from which the
+//     instances are cloned. It is called with g.mu held.
+func (g *CompiledGrammar[T]) update(fn func()) {
+	g.mu.Lock()
+
+	fn()
+
+	g.version++
+	g.free = nil
+
+	g.mu.Unlock()
+
+	g.ClearMemo()
+}
+
+// SetMetrics sets the metrics to which every lexing and every parse of the grammar
+// is reported.
+//
+// Parameters:
+//   - m: The metrics. Nil stops the reporting.
+func (g *CompiledGrammar[T]) SetMetrics(m gr.Metrics) {
+	if g == nil {
+		return
+	}
+
+	g.update(func() {
+		g.lexer.SetMetrics(m)
+		g.parser.SetMetrics(m)
+		g.metrics = m
+	})
+}
+
+// inherit is a helper function that applies the settings of another grammar to
+// the grammar: its configuration, its metrics and the capacity of its
+// memoization. It is used when a grammar replaces another one.
+//
+// Parameters:
+//   - old: The grammar whose settings are applied. Assumed to be non-nil.
+func (g *CompiledGrammar[T]) inherit(old *CompiledGrammar[T]) {
+	old.mu.Lock()
+	cfg, metrics := old.cfg, old.metrics
+	old.mu.Unlock()
+
+	if cfg != nil {
+		_ = g.Configure(*cfg)
+	}
+
+	if metrics != nil {
+		g.SetMetrics(metrics)
+	}
+
+	old.memo.mu.Lock()
+	capacity := old.memo.capacity
+	old.memo.mu.Unlock()
+
+	g.EnableMemo(capacity)
+}
+
+// run is a helper function that lexes and parses the given data.
+//
+// Parameters:
+//   - ctx: The context.
+//   - data: The input stream.
+//   - interner: The interner of the data of the tokens. If nil, the data is not
+//     interned.
+//   - user_ctx: The user context of the parse. Nil for none.
+//
+// Returns:
+//   - []*gr.Token[T]: The tokens that were lexed, EOF included.
+//   - gr.Result[*gr.Token[T]]: The result of the parse. The bytes that the lexer
+//     replaced come first in its diagnostics.
+//   - error: An error if the data could not be lexed.
+func (g *CompiledGrammar[T]) run(ctx context.Context, data []byte, interner *gr.Interner, user_ctx any) ([]*gr.Token[T], gr.Result[*gr.Token[T]], error) {
+	in := g.acquire()
+	defer g.release(in)
+
+	err := in.lex(ctx, data)
+	if err != nil {
+		return nil, gr.Result[*gr.Token[T]]{}, err
+	}
+
+	tokens, res := in.parse(ctx, interner, user_ctx)
+
+	return tokens, res, nil
+}
+
+// rerun is a helper function that lexes and parses the data of a previous run
+// after an edit. If the lexer still holds the tokens of that run, only the tokens
+// around the edit are lexed again; otherwise, the whole data is lexed.
+//
+// Parameters:
+//   - gen: The generation of the previous run. 0 if there is none.
+//   - edit: The span of the bytes of the previous data that are replaced.
+//   - text: The new bytes.
+//   - data: The data after the edit.
+//
+// Returns:
+//   - []*gr.Token[T]: The tokens that were lexed, EOF included.
+//   - gr.Result[*gr.Token[T]]: The result of the parse.
+//   - uint64: The generation of the run, to pass to the next call.
+//   - error: An error if the data could not be lexed.
+func (in *instance[T]) rerun(gen uint64, edit gr.Span, text, data []byte) ([]*gr.Token[T], gr.Result[*gr.Token[T]], uint64, error) {
+	var err error
+
+	if gen == 0 || gen != in.lexed {
+		err = in.lex(context.Background(), data)
+	} else if in.lexer.Relex(edit, text) == nil {
+		in.lexed++
+	} else {
+		// The lexer is left halfway through the edit; start over.
+		err = in.lex(context.Background(), data)
+	}
+
+	if err != nil {
+		return nil, gr.Result[*gr.Token[T]]{}, 0, err
+	}
+
+	tokens, res := in.parse(context.Background(), nil, nil)
+
+	return tokens, res, in.lexed, nil
+}
+
+// lex is a helper function that lexes the given data.
+//
+// Parameters:
+//   - ctx: The context.
+//   - data: The input stream.
+//
+// Returns:
+//   - error: An error if the data could not be lexed.
+func (in *instance[T]) lex(ctx context.Context, data []byte) error {
+	in.lexed++
+
+	err := in.lexer.SetInputStream(data)
+	if err != nil {
+		return err
+	}
+
+	return in.lexer.LexCtx(ctx)
+}
+
+// parse is a helper function that parses the tokens of the last lexing.
+//
+// Parameters:
+//   - ctx: The context.
+//   - interner: The interner of the data of the tokens. If nil, the data is not
+//     interned.
+//   - user_ctx: The user context of the parse. Nil for none.
+//
+// Returns:
+//   - []*gr.Token[T]: The tokens that were lexed, EOF included.
+//   - gr.Result[*gr.Token[T]]: The result of the parse. The bytes that the lexer
+//     replaced come first in its diagnostics.
+func (in *instance[T]) parse(ctx context.Context, interner *gr.Interner, user_ctx any) ([]*gr.Token[T], gr.Result[*gr.Token[T]]) {
+	tokens := slices.Clone(in.lexer.Tokens())
+
+	gr.InternTokens(interner, tokens)
+
+	res := in.parser.ParseResultWithCtx(ctx, user_ctx, tokens)
+
+	if diags := in.lexer.Diagnostics(); len(diags) > 0 {
+		res.Diagnostics = append(diags, res.Diagnostics...)
+	}
+
+	return tokens, res
+}
@@ -0,0 +1,284 @@
+package grammar
+
+import (
+	"slices"
+	"strconv"
+)
+
+// Bracketer is implemented by the token types that mark some of their values as
+// brackets; for instance, '(' and ')'.
+type Bracketer[T Enumer] interface {
+	// ClosingBracket returns the type that closes the given opening bracket.
+	//
+	// Returns:
+	//   - T: The type of the closing bracket.
+	//   - bool: True if the type is an opening bracket, false otherwise.
+	ClosingBracket() (T, bool)
+
+	// OpeningBracket returns the type that opens the given closing bracket.
+	//
+	// Returns:
+	//   - T: The type of the opening bracket.
+	//   - bool: True if the type is a closing bracket, false otherwise.
+	OpeningBracket() (T, bool)
+}
+
+// BracketPair is a pair of matching brackets.
+type BracketPair[T Enumer] struct {
+	// Open is the opening bracket. Nil if the closing bracket is not matched.
+	Open *Token[T]
+
+	// Close is the closing bracket. Nil if the opening bracket is not matched.
+	Close *Token[T]
+
+	// OpenSpan is the span of the opening bracket. Empty if Open is nil.
+	OpenSpan Span
+
+	// CloseSpan is the span of the closing bracket. Empty if Close is nil.
+	CloseSpan Span
+
+	// Depth is the number of pairs the pair is nested in. The outermost pairs
+	// have a depth of 0.
+	Depth int
+
+	// Expected is, when only the opening bracket was found, the offset at which the
+	// closing bracket was expected at the latest; that is, the offset of the closing
+	// bracket of an enclosing pair or the end of the input. -1 otherwise.
+	Expected int
+}
+
+// IsMatched checks whether both brackets of the pair were found.
+//
+// Returns:
+//   - bool: True if the pair is matched, false otherwise.
+func (bp BracketPair[T]) IsMatched() bool {
+	return bp.Open != nil && bp.Close != nil
+}
+
+// start is a helper function that returns the offset of the first bracket of the pair.
+//
+// Returns:
+//   - int: The offset of the first bracket.
+func (bp BracketPair[T]) start() int {
+	if bp.Open != nil {
+		return bp.OpenSpan.Start
+	}
+
+	return bp.CloseSpan.Start
+}
+
+// BracketPairs returns the brackets of the forest, paired with each other; for
+// editor features such as rainbow brackets or the highlighting of the matching
+// bracket. The brackets are the terminal tokens whose type marks them as such
+// through the Bracketer interface.
+//
+// Parameters:
+//   - forest: The forest.
+//
+// Returns:
+//   - []BracketPair[T]: The pairs, ordered by the offset of their first bracket.
+//     Nil if T does not implement Bracketer.
+//
+// A closing bracket that does not match the innermost opening bracket closes the
+// nearest one it matches, leaving the ones in between unmatched; if there is no
+// such bracket, it is left unmatched.
+func BracketPairs[T Enumer](forest []*Token[T]) []BracketPair[T] {
+	var zero T
+
+	if _, ok := any(zero).(Bracketer[T]); !ok {
+		return nil
+	}
+
+	var pairs []BracketPair[T]
+	var opened []*Token[T]
+
+	unmatched := func(open *Token[T], depth, expected int) {
+		pairs = append(pairs, BracketPair[T]{
+			Open:     open,
+			OpenSpan: open.Span(),
+			Depth:    depth,
+			Expected: expected,
+		})
+	}
+
+	all := leaves(forest)
+
+	for _, leaf := range all {
+		b := any(leaf.Type).(Bracketer[T])
+
+		if _, ok := b.ClosingBracket(); ok {
+			opened = append(opened, leaf)
+
+			continue
+		}
+
+		open_type, ok := b.OpeningBracket()
+		if !ok {
+			continue
+		}
+
+		idx := -1
+
+		for i := len(opened) - 1; i >= 0; i-- {
+			if opened[i].Type == open_type {
+				idx = i
+
+				break
+			}
+		}
+
+		if idx == -1 {
+			pairs = append(pairs, BracketPair[T]{
+				Close:     leaf,
+				CloseSpan: leaf.Span(),
+				Depth:     len(opened),
+				Expected:  -1,
+			})
+
+			continue
+		}
+
+		for len(opened) > idx+1 {
+			unmatched(opened[len(opened)-1], len(opened)-1, leaf.Pos)
+			opened = opened[:len(opened)-1]
+		}
+
+		pairs = append(pairs, BracketPair[T]{
+			Open:      opened[idx],
+			Close:     leaf,
+			OpenSpan:  opened[idx].Span(),
+			CloseSpan: leaf.Span(),
+			Depth:     idx,
+			Expected:  -1,
+		})
+
+		opened = opened[:idx]
+	}
+
+	end := 0
+
+	if len(all) > 0 {
+		end = all[len(all)-1].Span().End
+	}
+
+	for len(opened) > 0 {
+		unmatched(opened[len(opened)-1], len(opened)-1, end)
+		opened = opened[:len(opened)-1]
+	}
+
+	slices.SortStableFunc(pairs, func(a, b BracketPair[T]) int {
+		return a.start() - b.start()
+	})
+
+	return pairs
+}
+
+// ErrMissingCloser is the error for an opening bracket that is never closed.
+type ErrMissingCloser[T Enumer] struct {
+	// Open is the opening bracket.
+	Open *Token[T]
+
+	// Closer is the type of the missing closing bracket.
+	Closer T
+
+	// At is the offset at which the closing bracket was expected at the latest.
+	At int
+}
+
+// Error implements the error interface.
+//
+// Message: "missing <closer> at <at> to close <open> at <pos>"
+func (e ErrMissingCloser[T]) Error() string {
+	var open string
+
+	if e.Open != nil {
+		open = strconv.Quote(e.Open.Type.String()) + " at " + strconv.Itoa(e.Open.Pos)
+	} else {
+		open = "the bracket"
+	}
+
+	return "missing " + strconv.Quote(e.Closer.String()) + " at " + strconv.Itoa(e.At) + " to close " + open
+}
+
+// NewErrMissingCloser creates a new ErrMissingCloser error.
+//
+// Parameters:
+//   - open: The opening bracket.
+//   - closer: The type of the missing closing bracket.
+//   - at: The offset at which the closing bracket was expected at the latest.
+//
+// Returns:
+//   - *ErrMissingCloser[T]: The new error. Never returns nil.
+func NewErrMissingCloser[T Enumer](open *Token[T], closer T, at int) *ErrMissingCloser[T] {
+	return &ErrMissingCloser[T]{
+		Open:   open,
+		Closer: closer,
+		At:     at,
+	}
+}
+
+// MissingClosers reports the opening brackets of the forest that are never closed,
+// along with the closing bracket to insert and the latest offset at which it was
+// expected; that is, the offset of the closing bracket of the enclosing pair or,
+// if there is none, the end of the input. (See BracketPairs)
+//
+// Parameters:
+//   - forest: The forest.
+//
+// Returns:
+//   - []*ErrMissingCloser[T]: The missing closing brackets, ordered by the offset of
+//     their opening bracket. Nil if none or if T does not implement Bracketer.
+func MissingClosers[T Enumer](forest []*Token[T]) []*ErrMissingCloser[T] {
+	var errs []*ErrMissingCloser[T]
+
+	for _, pair := range BracketPairs(forest) {
+		if pair.Open == nil || pair.Close != nil {
+			continue
+		}
+
+		closer, _ := any(pair.Open.Type).(Bracketer[T]).ClosingBracket()
+		// dbg.AssertOk(ok, "ClosingBracket()")
+
+		errs = append(errs, NewErrMissingCloser(pair.Open, closer, pair.Expected))
+	}
+
+	return errs
+}
+
+// leaves is a helper function that returns the terminal tokens of the forest that
+// have a position, in the order of the input stream.
+//
+// Parameters:
+//   - forest: The forest.
+//
+// Returns:
+//   - []*Token[T]: The terminal tokens.
+func leaves[T Enumer](forest []*Token[T]) []*Token[T] {
+	var leaves []*Token[T]
+
+	stack := slices.Clone(forest)
+	slices.Reverse(stack)
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if top == nil {
+			continue
+		}
+
+		if len(top.Children) == 0 {
+			if top.Pos >= 0 {
+				leaves = append(leaves, top)
+			}
+
+			continue
+		}
+
+		for i := len(top.Children) - 1; i >= 0; i-- {
+			stack = append(stack, top.Children[i])
+		}
+	}
+
+	return leaves
+}
@@ -0,0 +1,115 @@
+package grammar
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type bracket_type int
+
+const (
+	bt_eof bracket_type = iota
+	bt_word
+	bt_lparen
+	bt_rparen
+	bt_lbrack
+	bt_rbrack
+)
+
+func (t bracket_type) String() string {
+	return [...]string{"EOF", "Word", "(", ")", "[", "]"}[t]
+}
+
+// ClosingBracket implements the Bracketer interface.
+func (t bracket_type) ClosingBracket() (bracket_type, bool) {
+	switch t {
+	case bt_lparen:
+		return bt_rparen, true
+	case bt_lbrack:
+		return bt_rbrack, true
+	default:
+		return bt_eof, false
+	}
+}
+
+// OpeningBracket implements the Bracketer interface.
+func (t bracket_type) OpeningBracket() (bracket_type, bool) {
+	switch t {
+	case bt_rparen:
+		return bt_lparen, true
+	case bt_rbrack:
+		return bt_lbrack, true
+	default:
+		return bt_eof, false
+	}
+}
+
+// bracket_forest is a helper function that splits the input on spaces into
+// terminal tokens, under a single root.
+func bracket_forest(input string) []*Token[bracket_type] {
+	types := map[string]bracket_type{"(": bt_lparen, ")": bt_rparen, "[": bt_lbrack, "]": bt_rbrack}
+
+	var tokens []*Token[bracket_type]
+
+	pos := 0
+
+	for _, field := range strings.Split(input, " ") {
+		type_, ok := types[field]
+		if !ok {
+			type_ = bt_word
+		}
+
+		tk := NewTerminalToken(type_, field)
+		tk.Pos = pos
+
+		tokens = append(tokens, tk)
+
+		pos += len(field) + 1
+	}
+
+	return []*Token[bracket_type]{{Type: bt_word, Pos: 0, Children: tokens}}
+}
+
+func TestBracketPairs(t *testing.T) {
+	pairs := BracketPairs(bracket_forest("( [ a ] ) ]"))
+
+	var got []string
+
+	for _, pair := range pairs {
+		got = append(got, fmt.Sprintf("%v-%v@%d", pair.OpenSpan, pair.CloseSpan, pair.Depth))
+	}
+
+	want := []string{
+		fmt.Sprintf("%v-%v@0", NewSpan(0, 1), NewSpan(8, 9)),
+		fmt.Sprintf("%v-%v@1", NewSpan(2, 3), NewSpan(6, 7)),
+		fmt.Sprintf("%v-%v@0", Span{}, NewSpan(10, 11)),
+	}
+
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected the pairs %v, got %v", want, got)
+	}
+}
+
+func TestMissingClosers(t *testing.T) {
+	errs := MissingClosers(bracket_forest("( [ a ) ["))
+
+	want := []string{
+		`missing "]" at 6 to close "[" at 2`,
+		`missing "]" at 9 to close "[" at 8`,
+	}
+
+	var got []string
+
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if errs := MissingClosers(bracket_forest("( a )")); len(errs) != 0 {
+		t.Errorf("expected no missing closer, got %v", errs)
+	}
+}
@@ -0,0 +1,50 @@
+package grammar
+
+import "fmt"
+
+// ErrCancelled occurs when a lexing or a parse is stopped by its context, either
+// because the context was cancelled or because its deadline passed. The result
+// that carries it holds what was lexed or parsed so far.
+type ErrCancelled struct {
+	// Reason is the error of the context; context.Canceled or
+	// context.DeadlineExceeded.
+	Reason error
+
+	// Steps is the number of steps done before the cancellation; that is, the
+	// number of explored branches for a lexing and the number of actions for a
+	// parse.
+	Steps int
+}
+
+// Error implements the error interface.
+//
+// Message:
+//
+//	"cancelled after <steps> steps: <reason>"
+func (e ErrCancelled) Error() string {
+	return fmt.Sprintf("cancelled after %d steps: %v", e.Steps, e.Reason)
+}
+
+// Unwrap returns the error of the context, so that errors.Is can tell a
+// cancellation from a deadline.
+//
+// Returns:
+//   - error: The error of the context.
+func (e ErrCancelled) Unwrap() error {
+	return e.Reason
+}
+
+// NewErrCancelled creates a new ErrCancelled error.
+//
+// Parameters:
+//   - reason: The error of the context.
+//   - steps: The number of steps done before the cancellation.
+//
+// Returns:
+//   - *ErrCancelled: The new error. Never returns nil.
+func NewErrCancelled(reason error, steps int) *ErrCancelled {
+	return &ErrCancelled{
+		Reason: reason,
+		Steps:  steps,
+	}
+}
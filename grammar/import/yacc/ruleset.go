@@ -0,0 +1,263 @@
+package yacc
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	prx "github.com/PlayerR9/grammar/PREV/parser"
+)
+
+// TokenTyper is the type of the symbols of the imported rule sets. It is the same
+// as the token types of the parsers.
+type TokenTyper interface {
+	~int
+
+	// String returns the literal name of the token type.
+	//
+	// Returns:
+	//   - string: The literal name of the token type.
+	String() string
+
+	// IsTerminal checks if the token type is a terminal.
+	//
+	// Returns:
+	//   - bool: True if the token type is a terminal, false otherwise.
+	IsTerminal() bool
+}
+
+// max_nullables is the maximum number of nullable symbols in the right-hand side
+// of a rule, as every subset of them makes a rule when the empty rules are
+// eliminated.
+const max_nullables = 12
+
+// Symbols returns the names of the symbols of the rule sets made by ToRuleSet, by
+// value of the token type: "$end" (the end of the input), the tokens, "$accept"
+// (the source nonterminal) and the nonterminals. This is the same order as the
+// one of the parser generator.
+//
+// Returns:
+//   - []string: The names of the symbols.
+//   - int: The number of terminals; that is, the symbols before "$accept".
+func (g Grammar) Symbols() ([]string, int) {
+	names := make([]string, 0, len(g.Tokens)+len(g.Nonterminals)+2)
+
+	names = append(names, "$end")
+	names = append(names, g.Tokens...)
+	names = append(names, "$accept")
+	names = append(names, g.Nonterminals...)
+
+	return names, len(g.Tokens) + 1
+}
+
+// ToRuleSet converts a grammar into a rule set whose token types are the indices
+// of the symbols in Symbols. The rule "$accept : start $end" is added first,
+// followed by "$accept : $end" if the start symbol is nullable, so that the empty
+// input is accepted as with yacc.
+//
+// The rule sets have no empty rule, so the empty alternatives are eliminated: a
+// rule is added for every way of omitting the nullable symbols of the right-hand
+// side of another rule, and the nonterminals that only derive the empty string are
+// dropped. Thus, the nullable nonterminals do not appear in the syntax trees when
+// they match nothing. The rules whose right-hand side starts with "error" become
+// error productions (see RuleSet.MustMakeRuleWithError).
+//
+// The precedences are not part of the rule set; they are in g.Levels and in the
+// Prec of every rule. The items are not determined.
+//
+// Parameters:
+//   - g: The grammar.
+//
+// Returns:
+//   - *prx.RuleSet[T]: The rule set. Nil if an error occurred.
+//   - error: An error if g is nil, if T does not tell the terminals apart the way
+//     Symbols does, if "error" is used elsewhere than at the start of a rule or
+//     is followed by a nonterminal, or if a rule has too many nullable symbols.
+func ToRuleSet[T TokenTyper](g *Grammar) (*prx.RuleSet[T], error) {
+	if g == nil {
+		return nil, errors.New("no grammar was given")
+	}
+
+	names, terminals := g.Symbols()
+
+	for i, name := range names {
+		if T(i).IsTerminal() != (i < terminals) {
+			return nil, fmt.Errorf("symbol %q (%d) is a terminal in the grammar but not for the token type, or vice versa", name, i)
+		}
+	}
+
+	types := make(map[string]T, len(names))
+
+	for i, name := range names {
+		types[name] = T(i)
+	}
+
+	rules, err := g.eliminate_empty()
+	if err != nil {
+		return nil, err
+	}
+
+	rs := prx.NewRuleSet[T]()
+
+	source := types["$accept"]
+
+	if slices.ContainsFunc(rules, func(rule Rule) bool { return rule.Lhs == g.Start }) {
+		rs.MustMakeRule(source, []T{types[g.Start], types["$end"]})
+	}
+
+	if g.nullables()[g.Start] {
+		rs.MustMakeRule(source, []T{types["$end"]})
+	}
+
+	for _, rule := range rules {
+		rhs := make([]T, 0, len(rule.Rhs))
+
+		for _, symbol := range rule.Rhs {
+			rhs = append(rhs, types[symbol])
+		}
+
+		idx := slices.Index(rule.Rhs, "error")
+
+		switch {
+		case idx < 0:
+			rs.MustMakeRule(types[rule.Lhs], rhs)
+		case idx > 0:
+			return nil, fmt.Errorf("line %d: rule %q only supports \"error\" at the start of its right-hand side", rule.Line, rule.Lhs)
+		case slices.ContainsFunc(rhs[1:], func(t T) bool { return !t.IsTerminal() }):
+			return nil, fmt.Errorf("line %d: rule %q only supports terminals after \"error\"", rule.Line, rule.Lhs)
+		default:
+			rs.MustMakeRuleWithError(types[rule.Lhs], rhs[0], rhs[1:])
+		}
+	}
+
+	return rs, nil
+}
+
+// Import parses the content of a yacc or bison file and converts its grammar into
+// a rule set. See Parse and ToRuleSet.
+//
+// Parameters:
+//   - data: The content of the file.
+//
+// Returns:
+//   - *prx.RuleSet[T]: The rule set. Nil if an error occurred.
+//   - []string: The names of the symbols, by value of the token type. (see
+//     Grammar.Symbols)
+//   - error: An error if the file could not be parsed or converted.
+func Import[T TokenTyper](data []byte) (*prx.RuleSet[T], []string, error) {
+	g, err := Parse(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rs, err := ToRuleSet[T](g)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names, _ := g.Symbols()
+
+	return rs, names, nil
+}
+
+// nullables is a helper function that computes the nullable nonterminals.
+//
+// Returns:
+//   - map[string]bool: The nullable nonterminals. Never returns nil.
+func (g Grammar) nullables() map[string]bool {
+	nullable := make(map[string]bool)
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, rule := range g.Rules {
+			if nullable[rule.Lhs] {
+				continue
+			}
+
+			ok := !slices.ContainsFunc(rule.Rhs, func(s string) bool { return !nullable[s] })
+			if ok {
+				nullable[rule.Lhs] = true
+				changed = true
+			}
+		}
+	}
+
+	return nullable
+}
+
+// eliminate_empty is a helper function that computes the rules of the grammar
+// without the empty rules. See ToRuleSet.
+//
+// Returns:
+//   - []Rule: The rules, in order of the rules they come from.
+//   - error: An error if a rule has too many nullable symbols.
+func (g Grammar) eliminate_empty() ([]Rule, error) {
+	nullable := g.nullables()
+
+	var rules []Rule
+
+	seen := make(map[string]bool)
+
+	for _, rule := range g.Rules {
+		var positions []int
+
+		for i, symbol := range rule.Rhs {
+			if nullable[symbol] {
+				positions = append(positions, i)
+			}
+		}
+
+		if len(positions) > max_nullables {
+			return nil, fmt.Errorf("line %d: rule %q has more than %d nullable symbols", rule.Line, rule.Lhs, max_nullables)
+		}
+
+		for mask := 0; mask < 1<<len(positions); mask++ {
+			rhs := make([]string, 0, len(rule.Rhs))
+
+			for i, symbol := range rule.Rhs {
+				idx := slices.Index(positions, i)
+
+				if idx < 0 || mask&(1<<idx) == 0 {
+					rhs = append(rhs, symbol)
+				}
+			}
+
+			if len(rhs) == 0 || len(rhs) == 1 && rhs[0] == rule.Lhs {
+				continue
+			}
+
+			key := rule.Lhs + ":" + strings.Join(rhs, " ")
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+
+			rules = append(rules, Rule{Lhs: rule.Lhs, Rhs: rhs, Prec: rule.Prec, Line: rule.Line})
+		}
+	}
+
+	// The nonterminals that only derive the empty string have no rule left: the
+	// rules that use them are dropped, as the variants without them are kept.
+	for {
+		defined := make(map[string]bool)
+
+		for _, rule := range rules {
+			defined[rule.Lhs] = true
+		}
+
+		n := len(rules)
+
+		rules = slices.DeleteFunc(rules, func(rule Rule) bool {
+			return slices.ContainsFunc(rule.Rhs, func(s string) bool {
+				return slices.Contains(g.Nonterminals, s) && !defined[s]
+			})
+		})
+
+		if len(rules) == n {
+			return rules, nil
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package yacc
+
+import (
+	"bytes"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token_kind is the kind of a token of a grammar file.
+type token_kind int
+
+const (
+	// kind_ident is an identifier. (e.g., expr or NUMBER)
+	kind_ident token_kind = iota
+
+	// kind_char is a character literal, quotes included. (e.g., '+')
+	kind_char
+
+	// kind_string is a string literal, quotes included. (e.g., "<=")
+	kind_string
+
+	// kind_number is an integer.
+	kind_number
+
+	// kind_directive is a directive, '%' included. (e.g., %token)
+	kind_directive
+
+	// kind_tag is a type tag, brackets included. (e.g., <ival>)
+	kind_tag
+
+	// kind_punct is one of ':', '|', ';', ',' and '='.
+	kind_punct
+
+	// kind_mark is the "%%" separator between the declarations and the rules.
+	kind_mark
+)
+
+// file_token is a token of a grammar file.
+type file_token struct {
+	// kind is the kind of the token.
+	kind token_kind
+
+	// text is the text of the token.
+	text string
+
+	// line is the line of the token, starting from 1.
+	line int
+}
+
+// scanner splits a grammar file into tokens. The C code (the actions, the
+// prologue, the %union, ...), the comments and the named references are skipped.
+type scanner struct {
+	// data is the rest of the file.
+	data []byte
+
+	// line is the current line, starting from 1.
+	line int
+}
+
+// is_ident_rune is a helper function that checks whether a rune can be part of
+// an identifier.
+//
+// Parameters:
+//   - r: The rune.
+//
+// Returns:
+//   - bool: True if r can be part of an identifier, false otherwise.
+func is_ident_rune(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// scan_file is a helper function that splits the content of a grammar file into
+// tokens, up to the second "%%" separator; the epilogue is ignored.
+//
+// Parameters:
+//   - data: The content of the file.
+//
+// Returns:
+//   - []file_token: The tokens.
+//   - error: An error if the content holds an invalid character or an
+//     unterminated literal, comment or block of code.
+func scan_file(data []byte) ([]file_token, error) {
+	s := &scanner{data: data, line: 1}
+
+	var tokens []file_token
+
+	marks := 0
+
+	for len(s.data) > 0 {
+		tk, ok, err := s.next()
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if tk.kind == kind_mark {
+			marks++
+
+			if marks == 2 {
+				break
+			}
+		}
+
+		tokens = append(tokens, tk)
+	}
+
+	return tokens, nil
+}
+
+// advance is a helper function that skips n bytes and counts their newlines.
+//
+// Parameters:
+//   - n: The number of bytes. Assumed to be within the rest of the file.
+func (s *scanner) advance(n int) {
+	s.line += bytes.Count(s.data[:n], []byte{'\n'})
+	s.data = s.data[n:]
+}
+
+// next is a helper function that reads the next token.
+//
+// Returns:
+//   - file_token: The token.
+//   - bool: False if what was read is skipped, true otherwise.
+//   - error: An error if the token is invalid.
+func (s *scanner) next() (file_token, bool, error) {
+	r, size := utf8.DecodeRune(s.data)
+	line := s.line
+
+	make_token := func(kind token_kind, n int) (file_token, bool, error) {
+		tk := file_token{kind: kind, text: string(s.data[:n]), line: line}
+		s.advance(n)
+
+		return tk, true, nil
+	}
+
+	switch {
+	case unicode.IsSpace(r):
+		s.advance(size)
+
+		return file_token{}, false, nil
+	case bytes.HasPrefix(s.data, []byte("//")):
+		end := bytes.IndexByte(s.data, '\n')
+		if end < 0 {
+			end = len(s.data)
+		}
+
+		s.advance(end)
+
+		return file_token{}, false, nil
+	case bytes.HasPrefix(s.data, []byte("/*")):
+		end := bytes.Index(s.data[2:], []byte("*/"))
+		if end < 0 {
+			return file_token{}, false, fmt.Errorf("line %d: unterminated comment", line)
+		}
+
+		s.advance(end + 4)
+
+		return file_token{}, false, nil
+	case bytes.HasPrefix(s.data, []byte("%{")):
+		end := bytes.Index(s.data, []byte("%}"))
+		if end < 0 {
+			return file_token{}, false, fmt.Errorf("line %d: unterminated %%{ block", line)
+		}
+
+		s.advance(end + 2)
+
+		return file_token{}, false, nil
+	case bytes.HasPrefix(s.data, []byte("%%")):
+		return make_token(kind_mark, 2)
+	case r == '{':
+		n, err := s.code_size()
+		if err != nil {
+			return file_token{}, false, err
+		}
+
+		s.advance(n)
+
+		return file_token{}, false, nil
+	case r == '[':
+		// A named reference (e.g., exp[left]).
+		end := bytes.IndexByte(s.data, ']')
+		if end < 0 {
+			return file_token{}, false, fmt.Errorf("line %d: unterminated named reference", line)
+		}
+
+		s.advance(end + 1)
+
+		return file_token{}, false, nil
+	case r == '\'' || r == '"':
+		n, err := literal_size(s.data)
+		if err != nil {
+			return file_token{}, false, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if r == '\'' {
+			return make_token(kind_char, n)
+		}
+
+		return make_token(kind_string, n)
+	case r == '<':
+		end := bytes.IndexByte(s.data, '>')
+		if end < 0 {
+			return file_token{}, false, fmt.Errorf("line %d: unterminated type tag", line)
+		}
+
+		return make_token(kind_tag, end+1)
+	case r == ':' || r == '|' || r == ';' || r == ',' || r == '=':
+		return make_token(kind_punct, 1)
+	case r == '%' || is_ident_rune(r):
+		n := size
+
+		for n < len(s.data) {
+			next, next_size := utf8.DecodeRune(s.data[n:])
+			if !is_ident_rune(next) && (r != '%' || next != '-') {
+				break
+			}
+
+			n += next_size
+		}
+
+		switch {
+		case r == '%' && n == 1:
+			return file_token{}, false, fmt.Errorf("line %d: expected a directive after '%%'", line)
+		case r == '%':
+			return make_token(kind_directive, n)
+		case unicode.IsDigit(r):
+			return make_token(kind_number, n)
+		default:
+			return make_token(kind_ident, n)
+		}
+	default:
+		return file_token{}, false, fmt.Errorf("line %d: unexpected character %q", line, r)
+	}
+}
+
+// code_size is a helper function that returns the size of the block of C code at
+// the start of the rest of the file, braces included. The braces of the literals
+// and of the comments of the code are not counted.
+//
+// Returns:
+//   - int: The size of the block.
+//   - error: An error if the block is not terminated.
+func (s *scanner) code_size() (int, error) {
+	depth := 0
+
+	for i := 0; i < len(s.data); i++ {
+		switch c := s.data[i]; {
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+
+			if depth == 0 {
+				return i + 1, nil
+			}
+		case c == '\'' || c == '"':
+			n, err := literal_size(s.data[i:])
+			if err != nil {
+				// An apostrophe in a comment-less action, such as $<it's>; keep going.
+				continue
+			}
+
+			i += n - 1
+		case bytes.HasPrefix(s.data[i:], []byte("/*")):
+			end := bytes.Index(s.data[i+2:], []byte("*/"))
+			if end < 0 {
+				return 0, fmt.Errorf("line %d: unterminated comment in action", s.line)
+			}
+
+			i += end + 3
+		case bytes.HasPrefix(s.data[i:], []byte("//")):
+			end := bytes.IndexByte(s.data[i:], '\n')
+			if end < 0 {
+				end = len(s.data) - i
+			}
+
+			i += end - 1
+		}
+	}
+
+	return 0, fmt.Errorf("line %d: unterminated action", s.line)
+}
+
+// literal_size is a helper function that returns the size of the character or
+// string literal at the start of data, quotes included.
+//
+// Parameters:
+//   - data: The data. Assumed to start with a quote.
+//
+// Returns:
+//   - int: The size of the literal.
+//   - error: An error if the literal is not terminated on its line.
+func literal_size(data []byte) (int, error) {
+	quote := data[0]
+
+	for i := 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '\n':
+			return 0, fmt.Errorf("unterminated literal %s", data[:i])
+		case quote:
+			return i + 1, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unterminated literal %s", data)
+}
@@ -0,0 +1,374 @@
+// Package yacc imports the grammars of yacc and bison files (.y), so that
+// projects built on goyacc can migrate to the parsers of this module. The
+// declarations, the precedences and the rules are read; the C (or Go) code of the
+// actions, of the prologue and of the epilogue is ignored.
+package yacc
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// Assoc is the associativity of a precedence level.
+type Assoc int
+
+const (
+	// AssocLeft is the associativity of the %left declarations.
+	AssocLeft Assoc = iota
+
+	// AssocRight is the associativity of the %right declarations.
+	AssocRight
+
+	// AssocNone is the associativity of the %nonassoc declarations.
+	AssocNone
+
+	// AssocPrecedence is the associativity of the %precedence declarations of
+	// bison; that is, a precedence without associativity.
+	AssocPrecedence
+)
+
+// String implements the fmt.Stringer interface.
+func (a Assoc) String() string {
+	switch a {
+	case AssocLeft:
+		return "left"
+	case AssocRight:
+		return "right"
+	case AssocNone:
+		return "nonassoc"
+	case AssocPrecedence:
+		return "precedence"
+	default:
+		return "Assoc(" + strconv.Itoa(int(a)) + ")"
+	}
+}
+
+// Level is a precedence level: the tokens of a %left, %right, %nonassoc or
+// %precedence declaration.
+type Level struct {
+	// Assoc is the associativity of the level.
+	Assoc Assoc
+
+	// Tokens are the tokens of the level, in order.
+	Tokens []string
+}
+
+// Rule is a rule of a grammar file.
+type Rule struct {
+	// Lhs is the left-hand side of the rule.
+	Lhs string
+
+	// Rhs is the right-hand side of the rule. Empty for the empty alternatives
+	// (e.g., "%empty" or nothing between ':' and '|').
+	Rhs []string
+
+	// Prec is the token whose precedence the rule has: the one of its %prec
+	// modifier or, as in yacc, the last token of Rhs that has a precedence. Empty
+	// if the rule has no precedence.
+	Prec string
+
+	// Line is the line of the rule, starting from 1.
+	Line int
+}
+
+// Grammar is the grammar of a yacc or bison file.
+//
+// The tokens are named after their identifier or, for the character literals,
+// after the literal itself, quotes included (e.g., "'+'"). The string aliases of
+// bison (e.g., %token LE "<=") are replaced with the token they stand for.
+type Grammar struct {
+	// Tokens are the tokens, in order of declaration. The character literals that
+	// are not declared and the "error" token follow, in order of first use.
+	Tokens []string
+
+	// Nonterminals are the left-hand sides of the rules, in order of appearance.
+	Nonterminals []string
+
+	// Start is the start symbol: the one of the %start declaration or, by
+	// default, the left-hand side of the first rule.
+	Start string
+
+	// Rules are the rules, in order of appearance. Alternatives are split into
+	// separate rules.
+	Rules []Rule
+
+	// Levels are the precedence levels, from the lowest to the highest.
+	Levels []Level
+
+	// aliases are the tokens, by string alias.
+	aliases map[string]string
+}
+
+// Parse parses the content of a yacc or bison file.
+//
+// Parameters:
+//   - data: The content of the file.
+//
+// Returns:
+//   - *Grammar: The grammar. Nil if an error occurred.
+//   - error: An error if the content is not a valid grammar.
+//
+// Unknown directives (e.g., %union, %define or %expect) are ignored, along with
+// their arguments.
+func Parse(data []byte) (*Grammar, error) {
+	tokens, err := scan_file(data)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Grammar{
+		aliases: make(map[string]string),
+	}
+
+	for len(tokens) > 0 && tokens[0].kind != kind_mark {
+		head := tokens[0]
+
+		if head.kind != kind_directive {
+			return nil, fmt.Errorf("line %d: expected a declaration, got %q instead", head.line, head.text)
+		}
+
+		end := 1
+
+		for end < len(tokens) && tokens[end].kind != kind_directive && tokens[end].kind != kind_mark {
+			end++
+		}
+
+		err := g.declare(head, tokens[1:end])
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = tokens[end:]
+	}
+
+	if len(tokens) == 0 {
+		return nil, errors.New("expected '%%' between the declarations and the rules")
+	}
+
+	tokens = tokens[1:]
+
+	for len(tokens) > 0 {
+		tokens, err = g.parse_rule(tokens)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = g.check()
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// declare is a helper function that applies a declaration.
+//
+// Parameters:
+//   - head: The directive of the declaration.
+//   - args: The tokens that follow the directive.
+//
+// Returns:
+//   - error: An error if the declaration is not well-formed.
+func (g *Grammar) declare(head file_token, args []file_token) error {
+	var level *Level
+
+	switch head.text {
+	case "%token":
+	case "%left":
+		level = &Level{Assoc: AssocLeft}
+	case "%right":
+		level = &Level{Assoc: AssocRight}
+	case "%nonassoc":
+		level = &Level{Assoc: AssocNone}
+	case "%precedence":
+		level = &Level{Assoc: AssocPrecedence}
+	case "%start":
+		if len(args) == 0 || args[0].kind != kind_ident {
+			return fmt.Errorf("line %d: expected a symbol after %%start", head.line)
+		}
+
+		g.Start = args[0].text
+
+		return nil
+	default:
+		return nil
+	}
+
+	var last string
+
+	for _, arg := range args {
+		switch arg.kind {
+		case kind_ident, kind_char:
+			last = arg.text
+
+			if !slices.Contains(g.Tokens, last) {
+				g.Tokens = append(g.Tokens, last)
+			}
+
+			if level != nil {
+				level.Tokens = append(level.Tokens, last)
+			}
+		case kind_string:
+			if last == "" {
+				return fmt.Errorf("line %d: alias %s does not follow a token", arg.line, arg.text)
+			}
+
+			g.aliases[arg.text] = last
+		case kind_tag, kind_number, kind_punct:
+			// Types, token numbers and separators are ignored.
+		default:
+			return fmt.Errorf("line %d: unexpected %q in %s", arg.line, arg.text, head.text)
+		}
+	}
+
+	if level != nil {
+		g.Levels = append(g.Levels, *level)
+	}
+
+	return nil
+}
+
+// parse_rule is a helper function that parses the alternatives of a rule. As in
+// yacc, the final ';' is optional.
+//
+// Parameters:
+//   - tokens: The tokens of the rules section, starting with the left-hand side.
+//
+// Returns:
+//   - []file_token: The tokens after the rule.
+//   - error: An error if the rule is not well-formed.
+func (g *Grammar) parse_rule(tokens []file_token) ([]file_token, error) {
+	lhs := tokens[0]
+
+	if lhs.kind != kind_ident || len(tokens) < 2 || tokens[1].text != ":" {
+		return nil, fmt.Errorf("line %d: expected a rule, got %q instead", lhs.line, lhs.text)
+	}
+
+	tokens = tokens[2:]
+
+	if !slices.Contains(g.Nonterminals, lhs.text) {
+		g.Nonterminals = append(g.Nonterminals, lhs.text)
+	}
+
+	rule := Rule{Lhs: lhs.text, Line: lhs.line}
+
+	for {
+		if len(tokens) == 0 || tokens[0].kind == kind_ident && len(tokens) > 1 && tokens[1].text == ":" {
+			g.Rules = append(g.Rules, rule)
+
+			return tokens, nil
+		}
+
+		tk := tokens[0]
+		tokens = tokens[1:]
+
+		switch {
+		case tk.text == "|" || tk.text == ";":
+			g.Rules = append(g.Rules, rule)
+
+			if tk.text == ";" {
+				return tokens, nil
+			}
+
+			rule = Rule{Lhs: lhs.text, Line: tk.line}
+		case tk.kind == kind_ident || tk.kind == kind_char:
+			rule.Rhs = append(rule.Rhs, tk.text)
+		case tk.kind == kind_string:
+			name, ok := g.aliases[tk.text]
+			if !ok {
+				return nil, fmt.Errorf("line %d: alias %s of rule %q is not declared", tk.line, tk.text, lhs.text)
+			}
+
+			rule.Rhs = append(rule.Rhs, name)
+		case tk.text == "%empty":
+		case tk.text == "%prec":
+			if len(tokens) == 0 || tokens[0].kind != kind_ident && tokens[0].kind != kind_char {
+				return nil, fmt.Errorf("line %d: expected a token after %%prec", tk.line)
+			}
+
+			rule.Prec = tokens[0].text
+			tokens = tokens[1:]
+		default:
+			return nil, fmt.Errorf("line %d: unexpected %q in rule %q", tk.line, tk.text, lhs.text)
+		}
+	}
+}
+
+// check is a helper function that declares the character literals and the error
+// token, checks that every symbol is either a token or a nonterminal, sets the
+// start symbol and the precedences of the rules.
+//
+// Returns:
+//   - error: An error if a symbol is undefined or defined twice, or if there is
+//     no rule.
+func (g *Grammar) check() error {
+	if len(g.Rules) == 0 {
+		return errors.New("the grammar has no rule")
+	}
+
+	var errs []error
+
+	for _, nt := range g.Nonterminals {
+		if slices.Contains(g.Tokens, nt) {
+			errs = append(errs, fmt.Errorf("%q is both a token and a nonterminal", nt))
+		}
+	}
+
+	for i := range g.Rules {
+		rule := &g.Rules[i]
+
+		for _, symbol := range rule.Rhs {
+			switch {
+			case slices.Contains(g.Tokens, symbol), slices.Contains(g.Nonterminals, symbol):
+			case symbol == "error" || symbol[0] == '\'':
+				g.Tokens = append(g.Tokens, symbol)
+			default:
+				errs = append(errs, fmt.Errorf("line %d: symbol %q of rule %q is undefined", rule.Line, symbol, rule.Lhs))
+			}
+		}
+
+		if rule.Prec != "" {
+			if _, ok := g.level_of(rule.Prec); !ok {
+				errs = append(errs, fmt.Errorf("line %d: %%prec %q of rule %q has no precedence", rule.Line, rule.Prec, rule.Lhs))
+			}
+
+			continue
+		}
+
+		for _, symbol := range slices.Backward(rule.Rhs) {
+			if _, ok := g.level_of(symbol); ok {
+				rule.Prec = symbol
+				break
+			}
+		}
+	}
+
+	if g.Start == "" {
+		g.Start = g.Rules[0].Lhs
+	} else if !slices.Contains(g.Nonterminals, g.Start) {
+		errs = append(errs, fmt.Errorf("start symbol %q is not a nonterminal", g.Start))
+	}
+
+	return errors.Join(errs...)
+}
+
+// level_of is a helper function that returns the precedence level of a token.
+//
+// Parameters:
+//   - token: The token.
+//
+// Returns:
+//   - int: The index of the level in Levels.
+//   - bool: True if the token has a precedence, false otherwise.
+func (g Grammar) level_of(token string) (int, bool) {
+	for i, level := range g.Levels {
+		if slices.Contains(level.Tokens, token) {
+			return i, true
+		}
+	}
+
+	return -1, false
+}
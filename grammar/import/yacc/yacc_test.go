@@ -0,0 +1,255 @@
+package yacc
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/PREV/grammar"
+	prx "github.com/PlayerR9/grammar/PREV/parser"
+)
+
+// test_calc is a goyacc grammar of a calculator.
+const test_calc = `%{
+package calc
+
+import "fmt"
+%}
+
+%union {
+	num int
+}
+
+%token <num> NUM
+%token LE "<="
+
+%left '+' '-'
+%left '*' '/'
+%right UMINUS
+
+%start input
+
+%%
+
+input
+	: /* empty */
+	| input line
+	;
+
+line : '\n' | expr '\n' { fmt.Println($1) }
+     | error '\n'       { yyerrok }
+
+expr
+	: NUM
+	| expr '+' expr     { $$ = $1 + $3 }
+	| expr '-' expr     { $$ = $1 - $3 }
+	| expr '*' expr     { $$ = $1 * $3 }
+	| expr '/' expr     { if $3 == 0 { yylex.Error("division by zero") } else { $$ = $1 / $3 } }
+	| expr "<=" expr
+	| '-' expr %prec UMINUS
+	| '(' expr ')'      { $$ = $2 /* } */ }
+	;
+
+%%
+
+func main() {}
+`
+
+// test_symbol is the token type of the imported rule sets: the first
+// test_terminals symbols are terminals.
+type test_symbol int
+
+// test_terminals is the number of terminals of test_symbol.
+var test_terminals int
+
+// String implements the fmt.Stringer interface.
+func (s test_symbol) String() string {
+	return "S" + strconv.Itoa(int(s))
+}
+
+// IsTerminal implements the TokenTyper interface.
+func (s test_symbol) IsTerminal() bool {
+	return int(s) < test_terminals
+}
+
+func TestParse(t *testing.T) {
+	g, err := Parse([]byte(test_calc))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want_tokens := []string{"NUM", "LE", "'+'", "'-'", "'*'", "'/'", "UMINUS", "'\\n'", "error", "'('", "')'"}
+	if !slices.Equal(g.Tokens, want_tokens) {
+		t.Errorf("expected tokens %q, got %q", want_tokens, g.Tokens)
+	}
+
+	want_nts := []string{"input", "line", "expr"}
+	if !slices.Equal(g.Nonterminals, want_nts) {
+		t.Errorf("expected nonterminals %q, got %q", want_nts, g.Nonterminals)
+	}
+
+	if g.Start != "input" {
+		t.Errorf("expected start %q, got %q", "input", g.Start)
+	}
+
+	if len(g.Rules) != 13 {
+		t.Fatalf("expected 13 rules, got %d", len(g.Rules))
+	}
+
+	if len(g.Rules[0].Rhs) != 0 {
+		t.Errorf("expected the first rule to be empty, got %q", g.Rules[0].Rhs)
+	}
+
+	tests := []struct {
+		idx  int
+		rhs  []string
+		prec string
+	}{
+		{6, []string{"expr", "'+'", "expr"}, "'+'"},
+		{10, []string{"expr", "LE", "expr"}, ""},
+		{11, []string{"'-'", "expr"}, "UMINUS"},
+		{12, []string{"'('", "expr", "')'"}, ""},
+	}
+
+	for _, tt := range tests {
+		rule := g.Rules[tt.idx]
+
+		if !slices.Equal(rule.Rhs, tt.rhs) || rule.Prec != tt.prec {
+			t.Errorf("rule %d: expected %q %%prec %q, got %q %%prec %q", tt.idx, tt.rhs, tt.prec, rule.Rhs, rule.Prec)
+		}
+	}
+
+	if len(g.Levels) != 3 || g.Levels[2].Assoc != AssocRight || g.Levels[0].Tokens[1] != "'-'" {
+		t.Errorf("unexpected levels %v", g.Levels)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"no separator", "%token A\n"},
+		{"undefined", "%%\na : B ;\n"},
+		{"no rule", "%token A\n%%\n"},
+		{"bad prec", "%token A\n%%\na : A %prec A ;\n"},
+		{"unterminated action", "%%\na : 'x' { oops ;\n"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.data))
+		if err == nil {
+			t.Errorf("%s: expected an error, got nil", tt.name)
+		}
+	}
+}
+
+func TestToRuleSet(t *testing.T) {
+	g, err := Parse([]byte(test_calc))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	names, terminals := g.Symbols()
+	test_terminals = terminals
+
+	rs, err := ToRuleSet[test_symbol](g)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var rules []string
+
+	for rule := range rs.Rules() {
+		text := names[rule.Lhs()] + ":"
+
+		for symbol := range rule.Rhs() {
+			text += " " + names[symbol]
+		}
+
+		rules = append(rules, text)
+	}
+
+	// input is nullable: its empty rule is dropped, the empty input is accepted by
+	// "$accept: $end" and "input line" gets a variant without input.
+	want := []string{
+		"$accept: input $end",
+		"$accept: $end",
+		"input: input line",
+		"input: line",
+	}
+
+	if !slices.Equal(rules[:len(want)], want) {
+		t.Errorf("expected the rules to start with %q, got %q", want, rules[:len(want)])
+	}
+
+	if slices.Contains(rules, "line: error '\\n'") {
+		t.Errorf("expected the error rule to be an error production")
+	}
+
+	_, err = prx.NewLALRTable(rs)
+
+	var conflicts *prx.ErrConflicts[test_symbol]
+
+	if !errors.As(err, &conflicts) {
+		t.Errorf("expected the ambiguous expressions to conflict, got %v", err)
+	}
+}
+
+func TestToRuleSetEmptyInput(t *testing.T) {
+	g, err := Parse([]byte("%token X\n%%\ninput : /* empty */ | input X ;\n"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	names, terminals := g.Symbols()
+	test_terminals = terminals
+
+	rs, err := ToRuleSet[test_symbol](g)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	pt, err := prx.NewLALRTable(rs)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		input []test_symbol
+		want  string
+	}{
+		{nil, "$accept: $end"},
+		{[]test_symbol{1, 1}, "$accept: input $end"},
+	}
+
+	for _, tt := range tests {
+		tokens := make([]*gr.Token[test_symbol], 0, len(tt.input)+1)
+
+		for _, symbol := range tt.input {
+			tokens = append(tokens, gr.NewToken(symbol, "x", nil))
+		}
+
+		tokens = append(tokens, gr.NewToken(test_symbol(0), "", nil))
+
+		res := pt.ParseResult(tokens)
+		if res.Err != nil {
+			t.Errorf("%d tokens: expected no error, got %v", len(tt.input), res.Err)
+
+			continue
+		}
+
+		root := res.Forest[0].Root()
+
+		got := names[root.Type] + ":"
+
+		for c := root.FirstChild; c != nil; c = c.NextSibling {
+			got += " " + names[c.Type]
+		}
+
+		if got != tt.want {
+			t.Errorf("%d tokens: expected %q, got %q", len(tt.input), tt.want, got)
+		}
+	}
+}
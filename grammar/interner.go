@@ -0,0 +1,87 @@
+package grammar
+
+import "sync"
+
+// Interner makes equal strings share the same memory. Sharing an interner between
+// the files of a project keeps a single copy of each identifier, keyword, ...
+// however many times it occurs. It is safe for concurrent use.
+type Interner struct {
+	// mu protects table.
+	mu sync.Mutex
+
+	// table maps each string to its interned copy.
+	table map[string]string
+}
+
+// NewInterner creates a new, empty, interner.
+//
+// Returns:
+//   - *Interner: The new interner. Never returns nil.
+func NewInterner() *Interner {
+	return &Interner{
+		table: make(map[string]string),
+	}
+}
+
+// Intern returns the interned copy of the given string.
+//
+// Parameters:
+//   - s: The string to intern.
+//
+// Returns:
+//   - string: The interned copy. Equal strings always get the same copy.
+func (in *Interner) Intern(s string) string {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if in.table == nil {
+		in.table = make(map[string]string)
+	}
+
+	interned, ok := in.table[s]
+	if !ok {
+		in.table[s] = s
+		interned = s
+	}
+
+	return interned
+}
+
+// Len returns the number of distinct strings that were interned.
+//
+// Returns:
+//   - int: The number of interned strings.
+func (in *Interner) Len() int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	return len(in.table)
+}
+
+// InternTokens replaces the data of the given tokens, and of their children, by
+// their interned copies.
+//
+// Parameters:
+//   - in: The interner.
+//   - tokens: The tokens.
+func InternTokens[T Enumer](in *Interner, tokens []*Token[T]) {
+	if in == nil {
+		return
+	}
+
+	stack := make([]*Token[T], 0, len(tokens))
+	stack = append(stack, tokens...)
+
+	for len(stack) > 0 {
+		tk := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if tk == nil {
+			continue
+		}
+
+		tk.Data = in.Intern(tk.Data)
+
+		stack = append(stack, tk.Children...)
+	}
+}
@@ -0,0 +1,244 @@
+package grammar
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// ForestVersion is the version of the format written by ForestToJSON. It is
+// increased whenever the format changes in a way that older releases cannot read.
+const ForestVersion int = 1
+
+// json_forest is the JSON form of a forest.
+type json_forest struct {
+	// Version is the version of the format.
+	Version int `json:"version"`
+
+	// Trees are the trees of the forest.
+	Trees []*json_token `json:"forest"`
+}
+
+// json_token is the JSON form of a token.
+type json_token struct {
+	// Type is the name of the type of the token.
+	Type string `json:"type"`
+
+	// Data is the data of the token.
+	Data string `json:"data,omitempty"`
+
+	// Pos is the offset, in bytes, of the token in the input stream.
+	Pos int `json:"pos"`
+
+	// Start is the start of the span of the token.
+	Start int `json:"start"`
+
+	// End is the end of the span of the token.
+	End int `json:"end"`
+
+	// Children are the children of the token.
+	Children []*json_token `json:"children,omitempty"`
+}
+
+// json_of is a helper function that converts a parse tree to its JSON form.
+//
+// Parameters:
+//   - root: The root of the parse tree. Assumed to be non-nil.
+//
+// Returns:
+//   - *json_token: The JSON form of the root. Never returns nil.
+func json_of[T Enumer](root *Token[T]) *json_token {
+	type pair struct {
+		tk *Token[T]
+		jt *json_token
+	}
+
+	top := &json_token{}
+
+	stack := []pair{{tk: root, jt: top}}
+
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		span := p.tk.Span()
+
+		p.jt.Type = p.tk.Type.String()
+		p.jt.Data = p.tk.Data
+		p.jt.Pos = p.tk.Pos
+		p.jt.Start = span.Start
+		p.jt.End = span.End
+
+		for _, child := range p.tk.Children {
+			if child == nil {
+				continue
+			}
+
+			jt := &json_token{}
+
+			p.jt.Children = append(p.jt.Children, jt)
+			stack = append(stack, pair{tk: child, jt: jt})
+		}
+	}
+
+	return top
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+//
+// Format:
+//
+//	{"type": "<type>", "data": "<data>", "pos": <pos>, "start": <start>, "end": <end>, "children": [...]}
+//
+// The data and the children are omitted when empty; start and end are the bounds
+// of the span of the token.
+func (tk *Token[T]) MarshalJSON() ([]byte, error) {
+	if tk == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(json_of(tk))
+}
+
+// ForestToJSON encodes a forest as JSON, along with the version of the format.
+//
+// Format:
+//
+//	{"version": <ForestVersion>, "forest": [<token>, ...]}
+//
+// The tokens are encoded as by Token.MarshalJSON.
+//
+// Parameters:
+//   - forest: The forest. Nil trees are encoded as null.
+//
+// Returns:
+//   - []byte: The JSON encoding of the forest.
+//   - error: An error if the forest could not be encoded.
+func ForestToJSON[T Enumer](forest []*Token[T]) ([]byte, error) {
+	trees := make([]*json_token, 0, len(forest))
+
+	for _, root := range forest {
+		if root == nil {
+			trees = append(trees, nil)
+		} else {
+			trees = append(trees, json_of(root))
+		}
+	}
+
+	return json.Marshal(json_forest{
+		Version: ForestVersion,
+		Trees:   trees,
+	})
+}
+
+// ForestFromJSON decodes a forest encoded by ForestToJSON. The lookaheads of the
+// leaves are linked in the order of the forest, and every non-terminal token gets
+// the lookahead of its last child.
+//
+// Parameters:
+//   - data: The JSON encoding of the forest.
+//   - types: The token types that can appear in the forest. They are recognized by
+//     their name.
+//
+// Returns:
+//   - []*Token[T]: The forest.
+//   - error: An error if the data is not a valid encoding, if it was written in a
+//     version of the format that cannot be read (of type *ErrUnsupportedVersion)
+//     or if a token has a type that is not among types.
+func ForestFromJSON[T Enumer](data []byte, types ...T) ([]*Token[T], error) {
+	var encoded json_forest
+
+	err := json.Unmarshal(data, &encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	err = CheckVersion("forest", encoded.Version, 1, ForestVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	trees := encoded.Trees
+
+	table := make(map[string]T, len(types))
+
+	for _, type_ := range types {
+		table[type_.String()] = type_
+	}
+
+	type pair struct {
+		jt *json_token
+		tk *Token[T]
+	}
+
+	forest := make([]*Token[T], 0, len(trees))
+
+	var order []pair
+
+	for _, root := range trees {
+		if root == nil {
+			forest = append(forest, nil)
+			continue
+		}
+
+		top := &Token[T]{}
+		forest = append(forest, top)
+
+		stack := []pair{{jt: root, tk: top}}
+
+		for len(stack) > 0 {
+			p := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			order = append(order, p)
+
+			type_, ok := table[p.jt.Type]
+			if !ok {
+				return nil, fmt.Errorf("token at %d: unknown token type %q", p.jt.Pos, p.jt.Type)
+			}
+
+			p.tk.Type = type_
+			p.tk.Data = p.jt.Data
+			p.tk.Pos = p.jt.Pos
+
+			if len(p.jt.Children) == 0 {
+				continue
+			}
+
+			p.tk.Children = make([]*Token[T], 0, len(p.jt.Children))
+
+			for _, child := range p.jt.Children {
+				if child == nil {
+					continue
+				}
+
+				tk := &Token[T]{}
+
+				p.tk.Children = append(p.tk.Children, tk)
+				stack = append(stack, pair{jt: child, tk: tk})
+			}
+
+			// Children are visited in order, so that the leaves are met in the
+			// order of the input.
+			slices.Reverse(stack[len(stack)-len(p.tk.Children):])
+		}
+	}
+
+	var leaves []*Token[T]
+
+	for _, p := range order {
+		if len(p.tk.Children) == 0 {
+			leaves = append(leaves, p.tk)
+		}
+	}
+
+	LinkLookaheads(leaves)
+
+	for _, p := range slices.Backward(order) {
+		if len(p.tk.Children) > 0 {
+			p.tk.Lookahead = p.tk.Children[len(p.tk.Children)-1].Lookahead
+		}
+	}
+
+	return forest, nil
+}
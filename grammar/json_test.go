@@ -0,0 +1,117 @@
+package grammar
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type json_type int
+
+const (
+	jt_eof json_type = iota
+	jt_word
+	jt_list
+	jt_source
+)
+
+func (t json_type) String() string {
+	return [...]string{"EOF", "Word", "List", "Source"}[t]
+}
+
+// new_json_forest is a helper function that makes the forest of "ab cd" and of
+// a lone word at 9.
+func new_json_forest(t *testing.T) []*Token[json_type] {
+	leaf := func(type_ json_type, data string, pos int) *Token[json_type] {
+		tk := NewTerminalToken(type_, data)
+		tk.Pos = pos
+
+		return tk
+	}
+
+	ab, cd, eof := leaf(jt_word, "ab", 0), leaf(jt_word, "cd", 3), leaf(jt_eof, "", 5)
+	LinkLookaheads([]*Token[json_type]{ab, cd, eof})
+
+	list, err := NewToken(jt_list, "", []*Token[json_type]{ab, cd})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	source, err := NewToken(jt_source, "", []*Token[json_type]{list, eof})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	return []*Token[json_type]{source, leaf(jt_word, "ef", 9)}
+}
+
+func TestForestJSONRoundTrip(t *testing.T) {
+	forest := new_json_forest(t)
+
+	data, err := ForestToJSON(forest)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	const list_json = `{"type":"List","pos":0,"start":0,"end":5,"children":[`
+
+	if !bytes.Contains(data, []byte(list_json)) {
+		t.Fatalf("expected the encoding to hold %s, got %s", list_json, data)
+	}
+
+	got, err := ForestFromJSON(data, jt_eof, jt_word, jt_list, jt_source)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	again, err := ForestToJSON(got)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !bytes.Equal(again, data) {
+		t.Fatalf("expected the encoding %s, got %s", data, again)
+	}
+
+	list := got[0].Children[0]
+
+	if span := list.Span(); span != (Span{Start: 0, End: 5}) {
+		t.Errorf("expected the list to span %v, got %v", Span{Start: 0, End: 5}, span)
+	}
+
+	ab, cd := list.Children[0], list.Children[1]
+
+	if ab.Lookahead != cd || cd.Lookahead == nil || cd.Lookahead.Type != jt_eof {
+		t.Errorf("expected the lookaheads of the leaves to be linked in order")
+	}
+
+	if cd.Lookahead.Lookahead != got[1] {
+		t.Errorf("expected the lookahead of EOF to be the next tree")
+	}
+}
+
+func TestForestFromJSONVersion(t *testing.T) {
+	tests := []struct {
+		data    string
+		version bool
+	}{
+		{`[{"type":"Word","data":"ab","pos":0}]`, false},
+		{`{"version":0,"forest":[]}`, true},
+		{`{"version":2,"forest":[]}`, true},
+	}
+
+	for _, test := range tests {
+		_, err := ForestFromJSON([]byte(test.data), jt_word)
+		if err == nil {
+			t.Errorf("%s: expected an error, got none", test.data)
+
+			continue
+		}
+
+		var version_err *ErrUnsupportedVersion
+
+		if got := errors.As(err, &version_err); got != test.version {
+			t.Errorf("%s: expected an *ErrUnsupportedVersion to be %t, got %v", test.data, test.version, err)
+		}
+	}
+}
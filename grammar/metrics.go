@@ -0,0 +1,69 @@
+package grammar
+
+import "time"
+
+// Operation is an operation that is reported to the metrics.
+type Operation string
+
+const (
+	// OpLex is the lexing of an input stream.
+	OpLex Operation = "lex"
+
+	// OpParse is the parsing of a token stream.
+	OpParse Operation = "parse"
+)
+
+// Metrics receives the measurements of the lexers and the parsers so that services
+// can export them (to Prometheus, for instance) without wrapping every call.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// IncTotal increments the counter of the operations that were run.
+	//
+	// Parameters:
+	//   - op: The operation.
+	IncTotal(op Operation)
+
+	// IncErrors increments the counter of the operations that failed.
+	//
+	// Parameters:
+	//   - op: The operation.
+	IncErrors(op Operation)
+
+	// ObserveDuration adds the duration of an operation to its histogram.
+	//
+	// Parameters:
+	//   - op: The operation.
+	//   - d: The duration of the operation.
+	ObserveDuration(op Operation, d time.Duration)
+
+	// ObserveTokens adds the number of tokens that an operation produced (when
+	// lexing) or consumed (when parsing) to its histogram.
+	//
+	// Parameters:
+	//   - op: The operation.
+	//   - n: The number of tokens.
+	ObserveTokens(op Operation, n int)
+}
+
+// Record reports an operation that started at the given time to the metrics.
+//
+// Parameters:
+//   - m: The metrics. If nil, nothing is reported.
+//   - op: The operation.
+//   - start: The time at which the operation started.
+//   - tokens: The number of tokens of the operation.
+//   - err: The error of the operation. Nil if it succeeded.
+func Record(m Metrics, op Operation, start time.Time, tokens int, err error) {
+	if m == nil {
+		return
+	}
+
+	m.IncTotal(op)
+
+	if err != nil {
+		m.IncErrors(op)
+	}
+
+	m.ObserveDuration(op, time.Since(start))
+	m.ObserveTokens(op, tokens)
+}
@@ -0,0 +1,68 @@
+package grammar
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrPanic is the error that occurs when a user-supplied callback panics.
+type ErrPanic struct {
+	// Value is the value the callback panicked with.
+	Value any
+
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+// Error implements the error interface.
+//
+// Message: "callback panicked: <value>"
+func (e ErrPanic) Error() string {
+	return fmt.Sprintf("callback panicked: %v", e.Value)
+}
+
+// Unwrap returns the value the callback panicked with if it is an error.
+//
+// Returns:
+//   - error: The value as an error. Nil if the value is not an error.
+func (e ErrPanic) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// NewErrPanic creates a new ErrPanic error.
+//
+// Parameters:
+//   - value: The value the callback panicked with.
+//   - stack: The stack trace at the time of the panic.
+//
+// Returns:
+//   - *ErrPanic: The new error. Never returns nil.
+func NewErrPanic(value any, stack []byte) *ErrPanic {
+	return &ErrPanic{
+		Value: value,
+		Stack: stack,
+	}
+}
+
+// Recover converts a panic into an *ErrPanic error. It must be deferred directly
+// by the function that calls the user-supplied callback.
+//
+// Parameters:
+//   - err: The error of the calling function. Overwritten iff a panic occurred.
+//
+// Example:
+//
+//	func call(fn func() error) (err error) {
+//		defer grammar.Recover(&err)
+//
+//		return fn()
+//	}
+func Recover(err *error) {
+	r := recover()
+	if r == nil || err == nil {
+		return
+	}
+
+	*err = NewErrPanic(r, debug.Stack())
+}
@@ -0,0 +1,199 @@
+package grammar
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"unicode"
+	"unicode/utf8"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+)
+
+// TypeRenamer rewrites token type names from one version of a grammar to another.
+// It works on any textual artifact that refers to token types by name (serialized
+// token streams, golden trees, saved tables, ...) so that test corpora can follow
+// the evolution of the grammar.
+//
+// Only whole identifiers are renamed: with the mapping "ID" -> "IDENT", the text
+// "ID VALID_ID" becomes "IDENT VALID_ID". Identifiers inside string literals,
+// quoted with '"' or '`', are data and are left untouched. All renames are applied
+// simultaneously, so swapping two names is allowed.
+type TypeRenamer struct {
+	// mapping maps the old names to the new ones.
+	mapping map[string]string
+}
+
+// NewTypeRenamer creates a new type renamer.
+//
+// Parameters:
+//   - mapping: The mapping of old names to new names.
+//
+// Returns:
+//   - *TypeRenamer: The new type renamer.
+//   - error: An error of type *errors.ErrInvalidParameter if a name is not a valid identifier.
+func NewTypeRenamer(mapping map[string]string) (*TypeRenamer, error) {
+	table := make(map[string]string, len(mapping))
+
+	for old_name, new_name := range mapping {
+		if !is_identifier(old_name) {
+			return nil, gcers.NewErrInvalidParameter("mapping", fmt.Errorf("%q is not a valid type name", old_name))
+		} else if !is_identifier(new_name) {
+			return nil, gcers.NewErrInvalidParameter("mapping", fmt.Errorf("%q is not a valid type name", new_name))
+		}
+
+		if old_name != new_name {
+			table[old_name] = new_name
+		}
+	}
+
+	return &TypeRenamer{
+		mapping: table,
+	}, nil
+}
+
+// Rename returns the new name of the given type name.
+//
+// Parameters:
+//   - name: The old name.
+//
+// Returns:
+//   - string: The new name. The name itself if it is not renamed.
+func (r TypeRenamer) Rename(name string) string {
+	new_name, ok := r.mapping[name]
+	if !ok {
+		return name
+	}
+
+	return new_name
+}
+
+// RenameBytes renames every type name that occurs in the given data, outside of
+// its string literals.
+//
+// Parameters:
+//   - data: The data to rewrite.
+//
+// Returns:
+//   - []byte: The rewritten data. Never shares memory with data.
+func (r TypeRenamer) RenameBytes(data []byte) []byte {
+	var buffer bytes.Buffer
+
+	buffer.Grow(len(data))
+
+	for len(data) > 0 {
+		c, size := utf8.DecodeRune(data)
+
+		if c == '"' || c == '`' {
+			end := literal_end(data, c)
+
+			buffer.Write(data[:end])
+			data = data[end:]
+
+			continue
+		}
+
+		if !is_identifier_rune(c) {
+			buffer.Write(data[:size])
+			data = data[size:]
+
+			continue
+		}
+
+		end := size
+
+		for end < len(data) {
+			c, size := utf8.DecodeRune(data[end:])
+			if !is_identifier_rune(c) {
+				break
+			}
+
+			end += size
+		}
+
+		buffer.WriteString(r.Rename(string(data[:end])))
+		data = data[end:]
+	}
+
+	return buffer.Bytes()
+}
+
+// Migrate reads all the data from the reader, renames every type name in it and
+// writes the result to the writer.
+//
+// Parameters:
+//   - w: The writer to write the migrated data to.
+//   - rd: The reader to read the data from.
+//
+// Returns:
+//   - error: An error if the reading or the writing failed.
+func (r TypeRenamer) Migrate(w io.Writer, rd io.Reader) error {
+	if w == nil {
+		return gcers.NewErrNilParameter("w")
+	} else if rd == nil {
+		return gcers.NewErrNilParameter("rd")
+	}
+
+	data, err := io.ReadAll(rd)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(r.RenameBytes(data))
+	return err
+}
+
+// literal_end is a helper function that finds the end of the string literal at
+// the start of the data. Backslashes escape the next character of '"' literals.
+// An unterminated literal ends with the data.
+//
+// Parameters:
+//   - data: The data, starting with the opening quote.
+//   - quote: The quote of the literal.
+//
+// Returns:
+//   - int: The length of the literal, quotes included.
+func literal_end(data []byte, quote rune) int {
+	for i := 1; i < len(data); i++ {
+		switch {
+		case data[i] == '\\' && quote == '"':
+			i++
+		case rune(data[i]) == quote:
+			return i + 1
+		}
+	}
+
+	return len(data)
+}
+
+// is_identifier_rune checks whether the given rune can be part of a type name.
+//
+// Parameters:
+//   - c: The rune to check.
+//
+// Returns:
+//   - bool: True if the rune can be part of a type name, false otherwise.
+func is_identifier_rune(c rune) bool {
+	return c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+// is_identifier checks whether the given string is a valid type name.
+//
+// Parameters:
+//   - name: The name to check.
+//
+// Returns:
+//   - bool: True if the name is a valid type name, false otherwise.
+func is_identifier(name string) bool {
+	if name == "" || !utf8.ValidString(name) {
+		return false
+	}
+
+	for _, c := range name {
+		if !is_identifier_rune(c) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,30 @@
+package grammar
+
+import (
+	"testing"
+)
+
+func TestRenameBytes(t *testing.T) {
+	r, err := NewTypeRenamer(map[string]string{"ID": "IDENT", "NUM": "ID"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"ID VALID_ID NUM", "IDENT VALID_ID ID"},
+		{`ID "ID" NUM`, `IDENT "ID" ID`},
+		{`ID "say \"ID\"" ID`, `IDENT "say \"ID\"" IDENT`},
+		{"ID `NUM` NUM", "IDENT `NUM` ID"},
+		{`ID "ID`, `IDENT "ID`},
+	}
+
+	for _, test := range tests {
+		got := string(r.RenameBytes([]byte(test.input)))
+		if got != test.want {
+			t.Errorf("input %q: expected %q, got %q", test.input, test.want, got)
+		}
+	}
+}
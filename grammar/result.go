@@ -0,0 +1,191 @@
+package grammar
+
+import "slices"
+
+// Result is the outcome of a parse. Every parsing engine of the module returns it
+// so that callers handle successes and failures the same way.
+type Result[N any] struct {
+	// Forest is the forest that was parsed. On success, it holds exactly one tree;
+	// on failure, it holds whatever could be built before the failure.
+	Forest []N
+
+	// Diagnostics are the non-fatal problems found while parsing, such as warnings
+	// or the failures of the alternatives that were discarded.
+	Diagnostics []error
+
+	// Err is the reason why the parse failed. Nil on success.
+	Err error
+
+	// ErrPos is the offset, in bytes, at which the parse failed. -1 on success or
+	// if the position is unknown.
+	ErrPos int
+
+	// frozen is true if the result must not be modified anymore. See Freeze.
+	frozen bool
+}
+
+// NewResult creates a new successful result.
+//
+// Parameters:
+//   - root: The root of the parse tree.
+//   - diagnostics: The non-fatal problems found while parsing.
+//
+// Returns:
+//   - Result[N]: The new result.
+func NewResult[N any](root N, diagnostics ...error) Result[N] {
+	return Result[N]{
+		Forest:      []N{root},
+		Diagnostics: diagnostics,
+		ErrPos:      -1,
+	}
+}
+
+// NewFailedResult creates a new failed result.
+//
+// Parameters:
+//   - forest: The partial forest built before the failure.
+//   - err: The reason of the failure.
+//   - diagnostics: The non-fatal problems found while parsing.
+//
+// Returns:
+//   - Result[N]: The new result.
+func NewFailedResult[N any](forest []N, err error, diagnostics ...error) Result[N] {
+	return Result[N]{
+		Forest:      forest,
+		Diagnostics: diagnostics,
+		Err:         err,
+		ErrPos:      -1,
+	}
+}
+
+// At sets the position at which the parse failed.
+//
+// Parameters:
+//   - pos: The offset, in bytes, of the failure. -1 if unknown.
+//
+// Returns:
+//   - Result[N]: The result with the new position.
+func (r Result[N]) At(pos int) Result[N] {
+	if pos < 0 {
+		pos = -1
+	}
+
+	r.ErrPos = pos
+
+	return r
+}
+
+// IsOK checks whether the parse succeeded.
+//
+// Returns:
+//   - bool: True if the parse succeeded, false otherwise.
+func (r Result[N]) IsOK() bool {
+	return r.Err == nil && len(r.Forest) == 1
+}
+
+// Root returns the root of the parse tree.
+//
+// Returns:
+//   - N: The root of the parse tree.
+//   - bool: True if the parse succeeded, false otherwise.
+func (r Result[N]) Root() (N, bool) {
+	if !r.IsOK() {
+		return *new(N), false
+	}
+
+	return r.Forest[0], true
+}
+
+// Unpack returns the fields of the result.
+//
+// Returns:
+//   - []N: The forest.
+//   - []error: The diagnostics.
+//   - error: The reason of the failure. Nil on success.
+func (r Result[N]) Unpack() ([]N, []error, error) {
+	return r.Forest, r.Diagnostics, r.Err
+}
+
+// PartialForest returns the largest well-formed prefix of the forest that was
+// built before the failure, along with the position of the failure. Editors can
+// use it to keep outlines and highlighting working on invalid inputs.
+//
+// Returns:
+//   - []N: The partial forest. On success, the forest itself.
+//   - int: The offset, in bytes, of the failure. -1 on success or if unknown.
+func (r Result[N]) PartialForest() ([]N, int) {
+	if r.IsOK() {
+		return r.Forest, -1
+	}
+
+	return r.Forest, r.ErrPos
+}
+
+// Cloner is implemented by the nodes of a forest that can be deep copied, such as
+// *Token.
+type Cloner[N any] interface {
+	// Clone returns a deep copy of the node.
+	//
+	// Returns:
+	//   - N: The copy of the node.
+	Clone() N
+}
+
+// Freeze marks the result as immutable so that it can be shared by several
+// goroutines without copying it; since none of them modifies it, no
+// synchronization is needed to read it.
+//
+// Returns:
+//   - Result[N]: The frozen result. It shares its forest with the receiver.
+//
+// Freezing is a promise, not an enforcement: nothing prevents the nodes from being
+// modified, but a consumer that needs to do so must work on a Clone. The forest
+// and the diagnostics are clipped so that appending to them never writes to the
+// shared memory.
+func (r Result[N]) Freeze() Result[N] {
+	r.Forest = slices.Clip(r.Forest)
+	r.Diagnostics = slices.Clip(r.Diagnostics)
+	r.frozen = true
+
+	return r
+}
+
+// IsFrozen checks whether the result was frozen.
+//
+// Returns:
+//   - bool: True if the result is frozen, false otherwise.
+func (r Result[N]) IsFrozen() bool {
+	return r.frozen
+}
+
+// Clone returns a copy of the result that can be modified without affecting the
+// receiver, even if the latter is frozen. Nodes that implement Cloner are deep
+// copied; the other ones are copied as is.
+//
+// Returns:
+//   - Result[N]: The copy of the result. It is not frozen.
+//
+// Trees are copied independently; so, with *Token nodes, a lookahead that leads
+// from one tree to another one leads to a copy of its own instead.
+func (r Result[N]) Clone() Result[N] {
+	var forest []N
+
+	if r.Forest != nil {
+		forest = make([]N, 0, len(r.Forest))
+
+		for _, node := range r.Forest {
+			if cloner, ok := any(node).(Cloner[N]); ok {
+				node = cloner.Clone()
+			}
+
+			forest = append(forest, node)
+		}
+	}
+
+	return Result[N]{
+		Forest:      forest,
+		Diagnostics: slices.Clone(r.Diagnostics),
+		Err:         r.Err,
+		ErrPos:      r.ErrPos,
+	}
+}
@@ -0,0 +1,161 @@
+package grammar
+
+import (
+	"sync"
+	"testing"
+)
+
+type test_type int
+
+const (
+	tt_eof test_type = iota
+	tt_word
+	tt_list
+)
+
+func (t test_type) String() string {
+	switch t {
+	case tt_eof:
+		return "EOF"
+	case tt_word:
+		return "Word"
+	case tt_list:
+		return "List"
+	default:
+		return "?"
+	}
+}
+
+// new_test_result is a helper function that parses "a b c" as a list of words.
+func new_test_result() Result[*Token[test_type]] {
+	words := []*Token[test_type]{
+		{Type: tt_word, Data: "a", Pos: 0},
+		{Type: tt_word, Data: "b", Pos: 2},
+		{Type: tt_word, Data: "c", Pos: 4},
+		{Type: tt_eof, Pos: -1},
+	}
+
+	LinkLookaheads(words)
+
+	root := &Token[test_type]{
+		Type:      tt_list,
+		Pos:       0,
+		Lookahead: words[3],
+		Children:  words[:3],
+	}
+
+	return NewResult(root)
+}
+
+func TestFreeze(t *testing.T) {
+	res := new_test_result()
+	if res.IsFrozen() {
+		t.Fatalf("expected a new result not to be frozen")
+	}
+
+	frozen := res.Freeze()
+	if !frozen.IsFrozen() {
+		t.Fatalf("expected the result to be frozen")
+	} else if frozen.Forest[0] != res.Forest[0] {
+		t.Errorf("expected Freeze not to copy the forest")
+	} else if cap(frozen.Forest) != len(frozen.Forest) {
+		t.Errorf("expected the forest to be clipped, got a capacity of %d for a length of %d", cap(frozen.Forest), len(frozen.Forest))
+	}
+}
+
+func TestClone(t *testing.T) {
+	res := new_test_result().Freeze()
+
+	cp := res.Clone()
+	if cp.IsFrozen() {
+		t.Errorf("expected the clone not to be frozen")
+	}
+
+	orig, _ := res.Root()
+	root, ok := cp.Root()
+	if !ok {
+		t.Fatalf("expected the clone to succeed")
+	} else if root == orig {
+		t.Fatalf("expected the root to be copied")
+	}
+
+	for i, child := range root.Children {
+		if child == orig.Children[i] {
+			t.Errorf("expected child %d to be copied", i)
+		} else if child.Data != orig.Children[i].Data {
+			t.Errorf("expected child %d to have data %q, got %q instead", i, orig.Children[i].Data, child.Data)
+		}
+	}
+
+	if root.Children[0].Lookahead != root.Children[1] {
+		t.Errorf("expected the lookahead of the first child to be the second copied child")
+	}
+
+	eof := root.Children[2].Lookahead
+	if eof == nil || eof == orig.Children[2].Lookahead || eof.Type != tt_eof {
+		t.Errorf("expected the lookahead of the last child to be a copy of the EOF token")
+	} else if root.Lookahead != eof {
+		t.Errorf("expected the lookahead of the root to be the copied EOF token")
+	}
+
+	root.Children[0].Data = "z"
+
+	if orig.Children[0].Data != "a" {
+		t.Errorf("expected the original to be unchanged, got %q instead", orig.Children[0].Data)
+	}
+}
+
+// TestFrozenSharing is meant to be run with the race detector: readers share a
+// frozen result while writers modify clones of it.
+func TestFrozenSharing(t *testing.T) {
+	res := new_test_result().Freeze()
+
+	const goroutines int = 8
+
+	var wg sync.WaitGroup
+
+	for i := range goroutines {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			root, _ := res.Root()
+
+			if span := root.Span(); span != NewSpan(0, 5) {
+				t.Errorf("expected span [0, 5), got %v instead", span)
+			}
+
+			for tk := root.Children[0]; tk != nil; tk = tk.Lookahead {
+				_ = tk.Data
+			}
+
+			// Appending to a frozen forest must not write to the shared memory.
+			_ = append(res.Forest, nil)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			cp := res.Clone()
+			root, _ := cp.Root()
+
+			for _, child := range root.Children {
+				child.Data += string(rune('0' + i))
+				child.Pos++
+			}
+
+			cp.Forest = append(cp.Forest, root.Clone())
+		}()
+	}
+
+	wg.Wait()
+
+	root, _ := res.Root()
+
+	for i, want := range []string{"a", "b", "c"} {
+		if got := root.Children[i].Data; got != want {
+			t.Errorf("expected child %d to have data %q, got %q instead", i, want, got)
+		}
+	}
+}
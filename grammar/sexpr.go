@@ -0,0 +1,79 @@
+package grammar
+
+import (
+	"bytes"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ForestToSExpr encodes a forest as S-expressions, one tree after the other. As
+// the positions of the tokens are left out, the encoding is meant to be read by
+// people; for instance, in the snapshots of golden tests.
+//
+// Format:
+//
+//	(<type> "<data>"
+//	  (<type> "<data>")
+//	  ...)
+//
+// where the data is a Go-quoted string, omitted when empty, and the children are
+// indented by two spaces. Every tree ends with a newline; nil trees are encoded as
+// "()".
+//
+// Parameters:
+//   - forest: The forest.
+//
+// Returns:
+//   - []byte: The encoding of the forest. Empty if the forest is.
+func ForestToSExpr[T Enumer](forest []*Token[T]) []byte {
+	type frame struct {
+		tk    *Token[T]
+		depth int
+		close bool
+	}
+
+	var buf bytes.Buffer
+
+	for _, root := range forest {
+		if root == nil {
+			buf.WriteString("()\n")
+			continue
+		}
+
+		stack := []frame{{tk: root}}
+
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if top.close {
+				buf.WriteByte(')')
+				continue
+			}
+
+			if top.tk != root {
+				buf.WriteByte('\n')
+				buf.WriteString(strings.Repeat("  ", top.depth))
+			}
+
+			buf.WriteString("(" + top.tk.Type.String())
+
+			if top.tk.Data != "" {
+				buf.WriteString(" " + strconv.Quote(top.tk.Data))
+			}
+
+			stack = append(stack, frame{close: true})
+
+			for _, child := range slices.Backward(top.tk.Children) {
+				if child != nil {
+					stack = append(stack, frame{tk: child, depth: top.depth + 1})
+				}
+			}
+		}
+
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,185 @@
+package grammar
+
+import (
+	"bytes"
+)
+
+// Span is a half-open range [Start, End) of byte offsets in the input stream.
+type Span struct {
+	// Start is the offset of the first byte of the span.
+	Start int
+
+	// End is the offset of the byte after the last byte of the span.
+	End int
+}
+
+// NewSpan creates a new span. The bounds are swapped if end is before start.
+//
+// Parameters:
+//   - start: The offset of the first byte.
+//   - end: The offset of the byte after the last byte.
+//
+// Returns:
+//   - Span: The new span.
+func NewSpan(start, end int) Span {
+	if end < start {
+		start, end = end, start
+	}
+
+	return Span{
+		Start: start,
+		End:   end,
+	}
+}
+
+// Len returns the number of bytes in the span.
+//
+// Returns:
+//   - int: The number of bytes in the span.
+func (s Span) Len() int {
+	return max(s.End-s.Start, 0)
+}
+
+// IsEmpty checks whether the span contains no byte.
+//
+// Returns:
+//   - bool: True if the span is empty, false otherwise.
+func (s Span) IsEmpty() bool {
+	return s.End <= s.Start
+}
+
+// Contains checks whether the span contains the given offset.
+//
+// Parameters:
+//   - pos: The offset.
+//
+// Returns:
+//   - bool: True if the offset is in the span, false otherwise.
+func (s Span) Contains(pos int) bool {
+	return s.Start <= pos && pos < s.End
+}
+
+// Covers checks whether the span contains the whole other span.
+//
+// Parameters:
+//   - other: The other span.
+//
+// Returns:
+//   - bool: True if the other span is in the span, false otherwise.
+func (s Span) Covers(other Span) bool {
+	return s.Start <= other.Start && other.End <= s.End
+}
+
+// Union returns the smallest span that contains both spans.
+//
+// Parameters:
+//   - other: The other span.
+//
+// Returns:
+//   - Span: The union of the spans.
+func (s Span) Union(other Span) Span {
+	return Span{
+		Start: min(s.Start, other.Start),
+		End:   max(s.End, other.End),
+	}
+}
+
+// Intersect returns the bytes that both spans have in common.
+//
+// Parameters:
+//   - other: The other span.
+//
+// Returns:
+//   - Span: The intersection of the spans.
+//   - bool: True if the spans overlap, false otherwise.
+func (s Span) Intersect(other Span) (Span, bool) {
+	start := max(s.Start, other.Start)
+	end := min(s.End, other.End)
+
+	if end <= start {
+		return Span{}, false
+	}
+
+	return Span{
+		Start: start,
+		End:   end,
+	}, true
+}
+
+// Lines returns the lines of the given data that the span touches.
+//
+// Parameters:
+//   - data: The input stream.
+//
+// Returns:
+//   - int: The first line. The first line of data is 1.
+//   - int: The last line.
+//
+// Offsets outside of data are clamped to its bounds.
+func (s Span) Lines(data []byte) (int, int) {
+	start := min(max(s.Start, 0), len(data))
+	end := min(max(s.End, start), len(data))
+
+	first := 1 + bytes.Count(data[:start], []byte{'\n'})
+
+	if end == start {
+		return first, first
+	}
+
+	return first, first + bytes.Count(data[start:end-1], []byte{'\n'})
+}
+
+// LineSpan returns the span that covers the given lines of the data, newlines excluded.
+//
+// Parameters:
+//   - data: The input stream.
+//   - first: The first line. The first line of data is 1.
+//   - last: The last line.
+//
+// Returns:
+//   - Span: The span of the lines.
+//   - bool: True if the lines exist, false otherwise.
+func LineSpan(data []byte, first, last int) (Span, bool) {
+	if first < 1 || last < first {
+		return Span{}, false
+	}
+
+	start := 0
+
+	for line := 1; line < first; line++ {
+		idx := bytes.IndexByte(data[start:], '\n')
+		if idx == -1 {
+			return Span{}, false
+		}
+
+		start += idx + 1
+	}
+
+	end := start
+
+	for line := first; ; line++ {
+		idx := bytes.IndexByte(data[end:], '\n')
+		if idx == -1 {
+			if line < last {
+				return Span{}, false
+			}
+
+			end = len(data)
+
+			break
+		}
+
+		if line == last {
+			end += idx
+
+			break
+		}
+
+		end += idx + 1
+	}
+
+	return Span{
+		Start: start,
+		End:   end,
+	}, true
+}
@@ -0,0 +1,165 @@
+package grammar
+
+import (
+	"testing"
+)
+
+func TestNewSpan(t *testing.T) {
+	if got := NewSpan(5, 2); got != (Span{Start: 2, End: 5}) {
+		t.Errorf("expected the bounds to be swapped, got %v", got)
+	}
+
+	if got := NewSpan(2, 2); !got.IsEmpty() || got.Len() != 0 {
+		t.Errorf("expected an empty span, got %v", got)
+	}
+}
+
+func TestSpanArithmetic(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Span
+		union   Span
+		inter   Span
+		overlap bool
+		covers  bool
+	}{
+		{
+			name:    "overlapping",
+			a:       Span{Start: 0, End: 5},
+			b:       Span{Start: 3, End: 8},
+			union:   Span{Start: 0, End: 8},
+			inter:   Span{Start: 3, End: 5},
+			overlap: true,
+		},
+		{
+			name:  "adjacent",
+			a:     Span{Start: 0, End: 3},
+			b:     Span{Start: 3, End: 6},
+			union: Span{Start: 0, End: 6},
+		},
+		{
+			name:  "disjoint",
+			a:     Span{Start: 0, End: 2},
+			b:     Span{Start: 4, End: 6},
+			union: Span{Start: 0, End: 6},
+		},
+		{
+			name:    "nested",
+			a:       Span{Start: 0, End: 10},
+			b:       Span{Start: 2, End: 4},
+			union:   Span{Start: 0, End: 10},
+			inter:   Span{Start: 2, End: 4},
+			overlap: true,
+			covers:  true,
+		},
+		{
+			name:   "empty inside",
+			a:      Span{Start: 0, End: 10},
+			b:      Span{Start: 4, End: 4},
+			union:  Span{Start: 0, End: 10},
+			covers: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Union(tt.b); got != tt.union {
+				t.Errorf("expected the union %v, got %v", tt.union, got)
+			}
+
+			if got := tt.b.Union(tt.a); got != tt.union {
+				t.Errorf("expected the union to commute, got %v", got)
+			}
+
+			inter, ok := tt.a.Intersect(tt.b)
+			if ok != tt.overlap || inter != tt.inter {
+				t.Errorf("expected the intersection %v (%t), got %v (%t)", tt.inter, tt.overlap, inter, ok)
+			}
+
+			if got := tt.a.Covers(tt.b); got != tt.covers {
+				t.Errorf("expected Covers to be %t, got %t", tt.covers, got)
+			}
+		})
+	}
+}
+
+func TestSpanContains(t *testing.T) {
+	s := Span{Start: 2, End: 5}
+
+	tests := []struct {
+		pos  int
+		want bool
+	}{
+		{pos: 1, want: false},
+		{pos: 2, want: true},
+		{pos: 4, want: true},
+		{pos: 5, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := s.Contains(tt.pos); got != tt.want {
+			t.Errorf("expected Contains(%d) to be %t, got %t", tt.pos, tt.want, got)
+		}
+	}
+}
+
+func TestSpanLines(t *testing.T) {
+	data := []byte("ab\ncd\nef")
+
+	tests := []struct {
+		name        string
+		span        Span
+		first, last int
+	}{
+		{name: "first line", span: Span{Start: 0, End: 2}, first: 1, last: 1},
+		{name: "up to the newline", span: Span{Start: 0, End: 3}, first: 1, last: 1},
+		{name: "two lines", span: Span{Start: 1, End: 4}, first: 1, last: 2},
+		{name: "empty", span: Span{Start: 3, End: 3}, first: 2, last: 2},
+		{name: "clamped", span: Span{Start: 6, End: 100}, first: 3, last: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last := tt.span.Lines(data)
+			if first != tt.first || last != tt.last {
+				t.Errorf("expected the lines %d-%d, got %d-%d", tt.first, tt.last, first, last)
+			}
+		})
+	}
+}
+
+func TestLineSpan(t *testing.T) {
+	data := []byte("ab\ncd\nef")
+
+	tests := []struct {
+		name        string
+		first, last int
+		want        Span
+		ok          bool
+	}{
+		{name: "first line", first: 1, last: 1, want: Span{Start: 0, End: 2}, ok: true},
+		{name: "middle lines", first: 2, last: 3, want: Span{Start: 3, End: 8}, ok: true},
+		{name: "last line", first: 3, last: 3, want: Span{Start: 6, End: 8}, ok: true},
+		{name: "past the end", first: 3, last: 4},
+		{name: "zero", first: 0, last: 1},
+		{name: "reversed", first: 2, last: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := LineSpan(data, tt.first, tt.last)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("expected %v (%t), got %v (%t)", tt.want, tt.ok, got, ok)
+			}
+
+			if !ok {
+				return
+			}
+
+			first, last := got.Lines(data)
+			if first != tt.first || last != tt.last {
+				t.Errorf("expected the span to touch the lines %d-%d, got %d-%d", tt.first, tt.last, first, last)
+			}
+		})
+	}
+}
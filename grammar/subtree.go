@@ -0,0 +1,201 @@
+package grammar
+
+// SpanNode is implemented by the nodes of a forest whose span in the input stream
+// is known, such as *Token[T].
+type SpanNode[N any] interface {
+	// Span returns the span of the node in the input stream.
+	//
+	// Returns:
+	//   - Span: The span of the node.
+	Span() Span
+
+	// Subtrees returns the children of the node.
+	//
+	// Returns:
+	//   - []N: The children of the node.
+	Subtrees() []N
+}
+
+// SubtreeAt returns the smallest node of the forest that covers the given offset.
+// Tooling that operates on selections uses it to find the node under the cursor.
+//
+// Parameters:
+//   - offset: The offset, in bytes, in the input stream.
+//
+// Returns:
+//   - N: The smallest node that covers the offset.
+//   - bool: True if a node covers the offset, false otherwise or if the nodes of
+//     the forest do not implement SpanNode.
+//
+// Works on failed results too, in which case the partial forest is searched.
+func (r Result[N]) SubtreeAt(offset int) (N, bool) {
+	var best N
+	found := false
+
+	nodes := r.Forest
+
+	for {
+		var next []N
+
+		for _, node := range nodes {
+			sn, ok := any(node).(SpanNode[N])
+			if !ok || !sn.Span().Contains(offset) {
+				continue
+			}
+
+			best = node
+			found = true
+			next = sn.Subtrees()
+
+			break
+		}
+
+		if next == nil {
+			return best, found
+		}
+
+		nodes = next
+	}
+}
+
+// ExtractSubtree copies a node of a parse tree into a standalone tree; that is, a
+// tree whose spans are relative to the start of the node and whose lookaheads do
+// not lead out of it.
+//
+// Parameters:
+//   - node: The node to extract.
+//
+// Returns:
+//   - *Token[T]: The standalone tree. Nil if node is nil.
+//
+// The original tree is not modified.
+func ExtractSubtree[T Enumer](node *Token[T]) *Token[T] {
+	if node == nil {
+		return nil
+	}
+
+	start := max(node.Span().Start, 0)
+
+	root := node.Moved(-start)
+
+	// Pairs the original tokens with their copies so that the lookaheads can be
+	// redirected to the copies.
+	copies := make(map[*Token[T]]*Token[T])
+
+	type pair struct {
+		orig, cp *Token[T]
+	}
+
+	stack := []pair{{orig: node, cp: root}}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		copies[top.orig] = top.cp
+
+		for i, child := range top.orig.Children {
+			if child != nil {
+				stack = append(stack, pair{orig: child, cp: top.cp.Children[i]})
+			}
+		}
+	}
+
+	for _, cp := range copies {
+		cp.Lookahead = copies[cp.Lookahead]
+	}
+
+	return root
+}
+
+// CloneForest deep copies a forest, such as the one of a result. The lookaheads of
+// the copies lead to the copies, including the lookaheads that lead out of the
+// forest (such as to the EOF token), which are copied too.
+//
+// Parameters:
+//   - forest: The forest. Nil trees are kept as nil.
+//
+// Returns:
+//   - []*Token[T]: The copy of the forest. Nil if forest is nil.
+//
+// The original forest is not modified and shares no token with its copy.
+func CloneForest[T Enumer](forest []*Token[T]) []*Token[T] {
+	if forest == nil {
+		return nil
+	}
+
+	copies := make(map[*Token[T]]*Token[T])
+
+	type pair struct {
+		orig, cp *Token[T]
+	}
+
+	clones := make([]*Token[T], 0, len(forest))
+
+	for _, root := range forest {
+		if root == nil {
+			clones = append(clones, nil)
+			continue
+		}
+
+		cp := root.Moved(0)
+		clones = append(clones, cp)
+
+		stack := []pair{{orig: root, cp: cp}}
+
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			copies[top.orig] = top.cp
+
+			for i, child := range top.orig.Children {
+				if child != nil {
+					stack = append(stack, pair{orig: child, cp: top.cp.Children[i]})
+				}
+			}
+		}
+	}
+
+	// The tokens outside of the forest are met while redirecting the lookaheads;
+	// a queue is used as they may have lookaheads of their own.
+	queue := make([]*Token[T], 0, len(copies))
+
+	for _, cp := range copies {
+		queue = append(queue, cp)
+	}
+
+	for len(queue) > 0 {
+		cp := queue[0]
+		queue = queue[1:]
+
+		if cp.Lookahead == nil {
+			continue
+		}
+
+		la, ok := copies[cp.Lookahead]
+		if !ok {
+			la = cp.Lookahead.Moved(0)
+			copies[cp.Lookahead] = la
+
+			queue = append(queue, la)
+		}
+
+		cp.Lookahead = la
+	}
+
+	return clones
+}
+
+// Clone returns a deep copy of the token, and of its children, whose lookaheads
+// lead to copies. It implements the Cloner interface.
+//
+// Returns:
+//   - *Token[T]: The copy of the token. Nil if the receiver is nil.
+func (tk *Token[T]) Clone() *Token[T] {
+	if tk == nil {
+		return nil
+	}
+
+	return CloneForest([]*Token[T]{tk})[0]
+}
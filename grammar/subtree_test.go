@@ -0,0 +1,82 @@
+package grammar
+
+import (
+	"testing"
+)
+
+func TestSubtreeAt(t *testing.T) {
+	res := Result[*Token[json_type]]{
+		Forest: new_json_forest(t),
+	}
+
+	tests := []struct {
+		name   string
+		offset int
+		type_  json_type
+		data   string
+		found  bool
+	}{
+		{name: "first leaf", offset: 0, type_: jt_word, data: "ab", found: true},
+		{name: "second leaf", offset: 4, type_: jt_word, data: "cd", found: true},
+		{name: "between the leaves", offset: 2, type_: jt_list, found: true},
+		{name: "second tree", offset: 10, type_: jt_word, data: "ef", found: true},
+		{name: "between the trees", offset: 7},
+		{name: "before the input", offset: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, ok := res.SubtreeAt(tt.offset)
+			if ok != tt.found {
+				t.Fatalf("expected found to be %t, got %t", tt.found, ok)
+			} else if !ok {
+				return
+			}
+
+			if node.Type != tt.type_ || node.Data != tt.data {
+				t.Errorf("expected %s %q, got %s %q", tt.type_, tt.data, node.Type, node.Data)
+			}
+		})
+	}
+}
+
+func TestExtractSubtree(t *testing.T) {
+	forest := new_json_forest(t)
+
+	list := forest[0].Children[0]
+	cd := list.Children[1]
+
+	sub := ExtractSubtree(cd)
+
+	if sub.Pos != 0 || sub.Span() != (Span{Start: 0, End: 2}) {
+		t.Errorf("expected the leaf to start at 0, got %d with the span %v", sub.Pos, sub.Span())
+	}
+
+	if sub.Lookahead != nil {
+		t.Errorf("expected the lookahead out of the subtree to be dropped, got %v", sub.Lookahead)
+	}
+
+	if cd.Pos != 3 || cd.Lookahead == nil {
+		t.Errorf("expected the original leaf to be untouched, got %d and %v", cd.Pos, cd.Lookahead)
+	}
+
+	sub = ExtractSubtree(list)
+
+	ab, cd_cp := sub.Children[0], sub.Children[1]
+
+	if ab == list.Children[0] || cd_cp == cd {
+		t.Fatal("expected the children to be copied")
+	}
+
+	if ab.Lookahead != cd_cp {
+		t.Errorf("expected the lookahead of the copy of ab to be the copy of cd, got %v", ab.Lookahead)
+	}
+
+	if cd_cp.Lookahead != nil {
+		t.Errorf("expected the last leaf to have no lookahead, got %v", cd_cp.Lookahead)
+	}
+
+	if ExtractSubtree[json_type](nil) != nil {
+		t.Error("expected nil for a nil node")
+	}
+}
@@ -0,0 +1,185 @@
+package grammar
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// token_reader is the io.Reader returned by NewTokenReader.
+type token_reader[T Enumer] struct {
+	// tokens are the tokens that are yet to be encoded.
+	tokens []*Token[T]
+
+	// buf is the encoding of the current token that is yet to be read.
+	buf []byte
+}
+
+// Read implements the io.Reader interface.
+func (r *token_reader[T]) Read(p []byte) (int, error) {
+	var n int
+
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			if len(r.tokens) == 0 {
+				break
+			}
+
+			r.buf = append(r.buf[:0], token_line(r.tokens[0])...)
+			r.tokens = r.tokens[1:]
+		}
+
+		c := copy(p[n:], r.buf)
+		r.buf = r.buf[c:]
+		n += c
+	}
+
+	if n == 0 && len(p) > 0 {
+		return 0, io.EOF
+	}
+
+	return n, nil
+}
+
+// NewTokenReader returns a reader of the textual encoding of a token stream, so
+// that the stream can go through line-oriented tools such as grep, sed or sort
+// before being read back with ReadTokens.
+//
+// Parameters:
+//   - tokens: The tokens. Nil tokens are skipped. Only the leaves are encoded; the
+//     children of non-terminal tokens are ignored.
+//
+// Returns:
+//   - io.Reader: The reader. Never returns nil.
+//
+// Format (one line per token):
+//
+//	<type> <pos> <data>
+//
+// where the data is a Go-quoted string; thus, a line never contains a newline.
+// The tokens are encoded lazily as the reader is read.
+func NewTokenReader[T Enumer](tokens []*Token[T]) io.Reader {
+	list := make([]*Token[T], 0, len(tokens))
+
+	for _, tk := range tokens {
+		if tk != nil {
+			list = append(list, tk)
+		}
+	}
+
+	return &token_reader[T]{
+		tokens: list,
+	}
+}
+
+// token_line is a helper function that encodes a token as a line.
+//
+// Parameters:
+//   - tk: The token. Assumed to be non-nil.
+//
+// Returns:
+//   - string: The line, with its trailing newline.
+func token_line[T Enumer](tk *Token[T]) string {
+	return tk.Type.String() + " " + strconv.Itoa(tk.Pos) + " " + strconv.Quote(tk.Data) + "\n"
+}
+
+// ReadTokens reads a token stream encoded by NewTokenReader and links the
+// lookaheads of the tokens in order.
+//
+// Parameters:
+//   - r: The reader.
+//   - types: The token types that can appear in the stream. They are recognized by
+//     their name.
+//
+// Returns:
+//   - []*Token[T]: The tokens, in the order of their lines.
+//   - error: An error if r could not be read or if a line is not a valid encoding.
+//     The error tells the number of the line.
+//
+// Empty lines are skipped, so that the output of tools that blank out lines can be
+// read as is. A line can be up to 16MB long.
+func ReadTokens[T Enumer](r io.Reader, types ...T) ([]*Token[T], error) {
+	table := make(map[string]T, len(types))
+
+	for _, type_ := range types {
+		table[type_.String()] = type_
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+
+	var (
+		tokens []*Token[T]
+		line   int
+	)
+
+	for scanner.Scan() {
+		line++
+
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		tk, err := parse_token_line(string(text), table)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		tokens = append(tokens, tk)
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	LinkLookaheads(tokens)
+
+	return tokens, nil
+}
+
+// parse_token_line is a helper function that decodes a line written by
+// token_line.
+//
+// Parameters:
+//   - text: The line, without surrounding spaces.
+//   - table: The token types by name.
+//
+// Returns:
+//   - *Token[T]: The token.
+//   - error: An error if the line is not a valid encoding.
+func parse_token_line[T Enumer](text string, table map[string]T) (*Token[T], error) {
+	name, rest, ok := strings.Cut(text, " ")
+	if !ok {
+		return nil, fmt.Errorf("expected \"<type> <pos> <data>\", got %q", text)
+	}
+
+	type_, ok := table[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown token type %q", name)
+	}
+
+	pos_text, data_text, ok := strings.Cut(rest, " ")
+	if !ok {
+		return nil, fmt.Errorf("expected \"<type> <pos> <data>\", got %q", text)
+	}
+
+	pos, err := strconv.Atoi(pos_text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid position %q", pos_text)
+	}
+
+	data, err := strconv.Unquote(data_text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data %s", data_text)
+	}
+
+	tk := NewTerminalToken(type_, data)
+	tk.Pos = pos
+
+	return tk, nil
+}
@@ -0,0 +1,37 @@
+package grammar
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadTokensLarge(t *testing.T) {
+	data := strings.Repeat("x", 1<<20)
+
+	word := NewTerminalToken(jt_word, data)
+	word.Pos = 0
+
+	eof := NewTerminalToken(jt_eof, "")
+	eof.Pos = len(data)
+
+	tokens, err := ReadTokens(NewTokenReader([]*Token[json_type]{word, eof}), jt_eof, jt_word)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(tokens) != 2 {
+		t.Fatalf("expected 2 tokens, got %d", len(tokens))
+	}
+
+	if tokens[0].Data != data {
+		t.Errorf("expected a word of %d bytes, got %d bytes", len(data), len(tokens[0].Data))
+	}
+
+	if tokens[1].Pos != len(data) {
+		t.Errorf("expected EOF at %d, got %d", len(data), tokens[1].Pos)
+	}
+
+	if tokens[0].Lookahead != tokens[1] {
+		t.Errorf("expected the lookahead of the word to be EOF, got %v", tokens[0].Lookahead)
+	}
+}
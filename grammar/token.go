@@ -2,6 +2,7 @@ package grammar
 
 import (
 	gcers "github.com/PlayerR9/go-commons/errors"
+	"github.com/PlayerR9/grammar/internal/chain"
 )
 
 // Enumer is an interface for all token types. The 0th value is reserved for the EOF token.
@@ -24,7 +25,7 @@ type Token[T Enumer] struct {
 	// Data is the value of the token.
 	Data string
 
-	// Pos is the position of the token in the input stream.
+	// Pos is the offset, in bytes, of the token in the input stream.
 	Pos int
 
 	// Lookahead is the next token in the input stream.
@@ -32,6 +33,11 @@ type Token[T Enumer] struct {
 
 	// Children are the children of the token.
 	Children []*Token[T]
+
+	// Value is the value of the token computed from its data, such as the int64
+	// of an integer literal. Nil if the token has none. See TokenValueAs. Values
+	// are shared by the copies of the token and are not written by ForestToJSON.
+	Value any
 }
 
 // NewTerminalToken creates a new terminal token with the given type, data, and lookahead.
@@ -96,7 +102,180 @@ func (tk Token[T]) GetData() string {
 // GetPos returns the position of the token in the input stream.
 //
 // Returns:
-//   - int: The position of the token in the input stream.
+//   - int: The offset, in bytes, of the token in the input stream.
 func (tk Token[T]) GetPos() int {
 	return tk.Pos
 }
+
+// Span returns the span of the token in the input stream. The span of a
+// non-terminal token is the union of the spans of its children; children without
+// a position (such as the EOF token) are ignored.
+//
+// Returns:
+//   - Span: The span of the token.
+//
+// The tree is walked with an explicit stack; so, arbitrarily deep trees are
+// supported.
+func (tk Token[T]) Span() Span {
+	if len(tk.Children) == 0 {
+		return Span{
+			Start: tk.Pos,
+			End:   tk.Pos + len(tk.Data),
+		}
+	}
+
+	type frame struct {
+		tk    *Token[T]
+		next  int
+		span  Span
+		found bool
+	}
+
+	merge := func(f *frame, span Span) {
+		if span.Start < 0 {
+			return
+		}
+
+		if f.found {
+			f.span = f.span.Union(span)
+		} else {
+			f.span = span
+			f.found = true
+		}
+	}
+
+	stack := []*frame{{tk: &tk, span: Span{Start: tk.Pos, End: tk.Pos}}}
+
+	for {
+		top := stack[len(stack)-1]
+
+		if top.next < len(top.tk.Children) {
+			child := top.tk.Children[top.next]
+			top.next++
+
+			if child == nil {
+				continue
+			}
+
+			if len(child.Children) == 0 {
+				merge(top, Span{Start: child.Pos, End: child.Pos + len(child.Data)})
+			} else {
+				stack = append(stack, &frame{tk: child, span: Span{Start: child.Pos, End: child.Pos}})
+			}
+
+			continue
+		}
+
+		stack = stack[:len(stack)-1]
+
+		if len(stack) == 0 {
+			return top.span
+		}
+
+		merge(stack[len(stack)-1], top.span)
+	}
+}
+
+// TokenValueAs returns the value of a token as the given type.
+//
+// Parameters:
+//   - tk: The token.
+//
+// Returns:
+//   - V: The value of the token. The zero value if the token has no value of type V.
+//   - bool: True if tk is not nil and its value is of type V, false otherwise.
+func TokenValueAs[V any, T Enumer](tk *Token[T]) (V, bool) {
+	if tk == nil {
+		return *new(V), false
+	}
+
+	v, ok := tk.Value.(V)
+
+	return v, ok
+}
+
+// LinkLookaheads sets the Lookahead of every token to the token that follows it
+// and clears the Lookahead of the last one, so that no chain leaks from a
+// previous linking. Nil tokens are skipped.
+//
+// Parameters:
+//   - tokens: The tokens, in the order of the input stream.
+func LinkLookaheads[T Enumer](tokens []*Token[T]) {
+	chain.Link(tokens, func(tk *Token[T]) **Token[T] {
+		return &tk.Lookahead
+	})
+}
+
+// LookaheadAt follows k links of the lookahead chain of the token; which gives a
+// window of k tokens for parsers that look further than the next token.
+//
+// Parameters:
+//   - k: The number of links to follow. 0 is the token itself.
+//
+// Returns:
+//   - *Token[T]: The token k links ahead.
+//   - bool: True if the token exists, false otherwise.
+func (tk *Token[T]) LookaheadAt(k int) (*Token[T], bool) {
+	return chain.At(tk, k, func(tk *Token[T]) **Token[T] {
+		return &tk.Lookahead
+	})
+}
+
+// Subtrees returns the children of the token. It lets tokens be walked through the
+// SpanNode interface.
+//
+// Returns:
+//   - []*Token[T]: The children of the token.
+func (tk Token[T]) Subtrees() []*Token[T] {
+	return tk.Children
+}
+
+// Moved returns a deep copy of the token, and of its children, moved by the given
+// offset. Tokens without a position (such as the EOF token) are not moved, and
+// lookaheads are kept as is.
+//
+// Parameters:
+//   - offset: The offset, in bytes.
+//
+// Returns:
+//   - *Token[T]: The moved copy of the token. Nil if the receiver is nil.
+func (tk *Token[T]) Moved(offset int) *Token[T] {
+	if tk == nil {
+		return nil
+	}
+
+	root := *tk
+
+	stack := []*Token[T]{&root}
+
+	for len(stack) > 0 {
+		cp := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if cp.Pos >= 0 {
+			cp.Pos += offset
+		}
+
+		if len(cp.Children) == 0 {
+			continue
+		}
+
+		children := make([]*Token[T], 0, len(cp.Children))
+
+		for _, child := range cp.Children {
+			if child == nil {
+				children = append(children, nil)
+				continue
+			}
+
+			child_cp := *child
+
+			children = append(children, &child_cp)
+			stack = append(stack, &child_cp)
+		}
+
+		cp.Children = children
+	}
+
+	return &root
+}
@@ -0,0 +1,179 @@
+package grammar
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// The functions below convert columns between the three ways of addressing a
+// position within a line: byte offsets (as the tokens do), rune counts, and UTF-16
+// code units (as the Language Server Protocol and many editors do).
+//
+// Columns are 0-based and relative to the start of the line. Invalid UTF-8 bytes
+// count as one rune and one UTF-16 code unit each. Columns that fall inside of a
+// character are rounded down to the start of that character, and columns past the
+// end of the line are clamped to it.
+
+// ByteToRuneColumn converts a byte offset within a line to a rune column.
+//
+// Parameters:
+//   - line: The line.
+//   - offset: The offset, in bytes, within the line.
+//
+// Returns:
+//   - int: The number of runes before the offset.
+func ByteToRuneColumn(line []byte, offset int) int {
+	var col int
+
+	for i := 0; i < len(line); {
+		_, size := utf8.DecodeRune(line[i:])
+		if i+size > offset {
+			break
+		}
+
+		i += size
+		col++
+	}
+
+	return col
+}
+
+// RuneToByteOffset converts a rune column within a line to a byte offset.
+//
+// Parameters:
+//   - line: The line.
+//   - col: The number of runes before the position.
+//
+// Returns:
+//   - int: The offset, in bytes, within the line.
+func RuneToByteOffset(line []byte, col int) int {
+	var offset int
+
+	for ; col > 0 && offset < len(line); col-- {
+		_, size := utf8.DecodeRune(line[offset:])
+		offset += size
+	}
+
+	return offset
+}
+
+// ByteToUTF16Column converts a byte offset within a line to a UTF-16 column.
+//
+// Parameters:
+//   - line: The line.
+//   - offset: The offset, in bytes, within the line.
+//
+// Returns:
+//   - int: The number of UTF-16 code units before the offset.
+func ByteToUTF16Column(line []byte, offset int) int {
+	var col int
+
+	for i := 0; i < len(line); {
+		r, size := utf8.DecodeRune(line[i:])
+		if i+size > offset {
+			break
+		}
+
+		i += size
+		col += utf16_len(r)
+	}
+
+	return col
+}
+
+// UTF16ToByteOffset converts a UTF-16 column within a line to a byte offset.
+//
+// Parameters:
+//   - line: The line.
+//   - col: The number of UTF-16 code units before the position.
+//
+// Returns:
+//   - int: The offset, in bytes, within the line.
+func UTF16ToByteOffset(line []byte, col int) int {
+	var offset int
+
+	for offset < len(line) {
+		r, size := utf8.DecodeRune(line[offset:])
+
+		col -= utf16_len(r)
+		if col < 0 {
+			break
+		}
+
+		offset += size
+	}
+
+	return offset
+}
+
+// RuneToUTF16Column converts a rune column within a line to a UTF-16 column.
+//
+// Parameters:
+//   - line: The line.
+//   - col: The number of runes before the position.
+//
+// Returns:
+//   - int: The number of UTF-16 code units before the position.
+func RuneToUTF16Column(line []byte, col int) int {
+	return ByteToUTF16Column(line, RuneToByteOffset(line, col))
+}
+
+// UTF16ToRuneColumn converts a UTF-16 column within a line to a rune column.
+//
+// Parameters:
+//   - line: The line.
+//   - col: The number of UTF-16 code units before the position.
+//
+// Returns:
+//   - int: The number of runes before the position.
+func UTF16ToRuneColumn(line []byte, col int) int {
+	return ByteToRuneColumn(line, UTF16ToByteOffset(line, col))
+}
+
+// LineAt returns the line that contains the given offset of the data.
+//
+// Parameters:
+//   - data: The input stream.
+//   - offset: The offset, in bytes, within the data.
+//
+// Returns:
+//   - []byte: The line, newline excluded. Both "\n" and "\r\n" are newlines.
+//   - int: The offset, in bytes, of the start of the line within the data.
+//
+// Offsets outside of data are clamped to its bounds.
+func LineAt(data []byte, offset int) ([]byte, int) {
+	offset = min(max(offset, 0), len(data))
+
+	start := offset
+	for start > 0 && data[start-1] != '\n' {
+		start--
+	}
+
+	end := offset
+	for end < len(data) && data[end] != '\n' {
+		end++
+	}
+
+	if end > start && data[end-1] == '\r' && end < len(data) {
+		end--
+	}
+
+	return data[start:end], start
+}
+
+// utf16_len is a helper function that returns the number of UTF-16 code units of
+// a rune.
+//
+// Parameters:
+//   - r: The rune.
+//
+// Returns:
+//   - int: The number of UTF-16 code units; 1 for invalid runes.
+func utf16_len(r rune) int {
+	n := utf16.RuneLen(r)
+	if n < 0 {
+		return 1
+	}
+
+	return n
+}
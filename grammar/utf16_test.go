@@ -0,0 +1,106 @@
+package grammar
+
+import (
+	"testing"
+)
+
+func TestColumns(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		offset int
+		runes  int
+		utf16  int
+	}{
+		{name: "ascii", line: "abc", offset: 2, runes: 2, utf16: 2},
+		{name: "two bytes", line: "éa", offset: 2, runes: 1, utf16: 1},
+		{name: "surrogate pair", line: "😀a", offset: 4, runes: 1, utf16: 2},
+		{name: "after surrogate pair", line: "😀a", offset: 5, runes: 2, utf16: 3},
+		{name: "invalid utf-8", line: "\xffa", offset: 1, runes: 1, utf16: 1},
+		{name: "truncated rune", line: "\xf0\x9fa", offset: 2, runes: 2, utf16: 2},
+		{name: "end of line", line: "ab", offset: 2, runes: 2, utf16: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := []byte(tt.line)
+
+			if got := ByteToRuneColumn(line, tt.offset); got != tt.runes {
+				t.Errorf("expected the rune column %d, got %d", tt.runes, got)
+			}
+
+			if got := ByteToUTF16Column(line, tt.offset); got != tt.utf16 {
+				t.Errorf("expected the UTF-16 column %d, got %d", tt.utf16, got)
+			}
+
+			if got := RuneToByteOffset(line, tt.runes); got != tt.offset {
+				t.Errorf("expected the offset %d from the rune column, got %d", tt.offset, got)
+			}
+
+			if got := UTF16ToByteOffset(line, tt.utf16); got != tt.offset {
+				t.Errorf("expected the offset %d from the UTF-16 column, got %d", tt.offset, got)
+			}
+
+			if got := RuneToUTF16Column(line, tt.runes); got != tt.utf16 {
+				t.Errorf("expected the UTF-16 column %d from the rune column, got %d", tt.utf16, got)
+			}
+
+			if got := UTF16ToRuneColumn(line, tt.utf16); got != tt.runes {
+				t.Errorf("expected the rune column %d from the UTF-16 column, got %d", tt.runes, got)
+			}
+		})
+	}
+}
+
+func TestColumnsRounding(t *testing.T) {
+	line := []byte("😀a")
+
+	// Inside of the emoji: rounded down to its start.
+	if got := ByteToUTF16Column(line, 2); got != 0 {
+		t.Errorf("expected the UTF-16 column 0, got %d", got)
+	}
+
+	// Between the two code units of the surrogate pair.
+	if got := UTF16ToByteOffset(line, 1); got != 0 {
+		t.Errorf("expected the offset 0, got %d", got)
+	}
+
+	// Past the end of the line: clamped to it.
+	if got := UTF16ToByteOffset(line, 10); got != len(line) {
+		t.Errorf("expected the offset %d, got %d", len(line), got)
+	}
+
+	if got := RuneToByteOffset(line, 10); got != len(line) {
+		t.Errorf("expected the offset %d, got %d", len(line), got)
+	}
+}
+
+func TestLineAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		offset int
+		line   string
+		start  int
+	}{
+		{name: "first line", data: "ab\ncd", offset: 1, line: "ab", start: 0},
+		{name: "last line", data: "ab\ncd", offset: 4, line: "cd", start: 3},
+		{name: "at newline", data: "ab\ncd", offset: 2, line: "ab", start: 0},
+		{name: "crlf", data: "ab\r\ncd", offset: 1, line: "ab", start: 0},
+		{name: "after crlf", data: "ab\r\ncd", offset: 5, line: "cd", start: 4},
+		{name: "lone cr", data: "ab\r", offset: 1, line: "ab\r", start: 0},
+		{name: "empty line", data: "ab\n\ncd", offset: 3, line: "", start: 3},
+		{name: "negative", data: "ab\ncd", offset: -1, line: "ab", start: 0},
+		{name: "past the end", data: "ab\ncd", offset: 10, line: "cd", start: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, start := LineAt([]byte(tt.data), tt.offset)
+
+			if string(line) != tt.line || start != tt.start {
+				t.Errorf("expected %q at %d, got %q at %d", tt.line, tt.start, line, start)
+			}
+		})
+	}
+}
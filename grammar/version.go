@@ -0,0 +1,76 @@
+package grammar
+
+import "fmt"
+
+// ErrUnsupportedVersion occurs when a persisted artifact, such as an encoded
+// forest, was written in a format version that cannot be read.
+type ErrUnsupportedVersion struct {
+	// Format is the name of the format.
+	Format string
+
+	// Version is the version of the artifact.
+	Version int
+
+	// Min is the oldest version that can be read.
+	Min int
+
+	// Max is the latest version that can be read.
+	Max int
+}
+
+// Error implements the error interface.
+//
+// Message:
+//
+//	"<format> format version <version> is newer than the latest supported one
+//	(<max>); upgrade the module to read it"
+//
+// or, for versions that are too old:
+//
+//	"<format> format version <version> is no longer supported (oldest supported:
+//	<min>); decode it with an older release of the module and encode it again"
+func (e ErrUnsupportedVersion) Error() string {
+	if e.Version > e.Max {
+		return fmt.Sprintf("%s format version %d is newer than the latest supported one (%d); upgrade the module to read it", e.Format, e.Version, e.Max)
+	}
+
+	return fmt.Sprintf("%s format version %d is no longer supported (oldest supported: %d); decode it with an older release of the module and encode it again", e.Format, e.Version, e.Min)
+}
+
+// NewErrUnsupportedVersion creates a new ErrUnsupportedVersion error.
+//
+// Parameters:
+//   - format: The name of the format.
+//   - version: The version of the artifact.
+//   - oldest: The oldest version that can be read.
+//   - latest: The latest version that can be read.
+//
+// Returns:
+//   - *ErrUnsupportedVersion: The new error. Never returns nil.
+func NewErrUnsupportedVersion(format string, version, oldest, latest int) *ErrUnsupportedVersion {
+	return &ErrUnsupportedVersion{
+		Format:  format,
+		Version: version,
+		Min:     oldest,
+		Max:     latest,
+	}
+}
+
+// CheckVersion checks that a persisted artifact can be read.
+//
+// Parameters:
+//   - format: The name of the format.
+//   - version: The version of the artifact.
+//   - oldest: The oldest version that can be read.
+//   - latest: The latest version that can be read.
+//
+// Returns:
+//   - error: An error of type *ErrUnsupportedVersion if version is not between
+//     oldest and latest, inclusive.
+func CheckVersion(format string, version, oldest, latest int) error {
+	if version < oldest || version > latest {
+		return NewErrUnsupportedVersion(format, version, oldest, latest)
+	}
+
+	return nil
+}
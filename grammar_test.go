@@ -0,0 +1,328 @@
+package grammar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"unicode"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/grammar/lexer"
+	"github.com/PlayerR9/grammar/parser"
+)
+
+type test_type int
+
+const (
+	tt_eof test_type = iota
+	tt_word
+	nt_list
+	nt_source
+)
+
+func (t test_type) String() string {
+	return [...]string{"EOF", "Word", "List", "Source"}[t]
+}
+
+// new_test_grammar is a helper function that compiles the grammar of
+// new_test_spec.
+func new_test_grammar(t *testing.T) *CompiledGrammar[test_type] {
+	spec := new_test_spec(t)
+
+	return Compile(spec.Lexer, spec.Parser)
+}
+
+// new_test_spec is a helper function that makes the specification of the grammar
+// of lists of words separated by spaces:
+//
+//	Source -> List EOF | EOF
+//	List -> Word List | Word
+func new_test_spec(t *testing.T) Spec[test_type] {
+	must := func(rule *parser.Rule[test_type], err error) *parser.Rule[test_type] {
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		return rule
+	}
+
+	source := must(parser.NewRule(nt_source, nt_list, tt_eof))
+	empty := must(parser.NewRule(nt_source, tt_eof))
+	list := must(parser.NewRule(nt_list, tt_word, nt_list))
+	last := must(parser.NewRule(nt_list, tt_word))
+
+	lb := lexer.NewBuilder[test_type]()
+
+	_ = lb.RegisterSkip(" ")
+	_ = lb.RegisterSkip("\n")
+
+	lb.RegisterDefault(func(l *lexer.Lexer[test_type]) (*gr.Token[test_type], error) {
+		var word []rune
+
+		for {
+			c, ok := l.PeekRune()
+			if !ok || c == ' ' || c == '\n' {
+				break
+			} else if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '.' {
+				return nil, fmt.Errorf("unexpected character %q", c)
+			}
+
+			_, _ = l.NextRune()
+			word = append(word, c)
+		}
+
+		return gr.NewTerminalToken(tt_word, string(word)), nil
+	})
+
+	pb := parser.NewBuilder[test_type]()
+
+	pb.Register(tt_word, func(_ *parser.Parser[test_type], _, la *gr.Token[test_type]) (parser.Actioner, error) {
+		if la != nil && la.Type == tt_word {
+			return parser.NewShiftAct(), nil
+		}
+
+		return parser.NewReduceAct(last)
+	})
+
+	pb.Register(nt_list, func(p *parser.Parser[test_type], _, _ *gr.Token[test_type]) (parser.Actioner, error) {
+		if below, ok := p.Pop(); ok && below.Type == tt_word {
+			return parser.NewReduceAct(list)
+		}
+
+		return parser.NewShiftAct(), nil
+	})
+
+	pb.Register(tt_eof, func(p *parser.Parser[test_type], _, _ *gr.Token[test_type]) (parser.Actioner, error) {
+		if _, ok := p.Pop(); ok {
+			return parser.NewAcceptAct(source)
+		}
+
+		return parser.NewAcceptAct(empty)
+	})
+
+	return Spec[test_type]{
+		Lexer:  lb,
+		Parser: pb,
+	}
+}
+
+// words_of is a helper function that returns the words of a parsed list of words.
+func words_of(res gr.Result[*gr.Token[test_type]]) []string {
+	var words []string
+
+	root, ok := res.Root()
+	if !ok {
+		return nil
+	}
+
+	for list := root.Children[0]; list.Type == nt_list; list = list.Children[len(list.Children)-1] {
+		words = append(words, list.Children[0].Data)
+
+		if len(list.Children) == 1 {
+			break
+		}
+	}
+
+	return words
+}
+
+func TestRun(t *testing.T) {
+	g := new_test_grammar(t)
+
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"a b.c d", []string{"a", "b.c", "d"}},
+	}
+
+	for _, test := range tests {
+		res, err := Run([]byte(test.input), g)
+		if err != nil {
+			t.Fatalf("input %q: expected no error, got %v", test.input, err)
+		} else if res.Err != nil {
+			t.Fatalf("input %q: expected no parse error, got %v", test.input, res.Err)
+		}
+
+		got := words_of(res)
+
+		if fmt.Sprint(got) != fmt.Sprint(test.want) {
+			t.Errorf("input %q: expected the words %v, got %v", test.input, test.want, got)
+		}
+	}
+}
+
+func TestRunWith(t *testing.T) {
+	spec := new_test_spec(t)
+
+	last, err := parser.NewRule(nt_list, tt_word)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Every run collects its words in the slice given as its user context.
+	spec.Parser.Register(tt_word, func(p *parser.Parser[test_type], top1, la *gr.Token[test_type]) (parser.Actioner, error) {
+		words, ok := parser.UserContextAs[*[]string](p)
+		if !ok {
+			return nil, fmt.Errorf("expected a user context of type *[]string, got %T", p.UserContext())
+		}
+
+		*words = append(*words, top1.Data)
+
+		if la != nil && la.Type == tt_word {
+			return parser.NewShiftAct(), nil
+		}
+
+		return parser.NewReduceAct(last)
+	})
+
+	g := Compile(spec.Lexer, spec.Parser)
+	g.EnableMemo(8)
+
+	inputs := []string{"a b", "c", "d e f"}
+
+	var wg sync.WaitGroup
+
+	got := make([][]string, 3*len(inputs))
+
+	for i := range got {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			res, err := RunWith([]byte(inputs[i%len(inputs)]), g, &got[i])
+			if err == nil {
+				err = res.Err
+			}
+
+			if err != nil {
+				t.Errorf("run %d: expected no error, got %v", i, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for i, words := range got {
+		want := fmt.Sprint(strings.Fields(inputs[i%len(inputs)]))
+
+		if fmt.Sprint(words) != want {
+			t.Errorf("run %d: expected the words %s, got %v", i, want, words)
+		}
+	}
+
+	res, err := Run([]byte("a"), g)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	} else if res.Err == nil {
+		t.Errorf("expected a run without user context to fail, got no error")
+	}
+}
+
+func TestRunCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	spec := new_test_spec(t)
+
+	last, err := parser.NewRule(nt_list, tt_word)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	spec.Parser.Register(tt_word, func(_ *parser.Parser[test_type], top1, la *gr.Token[test_type]) (parser.Actioner, error) {
+		if top1.Data == "b" {
+			cancel()
+		}
+
+		if la != nil && la.Type == tt_word {
+			return parser.NewShiftAct(), nil
+		}
+
+		return parser.NewReduceAct(last)
+	})
+
+	g := Compile(spec.Lexer, spec.Parser)
+	g.EnableMemo(8)
+
+	res, err := RunCtx(ctx, []byte("a b c"), g)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var cancelled *gr.ErrCancelled
+
+	if !errors.As(res.Err, &cancelled) {
+		t.Fatalf("expected an *ErrCancelled, got %v", res.Err)
+	}
+
+	forest, _ := res.PartialForest()
+	if len(forest) != 3 || forest[0].Data != "a" || forest[1].Data != "b" {
+		t.Errorf("expected the partial forest to start with [a b], got %v", forest)
+	}
+
+	// The context is done; the lexing does not even start.
+	_, err = RunCtx(ctx, []byte("a b c"), g)
+	if !errors.As(err, &cancelled) {
+		t.Fatalf("expected an *ErrCancelled, got %v", err)
+	}
+
+	// The cancelled result was not memoized.
+	res, err = Run([]byte("a b c"), g)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	} else if res.Err != nil {
+		t.Fatalf("expected no parse error, got %v", res.Err)
+	}
+
+	if got := fmt.Sprint(words_of(res)); got != "[a b c]" {
+		t.Errorf("expected the words [a b c], got %s", got)
+	}
+}
+
+func TestRunFrozen(t *testing.T) {
+	g := new_test_grammar(t)
+
+	res, err := Run([]byte("a b c"), g)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	frozen := res.Freeze()
+
+	var wg sync.WaitGroup
+
+	got := make([]string, 4)
+
+	for i := range got {
+		wg.Add(2)
+
+		// The readers of the frozen result run alongside new runs that reuse the
+		// lexer and the parser of the first one.
+		go func() {
+			defer wg.Done()
+
+			got[i] = fmt.Sprint(words_of(frozen))
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_, _ = Run([]byte("d e"), g)
+		}()
+	}
+
+	wg.Wait()
+
+	for i, words := range got {
+		if words != "[a b c]" {
+			t.Errorf("reader %d: expected the words [a b c], got %s", i, words)
+		}
+	}
+}
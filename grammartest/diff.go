@@ -0,0 +1,126 @@
+package grammartest
+
+import (
+	"bytes"
+	"strings"
+)
+
+// max_diff_cells is the maximum size of the table of the longest common
+// subsequence of the lines of a diff. Beyond it, only the first different line is
+// shown.
+const max_diff_cells = 1 << 22
+
+// split_lines is a helper function that splits a text into lines, without their
+// newlines.
+//
+// Parameters:
+//   - data: The text.
+//
+// Returns:
+//   - []string: The lines. Nil if the text is empty.
+func split_lines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// Diff returns the line-by-line differences between an expected and an actual
+// text. The common lines are prefixed by two spaces, the missing ones by "- " and
+// the extra ones by "+ ".
+//
+// Parameters:
+//   - want: The expected text.
+//   - got: The actual text.
+//
+// Returns:
+//   - string: The differences. Empty if the texts are equal.
+//
+// For very large texts, only the first different line is shown.
+func Diff(want, got []byte) string {
+	if bytes.Equal(want, got) {
+		return ""
+	}
+
+	a := split_lines(want)
+	b := split_lines(got)
+
+	if (len(a)+1)*(len(b)+1) > max_diff_cells {
+		return first_difference(a, b)
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var builder strings.Builder
+
+	i, j := 0, 0
+	changed := false
+
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			builder.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			builder.WriteString("+ " + b[j] + "\n")
+			j++
+			changed = true
+		default:
+			builder.WriteString("- " + a[i] + "\n")
+			i++
+			changed = true
+		}
+	}
+
+	if !changed {
+		return "(the texts only differ by their final newline)\n"
+	}
+
+	return builder.String()
+}
+
+// first_difference is a helper function that shows the first line that differs
+// between two texts.
+//
+// Parameters:
+//   - a: The lines of the expected text.
+//   - b: The lines of the actual text.
+//
+// Returns:
+//   - string: The difference.
+func first_difference(a, b []string) string {
+	i := 0
+
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	var builder strings.Builder
+
+	if i < len(a) {
+		builder.WriteString("- " + a[i] + "\n")
+	}
+
+	if i < len(b) {
+		builder.WriteString("+ " + b[i] + "\n")
+	}
+
+	return builder.String()
+}
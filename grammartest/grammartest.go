@@ -0,0 +1,484 @@
+// Package grammartest runs golden tests on grammars: every input of a corpus is
+// lexed and parsed, and the tokens, the trees and the error are compared with
+// snapshots. Running the tests with the -update flag rewrites the snapshots of a
+// corpus directory instead, so that regression suites of language implementations
+// are cheap to maintain.
+//
+// A corpus directory is laid out as the ones of the corpus package: every
+// "<name>.input" file is a case, whose snapshots are "<name>.tokens", the tree
+// ("<name>.tree.sexp" or "<name>.tree.json", see Format) and "<name>.error".
+package grammartest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	grammar "github.com/PlayerR9/grammar"
+	"github.com/PlayerR9/grammar/corpus"
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// UpdateFlag is the -update flag of the tests. If true, the snapshots of the
+// corpus directories are rewritten instead of compared.
+var UpdateFlag = flag.Bool("update", false, "Rewrite the snapshots of the golden tests instead of comparing them.")
+
+// ErrorExt is the extension of the error snapshots of a corpus directory.
+const ErrorExt string = ".error"
+
+// Format is the format of the tree snapshots.
+type Format int
+
+const (
+	// FormatSExpr encodes the trees as by grammar.ForestToSExpr, in
+	// "<name>.tree.sexp" files.
+	FormatSExpr Format = iota
+
+	// FormatJSON encodes the trees as by grammar.ForestToJSON, indented, in
+	// "<name>.tree.json" files. This is the format of the corpus archives.
+	FormatJSON
+)
+
+// String implements the fmt.Stringer interface.
+func (f Format) String() string {
+	switch f {
+	case FormatSExpr:
+		return "S-expression"
+	case FormatJSON:
+		return "JSON"
+	default:
+		return "Format(" + strconv.Itoa(int(f)) + ")"
+	}
+}
+
+// Ext returns the extension of the tree snapshots of the format.
+//
+// Returns:
+//   - string: The extension. (e.g., ".tree.sexp")
+func (f Format) Ext() string {
+	if f == FormatJSON {
+		return corpus.TreeExt
+	}
+
+	return ".tree.sexp"
+}
+
+// encode is a helper function that encodes a forest in a format.
+//
+// Parameters:
+//   - f: The format.
+//   - forest: The forest.
+//
+// Returns:
+//   - []byte: The encoding, with a final newline.
+//   - error: An error if the forest could not be encoded.
+func encode[T gr.Enumer](f Format, forest []*gr.Token[T]) ([]byte, error) {
+	if f != FormatJSON {
+		return gr.ForestToSExpr(forest), nil
+	}
+
+	data, err := gr.ForestToJSON(forest)
+	if err != nil {
+		return nil, err
+	}
+
+	return indent_json(data)
+}
+
+// indent_json is a helper function that indents a JSON document by two spaces and
+// ends it with a newline, so that it diffs line by line.
+//
+// Parameters:
+//   - data: The document.
+//
+// Returns:
+//   - []byte: The indented document.
+//   - error: An error if data is not valid JSON.
+func indent_json(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := json.Indent(&buf, bytes.TrimSpace(data), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
+
+// RunFunc lexes and parses an input.
+//
+// Parameters:
+//   - input: The input.
+//
+// Returns:
+//   - []*gr.Token[T]: The tokens that were lexed, EOF included.
+//   - gr.Result[*gr.Token[T]]: The result of the parse. The lexing errors are
+//     failures of the result.
+type RunFunc[T gr.Enumer] func(input []byte) ([]*gr.Token[T], gr.Result[*gr.Token[T]])
+
+// FromGrammar returns the run function of a compiled grammar.
+//
+// Parameters:
+//   - g: The grammar.
+//
+// Returns:
+//   - RunFunc[T]: The run function.
+//   - error: An error of type *errors.ErrInvalidParameter if g is nil.
+func FromGrammar[T gr.Enumer](g *grammar.CompiledGrammar[T]) (RunFunc[T], error) {
+	if g == nil {
+		return nil, gcers.NewErrNilParameter("g")
+	}
+
+	fn := func(input []byte) ([]*gr.Token[T], gr.Result[*gr.Token[T]]) {
+		s, _ := grammar.NewEditorSession(g, input)
+
+		return s.Tokens(), s.Result()
+	}
+
+	return fn, nil
+}
+
+// Suite is a golden test suite of a grammar.
+type Suite[T gr.Enumer] struct {
+	// run is the run function of the grammar.
+	run RunFunc[T]
+
+	// format is the format of the tree snapshots of the corpus directories.
+	format Format
+
+	// tokens is true if the token streams are always checked.
+	tokens bool
+}
+
+// NewSuite creates a new golden test suite. The token streams are only checked
+// for the cases that have a token snapshot; see SetTokens.
+//
+// Parameters:
+//   - run: The run function of the grammar. (e.g., the one of FromGrammar)
+//   - format: The format of the tree snapshots of the corpus directories.
+//
+// Returns:
+//   - *Suite[T]: The new suite.
+//   - error: An error of type *errors.ErrInvalidParameter if run is nil.
+func NewSuite[T gr.Enumer](run RunFunc[T], format Format) (*Suite[T], error) {
+	if run == nil {
+		return nil, gcers.NewErrNilParameter("run")
+	}
+
+	return &Suite[T]{
+		run:    run,
+		format: format,
+	}, nil
+}
+
+// SetTokens sets whether the token streams of every case of a corpus directory
+// are checked, in which case -update writes the missing token snapshots.
+//
+// Parameters:
+//   - check: True to check every token stream, false to only check the ones that
+//     have a snapshot.
+func (s *Suite[T]) SetTokens(check bool) {
+	s.tokens = check
+}
+
+// outcome is the outcome of a case.
+type outcome struct {
+	// tokens is the token stream, in the encoding of grammar.NewTokenReader.
+	tokens []byte
+
+	// tree is the forest, in the format of the suite.
+	tree []byte
+
+	// err is the error, with a final newline. Nil on success.
+	err []byte
+}
+
+// outcome_of is a helper function that runs a case.
+//
+// Parameters:
+//   - input: The input of the case.
+//   - format: The format of the forest.
+//
+// Returns:
+//   - outcome: The outcome of the case.
+//   - error: An error if the outcome could not be encoded.
+func (s Suite[T]) outcome_of(input []byte, format Format) (outcome, error) {
+	tokens, res := s.run(input)
+
+	var out outcome
+
+	data, err := io.ReadAll(gr.NewTokenReader(tokens))
+	if err != nil {
+		return out, err
+	}
+
+	out.tokens = data
+
+	out.tree, err = encode(format, res.Forest)
+	if err != nil {
+		return out, err
+	}
+
+	if res.Err != nil {
+		out.err = []byte(res.Err.Error() + "\n")
+	}
+
+	return out, nil
+}
+
+// normalize is a helper function that turns the line endings of a snapshot into
+// newlines and ends it with a newline, so that snapshots edited by hand compare
+// equal.
+//
+// Parameters:
+//   - data: The snapshot.
+//
+// Returns:
+//   - []byte: The normalized snapshot. Nil if data is empty.
+func normalize(data []byte) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	if data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+
+	return data
+}
+
+// compare is a helper function that reports the differences between a snapshot
+// and the outcome of a case.
+//
+// Parameters:
+//   - t: The test of the case.
+//   - what: The name of the snapshot. (e.g., "sum.tokens")
+//   - want: The snapshot. Nil if there is none.
+//   - got: The outcome.
+func compare(t *testing.T, what string, want, got []byte) {
+	t.Helper()
+
+	want = normalize(want)
+	got = normalize(got)
+
+	if !bytes.Equal(want, got) {
+		t.Errorf("%s differs (-want +got):\n%s", what, Diff(want, got))
+	}
+}
+
+// RunDir runs the cases of a corpus directory, each in its own subtest. With the
+// -update flag, the snapshots are rewritten instead: the tree and the error
+// snapshots always, and the token snapshots if they exist or if the suite checks
+// every token stream. The error snapshots of the successful cases are removed.
+//
+// Parameters:
+//   - t: The test.
+//   - dir: The path of the directory.
+//
+// A missing tree snapshot, or an error without a snapshot, fails the case.
+func (s Suite[T]) RunDir(t *testing.T, dir string) {
+	t.Helper()
+
+	c, err := corpus.FromDir(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("failed to read corpus %q: %v", dir, err)
+	}
+
+	if len(c.Cases) == 0 {
+		t.Fatalf("corpus %q has no case", dir)
+	}
+
+	for _, tc := range c.Cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			s.run_dir_case(t, dir, tc)
+		})
+	}
+}
+
+// run_dir_case is a helper function that runs a case of a corpus directory.
+//
+// Parameters:
+//   - t: The test of the case.
+//   - dir: The path of the directory.
+//   - tc: The case.
+func (s Suite[T]) run_dir_case(t *testing.T, dir string, tc corpus.Case) {
+	t.Helper()
+
+	out, err := s.outcome_of(tc.Input, s.format)
+	if err != nil {
+		t.Fatalf("failed to encode the outcome: %v", err)
+	}
+
+	base := filepath.Join(dir, tc.Name)
+
+	want_tree, err := read_snapshot(base + s.format.Ext())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want_tree != nil && s.format == FormatJSON {
+		want_tree, err = indent_json(want_tree)
+		if err != nil {
+			t.Fatalf("invalid snapshot %s: %v", base+s.format.Ext(), err)
+		}
+	}
+
+	want_err, err := read_snapshot(base + ErrorExt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *UpdateFlag {
+		files := map[string][]byte{
+			s.format.Ext(): out.tree,
+			ErrorExt:       out.err,
+		}
+
+		if s.tokens || tc.Tokens != nil {
+			files[corpus.TokensExt] = out.tokens
+		}
+
+		for ext, data := range files {
+			err := write_snapshot(base+ext, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return
+	}
+
+	if want_tree == nil {
+		t.Errorf("missing snapshot %s; run the tests with -update to write it", base+s.format.Ext())
+	} else {
+		compare(t, tc.Name+s.format.Ext(), want_tree, out.tree)
+	}
+
+	if tc.Tokens != nil {
+		compare(t, tc.Name+corpus.TokensExt, tc.Tokens, out.tokens)
+	} else if s.tokens {
+		t.Errorf("missing snapshot %s; run the tests with -update to write it", base+corpus.TokensExt)
+	}
+
+	compare(t, tc.Name+ErrorExt, want_err, out.err)
+}
+
+// read_snapshot is a helper function that reads a snapshot that may not exist.
+//
+// Parameters:
+//   - path: The path of the snapshot.
+//
+// Returns:
+//   - []byte: The snapshot. Nil if it does not exist.
+//   - error: An error if the snapshot exists but could not be read.
+func read_snapshot(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	return data, err
+}
+
+// write_snapshot is a helper function that writes a snapshot, or removes it if it
+// is empty.
+//
+// Parameters:
+//   - path: The path of the snapshot.
+//   - data: The snapshot.
+//
+// Returns:
+//   - error: An error if the snapshot could not be written or removed.
+func write_snapshot(path string, data []byte) error {
+	if len(data) > 0 {
+		return os.WriteFile(path, data, 0o644)
+	}
+
+	err := os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// RunCorpus runs the cases of a corpus, each in its own subtest. The trees are
+// compared in the JSON format of the corpora, the token streams if the case has
+// some, and the cases must not fail, as corpora have no error snapshot. The
+// -update flag has no effect.
+//
+// Parameters:
+//   - t: The test.
+//   - c: The corpus.
+func (s Suite[T]) RunCorpus(t *testing.T, c *corpus.Corpus) {
+	t.Helper()
+
+	if c == nil {
+		t.Fatal(gcers.NewErrNilParameter("c"))
+	}
+
+	for _, tc := range c.Cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			out, err := s.outcome_of(tc.Input, FormatJSON)
+			if err != nil {
+				t.Fatalf("failed to encode the outcome: %v", err)
+			}
+
+			if out.err != nil {
+				t.Errorf("expected no error, got %s", strings.TrimSuffix(string(out.err), "\n"))
+			}
+
+			if tc.Tree != nil {
+				want, err := indent_json(tc.Tree)
+				if err != nil {
+					t.Fatalf("invalid tree of the case: %v", err)
+				}
+
+				compare(t, tc.Name+"/"+corpus.TreeName, want, out.tree)
+			}
+
+			if tc.Tokens != nil {
+				compare(t, tc.Name+"/"+corpus.TokensName, tc.Tokens, out.tokens)
+			}
+		})
+	}
+}
+
+// RunArchive reads a corpus archive, verifying its checksums, and runs its cases.
+// See RunCorpus.
+//
+// Parameters:
+//   - t: The test.
+//   - path: The path of the archive.
+func (s Suite[T]) RunArchive(t *testing.T, path string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read archive: %v", err)
+	}
+
+	c, err := corpus.Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("invalid archive %q: %v", path, err)
+	}
+
+	if *UpdateFlag {
+		t.Logf("archive %q is not updated; rebuild it from its directory", path)
+	}
+
+	s.RunCorpus(t, c)
+}
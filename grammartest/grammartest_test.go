@@ -0,0 +1,151 @@
+package grammartest
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PlayerR9/grammar/corpus"
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// test_type is the token type of the test grammar.
+type test_type int
+
+const (
+	tt_eof test_type = iota
+	tt_word
+	nt_list
+)
+
+// String implements the fmt.Stringer interface.
+func (t test_type) String() string {
+	return [...]string{"EOF", "WORD", "List"}[t]
+}
+
+// test_run lexes the words of the input and parses them into a list. A word
+// starting with '!' is a syntax error.
+func test_run(input []byte) ([]*gr.Token[test_type], gr.Result[*gr.Token[test_type]]) {
+	var tokens, words []*gr.Token[test_type]
+
+	for i, field := range strings.Fields(string(input)) {
+		tk := gr.NewTerminalToken(tt_word, field)
+		tk.Pos = i
+
+		tokens = append(tokens, tk)
+
+		if strings.HasPrefix(field, "!") {
+			return tokens, gr.NewFailedResult(words, errors.New("unexpected "+field))
+		}
+
+		words = append(words, tk)
+	}
+
+	root, _ := gr.NewToken(nt_list, "", words)
+
+	return tokens, gr.NewResult(root)
+}
+
+func TestForestToSExpr(t *testing.T) {
+	_, res := test_run([]byte("a b"))
+
+	got := string(gr.ForestToSExpr(append(res.Forest, nil)))
+
+	const want = "(List\n  (WORD \"a\")\n  (WORD \"b\"))\n()\n"
+
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	got := Diff([]byte("a\nb\nc\n"), []byte("a\nc\nd\n"))
+
+	const want = "  a\n- b\n  c\n+ d\n"
+
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if got := Diff([]byte("a\n"), []byte("a\n")); got != "" {
+		t.Errorf("expected no difference, got %q", got)
+	}
+}
+
+func TestRunDir(t *testing.T) {
+	dir := t.TempDir()
+
+	inputs := map[string]string{
+		"words": "hello world",
+		"bang":  "hello !world",
+	}
+
+	for name, input := range inputs {
+		err := os.WriteFile(filepath.Join(dir, name+corpus.InputExt), []byte(input), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, format := range []Format{FormatSExpr, FormatJSON} {
+		s, err := NewSuite(test_run, format)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		s.SetTokens(true)
+
+		*UpdateFlag = true
+		s.RunDir(t, dir)
+		*UpdateFlag = false
+
+		tree, err := os.ReadFile(filepath.Join(dir, "words"+format.Ext()))
+		if err != nil {
+			t.Fatalf("%s: expected the tree snapshot, got %v", format, err)
+		}
+
+		if !bytes.Contains(tree, []byte("world")) {
+			t.Errorf("%s: unexpected tree snapshot %q", format, tree)
+		}
+
+		_, err = os.Stat(filepath.Join(dir, "words"+ErrorExt))
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%s: expected no error snapshot, got %v", format, err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, "bang"+ErrorExt))
+		if err != nil || string(data) != "unexpected !world\n" {
+			t.Errorf("%s: unexpected error snapshot %q (%v)", format, data, err)
+		}
+
+		s.RunDir(t, dir)
+	}
+}
+
+func TestRunCorpus(t *testing.T) {
+	tokens, res := test_run([]byte("a b"))
+
+	tree, err := gr.ForestToJSON(res.Forest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	_, _ = buf.ReadFrom(gr.NewTokenReader(tokens))
+
+	c, err := corpus.New(corpus.Case{Name: "ab", Input: []byte("a b"), Tokens: buf.Bytes(), Tree: tree})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewSuite(test_run, FormatSExpr)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	s.RunCorpus(t, c)
+}
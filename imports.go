@@ -0,0 +1,292 @@
+package grammar
+
+import (
+	"slices"
+	"sync"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// ImportFunc extracts the names of the files that a parsed file imports (or
+// includes, requires, ...).
+//
+// Parameters:
+//   - file: The parsed file. Never nil.
+//
+// Returns:
+//   - []string: The names of the imported files.
+//
+// The function may be called concurrently for different files.
+type ImportFunc[T gr.Enumer] func(file *File[T]) []string
+
+// LoadFunc loads the content of an imported file that was not added to the project.
+//
+// Parameters:
+//   - name: The name of the file.
+//
+// Returns:
+//   - []byte: The content of the file.
+//   - error: An error if the file could not be loaded.
+type LoadFunc func(name string) ([]byte, error)
+
+// SetImportFunc sets the function that extracts the imports of the parsed files.
+//
+// Parameters:
+//   - fn: The function. If nil, ParseImports behaves as if no file imports another.
+func (p *Project[T]) SetImportFunc(fn ImportFunc[T]) {
+	if p == nil {
+		return
+	}
+
+	p.import_fn = fn
+}
+
+// SetLoadFunc sets the function that loads the imported files that were not added
+// to the project.
+//
+// Parameters:
+//   - fn: The function. If nil, the imports of files that were not added are ignored.
+func (p *Project[T]) SetLoadFunc(fn LoadFunc) {
+	if p == nil {
+		return
+	}
+
+	p.load_fn = fn
+}
+
+// ParseImports parses every file that was not parsed yet, extracts the imports of
+// the files whose imports were not extracted yet, loads and parses the imported
+// files, and so on until every import is resolved. Files are parsed in parallel
+// when the project has several instances of the grammar.
+//
+// Since the imports of a file are only known once it is parsed, the files are
+// parsed in the order they are discovered, not in the order of their imports. Only
+// the returned files are sorted, so that the steps that need the imported files
+// first (name resolution, type checking, ...) can walk them in order.
+//
+// Returns:
+//   - []*File[T]: The files, in topological order: every file comes after the
+//     files it imports and, among the files whose imports are all placed, the one
+//     that was added first comes first.
+//   - error: An error of type *ErrImportCycle if the imports form a cycle.
+//
+// A file that could not be loaded is added with a failed result whose error is the
+// reason and without imports; it is reported by Diagnostics like any other
+// failure. Imports of files that are not in the project are ignored.
+func (p *Project[T]) ParseImports() ([]*File[T], error) {
+	if p == nil {
+		return nil, gcers.NilReceiver
+	}
+
+	for {
+		var pending, unresolved []*File[T]
+
+		for _, file := range p.files {
+			if !file.parsed {
+				pending = append(pending, file)
+			} else if !file.resolved {
+				unresolved = append(unresolved, file)
+			}
+		}
+
+		if len(pending) == 0 && len(unresolved) == 0 {
+			break
+		}
+
+		p.parse_all(pending)
+
+		for _, file := range unresolved {
+			p.extract(file)
+		}
+
+		for _, file := range slices.Concat(pending, unresolved) {
+			p.resolve(file)
+		}
+	}
+
+	return p.order()
+}
+
+// parse_all is a helper function that parses the given files and extracts their
+// imports, using one worker per instance of the grammar.
+//
+// Parameters:
+//   - files: The files to parse.
+func (p *Project[T]) parse_all(files []*File[T]) {
+	queue := make(chan *File[T])
+
+	var wg sync.WaitGroup
+
+	for _, g := range p.grammars {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for file := range queue {
+				p.parse(g, file)
+				p.extract(file)
+			}
+		}()
+	}
+
+	for _, file := range files {
+		queue <- file
+	}
+
+	close(queue)
+
+	wg.Wait()
+}
+
+// extract is a helper function that extracts the imports of a parsed file. Without
+// an import function, the file imports nothing.
+//
+// Parameters:
+//   - file: The parsed file.
+func (p *Project[T]) extract(file *File[T]) {
+	file.Imports = nil
+
+	if p.import_fn != nil {
+		file.Imports = p.import_fn(file)
+	}
+
+	file.resolved = true
+}
+
+// resolve is a helper function that adds the files imported by the given file that
+// are not in the project yet.
+//
+// Parameters:
+//   - file: The parsed file.
+func (p *Project[T]) resolve(file *File[T]) {
+	if p.load_fn == nil {
+		return
+	}
+
+	for _, name := range file.Imports {
+		if _, ok := p.index[name]; ok {
+			continue
+		}
+
+		data, err := p.load_fn(name)
+
+		_ = p.AddFile(name, data)
+
+		if err != nil {
+			imported := p.files[p.index[name]]
+
+			imported.Result = gr.NewFailedResult[*gr.Token[T]](nil, err)
+			imported.parsed = true
+			imported.resolved = true
+		}
+	}
+}
+
+// order is a helper function that sorts the files in topological order.
+//
+// Returns:
+//   - []*File[T]: The sorted files.
+//   - error: An error of type *ErrImportCycle if the imports form a cycle.
+func (p Project[T]) order() ([]*File[T], error) {
+	// waiting[i] is the number of files that file i imports and that are not
+	// placed yet; importers[i] are the files that import file i.
+	waiting := make([]int, len(p.files))
+	importers := make([][]int, len(p.files))
+
+	for i, file := range p.files {
+		seen := make(map[int]bool, len(file.Imports))
+
+		for _, name := range file.Imports {
+			dep, ok := p.index[name]
+			if !ok || seen[dep] {
+				continue // not part of the project or already counted
+			}
+
+			seen[dep] = true
+
+			waiting[i]++
+			importers[dep] = append(importers[dep], i)
+		}
+	}
+
+	// ready are the files whose imports are all placed, by order of addition.
+	var ready []int
+
+	for i, count := range waiting {
+		if count == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	order := make([]*File[T], 0, len(p.files))
+
+	for len(ready) > 0 {
+		idx := ready[0]
+		ready = ready[1:]
+
+		order = append(order, p.files[idx])
+
+		for _, importer := range importers[idx] {
+			waiting[importer]--
+
+			if waiting[importer] == 0 {
+				pos, _ := slices.BinarySearch(ready, importer)
+				ready = slices.Insert(ready, pos, importer)
+			}
+		}
+	}
+
+	if len(order) < len(p.files) {
+		return nil, NewErrImportCycle(p.cycle(waiting))
+	}
+
+	return order, nil
+}
+
+// cycle is a helper function that finds a cycle among the files that could not be
+// placed. Each of them imports at least one other such file, so following those
+// imports from any of them must eventually come back to a file of the path.
+//
+// Parameters:
+//   - waiting: The number of imports of each file that are not placed.
+//
+// Returns:
+//   - []string: The names of the files of the cycle. The first and the last ones
+//     are the same.
+func (p Project[T]) cycle(waiting []int) []string {
+	curr := slices.IndexFunc(waiting, func(count int) bool {
+		return count > 0
+	})
+
+	var path []int
+
+	at := make(map[int]int)
+
+	for {
+		if pos, ok := at[curr]; ok {
+			path = append(path[pos:], curr)
+			break
+		}
+
+		at[curr] = len(path)
+		path = append(path, curr)
+
+		for _, name := range p.files[curr].Imports {
+			dep, ok := p.index[name]
+			if ok && waiting[dep] > 0 {
+				curr = dep
+				break
+			}
+		}
+	}
+
+	names := make([]string, 0, len(path))
+
+	for _, idx := range path {
+		names = append(names, p.files[idx].Name)
+	}
+
+	return names
+}
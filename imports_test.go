@@ -0,0 +1,179 @@
+package grammar
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// names_of is a helper function that returns the names of the given files.
+func names_of(files []*File[test_type]) []string {
+	names := make([]string, 0, len(files))
+
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+
+	return names
+}
+
+// new_test_project is a helper function that makes a project whose files import
+// the files named by their words.
+func new_test_project(t *testing.T, files ...[2]string) *Project[test_type] {
+	p, err := NewProject(new_test_grammar(t), new_test_grammar(t))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	p.SetImportFunc(func(file *File[test_type]) []string {
+		return words_of(file.Result)
+	})
+
+	for _, file := range files {
+		err := p.AddFile(file[0], []byte(file[1]))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	return p
+}
+
+func TestParseImports(t *testing.T) {
+	p := new_test_project(t,
+		[2]string{"a", "c"},
+		[2]string{"b", ""},
+		[2]string{"c", "d"},
+	)
+
+	p.SetLoadFunc(func(name string) ([]byte, error) {
+		if name == "d" {
+			return []byte("b"), nil
+		}
+
+		return nil, errors.New("not found")
+	})
+
+	files, err := p.ParseImports()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// b is placed first as it was added before d, which imports it.
+	want := []string{"b", "d", "c", "a"}
+
+	if got := names_of(files); !slices.Equal(got, want) {
+		t.Errorf("expected the order %v, got %v", want, got)
+	}
+
+	p = new_test_project(t,
+		[2]string{"a", "c"},
+		[2]string{"b", ""},
+		[2]string{"c", ""},
+	)
+
+	files, err = p.ParseImports()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// b and c do not depend on each other, so they keep the order they were added in.
+	want = []string{"b", "c", "a"}
+
+	if got := names_of(files); !slices.Equal(got, want) {
+		t.Errorf("expected the order %v, got %v", want, got)
+	}
+}
+
+func TestParseImportsParsedFiles(t *testing.T) {
+	p := new_test_project(t,
+		[2]string{"a", "b"},
+		[2]string{"b", ""},
+	)
+
+	if !p.Parse() {
+		t.Fatalf("expected the files to parse, got %v", p.Diagnostics())
+	}
+
+	files, err := p.ParseImports()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got := names_of(files); !slices.Equal(got, []string{"b", "a"}) {
+		t.Errorf("expected the imports of parsed files to be extracted, got the order %v", got)
+	}
+
+	a, _ := p.File("a")
+
+	p.SetImportFunc(nil)
+
+	err = p.AddFile("c", []byte("a"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err = p.ParseImports()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	c, _ := p.File("c")
+
+	if c.Imports != nil {
+		t.Errorf("expected no imports without an import function, got %v", c.Imports)
+	} else if !slices.Equal(a.Imports, []string{"b"}) {
+		t.Errorf("expected the imports of a to be kept, got %v", a.Imports)
+	}
+}
+
+func TestParseImportsLoadFailure(t *testing.T) {
+	p := new_test_project(t, [2]string{"a", "missing"})
+
+	p.SetLoadFunc(func(name string) ([]byte, error) {
+		return nil, errors.New("not found")
+	})
+
+	files, err := p.ParseImports()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got := names_of(files); !slices.Equal(got, []string{"missing", "a"}) {
+		t.Errorf("expected the order %v, got %v", []string{"missing", "a"}, got)
+	}
+
+	missing, _ := p.File("missing")
+
+	if missing.Result.Err == nil {
+		t.Errorf("expected the missing file to fail")
+	} else if missing.Imports != nil {
+		t.Errorf("expected the missing file to have no imports, got %v", missing.Imports)
+	}
+}
+
+func TestParseImportsCycle(t *testing.T) {
+	tests := []struct {
+		files [][2]string
+		want  []string
+	}{
+		{[][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}}, []string{"a", "b", "c", "a"}},
+		{[][2]string{{"x", ""}, {"a", "b"}, {"b", "b"}}, []string{"b", "b"}},
+	}
+
+	for _, test := range tests {
+		p := new_test_project(t, test.files...)
+
+		_, err := p.ParseImports()
+
+		var cycle *ErrImportCycle
+
+		if !errors.As(err, &cycle) {
+			t.Fatalf("expected an *ErrImportCycle, got %v", err)
+		}
+
+		if !slices.Equal(cycle.Files, test.want) {
+			t.Errorf("expected the cycle %v, got %v", test.want, cycle.Files)
+		}
+	}
+}
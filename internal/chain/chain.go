@@ -0,0 +1,52 @@
+// Package chain contains the handling of the lookahead chains of tokens, shared by
+// the token types of every generation of the grammar.
+package chain
+
+// Link sets the link of every element to the element that follows it and clears
+// the link of the last one, so that no chain leaks from a previous linking. Nil
+// elements are skipped.
+//
+// Parameters:
+//   - elems: The elements, in order.
+//   - link: The function that returns the link field of an element.
+func Link[E any](elems []*E, link func(elem *E) **E) {
+	var prev *E
+
+	for _, elem := range elems {
+		if elem == nil {
+			continue
+		}
+
+		if prev != nil {
+			*link(prev) = elem
+		}
+
+		prev = elem
+	}
+
+	if prev != nil {
+		*link(prev) = nil
+	}
+}
+
+// At follows k links of the chain that starts at an element.
+//
+// Parameters:
+//   - elem: The first element of the chain.
+//   - k: The number of links to follow. 0 is the element itself.
+//   - link: The function that returns the link field of an element.
+//
+// Returns:
+//   - *E: The element k links ahead.
+//   - bool: False if the chain is shorter than k or k is negative, true otherwise.
+func At[E any](elem *E, k int, link func(elem *E) **E) (*E, bool) {
+	if k < 0 {
+		return nil, false
+	}
+
+	for ; k > 0 && elem != nil; k-- {
+		elem = *link(elem)
+	}
+
+	return elem, elem != nil
+}
@@ -0,0 +1,43 @@
+package chain
+
+import (
+	"testing"
+)
+
+type node struct {
+	name string
+	next *node
+}
+
+func next_of(n *node) **node {
+	return &n.next
+}
+
+func TestLink(t *testing.T) {
+	a, b, c := &node{name: "a"}, &node{name: "b"}, &node{name: "c"}
+
+	c.next = a
+
+	Link([]*node{a, nil, b, c}, next_of)
+
+	if a.next != b || b.next != c || c.next != nil {
+		t.Fatalf("expected the chain a -> b -> c, got %v -> %v -> %v", a.next, b.next, c.next)
+	}
+
+	tests := []struct {
+		k    int
+		want *node
+	}{
+		{0, a},
+		{2, c},
+		{3, nil},
+		{-1, nil},
+	}
+
+	for _, test := range tests {
+		got, ok := At(a, test.k, next_of)
+		if got != test.want || ok != (test.want != nil) {
+			t.Errorf("k %d: expected %v, got %v (%t)", test.k, test.want, got, ok)
+		}
+	}
+}
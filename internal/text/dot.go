@@ -0,0 +1,48 @@
+package text
+
+import (
+	"strings"
+)
+
+// dot_replacer escapes the characters that are special in the strings of the DOT
+// language.
+var dot_replacer = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", ``,
+)
+
+// DOTString quotes a value as a string of the DOT language (Graphviz).
+//
+// Parameters:
+//   - value: The value to quote.
+//
+// Returns:
+//   - string: The quoted value.
+func DOTString(value string) string {
+	return `"` + dot_replacer.Replace(value) + `"`
+}
+
+// DOTLabel quotes lines as a label of the DOT language whose lines are left
+// justified.
+//
+// Parameters:
+//   - lines: The lines of the label.
+//
+// Returns:
+//   - string: The quoted label.
+func DOTLabel(lines []string) string {
+	var builder strings.Builder
+
+	builder.WriteRune('"')
+
+	for _, line := range lines {
+		builder.WriteString(dot_replacer.Replace(line))
+		builder.WriteString(`\l`)
+	}
+
+	builder.WriteRune('"')
+
+	return builder.String()
+}
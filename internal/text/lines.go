@@ -0,0 +1,144 @@
+// Package text contains the text helpers shared by the packages of the grammar:
+// line handling, searching, coordinates, and the formatting of lists in messages.
+package text
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// LimitLines keeps at most limit lines of the given data.
+//
+// Parameters:
+//   - data: The data to limit.
+//   - limit: The maximum number of lines to keep. If negative, every line is kept.
+//   - from_end: If true, the last lines are kept. Otherwise, the first ones are.
+//
+// Returns:
+//   - []byte: The limited data, without trailing or leading newline. Nil if data
+//     is empty or limit is 0. Shares memory with data.
+func LimitLines(data []byte, limit int, from_end bool) []byte {
+	if len(data) == 0 || limit == 0 {
+		return nil
+	} else if limit < 0 {
+		return data
+	}
+
+	if from_end {
+		for i := len(data) - 1; i >= 0; i-- {
+			if data[i] != '\n' {
+				continue
+			}
+
+			limit--
+
+			if limit == 0 {
+				return data[i+1:]
+			}
+		}
+
+		return data
+	}
+
+	for i, b := range data {
+		if b != '\n' {
+			continue
+		}
+
+		limit--
+
+		if limit == 0 {
+			return data[:i]
+		}
+	}
+
+	return data
+}
+
+// Index returns the index of the first occurrence of sep at or after from.
+//
+// Parameters:
+//   - data: The data to search.
+//   - from: The index to start searching from. Clamped to 0 if negative.
+//   - sep: The separator to search.
+//
+// Returns:
+//   - int: The index of the occurrence. -1 if sep is empty or not found.
+func Index(data []byte, from int, sep []byte) int {
+	if len(sep) == 0 || from >= len(data) {
+		return -1
+	} else if from < 0 {
+		from = 0
+	}
+
+	idx := bytes.Index(data[from:], sep)
+	if idx == -1 {
+		return -1
+	}
+
+	return from + idx
+}
+
+// LastIndex returns the index of the last occurrence of sep that starts at or
+// before from.
+//
+// Parameters:
+//   - data: The data to search.
+//   - from: The index to start searching from. Clamped to the last valid index.
+//   - sep: The separator to search.
+//
+// Returns:
+//   - int: The index of the occurrence. -1 if sep is empty or not found.
+func LastIndex(data []byte, from int, sep []byte) int {
+	if len(sep) == 0 || from < 0 {
+		return -1
+	}
+
+	end := min(from+len(sep), len(data))
+
+	return bytes.LastIndex(data[:end], sep)
+}
+
+// Coords returns the 0-indexed coordinates of the byte at the given position.
+//
+// Parameters:
+//   - data: The data.
+//   - pos: The byte position. Negative positions count from the end of data and
+//     positions past the end are clamped to the last byte.
+//
+// Returns:
+//   - int: The column, in runes; as the columns of the positions of the tokens.
+//     Invalid UTF-8 bytes count as one rune each.
+//   - int: The line.
+func Coords(data []byte, pos int) (int, int) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+
+	if pos < 0 {
+		pos = max(len(data)+pos, 0)
+	} else if pos >= len(data) {
+		pos = len(data) - 1
+	}
+
+	line := bytes.Count(data[:pos], []byte{'\n'})
+	column := utf8.RuneCount(data[bytes.LastIndexByte(data[:pos], '\n')+1 : pos])
+
+	return column, line
+}
+
+// ExpandTab returns the replacement of a tab character.
+//
+// Parameters:
+//   - size: The size of a tab. If not positive, tabs are not expanded.
+//   - rep: The bytes that replace each column of the tab.
+//
+// Returns:
+//   - []byte: The replacement. A single tab if size is not positive.
+func ExpandTab(size int, rep []byte) []byte {
+	if size <= 0 {
+		return []byte{'\t'}
+	}
+
+	return bytes.Repeat(rep, size)
+}
@@ -0,0 +1,125 @@
+package text
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Conjunction is the word that joins the last two elements of a list.
+type Conjunction int
+
+const (
+	// Or joins with "or" (e.g., "a, b, or c").
+	Or Conjunction = iota
+
+	// EitherOr joins with "or" and starts with "either" (e.g., "either a, b, or c").
+	EitherOr
+
+	// Nor joins with "nor" (e.g., "a, b, nor c").
+	Nor
+)
+
+// JoinList joins the non-empty values into an English list.
+//
+// Parameters:
+//   - values: The values to join.
+//   - conj: The conjunction of the list.
+//
+// Returns:
+//   - string: The list. Empty if there are no non-empty values.
+//
+// A single value is returned as is, without conjunction.
+func JoinList(values []string, conj Conjunction) string {
+	values = NonEmpty(values)
+
+	switch len(values) {
+	case 0:
+		return ""
+	case 1:
+		return values[0]
+	}
+
+	var builder strings.Builder
+
+	if conj == EitherOr {
+		builder.WriteString("either ")
+	}
+
+	builder.WriteString(strings.Join(values[:len(values)-1], ", "))
+
+	if len(values) > 2 {
+		builder.WriteRune(',')
+	}
+
+	if conj == Nor {
+		builder.WriteString(" nor ")
+	} else {
+		builder.WriteString(" or ")
+	}
+
+	builder.WriteString(values[len(values)-1])
+
+	return builder.String()
+}
+
+// NonEmpty returns the non-empty values.
+//
+// Parameters:
+//   - values: The values to filter.
+//
+// Returns:
+//   - []string: The non-empty values. Never shares memory with values.
+func NonEmpty(values []string) []string {
+	var result []string
+
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
+// Quoted returns the quoted values.
+//
+// Parameters:
+//   - values: The values to quote.
+//
+// Returns:
+//   - []string: The quoted values. Never shares memory with values.
+func Quoted(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(values))
+
+	for _, value := range values {
+		result = append(result, strconv.Quote(value))
+	}
+
+	return result
+}
+
+// Strings returns the string representation of the values.
+//
+// Parameters:
+//   - values: The values.
+//
+// Returns:
+//   - []string: The string representations.
+func Strings[T fmt.Stringer](values []T) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(values))
+
+	for _, value := range values {
+		result = append(result, value.String())
+	}
+
+	return result
+}
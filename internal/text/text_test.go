@@ -0,0 +1,214 @@
+package text
+
+import (
+	"testing"
+)
+
+type stringer int
+
+func (s stringer) String() string {
+	return "s" + string(rune('0'+s))
+}
+
+func TestLimitLines(t *testing.T) {
+	tests := []struct {
+		data     string
+		limit    int
+		from_end bool
+		want     string
+	}{
+		{"", 2, false, ""},
+		{"a\nb\nc", 0, false, ""},
+		{"a\nb\nc", -1, false, "a\nb\nc"},
+		{"a\nb\nc", 2, false, "a\nb"},
+		{"a\nb\nc", 2, true, "b\nc"},
+		{"a\nb\nc", 5, false, "a\nb\nc"},
+		{"a\nb\nc", 5, true, "a\nb\nc"},
+		{"a\n", 1, false, "a"},
+		{"a\n", 1, true, ""},
+		{"\n\n", 1, false, ""},
+	}
+
+	for _, test := range tests {
+		got := string(LimitLines([]byte(test.data), test.limit, test.from_end))
+		if got != test.want {
+			t.Errorf("LimitLines(%q, %d, %t): expected %q, got %q instead", test.data, test.limit, test.from_end, test.want, got)
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	data := []byte("ab\ncd\nef")
+
+	tests := []struct {
+		from int
+		sep  string
+		want int
+	}{
+		{0, "\n", 2},
+		{2, "\n", 2},
+		{3, "\n", 5},
+		{6, "\n", -1},
+		{-4, "\n", 2},
+		{100, "\n", -1},
+		{0, "", -1},
+		{0, "cd", 3},
+		{4, "cd", -1},
+	}
+
+	for _, test := range tests {
+		got := Index(data, test.from, []byte(test.sep))
+		if got != test.want {
+			t.Errorf("Index(%d, %q): expected %d, got %d instead", test.from, test.sep, test.want, got)
+		}
+	}
+}
+
+func TestLastIndex(t *testing.T) {
+	data := []byte("ab\ncd\nef")
+
+	tests := []struct {
+		from int
+		sep  string
+		want int
+	}{
+		{7, "\n", 5},
+		{5, "\n", 5},
+		{4, "\n", 2},
+		{1, "\n", -1},
+		{-1, "\n", -1},
+		{100, "\n", 5},
+		{7, "", -1},
+		{3, "cd", 3},
+		{2, "cd", -1},
+	}
+
+	for _, test := range tests {
+		got := LastIndex(data, test.from, []byte(test.sep))
+		if got != test.want {
+			t.Errorf("LastIndex(%d, %q): expected %d, got %d instead", test.from, test.sep, test.want, got)
+		}
+	}
+}
+
+func TestCoords(t *testing.T) {
+	data := []byte("ab\ncd\nef")
+
+	tests := []struct {
+		pos     int
+		x, y    int
+		message string
+	}{
+		{0, 0, 0, "start"},
+		{1, 1, 0, "first line"},
+		{2, 2, 0, "newline"},
+		{3, 0, 1, "second line"},
+		{7, 1, 2, "last byte"},
+		{100, 1, 2, "past the end"},
+		{-1, 1, 2, "from the end"},
+		{-100, 0, 0, "before the start"},
+	}
+
+	for _, test := range tests {
+		x, y := Coords(data, test.pos)
+		if x != test.x || y != test.y {
+			t.Errorf("%s: expected (%d, %d), got (%d, %d) instead", test.message, test.x, test.y, x, y)
+		}
+	}
+
+	x, y := Coords(nil, 5)
+	if x != 0 || y != 0 {
+		t.Errorf("empty data: expected (0, 0), got (%d, %d) instead", x, y)
+	}
+
+	// "é" is two bytes long but a single column.
+	x, y = Coords([]byte("a\nédf"), 5)
+	if x != 2 || y != 1 {
+		t.Errorf("multibyte line: expected (2, 1), got (%d, %d) instead", x, y)
+	}
+}
+
+func TestExpandTab(t *testing.T) {
+	if got := string(ExpandTab(0, []byte{' '})); got != "\t" {
+		t.Errorf("expected %q, got %q instead", "\t", got)
+	}
+
+	if got := string(ExpandTab(3, []byte{'~'})); got != "~~~" {
+		t.Errorf("expected %q, got %q instead", "~~~", got)
+	}
+}
+
+func TestJoinList(t *testing.T) {
+	tests := []struct {
+		values []string
+		conj   Conjunction
+		want   string
+	}{
+		{nil, Or, ""},
+		{[]string{"", ""}, Or, ""},
+		{[]string{"a"}, EitherOr, "a"},
+		{[]string{"a", "b"}, Or, "a or b"},
+		{[]string{"a", "", "b"}, EitherOr, "either a or b"},
+		{[]string{"a", "b", "c"}, Or, "a, b, or c"},
+		{[]string{"a", "b", "c"}, EitherOr, "either a, b, or c"},
+		{[]string{"a", "b", "c"}, Nor, "a, b, nor c"},
+	}
+
+	for _, test := range tests {
+		got := JoinList(test.values, test.conj)
+		if got != test.want {
+			t.Errorf("JoinList(%q, %d): expected %q, got %q instead", test.values, test.conj, test.want, got)
+		}
+	}
+}
+
+func TestNonEmpty(t *testing.T) {
+	values := []string{"a", "", "b"}
+
+	got := NonEmpty(values)
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("expected [a b], got %q instead", got)
+	}
+
+	if values[1] != "" {
+		t.Errorf("expected the input to be left untouched, got %q instead", values)
+	}
+}
+
+func TestQuoted(t *testing.T) {
+	values := []string{"a", "b\n"}
+
+	got := Quoted(values)
+	if len(got) != 2 || got[0] != `"a"` || got[1] != `"b\n"` {
+		t.Errorf("expected quoted values, got %q instead", got)
+	}
+
+	if values[0] != "a" {
+		t.Errorf("expected the input to be left untouched, got %q instead", values)
+	}
+
+	if Quoted(nil) != nil {
+		t.Errorf("expected nil")
+	}
+}
+
+func TestStrings(t *testing.T) {
+	got := Strings([]stringer{1, 2})
+	if len(got) != 2 || got[0] != "s1" || got[1] != "s2" {
+		t.Errorf("expected [s1 s2], got %q instead", got)
+	}
+}
+
+func TestDOTString(t *testing.T) {
+	got := DOTString("a \"b\" \\ c\n")
+	if got != `"a \"b\" \\ c\n"` {
+		t.Errorf("expected an escaped string, got %s instead", got)
+	}
+}
+
+func TestDOTLabel(t *testing.T) {
+	got := DOTLabel([]string{"a", `"b"`})
+	if got != `"a\l\"b\"\l"` {
+		t.Errorf("expected a left-justified label, got %s instead", got)
+	}
+}
@@ -0,0 +1,200 @@
+// Package tokentype contains the generation of token types, shared by the
+// tokentype, lexergen and parsergen commands so that the types they generate
+// behave the same.
+package tokentype
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Symbol is a symbol of a token type.
+type Symbol struct {
+	// Const is the name of the constant of the symbol.
+	Const string
+
+	// Name is the name of the symbol, as returned by String.
+	Name string
+}
+
+// Type is the data of a token type, as used by Template.
+type Type struct {
+	// Name is the name of the type.
+	Name string
+
+	// VarPrefix is the prefix of the unexported variables of the type.
+	VarPrefix string
+
+	// Symbols are the symbols, in order: EOF, then the terminals, then the
+	// non-terminals.
+	Symbols []Symbol
+
+	// LastTerminal is the name of the constant of the last terminal.
+	LastTerminal string
+}
+
+// New creates the data of a token type and checks the names of its symbols.
+//
+// Parameters:
+//   - type_name: The name of the type.
+//   - terminals: The names of the terminals, without EOF.
+//   - non_terminals: The names of the non-terminals.
+//
+// Returns:
+//   - *Type: The data. Nil if an error occurred.
+//   - error: An error if a name is invalid or if two symbols have the same
+//     constant. Every invalid name is reported.
+//
+// The constant of EOF is "EtEOF"; the one of a terminal is "Tt" followed by its
+// name and the one of a non-terminal "Nt" followed by its name, capitalized.
+func New(type_name string, terminals, non_terminals []string) (*Type, error) {
+	if !token.IsIdentifier(type_name) {
+		return nil, fmt.Errorf("invalid type name %q", type_name)
+	}
+
+	t := &Type{
+		Name:      type_name,
+		VarPrefix: SnakeCase(type_name),
+		Symbols:   []Symbol{{Const: "EtEOF", Name: "EOF"}},
+	}
+
+	seen := map[string]bool{"EtEOF": true, "EOF": true}
+
+	var errs []error
+
+	add := func(prefix, name string) {
+		c := ConstName(prefix, name)
+
+		switch {
+		case name == "" || !token.IsIdentifier(c):
+			errs = append(errs, fmt.Errorf("invalid name %q", name))
+		case seen[c] || seen[name]:
+			errs = append(errs, fmt.Errorf("name %q is already used", name))
+		default:
+			t.Symbols = append(t.Symbols, Symbol{Const: c, Name: name})
+		}
+
+		seen[c] = true
+		seen[name] = true
+	}
+
+	for _, name := range terminals {
+		add("Tt", name)
+	}
+
+	t.LastTerminal = t.Symbols[len(t.Symbols)-1].Const
+
+	for _, name := range non_terminals {
+		add("Nt", name)
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// ConstName gives the name of the constant of a symbol.
+//
+// Parameters:
+//   - prefix: The prefix of the constant ("Tt" for terminals, "Nt" for
+//     non-terminals).
+//   - name: The name of the symbol.
+//
+// Returns:
+//   - string: The name of the constant.
+func ConstName(prefix, name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+
+	return prefix + string(unicode.ToUpper(r)) + name[size:]
+}
+
+// SnakeCase turns a type name into snake case; for instance, "TokenType" into
+// "token_type".
+//
+// Parameters:
+//   - name: The type name.
+//
+// Returns:
+//   - string: The name in snake case.
+func SnakeCase(name string) string {
+	var builder strings.Builder
+
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				prev, _ := utf8.DecodeLastRuneInString(name[:i])
+				if !unicode.IsUpper(prev) && prev != '_' {
+					builder.WriteRune('_')
+				}
+			}
+
+			r = unicode.ToLower(r)
+		}
+
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+// Template is the template of the declarations of a token type, executed with a
+// *Type. It has neither the package clause nor the imports; the file must import
+// "strconv".
+const Template = `{{ $t := . -}}
+// {{ $t.Name }} is the type of the tokens. EOF comes first, then the terminals,
+// then the non-terminals.
+type {{ $t.Name }} int
+
+const (
+{{- range $i, $s := $t.Symbols }}
+	{{ if eq $i 0 }}{{ $s.Const }} {{ $t.Name }} = iota{{ else }}{{ $s.Const }}{{ end }}
+{{- end }}
+)
+
+// {{ $t.VarPrefix }}_names are the names of the symbols.
+var {{ $t.VarPrefix }}_names = [...]string{
+{{- range $t.Symbols }}
+	{{ printf "%q" .Name }},
+{{- end }}
+}
+
+// {{ $t.VarPrefix }}_consts are the names of the constants of the symbols.
+var {{ $t.VarPrefix }}_consts = [...]string{
+{{- range $t.Symbols }}
+	{{ printf "%q" .Const }},
+{{- end }}
+}
+
+// String implements the fmt.Stringer interface.
+func (t {{ $t.Name }}) String() string {
+	if t < 0 || int(t) >= len({{ $t.VarPrefix }}_names) {
+		return "{{ $t.Name }}(" + strconv.Itoa(int(t)) + ")"
+	}
+
+	return {{ $t.VarPrefix }}_names[t]
+}
+
+// GoString implements the fmt.GoStringer interface.
+func (t {{ $t.Name }}) GoString() string {
+	if t < 0 || int(t) >= len({{ $t.VarPrefix }}_consts) {
+		return "{{ $t.Name }}(" + strconv.Itoa(int(t)) + ")"
+	}
+
+	return {{ $t.VarPrefix }}_consts[t]
+}
+
+// IsTerminal checks whether the symbol is a terminal. EOF is a terminal.
+//
+// Returns:
+//   - bool: True if the symbol is a terminal, false otherwise.
+func (t {{ $t.Name }}) IsTerminal() bool {
+	return t <= {{ $t.LastTerminal }}
+}
+`
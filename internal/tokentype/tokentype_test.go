@@ -0,0 +1,94 @@
+package tokentype
+
+import (
+	"go/format"
+	"slices"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestNew(t *testing.T) {
+	tt, err := New("TokenType", []string{"plus", "Number"}, []string{"expr"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := []Symbol{
+		{Const: "EtEOF", Name: "EOF"},
+		{Const: "TtPlus", Name: "plus"},
+		{Const: "TtNumber", Name: "Number"},
+		{Const: "NtExpr", Name: "expr"},
+	}
+
+	if !slices.Equal(tt.Symbols, want) {
+		t.Errorf("expected the symbols %v, got %v", want, tt.Symbols)
+	}
+
+	if tt.LastTerminal != "TtNumber" {
+		t.Errorf("expected the last terminal TtNumber, got %s", tt.LastTerminal)
+	}
+
+	if tt.VarPrefix != "token_type" {
+		t.Errorf("expected the prefix token_type, got %s", tt.VarPrefix)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	tests := []struct {
+		terminals     []string
+		non_terminals []string
+	}{
+		{[]string{"plus", "Plus"}, nil},
+		{[]string{"plus"}, []string{"plus"}},
+		{[]string{"EOF"}, nil},
+		{[]string{"a-b"}, nil},
+		{[]string{""}, nil},
+	}
+
+	for _, test := range tests {
+		_, err := New("TokenType", test.terminals, test.non_terminals)
+		if err == nil {
+			t.Errorf("%v %v: expected an error, got nil", test.terminals, test.non_terminals)
+		}
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	tt, err := New("Kind", []string{"word"}, []string{"list"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	templ, err := template.New("").Parse(Template)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString("package p\n\nimport \"strconv\"\n")
+
+	err = templ.Execute(&builder, tt)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	data, err := format.Source([]byte(builder.String()))
+	if err != nil {
+		t.Fatalf("expected valid Go source, got %v", err)
+	}
+
+	for _, want := range []string{
+		"func (t Kind) String() string",
+		"func (t Kind) GoString() string",
+		"func (t Kind) IsTerminal() bool",
+		"return t <= TtWord",
+		"var kind_names = [...]string{\n\t\"EOF\",\n\t\"word\",\n\t\"list\",\n}",
+		"var kind_consts = [...]string{\n\t\"EtEOF\",\n\t\"TtWord\",\n\t\"NtList\",\n}",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected the source to contain %q, got:\n%s", want, data)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package lexer
 
 import (
 	"fmt"
+	"maps"
 	"unicode/utf8"
 
 	gcch "github.com/PlayerR9/go-commons/runes"
@@ -28,6 +29,9 @@ type Builder[T gr.Enumer] struct {
 	// def_fn is the default function to call for unrecognized tokens.
 	// If it is nil, then it is ignored.
 	def_fn LexFunc[T]
+
+	// values are the value functions of the token types.
+	values map[T]ValueFunc
 }
 
 func (b *Builder[T]) validate() error {
@@ -207,6 +211,32 @@ func (b *Builder[T]) RegisterDefault(fn LexFunc[T]) {
 	b.def_fn = fn
 }
 
+// RegisterValue registers the value function of a token type. Every token of that
+// type that the lexer makes, including by Relex, gets the value computed from its
+// data; so, downstream code does not parse the same data again.
+//
+// Parameters:
+//   - type_: The type of the tokens.
+//   - fn: The value function. If nil, the previous value function of type_ is
+//     cleared.
+func (b *Builder[T]) RegisterValue(type_ T, fn ValueFunc) {
+	if b == nil {
+		return
+	}
+
+	if fn == nil {
+		delete(b.values, type_)
+
+		return
+	}
+
+	if b.values == nil {
+		b.values = make(map[T]ValueFunc)
+	}
+
+	b.values[type_] = fn
+}
+
 // Build builds a new Lexer instance.
 //
 // Returns:
@@ -223,6 +253,7 @@ func (b Builder[T]) Build() *Lexer[T] {
 	return &Lexer[T]{
 		table:  table,
 		def_fn: fn,
+		values: maps.Clone(b.values),
 	}
 }
 
@@ -242,4 +273,5 @@ func (b *Builder[T]) Reset() {
 	}
 
 	b.def_fn = nil
+	b.values = nil
 }
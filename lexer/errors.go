@@ -0,0 +1,129 @@
+package lexer
+
+import (
+	"fmt"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// ErrInvalidText is an error that occurs when the input stream contains bytes
+// that are not text: bytes that are not valid UTF-8, NUL bytes and, if the lexer
+// rejects them (see SetRejectControl), the other control characters but the tab,
+// the newline, the vertical tab, the form feed and the carriage return.
+type ErrInvalidText struct {
+	// Span is the span of the run of invalid bytes.
+	Span gr.Span
+
+	// First is the first byte of the run.
+	First byte
+}
+
+// Error implements the error interface.
+//
+// Message: "invalid text at [<start>, <end>): <description of the first byte>"
+func (e ErrInvalidText) Error() string {
+	var desc string
+
+	switch {
+	case e.First == 0:
+		desc = "NUL byte"
+	case e.First < 0x20 || e.First == 0x7f:
+		desc = fmt.Sprintf("control character %#02x", e.First)
+	default:
+		desc = fmt.Sprintf("invalid UTF-8 byte %#02x", e.First)
+	}
+
+	return fmt.Sprintf("invalid text at [%d, %d): %s", e.Span.Start, e.Span.End, desc)
+}
+
+// NewErrInvalidText creates a new ErrInvalidText error.
+//
+// Parameters:
+//   - span: The span of the run of invalid bytes.
+//   - first: The first byte of the run.
+//
+// Returns:
+//   - *ErrInvalidText: The new error. Never returns nil.
+func NewErrInvalidText(span gr.Span, first byte) *ErrInvalidText {
+	return &ErrInvalidText{
+		Span:  span,
+		First: first,
+	}
+}
+
+// ErrInvalidValue is an error that occurs when the value function of a token
+// type rejects the data of a token.
+type ErrInvalidValue struct {
+	// Span is the span of the token.
+	Span gr.Span
+
+	// Data is the data of the token.
+	Data string
+
+	// Err is the error of the value function.
+	Err error
+}
+
+// Error implements the error interface.
+//
+// Message: "invalid value at [<start>, <end>): <data>: <err>"
+func (e ErrInvalidValue) Error() string {
+	return fmt.Sprintf("invalid value at [%d, %d): %q: %v", e.Span.Start, e.Span.End, e.Data, e.Err)
+}
+
+// Unwrap returns the error of the value function.
+//
+// Returns:
+//   - error: The error.
+func (e ErrInvalidValue) Unwrap() error {
+	return e.Err
+}
+
+// NewErrInvalidValue creates a new ErrInvalidValue error.
+//
+// Parameters:
+//   - span: The span of the token.
+//   - data: The data of the token.
+//   - err: The error of the value function.
+//
+// Returns:
+//   - *ErrInvalidValue: The new error. Never returns nil.
+func NewErrInvalidValue(span gr.Span, data string, err error) *ErrInvalidValue {
+	return &ErrInvalidValue{
+		Span: span,
+		Data: data,
+		Err:  err,
+	}
+}
+
+// ErrUnterminated is an error that occurs when a quoted literal, such as a
+// template, is not closed before the end of the input stream.
+type ErrUnterminated struct {
+	// Start is the position of the opening quote.
+	Start int
+
+	// Quote is the quote that was expected to close the literal.
+	Quote rune
+}
+
+// Error implements the error interface.
+//
+// Message: "literal at <start> is not terminated: missing closing <quote>"
+func (e ErrUnterminated) Error() string {
+	return fmt.Sprintf("literal at %d is not terminated: missing closing %q", e.Start, e.Quote)
+}
+
+// NewErrUnterminated creates a new ErrUnterminated error.
+//
+// Parameters:
+//   - start: The position of the opening quote.
+//   - quote: The quote that was expected to close the literal.
+//
+// Returns:
+//   - *ErrUnterminated: The new error. Never returns nil.
+func NewErrUnterminated(start int, quote rune) *ErrUnterminated {
+	return &ErrUnterminated{
+		Start: start,
+		Quote: quote,
+	}
+}
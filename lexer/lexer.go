@@ -1,10 +1,12 @@
 package lexer
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"time"
+	"unicode/utf8"
 
-	gcch "github.com/PlayerR9/go-commons/runes"
 	gr "github.com/PlayerR9/grammar/grammar"
 )
 
@@ -13,20 +15,78 @@ type Lexer[T gr.Enumer] struct {
 	// chars is the characters left in the input stream.
 	chars []rune
 
-	// prev_pos is the previous position in the input stream.
+	// prev_pos is the offset, in bytes, of the start of the current token.
 	prev_pos int
 
-	// curr_pos is the current position in the input stream.
+	// curr_pos is the offset, in bytes, of the next rune in the input stream.
 	curr_pos int
 
+	// data is the input stream.
+	data []byte
+
 	// tokens is the list of tokens lexed so far.
 	tokens []*gr.Token[T]
 
+	// ends are the offsets, in bytes, at which the lexing of each token ended.
+	ends []int
+
 	// table is the table of lexing functions.
 	table map[rune]LexFunc[T]
 
 	// def_fn is the default lexing function.
 	def_fn LexFunc[T]
+
+	// metrics are the metrics to report to. Nil if none.
+	metrics gr.Metrics
+
+	// replace is true if the bytes that are not text are replaced by replacement.
+	replace bool
+
+	// replacement is the rune that replaces each run of bytes that are not text.
+	replacement rune
+
+	// reject_control is true if the control characters are not text.
+	reject_control bool
+
+	// invalid are the runs of bytes that were replaced, in order.
+	invalid []*ErrInvalidText
+
+	// values are the value functions of the token types.
+	values map[T]ValueFunc
+}
+
+// SetMetrics sets the metrics to which every call to Lex is reported.
+//
+// Parameters:
+//   - m: The metrics. Nil stops the reporting.
+func (l *Lexer[T]) SetMetrics(m gr.Metrics) {
+	if l == nil {
+		return
+	}
+
+	l.metrics = m
+}
+
+// Clone returns a new lexer with the same lexing functions and settings, but
+// without any input stream; so that several goroutines can lex with the same
+// rules at once.
+//
+// Returns:
+//   - *Lexer[T]: The new lexer. Nil if the receiver is nil.
+func (l *Lexer[T]) Clone() *Lexer[T] {
+	if l == nil {
+		return nil
+	}
+
+	return &Lexer[T]{
+		table:          l.table,
+		def_fn:         l.def_fn,
+		metrics:        l.metrics,
+		replace:        l.replace,
+		replacement:    l.replacement,
+		reject_control: l.reject_control,
+		values:         l.values,
+	}
 }
 
 // NextRune advances the lexer to the next rune in the input stream.
@@ -40,9 +100,14 @@ func (l *Lexer[T]) NextRune() (rune, bool) {
 	}
 
 	r := l.chars[0]
-	l.chars = l.chars[1:]
 
-	l.curr_pos++
+	if run, ok := l.run_at(); ok {
+		l.curr_pos += run.Span.Len()
+	} else {
+		l.curr_pos += utf8.RuneLen(r)
+	}
+
+	l.chars = l.chars[1:]
 
 	return r, true
 }
@@ -66,11 +131,14 @@ func (l Lexer[T]) PeekRune() (rune, bool) {
 //   - *Token: The token that was lexed.
 //   - error: An error if the token could not be lexed.
 //
-// Nil tokens are ignored.
-func (l *Lexer[T]) lex_one(char rune) (*gr.Token[T], error) {
+// Nil tokens are ignored. A panic of the lexing function is returned as an
+// error of type *gr.ErrPanic.
+func (l *Lexer[T]) lex_one(char rune) (tk *gr.Token[T], err error) {
+	defer gr.Recover(&err)
+
 	fn, ok := l.table[char]
 	if ok {
-		tk, err := fn(l)
+		tk, err = fn(l)
 		if err != nil {
 			return nil, err
 		}
@@ -79,10 +147,14 @@ func (l *Lexer[T]) lex_one(char rune) (*gr.Token[T], error) {
 	}
 
 	if l.def_fn == nil {
+		if run, ok := l.run_at(); ok {
+			return nil, run
+		}
+
 		return nil, fmt.Errorf("unexpected character %q", char)
 	}
 
-	tk, err := l.def_fn(l)
+	tk, err = l.def_fn(l)
 	if err != nil {
 		return nil, err
 	}
@@ -93,21 +165,31 @@ func (l *Lexer[T]) lex_one(char rune) (*gr.Token[T], error) {
 // Tokens is a function that returns the list of tokens. The last token
 // is guaranteed to be an EOF token.
 //
-// Parameters:
-//   - tokens: The list of tokens.
-//
 // Returns:
 //   - []*Token: The list of tokens with an EOF token added to the end.
+//
+// The tokens are copies of the ones the lexer holds, linked to each other by
+// their lookaheads. Hence, neither a later call to Tokens nor a call to Relex
+// changes the tokens returned by a previous call. The children of the tokens are
+// shared.
 func (l *Lexer[T]) Tokens() []*gr.Token[T] {
-	tk_eof := gr.NewTerminalToken(T(0), "")
-	tk_eof.Pos = -1
+	copies := make([]gr.Token[T], len(l.tokens)+1)
 
-	tokens := append(l.tokens, tk_eof)
+	tokens := make([]*gr.Token[T], 0, len(l.tokens)+1)
 
-	for i := 0; i < len(tokens)-1; i++ {
-		tokens[i].Lookahead = tokens[i+1]
+	for i, tk := range l.tokens {
+		copies[i] = *tk
+		tokens = append(tokens, &copies[i])
 	}
 
+	tk_eof := &copies[len(l.tokens)]
+	tk_eof.Type = T(0)
+	tk_eof.Pos = -1
+
+	tokens = append(tokens, tk_eof)
+
+	gr.LinkLookaheads(tokens)
+
 	return tokens
 }
 
@@ -117,49 +199,127 @@ func (l *Lexer[T]) Tokens() []*gr.Token[T] {
 //   - data: The input stream to set.
 //
 // Returns:
-//   - error: An error if the input stream could not be set.
+//   - error: An error of type *ErrInvalidText if the input stream contains bytes
+//     that are not text and no replacement is set. See SetReplacement.
 func (l *Lexer[T]) SetInputStream(data []byte) error {
-	chars, err := gcch.BytesToUtf8(data)
+	chars, runs, err := l.decode(data, 0)
 	if err != nil {
 		return err
 	}
 
+	l.data = data
 	l.chars = chars
+	l.invalid = runs
+	l.prev_pos = 0
+	l.curr_pos = 0
 
 	return nil
 }
 
+// instance is a helper function that makes a new lexer with the rules and the
+// settings of the lexer but with its own input stream, so that lexing with it
+// leaves the lexer untouched.
+//
+// Returns:
+//   - *Lexer[T]: The new lexer. Never returns nil.
+func (l Lexer[T]) instance() *Lexer[T] {
+	return &Lexer[T]{
+		table:          l.table,
+		def_fn:         l.def_fn,
+		replace:        l.replace,
+		replacement:    l.replacement,
+		reject_control: l.reject_control,
+		values:         l.values,
+	}
+}
+
 // Lex lexes the input stream and returns a list of tokens.
 //
+// Returns:
+//   - error: An error if the input stream could not be lexed.
+func (l *Lexer[T]) Lex() error {
+	return l.LexCtx(context.Background())
+}
+
+// LexCtx is like Lex but stops as soon as the context is done. The tokens lexed
+// so far are then kept; see Tokens.
+//
+// Parameters:
+//   - ctx: The context.
+//
+// Returns:
+//   - error: An error of type *gr.ErrCancelled if the context is done before the
+//     end of the input stream, or an error if the input stream could not be lexed.
+func (l *Lexer[T]) LexCtx(ctx context.Context) error {
+	start := time.Now()
+
+	err := l.lex(ctx)
+
+	gr.Record(l.metrics, gr.OpLex, start, len(l.tokens), err)
+
+	return err
+}
+
+// lex is a helper function that lexes the input stream.
+//
 // Parameters:
-//   - data: The input stream to lex.
+//   - ctx: The context.
 //
 // Returns:
 //   - error: An error if the input stream could not be lexed.
-func (l *Lexer[T]) Lex() error {
+func (l *Lexer[T]) lex(ctx context.Context) error {
 	if l.chars == nil {
 		l.tokens = make([]*gr.Token[T], 0)
 	} else {
 		l.tokens = l.tokens[:0]
 	}
 
+	l.ends = l.ends[:0]
+
 	for len(l.chars) > 0 {
-		char := l.chars[0]
+		if err := ctx.Err(); err != nil {
+			return gr.NewErrCancelled(err, len(l.tokens))
+		}
 
-		tk, err := l.lex_one(char)
+		err := l.next()
 		if err == io.EOF {
 			break
 		} else if err != nil {
 			return err
 		}
+	}
+
+	return nil
+}
 
-		if tk != nil {
-			tk.Pos = l.prev_pos
-			l.tokens = append(l.tokens, tk)
+// next is a helper function that lexes the next token of the input stream.
+//
+// Returns:
+//   - error: An error if the token could not be lexed. io.EOF if the lexing
+//     function asked to stop.
+func (l *Lexer[T]) next() error {
+	char := l.chars[0]
+
+	tk, err := l.lex_one(char)
+	if err != nil {
+		return err
+	}
+
+	if tk != nil {
+		tk.Pos = l.prev_pos
+
+		if fn, ok := l.values[tk.Type]; ok {
+			tk.Value, err = fn(tk.Data)
+			if err != nil {
+				return NewErrInvalidValue(gr.NewSpan(l.prev_pos, l.curr_pos), tk.Data, err)
+			}
 		}
 
-		l.prev_pos = l.curr_pos
+		l.tokens = append(l.tokens, tk)
+		l.ends = append(l.ends, l.curr_pos)
 	}
 
+	l.prev_pos = l.curr_pos
+
 	return nil
 }
@@ -0,0 +1,85 @@
+package lexer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+func TestLexPanic(t *testing.T) {
+	b := NewBuilder[test_type]()
+
+	b.RegisterDefault(func(_ *Lexer[test_type]) (*gr.Token[test_type], error) {
+		panic("boom")
+	})
+
+	l := b.Build()
+
+	err := l.SetInputStream([]byte("ab"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = l.Lex()
+
+	var panic_err *gr.ErrPanic
+
+	if !errors.As(err, &panic_err) {
+		t.Fatalf("expected an *ErrPanic, got %v", err)
+	} else if panic_err.Value != "boom" {
+		t.Errorf("expected the value %q, got %v", "boom", panic_err.Value)
+	}
+}
+
+func TestLexCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := NewBuilder[test_type]()
+
+	_ = b.RegisterSkip(" ")
+
+	b.RegisterDefault(func(l *Lexer[test_type]) (*gr.Token[test_type], error) {
+		tk, err := lex_test_word(l)
+		if err == nil && tk.Data == "b" {
+			cancel()
+		}
+
+		return tk, err
+	})
+
+	l := b.Build()
+
+	err := l.SetInputStream([]byte("a b c d"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = l.LexCtx(ctx)
+
+	var cancelled *gr.ErrCancelled
+
+	if !errors.As(err, &cancelled) {
+		t.Fatalf("expected an *ErrCancelled, got %v", err)
+	} else if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the error to wrap %v, got %v", context.Canceled, err)
+	}
+
+	var words []string
+
+	for _, tk := range l.Tokens() {
+		if tk.Type != tt_eof {
+			words = append(words, tk.Data)
+		}
+	}
+
+	if len(words) != 2 || words[0] != "a" || words[1] != "b" {
+		t.Errorf("expected the tokens [a b], got %v", words)
+	}
+
+	if cancelled.Steps != 2 {
+		t.Errorf("expected 2 steps, got %d", cancelled.Steps)
+	}
+}
@@ -0,0 +1,118 @@
+package lexer
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// test_metrics is a gr.Metrics that counts what it receives.
+type test_metrics struct {
+	mu sync.Mutex
+
+	totals, errors, durations int
+
+	tokens []int
+}
+
+// IncTotal implements the gr.Metrics interface.
+func (m *test_metrics) IncTotal(op gr.Operation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if op == gr.OpLex {
+		m.totals++
+	}
+}
+
+// IncErrors implements the gr.Metrics interface.
+func (m *test_metrics) IncErrors(op gr.Operation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if op == gr.OpLex {
+		m.errors++
+	}
+}
+
+// ObserveDuration implements the gr.Metrics interface.
+func (m *test_metrics) ObserveDuration(op gr.Operation, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if op == gr.OpLex && d >= 0 {
+		m.durations++
+	}
+}
+
+// ObserveTokens implements the gr.Metrics interface.
+func (m *test_metrics) ObserveTokens(op gr.Operation, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if op == gr.OpLex {
+		m.tokens = append(m.tokens, n)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	b := NewBuilder[test_type]()
+
+	_ = b.RegisterSkip(" ")
+
+	b.RegisterDefault(lex_test_word)
+
+	l := b.Build()
+
+	m := &test_metrics{}
+
+	l.SetMetrics(m)
+
+	err := l.SetInputStream([]byte("a b c"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = l.Lex()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Without a default function, the word cannot be lexed.
+	failing := NewBuilder[test_type]().Build()
+
+	failing.SetMetrics(m)
+
+	err = failing.SetInputStream([]byte("a"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := failing.Lex(); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+
+	if m.totals != 2 || m.errors != 1 || m.durations != 2 {
+		t.Errorf("expected 2 lexings, 1 error and 2 durations, got %d, %d and %d", m.totals, m.errors, m.durations)
+	}
+
+	if len(m.tokens) != 2 || m.tokens[0] != 3 || m.tokens[1] != 0 {
+		t.Errorf("expected the token counts [3 0], got %v", m.tokens)
+	}
+
+	// A clone reports to the same metrics until they are removed.
+	clone := l.Clone()
+
+	_ = clone.SetInputStream([]byte("d"))
+	_ = clone.Lex()
+
+	clone.SetMetrics(nil)
+
+	_ = clone.Lex()
+
+	if m.totals != 3 {
+		t.Errorf("expected 3 lexings, got %d", m.totals)
+	}
+}
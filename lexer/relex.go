@@ -0,0 +1,136 @@
+package lexer
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// Relex updates the tokens of the last call to Lex after an edit of the input
+// stream. Only the tokens around the edit are lexed again; the others are reused,
+// moved by the difference of size of the edit.
+//
+// Lexing restarts at the end of the last token that ends before the edit and stops
+// as soon as it reaches, after the edit, the start of a token that was lexed before
+// the edit. Hence, lexing functions must not look more than one character past the
+// end of their tokens.
+//
+// Parameters:
+//   - edit: The span of the bytes of the previous input stream that are replaced.
+//   - text: The new bytes.
+//
+// Returns:
+//   - error: An error if the edit is not within the input stream or if the new input
+//     stream could not be lexed; in particular, an error of type *ErrInvalidText if
+//     the new bytes are not text and no replacement is set.
+//
+// On error, the lexer is left as it was before the call. The tokens returned by
+// previous calls to Tokens are left untouched.
+func (l *Lexer[T]) Relex(edit gr.Span, text []byte) error {
+	if l == nil {
+		return gcers.NilReceiver
+	}
+
+	start := time.Now()
+
+	err := l.relex(edit, text)
+
+	gr.Record(l.metrics, gr.OpLex, start, len(l.tokens), err)
+
+	return err
+}
+
+// relex is a helper function that re-lexes the input stream after an edit.
+//
+// Parameters:
+//   - edit: The span of the bytes of the previous input stream that are replaced.
+//   - text: The new bytes.
+//
+// Returns:
+//   - error: An error if the edit is not within the input stream or if the new input
+//     stream could not be lexed.
+func (l *Lexer[T]) relex(edit gr.Span, text []byte) error {
+	if edit.Start < 0 || edit.End > len(l.data) || edit.End < edit.Start {
+		return gcers.NewErrInvalidParameter("edit", fmt.Errorf("span [%d, %d) is not within the input stream of %d bytes", edit.Start, edit.End, len(l.data)))
+	}
+
+	data := slices.Concat(l.data[:edit.Start], text, l.data[edit.End:])
+	delta := len(text) - edit.Len()
+
+	keep := 0
+
+	for keep < len(l.ends) && l.ends[keep] < edit.Start {
+		keep++
+	}
+
+	restart := 0
+
+	if keep > 0 {
+		restart = l.ends[keep-1]
+	}
+
+	chars, runs, err := l.decode(data[restart:], restart)
+	if err != nil {
+		return err
+	}
+
+	kept := 0
+
+	for kept < len(l.invalid) && l.invalid[kept].Span.End <= restart {
+		kept++
+	}
+
+	// The state is only ever replaced, never changed in place, so a shallow copy is
+	// enough to roll back.
+	saved := *l
+
+	old_tokens := slices.Clone(l.tokens[keep:])
+	old_ends := slices.Clone(l.ends[keep:])
+
+	l.data = data
+	l.chars = chars
+	l.invalid = slices.Concat(l.invalid[:kept], runs)
+	l.prev_pos = restart
+	l.curr_pos = restart
+	l.tokens = slices.Clone(l.tokens[:keep])
+	l.ends = slices.Clone(l.ends[:keep])
+
+	edit_end := edit.Start + len(text)
+	idx := 0
+
+	for len(l.chars) > 0 {
+		if l.curr_pos >= edit_end {
+			for idx < len(old_tokens) && old_tokens[idx].Pos+delta < l.curr_pos {
+				idx++
+			}
+
+			if idx < len(old_tokens) && old_tokens[idx].Pos+delta == l.curr_pos {
+				for i := idx; i < len(old_tokens); i++ {
+					l.tokens = append(l.tokens, old_tokens[i].Moved(delta))
+					l.ends = append(l.ends, old_ends[i]+delta)
+				}
+
+				l.chars = nil
+				l.prev_pos = len(data)
+				l.curr_pos = len(data)
+
+				return nil
+			}
+		}
+
+		err := l.next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			*l = saved
+
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package lexer
+
+import (
+	"fmt"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// lex_test_strict_word is a helper function that lexes a word of letters and
+// fails on any other character.
+func lex_test_strict_word(l *Lexer[test_type]) (*gr.Token[test_type], error) {
+	c, _ := l.PeekRune()
+	if c < 'a' || c > 'z' {
+		return nil, fmt.Errorf("unexpected character %q", c)
+	}
+
+	return lex_test_word(l)
+}
+
+// words_at is a helper function that returns the tokens of a lexer in the form
+// "data@pos", EOF excluded.
+func words_at(l *Lexer[test_type]) []string {
+	var words []string
+
+	for _, tk := range l.Tokens() {
+		if tk.Type != tt_eof {
+			words = append(words, fmt.Sprintf("%s@%d", tk.Data, tk.Pos))
+		}
+	}
+
+	return words
+}
+
+func TestRelex(t *testing.T) {
+	b := NewBuilder[test_type]()
+
+	_ = b.RegisterSkip(" ")
+	b.RegisterDefault(lex_test_strict_word)
+
+	l := b.Build()
+
+	err := l.SetInputStream([]byte("ab cd ef"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = l.Lex()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = l.Relex(gr.NewSpan(3, 5), []byte("xyz"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := "[ab@0 xyz@3 ef@7]"
+
+	if got := fmt.Sprint(words_at(l)); got != want {
+		t.Fatalf("expected the tokens %s, got %s", want, got)
+	}
+
+	err = l.Relex(gr.NewSpan(3, 6), []byte("x!z"))
+	if err == nil {
+		t.Fatalf("expected an error, got none")
+	}
+
+	if got := fmt.Sprint(words_at(l)); got != want {
+		t.Fatalf("expected the tokens %s after the failed edit, got %s", want, got)
+	}
+
+	err = l.Relex(gr.NewSpan(0, 2), []byte("q"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want = "[q@0 xyz@2 ef@6]"
+
+	if got := fmt.Sprint(words_at(l)); got != want {
+		t.Errorf("expected the tokens %s, got %s", want, got)
+	}
+}
+
+func TestRelexPreviousTokens(t *testing.T) {
+	b := NewBuilder[test_type]()
+
+	_ = b.RegisterSkip(" ")
+	b.RegisterDefault(lex_test_strict_word)
+
+	l := b.Build()
+
+	err := l.SetInputStream([]byte("ab cd ef"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = l.Lex()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	before := l.Tokens()
+
+	err = l.Relex(gr.NewSpan(3, 5), []byte("xyz"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	after := l.Tokens()
+
+	if after[0].Lookahead.Data != "xyz" {
+		t.Errorf("expected the lookahead %q, got %q", "xyz", after[0].Lookahead.Data)
+	}
+
+	if before[0].Lookahead != before[1] || before[1].Data != "cd" || before[2].Pos != 6 {
+		t.Errorf("expected the tokens before the edit to be left untouched")
+	}
+}
@@ -0,0 +1,274 @@
+package lexer
+
+import (
+	"fmt"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// ExprParser is the parser of the interpolated expressions of a template.
+// *parser.Parser[T] implements it.
+type ExprParser[T gr.Enumer] interface {
+	// ParseResult parses the tokens of an expression.
+	//
+	// Parameters:
+	//   - tokens: The tokens of the expression, EOF included.
+	//
+	// Returns:
+	//   - gr.Result[*gr.Token[T]]: The result of the parse.
+	ParseResult(tokens []*gr.Token[T]) gr.Result[*gr.Token[T]]
+}
+
+// Template is the recipe of a template string with interpolated expressions, such as
+// `Hello, ${name}!`. The text of the template is lexed by the lexer that registers
+// it, while each interpolated expression is an island lexed and parsed by its own
+// grammar.
+//
+// The whole template is lexed as a single token of type Type whose children are, in
+// order, the text chunks (tokens of type Text) and the parse trees of the
+// interpolated expressions; all of them with their position in the input stream.
+type Template[T gr.Enumer] struct {
+	// Type is the type of the token of the template.
+	Type T
+
+	// Text is the type of the text chunks.
+	Text T
+
+	// Quote is the character that opens and closes the template. (e.g., '`')
+	Quote rune
+
+	// Start is the literal that opens an interpolated expression. (e.g., "${")
+	Start string
+
+	// End is the character that closes an interpolated expression. (e.g., '}')
+	End rune
+
+	// Nest is the character that, within an expression, must be balanced by an End
+	// before the expression can end. (e.g., '{') 0 if none.
+	Nest rune
+
+	// Lexer is the lexer of the interpolated expressions. Each expression is lexed
+	// by a new instance with its rules; so it may be the lexer that registers the
+	// template, and it is never modified.
+	Lexer *Lexer[T]
+
+	// Parser is the parser of the interpolated expressions.
+	Parser ExprParser[T]
+}
+
+// RegisterTemplate registers a new template rule. Within the text of the template,
+// a backslash escapes the character that follows it.
+//
+// Parameters:
+//   - tmpl: The recipe of the template.
+//
+// Returns:
+//   - error: An error of type *errors.ErrInvalidParameter if the recipe is incomplete.
+//
+// Previously registered rules with the same first character will be overwritten.
+func (b *Builder[T]) RegisterTemplate(tmpl Template[T]) error {
+	if b == nil {
+		return nil
+	}
+
+	if tmpl.Start == "" {
+		return gcers.NewErrInvalidParameter("tmpl.Start", gcers.NewErrEmpty(tmpl.Start))
+	} else if tmpl.Lexer == nil {
+		return gcers.NewErrNilParameter("tmpl.Lexer")
+	} else if tmpl.Parser == nil {
+		return gcers.NewErrNilParameter("tmpl.Parser")
+	}
+
+	if b.table == nil {
+		b.table = make(map[rune]LexFunc[T])
+	}
+
+	b.table[tmpl.Quote] = tmpl.lex
+
+	return nil
+}
+
+// has_prefix is a helper function that checks whether the characters start with the
+// given prefix.
+//
+// Parameters:
+//   - chars: The characters.
+//   - prefix: The prefix.
+//
+// Returns:
+//   - bool: True if chars starts with prefix, false otherwise.
+func has_prefix(chars []rune, prefix []rune) bool {
+	if len(chars) < len(prefix) {
+		return false
+	}
+
+	for i, c := range prefix {
+		if chars[i] != c {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lex is the lexing function of the template.
+//
+// Parameters:
+//   - l: The lexer. Assumed to be non-nil and on the opening quote.
+//
+// Returns:
+//   - *gr.Token[T]: The token of the template.
+//   - error: An error of type *ErrUnterminated if the template is not
+//     terminated, or an error if an interpolated expression is invalid.
+func (tmpl Template[T]) lex(l *Lexer[T]) (*gr.Token[T], error) {
+	start := l.curr_pos
+
+	_, _ = l.NextRune()
+	// dbg.AssertOk(ok, "l.NextRune()")
+
+	open := []rune(tmpl.Start)
+
+	var children []*gr.Token[T]
+	var text []rune
+
+	text_pos := l.curr_pos
+
+	flush := func() {
+		if len(text) == 0 {
+			return
+		}
+
+		tk := gr.NewTerminalToken(tmpl.Text, string(text))
+		tk.Pos = text_pos
+
+		children = append(children, tk)
+		text = text[:0]
+	}
+
+	for {
+		c, ok := l.PeekRune()
+		if !ok {
+			return nil, NewErrUnterminated(start, tmpl.Quote)
+		}
+
+		if c == tmpl.Quote {
+			_, _ = l.NextRune()
+
+			break
+		}
+
+		if has_prefix(l.chars, open) {
+			flush()
+
+			for range open {
+				_, _ = l.NextRune()
+			}
+
+			root, err := tmpl.lex_expr(l)
+			if err != nil {
+				return nil, err
+			}
+
+			children = append(children, root)
+			text_pos = l.curr_pos
+
+			continue
+		}
+
+		if len(text) == 0 {
+			text_pos = l.curr_pos
+		}
+
+		_, _ = l.NextRune()
+		text = append(text, c)
+
+		if c != '\\' {
+			continue
+		}
+
+		c, ok = l.NextRune()
+		if !ok {
+			return nil, NewErrUnterminated(start, tmpl.Quote)
+		}
+
+		text = append(text, c)
+	}
+
+	flush()
+
+	if len(children) == 0 {
+		tk := gr.NewTerminalToken(tmpl.Text, "")
+		tk.Pos = text_pos
+
+		children = append(children, tk)
+	}
+
+	tk, _ := gr.NewToken(tmpl.Type, "", children)
+	// dbg.AssertErr(err, "gr.NewToken(tmpl.Type, \"\", children)")
+
+	return tk, nil
+}
+
+// lex_expr is a helper function that lexes and parses an interpolated expression.
+//
+// Parameters:
+//   - l: The lexer. Assumed to be non-nil and right after the start of the expression.
+//
+// Returns:
+//   - *gr.Token[T]: The root of the parse tree of the expression.
+//   - error: An error if the expression is not terminated or is invalid.
+func (tmpl Template[T]) lex_expr(l *Lexer[T]) (*gr.Token[T], error) {
+	offset := l.curr_pos
+
+	var expr []rune
+
+	depth := 0
+
+	for {
+		c, ok := l.NextRune()
+		if !ok {
+			return nil, fmt.Errorf("expression at %d is not terminated", offset)
+		}
+
+		if c == tmpl.End {
+			if depth == 0 {
+				break
+			}
+
+			depth--
+		} else if tmpl.Nest != 0 && c == tmpl.Nest {
+			depth++
+		}
+
+		expr = append(expr, c)
+	}
+
+	lx := tmpl.Lexer.instance()
+
+	err := lx.SetInputStream([]byte(string(expr)))
+	if err != nil {
+		return nil, fmt.Errorf("expression at %d: %w", offset, err)
+	}
+
+	err = lx.Lex()
+	if err != nil {
+		return nil, fmt.Errorf("expression at %d: %w", offset, err)
+	}
+
+	res := tmpl.Parser.ParseResult(lx.Tokens())
+
+	if res.Err != nil {
+		pos := offset
+
+		if res.ErrPos >= 0 {
+			pos += res.ErrPos
+		}
+
+		return nil, fmt.Errorf("expression at %d: %w", pos, res.Err)
+	}
+
+	root, _ := res.Root()
+
+	return root.Moved(offset), nil
+}
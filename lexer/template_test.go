@@ -0,0 +1,167 @@
+package lexer
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+type test_type int
+
+const (
+	tt_eof test_type = iota
+	tt_word
+	tt_text
+	tt_template
+	nt_expr
+)
+
+func (t test_type) String() string {
+	return [...]string{"EOF", "Word", "Text", "Template", "Expr"}[t]
+}
+
+// expr_parser is the parser of the test expressions: a list of words.
+type expr_parser struct{}
+
+// ParseResult implements the ExprParser interface.
+func (expr_parser) ParseResult(tokens []*gr.Token[test_type]) gr.Result[*gr.Token[test_type]] {
+	root, err := gr.NewToken(nt_expr, "", tokens[:len(tokens)-1])
+	if err != nil {
+		return gr.NewFailedResult[*gr.Token[test_type]](nil, err)
+	}
+
+	return gr.NewResult(root)
+}
+
+// lex_test_word is a helper function that lexes a word of letters.
+func lex_test_word(l *Lexer[test_type]) (*gr.Token[test_type], error) {
+	var word []rune
+
+	for {
+		c, ok := l.PeekRune()
+		if !ok || c < 'a' || c > 'z' {
+			break
+		}
+
+		_, _ = l.NextRune()
+		word = append(word, c)
+	}
+
+	return gr.NewTerminalToken(tt_word, string(word)), nil
+}
+
+func TestRegisterTemplate(t *testing.T) {
+	eb := NewBuilder[test_type]()
+
+	_ = eb.RegisterSkip(" ")
+	eb.RegisterDefault(lex_test_word)
+
+	expr_lexer := eb.Build()
+
+	b := NewBuilder[test_type]()
+
+	_ = b.RegisterSkip(" ")
+	b.RegisterDefault(lex_test_word)
+
+	err := b.RegisterTemplate(Template[test_type]{
+		Type:   tt_template,
+		Text:   tt_text,
+		Quote:  '`',
+		Start:  "${",
+		End:    '}',
+		Lexer:  expr_lexer,
+		Parser: expr_parser{},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	l := b.Build()
+
+	err = l.SetInputStream([]byte("x `a${b c}d${e}` y"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = l.Lex()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var elems []string
+
+	for _, tk := range l.Tokens() {
+		elems = append(elems, sexpr_of(tk))
+	}
+
+	const want = "x@0 (Template a@3 (Expr b@6 c@8) d@10 (Expr e@13)) y@17 EOF@-1"
+
+	if got := strings.Join(elems, " "); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	if tokens := expr_lexer.Tokens(); len(tokens) != 1 {
+		t.Errorf("expected the lexer of the expressions to be left untouched, got %d tokens", len(tokens))
+	}
+}
+
+// sexpr_of is a helper function that writes a token tree as "(Type child...)",
+// with the data and the position of the leaves.
+func sexpr_of(tk *gr.Token[test_type]) string {
+	if len(tk.Children) == 0 {
+		data := tk.Data
+		if data == "" {
+			data = tk.Type.String()
+		}
+
+		return data + "@" + strconv.Itoa(tk.Pos)
+	}
+
+	elems := []string{tk.Type.String()}
+
+	for _, child := range tk.Children {
+		elems = append(elems, sexpr_of(child))
+	}
+
+	return "(" + strings.Join(elems, " ") + ")"
+}
+
+func TestRegisterTemplateUnterminated(t *testing.T) {
+	b := NewBuilder[test_type]()
+
+	_ = b.RegisterSkip(" ")
+	b.RegisterDefault(lex_test_word)
+
+	err := b.RegisterTemplate(Template[test_type]{
+		Type:   tt_template,
+		Text:   tt_text,
+		Quote:  '`',
+		Start:  "${",
+		End:    '}',
+		Lexer:  b.Build(),
+		Parser: expr_parser{},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	l := b.Build()
+
+	err = l.SetInputStream([]byte("x `ab"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = l.Lex()
+
+	var unterminated *ErrUnterminated
+
+	if !errors.As(err, &unterminated) {
+		t.Fatalf("expected an *ErrUnterminated, got %v", err)
+	} else if unterminated.Start != 2 {
+		t.Errorf("expected the literal to start at %d, got %d", 2, unterminated.Start)
+	}
+}
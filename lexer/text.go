@@ -0,0 +1,157 @@
+package lexer
+
+import (
+	"slices"
+	"unicode/utf8"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// SetReplacement sets the rune that replaces every run of bytes of the input
+// stream that are not text (see ErrInvalidText). Each run is replaced by a single
+// rune that spans the whole run, so the positions of the tokens remain offsets in
+// the input stream, and is reported by Diagnostics.
+//
+// Parameters:
+//   - r: The replacement, such as utf8.RuneError. If negative, SetInputStream fails
+//     on the first run instead, which is the default.
+func (l *Lexer[T]) SetReplacement(r rune) {
+	if l == nil {
+		return
+	}
+
+	l.replace = r >= 0
+	l.replacement = r
+}
+
+// SetRejectControl sets whether the control characters are text. By default,
+// they are, so that inputs such as terminal logs, with their escape sequences,
+// can be lexed; only invalid UTF-8 and NUL bytes are not text.
+//
+// Parameters:
+//   - reject: If true, the C0 control characters but the tab, the newline, the
+//     vertical tab, the form feed and the carriage return, and DEL, are not text
+//     either.
+func (l *Lexer[T]) SetRejectControl(reject bool) {
+	if l == nil {
+		return
+	}
+
+	l.reject_control = reject
+}
+
+// Diagnostics returns the runs of bytes that were replaced in the input stream.
+//
+// Returns:
+//   - []error: The errors of type *ErrInvalidText, in order. Nil if the input
+//     stream is text or if no replacement is set.
+func (l Lexer[T]) Diagnostics() []error {
+	if len(l.invalid) == 0 {
+		return nil
+	}
+
+	errs := make([]error, 0, len(l.invalid))
+
+	for _, err := range l.invalid {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// is_text is a helper function that checks whether a decoded rune is text.
+//
+// Parameters:
+//   - r: The rune.
+//   - size: The number of bytes it was decoded from.
+//   - reject_control: True if the control characters are not text.
+//
+// Returns:
+//   - bool: True if the rune is text, false otherwise.
+func is_text(r rune, size int, reject_control bool) bool {
+	switch {
+	case r == utf8.RuneError && size <= 1:
+		return false
+	case r == 0:
+		return false
+	case !reject_control:
+		return true
+	case r == 0x7f:
+		return false
+	case r >= 0x20:
+		return true
+	default:
+		return r >= '\t' && r <= '\r'
+	}
+}
+
+// decode is a helper function that decodes bytes of the input stream into runes.
+//
+// Parameters:
+//   - data: The bytes.
+//   - base: The offset of data in the input stream.
+//
+// Returns:
+//   - []rune: The runes, with every run of bytes that are not text replaced by the
+//     replacement of the lexer.
+//   - []*ErrInvalidText: The runs that were replaced.
+//   - error: An error of type *ErrInvalidText for the first run if the lexer has no
+//     replacement.
+func (l Lexer[T]) decode(data []byte, base int) ([]rune, []*ErrInvalidText, error) {
+	chars := make([]rune, 0, len(data))
+
+	var runs []*ErrInvalidText
+
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if is_text(r, size, l.reject_control) {
+			chars = append(chars, r)
+			i += size
+
+			continue
+		}
+
+		start := i
+
+		for i < len(data) {
+			r, size := utf8.DecodeRune(data[i:])
+			if is_text(r, size, l.reject_control) {
+				break
+			}
+
+			i += size
+		}
+
+		run := NewErrInvalidText(gr.NewSpan(base+start, base+i), data[start])
+
+		if !l.replace {
+			return nil, nil, run
+		}
+
+		chars = append(chars, l.replacement)
+		runs = append(runs, run)
+	}
+
+	return chars, runs, nil
+}
+
+// run_at is a helper function that returns the run of replaced bytes at the
+// current position.
+//
+// Returns:
+//   - *ErrInvalidText: The run. Nil if the next rune is not a replacement.
+//   - bool: True if the run exists, false otherwise.
+func (l Lexer[T]) run_at() (*ErrInvalidText, bool) {
+	if !l.replace || len(l.chars) == 0 || l.chars[0] != l.replacement {
+		return nil, false
+	}
+
+	idx, ok := slices.BinarySearchFunc(l.invalid, l.curr_pos, func(run *ErrInvalidText, pos int) int {
+		return run.Span.Start - pos
+	})
+	if !ok {
+		return nil, false
+	}
+
+	return l.invalid[idx], true
+}
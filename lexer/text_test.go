@@ -0,0 +1,102 @@
+package lexer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// lex_test_field is a helper function that lexes every character up to the next
+// space as a word.
+func lex_test_field(l *Lexer[test_type]) (*gr.Token[test_type], error) {
+	var word []rune
+
+	for {
+		c, ok := l.PeekRune()
+		if !ok || c == ' ' {
+			break
+		}
+
+		_, _ = l.NextRune()
+		word = append(word, c)
+	}
+
+	return gr.NewTerminalToken(tt_word, string(word)), nil
+}
+
+func TestSetInputStreamText(t *testing.T) {
+	tests := []struct {
+		data   string
+		reject bool
+		valid  bool
+	}{
+		{"a\x1b[0mb", false, true},
+		{"a\x7fb", false, true},
+		{"a\tb\r\n", false, true},
+		{"a\x00b", false, false},
+		{"a\xffb", false, false},
+		{"a\x1b[0mb", true, false},
+		{"a\x7fb", true, false},
+		{"a\tb\r\n", true, true},
+	}
+
+	for _, test := range tests {
+		l := NewBuilder[test_type]().Build()
+		l.SetRejectControl(test.reject)
+
+		err := l.SetInputStream([]byte(test.data))
+
+		if test.valid {
+			if err != nil {
+				t.Errorf("%q (reject=%t): expected no error, got %v", test.data, test.reject, err)
+			}
+
+			continue
+		}
+
+		var invalid *ErrInvalidText
+
+		if !errors.As(err, &invalid) {
+			t.Errorf("%q (reject=%t): expected an error of type *ErrInvalidText, got %v", test.data, test.reject, err)
+		} else if want := gr.NewSpan(1, 2); invalid.Span != want {
+			t.Errorf("%q (reject=%t): expected the span %v, got %v", test.data, test.reject, want, invalid.Span)
+		}
+	}
+}
+
+func TestRelexControl(t *testing.T) {
+	b := NewBuilder[test_type]()
+
+	_ = b.RegisterSkip(" ")
+	b.RegisterDefault(lex_test_field)
+
+	l := b.Build()
+
+	err := l.SetInputStream([]byte("ab cd"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = l.Lex()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = l.Relex(gr.NewSpan(3, 5), []byte("\x1bx"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := "[ab@0 \x1bx@3]"
+
+	if got := fmt.Sprint(words_at(l)); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	err = l.Relex(gr.NewSpan(3, 5), []byte("\x00x"))
+	if err == nil {
+		t.Errorf("expected an error, got nil")
+	}
+}
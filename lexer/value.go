@@ -0,0 +1,69 @@
+package lexer
+
+import (
+	"strconv"
+)
+
+// ValueFunc computes the value of a token from its data.
+//
+// Parameters:
+//   - data: The data of the token.
+//
+// Returns:
+//   - any: The value of the token.
+//   - error: An error if data is not a valid representation of a value.
+type ValueFunc func(data string) (any, error)
+
+// IntValue is a ValueFunc that parses integer literals, in any base accepted by
+// Go (such as "42", "0x2a" or "1_000"), into int64 values.
+//
+// Parameters:
+//   - data: The data of the token.
+//
+// Returns:
+//   - any: The value, of type int64.
+//   - error: An error of type *strconv.NumError if data is not a valid integer.
+func IntValue(data string) (any, error) {
+	v, err := strconv.ParseInt(data, 0, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// FloatValue is a ValueFunc that parses floating-point literals into float64
+// values.
+//
+// Parameters:
+//   - data: The data of the token.
+//
+// Returns:
+//   - any: The value, of type float64.
+//   - error: An error of type *strconv.NumError if data is not a valid number.
+func FloatValue(data string) (any, error) {
+	v, err := strconv.ParseFloat(data, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// QuotedValue is a ValueFunc that unquotes Go string and character literals
+// (such as "\"a\\tb\"", "`raw`" or "'x'") into string values.
+//
+// Parameters:
+//   - data: The data of the token.
+//
+// Returns:
+//   - any: The value, of type string.
+//   - error: An error if data is not a valid quoted literal.
+func QuotedValue(data string) (any, error) {
+	v, err := strconv.Unquote(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
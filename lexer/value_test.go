@@ -0,0 +1,116 @@
+package lexer
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+func TestValueFuncs(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   ValueFunc
+		data string
+		want any
+		ok   bool
+	}{
+		{name: "int", fn: IntValue, data: "42", want: int64(42), ok: true},
+		{name: "hex int", fn: IntValue, data: "0x2a", want: int64(42), ok: true},
+		{name: "int with underscores", fn: IntValue, data: "1_000", want: int64(1000), ok: true},
+		{name: "bad int", fn: IntValue, data: "4x"},
+		{name: "float", fn: FloatValue, data: "2.5", want: 2.5, ok: true},
+		{name: "bad float", fn: FloatValue, data: "2.5.1"},
+		{name: "string", fn: QuotedValue, data: `"a\tb"`, want: "a\tb", ok: true},
+		{name: "raw string", fn: QuotedValue, data: "`a\\tb`", want: `a\tb`, ok: true},
+		{name: "unterminated string", fn: QuotedValue, data: `"ab`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn(tt.data)
+			if (err == nil) != tt.ok {
+				t.Fatalf("expected success to be %t, got %v", tt.ok, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+// lex_test_literal is a helper function that lexes a word of anything but spaces.
+func lex_test_literal(l *Lexer[test_type]) (*gr.Token[test_type], error) {
+	var word []rune
+
+	for {
+		c, ok := l.PeekRune()
+		if !ok || c == ' ' {
+			break
+		}
+
+		_, _ = l.NextRune()
+		word = append(word, c)
+	}
+
+	return gr.NewTerminalToken(tt_word, string(word)), nil
+}
+
+func TestRegisterValue(t *testing.T) {
+	b := NewBuilder[test_type]()
+
+	_ = b.RegisterSkip(" ")
+
+	b.RegisterDefault(lex_test_literal)
+	b.RegisterValue(tt_word, IntValue)
+
+	l := b.Build()
+
+	err := l.SetInputStream([]byte("42 0x10"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = l.Lex()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tokens := l.Tokens()
+
+	for i, want := range []int64{42, 16} {
+		got, ok := gr.TokenValueAs[int64](tokens[i])
+		if !ok || got != want {
+			t.Errorf("token %d: expected the value %d, got %v", i, want, tokens[i].Value)
+		}
+	}
+
+	if _, ok := gr.TokenValueAs[string](tokens[0]); ok {
+		t.Error("expected the value not to be a string")
+	}
+
+	err = l.SetInputStream([]byte("42 x"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = l.Lex()
+
+	var invalid *ErrInvalidValue
+
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected an *ErrInvalidValue, got %v", err)
+	}
+
+	if invalid.Span != gr.NewSpan(3, 4) || invalid.Data != "x" {
+		t.Errorf("expected \"x\" at [3, 4), got %q at %v", invalid.Data, invalid.Span)
+	}
+
+	var num_err *strconv.NumError
+
+	if !errors.As(err, &num_err) {
+		t.Errorf("expected the error to wrap a *strconv.NumError, got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package lexer
+
+import (
+	"fmt"
+	"slices"
+	"unicode"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gcch "github.com/PlayerR9/go-commons/runes"
+	gr "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/grammar/vocab"
+)
+
+// vocab_word is a word of a vocabulary included in a lexer.
+type vocab_word[T gr.Enumer] struct {
+	// type_ is the type of the tokens of the word.
+	type_ T
+
+	// chars are the characters of the word.
+	chars []rune
+}
+
+// match is a helper function that checks whether the word is at the start of
+// the given characters. A word that ends with a letter, a digit or an underscore
+// must not be followed by one, so that "SELECTION" is not "SELECT" and "ION".
+//
+// Parameters:
+//   - chars: The characters left in the input stream.
+//   - fold: True if the case of the characters is ignored.
+//
+// Returns:
+//   - bool: True if the word matches, false otherwise.
+func (w vocab_word[T]) match(chars []rune, fold bool) bool {
+	if len(chars) < len(w.chars) {
+		return false
+	}
+
+	for i, c := range w.chars {
+		if c != chars[i] && (!fold || !same_fold(c, chars[i])) {
+			return false
+		}
+	}
+
+	if len(chars) == len(w.chars) {
+		return true
+	}
+
+	return !is_word_char(w.chars[len(w.chars)-1]) || !is_word_char(chars[len(w.chars)])
+}
+
+// same_fold is a helper function that checks whether two characters are the same
+// regardless of their case.
+//
+// Parameters:
+//   - c: The first character.
+//   - char: The second character.
+//
+// Returns:
+//   - bool: True if the characters are the same, false otherwise.
+func same_fold(c, char rune) bool {
+	for r := unicode.SimpleFold(c); r != c; r = unicode.SimpleFold(r) {
+		if r == char {
+			return true
+		}
+	}
+
+	return false
+}
+
+// is_word_char is a helper function that checks whether a character can be part
+// of an identifier.
+//
+// Parameters:
+//   - c: The character.
+//
+// Returns:
+//   - bool: True if the character is a letter, a digit or an underscore.
+func is_word_char(c rune) bool {
+	return c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+// Include registers the words of a vocabulary, such as the ones of the vocab
+// packages. At each character, the longest word that matches is lexed; if none
+// does, the rule that was registered for that character, if any, or else the
+// default rule is used. The words of a case-insensitive vocabulary are matched
+// regardless of their case, and their tokens keep the text of the input.
+//
+// Parameters:
+//   - v: The vocabulary.
+//   - symbol_of: The function that gives the type of each word. Words for which
+//     it returns false are not registered.
+//
+// Returns:
+//   - error: An error if symbol_of is nil or if a word is not valid UTF-8.
+func (b *Builder[T]) Include(v vocab.Vocabulary, symbol_of vocab.SymbolFunc[T]) error {
+	if b == nil {
+		return nil
+	} else if symbol_of == nil {
+		return gcers.NewErrNilParameter("symbol_of")
+	}
+
+	groups := make(map[rune][]vocab_word[T])
+
+	for _, word := range v.Words {
+		type_, ok := symbol_of(word)
+		if !ok || word.Text == "" {
+			continue
+		}
+
+		chars, err := gcch.StringToUtf8(word.Text)
+		if err != nil {
+			return fmt.Errorf("word %q of %s: %w", word.Text, v.Name, err)
+		}
+
+		w := vocab_word[T]{
+			type_: type_,
+			chars: chars,
+		}
+
+		first := chars[0]
+		groups[first] = append(groups[first], w)
+
+		if !v.CaseInsensitive {
+			continue
+		}
+
+		for r := unicode.SimpleFold(first); r != first; r = unicode.SimpleFold(r) {
+			groups[r] = append(groups[r], w)
+		}
+	}
+
+	if b.table == nil {
+		b.table = make(map[rune]LexFunc[T])
+	}
+
+	for char, words := range groups {
+		slices.SortStableFunc(words, func(a, b vocab_word[T]) int {
+			return len(b.chars) - len(a.chars)
+		})
+
+		b.table[char] = include_fn(words, v.CaseInsensitive, b.table[char])
+	}
+
+	return nil
+}
+
+// include_fn is a helper function that makes the lexing function of the words
+// of a vocabulary that start with the same character.
+//
+// Parameters:
+//   - words: The words, longest first.
+//   - fold: True if the case of the input is ignored.
+//   - prev: The rule that was registered for the character. Nil if none.
+//
+// Returns:
+//   - LexFunc[T]: The lexing function. Never returns nil.
+func include_fn[T gr.Enumer](words []vocab_word[T], fold bool, prev LexFunc[T]) LexFunc[T] {
+	return func(lexer *Lexer[T]) (*gr.Token[T], error) {
+		for _, w := range words {
+			if !w.match(lexer.chars, fold) {
+				continue
+			}
+
+			data := string(lexer.chars[:len(w.chars)])
+
+			for range w.chars {
+				_, _ = lexer.NextRune()
+			}
+
+			return gr.NewTerminalToken(w.type_, data), nil
+		}
+
+		if prev != nil {
+			return prev(lexer)
+		} else if lexer.def_fn != nil {
+			return lexer.def_fn(lexer)
+		}
+
+		return nil, fmt.Errorf("unexpected character %q", lexer.chars[0])
+	}
+}
@@ -0,0 +1,82 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/grammar/vocab"
+	"github.com/PlayerR9/grammar/vocab/sql"
+)
+
+type sql_type int
+
+const (
+	st_eof sql_type = iota
+	st_ident
+	st_select
+	st_from
+	st_plus
+	st_less
+	st_less_equal
+)
+
+func (t sql_type) String() string {
+	return [...]string{"EOF", "Ident", "Select", "From", "Plus", "Less", "LessEqual"}[t]
+}
+
+// lex_test_ident is a helper function that lexes an identifier of letters.
+func lex_test_ident(l *Lexer[sql_type]) (*gr.Token[sql_type], error) {
+	var ident []rune
+
+	for {
+		c, ok := l.PeekRune()
+		if !ok || !unicode.IsLetter(c) {
+			break
+		}
+
+		_, _ = l.NextRune()
+		ident = append(ident, c)
+	}
+
+	return gr.NewTerminalToken(st_ident, string(ident)), nil
+}
+
+func TestInclude(t *testing.T) {
+	b := NewBuilder[sql_type]()
+
+	_ = b.RegisterSkip(" ")
+	b.RegisterDefault(lex_test_ident)
+
+	symbols := vocab.ByName("", st_select, st_from, st_plus, st_less, st_less_equal)
+
+	err := b.Include(sql.Vocabulary(), symbols)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	l := b.Build()
+
+	err = l.SetInputStream([]byte("select Selection From x <= y + z < w"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	err = l.Lex()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var elems []string
+
+	for _, tk := range l.Tokens() {
+		elems = append(elems, tk.Type.String()+":"+tk.Data)
+	}
+
+	const want = "Select:select Ident:Selection From:From Ident:x LessEqual:<= Ident:y Plus:+ Ident:z Less:< Ident:w EOF:"
+
+	if got := strings.Join(elems, " "); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,151 @@
+package grammar
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/PlayerR9/grammar/cache"
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// memo_table is the memoization of the results of a compiled grammar.
+type memo_table[T gr.Enumer] struct {
+	// mu protects the other fields.
+	mu sync.Mutex
+
+	// capacity is the maximum number of results that are kept. Zero if the
+	// memoization is disabled.
+	capacity int
+
+	// results maps the keys of the inputs to their result.
+	results map[string]gr.Result[*gr.Token[T]]
+
+	// order are the keys of the results, from the oldest to the newest.
+	order []string
+}
+
+// get is a helper function that returns the memoized result of an input.
+//
+// Parameters:
+//   - key: The key of the input.
+//
+// Returns:
+//   - gr.Result[*gr.Token[T]]: A copy of the result.
+//   - bool: True if the result is memoized, false otherwise.
+func (m *memo_table[T]) get(key string) (gr.Result[*gr.Token[T]], bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res, ok := m.results[key]
+	if !ok {
+		return gr.Result[*gr.Token[T]]{}, false
+	}
+
+	return res.Clone(), true
+}
+
+// put is a helper function that memoizes the result of an input. The oldest
+// result is dropped if the memoization is full.
+//
+// Parameters:
+//   - key: The key of the input.
+//   - res: The result. A copy of it is kept.
+func (m *memo_table[T]) put(key string, res gr.Result[*gr.Token[T]]) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.capacity <= 0 {
+		return
+	}
+
+	if _, ok := m.results[key]; ok {
+		return
+	}
+
+	for len(m.order) >= m.capacity {
+		delete(m.results, m.order[0])
+		m.order = m.order[1:]
+	}
+
+	m.results[key] = res.Clone()
+	m.order = append(m.order, key)
+}
+
+// EnableMemo makes Run memoize the results of the grammar: running it again on the
+// exact same bytes returns a copy of the previous result instead of lexing and
+// parsing them again. This is meant for build tools that parse unchanged files
+// over and over.
+//
+// Parameters:
+//   - capacity: The maximum number of results that are kept; the oldest one is
+//     dropped when it is exceeded. If not positive, the memoization is disabled.
+//
+// Changing the capacity drops the memoized results. Inputs that could not be
+// lexed are not memoized.
+func (g *CompiledGrammar[T]) EnableMemo(capacity int) {
+	if g == nil {
+		return
+	}
+
+	g.memo.mu.Lock()
+	defer g.memo.mu.Unlock()
+
+	g.memo.capacity = max(capacity, 0)
+	g.memo.results = nil
+	g.memo.order = nil
+
+	if capacity > 0 {
+		g.memo.results = make(map[string]gr.Result[*gr.Token[T]])
+	}
+}
+
+// ClearMemo drops the results memoized by Run. The memoization stays enabled.
+func (g *CompiledGrammar[T]) ClearMemo() {
+	if g == nil {
+		return
+	}
+
+	g.memo.mu.Lock()
+	defer g.memo.mu.Unlock()
+
+	clear(g.memo.results)
+	g.memo.order = nil
+}
+
+// memoized is a helper function that runs the grammar through its memoization.
+//
+// Parameters:
+//   - ctx: The context.
+//   - data: The input stream.
+//
+// Returns:
+//   - gr.Result[*gr.Token[T]]: The result of the parse.
+//   - error: An error if the data could not be lexed.
+func (g *CompiledGrammar[T]) memoized(ctx context.Context, data []byte) (gr.Result[*gr.Token[T]], error) {
+	g.memo.mu.Lock()
+	enabled := g.memo.capacity > 0
+	g.memo.mu.Unlock()
+
+	if !enabled {
+		_, res, err := g.run(ctx, data, nil, nil)
+		return res, err
+	}
+
+	key := cache.Key(data)
+
+	res, ok := g.memo.get(key)
+	if ok {
+		return res, nil
+	}
+
+	_, res, err := g.run(ctx, data, nil, nil)
+
+	var cancelled *gr.ErrCancelled
+
+	if err == nil && !errors.As(res.Err, &cancelled) {
+		g.memo.put(key, res)
+	}
+
+	return res, err
+}
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"fmt"
+
 	gcers "github.com/PlayerR9/go-commons/errors"
 	gr "github.com/PlayerR9/grammar/grammar"
 )
@@ -84,3 +86,53 @@ func NewAcceptAct[T gr.Enumer](rule *Rule[T]) (*AcceptAct[T], error) {
 func (a AcceptAct[T]) Rule() *Rule[T] {
 	return a.rule
 }
+
+// SplitAct is an action that splits the lookahead token into several tokens;
+// for instance, a '>>' into two '>' when closing nested generics.
+type SplitAct[T gr.Enumer] struct {
+	// types are the types of the parts.
+	types []T
+
+	// sizes are the sizes, in bytes, of the parts.
+	sizes []int
+}
+
+// NewSplitAct creates a new split action.
+//
+// Parameters:
+//   - types: The types of the parts, in order.
+//   - sizes: The sizes, in bytes, of the parts, in order. Their sum must be the
+//     size of the lookahead token.
+//
+// Returns:
+//   - *SplitAct: The new split action.
+//   - error: An error of type *errors.ErrInvalidParameter if there are less than
+//     two parts, if types and sizes do not have the same length, or if a size
+//     is not positive.
+func NewSplitAct[T gr.Enumer](types []T, sizes []int) (*SplitAct[T], error) {
+	if len(types) < 2 {
+		return nil, gcers.NewErrInvalidParameter("types", fmt.Errorf("expected at least 2 parts, got %d", len(types)))
+	} else if len(sizes) != len(types) {
+		return nil, gcers.NewErrInvalidParameter("sizes", fmt.Errorf("expected %d sizes, got %d", len(types), len(sizes)))
+	}
+
+	for i, size := range sizes {
+		if size <= 0 {
+			return nil, gcers.NewErrInvalidParameter("sizes", fmt.Errorf("size of part %d must be positive, got %d", i, size))
+		}
+	}
+
+	return &SplitAct[T]{
+		types: types,
+		sizes: sizes,
+	}, nil
+}
+
+// Parts returns the parts the lookahead token is split into.
+//
+// Returns:
+//   - []T: The types of the parts.
+//   - []int: The sizes, in bytes, of the parts.
+func (a SplitAct[T]) Parts() ([]T, []int) {
+	return a.types, a.sizes
+}
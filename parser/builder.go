@@ -6,6 +6,10 @@ import gr "github.com/PlayerR9/grammar/grammar"
 type Builder[T gr.Enumer] struct {
 	// table is the table of rules.
 	table map[T]ParseFunc[T]
+
+	// adjacency is the table of adjacency constraints. True if the pair of types
+	// must be adjacent, false if they must not.
+	adjacency map[[2]T]bool
 }
 
 // NewBuilder creates a new parser builder.
@@ -34,6 +38,46 @@ func (b *Builder[T]) Register(type_ T, fn ParseFunc[T]) {
 	b.table[type_] = fn
 }
 
+// MustBeAdjacent declares that a token of type left followed by a token of type
+// right must not be separated by anything (e.g., the two '>' of a '>>' operator).
+//
+// Parameters:
+//   - left: The type of the first token.
+//   - right: The type of the second token.
+//
+// Previous constraints on the same pair of types will be overwritten.
+func (b *Builder[T]) MustBeAdjacent(left, right T) {
+	if b == nil {
+		return
+	}
+
+	if b.adjacency == nil {
+		b.adjacency = make(map[[2]T]bool)
+	}
+
+	b.adjacency[[2]T{left, right}] = true
+}
+
+// MustNotBeAdjacent declares that a token of type left followed by a token of type
+// right must be separated by something (e.g., the two '>' closing nested generics).
+//
+// Parameters:
+//   - left: The type of the first token.
+//   - right: The type of the second token.
+//
+// Previous constraints on the same pair of types will be overwritten.
+func (b *Builder[T]) MustNotBeAdjacent(left, right T) {
+	if b == nil {
+		return
+	}
+
+	if b.adjacency == nil {
+		b.adjacency = make(map[[2]T]bool)
+	}
+
+	b.adjacency[[2]T{left, right}] = false
+}
+
 // Build builds a parser.
 //
 // Returns:
@@ -45,8 +89,15 @@ func (b Builder[T]) Build() *Parser[T] {
 		table[k] = v
 	}
 
+	adjacency := make(map[[2]T]bool, len(b.adjacency))
+
+	for k, v := range b.adjacency {
+		adjacency[k] = v
+	}
+
 	return &Parser[T]{
-		table: table,
+		table:     table,
+		adjacency: adjacency,
 	}
 }
 
@@ -62,4 +113,10 @@ func (b *Builder[T]) Reset() {
 	}
 
 	b.table = nil
+
+	for k := range b.adjacency {
+		delete(b.adjacency, k)
+	}
+
+	b.adjacency = nil
 }
@@ -138,3 +138,101 @@ func NewErrUnexpectedToken[T gr.Enumer](left, right T, got *T) *ErrUnexpectedTok
 		Got:   got,
 	}
 }
+
+// ErrAdjacency is an error that occurs when two tokens violate an adjacency
+// constraint.
+type ErrAdjacency[T gr.Enumer] struct {
+	// Left is the type of the first token.
+	Left T
+
+	// Right is the type of the second token.
+	Right T
+
+	// Adjacent is true if the tokens had to be adjacent, false if they had to be
+	// separated.
+	Adjacent bool
+}
+
+// Error implements the error interface.
+//
+// Message: "<left> and <right> must be adjacent" or "<left> and <right> must not be adjacent"
+func (e ErrAdjacency[T]) Error() string {
+	if e.Adjacent {
+		return fmt.Sprintf("%q and %q must be adjacent", e.Left.String(), e.Right.String())
+	} else {
+		return fmt.Sprintf("%q and %q must not be adjacent", e.Left.String(), e.Right.String())
+	}
+}
+
+// NewErrAdjacency creates a new ErrAdjacency error.
+//
+// Parameters:
+//   - left: The type of the first token.
+//   - right: The type of the second token.
+//   - adjacent: True if the tokens had to be adjacent, false if they had to be separated.
+//
+// Returns:
+//   - *ErrAdjacency: The new error. Never returns nil.
+func NewErrAdjacency[T gr.Enumer](left, right T, adjacent bool) *ErrAdjacency[T] {
+	return &ErrAdjacency[T]{
+		Left:     left,
+		Right:    right,
+		Adjacent: adjacent,
+	}
+}
+
+// ErrTreeLimit is an error that occurs when the parse tree exceeds one of the
+// limits of the parser.
+type ErrTreeLimit[T gr.Enumer] struct {
+	// Limit is the name of the exceeded limit. (i.e., "depth" or "nodes")
+	Limit string
+
+	// Max is the value of the limit.
+	Max int
+
+	// Chain are the types of the nonterminals along the deepest path of the node
+	// that exceeded the limit, from the node down.
+	Chain []T
+}
+
+// Error implements the error interface.
+//
+// Message: "parse tree exceeds the maximum <limit> of <max> (deepest rule chain: <chain>)"
+func (e ErrTreeLimit[T]) Error() string {
+	msg := fmt.Sprintf("parse tree exceeds the maximum %s of %d", e.Limit, e.Max)
+
+	if len(e.Chain) == 0 {
+		return msg
+	}
+
+	const max_shown = 8
+
+	elems := make([]string, 0, min(len(e.Chain), max_shown)+1)
+
+	for _, type_ := range e.Chain[:min(len(e.Chain), max_shown)] {
+		elems = append(elems, type_.String())
+	}
+
+	if len(e.Chain) > max_shown {
+		elems = append(elems, fmt.Sprintf("... (%d more)", len(e.Chain)-max_shown))
+	}
+
+	return msg + " (deepest rule chain: " + strings.Join(elems, " > ") + ")"
+}
+
+// NewErrTreeLimit creates a new ErrTreeLimit error.
+//
+// Parameters:
+//   - limit: The name of the exceeded limit.
+//   - value: The value of the limit.
+//   - chain: The deepest chain of rules of the node that exceeded the limit.
+//
+// Returns:
+//   - *ErrTreeLimit: The new error. Never returns nil.
+func NewErrTreeLimit[T gr.Enumer](limit string, value int, chain []T) *ErrTreeLimit[T] {
+	return &ErrTreeLimit[T]{
+		Limit: limit,
+		Max:   value,
+		Chain: chain,
+	}
+}
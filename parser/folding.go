@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"slices"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// FoldingRange is a range of lines that editors can fold.
+type FoldingRange[T gr.Enumer] struct {
+	// Type is the type of the node that is folded.
+	Type T
+
+	// Span is the span of the node.
+	Span gr.Span
+
+	// StartLine is the first line of the range. The first line of the input is 1.
+	StartLine int
+
+	// EndLine is the last line of the range.
+	EndLine int
+}
+
+// FoldingRanges returns the folding ranges of the forest; that is, the ranges of
+// the nodes produced by foldable rules that span more than one line.
+//
+// Parameters:
+//   - data: The input stream the forest was parsed from.
+//   - forest: The forest.
+//   - rules: The rules of the grammar. Rules that are not foldable are ignored.
+//
+// Returns:
+//   - []FoldingRange[T]: The folding ranges, ordered by their first line and from
+//     the outermost to the innermost. Ranges that cover the same lines as a
+//     previous one are omitted.
+func FoldingRanges[T gr.Enumer](data []byte, forest []*gr.Token[T], rules ...*Rule[T]) []FoldingRange[T] {
+	var foldable []*Rule[T]
+
+	for _, rule := range rules {
+		if rule != nil && rule.Foldable() {
+			foldable = append(foldable, rule)
+		}
+	}
+
+	if len(foldable) == 0 {
+		return nil
+	}
+
+	var ranges []FoldingRange[T]
+
+	stack := slices.Clone(forest)
+	slices.Reverse(stack)
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if top == nil || len(top.Children) == 0 {
+			continue
+		}
+
+		for i := len(top.Children) - 1; i >= 0; i-- {
+			stack = append(stack, top.Children[i])
+		}
+
+		ok := slices.ContainsFunc(foldable, func(rule *Rule[T]) bool {
+			return rule.produced(top)
+		})
+		if !ok {
+			continue
+		}
+
+		span := top.Span()
+		if span.Start < 0 {
+			continue
+		}
+
+		first, last := span.Lines(data)
+		if first == last {
+			continue
+		}
+
+		ok = slices.ContainsFunc(ranges, func(fr FoldingRange[T]) bool {
+			return fr.StartLine == first && fr.EndLine == last
+		})
+		if ok {
+			continue
+		}
+
+		ranges = append(ranges, FoldingRange[T]{
+			Type:      top.Type,
+			Span:      span,
+			StartLine: first,
+			EndLine:   last,
+		})
+	}
+
+	slices.SortStableFunc(ranges, func(a, b FoldingRange[T]) int {
+		return a.StartLine - b.StartLine
+	})
+
+	return ranges
+}
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// Limits are the caps of a parse; they guard against pathological inputs, such as
+// deeply nested expressions, that would otherwise blow up the parse tree. A
+// non-positive limit means that the tree is not limited.
+type Limits struct {
+	// MaxDepth is the maximum depth of the parse tree. Terminal tokens have a depth
+	// of 1.
+	MaxDepth int
+
+	// MaxNodes is the maximum number of nodes created by reductions.
+	MaxNodes int
+}
+
+// SetLimits sets the caps of every subsequent call to Parse. When one of them is
+// exceeded, the parse fails with an error of type *ErrTreeLimit.
+//
+// Parameters:
+//   - limits: The limits.
+func (p *Parser[T]) SetLimits(limits Limits) {
+	if p == nil {
+		return
+	}
+
+	p.limits = limits
+}
+
+// check_limits is a helper function that records the node that was just reduced
+// and checks it against the limits. Nothing is recorded if no limit is set.
+//
+// Parameters:
+//   - tk: The reduced node. Assumed to be non-nil.
+//
+// Returns:
+//   - error: An error of type *ErrTreeLimit if a limit is exceeded.
+func (p *Parser[T]) check_limits(tk *gr.Token[T]) error {
+	if p.limits.MaxDepth <= 0 && p.limits.MaxNodes <= 0 {
+		return nil
+	}
+
+	p.nodes++
+
+	depth := 0
+
+	for _, child := range tk.Children {
+		depth = max(depth, p.depth_of(child))
+	}
+
+	depth++
+
+	if p.depths == nil {
+		p.depths = make(map[*gr.Token[T]]int)
+	}
+
+	p.depths[tk] = depth
+
+	if p.limits.MaxDepth > 0 && depth > p.limits.MaxDepth {
+		return NewErrTreeLimit("depth", p.limits.MaxDepth, p.chain(tk))
+	}
+
+	if p.limits.MaxNodes > 0 && p.nodes > p.limits.MaxNodes {
+		return NewErrTreeLimit("nodes", p.limits.MaxNodes, p.chain(tk))
+	}
+
+	return nil
+}
+
+// depth_of is a helper function that returns the depth of a node.
+//
+// Parameters:
+//   - tk: The node.
+//
+// Returns:
+//   - int: The depth of the node. 1 for terminal tokens.
+func (p Parser[T]) depth_of(tk *gr.Token[T]) int {
+	depth, ok := p.depths[tk]
+	if !ok {
+		return 1
+	}
+
+	return depth
+}
+
+// chain is a helper function that returns the deepest chain of rules of a node.
+//
+// Parameters:
+//   - tk: The node.
+//
+// Returns:
+//   - []T: The types of the nonterminals along the deepest path, from the node down.
+func (p Parser[T]) chain(tk *gr.Token[T]) []T {
+	var chain []T
+
+	for tk != nil && len(tk.Children) > 0 {
+		chain = append(chain, tk.Type)
+
+		var deepest *gr.Token[T]
+
+		for _, child := range tk.Children {
+			if deepest == nil || p.depth_of(child) > p.depth_of(deepest) {
+				deepest = child
+			}
+		}
+
+		tk = deepest
+	}
+
+	return chain
+}
@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetLimits(t *testing.T) {
+	p := new_test_parser(t)
+
+	res := p.ParseResult(lex_test_input("a"))
+	if res.Err != nil {
+		t.Fatalf("expected no error, got %v", res.Err)
+	}
+
+	if len(p.depths) != 0 || p.nodes != 0 {
+		t.Errorf("expected nothing recorded without limits, got %d depths and %d nodes", len(p.depths), p.nodes)
+	}
+
+	p.SetLimits(Limits{MaxDepth: 1})
+
+	res = p.ParseResult(lex_test_input("a"))
+
+	var limit_err *ErrTreeLimit[test_type]
+
+	if !errors.As(res.Err, &limit_err) {
+		t.Fatalf("expected an *ErrTreeLimit, got %v", res.Err)
+	}
+
+	p.SetLimits(Limits{MaxNodes: 1})
+
+	res = p.ParseResult(lex_test_input("a"))
+	if res.Err != nil {
+		t.Errorf("expected no error, got %v", res.Err)
+	}
+}
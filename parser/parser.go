@@ -1,9 +1,12 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 	"slices"
+	"time"
 
+	gcers "github.com/PlayerR9/go-commons/errors"
 	gr "github.com/PlayerR9/grammar/grammar"
 )
 
@@ -32,6 +35,87 @@ type Parser[T gr.Enumer] struct {
 
 	// popped is the list of tokens that have been popped.
 	popped []*gr.Token[T]
+
+	// adjacency is the table of adjacency constraints. True if the pair of types
+	// must be adjacent, false if they must not.
+	adjacency map[[2]T]bool
+
+	// last is the last token that was shifted. Nil if none.
+	last *gr.Token[T]
+
+	// user_ctx is the user context of the parse in progress. Nil if none.
+	user_ctx any
+
+	// metrics are the metrics to report to. Nil if none.
+	metrics gr.Metrics
+
+	// limits are the caps of a parse.
+	limits Limits
+
+	// nodes is the number of nodes created by the current parse.
+	nodes int
+
+	// depths are the depths of the nodes created by the current parse.
+	depths map[*gr.Token[T]]int
+}
+
+// SetMetrics sets the metrics to which every call to Parse is reported.
+//
+// Parameters:
+//   - m: The metrics. Nil stops the reporting.
+func (p *Parser[T]) SetMetrics(m gr.Metrics) {
+	if p == nil {
+		return
+	}
+
+	p.metrics = m
+}
+
+// Clone returns a new parser with the same parse functions and settings, but
+// without any parse in progress; so that several goroutines can parse with the
+// same rules at once.
+//
+// Returns:
+//   - *Parser[T]: The new parser. Nil if the receiver is nil.
+func (p *Parser[T]) Clone() *Parser[T] {
+	if p == nil {
+		return nil
+	}
+
+	return &Parser[T]{
+		table:     p.table,
+		adjacency: p.adjacency,
+		metrics:   p.metrics,
+		limits:    p.limits,
+	}
+}
+
+// UserContext returns the user context of the parse in progress; that is, the one
+// given to ParseResultWith. Parse functions use it to reach the state of the
+// application without global variables.
+//
+// Returns:
+//   - any: The user context. Nil if none was given or if no parse is in progress.
+func (p Parser[T]) UserContext() any {
+	return p.user_ctx
+}
+
+// UserContextAs returns the user context of the parse in progress as a value of
+// type C. See UserContext.
+//
+// Parameters:
+//   - p: The parser.
+//
+// Returns:
+//   - C: The user context.
+//   - bool: True if the parse has a user context of type C, false otherwise.
+func UserContextAs[C any, T gr.Enumer](p *Parser[T]) (C, bool) {
+	if p == nil {
+		return *new(C), false
+	}
+
+	ctx, ok := p.user_ctx.(C)
+	return ctx, ok
 }
 
 // Pop pops a token from the stack.
@@ -40,12 +124,12 @@ type Parser[T gr.Enumer] struct {
 //   - *gr.Token[T]: The popped token.
 //   - bool: True if the token was popped, false otherwise.
 func (p *Parser[T]) Pop() (*gr.Token[T], bool) {
-	if len(p.tokens) == 0 {
+	if len(p.stack) == 0 {
 		return nil, false
 	}
 
-	tk := p.tokens[0]
-	p.tokens = p.tokens[1:]
+	tk := p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
 
 	p.popped = append(p.popped, tk)
 
@@ -57,7 +141,10 @@ func (p *Parser[T]) Pop() (*gr.Token[T], bool) {
 // Returns:
 //   - Actioner: The action to perform.
 //   - error: An error if the decision is invalid.
-func (p *Parser[T]) decision() (Actioner, error) {
+//
+// A panic of the decision function is returned as an error of type
+// *gr.ErrPanic.
+func (p *Parser[T]) decision() (act Actioner, err error) {
 	top1, ok := p.Pop()
 	if !ok {
 		return nil, fmt.Errorf("unexpected EOF")
@@ -68,7 +155,9 @@ func (p *Parser[T]) decision() (Actioner, error) {
 		return nil, fmt.Errorf("unexpected token: %v", top1)
 	}
 
-	act, err := fn(p, top1, top1.Lookahead)
+	defer gr.Recover(&err)
+
+	act, err = fn(p, top1, top1.Lookahead)
 	if err != nil {
 		return nil, err
 	}
@@ -97,11 +186,100 @@ func (p *Parser[T]) shift() bool {
 	return true
 }
 
+// check_adjacency is a helper function that checks the adjacency constraints
+// between the last two tokens that were shifted.
+//
+// Returns:
+//   - error: An error of type *ErrAdjacency if a constraint is violated.
+func (p *Parser[T]) check_adjacency() error {
+	top := p.stack[len(p.stack)-1]
+
+	prev := p.last
+	p.last = top
+
+	if prev == nil || prev.Pos < 0 || top.Pos < 0 {
+		return nil
+	}
+
+	want, ok := p.adjacency[[2]T{prev.Type, top.Type}]
+	if !ok {
+		return nil
+	}
+
+	got := prev.Span().End == top.Pos
+	if got == want {
+		return nil
+	}
+
+	return NewErrAdjacency(prev.Type, top.Type, want)
+}
+
+// split is a helper function that splits the lookahead token into several tokens,
+// each with its own span.
+//
+// Parameters:
+//   - act: The split action.
+//
+// Returns:
+//   - error: An error if there is no lookahead or if the parts do not cover it.
+func (p *Parser[T]) split(act *SplitAct[T]) error {
+	if len(p.tokens) == 0 {
+		return fmt.Errorf("nothing to split")
+	}
+
+	la := p.tokens[0]
+	span := la.Span()
+
+	types, sizes := act.Parts()
+
+	total := 0
+
+	for _, size := range sizes {
+		total += size
+	}
+
+	if total != len(la.Data) {
+		return fmt.Errorf("cannot split %q into %d bytes", la.Data, total)
+	}
+
+	parts := make([]*gr.Token[T], 0, len(types))
+	offset := 0
+
+	for i, type_ := range types {
+		part := gr.NewTerminalToken(type_, la.Data[offset:offset+sizes[i]])
+
+		// Each part covers its own bytes of the span of the lookahead; the parts of
+		// a token without a position have none either.
+		part.Pos = -1
+
+		if span.Start >= 0 {
+			part.Pos = span.Start + offset
+		}
+
+		if len(parts) > 0 {
+			parts[len(parts)-1].Lookahead = part
+		}
+
+		parts = append(parts, part)
+		offset += sizes[i]
+	}
+
+	parts[len(parts)-1].Lookahead = la.Lookahead
+
+	if len(p.stack) > 0 {
+		p.stack[len(p.stack)-1].Lookahead = parts[0]
+	}
+
+	p.tokens = append(parts, p.tokens[1:]...)
+
+	return nil
+}
+
 // refuse is a helper function that refuses all tokens that were popped.
 func (p *Parser[T]) refuse() {
 	for len(p.popped) > 0 {
-		top := p.popped[0]
-		p.popped = p.popped[1:]
+		top := p.popped[len(p.popped)-1]
+		p.popped = p.popped[:len(p.popped)-1]
 
 		p.stack = append(p.stack, top)
 	}
@@ -134,26 +312,26 @@ func (p Parser[T]) get_popped() []*gr.Token[T] {
 //   - error: An error if the rule could not be reduced.
 func (p *Parser[T]) reduce(rule *Rule[T]) error {
 	if rule == nil {
-		panic("rule should not be nil")
+		return gcers.NewErrNilParameter("rule")
 	}
 
 	for rhs := range rule.BackwardRhs() {
 		top, ok := p.Pop()
 		if !ok {
 			return NewErrUnexpectedToken(rhs, rhs, nil)
-		} else if top.Type != rhs {
+		} else if !rule.accepts(rhs, top.Type) {
 			return NewErrUnexpectedToken(rhs, rhs, &top.Type)
 		}
 	}
 
 	popped := p.get_popped()
 	if len(popped) == 0 {
-		panic("popped should not be empty")
+		return gcers.NewErrInvalidParameter("rule", gcers.NewErrEmpty(rule.rhss))
 	}
 
 	tk, err := gr.NewToken(rule.Lhs(), "", popped)
 	if err != nil {
-		panic(fmt.Sprintf("could not create token: %v", err))
+		return fmt.Errorf("could not create token: %w", err)
 	}
 
 	p.stack = append(p.stack, tk)
@@ -161,6 +339,50 @@ func (p *Parser[T]) reduce(rule *Rule[T]) error {
 	return nil
 }
 
+// forest returns a copy of the stack, from the bottom to the top.
+//
+// Returns:
+//   - []*gr.Token[T]: The forest.
+func (p Parser[T]) forest() []*gr.Token[T] {
+	forest := make([]*gr.Token[T], len(p.stack))
+	copy(forest, p.stack)
+
+	return forest
+}
+
+// fail makes the result of a failed parse. The stack holds the largest well-formed
+// prefix of the forest, and the failure is located at the given token.
+//
+// Parameters:
+//   - err: The reason of the failure.
+//   - at: The token that could not be parsed. Nil if unknown.
+//
+// Returns:
+//   - gr.Result[*gr.Token[T]]: The failed result.
+func (p Parser[T]) fail(err error, at *gr.Token[T]) gr.Result[*gr.Token[T]] {
+	res := gr.NewFailedResult(p.forest(), err)
+
+	if at == nil {
+		return res
+	}
+
+	return res.At(at.Span().Start)
+}
+
+// lookahead is a helper function that returns the token the parser could not go
+// past: the next token of the input or, once the input is exhausted, the last
+// token that was shifted.
+//
+// Returns:
+//   - *gr.Token[T]: The token. Nil if no token was shifted.
+func (p Parser[T]) lookahead() *gr.Token[T] {
+	if len(p.tokens) > 0 {
+		return p.tokens[0]
+	}
+
+	return p.last
+}
+
 // Parse parses a list of tokens.
 //
 // Parameters:
@@ -169,55 +391,172 @@ func (p *Parser[T]) reduce(rule *Rule[T]) error {
 // Returns:
 //   - *gr.Token[T]: The root token of the parse tree.
 //   - error: An error if the parse failed.
+//
+// Use ParseResult to get the partial forest and the diagnostics of the parse.
 func (p *Parser[T]) Parse(tokens []*gr.Token[T]) (*gr.Token[T], error) {
+	res := p.ParseResult(tokens)
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	return res.Forest[0], nil
+}
+
+// ParseResult is like Parse but returns the result of the parse.
+//
+// Parameters:
+//   - tokens: The list of tokens to parse.
+//
+// Returns:
+//   - gr.Result[*gr.Token[T]]: The result of the parse. On success, its forest
+//     holds the root token of the parse tree.
+func (p *Parser[T]) ParseResult(tokens []*gr.Token[T]) gr.Result[*gr.Token[T]] {
+	return p.ParseResultWith(nil, tokens)
+}
+
+// ParseResultWith is like ParseResult but gives a user context to the parse
+// functions, through UserContext, for the duration of the parse. The context is
+// not kept afterwards, so concurrent sessions that parse with their own clone of
+// the parser (see Clone) each see their own context.
+//
+// Parameters:
+//   - user_ctx: The user context. Nil for none.
+//   - tokens: The list of tokens to parse.
+//
+// Returns:
+//   - gr.Result[*gr.Token[T]]: The result of the parse. On success, its forest
+//     holds the root token of the parse tree.
+func (p *Parser[T]) ParseResultWith(user_ctx any, tokens []*gr.Token[T]) gr.Result[*gr.Token[T]] {
+	return p.ParseResultWithCtx(context.Background(), user_ctx, tokens)
+}
+
+// ParseResultCtx is like ParseResult but stops as soon as the context is done.
+//
+// Parameters:
+//   - ctx: The context.
+//   - tokens: The list of tokens to parse.
+//
+// Returns:
+//   - gr.Result[*gr.Token[T]]: The result of the parse. If the context is done
+//     before the end of the parse, its error is of type *gr.ErrCancelled and its
+//     forest holds what was parsed so far.
+func (p *Parser[T]) ParseResultCtx(ctx context.Context, tokens []*gr.Token[T]) gr.Result[*gr.Token[T]] {
+	return p.ParseResultWithCtx(ctx, nil, tokens)
+}
+
+// ParseResultWithCtx combines ParseResultWith and ParseResultCtx.
+//
+// Parameters:
+//   - ctx: The context.
+//   - user_ctx: The user context. Nil for none.
+//   - tokens: The list of tokens to parse.
+//
+// Returns:
+//   - gr.Result[*gr.Token[T]]: The result of the parse, as for ParseResultCtx.
+func (p *Parser[T]) ParseResultWithCtx(ctx context.Context, user_ctx any, tokens []*gr.Token[T]) gr.Result[*gr.Token[T]] {
+	start := time.Now()
+
+	p.user_ctx = user_ctx
+	defer func() {
+		p.user_ctx = nil
+	}()
+
+	res := p.parse(ctx, tokens)
+
+	gr.Record(p.metrics, gr.OpParse, start, len(tokens), res.Err)
+
+	return res
+}
+
+// parse is a helper function that parses a list of tokens.
+//
+// Parameters:
+//   - ctx: The context.
+//   - tokens: The list of tokens to parse.
+//
+// Returns:
+//   - gr.Result[*gr.Token[T]]: The result of the parse.
+func (p *Parser[T]) parse(ctx context.Context, tokens []*gr.Token[T]) gr.Result[*gr.Token[T]] {
+	p.tokens = tokens
+	p.stack = p.stack[:0]
+	p.popped = p.popped[:0]
+	p.last = nil
+	p.nodes = 0
+	clear(p.depths)
+
 	if !p.shift() {
-		return nil, fmt.Errorf("nothing to parse")
+		return gr.NewFailedResult[*gr.Token[T]](nil, fmt.Errorf("nothing to parse"))
 	}
 
-	for {
+	p.last = p.stack[0]
+
+	for steps := 0; ; steps++ {
+		if err := ctx.Err(); err != nil {
+			return p.fail(gr.NewErrCancelled(err, steps), p.lookahead())
+		}
+
 		act, err := p.decision()
 		p.refuse()
 
 		if err != nil {
-			return nil, err
+			return p.fail(err, p.lookahead())
 		} else if act == nil {
-			return nil, fmt.Errorf("no decision was made")
+			return p.fail(fmt.Errorf("no decision was made"), p.lookahead())
 		}
 
 		switch act := act.(type) {
 		case *ShiftAct:
 			if !p.shift() {
-				return nil, fmt.Errorf("could not shift")
+				return p.fail(fmt.Errorf("could not shift"), p.lookahead())
+			}
+
+			err := p.check_adjacency()
+			if err != nil {
+				return p.fail(err, p.last)
+			}
+		case *SplitAct[T]:
+			err := p.split(act)
+			if err != nil {
+				return p.fail(err, p.lookahead())
 			}
 		case *ReduceAct[T]:
 			err := p.reduce(act.Rule())
 			if err != nil {
-				return nil, err
+				p.refuse()
+
+				return p.fail(err, p.lookahead())
 			}
 
 			p.accept()
+
+			err = p.check_limits(p.stack[len(p.stack)-1])
+			if err != nil {
+				return p.fail(err, p.stack[len(p.stack)-1])
+			}
 		case *AcceptAct[T]:
 			err := p.reduce(act.Rule())
 			if err != nil {
-				return nil, err
+				p.refuse()
+
+				return p.fail(err, p.lookahead())
 			}
 
 			p.accept()
 
-			forest := make([]*gr.Token[T], len(p.stack))
-			copy(forest, p.stack)
+			err = p.check_limits(p.stack[len(p.stack)-1])
+			if err != nil {
+				return p.fail(err, p.stack[len(p.stack)-1])
+			}
 
-			slices.Reverse(forest)
+			forest := p.forest()
 
 			if len(forest) != 1 {
-				return nil, fmt.Errorf("expected exactly one root but got %d", len(forest))
+				return gr.NewFailedResult(forest, fmt.Errorf("expected exactly one root but got %d", len(forest)))
 			}
 
-			root := forest[0]
-
-			return root, nil
+			return gr.NewResult(forest[0])
 		default:
-			return nil, fmt.Errorf("unexpected action: %T", act)
+			return p.fail(fmt.Errorf("unexpected action: %T", act), p.lookahead())
 		}
 	}
 }
@@ -0,0 +1,287 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+type test_type int
+
+const (
+	tt_eof test_type = iota
+	tt_word
+	nt_source
+)
+
+func (t test_type) String() string {
+	return [...]string{"EOF", "Word", "Source"}[t]
+}
+
+// new_test_parser is a helper function that makes the parser of "Source -> Word EOF".
+func new_test_parser(t *testing.T) *Parser[test_type] {
+	rule, err := NewRule(nt_source, tt_word, tt_eof)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	b := NewBuilder[test_type]()
+
+	b.Register(tt_word, func(_ *Parser[test_type], top1, la *gr.Token[test_type]) (Actioner, error) {
+		if la == nil || la.Type != tt_eof {
+			return nil, fmt.Errorf("expected EOF after %q", top1.Data)
+		}
+
+		return NewShiftAct(), nil
+	})
+
+	b.Register(tt_eof, func(_ *Parser[test_type], _, _ *gr.Token[test_type]) (Actioner, error) {
+		return NewAcceptAct(rule)
+	})
+
+	return b.Build()
+}
+
+// lex_test_input is a helper function that makes the tokens of the given words,
+// one byte apart, followed by the EOF token.
+func lex_test_input(words ...string) []*gr.Token[test_type] {
+	tokens := make([]*gr.Token[test_type], 0, len(words)+1)
+	pos := 0
+
+	for _, word := range words {
+		tk := gr.NewTerminalToken(tt_word, word)
+		tk.Pos = pos
+
+		tokens = append(tokens, tk)
+		pos += len(word) + 1
+	}
+
+	eof := gr.NewTerminalToken(tt_eof, "")
+	eof.Pos = -1
+
+	tokens = append(tokens, eof)
+
+	gr.LinkLookaheads(tokens)
+
+	return tokens
+}
+
+func TestParseResultErrPos(t *testing.T) {
+	p := new_test_parser(t)
+
+	res := p.ParseResult(lex_test_input("a"))
+	if res.Err != nil {
+		t.Fatalf("expected no error, got %v", res.Err)
+	} else if res.ErrPos != -1 {
+		t.Errorf("expected an error position of %d, got %d", -1, res.ErrPos)
+	}
+
+	// The decision fails on "abc" but the failure is at the token it cannot go past.
+	res = p.ParseResult(lex_test_input("abc", "de"))
+	if res.Err == nil {
+		t.Fatal("expected an error, got nil")
+	} else if res.ErrPos != 4 {
+		t.Errorf("expected an error position of %d, got %d", 4, res.ErrPos)
+	}
+
+	forest, pos := res.PartialForest()
+	if pos != 4 {
+		t.Errorf("expected a partial forest position of %d, got %d", 4, pos)
+	} else if len(forest) != 1 || forest[0].Data != "abc" {
+		t.Errorf("expected the partial forest to hold %q, got %v", "abc", forest)
+	}
+}
+
+func TestParseSplit(t *testing.T) {
+	rule, err := NewRule(nt_source, tt_word, tt_word, tt_word, tt_eof)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	b := NewBuilder[test_type]()
+
+	b.Register(tt_word, func(_ *Parser[test_type], _, la *gr.Token[test_type]) (Actioner, error) {
+		if la != nil && len(la.Data) == 2 {
+			return NewSplitAct([]test_type{tt_word, tt_word}, []int{1, 1})
+		}
+
+		return NewShiftAct(), nil
+	})
+
+	b.Register(tt_eof, func(_ *Parser[test_type], _, _ *gr.Token[test_type]) (Actioner, error) {
+		return NewAcceptAct(rule)
+	})
+
+	p := b.Build()
+
+	tests := []struct {
+		pos  int
+		want []gr.Span
+	}{
+		{2, []gr.Span{gr.NewSpan(2, 3), gr.NewSpan(3, 4)}},
+		{-1, []gr.Span{gr.NewSpan(-1, 0), gr.NewSpan(-1, 0)}},
+	}
+
+	for _, test := range tests {
+		tokens := lex_test_input("x", "ab")
+		tokens[1].Pos = test.pos
+
+		root, err := p.Parse(tokens)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		for i, want := range test.want {
+			part := root.Children[i+1]
+
+			if got := part.Span(); got != want {
+				t.Errorf("position %d: expected part %d to span %v, got %v", test.pos, i, want, got)
+			}
+		}
+	}
+}
+
+func TestParsePanic(t *testing.T) {
+	b := NewBuilder[test_type]()
+
+	b.Register(tt_word, func(_ *Parser[test_type], _, _ *gr.Token[test_type]) (Actioner, error) {
+		panic("boom")
+	})
+
+	p := b.Build()
+
+	res := p.ParseResult(lex_test_input("a"))
+
+	var panic_err *gr.ErrPanic
+
+	if !errors.As(res.Err, &panic_err) {
+		t.Fatalf("expected an *ErrPanic, got %v", res.Err)
+	} else if panic_err.Value != "boom" {
+		t.Errorf("expected the value %q, got %v", "boom", panic_err.Value)
+	}
+
+	forest, _ := res.PartialForest()
+	if len(forest) != 1 || forest[0].Data != "a" {
+		t.Errorf("expected the partial forest to hold %q, got %v", "a", forest)
+	}
+}
+
+func TestParseReduceInvalidRule(t *testing.T) {
+	tests := []struct {
+		name string
+		rule *Rule[test_type]
+	}{
+		{"nil rule", nil},
+		{"empty rule", &Rule[test_type]{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBuilder[test_type]()
+
+			b.Register(tt_word, func(_ *Parser[test_type], _, _ *gr.Token[test_type]) (Actioner, error) {
+				return &ReduceAct[test_type]{rule: tt.rule}, nil
+			})
+
+			p := b.Build()
+
+			res := p.ParseResult(lex_test_input("a"))
+			if res.Err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestParseResultWith(t *testing.T) {
+	rule, err := NewRule(nt_source, tt_word, tt_eof)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var seen []any
+
+	b := NewBuilder[test_type]()
+
+	b.Register(tt_word, func(p *Parser[test_type], _, _ *gr.Token[test_type]) (Actioner, error) {
+		seen = append(seen, p.UserContext())
+
+		return NewShiftAct(), nil
+	})
+
+	b.Register(tt_eof, func(p *Parser[test_type], _, _ *gr.Token[test_type]) (Actioner, error) {
+		name, _ := UserContextAs[string](p)
+		seen = append(seen, name)
+
+		return NewAcceptAct(rule)
+	})
+
+	p := b.Build()
+
+	res := p.ParseResultWith("ctx", lex_test_input("a"))
+	if res.Err != nil {
+		t.Fatalf("expected no error, got %v", res.Err)
+	}
+
+	if len(seen) != 2 || seen[0] != "ctx" || seen[1] != "ctx" {
+		t.Errorf("expected the parse functions to see %q, got %v", "ctx", seen)
+	}
+
+	if got := p.UserContext(); got != nil {
+		t.Errorf("expected no user context after the parse, got %v", got)
+	}
+
+	seen = nil
+
+	_ = p.ParseResult(lex_test_input("a"))
+
+	if len(seen) != 2 || seen[0] != nil || seen[1] != "" {
+		t.Errorf("expected the next parse to see no user context, got %v", seen)
+	}
+}
+
+func TestParseResultCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := NewBuilder[test_type]()
+
+	b.Register(tt_word, func(_ *Parser[test_type], top1, _ *gr.Token[test_type]) (Actioner, error) {
+		if top1.Data == "b" {
+			cancel()
+		}
+
+		return NewShiftAct(), nil
+	})
+
+	p := b.Build()
+
+	res := p.ParseResultCtx(ctx, lex_test_input("a", "b", "c", "d"))
+
+	var cancelled *gr.ErrCancelled
+
+	if !errors.As(res.Err, &cancelled) {
+		t.Fatalf("expected an *ErrCancelled, got %v", res.Err)
+	} else if cancelled.Steps != 2 {
+		t.Errorf("expected 2 steps, got %d", cancelled.Steps)
+	}
+
+	forest, at := res.PartialForest()
+
+	var words []string
+
+	for _, tk := range forest {
+		words = append(words, tk.Data)
+	}
+
+	if fmt.Sprint(words) != "[a b c]" {
+		t.Errorf("expected the partial forest [a b c], got %v", words)
+	}
+
+	if at != 6 {
+		t.Errorf("expected the failure at 6, got %d", at)
+	}
+}
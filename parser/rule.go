@@ -7,6 +7,18 @@ import (
 	gr "github.com/PlayerR9/grammar/grammar"
 )
 
+// EquivFunc is a function that tells whether a token can stand for the expected
+// symbol of a rule even though their types differ. (e.g., a contextual keyword that
+// is an identifier in some rules)
+//
+// Parameters:
+//   - expected: The symbol expected by the rule.
+//   - got: The type of the token.
+//
+// Returns:
+//   - bool: True if the token is accepted, false otherwise.
+type EquivFunc[T gr.Enumer] func(expected, got T) bool
+
 // Rule represents a rule in the grammar.
 type Rule[T gr.Enumer] struct {
 	// lhs is the left hand side of the rule.
@@ -14,6 +26,12 @@ type Rule[T gr.Enumer] struct {
 
 	// rhss is the right hand side of the rule.
 	rhss []T
+
+	// foldable is true if the nodes of the rule can be folded by editors.
+	foldable bool
+
+	// equiv is the equivalence of the rule. Nil if types must be equal.
+	equiv EquivFunc[T]
 }
 
 // NewRule creates a new rule.
@@ -59,3 +77,77 @@ func (r Rule[T]) BackwardRhs() iter.Seq[T] {
 func (r Rule[T]) Lhs() T {
 	return r.lhs
 }
+
+// SetFoldable marks the rule as foldable; that is, editors can fold the nodes it
+// produces when they span several lines. (e.g., blocks, function bodies, etc.)
+//
+// Parameters:
+//   - foldable: True if the rule is foldable, false otherwise.
+func (r *Rule[T]) SetFoldable(foldable bool) {
+	if r == nil {
+		return
+	}
+
+	r.foldable = foldable
+}
+
+// Foldable checks whether the rule is foldable.
+//
+// Returns:
+//   - bool: True if the rule is foldable, false otherwise.
+func (r Rule[T]) Foldable() bool {
+	return r.foldable
+}
+
+// SetEquivalence sets the equivalence used when the rule is reduced; that is, the
+// function that decides whether a token whose type differs from the expected
+// symbol is accepted anyway. It avoids duplicating rules for contextual keywords.
+//
+// Parameters:
+//   - fn: The equivalence. Nil if types must be equal.
+func (r *Rule[T]) SetEquivalence(fn EquivFunc[T]) {
+	if r == nil {
+		return
+	}
+
+	r.equiv = fn
+}
+
+// accepts checks whether a token of the given type is accepted in place of the
+// expected symbol.
+//
+// Parameters:
+//   - expected: The symbol expected by the rule.
+//   - got: The type of the token.
+//
+// Returns:
+//   - bool: True if the token is accepted, false otherwise.
+func (r Rule[T]) accepts(expected, got T) bool {
+	if expected == got {
+		return true
+	}
+
+	return r.equiv != nil && r.equiv(expected, got)
+}
+
+// produced checks whether the given node was produced by the rule.
+//
+// Parameters:
+//   - node: The node. Assumed to be non-nil.
+//
+// Returns:
+//   - bool: True if the node has the left hand side of the rule as type and its right
+//     hand side as children, false otherwise.
+func (r Rule[T]) produced(node *gr.Token[T]) bool {
+	if node.Type != r.lhs || len(node.Children) != len(r.rhss) {
+		return false
+	}
+
+	for i, child := range node.Children {
+		if !r.accepts(r.rhss[i], child.Type) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,82 @@
+// Package plugins loads grammars that are distributed separately as Go plugins,
+// so that the CLI tools can work with them. It is kept apart from the grammar
+// package so that only the programs that load plugins link the plugin runtime.
+package plugins
+
+import (
+	"fmt"
+	"plugin"
+
+	"github.com/PlayerR9/grammar"
+)
+
+// Symbol is the name of the symbol that plugins export for Open. It must be a
+// variable of type grammar.GrammarProvider or a function that returns one.
+//
+// A plugin usually registers its grammar in a grammar.Registry from an init
+// function and exports the provider of Registry.Provider under this name.
+const Symbol string = "Provider"
+
+// Open opens a Go plugin and returns the grammar provider it exports under the
+// name Symbol.
+//
+// Parameters:
+//   - path: The path of the plugin.
+//
+// Returns:
+//   - grammar.GrammarProvider: The provider of the plugin.
+//   - error: An error if the plugin could not be opened or does not export a
+//     provider.
+//
+// Go plugins are only supported on some platforms; on the others, an error is
+// always returned.
+func Open(path string) (grammar.GrammarProvider, error) {
+	plug, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sym, err := plug.Lookup(Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := provider_of(sym)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %q: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// provider_of is a helper function that returns the provider of a symbol of a
+// plugin.
+//
+// Parameters:
+//   - sym: The symbol.
+//
+// Returns:
+//   - grammar.GrammarProvider: The provider. Never nil on success.
+//   - error: An error if the symbol is not a provider or gives a nil one.
+func provider_of(sym plugin.Symbol) (grammar.GrammarProvider, error) {
+	var p grammar.GrammarProvider
+
+	switch sym := sym.(type) {
+	case *grammar.GrammarProvider:
+		if sym != nil {
+			p = *sym
+		}
+	case func() grammar.GrammarProvider:
+		p = sym()
+	case grammar.GrammarProvider:
+		p = sym
+	default:
+		return nil, fmt.Errorf("symbol %q is of type %T, not a grammar provider", Symbol, sym)
+	}
+
+	if p == nil {
+		return nil, fmt.Errorf("symbol %q is a nil grammar provider", Symbol)
+	}
+
+	return p, nil
+}
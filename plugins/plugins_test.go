@@ -0,0 +1,59 @@
+package plugins
+
+import (
+	"testing"
+
+	"github.com/PlayerR9/grammar"
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// test_provider is a grammar provider that parses nothing.
+type test_provider struct{}
+
+func (test_provider) Name() string {
+	return "test"
+}
+
+func (test_provider) Parse(_ []byte) (gr.Result[*grammar.Node], error) {
+	return gr.Result[*grammar.Node]{}, nil
+}
+
+func TestProviderOf(t *testing.T) {
+	var p grammar.GrammarProvider = test_provider{}
+	var none grammar.GrammarProvider
+
+	valid := []any{
+		&p,
+		func() grammar.GrammarProvider { return p },
+		p,
+	}
+
+	for _, sym := range valid {
+		got, err := provider_of(sym)
+		if err != nil {
+			t.Fatalf("%T: expected no error, got %v", sym, err)
+		} else if got.Name() != "test" {
+			t.Errorf("%T: expected the provider %q, got %q", sym, "test", got.Name())
+		}
+	}
+
+	invalid := []any{
+		&none,
+		func() grammar.GrammarProvider { return nil },
+		42,
+	}
+
+	for _, sym := range invalid {
+		_, err := provider_of(sym)
+		if err == nil {
+			t.Errorf("%T: expected an error, got none", sym)
+		}
+	}
+}
+
+func TestOpenMissing(t *testing.T) {
+	_, err := Open("does-not-exist.so")
+	if err == nil {
+		t.Errorf("expected an error, got none")
+	}
+}
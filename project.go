@@ -0,0 +1,244 @@
+package grammar
+
+import (
+	"context"
+	"fmt"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// File is a file of a project.
+type File[T gr.Enumer] struct {
+	// Name is the name of the file. It is unique within the project.
+	Name string
+
+	// Data is the content of the file.
+	Data []byte
+
+	// Result is the result of the parse of the file. Only meaningful once the file
+	// is parsed.
+	Result gr.Result[*gr.Token[T]]
+
+	// Imports are the names of the files that the file imports. Only set by
+	// ParseImports.
+	Imports []string
+
+	// parsed is true if the file was parsed, false otherwise.
+	parsed bool
+
+	// resolved is true if the imports of the file were extracted, false otherwise.
+	resolved bool
+}
+
+// IsParsed checks whether the file was parsed.
+//
+// Returns:
+//   - bool: True if the file was parsed, false otherwise.
+func (f File[T]) IsParsed() bool {
+	return f.parsed
+}
+
+// Project is a set of files that are parsed with the same grammar. The token data
+// of every file is interned in a shared interner, and the problems of every file
+// are gathered in a combined list of diagnostics.
+type Project[T gr.Enumer] struct {
+	// grammars are the instances of the grammar of the files. Each of them is used
+	// by at most one worker at a time.
+	grammars []*CompiledGrammar[T]
+
+	// import_fn extracts the imports of the parsed files. Nil if imports are not
+	// extracted.
+	import_fn ImportFunc[T]
+
+	// load_fn loads the imported files that were not added. Nil if they are not
+	// loaded.
+	load_fn LoadFunc
+
+	// interner is the interner shared by the files.
+	interner *gr.Interner
+
+	// files are the files of the project, in the order they were added.
+	files []*File[T]
+
+	// index maps the names of the files to their index in files.
+	index map[string]int
+}
+
+// NewProject creates a new, empty, project.
+//
+// Parameters:
+//   - g: The grammar of the files.
+//   - copies: Other instances of the same grammar. ParseImports parses as many
+//     files in parallel as there are instances.
+//
+// Returns:
+//   - *Project[T]: The new project.
+//   - error: An error of type *errors.ErrInvalidParameter if g or one of the
+//     copies is nil.
+func NewProject[T gr.Enumer](g *CompiledGrammar[T], copies ...*CompiledGrammar[T]) (*Project[T], error) {
+	if g == nil {
+		return nil, gcers.NewErrNilParameter("g")
+	}
+
+	for _, c := range copies {
+		if c == nil {
+			return nil, gcers.NewErrNilParameter("copies")
+		}
+	}
+
+	return &Project[T]{
+		grammars: append([]*CompiledGrammar[T]{g}, copies...),
+		interner: gr.NewInterner(),
+		index:    make(map[string]int),
+	}, nil
+}
+
+// AddFile adds a file to the project. The file is parsed on the next call to Parse.
+//
+// Parameters:
+//   - name: The name of the file.
+//   - data: The content of the file.
+//
+// Returns:
+//   - error: An error of type *errors.ErrInvalidParameter if a file with the same
+//     name was already added.
+func (p *Project[T]) AddFile(name string, data []byte) error {
+	if p == nil {
+		return gcers.NilReceiver
+	}
+
+	if _, ok := p.index[name]; ok {
+		return gcers.NewErrInvalidParameter("name", fmt.Errorf("file %q was already added", name))
+	}
+
+	p.index[name] = len(p.files)
+
+	p.files = append(p.files, &File[T]{
+		Name: name,
+		Data: data,
+	})
+
+	return nil
+}
+
+// Parse parses every file that was not parsed yet.
+//
+// Returns:
+//   - bool: True if every file of the project parsed successfully, false otherwise.
+//
+// The problems of the files are reported by Diagnostics.
+func (p *Project[T]) Parse() bool {
+	if p == nil {
+		return false
+	}
+
+	ok := true
+
+	for _, file := range p.files {
+		if !file.parsed {
+			p.parse(p.grammars[0], file)
+		}
+
+		if !file.Result.IsOK() {
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// parse is a helper function that parses a file.
+//
+// Parameters:
+//   - g: The grammar to parse the file with.
+//   - file: The file to parse.
+func (p *Project[T]) parse(g *CompiledGrammar[T], file *File[T]) {
+	// dbg.AssertNotNil(g, "g")
+	// dbg.AssertNotNil(file, "file")
+
+	_, res, err := g.run(context.Background(), file.Data, p.interner, nil)
+	if err != nil {
+		res = gr.NewFailedResult[*gr.Token[T]](nil, err)
+	}
+
+	file.Result = res
+	file.parsed = true
+}
+
+// File returns the file with the given name.
+//
+// Parameters:
+//   - name: The name of the file.
+//
+// Returns:
+//   - *File[T]: The file. Nil if the project has no such file.
+//   - bool: True if the project has the file, false otherwise.
+func (p Project[T]) File(name string) (*File[T], bool) {
+	idx, ok := p.index[name]
+	if !ok {
+		return nil, false
+	}
+
+	return p.files[idx], true
+}
+
+// Files returns the files of the project, in the order they were added.
+//
+// Returns:
+//   - []*File[T]: The files.
+func (p Project[T]) Files() []*File[T] {
+	files := make([]*File[T], len(p.files))
+	copy(files, p.files)
+
+	return files
+}
+
+// Forests returns the forests of the parsed files.
+//
+// Returns:
+//   - map[string][]*gr.Token[T]: The forest of each parsed file, by file name.
+func (p Project[T]) Forests() map[string][]*gr.Token[T] {
+	forests := make(map[string][]*gr.Token[T], len(p.files))
+
+	for _, file := range p.files {
+		if file.parsed {
+			forests[file.Name] = file.Result.Forest
+		}
+	}
+
+	return forests
+}
+
+// Diagnostics returns the problems of every parsed file, in the order the files
+// were added.
+//
+// Returns:
+//   - []error: The problems. Each of them is of type *ErrInFile.
+func (p Project[T]) Diagnostics() []error {
+	var diagnostics []error
+
+	for _, file := range p.files {
+		if !file.parsed {
+			continue
+		}
+
+		if file.Result.Err != nil {
+			diagnostics = append(diagnostics, NewErrInFile(file.Name, file.Result.Err))
+		}
+
+		for _, diag := range file.Result.Diagnostics {
+			diagnostics = append(diagnostics, NewErrInFile(file.Name, diag))
+		}
+	}
+
+	return diagnostics
+}
+
+// Interner returns the interner shared by the files of the project.
+//
+// Returns:
+//   - *gr.Interner: The interner. Never returns nil.
+func (p Project[T]) Interner() *gr.Interner {
+	return p.interner
+}
@@ -0,0 +1,75 @@
+package grammar
+
+import (
+	"errors"
+	"testing"
+	"unsafe"
+)
+
+func TestProject(t *testing.T) {
+	p, err := NewProject(new_test_grammar(t))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, file := range [][2]string{{"a", "x y"}, {"b", "y z"}, {"bad", "x !"}} {
+		err := p.AddFile(file[0], []byte(file[1]))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	if err := p.AddFile("a", nil); err == nil {
+		t.Error("expected an error for a file added twice, got nil")
+	}
+
+	if ok := p.Parse(); ok {
+		t.Error("expected the project to fail because of the bad file")
+	}
+
+	forests := p.Forests()
+	if len(forests) != 3 {
+		t.Errorf("expected 3 forests, got %d", len(forests))
+	}
+
+	diags := p.Diagnostics()
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %v", diags)
+	}
+
+	var in_file *ErrInFile
+
+	if !errors.As(diags[0], &in_file) || in_file.File != "bad" {
+		t.Errorf("expected an error in the file bad, got %v", diags[0])
+	}
+
+	// The "y" of both files is the same interned string.
+	a, _ := p.File("a")
+	b, _ := p.File("b")
+
+	ya := words_of(a.Result)[1]
+	yb := words_of(b.Result)[0]
+
+	if ya != "y" || yb != "y" {
+		t.Fatalf("expected the word y in both files, got %q and %q", ya, yb)
+	} else if unsafe.StringData(ya) != unsafe.StringData(yb) {
+		t.Error("expected the files to share the interned word y")
+	}
+
+	// Only the new files are parsed again.
+	err = p.AddFile("c", []byte("z"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	p.Parse()
+
+	c, _ := p.File("c")
+	if !c.IsParsed() || !c.Result.IsOK() {
+		t.Errorf("expected the file c to be parsed, got %v", c.Result.Err)
+	}
+
+	if got, _ := p.File("a"); got.Result.Forest[0] != forests["a"][0] {
+		t.Error("expected the file a not to be parsed again")
+	}
+}
@@ -0,0 +1,181 @@
+package grammar
+
+import (
+	"errors"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/grammar"
+)
+
+// Node is a node of a parse tree whose token type is not known by its user. Its
+// type is the name of the token type.
+type Node struct {
+	// Type is the name of the type of the token.
+	Type string
+
+	// Data is the data of the token.
+	Data string
+
+	// Span is the span of the token in the input stream.
+	Span gr.Span
+
+	// Children are the children of the token.
+	Children []*Node
+}
+
+// GrammarProvider provides a grammar to the tools that do not know its token type,
+// such as the CLI tools working with grammars that are distributed separately (see
+// the plugins package).
+type GrammarProvider interface {
+	// Name returns the name of the grammar.
+	//
+	// Returns:
+	//   - string: The name of the grammar. Never empty.
+	Name() string
+
+	// Parse lexes and parses the given data.
+	//
+	// Parameters:
+	//   - data: The input stream.
+	//
+	// Returns:
+	//   - gr.Result[*Node]: The result of the parse.
+	//   - error: An error if the data could not be lexed.
+	Parse(data []byte) (gr.Result[*Node], error)
+}
+
+// provider is the GrammarProvider of a compiled grammar.
+type provider[T gr.Enumer] struct {
+	// name is the name of the grammar.
+	name string
+
+	// g is the compiled grammar. Nil if the grammar is the one of r.
+	g *CompiledGrammar[T]
+
+	// r is the registry that holds the grammar under name. Nil if g is set.
+	r *Registry[T]
+}
+
+// Name implements the GrammarProvider interface.
+func (p provider[T]) Name() string {
+	return p.name
+}
+
+// Parse implements the GrammarProvider interface.
+func (p provider[T]) Parse(data []byte) (gr.Result[*Node], error) {
+	var res gr.Result[*gr.Token[T]]
+	var err error
+
+	if p.r != nil {
+		res, err = p.r.Run(p.name, data)
+	} else {
+		res, err = Run(data, p.g)
+	}
+
+	forest := make([]*Node, 0, len(res.Forest))
+
+	for _, root := range res.Forest {
+		forest = append(forest, node_of(root))
+	}
+
+	return gr.Result[*Node]{
+		Forest:      forest,
+		Diagnostics: res.Diagnostics,
+		Err:         res.Err,
+		ErrPos:      res.ErrPos,
+	}, err
+}
+
+// node_of is a helper function that converts a parse tree to nodes.
+//
+// Parameters:
+//   - root: The root of the parse tree.
+//
+// Returns:
+//   - *Node: The root node. Nil if root is nil.
+func node_of[T gr.Enumer](root *gr.Token[T]) *Node {
+	if root == nil {
+		return nil
+	}
+
+	type pair struct {
+		tk   *gr.Token[T]
+		node *Node
+	}
+
+	top := &Node{}
+
+	stack := []pair{{tk: root, node: top}}
+
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		p.node.Type = p.tk.Type.String()
+		p.node.Data = p.tk.Data
+		p.node.Span = p.tk.Span()
+
+		for _, child := range p.tk.Children {
+			if child == nil {
+				continue
+			}
+
+			node := &Node{}
+
+			p.node.Children = append(p.node.Children, node)
+			stack = append(stack, pair{tk: child, node: node})
+		}
+	}
+
+	return top
+}
+
+// NewProvider creates the GrammarProvider of a compiled grammar.
+//
+// Parameters:
+//   - name: The name of the grammar.
+//   - g: The compiled grammar.
+//
+// Returns:
+//   - GrammarProvider: The provider.
+//   - error: An error of type *errors.ErrInvalidParameter if name is empty or g
+//     is nil.
+func NewProvider[T gr.Enumer](name string, g *CompiledGrammar[T]) (GrammarProvider, error) {
+	if name == "" {
+		return nil, gcers.NewErrInvalidParameter("name", errors.New("must not be empty"))
+	} else if g == nil {
+		return nil, gcers.NewErrNilParameter("g")
+	}
+
+	return provider[T]{
+		name: name,
+		g:    g,
+	}, nil
+}
+
+// Provider returns the GrammarProvider of the grammar registered under the given
+// name. The provider parses with the version of the grammar that is current at
+// each call, so it follows the reloads.
+//
+// Parameters:
+//   - name: The name of the grammar.
+//
+// Returns:
+//   - GrammarProvider: The provider.
+//   - error: An error of type *ErrUnknownGrammar if no grammar is registered under
+//     the name.
+func (r *Registry[T]) Provider(name string) (GrammarProvider, error) {
+	if r == nil {
+		return nil, gcers.NilReceiver
+	}
+
+	_, ok := r.Get(name)
+	if !ok {
+		return nil, NewErrUnknownGrammar(name)
+	}
+
+	return provider[T]{
+		name: name,
+		r:    r,
+	}, nil
+}
@@ -0,0 +1,98 @@
+package grammar
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// sexpr_of_node is a helper function that writes a node tree as "(Type child...)",
+// with the data of the leaves.
+func sexpr_of_node(node *Node) string {
+	if len(node.Children) == 0 {
+		if node.Data == "" {
+			return node.Type
+		}
+
+		return node.Data
+	}
+
+	elems := []string{node.Type}
+
+	for _, child := range node.Children {
+		elems = append(elems, sexpr_of_node(child))
+	}
+
+	return "(" + strings.Join(elems, " ") + ")"
+}
+
+func TestNewProvider(t *testing.T) {
+	p, err := NewProvider("words", new_test_grammar(t))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	res, err := p.Parse([]byte("a b"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	} else if res.Err != nil {
+		t.Fatalf("expected no parse error, got %v", res.Err)
+	}
+
+	root, ok := res.Root()
+	if !ok {
+		t.Fatalf("expected a root, got %d trees", len(res.Forest))
+	}
+
+	const want = "(Source (List a (List b)) EOF)"
+
+	if got := sexpr_of_node(root); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	if got := root.Children[0].Span; got.Start != 0 || got.End != 3 {
+		t.Errorf("expected the list to span [0, 3), got %v", got)
+	}
+}
+
+func TestRegistryProvider(t *testing.T) {
+	r := NewRegistry[test_type]()
+
+	_, err := r.Provider("words")
+
+	var unknown *ErrUnknownGrammar
+
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected an *ErrUnknownGrammar, got %v", err)
+	}
+
+	err = r.Register("words", new_test_spec(t))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	p, err := r.Provider("words")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err = p.Parse([]byte("a ,b"))
+	if err == nil {
+		t.Fatalf("expected a lexing error before the reload, got none")
+	}
+
+	spec := new_test_spec(t)
+	_ = spec.Lexer.RegisterSkip(",")
+
+	err = r.Reload("words", spec)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	res, err := p.Parse([]byte("a ,b"))
+	if err != nil {
+		t.Fatalf("expected no error after the reload, got %v", err)
+	} else if res.Err != nil {
+		t.Fatalf("expected no parse error after the reload, got %v", res.Err)
+	}
+}
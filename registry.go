@@ -0,0 +1,178 @@
+package grammar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	gr "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/grammar/lexer"
+	"github.com/PlayerR9/grammar/parser"
+)
+
+// Spec is the specification of a grammar; that is, what a grammar is compiled from.
+type Spec[T gr.Enumer] struct {
+	// Lexer is the builder of the lexer.
+	Lexer lexer.Builder[T]
+
+	// Parser is the builder of the parser.
+	Parser parser.Builder[T]
+}
+
+// Registry is a set of named grammars that can be replaced while they are in use.
+// It is safe for concurrent use.
+//
+// Parse sessions get the grammar that is current when they start; a reload only
+// affects the sessions that start after it, while the in-flight ones finish on
+// the grammar they started with.
+type Registry[T gr.Enumer] struct {
+	// mu protects grammars.
+	mu sync.RWMutex
+
+	// grammars maps the names of the grammars to their current compiled version.
+	grammars map[string]*CompiledGrammar[T]
+}
+
+// NewRegistry creates a new, empty, registry.
+//
+// Returns:
+//   - *Registry[T]: The new registry. Never returns nil.
+func NewRegistry[T gr.Enumer]() *Registry[T] {
+	return &Registry[T]{
+		grammars: make(map[string]*CompiledGrammar[T]),
+	}
+}
+
+// Register compiles the given specification and registers it under the given name.
+//
+// Parameters:
+//   - name: The name of the grammar.
+//   - spec: The specification of the grammar.
+//
+// Returns:
+//   - error: An error of type *errors.ErrInvalidParameter if the name is empty or
+//     already registered.
+func (r *Registry[T]) Register(name string, spec Spec[T]) error {
+	if r == nil {
+		return gcers.NilReceiver
+	} else if name == "" {
+		return gcers.NewErrInvalidParameter("name", errors.New("must not be empty"))
+	}
+
+	g := Compile(spec.Lexer, spec.Parser)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.grammars[name]; ok {
+		return gcers.NewErrInvalidParameter("name", fmt.Errorf("grammar %q is already registered", name))
+	}
+
+	if r.grammars == nil {
+		r.grammars = make(map[string]*CompiledGrammar[T])
+	}
+
+	r.grammars[name] = g
+
+	return nil
+}
+
+// Reload recompiles the grammar registered under the given name from the given
+// specification and swaps it in for the sessions that start afterwards. The new
+// grammar keeps the settings of the previous one: its configuration, its metrics
+// and the capacity of its memoization.
+//
+// Parameters:
+//   - name: The name of the grammar.
+//   - spec: The new specification of the grammar.
+//
+// Returns:
+//   - error: An error of type *ErrUnknownGrammar if no grammar is registered under
+//     the name.
+//
+// The grammar is compiled before the swap, so a session never sees a partially
+// built grammar.
+func (r *Registry[T]) Reload(name string, spec Spec[T]) error {
+	if r == nil {
+		return gcers.NilReceiver
+	}
+
+	g := Compile(spec.Lexer, spec.Parser)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, ok := r.grammars[name]
+	if !ok {
+		return NewErrUnknownGrammar(name)
+	}
+
+	g.inherit(old)
+
+	r.grammars[name] = g
+
+	return nil
+}
+
+// Get returns the current version of the grammar registered under the given name.
+//
+// Parameters:
+//   - name: The name of the grammar.
+//
+// Returns:
+//   - *CompiledGrammar[T]: The grammar. Nil if no grammar is registered under the name.
+//   - bool: True if the grammar is registered, false otherwise.
+func (r *Registry[T]) Get(name string) (*CompiledGrammar[T], bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	g, ok := r.grammars[name]
+	return g, ok
+}
+
+// Run lexes and parses the given data with the current version of the grammar
+// registered under the given name.
+//
+// Parameters:
+//   - name: The name of the grammar.
+//   - data: The input stream.
+//
+// Returns:
+//   - gr.Result[*gr.Token[T]]: The result of the parse.
+//   - error: An error of type *ErrUnknownGrammar if no grammar is registered under
+//     the name, or an error if the data could not be lexed.
+func (r *Registry[T]) Run(name string, data []byte) (gr.Result[*gr.Token[T]], error) {
+	g, ok := r.Get(name)
+	if !ok {
+		return gr.Result[*gr.Token[T]]{}, NewErrUnknownGrammar(name)
+	}
+
+	return Run(data, g)
+}
+
+// RunCtx is like Run but stops as soon as the context is done; see the RunCtx
+// function.
+//
+// Parameters:
+//   - ctx: The context.
+//   - name: The name of the grammar.
+//   - data: The input stream.
+//
+// Returns:
+//   - gr.Result[*gr.Token[T]]: The result of the parse.
+//   - error: An error as for Run, or an error of type *gr.ErrCancelled if the
+//     context is done during the lexing.
+func (r *Registry[T]) RunCtx(ctx context.Context, name string, data []byte) (gr.Result[*gr.Token[T]], error) {
+	g, ok := r.Get(name)
+	if !ok {
+		return gr.Result[*gr.Token[T]]{}, NewErrUnknownGrammar(name)
+	}
+
+	return RunCtx(ctx, data, g)
+}
@@ -0,0 +1,123 @@
+package grammar
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	gr "github.com/PlayerR9/grammar/grammar"
+	"github.com/PlayerR9/grammar/lexer"
+)
+
+func TestRegistryReload(t *testing.T) {
+	r := NewRegistry[test_type]()
+
+	err := r.Register("words", new_test_spec(t))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	old, _ := r.Get("words")
+
+	err = old.Configure(Config{Lexer: LexerConfig{Replacement: "x"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	old.EnableMemo(4)
+
+	err = r.Reload("words", new_test_spec(t))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	g, _ := r.Get("words")
+	if g == old {
+		t.Fatalf("expected a new grammar after the reload")
+	}
+
+	// The grammar obtained before the reload keeps working.
+	res, err := Run([]byte("a b"), old)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	} else if got := words_of(res); fmt.Sprint(got) != "[a b]" {
+		t.Errorf("expected the words [a b], got %v", got)
+	}
+
+	// The new grammar keeps the settings of the previous one.
+	res, err = r.Run("words", []byte("a\xffb"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	} else if got := words_of(res); fmt.Sprint(got) != "[axb]" {
+		t.Errorf("expected the words [axb], got %v", got)
+	}
+
+	if g.memo.capacity != 4 {
+		t.Errorf("expected a memoization of %d results, got %d", 4, g.memo.capacity)
+	}
+}
+
+func TestRegistryRunConcurrent(t *testing.T) {
+	const n = 4
+
+	var arrived sync.WaitGroup
+
+	arrived.Add(n)
+
+	spec := new_test_spec(t)
+
+	// Every run waits for the others in its lexer, so the runs cannot be
+	// serialized.
+	spec.Lexer.RegisterDefault(func(l *lexer.Lexer[test_type]) (*gr.Token[test_type], error) {
+		arrived.Done()
+		arrived.Wait()
+
+		c, _ := l.NextRune()
+
+		return gr.NewTerminalToken(tt_word, string(c)), nil
+	})
+
+	r := NewRegistry[test_type]()
+
+	err := r.Register("words", spec)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	words := make([]string, n)
+
+	var wg sync.WaitGroup
+
+	for i := range n {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			res, err := r.Run("words", []byte{byte('a' + i)})
+			if err == nil {
+				words[i] = fmt.Sprint(words_of(res))
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the runs to proceed concurrently")
+	}
+
+	for i, got := range words {
+		if want := fmt.Sprintf("[%c]", 'a'+i); got != want {
+			t.Errorf("expected the words %s for run %d, got %s", want, i, got)
+		}
+	}
+}
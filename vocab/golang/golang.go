@@ -0,0 +1,107 @@
+// Package golang is the vocabulary of the Go programming language.
+package golang
+
+import (
+	"slices"
+
+	"github.com/PlayerR9/grammar/vocab"
+)
+
+// words are the words of the vocabulary.
+var words = []vocab.Word{
+	// Keywords.
+	{Text: "break", Name: "Break", Kind: vocab.Keyword},
+	{Text: "case", Name: "Case", Kind: vocab.Keyword},
+	{Text: "chan", Name: "Chan", Kind: vocab.Keyword},
+	{Text: "const", Name: "Const", Kind: vocab.Keyword},
+	{Text: "continue", Name: "Continue", Kind: vocab.Keyword},
+	{Text: "default", Name: "Default", Kind: vocab.Keyword},
+	{Text: "defer", Name: "Defer", Kind: vocab.Keyword},
+	{Text: "else", Name: "Else", Kind: vocab.Keyword},
+	{Text: "fallthrough", Name: "Fallthrough", Kind: vocab.Keyword},
+	{Text: "for", Name: "For", Kind: vocab.Keyword},
+	{Text: "func", Name: "Func", Kind: vocab.Keyword},
+	{Text: "go", Name: "Go", Kind: vocab.Keyword},
+	{Text: "goto", Name: "Goto", Kind: vocab.Keyword},
+	{Text: "if", Name: "If", Kind: vocab.Keyword},
+	{Text: "import", Name: "Import", Kind: vocab.Keyword},
+	{Text: "interface", Name: "Interface", Kind: vocab.Keyword},
+	{Text: "map", Name: "Map", Kind: vocab.Keyword},
+	{Text: "package", Name: "Package", Kind: vocab.Keyword},
+	{Text: "range", Name: "Range", Kind: vocab.Keyword},
+	{Text: "return", Name: "Return", Kind: vocab.Keyword},
+	{Text: "select", Name: "Select", Kind: vocab.Keyword},
+	{Text: "struct", Name: "Struct", Kind: vocab.Keyword},
+	{Text: "switch", Name: "Switch", Kind: vocab.Keyword},
+	{Text: "type", Name: "Type", Kind: vocab.Keyword},
+	{Text: "var", Name: "Var", Kind: vocab.Keyword},
+
+	// Operators.
+	{Text: "+", Name: "Add", Kind: vocab.Operator},
+	{Text: "-", Name: "Sub", Kind: vocab.Operator},
+	{Text: "*", Name: "Mul", Kind: vocab.Operator},
+	{Text: "/", Name: "Quo", Kind: vocab.Operator},
+	{Text: "%", Name: "Rem", Kind: vocab.Operator},
+	{Text: "&", Name: "And", Kind: vocab.Operator},
+	{Text: "|", Name: "Or", Kind: vocab.Operator},
+	{Text: "^", Name: "Xor", Kind: vocab.Operator},
+	{Text: "<<", Name: "Shl", Kind: vocab.Operator},
+	{Text: ">>", Name: "Shr", Kind: vocab.Operator},
+	{Text: "&^", Name: "AndNot", Kind: vocab.Operator},
+	{Text: "+=", Name: "AddAssign", Kind: vocab.Operator},
+	{Text: "-=", Name: "SubAssign", Kind: vocab.Operator},
+	{Text: "*=", Name: "MulAssign", Kind: vocab.Operator},
+	{Text: "/=", Name: "QuoAssign", Kind: vocab.Operator},
+	{Text: "%=", Name: "RemAssign", Kind: vocab.Operator},
+	{Text: "&=", Name: "AndAssign", Kind: vocab.Operator},
+	{Text: "|=", Name: "OrAssign", Kind: vocab.Operator},
+	{Text: "^=", Name: "XorAssign", Kind: vocab.Operator},
+	{Text: "<<=", Name: "ShlAssign", Kind: vocab.Operator},
+	{Text: ">>=", Name: "ShrAssign", Kind: vocab.Operator},
+	{Text: "&^=", Name: "AndNotAssign", Kind: vocab.Operator},
+	{Text: "&&", Name: "LogicalAnd", Kind: vocab.Operator},
+	{Text: "||", Name: "LogicalOr", Kind: vocab.Operator},
+	{Text: "<-", Name: "Arrow", Kind: vocab.Operator},
+	{Text: "++", Name: "Inc", Kind: vocab.Operator},
+	{Text: "--", Name: "Dec", Kind: vocab.Operator},
+	{Text: "==", Name: "Equal", Kind: vocab.Operator},
+	{Text: "<", Name: "Less", Kind: vocab.Operator},
+	{Text: ">", Name: "Greater", Kind: vocab.Operator},
+	{Text: "=", Name: "Assign", Kind: vocab.Operator},
+	{Text: "!", Name: "Not", Kind: vocab.Operator},
+	{Text: "~", Name: "Tilde", Kind: vocab.Operator},
+	{Text: "!=", Name: "NotEqual", Kind: vocab.Operator},
+	{Text: "<=", Name: "LessEqual", Kind: vocab.Operator},
+	{Text: ">=", Name: "GreaterEqual", Kind: vocab.Operator},
+	{Text: ":=", Name: "Define", Kind: vocab.Operator},
+
+	// Punctuation.
+	{Text: "(", Name: "LeftParen", Kind: vocab.Punctuation},
+	{Text: ")", Name: "RightParen", Kind: vocab.Punctuation},
+	{Text: "[", Name: "LeftBracket", Kind: vocab.Punctuation},
+	{Text: "]", Name: "RightBracket", Kind: vocab.Punctuation},
+	{Text: "{", Name: "LeftBrace", Kind: vocab.Punctuation},
+	{Text: "}", Name: "RightBrace", Kind: vocab.Punctuation},
+	{Text: ",", Name: "Comma", Kind: vocab.Punctuation},
+	{Text: ";", Name: "Semicolon", Kind: vocab.Punctuation},
+	{Text: ".", Name: "Period", Kind: vocab.Punctuation},
+	{Text: ":", Name: "Colon", Kind: vocab.Punctuation},
+	{Text: "...", Name: "Ellipsis", Kind: vocab.Punctuation},
+
+	// Predeclared literals.
+	{Text: "true", Name: "True", Kind: vocab.Literal},
+	{Text: "false", Name: "False", Kind: vocab.Literal},
+	{Text: "nil", Name: "Nil", Kind: vocab.Literal},
+	{Text: "iota", Name: "Iota", Kind: vocab.Literal},
+}
+
+// Vocabulary returns the vocabulary of the Go programming language.
+//
+// Returns:
+//   - vocab.Vocabulary: The vocabulary. Its words can be modified freely.
+func Vocabulary() vocab.Vocabulary {
+	return vocab.Vocabulary{
+		Name:  "Go",
+		Words: slices.Clone(words),
+	}
+}
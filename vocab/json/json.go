@@ -0,0 +1,35 @@
+// Package json is the vocabulary of JSON.
+package json
+
+import (
+	"slices"
+
+	"github.com/PlayerR9/grammar/vocab"
+)
+
+// words are the words of the vocabulary.
+var words = []vocab.Word{
+	// Punctuation.
+	{Text: "{", Name: "LeftBrace", Kind: vocab.Punctuation},
+	{Text: "}", Name: "RightBrace", Kind: vocab.Punctuation},
+	{Text: "[", Name: "LeftBracket", Kind: vocab.Punctuation},
+	{Text: "]", Name: "RightBracket", Kind: vocab.Punctuation},
+	{Text: ":", Name: "Colon", Kind: vocab.Punctuation},
+	{Text: ",", Name: "Comma", Kind: vocab.Punctuation},
+
+	// Literals.
+	{Text: "true", Name: "True", Kind: vocab.Literal},
+	{Text: "false", Name: "False", Kind: vocab.Literal},
+	{Text: "null", Name: "Null", Kind: vocab.Literal},
+}
+
+// Vocabulary returns the vocabulary of JSON.
+//
+// Returns:
+//   - vocab.Vocabulary: The vocabulary. Its words can be modified freely.
+func Vocabulary() vocab.Vocabulary {
+	return vocab.Vocabulary{
+		Name:  "JSON",
+		Words: slices.Clone(words),
+	}
+}
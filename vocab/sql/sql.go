@@ -0,0 +1,121 @@
+// Package sql is the vocabulary of SQL (the keywords common to its main dialects, in upper case).
+package sql
+
+import (
+	"slices"
+
+	"github.com/PlayerR9/grammar/vocab"
+)
+
+// words are the words of the vocabulary.
+var words = []vocab.Word{
+	// Keywords.
+	{Text: "SELECT", Name: "Select", Kind: vocab.Keyword},
+	{Text: "FROM", Name: "From", Kind: vocab.Keyword},
+	{Text: "WHERE", Name: "Where", Kind: vocab.Keyword},
+	{Text: "AND", Name: "And", Kind: vocab.Keyword},
+	{Text: "OR", Name: "Or", Kind: vocab.Keyword},
+	{Text: "NOT", Name: "Not", Kind: vocab.Keyword},
+	{Text: "INSERT", Name: "Insert", Kind: vocab.Keyword},
+	{Text: "INTO", Name: "Into", Kind: vocab.Keyword},
+	{Text: "VALUES", Name: "Values", Kind: vocab.Keyword},
+	{Text: "UPDATE", Name: "Update", Kind: vocab.Keyword},
+	{Text: "SET", Name: "Set", Kind: vocab.Keyword},
+	{Text: "DELETE", Name: "Delete", Kind: vocab.Keyword},
+	{Text: "CREATE", Name: "Create", Kind: vocab.Keyword},
+	{Text: "TABLE", Name: "Table", Kind: vocab.Keyword},
+	{Text: "DROP", Name: "Drop", Kind: vocab.Keyword},
+	{Text: "ALTER", Name: "Alter", Kind: vocab.Keyword},
+	{Text: "ADD", Name: "Add", Kind: vocab.Keyword},
+	{Text: "COLUMN", Name: "Column", Kind: vocab.Keyword},
+	{Text: "INDEX", Name: "Index", Kind: vocab.Keyword},
+	{Text: "VIEW", Name: "View", Kind: vocab.Keyword},
+	{Text: "PRIMARY", Name: "Primary", Kind: vocab.Keyword},
+	{Text: "KEY", Name: "Key", Kind: vocab.Keyword},
+	{Text: "FOREIGN", Name: "Foreign", Kind: vocab.Keyword},
+	{Text: "REFERENCES", Name: "References", Kind: vocab.Keyword},
+	{Text: "UNIQUE", Name: "Unique", Kind: vocab.Keyword},
+	{Text: "DEFAULT", Name: "Default", Kind: vocab.Keyword},
+	{Text: "CHECK", Name: "Check", Kind: vocab.Keyword},
+	{Text: "CONSTRAINT", Name: "Constraint", Kind: vocab.Keyword},
+	{Text: "JOIN", Name: "Join", Kind: vocab.Keyword},
+	{Text: "INNER", Name: "Inner", Kind: vocab.Keyword},
+	{Text: "LEFT", Name: "Left", Kind: vocab.Keyword},
+	{Text: "RIGHT", Name: "Right", Kind: vocab.Keyword},
+	{Text: "FULL", Name: "Full", Kind: vocab.Keyword},
+	{Text: "OUTER", Name: "Outer", Kind: vocab.Keyword},
+	{Text: "CROSS", Name: "Cross", Kind: vocab.Keyword},
+	{Text: "ON", Name: "On", Kind: vocab.Keyword},
+	{Text: "USING", Name: "Using", Kind: vocab.Keyword},
+	{Text: "AS", Name: "As", Kind: vocab.Keyword},
+	{Text: "DISTINCT", Name: "Distinct", Kind: vocab.Keyword},
+	{Text: "ALL", Name: "All", Kind: vocab.Keyword},
+	{Text: "GROUP", Name: "Group", Kind: vocab.Keyword},
+	{Text: "BY", Name: "By", Kind: vocab.Keyword},
+	{Text: "HAVING", Name: "Having", Kind: vocab.Keyword},
+	{Text: "ORDER", Name: "Order", Kind: vocab.Keyword},
+	{Text: "ASC", Name: "Asc", Kind: vocab.Keyword},
+	{Text: "DESC", Name: "Desc", Kind: vocab.Keyword},
+	{Text: "LIMIT", Name: "Limit", Kind: vocab.Keyword},
+	{Text: "OFFSET", Name: "Offset", Kind: vocab.Keyword},
+	{Text: "UNION", Name: "Union", Kind: vocab.Keyword},
+	{Text: "INTERSECT", Name: "Intersect", Kind: vocab.Keyword},
+	{Text: "EXCEPT", Name: "Except", Kind: vocab.Keyword},
+	{Text: "IN", Name: "In", Kind: vocab.Keyword},
+	{Text: "BETWEEN", Name: "Between", Kind: vocab.Keyword},
+	{Text: "LIKE", Name: "Like", Kind: vocab.Keyword},
+	{Text: "IS", Name: "Is", Kind: vocab.Keyword},
+	{Text: "EXISTS", Name: "Exists", Kind: vocab.Keyword},
+	{Text: "CASE", Name: "Case", Kind: vocab.Keyword},
+	{Text: "WHEN", Name: "When", Kind: vocab.Keyword},
+	{Text: "THEN", Name: "Then", Kind: vocab.Keyword},
+	{Text: "ELSE", Name: "Else", Kind: vocab.Keyword},
+	{Text: "END", Name: "End", Kind: vocab.Keyword},
+	{Text: "CAST", Name: "Cast", Kind: vocab.Keyword},
+	{Text: "WITH", Name: "With", Kind: vocab.Keyword},
+	{Text: "RECURSIVE", Name: "Recursive", Kind: vocab.Keyword},
+	{Text: "BEGIN", Name: "Begin", Kind: vocab.Keyword},
+	{Text: "COMMIT", Name: "Commit", Kind: vocab.Keyword},
+	{Text: "ROLLBACK", Name: "Rollback", Kind: vocab.Keyword},
+	{Text: "TRANSACTION", Name: "Transaction", Kind: vocab.Keyword},
+
+	// Operators.
+	{Text: "+", Name: "Plus", Kind: vocab.Operator},
+	{Text: "-", Name: "Sub", Kind: vocab.Operator},
+	{Text: "*", Name: "Mul", Kind: vocab.Operator},
+	{Text: "/", Name: "Quo", Kind: vocab.Operator},
+	{Text: "%", Name: "Rem", Kind: vocab.Operator},
+	{Text: "||", Name: "Concat", Kind: vocab.Operator},
+	{Text: "=", Name: "Equal", Kind: vocab.Operator},
+	{Text: "<>", Name: "NotEqual", Kind: vocab.Operator},
+	{Text: "!=", Name: "BangEqual", Kind: vocab.Operator},
+	{Text: "<", Name: "Less", Kind: vocab.Operator},
+	{Text: ">", Name: "Greater", Kind: vocab.Operator},
+	{Text: "<=", Name: "LessEqual", Kind: vocab.Operator},
+	{Text: ">=", Name: "GreaterEqual", Kind: vocab.Operator},
+
+	// Punctuation.
+	{Text: "(", Name: "LeftParen", Kind: vocab.Punctuation},
+	{Text: ")", Name: "RightParen", Kind: vocab.Punctuation},
+	{Text: ",", Name: "Comma", Kind: vocab.Punctuation},
+	{Text: ";", Name: "Semicolon", Kind: vocab.Punctuation},
+	{Text: ".", Name: "Period", Kind: vocab.Punctuation},
+
+	// Literals.
+	{Text: "NULL", Name: "Null", Kind: vocab.Literal},
+	{Text: "TRUE", Name: "True", Kind: vocab.Literal},
+	{Text: "FALSE", Name: "False", Kind: vocab.Literal},
+}
+
+// Vocabulary returns the vocabulary of SQL (the keywords common to its main dialects, in upper case).
+// Its words are matched regardless of their case.
+//
+// Returns:
+//   - vocab.Vocabulary: The vocabulary. Its words can be modified freely.
+func Vocabulary() vocab.Vocabulary {
+	return vocab.Vocabulary{
+		Name:            "SQL",
+		Words:           slices.Clone(words),
+		CaseInsensitive: true,
+	}
+}
@@ -0,0 +1,142 @@
+// Package vocab holds the types of the vocabularies that are published as data
+// packages (such as vocab/golang, vocab/sql and vocab/json), so that lexers can
+// include a standard set of words instead of registering them one by one.
+package vocab
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// Kind is the kind of a word of a vocabulary.
+type Kind int
+
+const (
+	// Keyword is a reserved word, such as "func".
+	Keyword Kind = iota
+
+	// Operator is an operator, such as "+=".
+	Operator
+
+	// Punctuation is a delimiter or a separator, such as "{" or ",".
+	Punctuation
+
+	// Literal is a predeclared literal, such as "true".
+	Literal
+)
+
+// String implements the fmt.Stringer interface.
+func (k Kind) String() string {
+	switch k {
+	case Keyword:
+		return "keyword"
+	case Operator:
+		return "operator"
+	case Punctuation:
+		return "punctuation"
+	case Literal:
+		return "literal"
+	default:
+		return "Kind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// Word is a word of a vocabulary.
+type Word struct {
+	// Text is the text of the word, as it appears in the input.
+	Text string
+
+	// Name is the conventional name of the word, in PascalCase (e.g., "AddAssign"
+	// for "+="). It lets the word be mapped to a token type by name.
+	Name string
+
+	// Kind is the kind of the word.
+	Kind Kind
+}
+
+// Vocabulary is a set of words of a language.
+type Vocabulary struct {
+	// Name is the name of the language.
+	Name string
+
+	// Words are the words of the vocabulary. No two words have the same text or
+	// the same name.
+	Words []Word
+
+	// CaseInsensitive is true if the words are matched regardless of their case,
+	// such as the keywords of SQL.
+	CaseInsensitive bool
+}
+
+// OfKind returns the words of the given kinds.
+//
+// Parameters:
+//   - kinds: The kinds of the words.
+//
+// Returns:
+//   - []Word: The words, in the order of the vocabulary.
+func (v Vocabulary) OfKind(kinds ...Kind) []Word {
+	var words []Word
+
+	for _, word := range v.Words {
+		if slices.Contains(kinds, word.Kind) {
+			words = append(words, word)
+		}
+	}
+
+	return words
+}
+
+// Lookup returns the word with the given text.
+//
+// Parameters:
+//   - text: The text of the word.
+//
+// Returns:
+//   - Word: The word.
+//   - bool: True if the vocabulary has the word, false otherwise.
+func (v Vocabulary) Lookup(text string) (Word, bool) {
+	idx := slices.IndexFunc(v.Words, func(word Word) bool {
+		return word.Text == text
+	})
+
+	if idx == -1 {
+		return Word{}, false
+	}
+
+	return v.Words[idx], true
+}
+
+// SymbolFunc gives the symbol (token type) of a word.
+//
+// Parameters:
+//   - word: The word.
+//
+// Returns:
+//   - S: The symbol of the word.
+//   - bool: False if the word must not be included, true otherwise.
+type SymbolFunc[S any] func(word Word) (S, bool)
+
+// ByName returns a SymbolFunc that gives to each word the symbol whose name is the
+// name of the word with the given prefix; such as "TtAddAssign" for "+=" with the
+// prefix "Tt". Words without such a symbol are not included.
+//
+// Parameters:
+//   - prefix: The prefix of the names of the symbols.
+//   - symbols: The symbols.
+//
+// Returns:
+//   - SymbolFunc[S]: The function. Never returns nil.
+func ByName[S fmt.Stringer](prefix string, symbols ...S) SymbolFunc[S] {
+	table := make(map[string]S, len(symbols))
+
+	for _, symbol := range symbols {
+		table[symbol.String()] = symbol
+	}
+
+	return func(word Word) (S, bool) {
+		symbol, ok := table[prefix+word.Name]
+		return symbol, ok
+	}
+}
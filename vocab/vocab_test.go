@@ -0,0 +1,36 @@
+package vocab_test
+
+import (
+	"testing"
+
+	"github.com/PlayerR9/grammar/vocab"
+	"github.com/PlayerR9/grammar/vocab/golang"
+	"github.com/PlayerR9/grammar/vocab/json"
+	"github.com/PlayerR9/grammar/vocab/sql"
+)
+
+func TestVocabularyUnique(t *testing.T) {
+	vocabularies := []vocab.Vocabulary{
+		golang.Vocabulary(),
+		json.Vocabulary(),
+		sql.Vocabulary(),
+	}
+
+	for _, v := range vocabularies {
+		texts := make(map[string]bool, len(v.Words))
+		names := make(map[string]bool, len(v.Words))
+
+		for _, word := range v.Words {
+			if texts[word.Text] {
+				t.Errorf("%s: expected the text %q once, got it twice", v.Name, word.Text)
+			}
+
+			if names[word.Name] {
+				t.Errorf("%s: expected the name %q once, got it twice", v.Name, word.Name)
+			}
+
+			texts[word.Text] = true
+			names[word.Name] = true
+		}
+	}
+}